@@ -165,6 +165,14 @@ const (
 	AgentConnUpperThreshold = "AGENT_CONN_UPPER_THRESHOLD"
 	AgentConnLookbackWindow = "AGENT_CONN_LOOKBACK_WINDOW"
 
+	// The API call limits for user connections. Facade group
+	// limits take the form "<group>=<rate>/<burst>,...".
+	AgentUserCallRate          = "AGENT_USER_CALL_RATE"
+	AgentUserCallBurst         = "AGENT_USER_CALL_BURST"
+	AgentConnCallRate          = "AGENT_CONN_CALL_RATE"
+	AgentConnCallBurst         = "AGENT_CONN_CALL_BURST"
+	AgentFacadeGroupCallLimits = "AGENT_FACADE_GROUP_CALL_LIMITS"
+
 	MgoStatsEnabled = "MGO_STATS_ENABLED"
 
 	// LoggingOverride will set the logging for this agent to the value
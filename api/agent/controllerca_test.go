@@ -0,0 +1,75 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/agent"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type controllerCASuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&controllerCASuite{})
+
+func newStateWithVersion(c *gc.C, version int, check func(request string, arg, result interface{}) error) *agent.State {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, _ int, _, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Agent")
+			return check(request, arg, result)
+		},
+		BestVersion: version,
+	}
+	st, err := agent.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	return st
+}
+
+func (s *controllerCASuite) TestControllerCACert(c *gc.C) {
+	st := newStateWithVersion(c, 3, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "ControllerCACert")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.StringResult)) = params.StringResult{Result: "ca-bundle"}
+		return nil
+	})
+	caCert, err := st.ControllerCACert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caCert, gc.Equals, "ca-bundle")
+}
+
+func (s *controllerCASuite) TestSetControllerCATrusted(c *gc.C) {
+	st := newStateWithVersion(c, 3, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "SetControllerCATrusted")
+		c.Check(arg, jc.DeepEquals, params.EntityCACerts{
+			Entities: []params.EntityCACert{{Tag: "unit-mysql-0", CACert: "ca-bundle"}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	err := st.SetControllerCATrusted(names.NewUnitTag("mysql/0"), "ca-bundle")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *controllerCASuite) TestNotSupported(c *gc.C) {
+	st := newStateWithVersion(c, 2, func(request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call %q", request)
+		return nil
+	})
+	_, err := st.ControllerCACert()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = st.WatchControllerCACert()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = st.SetControllerCATrusted(names.NewMachineTag("0"), "ca-bundle")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
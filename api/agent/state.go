@@ -12,9 +12,11 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/common/cloudspec"
+	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/watcher"
 )
 
 // State provides access to an agent's view of the state.
@@ -77,6 +79,55 @@ func (st *State) IsMaster() (bool, error) {
 	return results.Master, err
 }
 
+// ControllerCACert returns the controller CA certificates the agent
+// should trust. While the controller CA is being rotated, this is a
+// bundle holding both the old and the new CA certificates.
+func (st *State) ControllerCACert() (string, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return "", errors.NotSupportedf("controller CA rotation")
+	}
+	var result params.StringResult
+	if err := st.facade.FacadeCall("ControllerCACert", nil, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// WatchControllerCACert returns a watcher which notifies when the
+// controller CA certificates the agent should trust might have changed.
+func (st *State) WatchControllerCACert() (watcher.NotifyWatcher, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("controller CA rotation")
+	}
+	var result params.NotifyWatchResult
+	if err := st.facade.FacadeCall("WatchControllerCACert", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
+// SetControllerCATrusted reports the controller CA certificates now
+// trusted by the agent with the given tag.
+func (st *State) SetControllerCATrusted(tag names.Tag, caCert string) error {
+	if st.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("controller CA rotation")
+	}
+	var results params.ErrorResults
+	args := params.EntityCACerts{
+		Entities: []params.EntityCACert{{Tag: tag.String(), CACert: caCert}},
+	}
+	if err := st.facade.FacadeCall("SetControllerCATrusted", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 type Entity struct {
 	st  *State
 	tag names.Tag
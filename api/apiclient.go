@@ -100,6 +100,10 @@ type state struct {
 	// by an officially signed certificate.
 	publicDNSName string
 
+	// controllerCACert holds the controller's CA certificate bundle
+	// returned from Login, for user logins only.
+	controllerCACert string
+
 	// facadeVersions holds the versions of all facades as reported by
 	// Login
 	facadeVersions map[string][]int
@@ -151,6 +155,10 @@ type state struct {
 	// bakeryClient holds the client that will be used to
 	// authorize macaroon based login requests.
 	bakeryClient *httpbakery.Client
+
+	// oidcLogin, if non-nil, is used to obtain an ID token from
+	// an OpenID Connect issuer when the controller requires one.
+	oidcLogin func(issuerURL, clientID string) (string, error)
 }
 
 // RedirectError is returned from Open when the controller
@@ -244,6 +252,7 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 		nonce:        info.Nonce,
 		tlsConfig:    dialResult.tlsConfig,
 		bakeryClient: bakeryClient,
+		oidcLogin:    opts.OIDCLogin,
 		modelTag:     info.ModelTag,
 	}
 	if !info.SkipLogin {
@@ -955,14 +964,21 @@ var apiCallRetryStrategy = retry.LimitTime(10*time.Second,
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
 func (s *state) APICall(facade string, version int, id, method string, args, response interface{}) error {
-	for a := retry.Start(apiCallRetryStrategy, s.clock); a.Next(); {
+	timer := &apiCallTimer{}
+	for a := retry.Start(timer, s.clock); a.Next(); {
 		err := s.client.Call(rpc.Request{
 			Type:    facade,
 			Version: version,
 			Id:      id,
 			Action:  method,
 		}, args, response)
-		if params.ErrCode(err) != params.CodeRetry {
+		// Retry calls the server asks us to, including those
+		// refused because we've exceeded our rate limit.
+		switch params.ErrCode(err) {
+		case params.CodeRetry:
+		case params.CodeRateLimitExceeded:
+			timer.retryAfter = retryAfter(err)
+		default:
 			return errors.Trace(err)
 		}
 		if !a.More() {
@@ -972,6 +988,47 @@ func (s *state) APICall(facade string, version int, id, method string, args, res
 	panic("unreachable")
 }
 
+// apiCallTimer is a retry.Strategy and retry.Timer that backs off as
+// apiCallRetryStrategy does, except that it waits for at least as long
+// as the server asked before retrying a call it refused because of
+// our rate limit.
+type apiCallTimer struct {
+	retry.Timer
+	retryAfter time.Duration
+}
+
+// NewTimer implements retry.Strategy.
+func (t *apiCallTimer) NewTimer(now time.Time) retry.Timer {
+	t.Timer = apiCallRetryStrategy.NewTimer(now)
+	return t
+}
+
+// NextSleep implements retry.Timer.
+func (t *apiCallTimer) NextSleep(now time.Time) (time.Duration, bool) {
+	sleep, ok := t.Timer.NextSleep(now)
+	if ok && sleep < t.retryAfter {
+		sleep = t.retryAfter
+	}
+	t.retryAfter = 0
+	return sleep, ok
+}
+
+// retryAfter returns how long the server asked us to wait before
+// retrying the call that failed with the given error, or zero if
+// it did not say.
+func retryAfter(err error) time.Duration {
+	rerr, ok := errors.Cause(err).(*rpc.RequestError)
+	if !ok {
+		return 0
+	}
+	var info params.ErrorInfo
+	if err := rerr.UnmarshalInfo(&info); err != nil {
+		logger.Debugf("cannot read error info: %v", err)
+		return 0
+	}
+	return info.RetryAfter
+}
+
 func (s *state) Close() error {
 	err := s.client.Close()
 	select {
@@ -1054,6 +1111,13 @@ func (s *state) PublicDNSName() string {
 	return s.publicDNSName
 }
 
+// ControllerCACert returns the bundle of CA certificates trusted by the
+// controller, as reported on login. It is empty for agent logins and
+// for older controllers.
+func (s *state) ControllerCACert() string {
+	return s.controllerCACert
+}
+
 // AllFacadeVersions returns what versions we know about for all facades
 func (s *state) AllFacadeVersions() map[string][]int {
 	facades := make(map[string][]int, len(s.facadeVersions))
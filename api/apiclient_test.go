@@ -984,6 +984,45 @@ func (s *apiclientSuite) TestAPICallRetries(c *gc.C) {
 	c.Check(clock.waits, jc.DeepEquals, []time.Duration{100 * time.Millisecond})
 }
 
+func (s *apiclientSuite) TestAPICallRetriesRateLimited(c *gc.C) {
+	clock := &fakeClock{}
+	rateLimitError := errors.Trace(&rpc.RequestError{
+		Message: "rate limit exceeded, retry after 1s",
+		Code:    params.CodeRateLimitExceeded,
+	})
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: newRPCConnection(rateLimitError, rateLimitError),
+		Clock:         clock,
+	})
+
+	err := conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(clock.waits, jc.DeepEquals, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+	})
+}
+
+func (s *apiclientSuite) TestAPICallRateLimitedWaitsRetryAfter(c *gc.C) {
+	clock := &fakeClock{}
+	rateLimitError := errors.Trace(&rpc.RequestError{
+		Message: "rate limit exceeded, retry after 1s",
+		Code:    params.CodeRateLimitExceeded,
+		Info:    map[string]interface{}{"retry-after": float64(time.Second)},
+	})
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: newRPCConnection(rateLimitError, rateLimitError),
+		Clock:         clock,
+	})
+
+	err := conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(clock.waits, jc.DeepEquals, []time.Duration{
+		time.Second,
+		time.Second,
+	})
+}
+
 func (s *apiclientSuite) TestAPICallRetriesLimit(c *gc.C) {
 	clock := &fakeClock{}
 	retryError := errors.Trace(&rpc.RequestError{Message: "hmm...", Code: params.CodeRetry})
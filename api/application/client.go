@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/devices"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/storage"
 )
 
@@ -816,3 +817,50 @@ func (c *Client) ResolveUnitErrors(units []string, all, retry bool) error {
 	}
 	return errors.Trace(results.Combine())
 }
+
+// GrantApplication grants a user access to the specified applications.
+func (c *Client) GrantApplication(user, access string, applications ...string) error {
+	return c.modifyApplicationUser(params.GrantApplicationAccess, user, access, applications)
+}
+
+// RevokeApplication revokes a user's access to the specified applications.
+func (c *Client) RevokeApplication(user, access string, applications ...string) error {
+	return c.modifyApplicationUser(params.RevokeApplicationAccess, user, access, applications)
+}
+
+func (c *Client) modifyApplicationUser(action params.ApplicationAction, user, access string, applications []string) error {
+	if c.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("application access not supported by this version of Juju")
+	}
+	if !names.IsValidUser(user) {
+		return errors.Errorf("invalid username: %q", user)
+	}
+	userTag := names.NewUserTag(user)
+
+	applicationAccess := permission.Access(access)
+	if err := permission.ValidateApplicationAccess(applicationAccess); err != nil {
+		return errors.Trace(err)
+	}
+	var args params.ModifyApplicationAccessRequest
+	for _, application := range applications {
+		if !names.IsValidApplication(application) {
+			return errors.NotValidf("application name %q", application)
+		}
+		args.Changes = append(args.Changes, params.ModifyApplicationAccess{
+			UserTag:        userTag.String(),
+			Action:         action,
+			Access:         params.ApplicationAccessPermission(applicationAccess),
+			ApplicationTag: names.NewApplicationTag(application).String(),
+		})
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ModifyApplicationAccess", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result.Results) != len(args.Changes) {
+		return errors.Errorf("expected %d results, got %d", len(args.Changes), len(result.Results))
+	}
+	return result.Combine()
+}
@@ -1121,3 +1121,73 @@ func (s *applicationSuite) TestResolveUnitErrorsAll(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *applicationSuite) TestGrantApplication(c *gc.C) {
+	called := false
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Check(objType, gc.Equals, "Application")
+				c.Check(request, gc.Equals, "ModifyApplicationAccess")
+				c.Check(a, jc.DeepEquals, params.ModifyApplicationAccessRequest{
+					Changes: []params.ModifyApplicationAccess{{
+						UserTag:        "user-bob",
+						Action:         params.GrantApplicationAccess,
+						Access:         params.ApplicationOperateAccess,
+						ApplicationTag: "application-postgresql",
+					}},
+				})
+				result := response.(*params.ErrorResults)
+				result.Results = make([]params.ErrorResult, 1)
+				return nil
+			},
+		),
+		BestVersion: 8,
+	})
+	err := client.GrantApplication("bob", "operate", "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestRevokeApplication(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Check(request, gc.Equals, "ModifyApplicationAccess")
+				args := a.(params.ModifyApplicationAccessRequest)
+				c.Assert(args.Changes, gc.HasLen, 1)
+				c.Check(args.Changes[0].Action, gc.Equals, params.RevokeApplicationAccess)
+				result := response.(*params.ErrorResults)
+				result.Results = []params.ErrorResult{{Error: &params.Error{Message: "boom"}}}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	})
+	err := client.RevokeApplication("bob", "admin", "postgresql")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *applicationSuite) TestGrantApplicationInvalidAccess(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			},
+		),
+		BestVersion: 8,
+	})
+	err := client.GrantApplication("bob", "write", "postgresql")
+	c.Assert(err, gc.ErrorMatches, `"write" application access not valid`)
+}
+
+func (s *applicationSuite) TestGrantApplicationNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	err := client.GrantApplication("bob", "read", "postgresql")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,170 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+)
+
+// OIDCUserDomain is the domain of users whose identity is
+// asserted by a controller's OpenID Connect issuer.
+const OIDCUserDomain = "oidc"
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultPollInterval is the interval at which the token
+	// endpoint is polled if the issuer does not specify one.
+	defaultPollInterval = 5 * time.Second
+
+	// slowDownIncrement is added to the poll interval whenever
+	// the issuer asks us to slow down.
+	slowDownIncrement = 5 * time.Second
+)
+
+// OIDCDeviceLogin obtains ID tokens from an OpenID Connect issuer
+// using the OAuth 2.0 device authorization grant, so that users can
+// log in with a browser on any device.
+type OIDCDeviceLogin struct {
+	// Client is used to make requests to the issuer. If it is nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+
+	// Clock is used to wait between polls of the issuer's token
+	// endpoint. If it is nil, clock.WallClock is used.
+	Clock clock.Clock
+
+	// Notify is called with the URL the user must visit, and the
+	// code they must enter there, to approve the login.
+	Notify func(verificationURI, userCode string) error
+}
+
+type oidcDiscovery struct {
+	Issuer                      string `json:"issuer"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+type deviceAuthorization struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        *int   `json:"interval"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Login obtains an ID token for the given client from the given
+// issuer. It is suitable for use as api.DialOpts.OIDCLogin.
+func (l *OIDCDeviceLogin) Login(issuerURL, clientID string) (string, error) {
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	clk := l.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+
+	var discovery oidcDiscovery
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(client, discoveryURL, &discovery); err != nil {
+		return "", errors.Annotate(err, "cannot fetch OIDC discovery document")
+	}
+	if discovery.DeviceAuthorizationEndpoint == "" {
+		return "", errors.Errorf("OIDC issuer %q does not support device authorization", issuerURL)
+	}
+
+	var auth deviceAuthorization
+	if err := postForm(client, discovery.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {clientID},
+		"scope":     {"openid profile"},
+	}, &auth); err != nil {
+		return "", errors.Annotate(err, "cannot start device authorization")
+	}
+	if err := l.Notify(auth.VerificationURI, auth.UserCode); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	interval := defaultPollInterval
+	if auth.Interval != nil {
+		interval = time.Duration(*auth.Interval) * time.Second
+	}
+	deadline := clk.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	for {
+		<-clk.After(interval)
+		if auth.ExpiresIn > 0 && clk.Now().After(deadline) {
+			return "", errors.New("OIDC device authorization expired")
+		}
+		resp, err := client.PostForm(discovery.TokenEndpoint, url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {auth.DeviceCode},
+			"client_id":   {clientID},
+		})
+		if err != nil {
+			return "", errors.Annotate(err, "cannot poll OIDC token endpoint")
+		}
+		var token tokenResponse
+		err = json.NewDecoder(resp.Body).Decode(&token)
+		resp.Body.Close()
+		if err != nil {
+			return "", errors.Annotate(err, "cannot decode OIDC token response")
+		}
+		switch token.Error {
+		case "":
+			if token.IDToken == "" {
+				return "", errors.New("OIDC issuer did not return an ID token")
+			}
+			return token.IDToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDownIncrement
+		case "access_denied":
+			return "", errors.New("OIDC login was denied")
+		case "expired_token":
+			return "", errors.New("OIDC device authorization expired")
+		default:
+			if token.ErrorDescription != "" {
+				return "", errors.Errorf("OIDC login failed: %s: %s", token.Error, token.ErrorDescription)
+			}
+			return "", errors.Errorf("OIDC login failed: %s", token.Error)
+		}
+	}
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(v))
+}
+
+func postForm(client *http.Client, endpoint string, form url.Values, v interface{}) error {
+	resp, err := client.PostForm(endpoint, form)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("POST %s: %s", endpoint, resp.Status)
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(v))
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/testing/oidctest"
+)
+
+type OIDCDeviceLoginSuite struct {
+	testing.IsolationSuite
+	issuer *oidctest.Issuer
+	login  *authentication.OIDCDeviceLogin
+
+	verificationURI string
+	userCode        string
+}
+
+var _ = gc.Suite(&OIDCDeviceLoginSuite{})
+
+func (s *OIDCDeviceLoginSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.issuer = oidctest.NewIssuer()
+	s.AddCleanup(func(*gc.C) { s.issuer.Close() })
+	s.verificationURI, s.userCode = "", ""
+	s.login = &authentication.OIDCDeviceLogin{
+		Client: s.issuer.Client(),
+		Notify: func(verificationURI, userCode string) error {
+			s.verificationURI = verificationURI
+			s.userCode = userCode
+			return nil
+		},
+	}
+}
+
+func (s *OIDCDeviceLoginSuite) TestLogin(c *gc.C) {
+	claims := s.issuer.Claims("juju", "alice", nil, time.Hour)
+	s.issuer.Approve(claims, 2)
+	token, err := s.login.Login(s.issuer.URL, "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, s.issuer.IDToken(claims))
+	c.Assert(s.verificationURI, gc.Equals, s.issuer.URL+"/activate")
+	c.Assert(s.userCode, gc.Equals, oidctest.UserCode)
+}
+
+func (s *OIDCDeviceLoginSuite) TestLoginDenied(c *gc.C) {
+	_, err := s.login.Login(s.issuer.URL, "juju")
+	c.Assert(err, gc.ErrorMatches, "OIDC login was denied")
+	c.Assert(s.userCode, gc.Equals, oidctest.UserCode)
+}
+
+func (s *OIDCDeviceLoginSuite) TestLoginNoIssuer(c *gc.C) {
+	s.issuer.Close()
+	_, err := s.login.Login(s.issuer.URL, "juju")
+	c.Assert(err, gc.ErrorMatches, "cannot fetch OIDC discovery document: .*")
+	c.Assert(s.userCode, gc.Equals, "")
+}
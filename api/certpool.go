@@ -13,6 +13,7 @@ import (
 	"github.com/juju/os/series"
 	"github.com/juju/utils/cert"
 
+	jujucert "github.com/juju/juju/cert"
 	"github.com/juju/juju/juju/paths"
 )
 
@@ -25,11 +26,15 @@ func CreateCertPool(caCert string) (*x509.CertPool, error) {
 
 	pool := x509.NewCertPool()
 	if caCert != "" {
-		xcert, err := cert.ParseCert(caCert)
+		// While the controller CA is being rotated, caCert holds
+		// both the old and the new CA certificates.
+		xcerts, err := jujucert.ParseCertBundle(caCert)
 		if err != nil {
 			return nil, errors.Annotatef(err, "cannot parse certificate %q", caCert)
 		}
-		pool.AddCert(xcert)
+		for _, xcert := range xcerts {
+			pool.AddCert(xcert)
+		}
 	}
 
 	count := processCertDir(pool)
@@ -158,6 +158,10 @@ func (c *Client) AddMachines(machineParams []params.AddMachineParams) ([]params.
 // ProvisioningScript returns a shell script that, when run,
 // provisions a machine agent on the machine executing the script.
 func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (script string, err error) {
+	if c.facade.BestAPIVersion() < 3 && args.DryRun {
+		return "", errors.New(
+			"dry runs of provisioning scripts not supported by the controller")
+	}
 	var result params.ProvisioningScriptResult
 	if err = c.facade.FacadeCall("ProvisioningScript", args, &result); err != nil {
 		return "", err
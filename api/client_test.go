@@ -688,3 +688,10 @@ func (s *IsolatedClientSuite) TestFindAllErrorsOnOlderController(c *gc.C) {
 	_, err := client.FindTools(0, 0, "", "", "proposed")
 	c.Assert(err, gc.ErrorMatches, "passing agent-stream not supported by the controller")
 }
+
+func (s *IsolatedClientSuite) TestProvisioningScriptDryRunErrorsOnOlderController(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 2}
+	client := api.APIClient(apiCaller)
+	_, err := client.ProvisioningScript(params.ProvisioningScriptParams{DryRun: true})
+	c.Assert(err, gc.ErrorMatches, "dry runs of provisioning scripts not supported by the controller")
+}
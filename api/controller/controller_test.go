@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/api/controller"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/environs"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -238,6 +239,64 @@ func (s *Suite) TestHostedModelConfigs_FormatResults(c *gc.C) {
 	c.Assert(third.Error.Error(), gc.Equals, "validating CloudSpec: empty Type not valid")
 }
 
+func (s *Suite) TestPrecheckMigration(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			out := result.(*params.MigrationPrecheckResults)
+			*out = params.MigrationPrecheckResults{
+				Results: []params.MigrationPrecheckResult{{
+					Problems: []params.MigrationPrecheckProblem{{
+						Scope:   "model",
+						Entity:  "unit-foo-0",
+						Message: "unit foo/0 is dead",
+					}},
+				}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	spec := makeSpec()
+	report, err := client.PrecheckMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(report.Problems, jc.DeepEquals, []migration.PrecheckProblem{{
+		Scope:   migration.PrecheckScopeModel,
+		Entity:  "unit-foo-0",
+		Message: "unit foo/0 is dead",
+	}})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.PrecheckMigration", []interface{}{specToArgs(spec)}},
+	})
+}
+
+func (s *Suite) TestPrecheckMigrationError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			out := result.(*params.MigrationPrecheckResults)
+			*out = params.MigrationPrecheckResults{
+				Results: []params.MigrationPrecheckResult{{
+					Error: common.ServerError(errors.New("boom")),
+				}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.PrecheckMigration(makeSpec())
+	c.Check(err, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestPrecheckMigrationOldController(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 5}
+	client := controller.NewClient(apiCaller)
+	_, err := client.PrecheckMigration(makeSpec())
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func makeInitiateMigrationClient(results params.InitiateMigrationResults) (
 	*controller.Client, *jujutesting.Stub,
 ) {
@@ -380,3 +439,44 @@ func (s *Suite) TestConfigSetAgainstOlderAPIVersion(c *gc.C) {
 	})
 	c.Assert(err, gc.ErrorMatches, "this controller version doesn't support updating controller config")
 }
+
+func (s *Suite) TestRotateControllerCA(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 7,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.ControllerCARotationStatus)) = params.ControllerCARotationStatus{
+				Phase:            "trust",
+				CACert:           "old+new",
+				NewCACert:        "new",
+				UntrustingAgents: []string{"uuid:machine-0"},
+			}
+			return stub.NextErr()
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	status, err := client.RotateControllerCA()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, controller.CARotationStatus{
+		Phase:            "trust",
+		CACert:           "old+new",
+		NewCACert:        "new",
+		UntrustingAgents: []string{"uuid:machine-0"},
+	})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.RotateControllerCA", []interface{}{nil}},
+	})
+}
+
+func (s *Suite) TestControllerCARotationNotSupported(c *gc.C) {
+	client := controller.NewClient(apitesting.BestVersionCaller{BestVersion: 6})
+	_, err := client.RotateControllerCA()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.ControllerCARotationStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.IssueControllerCertsFromNewCA()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.RetireOldControllerCA()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
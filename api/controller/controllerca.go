@@ -0,0 +1,86 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// CARotationStatus describes the progress of a controller CA rotation.
+type CARotationStatus struct {
+	// Phase is empty when no rotation is in progress; otherwise it is
+	// "trust" while agents learn the new CA, and "issue" once server
+	// certificates are issued by it.
+	Phase string
+
+	// CACert holds the bundle of CA certificates currently trusted
+	// by the controller.
+	CACert string
+
+	// NewCACert holds the CA certificate being introduced.
+	NewCACert string
+
+	// UntrustingAgents holds the agents yet to trust the new CA.
+	UntrustingAgents []string
+
+	// PendingControllers holds the ids of controller machines still
+	// serving a certificate issued by the old CA.
+	PendingControllers []string
+}
+
+func (c *Client) checkCARotationSupported() error {
+	if c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("controller CA rotation on this controller version")
+	}
+	return nil
+}
+
+// RotateControllerCA starts a controller CA rotation, introducing a new
+// CA that is trusted alongside the current one.
+func (c *Client) RotateControllerCA() (CARotationStatus, error) {
+	return c.caRotationCall("RotateControllerCA")
+}
+
+// ControllerCARotationStatus returns the progress of the controller CA
+// rotation in progress, if any.
+func (c *Client) ControllerCARotationStatus() (CARotationStatus, error) {
+	return c.caRotationCall("ControllerCARotationStatus")
+}
+
+func (c *Client) caRotationCall(request string) (CARotationStatus, error) {
+	if err := c.checkCARotationSupported(); err != nil {
+		return CARotationStatus{}, errors.Trace(err)
+	}
+	var result params.ControllerCARotationStatus
+	if err := c.facade.FacadeCall(request, nil, &result); err != nil {
+		return CARotationStatus{}, errors.Trace(err)
+	}
+	return CARotationStatus{
+		Phase:              result.Phase,
+		CACert:             result.CACert,
+		NewCACert:          result.NewCACert,
+		UntrustingAgents:   result.UntrustingAgents,
+		PendingControllers: result.PendingControllers,
+	}, nil
+}
+
+// IssueControllerCertsFromNewCA has the controllers issue their server
+// certificates from the new CA. Every agent must trust it first.
+func (c *Client) IssueControllerCertsFromNewCA() error {
+	if err := c.checkCARotationSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.facade.FacadeCall("IssueControllerCertsFromNewCA", nil, nil))
+}
+
+// RetireOldControllerCA completes the controller CA rotation, after which
+// only the new CA is trusted.
+func (c *Client) RetireOldControllerCA() error {
+	if err := c.checkCARotationSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.facade.FacadeCall("RetireOldControllerCA", nil, nil))
+}
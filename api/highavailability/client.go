@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/replicaset"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -83,3 +84,45 @@ func (c *Client) ResumeHAReplicationAfterUpgrade(members []replicaset.Member) er
 	}
 	return nil
 }
+
+// ControllerMembers returns the voting and replica-set status of each
+// controller machine.
+func (c *Client) ControllerMembers() ([]params.ControllerMember, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("controller-members")
+	}
+	var result params.ControllerMembersResult
+	if err := c.facade.FacadeCall("ControllerMembers", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Members, nil
+}
+
+// RemoveControllerMember removes the specified machines from the set of
+// controllers, provided a healthy majority of voters remains.
+func (c *Client) RemoveControllerMember(machineIds ...string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("remove-controller-member")
+	}
+	args := params.Entities{Entities: make([]params.Entity, len(machineIds))}
+	for i, id := range machineIds {
+		if !names.IsValidMachine(id) {
+			return errors.NotValidf("machine ID %q", id)
+		}
+		args.Entities[i].Tag = names.NewMachineTag(id).String()
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveControllerMember", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// StepDown asks the current mongo primary to step down so that a new
+// primary is elected.
+func (c *Client) StepDown() error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("step-down")
+	}
+	return c.facade.FacadeCall("StepDown", nil, nil)
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type membersSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&membersSuite{})
+
+func newClient(version int, f apitesting.APICallerFunc) *highavailability.Client {
+	return highavailability.NewClient(apitesting.BestVersionCaller{f, version})
+}
+
+func (s *membersSuite) TestControllerMembers(c *gc.C) {
+	client := newClient(3, func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "HighAvailability")
+		c.Check(request, gc.Equals, "ControllerMembers")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.ControllerMembersResult)) = params.ControllerMembersResult{
+			Members: []params.ControllerMember{{Tag: "machine-0", HasVote: true}},
+		}
+		return nil
+	})
+	members, err := client.ControllerMembers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(members, jc.DeepEquals, []params.ControllerMember{{Tag: "machine-0", HasVote: true}})
+}
+
+func (s *membersSuite) TestRemoveControllerMember(c *gc.C) {
+	client := newClient(3, func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "RemoveControllerMember")
+		c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "machine-2"}}})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "no quorum"}}},
+		}
+		return nil
+	})
+	err := client.RemoveControllerMember("2")
+	c.Assert(err, gc.ErrorMatches, "no quorum")
+}
+
+func (s *membersSuite) TestRemoveControllerMemberInvalidId(c *gc.C) {
+	client := newClient(3, func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call")
+		return nil
+	})
+	err := client.RemoveControllerMember("foo")
+	c.Assert(err, gc.ErrorMatches, `machine ID "foo" not valid`)
+}
+
+func (s *membersSuite) TestStepDown(c *gc.C) {
+	client := newClient(3, func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "StepDown")
+		return errors.New("boom")
+	})
+	err := client.StepDown()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *membersSuite) TestNotSupported(c *gc.C) {
+	client := newClient(2, func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call")
+		return nil
+	})
+	_, err := client.ControllerMembers()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.RemoveControllerMember("1")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.StepDown()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
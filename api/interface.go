@@ -147,6 +147,13 @@ type DialOpts struct {
 	// the HTTP client is ignored.
 	BakeryClient *httpbakery.Client
 
+	// OIDCLogin is used to obtain an ID token from the controller's
+	// OpenID Connect issuer when logging in as a user whose identity
+	// is asserted by that issuer. It is called with the issuer URL
+	// and the client ID registered for the controller, and returns
+	// the ID token to log in with. If it is nil, such logins fail.
+	OIDCLogin func(issuerURL, clientID string) (string, error)
+
 	// InsecureSkipVerify skips TLS certificate verification
 	// when connecting to the controller. This should only
 	// be used in tests, or when verification cannot be
@@ -250,6 +257,11 @@ type Connection interface {
 	// the connection.
 	PublicDNSName() string
 
+	// ControllerCACert returns the bundle of CA certificates trusted
+	// by the controller, as reported on login. It is empty for agent
+	// logins and for older controllers.
+	ControllerCACert() string
+
 	// These are a bit off -- ServerVersion is apparently not known until after
 	// Login()? Maybe evidence of need for a separate AuthenticatedConnection..?
 	Login(name names.Tag, password, nonce string, ms []macaroon.Slice) error
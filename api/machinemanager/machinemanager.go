@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 )
 
 const machineManagerFacade = "MachineManager"
@@ -182,3 +183,80 @@ func (client *Client) UpgradeSeriesComplete(machineName string) error {
 
 	return nil
 }
+
+// ReplaceMachines replaces the instances of the specified machines,
+// keeping the machines' IDs, units and storage.
+func (client *Client) ReplaceMachines(machines ...string) ([]params.ErrorResult, error) {
+	if client.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("replacing machines")
+	}
+	args := params.Entities{
+		Entities: make([]params.Entity, len(machines)),
+	}
+	for i, id := range machines {
+		if !names.IsValidMachine(id) {
+			return nil, errors.NotValidf("machine ID %q", id)
+		}
+		args.Entities[i].Tag = names.NewMachineTag(id).String()
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("ReplaceMachines", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machines) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(machines), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetMachineMaintenance puts the specified machine into maintenance,
+// recording the given progress message along with any units added
+// elsewhere to replace those it hosts, keyed by the name of the unit
+// replaced. An empty message takes the machine out of maintenance. All
+// the replacement units recorded for the machine are returned.
+func (client *Client) SetMachineMaintenance(machine, message string, replacements map[string]string) (map[string]string, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("machine maintenance")
+	}
+	if !names.IsValidMachine(machine) {
+		return nil, errors.NotValidf("machine ID %q", machine)
+	}
+	args := params.MachineMaintenanceArgs{
+		Args: []params.MachineMaintenanceArg{{
+			Entity:       params.Entity{Tag: names.NewMachineTag(machine).String()},
+			Message:      message,
+			Replacements: replacements,
+		}},
+	}
+	var results params.MachineMaintenanceResults
+	if err := client.facade.FacadeCall("SetMachineMaintenance", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Replacements, nil
+}
+
+// InstanceTypes returns the instance types available in the model's
+// cloud region that match each of the given constraints.
+func (client *Client) InstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error) {
+	args := params.ModelInstanceTypesConstraints{
+		Constraints: make([]params.ModelInstanceTypesConstraint, len(cons)),
+	}
+	for i, value := range cons {
+		value := value
+		args.Constraints[i].Value = &value
+	}
+	var results params.InstanceTypesResults
+	if err := client.facade.FacadeCall("InstanceTypes", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(cons) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(cons), len(results.Results))
+	}
+	return results.Results, nil
+}
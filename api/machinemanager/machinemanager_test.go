@@ -13,6 +13,7 @@ import (
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -210,3 +211,137 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+func (s *MachinemanagerSuite) TestInstanceTypes(c *gc.C) {
+	mem := uint64(4096)
+	expectedResults := []params.InstanceTypesResult{{
+		InstanceTypes: []params.InstanceType{{Name: "m3.medium", CPUCores: 1, Memory: 3840}},
+	}, {
+		Error: &params.Error{Message: "boo"},
+	}}
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Assert(request, gc.Equals, "InstanceTypes")
+		c.Assert(a, jc.DeepEquals, params.ModelInstanceTypesConstraints{
+			Constraints: []params.ModelInstanceTypesConstraint{
+				{Value: &constraints.Value{}},
+				{Value: &constraints.Value{Mem: &mem}},
+			},
+		})
+		c.Assert(response, gc.FitsTypeOf, &params.InstanceTypesResults{})
+		out := response.(*params.InstanceTypesResults)
+		*out = params.InstanceTypesResults{Results: expectedResults}
+		return nil
+	})
+	results, err := client.InstanceTypes([]constraints.Value{{}, {Mem: &mem}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+}
+
+func (s *MachinemanagerSuite) TestInstanceTypesResultCountMismatch(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		return nil
+	})
+	_, err := client.InstanceTypes([]constraints.Value{{}})
+	c.Assert(err, gc.ErrorMatches, `expected 1 result\(s\), got 0`)
+}
+
+func (s *MachinemanagerSuite) TestReplaceMachines(c *gc.C) {
+	expectedResults := []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "boo"}},
+	}
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Assert(request, gc.Equals, "ReplaceMachines")
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{
+					{Tag: "machine-0"},
+					{Tag: "machine-1"},
+				},
+			})
+			c.Assert(response, gc.FitsTypeOf, &params.ErrorResults{})
+			*(response.(*params.ErrorResults)) = params.ErrorResults{Results: expectedResults}
+			return nil
+		},
+		BestVersion: 6,
+	})
+	results, err := client.ReplaceMachines("0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+}
+
+func (s *MachinemanagerSuite) TestReplaceMachinesInvalidId(c *gc.C) {
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 6,
+	})
+	_, err := client.ReplaceMachines("0", "!")
+	c.Assert(err, gc.ErrorMatches, `machine ID "!" not valid`)
+}
+
+func (s *MachinemanagerSuite) TestReplaceMachinesNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 5,
+	})
+	_, err := client.ReplaceMachines("0")
+	c.Assert(err, gc.ErrorMatches, "replacing machines not supported")
+}
+
+func (s *MachinemanagerSuite) TestSetMachineMaintenance(c *gc.C) {
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Assert(request, gc.Equals, "SetMachineMaintenance")
+			c.Assert(a, jc.DeepEquals, params.MachineMaintenanceArgs{
+				Args: []params.MachineMaintenanceArg{{
+					Entity:       params.Entity{Tag: "machine-0"},
+					Message:      "draining",
+					Replacements: map[string]string{"foo/0": "foo/1"},
+				}},
+			})
+			c.Assert(response, gc.FitsTypeOf, &params.MachineMaintenanceResults{})
+			*(response.(*params.MachineMaintenanceResults)) = params.MachineMaintenanceResults{
+				Results: []params.MachineMaintenanceResult{{
+					Replacements: map[string]string{"foo/0": "foo/1", "bar/0": "bar/1"},
+				}},
+			}
+			return nil
+		},
+		BestVersion: 7,
+	})
+	replacements, err := client.SetMachineMaintenance("0", "draining", map[string]string{"foo/0": "foo/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacements, jc.DeepEquals, map[string]string{"foo/0": "foo/1", "bar/0": "bar/1"})
+}
+
+func (s *MachinemanagerSuite) TestSetMachineMaintenanceError(c *gc.C) {
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			*(response.(*params.MachineMaintenanceResults)) = params.MachineMaintenanceResults{
+				Results: []params.MachineMaintenanceResult{{Error: &params.Error{Message: "boo"}}},
+			}
+			return nil
+		},
+		BestVersion: 7,
+	})
+	_, err := client.SetMachineMaintenance("0", "draining", nil)
+	c.Assert(err, gc.ErrorMatches, "boo")
+}
+
+func (s *MachinemanagerSuite) TestSetMachineMaintenanceNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 6,
+	})
+	_, err := client.SetMachineMaintenance("0", "draining", nil)
+	c.Assert(err, gc.ErrorMatches, "machine maintenance not supported")
+}
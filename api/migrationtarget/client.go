@@ -43,14 +43,36 @@ type Client struct {
 }
 
 func (c *Client) Prechecks(model coremigration.ModelInfo) error {
-	args := params.MigrationModelInfo{
+	args := makeModelInfoParams(model)
+	return c.caller.FacadeCall("Prechecks", args, nil)
+}
+
+// PrecheckReport runs the target controller's migration prechecks,
+// returning every problem found rather than failing at the first one.
+func (c *Client) PrecheckReport(model coremigration.ModelInfo) (coremigration.PrecheckReport, error) {
+	var report coremigration.PrecheckReport
+	if c.caller.BestAPIVersion() < 2 {
+		return report, errors.NotSupportedf("migration precheck reports on this controller version")
+	}
+	var result params.MigrationPrecheckReport
+	err := c.caller.FacadeCall("PrecheckReport", makeModelInfoParams(model), &result)
+	if err != nil {
+		return report, errors.Trace(err)
+	}
+	for _, p := range result.Problems {
+		report.Add(coremigration.PrecheckScope(p.Scope), p.Entity, p.Message)
+	}
+	return report, nil
+}
+
+func makeModelInfoParams(model coremigration.ModelInfo) params.MigrationModelInfo {
+	return params.MigrationModelInfo{
 		UUID:                   model.UUID,
 		Name:                   model.Name,
 		OwnerTag:               model.Owner.String(),
 		AgentVersion:           model.AgentVersion,
 		ControllerAgentVersion: model.ControllerAgentVersion,
 	}
-	return c.caller.FacadeCall("Prechecks", args, nil)
 }
 
 // Import takes a serialized model and imports it into the target
@@ -60,6 +82,17 @@ func (c *Client) Import(bytes []byte) error {
 	return c.caller.FacadeCall("Import", serialized, nil)
 }
 
+// ValidateImport takes a serialized model and checks that it could
+// be imported into the target controller. The target does not import
+// the model.
+func (c *Client) ValidateImport(bytes []byte) error {
+	if c.caller.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("import validation on this controller version")
+	}
+	serialized := params.SerializedModel{Bytes: bytes}
+	return c.caller.FacadeCall("ValidateImport", serialized, nil)
+}
+
 // Abort removes all data relating to a previously imported model.
 func (c *Client) Abort(modelUUID string) error {
 	args := params.ModelArgs{ModelTag: names.NewModelTag(modelUUID).String()}
@@ -89,6 +89,86 @@ func (s *ClientSuite) TestImport(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "boom")
 }
 
+func (s *ClientSuite) TestPrecheckReport(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, id, arg)
+			out := result.(*params.MigrationPrecheckReport)
+			*out = params.MigrationPrecheckReport{
+				Problems: []params.MigrationPrecheckProblem{{
+					Scope:   "target-controller",
+					Entity:  "model-uuid",
+					Message: "model named \"name\" already exists",
+				}},
+			}
+			return nil
+		},
+		BestVersion: 2,
+	}
+	client := migrationtarget.NewClient(apiCaller)
+
+	ownerTag := names.NewUserTag("owner")
+	vers := version.MustParse("1.2.3")
+	report, err := client.PrecheckReport(coremigration.ModelInfo{
+		UUID:                   "uuid",
+		Owner:                  ownerTag,
+		Name:                   "name",
+		AgentVersion:           vers,
+		ControllerAgentVersion: vers,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Problems, jc.DeepEquals, []coremigration.PrecheckProblem{{
+		Scope:   coremigration.PrecheckScopeTargetController,
+		Entity:  "model-uuid",
+		Message: "model named \"name\" already exists",
+	}})
+
+	expectedArg := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "name",
+		OwnerTag:               ownerTag.String(),
+		AgentVersion:           vers,
+		ControllerAgentVersion: vers,
+	}
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.PrecheckReport", []interface{}{"", expectedArg}},
+	})
+}
+
+func (s *ClientSuite) TestPrecheckReportNotSupported(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+	_, err := client.PrecheckReport(coremigration.ModelInfo{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	stub.CheckNoCalls(c)
+}
+
+func (s *ClientSuite) TestValidateImport(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, id, arg)
+			return errors.New("boom")
+		},
+		BestVersion: 2,
+	}
+	client := migrationtarget.NewClient(apiCaller)
+
+	err := client.ValidateImport([]byte("foo"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	expectedArg := params.SerializedModel{Bytes: []byte("foo")}
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.ValidateImport", []interface{}{"", expectedArg}},
+	})
+}
+
+func (s *ClientSuite) TestValidateImportNotSupported(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+	err := client.ValidateImport([]byte("foo"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	stub.CheckNoCalls(c)
+}
+
 func (s *ClientSuite) TestAbort(c *gc.C) {
 	client, stub := s.getClientAndStub(c)
 
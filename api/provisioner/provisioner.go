@@ -13,6 +13,7 @@ import (
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/netplan"
 	"github.com/juju/juju/tools"
 	"github.com/juju/juju/watcher"
 )
@@ -340,6 +341,7 @@ func (st *State) HostChangesForContainer(containerTag names.MachineTag) ([]netwo
 		res[i].BridgeName = bridgeInfo.BridgeName
 		res[i].DeviceName = bridgeInfo.HostDeviceName
 		res[i].MACAddress = bridgeInfo.MACAddress
+		res[i].DeviceType = netplan.DeviceType(bridgeInfo.DeviceType)
 	}
 	return res, result.Results[0].ReconfigureDelay, nil
 }
@@ -0,0 +1,117 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+const resourceTaggerFacade = "ResourceTagger"
+
+// Resource holds the details of an instance or volume whose tags
+// should be reconciled.
+type Resource struct {
+	// Tag is the tag of the machine or volume.
+	Tag names.Tag
+
+	// ProviderId is the provider's ID for the machine's instance,
+	// or for the volume.
+	ProviderId string
+
+	// Tags holds the tags the resource should have.
+	Tags map[string]string
+}
+
+// Client provides access to the ResourceTagger API facade.
+type Client struct {
+	*common.ModelWatcher
+
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client-side ResourceTagger facade.
+func NewClient(caller base.APICaller) *Client {
+	facadeCaller := base.NewFacadeCaller(caller, resourceTaggerFacade)
+	return &Client{
+		ModelWatcher: common.NewModelWatcher(facadeCaller),
+		facade:       facadeCaller,
+	}
+}
+
+// WatchApplicationConfig returns a NotifyWatcher that notifies of
+// changes to the application config, and hence the resource-tags,
+// of the model's applications.
+func (c *Client) WatchApplicationConfig() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	if err := c.facade.FacadeCall("WatchApplicationConfig", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
+
+// TaggableResources returns the provisioned instances and volumes in
+// the model, along with the tags each should have.
+func (c *Client) TaggableResources() ([]Resource, error) {
+	var result params.TaggableResourcesResult
+	if err := c.facade.FacadeCall("TaggableResources", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	resources := make([]Resource, len(result.Resources))
+	for i, r := range result.Resources {
+		tag, err := names.ParseTag(r.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		resources[i] = Resource{
+			Tag:        tag,
+			ProviderId: r.ProviderId,
+			Tags:       r.Tags,
+		}
+	}
+	return resources, nil
+}
+
+// TagDrift holds the outcome of reconciling the tags of a machine's
+// instance, or of a volume, whose tags had drifted.
+type TagDrift struct {
+	// Tag is the tag of the machine or volume.
+	Tag names.Tag
+
+	// Error holds the reason the tags could not be corrected,
+	// or nil if they were.
+	Error error
+}
+
+// SetTagDrift records the machines and volumes whose tags had drifted
+// in the most recent reconciliation, and whether they were corrected.
+func (c *Client) SetTagDrift(drift []TagDrift) error {
+	args := params.TagDriftArgs{Drift: make([]params.TagDrift, len(drift))}
+	for i, d := range drift {
+		args.Drift[i].Tag = d.Tag.String()
+		if d.Error != nil {
+			args.Drift[i].Error = &params.Error{Message: d.Error.Error()}
+		}
+	}
+	var result params.ErrorResult
+	if err := c.facade.FacadeCall("SetTagDrift", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return errors.Trace(result.Error)
+	}
+	return nil
+}
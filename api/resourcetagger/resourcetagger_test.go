@@ -0,0 +1,113 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/resourcetagger"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type resourceTaggerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&resourceTaggerSuite{})
+
+func (s *resourceTaggerSuite) TestTaggableResources(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ResourceTagger")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "TaggableResources")
+		c.Check(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.TaggableResourcesResult{})
+		*(result.(*params.TaggableResourcesResult)) = params.TaggableResourcesResult{
+			Resources: []params.TaggableResource{{
+				Tag:        "machine-0",
+				ProviderId: "i-0",
+				Tags:       map[string]string{"team": "web"},
+			}, {
+				Tag:        "volume-1",
+				ProviderId: "vol-1",
+				Tags:       map[string]string{"team": "db"},
+			}},
+		}
+		return nil
+	})
+	client := resourcetagger.NewClient(caller)
+	resources, err := client.TaggableResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, []resourcetagger.Resource{{
+		Tag:        names.NewMachineTag("0"),
+		ProviderId: "i-0",
+		Tags:       map[string]string{"team": "web"},
+	}, {
+		Tag:        names.NewVolumeTag("1"),
+		ProviderId: "vol-1",
+		Tags:       map[string]string{"team": "db"},
+	}})
+}
+
+func (s *resourceTaggerSuite) TestTaggableResourcesError(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.TaggableResourcesResult)) = params.TaggableResourcesResult{
+			Error: &params.Error{Message: "boom"},
+		}
+		return nil
+	})
+	client := resourcetagger.NewClient(caller)
+	_, err := client.TaggableResources()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *resourceTaggerSuite) TestWatchApplicationConfigError(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ResourceTagger")
+		c.Check(request, gc.Equals, "WatchApplicationConfig")
+		c.Check(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.NotifyWatchResult{})
+		*(result.(*params.NotifyWatchResult)) = params.NotifyWatchResult{
+			Error: &params.Error{Message: "boom"},
+		}
+		return nil
+	})
+	client := resourcetagger.NewClient(caller)
+	w, err := client.WatchApplicationConfig()
+	c.Assert(w, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *resourceTaggerSuite) TestSetTagDrift(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ResourceTagger")
+		c.Check(request, gc.Equals, "SetTagDrift")
+		c.Check(arg, jc.DeepEquals, params.TagDriftArgs{Drift: []params.TagDrift{
+			{Tag: "machine-0"},
+			{Tag: "volume-1", Error: &params.Error{Message: "boom"}},
+		}})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResult{})
+		return nil
+	})
+	client := resourcetagger.NewClient(caller)
+	err := client.SetTagDrift([]resourcetagger.TagDrift{
+		{Tag: names.NewMachineTag("0")},
+		{Tag: names.NewVolumeTag("1"), Error: errors.New("boom")},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *resourceTaggerSuite) TestSetTagDriftError(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	client := resourcetagger.NewClient(caller)
+	err := client.SetTagDrift(nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -84,3 +84,69 @@ func (api *API) ReloadSpaces() error {
 	}
 	return err
 }
+
+// RenameSpace renames a space, updating the subnets, endpoint bindings
+// and constraints which refer to it.
+func (api *API) RenameSpace(from, to string) error {
+	if api.facade.BestAPIVersion() < 4 {
+		return errors.NewNotSupported(nil, "Controller does not support renaming spaces")
+	}
+	var response params.ErrorResults
+	args := params.RenameSpacesParams{
+		Changes: []params.RenameSpaceParams{{
+			FromSpaceTag: names.NewSpaceTag(from).String(),
+			ToSpaceTag:   names.NewSpaceTag(to).String(),
+		}},
+	}
+	if err := api.facade.FacadeCall("RenameSpaces", args, &response); err != nil {
+		if params.IsCodeNotSupported(err) {
+			return errors.NewNotSupported(nil, err.Error())
+		}
+		return errors.Trace(err)
+	}
+	return response.OneError()
+}
+
+// RemoveSpace removes a space which is not in use, moving its subnets
+// to the default space.
+func (api *API) RemoveSpace(name string) error {
+	if api.facade.BestAPIVersion() < 4 {
+		return errors.NewNotSupported(nil, "Controller does not support removing spaces")
+	}
+	var response params.ErrorResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewSpaceTag(name).String()}},
+	}
+	if err := api.facade.FacadeCall("RemoveSpaces", args, &response); err != nil {
+		if params.IsCodeNotSupported(err) {
+			return errors.NewNotSupported(nil, err.Error())
+		}
+		return errors.Trace(err)
+	}
+	return response.OneError()
+}
+
+// UpdateSpace replaces the subnets of a space with the given subnets.
+func (api *API) UpdateSpace(name string, subnetIds []string) error {
+	if api.facade.BestAPIVersion() < 4 {
+		return errors.NewNotSupported(nil, "Controller does not support updating spaces")
+	}
+	subnetTags := make([]string, len(subnetIds))
+	for i, s := range subnetIds {
+		subnetTags[i] = names.NewSubnetTag(s).String()
+	}
+	var response params.ErrorResults
+	args := params.UpdateSpacesParams{
+		Spaces: []params.UpdateSpaceParams{{
+			SpaceTag:   names.NewSpaceTag(name).String(),
+			SubnetTags: subnetTags,
+		}},
+	}
+	if err := api.facade.FacadeCall("UpdateSpaces", args, &response); err != nil {
+		if params.IsCodeNotSupported(err) {
+			return errors.NewNotSupported(nil, err.Error())
+		}
+		return errors.Trace(err)
+	}
+	return response.OneError()
+}
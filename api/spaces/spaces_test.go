@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math/rand"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
@@ -167,3 +168,62 @@ func (s *SpacesSuite) TestListSpacesManyResults(c *gc.C) {
 func (s *SpacesSuite) TestListSpacesServerError(c *gc.C) {
 	s.testListSpaces(c, nil, errors.New("boom"), "boom")
 }
+
+func (s *SpacesSuite) newAPIWithVersion(c *gc.C, version int, calls *[]string) *spaces.API {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, v int, id, request string, args, result interface{}) error {
+			c.Check(objType, gc.Equals, "Spaces")
+			*calls = append(*calls, request)
+			switch request {
+			case "RenameSpaces":
+				c.Check(args, jc.DeepEquals, params.RenameSpacesParams{
+					Changes: []params.RenameSpaceParams{{
+						FromSpaceTag: "space-foo",
+						ToSpaceTag:   "space-bar",
+					}},
+				})
+			case "RemoveSpaces":
+				c.Check(args, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "space-foo"}},
+				})
+			case "UpdateSpaces":
+				c.Check(args, jc.DeepEquals, params.UpdateSpacesParams{
+					Spaces: []params.UpdateSpaceParams{{
+						SpaceTag:   "space-foo",
+						SubnetTags: []string{"subnet-10.0.0.0/24"},
+					}},
+				})
+			}
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+		BestVersion: version,
+	}
+	return spaces.NewAPI(apiCaller)
+}
+
+func (s *SpacesSuite) TestChangeSpaces(c *gc.C) {
+	var calls []string
+	api := s.newAPIWithVersion(c, 4, &calls)
+
+	c.Assert(api.RenameSpace("foo", "bar"), jc.ErrorIsNil)
+	c.Assert(api.RemoveSpace("foo"), jc.ErrorIsNil)
+	c.Assert(api.UpdateSpace("foo", []string{"10.0.0.0/24"}), jc.ErrorIsNil)
+	c.Assert(calls, jc.DeepEquals, []string{"RenameSpaces", "RemoveSpaces", "UpdateSpaces"})
+}
+
+func (s *SpacesSuite) TestChangeSpacesNotSupported(c *gc.C) {
+	var calls []string
+	api := s.newAPIWithVersion(c, 3, &calls)
+
+	err := api.RenameSpace("foo", "bar")
+	c.Assert(err, gc.ErrorMatches, "Controller does not support renaming spaces")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+	err = api.RemoveSpace("foo")
+	c.Assert(err, gc.ErrorMatches, "Controller does not support removing spaces")
+	err = api.UpdateSpace("foo", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, "Controller does not support updating spaces")
+	c.Assert(calls, gc.HasLen, 0)
+}
@@ -113,6 +113,29 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 			return errors.Errorf("login with discharged macaroons failed: %s", result.DischargeRequiredReason)
 		}
 	}
+	if result.OIDCLoginRequired != nil {
+		// The controller requires an ID token from its OpenID
+		// Connect issuer. Obtain one and retry the login with
+		// the token as credentials.
+		if st.oidcLogin == nil {
+			return errors.Errorf("OIDC login required: %s", result.OIDCLoginRequiredReason)
+		}
+		token, err := st.oidcLogin(result.OIDCLoginRequired.IssuerURL, result.OIDCLoginRequired.ClientID)
+		if err != nil {
+			return errors.Annotate(err, "cannot log in with OIDC issuer")
+		}
+		request.Credentials = token
+		result = params.LoginResult{} // zero result
+		err = st.APICall("Admin", 3, "", "Login", request, &result)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if result.OIDCLoginRequired != nil {
+			return errors.Errorf("login with OIDC ID token failed: %s", result.OIDCLoginRequiredReason)
+		}
+		// Use the token for HTTP requests made on this connection.
+		st.password = token
+	}
 
 	var controllerAccess string
 	var modelAccess string
@@ -131,6 +154,7 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 		controllerTag:    result.ControllerTag,
 		servers:          servers,
 		publicDNSName:    result.PublicDNSName,
+		controllerCACert: result.ControllerCACert,
 		facades:          result.Facades,
 		modelAccess:      modelAccess,
 		controllerAccess: controllerAccess,
@@ -153,6 +177,7 @@ type loginResultParams struct {
 	servers          [][]network.HostPort
 	facades          []params.FacadeVersions
 	publicDNSName    string
+	controllerCACert string
 }
 
 func (st *state) setLoginResult(p loginResultParams) error {
@@ -185,6 +210,7 @@ func (st *state) setLoginResult(p loginResultParams) error {
 	}
 	st.hostPorts = hostPorts
 	st.publicDNSName = p.publicDNSName
+	st.controllerCACert = p.controllerCACert
 
 	st.facadeVersions = make(map[string][]int, len(p.facades))
 	for _, facade := range p.facades {
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/modelmanager"
 	"github.com/juju/juju/api/usermanager"
+	"github.com/juju/juju/controller"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	coretesting "github.com/juju/juju/testing"
@@ -143,6 +144,46 @@ func (s *stateSuite) TestLoginMacaroonInvalidId(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "interaction required but not possible")
 }
 
+func (s *stateSuite) TestLoginOIDC(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.OIDCIssuerURL: "https://issuer.example.com",
+		controller.OIDCClientID:  "juju",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	info.Tag = nil
+	info.Password = ""
+	info.Macaroons = nil
+	info.SkipLogin = true
+	var called []string
+	apistate, err := api.Open(info, api.DialOpts{
+		OIDCLogin: func(issuerURL, clientID string) (string, error) {
+			called = append(called, issuerURL, clientID)
+			return "not-a-token", nil
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer apistate.Close()
+
+	err = apistate.Login(names.NewUserTag("alice@oidc"), "", "", nil)
+	c.Assert(err, gc.ErrorMatches, "login with OIDC ID token failed: ID token not valid")
+	c.Assert(called, jc.DeepEquals, []string{"https://issuer.example.com", "juju"})
+}
+
+func (s *stateSuite) TestLoginOIDCNotPossible(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.OIDCIssuerURL: "https://issuer.example.com",
+		controller.OIDCClientID:  "juju",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	apistate, _, _ := s.OpenAPIWithoutLogin(c)
+	defer apistate.Close()
+	err = apistate.Login(names.NewUserTag("alice@oidc"), "", "", nil)
+	c.Assert(err, gc.ErrorMatches, "OIDC login required: ID token required")
+}
+
 func (s *stateSuite) TestLoginTracksFacadeVersions(c *gc.C) {
 	apistate, tag, password := s.OpenAPIWithoutLogin(c)
 	defer apistate.Close()
@@ -172,7 +213,7 @@ func (s *stateSuite) TestAllFacadeVersionsSafeFromMutation(c *gc.C) {
 }
 
 func (s *stateSuite) TestBestFacadeVersion(c *gc.C) {
-	c.Check(s.APIState.BestFacadeVersion("Client"), gc.Equals, 2)
+	c.Check(s.APIState.BestFacadeVersion("Client"), gc.Equals, 3)
 }
 
 func (s *stateSuite) TestAPIHostPortsMovesConnectedValueFirst(c *gc.C) {
@@ -207,3 +207,91 @@ func (c *Client) ResetPassword(username string) ([]byte, error) {
 	}
 	return result.SecretKey, nil
 }
+
+// AddGroup adds a user group to the controller. Access may then be
+// granted to the group as "<name>@group".
+func (c *Client) AddGroup(name string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("user groups")
+	}
+	args := params.AddGroups{
+		Groups: []params.AddGroup{{Name: name}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddGroups", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// AddGroupMember adds the specified user to a user group.
+func (c *Client) AddGroupMember(group, username string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("user groups")
+	}
+	if !names.IsValidUser(username) {
+		return errors.Errorf("%q is not a valid username", username)
+	}
+	args := params.AddGroupMembers{
+		Members: []params.AddGroupMember{{
+			Group:   group,
+			UserTag: names.NewUserTag(username).String(),
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddGroupMembers", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// AddAPIToken adds an API token for the specified local user, returning
+// the token's id and the credentials with which to log in using it. The
+// credentials cannot be retrieved again later.
+func (c *Client) AddAPIToken(args params.AddAPIToken) (id, credentials string, _ error) {
+	if c.BestAPIVersion() < 4 {
+		return "", "", errors.NotSupportedf("API tokens")
+	}
+	var results params.AddAPITokenResults
+	err := c.facade.FacadeCall("AddAPIToken", params.AddAPITokens{
+		Tokens: []params.AddAPIToken{args},
+	}, &results)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return "", "", errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", "", errors.Trace(result.Error)
+	}
+	return result.Id, result.Credentials, nil
+}
+
+// APITokens returns information on the API tokens of the current user
+// or, if all is true, of every user.
+func (c *Client) APITokens(all bool) ([]params.APITokenInfo, error) {
+	if c.BestAPIVersion() < 4 {
+		return nil, errors.NotSupportedf("API tokens")
+	}
+	var results params.APITokenInfoResults
+	err := c.facade.FacadeCall("APITokens", params.APITokensRequest{All: all}, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// RevokeAPIToken revokes the API token with the given id.
+func (c *Client) RevokeAPIToken(id string) error {
+	if c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("API tokens")
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("RevokeAPIToken", params.RevokeAPITokens{Ids: []string{id}}, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -4,6 +4,8 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -270,3 +272,141 @@ func (s *usermanagerSuite) TestResetPasswordResultCount(c *gc.C) {
 	_, err := client.ResetPassword("foobar")
 	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 2")
 }
+
+func (s *usermanagerSuite) TestAddGroup(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "UserManager")
+			c.Check(request, gc.Equals, "AddGroups")
+			c.Check(arg, jc.DeepEquals, params.AddGroups{
+				Groups: []params.AddGroup{{Name: "devs"}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+		BestVersion: 3,
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.AddGroup("devs")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *usermanagerSuite) TestAddGroupNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 2,
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.AddGroup("devs")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.AddGroupMember("devs", "alice")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *usermanagerSuite) TestAddGroupMember(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "UserManager")
+			c.Check(request, gc.Equals, "AddGroupMembers")
+			c.Check(arg, jc.DeepEquals, params.AddGroupMembers{
+				Members: []params.AddGroupMember{{Group: "devs", UserTag: "user-alice@oidc"}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+		BestVersion: 3,
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.AddGroupMember("devs", "alice@oidc")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *usermanagerSuite) TestAddAPIToken(c *gc.C) {
+	expires := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "UserManager")
+			c.Check(request, gc.Equals, "AddAPIToken")
+			c.Check(arg, jc.DeepEquals, params.AddAPITokens{
+				Tokens: []params.AddAPIToken{{
+					UserTag:      "user-ci",
+					Expires:      expires,
+					FacadeGroups: []string{"deploy"},
+				}},
+			})
+			*(result.(*params.AddAPITokenResults)) = params.AddAPITokenResults{
+				Results: []params.AddAPITokenResult{{Id: "id", Credentials: "juju-api-token:id:secret"}},
+			}
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := usermanager.NewClient(apiCaller)
+	id, credentials, err := client.AddAPIToken(params.AddAPIToken{
+		UserTag:      "user-ci",
+		Expires:      expires,
+		FacadeGroups: []string{"deploy"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "id")
+	c.Assert(credentials, gc.Equals, "juju-api-token:id:secret")
+}
+
+func (s *usermanagerSuite) TestAPITokens(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "APITokens")
+			c.Check(arg, jc.DeepEquals, params.APITokensRequest{All: true})
+			*(result.(*params.APITokenInfoResults)) = params.APITokenInfoResults{
+				Results: []params.APITokenInfo{{Id: "id", UserTag: "user-ci"}},
+			}
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := usermanager.NewClient(apiCaller)
+	tokens, err := client.APITokens(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, jc.DeepEquals, []params.APITokenInfo{{Id: "id", UserTag: "user-ci"}})
+}
+
+func (s *usermanagerSuite) TestRevokeAPIToken(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "RevokeAPIToken")
+			c.Check(arg, jc.DeepEquals, params.RevokeAPITokens{Ids: []string{"id"}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.RevokeAPIToken("id")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *usermanagerSuite) TestAPITokensNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 3,
+	}
+	client := usermanager.NewClient(apiCaller)
+	_, _, err := client.AddAPIToken(params.AddAPIToken{UserTag: "user-ci"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.APITokens(false)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.RevokeAPIToken("id")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
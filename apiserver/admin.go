@@ -9,11 +9,13 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/utils/clock"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/facades/agent/presence"
@@ -25,6 +27,7 @@ import (
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 	statepresence "github.com/juju/juju/state/presence"
+	"github.com/juju/juju/state/watcher"
 	jujuversion "github.com/juju/juju/version"
 )
 
@@ -95,6 +98,17 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		logger.Infof("login failed with discharge-required error: %v", err)
 		return loginResult, nil
 	}
+	if err, ok := errors.Cause(err).(*common.OIDCLoginRequiredError); ok {
+		loginResult := params.LoginResult{
+			OIDCLoginRequired: &params.OIDCLoginInfo{
+				IssuerURL: err.IssuerURL,
+				ClientID:  err.ClientID,
+			},
+			OIDCLoginRequiredReason: err.Error(),
+		}
+		logger.Infof("login failed with OIDC-login-required error: %v", err)
+		return loginResult, nil
+	}
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
@@ -150,25 +164,96 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	recorderFactory := observer.NewRecorderFactory(
 		a.apiObserver, auditRecorder, auditConfig.CaptureAPIArgs)
 
+	// User clients record the controller's CA certificates, so they
+	// continue to trust it through a CA rotation.
+	var caCert string
+	if authResult.userLogin {
+		controllerConfig, err := a.root.state.ControllerConfig()
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		caCert, _ = controllerConfig.CACert()
+	}
+
 	a.root.rpcConn.ServeRoot(apiRoot, recorderFactory, serverError)
+	if authResult.apiToken != nil {
+		if err := a.closeOnAPITokenRevoked(authResult.apiToken); err != nil {
+			return fail, errors.Trace(err)
+		}
+	}
 	return params.LoginResult{
-		Servers:       params.FromNetworkHostsPorts(hostPorts),
-		ControllerTag: a.root.model.ControllerTag().String(),
-		UserInfo:      authResult.userInfo,
-		ServerVersion: jujuversion.Current.String(),
-		PublicDNSName: a.srv.publicDNSName(),
-		ModelTag:      modelTag,
-		Facades:       filterFacades(a.srv.facades, facadeFilters...),
+		Servers:          params.FromNetworkHostsPorts(hostPorts),
+		ControllerTag:    a.root.model.ControllerTag().String(),
+		UserInfo:         authResult.userInfo,
+		ServerVersion:    jujuversion.Current.String(),
+		PublicDNSName:    a.srv.publicDNSName(),
+		ModelTag:         modelTag,
+		Facades:          filterFacades(a.srv.facades, facadeFilters...),
+		ControllerCACert: caCert,
 	}, nil
 }
 
+// closeOnAPITokenRevoked closes the connection once the API token it
+// logged in with is revoked or expires, so that revoking a token cuts
+// off the sessions already using it.
+func (a *admin) closeOnAPITokenRevoked(token *state.APIToken) error {
+	w := a.root.state.WatchAPIToken(token.Id())
+	if _, ok := <-w.Changes(); !ok {
+		return errors.Annotate(watcher.EnsureErr(w), "cannot watch API token")
+	}
+	// The watcher is stopped along with the connection's other
+	// resources, which ends the goroutine below.
+	a.root.resources.Register(w)
+
+	st := a.root.state
+	conn := a.root.rpcConn
+	connectionID := a.root.connectionID
+	expired := a.srv.clock.After(token.Expires().Sub(a.srv.clock.Now()))
+	go func() {
+		for {
+			select {
+			case _, ok := <-w.Changes():
+				if !ok {
+					return
+				}
+				_, err := st.APIToken(token.Id())
+				if err == nil {
+					continue
+				} else if !errors.IsNotFound(err) {
+					logger.Errorf("cannot check API token %s: %v", token.Id(), err)
+					continue
+				}
+				logger.Infof("closing connection %d: API token %s revoked", connectionID, token.Id())
+			case <-expired:
+				logger.Infof("closing connection %d: API token %s expired", connectionID, token.Id())
+			}
+			conn.Close()
+			return
+		}
+	}()
+	return nil
+}
+
 func (a *admin) getAuditRecorder(req params.LoginRequest, authResult *authResult, cfg auditlog.Config) (*auditlog.Recorder, error) {
-	if !authResult.userLogin || !cfg.Enabled {
+	if !authResult.userLogin {
 		return nil, nil
 	}
 	// Wrap the audit logger in a filter that prevents us from logging
 	// lots of readonly conversations (like "juju status" requests).
+	// Every use of an API token is recorded, whether or not auditing
+	// is enabled, so that conversations are never filtered for token
+	// logins.
 	filter := observer.MakeInterestingRequestFilter(cfg.ExcludeMethods)
+	var apiTokenID string
+	if authResult.apiToken != nil {
+		if cfg.Target == nil {
+			return nil, errors.New("cannot record API token login: no audit log")
+		}
+		apiTokenID = authResult.apiToken.Id()
+		filter = func(auditlog.Request) bool { return true }
+	} else if !cfg.Enabled {
+		return nil, nil
+	}
 	result, err := auditlog.NewRecorder(
 		observer.NewAuditLogFilter(cfg.Target, filter),
 		a.srv.clock,
@@ -178,6 +263,7 @@ func (a *admin) getAuditRecorder(req params.LoginRequest, authResult *authResult
 			ModelName:    a.root.model.Name(),
 			ModelUUID:    a.root.model.UUID(),
 			ConnectionID: a.root.connectionID,
+			APITokenID:   apiTokenID,
 		},
 	)
 	if err != nil {
@@ -194,6 +280,14 @@ type authResult struct {
 	controllerOnlyLogin    bool
 	controllerMachineLogin bool
 	userInfo               *params.AuthUserInfo
+
+	// apiToken holds the API token the user logged in
+	// with, if any; its restrictions apply to the connection.
+	apiToken *state.APIToken
+
+	// userTag holds the tag of the authenticated user,
+	// local or external, for user logins.
+	userTag names.UserTag
 }
 
 func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
@@ -261,6 +355,12 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 		if err != nil {
 			return nil, a.handleAuthError(err)
 		}
+		if tokenEntity, ok := authInfo.Entity.(*authentication.TokenEntity); ok {
+			if err := a.checkAPITokenModel(tokenEntity.Token, result.controllerOnlyLogin); err != nil {
+				return nil, errors.Trace(err)
+			}
+			result.apiToken = tokenEntity.Token
+		}
 		result.controllerMachineLogin = authInfo.Controller
 		// controllerConn is used to indicate a connection from the controller
 		// to a non-controller model.
@@ -272,6 +372,9 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 			controllerConn = true
 		}
 		a.root.entity = authInfo.Entity
+		if userTag, ok := authInfo.Entity.Tag().(names.UserTag); ok {
+			result.userTag = userTag
+		}
 		// TODO(wallyworld) - we can't yet observe anonymous logins as entity must be non-nil
 		a.apiObserver.Login(
 			authInfo.Entity.Tag(),
@@ -301,10 +404,28 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 	return result, nil
 }
 
+// checkAPITokenModel checks that the API token may be used for the
+// connection: a token restricted to some models may only be used to
+// log in to those models, and not to the controller.
+func (a *admin) checkAPITokenModel(token *state.APIToken, controllerOnlyLogin bool) error {
+	models := token.Models()
+	if len(models) == 0 {
+		return nil
+	}
+	if controllerOnlyLogin || !set.NewStrings(models...).Contains(a.root.model.UUID()) {
+		logger.Debugf("API token %s not valid for this connection", token.Id())
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func (a *admin) handleAuthError(err error) error {
 	if err, ok := errors.Cause(err).(*common.DischargeRequiredError); ok {
 		return err
 	}
+	if err, ok := errors.Cause(err).(*common.OIDCLoginRequiredError); ok {
+		return err
+	}
 	if a.maintenanceInProgress() {
 		// An upgrade, restore or similar operation is in
 		// progress. It is possible for logins to fail until this
@@ -370,6 +491,15 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 	} else {
 		return nil, errors.Annotatef(err, "obtaining ControllerUser for logged in user %s", userTag.Id())
 	}
+	// Access granted to any of the user's groups applies to the user too.
+	effectiveAccess := common.EffectiveUserAccess(a.root.state)
+	if groupAccess, err := effectiveAccess(userTag, a.root.state.ControllerTag()); err == nil {
+		if groupAccess.GreaterControllerAccessThan(controllerAccess) {
+			controllerAccess = groupAccess
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Annotatef(err, "obtaining group controller access for logged in user %s", userTag.Id())
+	}
 	if !controllerOnlyLogin {
 		// Only grab modelUser permissions if this is not a controller only
 		// login. In all situations, if the model user is not found, they have
@@ -377,7 +507,7 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 		// admin.
 
 		var err error
-		modelAccess, err = a.root.state.UserPermission(userTag, a.root.model.ModelTag())
+		modelAccess, err = effectiveAccess(userTag, a.root.model.ModelTag())
 		if err != nil && controllerAccess != permission.SuperuserAccess {
 			return nil, errors.Wrap(err, common.ErrPerm)
 		}
@@ -131,6 +131,28 @@ func (s *loginSuite) TestLoginWithInvalidTag(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `.*"bar" is not a valid tag.*`)
 }
 
+func (s *loginSuite) TestLoginOIDCUserRequiresIDToken(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		corecontroller.OIDCIssuerURL: "https://issuer.example.com",
+		corecontroller.OIDCClientID:  "juju",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info := s.APIInfo(c)
+	st := s.openAPIWithoutLogin(c, info)
+
+	request := &params.LoginRequest{
+		AuthTag: names.NewUserTag("alice@oidc").String(),
+	}
+	var response params.LoginResult
+	err = st.APICall("Admin", 3, "", "Login", request, &response)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response.OIDCLoginRequired, jc.DeepEquals, &params.OIDCLoginInfo{
+		IssuerURL: "https://issuer.example.com",
+		ClientID:  "juju",
+	})
+	c.Assert(response.OIDCLoginRequiredReason, gc.Equals, "ID token required")
+}
+
 func (s *loginSuite) TestBadLogin(c *gc.C) {
 	// Start our own server so we can control when the first login
 	// happens. Otherwise in JujuConnSuite.SetUpTest api.Open is
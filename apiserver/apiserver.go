@@ -61,6 +61,7 @@ type Server struct {
 	dataDir                string
 	logDir                 string
 	limiter                utils.Limiter
+	callLimiter            *callLimiter
 	loginRetryPause        time.Duration
 	facades                *facade.Registry
 	modelUUID              string
@@ -255,6 +256,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		dataDir:                       cfg.DataDir,
 		logDir:                        cfg.LogDir,
 		limiter:                       limiter,
+		callLimiter:                   newCallLimiter(cfg.RateLimitConfig, cfg.Clock),
 		loginRetryPause:               cfg.RateLimitConfig.LoginRetryPause,
 		upgradeComplete:               cfg.UpgradeComplete,
 		restoreStatus:                 cfg.RestoreStatus,
@@ -295,6 +297,10 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		if err := cfg.PrometheusRegisterer.Register(apiserverCollectior); err != nil {
 			return nil, errors.Annotate(err, "registering apiserver metrics collector")
 		}
+		cfg.PrometheusRegisterer.Unregister(srv.callLimiter)
+		if err := cfg.PrometheusRegisterer.Register(srv.callLimiter); err != nil {
+			return nil, errors.Annotate(err, "registering apiserver call limiter metrics")
+		}
 	}
 
 	ready := make(chan struct{})
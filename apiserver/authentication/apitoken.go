@@ -0,0 +1,233 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// APITokenCredentialsPrefix prefixes the credentials presented by a user
+// logging in with an API token. The credentials take the form
+// "<prefix><token id>:<secret>".
+const APITokenCredentialsPrefix = "juju-api-token:"
+
+// IsAPITokenCredentials reports whether the login credentials hold an
+// API token rather than a password.
+func IsAPITokenCredentials(credentials string) bool {
+	return strings.HasPrefix(credentials, APITokenCredentialsPrefix)
+}
+
+// APITokenCredentials returns the login credentials for the API token
+// with the given id and secret.
+func APITokenCredentials(id, secret string) string {
+	return APITokenCredentialsPrefix + id + ":" + secret
+}
+
+func parseAPITokenCredentials(credentials string) (id, secret string, ok bool) {
+	if !IsAPITokenCredentials(credentials) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(credentials, APITokenCredentialsPrefix), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// TokenEntity is the entity that has logged in with an API token. The
+// token's restrictions apply to the connection.
+type TokenEntity struct {
+	state.Entity
+	Token *state.APIToken
+}
+
+// IsManager reports whether the underlying entity manages the
+// controller.
+func (e *TokenEntity) IsManager() bool {
+	if entity, ok := e.Entity.(interface {
+		IsManager() bool
+	}); ok {
+		return entity.IsManager()
+	}
+	return false
+}
+
+// LastLogin returns the last login time of the underlying entity.
+func (e *TokenEntity) LastLogin() (time.Time, error) {
+	if entity, ok := e.Entity.(interface {
+		LastLogin() (time.Time, error)
+	}); ok {
+		return entity.LastLogin()
+	}
+	return time.Time{}, state.NeverLoggedInError(e.Tag().Id())
+}
+
+// UpdateLastLogin updates the last login time of the underlying
+// entity.
+func (e *TokenEntity) UpdateLastLogin() error {
+	if entity, ok := e.Entity.(interface {
+		UpdateLastLogin() error
+	}); ok {
+		return entity.UpdateLastLogin()
+	}
+	return nil
+}
+
+// APITokenBackend provides the state needed to authenticate API token
+// logins.
+type APITokenBackend interface {
+	APIToken(id string) (*state.APIToken, error)
+	User(tag names.UserTag) (*state.User, error)
+}
+
+// APITokenAuthenticator authenticates local users logging in with an
+// API token issued by the controller.
+type APITokenAuthenticator struct {
+	Backend APITokenBackend
+	Clock   clock.Clock
+}
+
+var _ EntityAuthenticator = (*APITokenAuthenticator)(nil)
+
+// Authenticate implements EntityAuthenticator. On success the returned
+// entity is a *TokenEntity.
+func (a *APITokenAuthenticator) Authenticate(
+	entityFinder EntityFinder, tag names.Tag, req params.LoginRequest,
+) (state.Entity, error) {
+	userTag, ok := tag.(names.UserTag)
+	if !ok || !userTag.IsLocal() {
+		return nil, errors.Errorf("invalid request")
+	}
+	id, secret, ok := parseAPITokenCredentials(req.Credentials)
+	if !ok {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	token, err := a.Backend.APIToken(id)
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if token.UserTag().Id() != userTag.Id() || !token.SecretValid(secret) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	if token.Expired(a.Clock.Now()) {
+		logger.Debugf("API token %s for %s has expired", token.Id(), userTag.Id())
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	user, err := a.Backend.User(userTag)
+	if _, deleted := errors.Cause(err).(state.DeletedUserError); deleted || errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if user.IsDisabled() {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	entity, err := entityFinder.FindEntity(userTag)
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &TokenEntity{Entity: entity, Token: token}, nil
+}
+
+// apiTokenFacadeGroups holds the facade method groups that an API token
+// may be restricted to. Each group maps facade names to the methods in
+// the group; a nil method set allows every method of the facade.
+var apiTokenFacadeGroups = map[string]map[string]set.Strings{
+	"status": {
+		"Client": set.NewStrings("FullStatus", "StatusHistory", "GetModelConstraints"),
+	},
+	"deploy": {
+		"Application": nil,
+		"Charms":      nil,
+		"Resources":   nil,
+		"Client":      set.NewStrings("AddCharm", "AddCharmWithAuthorization", "ResolveCharms", "FullStatus"),
+	},
+	"actions": {
+		"Action": nil,
+	},
+	"machines": {
+		"MachineManager": nil,
+	},
+	"config": {
+		"ModelConfig": nil,
+	},
+	"backups": {
+		"Backups": nil,
+	},
+}
+
+// APITokenReadOnlyGroup names the facade method group that allows
+// the methods that don't change anything.
+const APITokenReadOnlyGroup = "read-only"
+
+// ValidateAPITokenFacadeGroups returns an error if any of the given
+// facade method group names is unknown.
+func ValidateAPITokenFacadeGroups(groups []string) error {
+	for _, group := range groups {
+		if _, ok := apiTokenFacadeGroups[group]; !ok && group != APITokenReadOnlyGroup {
+			return errors.NotValidf("facade group %q", group)
+		}
+	}
+	return nil
+}
+
+// APITokenFacadeGroups returns the names of the facade method groups
+// that an API token may be restricted to.
+func APITokenFacadeGroups() []string {
+	groups := set.NewStrings(APITokenReadOnlyGroup)
+	for group := range apiTokenFacadeGroups {
+		groups.Add(group)
+	}
+	return groups.SortedValues()
+}
+
+// IsAPITokenMethodAllowed reports whether a connection authenticated
+// with an API token restricted to the given facade method groups may
+// call the given method. Tokens may never be used to add further
+// tokens or to change passwords.
+func IsAPITokenMethodAllowed(groups []string, facadeName, methodName string) bool {
+	if facadeName == "Pinger" {
+		return true
+	}
+	if facadeName == "UserManager" && (methodName == "AddAPIToken" || methodName == "SetPassword") {
+		return false
+	}
+	if len(groups) == 0 {
+		return true
+	}
+	return set.NewStrings(groups...).Intersection(
+		set.NewStrings(FacadeMethodGroups(facadeName, methodName)...),
+	).Size() > 0
+}
+
+// FacadeMethodGroups returns the names of the facade method groups,
+// as used to restrict API tokens, that contain the given method.
+func FacadeMethodGroups(facadeName, methodName string) []string {
+	var groups []string
+	if observer.IsReadOnlyMethod(facadeName, methodName) {
+		groups = append(groups, APITokenReadOnlyGroup)
+	}
+	for group, facades := range apiTokenFacadeGroups {
+		methods, ok := facades[facadeName]
+		if ok && (methods == nil || methods.Contains(methodName)) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,168 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type apiTokenAuthenticatorSuite struct {
+	jujutesting.JujuConnSuite
+
+	user   *state.User
+	token  *state.APIToken
+	secret string
+}
+
+var _ = gc.Suite(&apiTokenAuthenticatorSuite{})
+
+func (s *apiTokenAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.user = s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	var err error
+	s.token, s.secret, err = s.State.AddAPIToken(state.AddAPITokenArgs{
+		User:      s.user.UserTag(),
+		CreatedBy: s.AdminUserTag(c),
+		Expires:   time.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *apiTokenAuthenticatorSuite) authenticate(tag names.Tag, credentials string) (state.Entity, error) {
+	authenticator := &authentication.APITokenAuthenticator{
+		Backend: s.State,
+		Clock:   clock.WallClock,
+	}
+	return authenticator.Authenticate(entityFinder{s.user}, tag, params.LoginRequest{
+		Credentials: credentials,
+	})
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticate(c *gc.C) {
+	c.Assert(authentication.IsAPITokenCredentials(s.secret), jc.IsFalse)
+	credentials := authentication.APITokenCredentials(s.token.Id(), s.secret)
+	c.Assert(authentication.IsAPITokenCredentials(credentials), jc.IsTrue)
+
+	entity, err := s.authenticate(s.user.UserTag(), credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	tokenEntity, ok := entity.(*authentication.TokenEntity)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(tokenEntity.Tag(), gc.Equals, s.user.Tag())
+	c.Assert(tokenEntity.Token.Id(), gc.Equals, s.token.Id())
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticateBadCredentials(c *gc.C) {
+	other := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	for i, test := range []struct {
+		tag         names.Tag
+		credentials string
+	}{{
+		tag:         s.user.UserTag(),
+		credentials: authentication.APITokenCredentials(s.token.Id(), "wrong"),
+	}, {
+		tag:         s.user.UserTag(),
+		credentials: authentication.APITokenCredentials("no-such-token", s.secret),
+	}, {
+		tag:         s.user.UserTag(),
+		credentials: authentication.APITokenCredentialsPrefix + s.token.Id(),
+	}, {
+		tag:         other.UserTag(),
+		credentials: authentication.APITokenCredentials(s.token.Id(), s.secret),
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.authenticate(test.tag, test.credentials)
+		c.Check(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	}
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticateRevoked(c *gc.C) {
+	err := s.State.RevokeAPIToken(s.token.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticate(s.user.UserTag(), authentication.APITokenCredentials(s.token.Id(), s.secret))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticateExpired(c *gc.C) {
+	authenticator := &authentication.APITokenAuthenticator{
+		Backend: s.State,
+		Clock:   testing.NewClock(s.token.Expires()),
+	}
+	_, err := authenticator.Authenticate(entityFinder{s.user}, s.user.UserTag(), params.LoginRequest{
+		Credentials: authentication.APITokenCredentials(s.token.Id(), s.secret),
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticateDisabledUser(c *gc.C) {
+	err := s.user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticate(s.user.UserTag(), authentication.APITokenCredentials(s.token.Id(), s.secret))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *apiTokenAuthenticatorSuite) TestTokenEntityForwardsLoginDetails(c *gc.C) {
+	entity, err := s.authenticate(s.user.UserTag(), authentication.APITokenCredentials(s.token.Id(), s.secret))
+	c.Assert(err, jc.ErrorIsNil)
+	tokenEntity := entity.(*authentication.TokenEntity)
+
+	_, err = tokenEntity.LastLogin()
+	c.Assert(state.IsNeverLoggedInError(err), jc.IsTrue)
+	err = tokenEntity.UpdateLastLogin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The login is recorded against the user.
+	err = s.user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	userLastLogin, err := s.user.LastLogin()
+	c.Assert(err, jc.ErrorIsNil)
+	lastLogin, err := tokenEntity.LastLogin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lastLogin, gc.Equals, userLastLogin)
+	c.Assert(tokenEntity.IsManager(), jc.IsFalse)
+}
+
+func (s *apiTokenAuthenticatorSuite) TestTokenEntityIsManager(c *gc.C) {
+	tokenEntity := &authentication.TokenEntity{
+		Entity: managerEntity{&fakeEntity{tag: s.user.Tag()}},
+		Token:  s.token,
+	}
+	c.Assert(tokenEntity.IsManager(), jc.IsTrue)
+
+	// Entities that don't record logins are treated as never
+	// having logged in.
+	tokenEntity.Entity = &fakeEntity{tag: s.user.Tag()}
+	c.Assert(tokenEntity.IsManager(), jc.IsFalse)
+	_, err := tokenEntity.LastLogin()
+	c.Assert(state.IsNeverLoggedInError(err), jc.IsTrue)
+	c.Assert(tokenEntity.UpdateLastLogin(), jc.ErrorIsNil)
+}
+
+type managerEntity struct {
+	state.Entity
+}
+
+func (managerEntity) IsManager() bool {
+	return true
+}
+
+func (s *apiTokenAuthenticatorSuite) TestFacadeMethodGroups(c *gc.C) {
+	c.Assert(authentication.FacadeMethodGroups("Client", "FullStatus"), jc.SameContents,
+		[]string{"read-only", "status", "deploy"})
+	c.Assert(authentication.FacadeMethodGroups("Action", "Enqueue"), jc.DeepEquals, []string{"actions"})
+	c.Assert(authentication.FacadeMethodGroups("Firewaller", "WatchOpenedPorts"), gc.HasLen, 0)
+}
@@ -0,0 +1,12 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import "net/http"
+
+// OIDCVerifierClient returns the HTTP client the verifier uses to
+// contact the issuer.
+func OIDCVerifierClient(v *OIDCVerifier) *http.Client {
+	return v.client
+}
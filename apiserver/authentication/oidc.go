@@ -0,0 +1,356 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// OIDCUserDomain is the domain given to users whose identity
+// is asserted by the controller's OpenID Connect issuer.
+const OIDCUserDomain = "oidc"
+
+const (
+	// oidcHTTPTimeout bounds each request made to the issuer, so that
+	// an unresponsive issuer cannot hold up logins indefinitely.
+	oidcHTTPTimeout = 30 * time.Second
+
+	// oidcKeyRefetchInterval is the minimum time between fetches of
+	// the issuer's key set prompted by tokens signed by unknown keys.
+	oidcKeyRefetchInterval = time.Minute
+)
+
+// OIDCConfig holds the controller configuration needed to verify
+// OpenID Connect ID tokens.
+type OIDCConfig struct {
+	// IssuerURL is the URL of the OpenID Connect issuer. ID tokens
+	// must carry this value in their "iss" claim.
+	IssuerURL string
+
+	// ClientID is the client ID registered with the issuer for
+	// the controller. ID tokens must carry this value in their
+	// "aud" claim.
+	ClientID string
+
+	// UsernameClaim names the claim holding the Juju user name.
+	UsernameClaim string
+
+	// GroupsClaim names the claim holding the groups the user
+	// belongs to.
+	GroupsClaim string
+}
+
+// OIDCIdentity describes a user whose ID token has been verified.
+type OIDCIdentity struct {
+	// Tag is the tag of the user, always in the OIDC user domain.
+	Tag names.UserTag
+
+	// Groups holds the names of the groups asserted by the issuer.
+	Groups []string
+
+	// Expiry holds the time at which the ID token expires.
+	Expiry time.Time
+}
+
+// OIDCVerifier verifies ID tokens issued by an OpenID Connect issuer.
+// The issuer's signing keys are fetched on demand, and refetched when
+// a token is signed by an unknown key, at most once a minute.
+type OIDCVerifier struct {
+	config OIDCConfig
+	client *http.Client
+	clock  clock.Clock
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      map[string]*rsa.PublicKey
+	lastFetch time.Time
+}
+
+// NewOIDCVerifier returns a new OIDCVerifier for the given
+// configuration. If client is nil, a client which times out
+// requests after 30 seconds is used.
+func NewOIDCVerifier(config OIDCConfig, client *http.Client, clock clock.Clock) *OIDCVerifier {
+	if client == nil {
+		client = &http.Client{Timeout: oidcHTTPTimeout}
+	}
+	return &OIDCVerifier{
+		config: config,
+		client: client,
+		clock:  clock,
+	}
+}
+
+// Config returns the configuration the verifier was created with.
+func (v *OIDCVerifier) Config() OIDCConfig {
+	return v.config
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify checks the signature and standard claims of the given
+// ID token, and returns the identity it asserts.
+func (v *OIDCVerifier) Verify(token string) (*OIDCIdentity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.NotValidf("ID token")
+	}
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, errors.Annotate(err, "cannot decode ID token header")
+	}
+	if header.Alg != "RS256" {
+		return nil, errors.NotSupportedf("ID token signing algorithm %q", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode ID token signature")
+	}
+	key, err := v.key(header.Kid)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("ID token signature not valid")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, errors.Annotate(err, "cannot decode ID token claims")
+	}
+	if iss, _ := claims["iss"].(string); iss != v.config.IssuerURL {
+		return nil, errors.Errorf("ID token issued by %q, expected %q", iss, v.config.IssuerURL)
+	}
+	if !audienceContains(claims["aud"], v.config.ClientID) {
+		return nil, errors.Errorf("ID token not issued for client %q", v.config.ClientID)
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("ID token has no expiry")
+	}
+	expiry := time.Unix(int64(exp), 0)
+	if !v.clock.Now().Before(expiry) {
+		return nil, errors.New("ID token has expired")
+	}
+
+	username, _ := claims[v.config.UsernameClaim].(string)
+	if !names.IsValidUserName(username) {
+		return nil, errors.Errorf("ID token claim %q holds invalid user name %q", v.config.UsernameClaim, username)
+	}
+	identity := &OIDCIdentity{
+		Tag:    names.NewLocalUserTag(username).WithDomain(OIDCUserDomain),
+		Expiry: expiry,
+	}
+	if groups, ok := claims[v.config.GroupsClaim].([]interface{}); ok {
+		for _, group := range groups {
+			if group, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, group)
+			}
+		}
+	}
+	return identity, nil
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(data, v))
+}
+
+// key returns the issuer's public key with the given ID, fetching
+// the issuer's key set if the key is not already known. The key set
+// is not fetched again within oidcKeyRefetchInterval of the last
+// fetch, so that tokens signed by unknown keys cannot be used to
+// flood the issuer with requests.
+func (v *OIDCVerifier) key(kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	now := v.clock.Now()
+	if !v.lastFetch.IsZero() && now.Before(v.lastFetch.Add(oidcKeyRefetchInterval)) {
+		return nil, errors.NotFoundf("ID token signing key %q", kid)
+	}
+	v.lastFetch = now
+	if err := v.fetchKeys(); err != nil {
+		return nil, errors.Annotate(err, "cannot fetch OIDC issuer keys")
+	}
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, errors.NotFoundf("ID token signing key %q", kid)
+}
+
+// fetchKeys fetches the issuer's key set, discovering its location
+// from the issuer's OpenID configuration if necessary.
+func (v *OIDCVerifier) fetchKeys() error {
+	if v.jwksURL == "" {
+		discovery, err := FetchOIDCDiscovery(v.client, v.config.IssuerURL)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		v.jwksURL = discovery.JWKSURI
+	}
+	var keySet struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := getJSON(v.client, v.jwksURL, &keySet); err != nil {
+		return errors.Trace(err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range keySet.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return errors.Annotatef(err, "decoding modulus of key %q", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return errors.Annotatef(err, "decoding exponent of key %q", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	v.keys = keys
+	return nil
+}
+
+// OIDCDiscovery holds the parts of an issuer's OpenID configuration
+// that Juju uses.
+type OIDCDiscovery struct {
+	Issuer                      string `json:"issuer"`
+	JWKSURI                     string `json:"jwks_uri"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// FetchOIDCDiscovery fetches the OpenID configuration of the
+// given issuer.
+func FetchOIDCDiscovery(client *http.Client, issuerURL string) (*OIDCDiscovery, error) {
+	var discovery OIDCDiscovery
+	url := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(client, url, &discovery); err != nil {
+		return nil, errors.Annotate(err, "cannot fetch OIDC discovery document")
+	}
+	if discovery.Issuer != issuerURL {
+		return nil, errors.Errorf("OIDC discovery document is for issuer %q, expected %q", discovery.Issuer, issuerURL)
+	}
+	return &discovery, nil
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(v))
+}
+
+// OIDCAuthenticator performs authentication for users whose identity
+// is asserted by an OpenID Connect issuer. The ID token is passed as
+// the login request's credentials. If no token is provided, or the
+// token has expired, a *common.OIDCLoginRequiredError is returned so
+// that the client can obtain a new token from the issuer.
+type OIDCAuthenticator struct {
+	Verifier *OIDCVerifier
+
+	// RecordGroups, if non-nil, is called with the groups claimed
+	// in a verified ID token before the user's entity is found, so
+	// that access granted to those groups applies to the user.
+	RecordGroups func(names.UserTag, []string) error
+}
+
+var _ EntityAuthenticator = (*OIDCAuthenticator)(nil)
+
+// Authenticate implements EntityAuthenticator.
+func (a *OIDCAuthenticator) Authenticate(
+	entityFinder EntityFinder, tag names.Tag, req params.LoginRequest,
+) (state.Entity, error) {
+	userTag, ok := tag.(names.UserTag)
+	if !ok || userTag.Domain() != OIDCUserDomain {
+		return nil, errors.Errorf("invalid request")
+	}
+	config := a.Verifier.Config()
+	if req.Credentials == "" {
+		return nil, &common.OIDCLoginRequiredError{
+			Cause:     errors.New("ID token required"),
+			IssuerURL: config.IssuerURL,
+			ClientID:  config.ClientID,
+		}
+	}
+	identity, err := a.Verifier.Verify(req.Credentials)
+	if err != nil {
+		logger.Debugf("OIDC authentication for %s failed: %v", userTag.Id(), err)
+		return nil, &common.OIDCLoginRequiredError{
+			Cause:     err,
+			IssuerURL: config.IssuerURL,
+			ClientID:  config.ClientID,
+		}
+	}
+	if identity.Tag.Id() != userTag.Id() {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	if a.RecordGroups != nil {
+		if err := a.RecordGroups(userTag, identity.Groups); err != nil {
+			return nil, errors.Annotate(err, "cannot record group claims")
+		}
+	}
+	entity, err := entityFinder.FindEntity(userTag)
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return entity, nil
+}
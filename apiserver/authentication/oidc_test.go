@@ -0,0 +1,203 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/oidctest"
+)
+
+type oidcSuite struct {
+	testing.IsolationSuite
+	issuer   *oidctest.Issuer
+	verifier *authentication.OIDCVerifier
+}
+
+var _ = gc.Suite(&oidcSuite{})
+
+func (s *oidcSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.issuer = oidctest.NewIssuer()
+	s.AddCleanup(func(*gc.C) { s.issuer.Close() })
+	s.verifier = authentication.NewOIDCVerifier(authentication.OIDCConfig{
+		IssuerURL:     s.issuer.URL,
+		ClientID:      "juju",
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+	}, s.issuer.Client(), clock.WallClock)
+}
+
+func (s *oidcSuite) TestVerify(c *gc.C) {
+	claims := s.issuer.Claims("juju", "alice", []string{"admins", "devs"}, time.Hour)
+	identity, err := s.verifier.Verify(s.issuer.IDToken(claims))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity.Tag, gc.Equals, names.NewUserTag("alice@oidc"))
+	c.Assert(identity.Groups, jc.DeepEquals, []string{"admins", "devs"})
+}
+
+func (s *oidcSuite) TestVerifyAudienceList(c *gc.C) {
+	claims := s.issuer.Claims("juju", "alice", nil, time.Hour)
+	claims["aud"] = []string{"other", "juju"}
+	identity, err := s.verifier.Verify(s.issuer.IDToken(claims))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity.Groups, gc.HasLen, 0)
+}
+
+func (s *oidcSuite) TestVerifyFailures(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		modify func(map[string]interface{})
+		err    string
+	}{{
+		about:  "wrong issuer",
+		modify: func(claims map[string]interface{}) { claims["iss"] = "https://elsewhere.example.com" },
+		err:    `ID token issued by "https://elsewhere.example.com", expected ".*"`,
+	}, {
+		about:  "wrong audience",
+		modify: func(claims map[string]interface{}) { claims["aud"] = "other" },
+		err:    `ID token not issued for client "juju"`,
+	}, {
+		about:  "expired",
+		modify: func(claims map[string]interface{}) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+		err:    `ID token has expired`,
+	}, {
+		about:  "invalid user name",
+		modify: func(claims map[string]interface{}) { claims["preferred_username"] = "alice@example.com" },
+		err:    `ID token claim "preferred_username" holds invalid user name "alice@example.com"`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		claims := s.issuer.Claims("juju", "alice", nil, time.Hour)
+		test.modify(claims)
+		_, err := s.verifier.Verify(s.issuer.IDToken(claims))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *oidcSuite) TestVerifyBadSignature(c *gc.C) {
+	claims := s.issuer.Claims("juju", "alice", nil, time.Hour)
+	_, err := s.verifier.Verify(s.issuer.ForeignIDToken(claims))
+	c.Assert(err, gc.ErrorMatches, "ID token signature not valid")
+}
+
+func (s *oidcSuite) TestUnknownKeyRefetchRateLimited(c *gc.C) {
+	clk := testing.NewClock(time.Now())
+	verifier := authentication.NewOIDCVerifier(s.verifier.Config(), s.issuer.Client(), clk)
+	claims := s.issuer.Claims("juju", "alice", nil, time.Hour)
+	_, err := verifier.Verify(s.issuer.IDToken(claims))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.issuer.KeySetRequests(), gc.Equals, 1)
+
+	// The first token signed by an unknown key may prompt a refetch,
+	// but not until a minute after the previous one.
+	token := s.issuer.UnknownKeyIDToken(claims)
+	for i := 0; i < 3; i++ {
+		_, err = verifier.Verify(token)
+		c.Assert(err, gc.ErrorMatches, `ID token signing key "unknown-key" not found`)
+	}
+	c.Assert(s.issuer.KeySetRequests(), gc.Equals, 1)
+
+	clk.Advance(time.Minute)
+	_, err = verifier.Verify(token)
+	c.Assert(err, gc.ErrorMatches, `ID token signing key "unknown-key" not found`)
+	c.Assert(s.issuer.KeySetRequests(), gc.Equals, 2)
+
+	// Known keys are still accepted in between.
+	_, err = verifier.Verify(s.issuer.IDToken(claims))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.issuer.KeySetRequests(), gc.Equals, 2)
+}
+
+func (s *oidcSuite) TestDefaultClientHasTimeout(c *gc.C) {
+	verifier := authentication.NewOIDCVerifier(s.verifier.Config(), nil, clock.WallClock)
+	client := authentication.OIDCVerifierClient(verifier)
+	c.Assert(client, gc.Not(gc.Equals), http.DefaultClient)
+	c.Assert(client.Timeout, gc.Equals, 30*time.Second)
+}
+
+func (s *oidcSuite) TestVerifyMalformed(c *gc.C) {
+	_, err := s.verifier.Verify("not-a-token")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *oidcSuite) TestAuthenticate(c *gc.C) {
+	tag := names.NewUserTag("alice@oidc")
+	entity := &fakeEntity{tag: tag}
+	auth := &authentication.OIDCAuthenticator{Verifier: s.verifier}
+	claims := s.issuer.Claims("juju", "alice", nil, time.Hour)
+	result, err := auth.Authenticate(entityFinder{entity}, tag, params.LoginRequest{
+		AuthTag:     tag.String(),
+		Credentials: s.issuer.IDToken(claims),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, entity)
+}
+
+func (s *oidcSuite) TestAuthenticateRecordsGroups(c *gc.C) {
+	tag := names.NewUserTag("alice@oidc")
+	var recorded []string
+	auth := &authentication.OIDCAuthenticator{
+		Verifier: s.verifier,
+		RecordGroups: func(user names.UserTag, groups []string) error {
+			c.Check(user, gc.Equals, tag)
+			recorded = groups
+			return nil
+		},
+	}
+	claims := s.issuer.Claims("juju", "alice", []string{"devs"}, time.Hour)
+	_, err := auth.Authenticate(entityFinder{&fakeEntity{tag: tag}}, tag, params.LoginRequest{
+		AuthTag:     tag.String(),
+		Credentials: s.issuer.IDToken(claims),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, jc.DeepEquals, []string{"devs"})
+}
+
+func (s *oidcSuite) TestAuthenticateOtherUser(c *gc.C) {
+	tag := names.NewUserTag("bob@oidc")
+	auth := &authentication.OIDCAuthenticator{Verifier: s.verifier}
+	claims := s.issuer.Claims("juju", "alice", nil, time.Hour)
+	_, err := auth.Authenticate(entityFinder{&fakeEntity{tag: tag}}, tag, params.LoginRequest{
+		AuthTag:     tag.String(),
+		Credentials: s.issuer.IDToken(claims),
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *oidcSuite) TestAuthenticateLoginRequired(c *gc.C) {
+	tag := names.NewUserTag("alice@oidc")
+	auth := &authentication.OIDCAuthenticator{Verifier: s.verifier}
+	expired := s.issuer.IDToken(s.issuer.Claims("juju", "alice", nil, -time.Minute))
+	for _, credentials := range []string{"", expired} {
+		_, err := auth.Authenticate(entityFinder{&fakeEntity{tag: tag}}, tag, params.LoginRequest{
+			AuthTag:     tag.String(),
+			Credentials: credentials,
+		})
+		c.Assert(err, jc.Satisfies, common.IsOIDCLoginRequiredError)
+		loginErr := errors.Cause(err).(*common.OIDCLoginRequiredError)
+		c.Check(loginErr.IssuerURL, gc.Equals, s.issuer.URL)
+		c.Check(loginErr.ClientID, gc.Equals, "juju")
+	}
+}
+
+type fakeEntity struct {
+	state.Entity
+	tag names.Tag
+}
+
+func (e *fakeEntity) Tag() names.Tag {
+	return e.tag
+}
@@ -217,6 +217,16 @@ type ExternalMacaroonAuthenticator struct {
 	// that is used to address the is-authenticated-user
 	// third party caveat to.
 	IdentityLocation string
+
+	// GetGroups, if non-nil, is called with the name declared by the
+	// identity service to find the groups it records the user as
+	// belonging to.
+	GetGroups func(username string) ([]string, error)
+
+	// RecordGroups, if non-nil, is called with the groups returned by
+	// GetGroups before the user's entity is found, so that access
+	// granted to those groups applies to the user.
+	RecordGroups func(names.UserTag, []string) error
 }
 
 var _ EntityAuthenticator = (*ExternalMacaroonAuthenticator)(nil)
@@ -277,6 +287,16 @@ func (m *ExternalMacaroonAuthenticator) Authenticate(entityFinder EntityFinder,
 		if tag.IsLocal() {
 			return nil, errors.Errorf("external identity provider has provided ostensibly local name %q", username)
 		}
+		if state.IsUserGroupTag(tag) {
+			// Tags in the group domain stand for user groups when
+			// granting access, so no user may log in with one.
+			return nil, errors.Errorf("external identity provider has provided reserved group name %q", username)
+		}
+	}
+	if m.GetGroups != nil && m.RecordGroups != nil {
+		if err := m.recordGroups(tag, username); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	entity, err := entityFinder.FindEntity(tag)
 	if errors.IsNotFound(err) {
@@ -287,6 +307,21 @@ func (m *ExternalMacaroonAuthenticator) Authenticate(entityFinder EntityFinder,
 	return entity, nil
 }
 
+// recordGroups records the groups the identity service has the user
+// as belonging to. Failing to get the groups does not prevent the user
+// logging in, but the groups previously recorded are left to lapse.
+func (m *ExternalMacaroonAuthenticator) recordGroups(tag names.UserTag, username string) error {
+	groups, err := m.GetGroups(username)
+	if err != nil {
+		logger.Warningf("cannot get groups for %q from the identity service: %v", username, err)
+		return nil
+	}
+	if err := m.RecordGroups(tag, groups); err != nil {
+		return errors.Annotate(err, "cannot record group claims")
+	}
+	return nil
+}
+
 func addMacaroonTimeBeforeCaveat(svc BakeryService, m *macaroon.Macaroon, t time.Time) error {
 	return svc.AddCaveat(m, checkers.TimeBeforeCaveat(t))
 }
@@ -287,6 +287,13 @@ var authenticateSuccessTests = []struct {
 		"cheat@local": true,
 	},
 	expectError: `external identity provider has provided ostensibly local name "cheat@local"`,
+}, {
+	about:              "reserved group domain",
+	dischargedUsername: "devs@group",
+	finder: simpleEntityFinder{
+		"user-devs@group": true,
+	},
+	expectError: `external identity provider has provided reserved group name "devs@group"`,
 }, {
 	about:              "FindEntity error",
 	dischargedUsername: "bobbrown@nowhere",
@@ -342,6 +349,54 @@ func (s *macaroonAuthenticatorSuite) TestMacaroonAuthentication(c *gc.C) {
 	}
 }
 
+func (s *macaroonAuthenticatorSuite) TestMacaroonAuthenticationRecordsGroups(c *gc.C) {
+	discharger := bakerytest.NewDischarger(nil, s.Checker)
+	defer discharger.Close()
+	s.username = "bobbrown"
+
+	svc, err := bakery.NewService(bakery.NewServiceParams{
+		Locator: discharger,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	mac, err := svc.NewMacaroon(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var getGroupsErr error
+	var recorded []string
+	authenticator := &authentication.ExternalMacaroonAuthenticator{
+		Service:          svc,
+		IdentityLocation: discharger.Location(),
+		Macaroon:         mac,
+		GetGroups: func(username string) ([]string, error) {
+			c.Check(username, gc.Equals, "bobbrown")
+			return []string{"devs"}, getGroupsErr
+		},
+		RecordGroups: func(tag names.UserTag, groups []string) error {
+			c.Check(tag, gc.Equals, names.NewUserTag("bobbrown@external"))
+			recorded = groups
+			return nil
+		},
+	}
+	finder := simpleEntityFinder{"user-bobbrown@external": true}
+
+	_, err = authenticator.Authenticate(finder, nil, params.LoginRequest{})
+	dischargeErr := errors.Cause(err).(*common.DischargeRequiredError)
+	ms, err := httpbakery.NewClient().DischargeAll(dischargeErr.Macaroon)
+	c.Assert(err, jc.ErrorIsNil)
+	req := params.LoginRequest{Macaroons: []macaroon.Slice{ms}}
+
+	_, err = authenticator.Authenticate(finder, nil, req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, jc.DeepEquals, []string{"devs"})
+
+	// The user can still log in when the identity service won't
+	// say which groups they belong to; nothing new is recorded.
+	recorded = nil
+	getGroupsErr = errors.New("forbidden")
+	_, err = authenticator.Authenticate(finder, nil, req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorded, gc.IsNil)
+}
+
 type errorEntityFinder string
 
 func (f errorEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
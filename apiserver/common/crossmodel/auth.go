@@ -159,7 +159,7 @@ func (a *AuthContext) checkOfferAccess(st Backend, username, offerUUID string) e
 	if isAdmin {
 		return nil
 	}
-	access, err := st.GetOfferAccess(offerUUID, userTag)
+	access, err := common.EffectiveOfferAccess(st, offerUUID, userTag)
 	if err != nil && !errors.IsNotFound(err) {
 		return common.ErrPerm
 	}
@@ -170,7 +170,7 @@ func (a *AuthContext) checkOfferAccess(st Backend, username, offerUUID string) e
 }
 
 func (api *AuthContext) hasControllerAdminAccess(st Backend, userTag names.UserTag) (bool, error) {
-	isAdmin, err := common.HasPermission(st.UserPermission, userTag, permission.SuperuserAccess, st.ControllerTag())
+	isAdmin, err := common.HasPermission(common.EffectiveUserAccess(st), userTag, permission.SuperuserAccess, st.ControllerTag())
 	if errors.IsNotFound(err) {
 		return false, nil
 	}
@@ -178,7 +178,7 @@ func (api *AuthContext) hasControllerAdminAccess(st Backend, userTag names.UserT
 }
 
 func (api *AuthContext) hasModelAdminAccess(st Backend, userTag names.UserTag) (bool, error) {
-	isAdmin, err := common.HasPermission(st.UserPermission, userTag, permission.AdminAccess, st.ModelTag())
+	isAdmin, err := common.HasPermission(common.EffectiveUserAccess(st), userTag, permission.AdminAccess, st.ModelTag())
 	if errors.IsNotFound(err) {
 		return false, nil
 	}
@@ -41,6 +41,9 @@ type Backend interface {
 	// UserPermission returns the access permission for the passed subject and target.
 	UserPermission(subject names.UserTag, target names.Tag) (permission.Access, error)
 
+	// UserGroups returns the tags standing for the groups the user is a member of.
+	UserGroups(names.UserTag) ([]names.UserTag, error)
+
 	// RemoteApplication returns a remote application by name.
 	RemoteApplication(string) (RemoteApplication, error)
 
@@ -55,6 +55,10 @@ func (m *mockState) UserPermission(subject names.UserTag, target names.Tag) (per
 	return perm, nil
 }
 
+func (m *mockState) UserGroups(user names.UserTag) ([]names.UserTag, error) {
+	return nil, nil
+}
+
 func (m *mockState) GetOfferAccess(offerUUID string, user names.UserTag) (permission.Access, error) {
 	perm, ok := m.permissions[offerUUID+":"+user.Id()]
 	if !ok {
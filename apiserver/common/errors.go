@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/txn"
@@ -77,6 +78,48 @@ func IsDischargeRequiredError(err error) bool {
 	return ok
 }
 
+// OIDCLoginRequiredError is the error returned when a user must obtain
+// an ID token from the controller's OpenID Connect issuer to complete
+// authentication.
+type OIDCLoginRequiredError struct {
+	Cause     error
+	IssuerURL string
+	ClientID  string
+}
+
+// Error implements the error interface.
+func (e *OIDCLoginRequiredError) Error() string {
+	return e.Cause.Error()
+}
+
+// IsOIDCLoginRequiredError reports whether the cause
+// of the error is a *OIDCLoginRequiredError.
+func IsOIDCLoginRequiredError(err error) bool {
+	_, ok := errors.Cause(err).(*OIDCLoginRequiredError)
+	return ok
+}
+
+// RateLimitExceededError is the error returned when a client has made
+// more API calls than its rate limit allows, and must wait before
+// making more.
+type RateLimitExceededError struct {
+	// RetryAfter holds how long the client should wait
+	// before the call is likely to succeed.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *RateLimitExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry after %v", e.RetryAfter)
+}
+
+// IsRateLimitExceededError reports whether the cause
+// of the error is a *RateLimitExceededError.
+func IsRateLimitExceededError(err error) bool {
+	_, ok := errors.Cause(err).(*RateLimitExceededError)
+	return ok
+}
+
 // IsUpgradeInProgress returns true if this error is caused
 // by an upgrade in progress.
 func IsUpgradeInProgressError(err error) bool {
@@ -184,6 +227,8 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		status = http.StatusUnauthorized
 	case params.CodeRetry:
 		status = http.StatusServiceUnavailable
+	case params.CodeRateLimitExceeded:
+		status = http.StatusTooManyRequests
 	}
 	return err1, status
 }
@@ -243,6 +288,11 @@ func ServerError(err error) *params.Error {
 		code = params.CodeNotImplemented
 	case state.IsIncompatibleSeriesError(err):
 		code = params.CodeIncompatibleSeries
+	case IsRateLimitExceededError(err):
+		code = params.CodeRateLimitExceeded
+		info = &params.ErrorInfo{
+			RetryAfter: err.(*RateLimitExceededError).RetryAfter,
+		}
 	default:
 		if err, ok := err.(*DischargeRequiredError); ok {
 			code = params.CodeDischargeRequired
@@ -347,6 +397,12 @@ func RestoreError(err error) error {
 	case params.ErrCode(err) == params.CodeDischargeRequired:
 		// TODO(ericsnow) Handle DischargeRequiredError here.
 		return err
+	case params.IsCodeRateLimitExceeded(err):
+		var retryAfter time.Duration
+		if info := err.(*params.Error).Info; info != nil {
+			retryAfter = info.RetryAfter
+		}
+		return &RateLimitExceededError{RetryAfter: retryAfter}
 	default:
 		return err
 	}
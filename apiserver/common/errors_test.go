@@ -6,6 +6,7 @@ package common_test
 import (
 	stderrors "errors"
 	"net/http"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -190,6 +191,17 @@ var errorTransformTests = []struct {
 	code:       params.CodeModelNotFound,
 	status:     http.StatusNotFound,
 	helperFunc: params.IsCodeModelNotFound,
+}, {
+	err:    &common.RateLimitExceededError{RetryAfter: time.Second},
+	code:   params.CodeRateLimitExceeded,
+	status: http.StatusTooManyRequests,
+	helperFunc: func(err error) bool {
+		err1, ok := err.(*params.Error)
+		if !ok || err1.Info == nil || err1.Info.RetryAfter != time.Second {
+			return false
+		}
+		return true
+	},
 }, {
 	err:    nil,
 	code:   "",
@@ -252,6 +264,8 @@ func (s *errorsSuite) TestErrorTransform(c *gc.C) {
 			c.Check(restored, jc.ErrorIsNil)
 		} else if t.code == "" {
 			c.Check(restored.Error(), gc.Equals, t.err.Error())
+		} else if t.code == params.CodeRateLimitExceeded {
+			c.Check(restored, jc.DeepEquals, t.err)
 		} else {
 			// TODO(ericsnow) Use a stricter DeepEquals check.
 			c.Check(errors.Cause(restored), gc.FitsTypeOf, t.err)
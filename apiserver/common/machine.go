@@ -4,7 +4,9 @@
 package common
 
 import (
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
@@ -95,6 +97,45 @@ func destroyMachines(st stateInterface, force bool, ids ...string) error {
 	return DestroyErr("machines", ids, errs)
 }
 
+// ApplicationsSharingMachine returns the names, sorted, of the
+// applications with principal units on the machine with the given id,
+// on the machines hosting it if it is a container, or in containers
+// on it. Anyone able to run commands as root on the machine can reach
+// the units of all of them.
+func ApplicationsSharingMachine(st origStateInterface, machineId string) ([]string, error) {
+	var ids []string
+	for id := state.ParentId(machineId); id != ""; id = state.ParentId(id) {
+		ids = append(ids, id)
+	}
+	// The machine's containers, and theirs, are appended as they are
+	// found; its hosts' other containers are not reachable from it.
+	hosts := len(ids)
+	ids = append(ids, machineId)
+	apps := set.NewStrings()
+	for i := 0; i < len(ids); i++ {
+		m, err := st.Machine(ids[i])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, unitName := range m.Principals() {
+			appName, err := names.UnitApplication(unitName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			apps.Add(appName)
+		}
+		if i < hosts {
+			continue
+		}
+		containers, err := m.Containers()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ids = append(ids, containers...)
+	}
+	return apps.SortedValues(), nil
+}
+
 // ModelMachineInfo returns information about machine hardware for
 // alive top level machines (not containers).
 func ModelMachineInfo(st ModelManagerBackend) (machineInfo []params.ModelMachineInfo, _ error) {
@@ -106,6 +106,18 @@ func (s *stateShim) AddSpace(name string, providerId network.Id, subnetIds []str
 	return err
 }
 
+func (s *stateShim) RenameSpace(from, to string) error {
+	return s.st.RenameSpace(from, to)
+}
+
+func (s *stateShim) RemoveSpace(name string) error {
+	return s.st.RemoveSpace(name)
+}
+
+func (s *stateShim) UpdateSpace(name string, subnets []string) error {
+	return s.st.UpdateSpace(name, subnets)
+}
+
 func (s *stateShim) AllSpaces() ([]BackingSpace, error) {
 	// TODO(dimitern): Make this ListSpaces() instead.
 	results, err := s.st.AllSpaces()
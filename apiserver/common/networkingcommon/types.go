@@ -113,6 +113,15 @@ type NetworkBacking interface {
 	// AllSpaces returns all known Juju network spaces.
 	AllSpaces() ([]BackingSpace, error)
 
+	// RenameSpace renames a space, updating everything which refers to it.
+	RenameSpace(from, to string) error
+
+	// RemoveSpace removes a space which is no longer in use.
+	RemoveSpace(name string) error
+
+	// UpdateSpace replaces the subnets of a space.
+	UpdateSpace(name string, subnets []string) error
+
 	// AddSubnet creates a backing subnet for an existing subnet.
 	AddSubnet(BackingSubnetInfo) (BackingSubnet, error)
 
@@ -12,11 +12,95 @@ import (
 )
 
 // EveryoneTagName represents a special group that encompasses
-// all external users.
+// all external users. Named user groups are resolved separately,
+// see GroupUserAccess.
 const EveryoneTagName = "everyone@external"
 
 type userAccessFunc func(names.UserTag, names.Tag) (permission.Access, error)
 
+type userGroupsFunc func(names.UserTag) ([]names.UserTag, error)
+
+// UserAccessGetter is implemented by types, notably *state.State, that
+// can report the access granted directly to a user or group, and the
+// groups a user is a member of.
+type UserAccessGetter interface {
+	UserPermission(names.UserTag, names.Tag) (permission.Access, error)
+	UserGroups(names.UserTag) ([]names.UserTag, error)
+}
+
+// EffectiveUserAccess returns an access getter, suitable for passing to
+// HasPermission and GetPermission, that resolves a user's effective
+// access from the given getter's grants.
+func EffectiveUserAccess(getter UserAccessGetter) userAccessFunc {
+	return GroupUserAccess(getter.UserPermission, getter.UserGroups)
+}
+
+// GroupUserAccess returns an access getter that reports the union of
+// the access granted to a user directly and to each group the user
+// is a member of. If neither the user nor any of their groups has been
+// granted access, the error from the user's access getter is returned.
+func GroupUserAccess(accessGetter userAccessFunc, groupsGetter userGroupsFunc) userAccessFunc {
+	return func(userTag names.UserTag, target names.Tag) (permission.Access, error) {
+		userAccess, userErr := accessGetter(userTag, target)
+		if userErr != nil && !errors.IsNotFound(userErr) {
+			return permission.NoAccess, errors.Trace(userErr)
+		}
+		groups, err := groupsGetter(userTag)
+		if err != nil {
+			return permission.NoAccess, errors.Annotatef(err, "obtaining groups for %s", userTag.Id())
+		}
+		for _, group := range groups {
+			groupAccess, err := accessGetter(group, target)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return permission.NoAccess, errors.Trace(err)
+			}
+			if greaterAccess(target, groupAccess, userAccess) {
+				userAccess = groupAccess
+				userErr = nil
+			}
+		}
+		return userAccess, userErr
+	}
+}
+
+// OfferAccessGetter is implemented by types, notably *state.State, that
+// can report the access granted directly to a user or group on an offer,
+// and the groups a user is a member of.
+type OfferAccessGetter interface {
+	GetOfferAccess(offerUUID string, user names.UserTag) (permission.Access, error)
+	UserGroups(names.UserTag) ([]names.UserTag, error)
+}
+
+// EffectiveOfferAccess returns the union of the access granted on the
+// offer with the given UUID to the user and to each group the user is
+// a member of.
+func EffectiveOfferAccess(getter OfferAccessGetter, offerUUID string, userTag names.UserTag) (permission.Access, error) {
+	offerAccess := func(subject names.UserTag, _ names.Tag) (permission.Access, error) {
+		return getter.GetOfferAccess(offerUUID, subject)
+	}
+	// The offer tag is only used to compare access levels.
+	target := names.NewApplicationOfferTag(offerUUID)
+	return GroupUserAccess(offerAccess, getter.UserGroups)(userTag, target)
+}
+
+// greaterAccess reports whether access a is greater than
+// access b on the given target.
+func greaterAccess(target names.Tag, a, b permission.Access) bool {
+	switch target.Kind() {
+	case names.ControllerTagKind:
+		return a.GreaterControllerAccessThan(b)
+	case names.ModelTagKind:
+		return a.GreaterModelAccessThan(b)
+	case names.ApplicationOfferTagKind:
+		return a.GreaterOfferAccessThan(b)
+	case names.ApplicationTagKind:
+		return a.GreaterApplicationAccessThan(b)
+	}
+	return false
+}
+
 // HasPermission returns true if the specified user has the specified
 // permission on target.
 func HasPermission(
@@ -31,6 +115,8 @@ func HasPermission(
 		validate = permission.ValidateModelAccess
 	case names.ApplicationOfferTagKind:
 		validate = permission.ValidateOfferAccess
+	case names.ApplicationTagKind:
+		validate = permission.ValidateApplicationAccess
 	default:
 		return false, nil
 	}
@@ -56,7 +142,8 @@ func HasPermission(
 	modelPermission := userAccess.EqualOrGreaterModelAccessThan(requestedPermission) && target.Kind() == names.ModelTagKind
 	controllerPermission := userAccess.EqualOrGreaterControllerAccessThan(requestedPermission) && target.Kind() == names.ControllerTagKind
 	offerPermission := userAccess.EqualOrGreaterOfferAccessThan(requestedPermission) && target.Kind() == names.ApplicationOfferTagKind
-	if !controllerPermission && !modelPermission && !offerPermission {
+	applicationPermission := userAccess.EqualOrGreaterApplicationAccessThan(requestedPermission) && target.Kind() == names.ApplicationTagKind
+	if !controllerPermission && !modelPermission && !offerPermission && !applicationPermission {
 		return false, nil
 	}
 	return true, nil
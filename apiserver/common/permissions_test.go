@@ -130,6 +130,30 @@ func (r *PermissionSuite) TestHasPermission(c *gc.C) {
 			access:           permission.AddModelAccess,
 			expected:         false,
 		},
+		{
+			title:            "user has lesser application permission than required",
+			userGetterAccess: permission.ReadAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewApplicationTag("mysql"),
+			access:           permission.OperateAccess,
+			expected:         false,
+		},
+		{
+			title:            "user has greater application permission than required",
+			userGetterAccess: permission.AdminAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewApplicationTag("mysql"),
+			access:           permission.OperateAccess,
+			expected:         true,
+		},
+		{
+			title:            "user requests model permission on application",
+			userGetterAccess: permission.AdminAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewApplicationTag("mysql"),
+			access:           permission.WriteAccess,
+			expected:         false,
+		},
 	}
 	for i, t := range testCases {
 		userGetter := &fakeUserAccess{
@@ -221,3 +245,80 @@ func (r *PermissionSuite) TestEveryoneAtExternal(c *gc.C) {
 		c.Assert(hasPermission, gc.Equals, t.expected)
 	}
 }
+
+type fakeGroupUserAccess struct {
+	access map[string]permission.Access
+	groups map[string][]names.UserTag
+}
+
+func (f *fakeGroupUserAccess) UserPermission(subject names.UserTag, object names.Tag) (permission.Access, error) {
+	access, ok := f.access[subject.Id()]
+	if !ok {
+		return permission.NoAccess, errors.NotFoundf("access for %q", subject.Id())
+	}
+	return access, nil
+}
+
+func (f *fakeGroupUserAccess) GetOfferAccess(offerUUID string, subject names.UserTag) (permission.Access, error) {
+	return f.UserPermission(subject, nil)
+}
+
+func (f *fakeGroupUserAccess) UserGroups(subject names.UserTag) ([]names.UserTag, error) {
+	return f.groups[subject.Id()], nil
+}
+
+func (r *PermissionSuite) TestGroupAccess(c *gc.C) {
+	modelTag := names.NewModelTag("beef1beef2-0000-0000-000011112222")
+	getter := &fakeGroupUserAccess{
+		access: map[string]permission.Access{
+			"alice":      permission.ReadAccess,
+			"devs@group": permission.WriteAccess,
+			"ops@group":  permission.AdminAccess,
+		},
+		groups: map[string][]names.UserTag{
+			"alice":      {names.NewUserTag("devs@group")},
+			"bob@oidc":   {names.NewUserTag("devs@group"), names.NewUserTag("ops@group")},
+			"carol@oidc": {names.NewUserTag("qa@group")},
+		},
+	}
+	access := common.EffectiveUserAccess(getter)
+
+	for i, t := range []struct {
+		user     string
+		expected permission.Access
+	}{
+		{"alice", permission.WriteAccess},
+		{"bob@oidc", permission.AdminAccess},
+	} {
+		c.Logf("test %d: %s", i, t.user)
+		result, err := common.GetPermission(access, names.NewUserTag(t.user), modelTag)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result, gc.Equals, t.expected)
+	}
+
+	hasPermission, err := common.HasPermission(access, names.NewUserTag("alice"), permission.WriteAccess, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasPermission, jc.IsTrue)
+
+	// Neither the user nor their groups have been granted access.
+	_, err = access(names.NewUserTag("carol@oidc"), modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	hasPermission, err = common.HasPermission(access, names.NewUserTag("carol@oidc"), permission.ReadAccess, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasPermission, jc.IsFalse)
+}
+
+func (r *PermissionSuite) TestGroupOfferAccess(c *gc.C) {
+	getter := &fakeGroupUserAccess{
+		access: map[string]permission.Access{
+			"alice":      permission.ReadAccess,
+			"devs@group": permission.ConsumeAccess,
+		},
+		groups: map[string][]names.UserTag{
+			"alice": {names.NewUserTag("devs@group")},
+		},
+	}
+	access, err := common.EffectiveOfferAccess(getter, "offer-uuid", names.NewUserTag("alice"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.ConsumeAccess)
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/authentication"
 )
 
 // These vars define how we rate limit incoming connections.
@@ -36,6 +38,41 @@ type RateLimitConfig struct {
 	ConnLookbackWindow time.Duration
 	ConnLowerThreshold int
 	ConnUpperThreshold int
+
+	// UserCallRate and UserCallBurst limit the API calls made by
+	// each authenticated user, across all of their connections, to
+	// a sustained rate per second with bursts of up to the given
+	// size. A zero rate means calls are not limited.
+	UserCallRate  float64
+	UserCallBurst int
+
+	// ConnCallRate and ConnCallBurst limit the API calls made on
+	// each user connection in the same way.
+	ConnCallRate  float64
+	ConnCallBurst int
+
+	// FacadeGroupCallLimits holds additional per user limits on
+	// the calls made to the methods in each named facade method
+	// group, such as "status".
+	FacadeGroupCallLimits map[string]CallLimit
+}
+
+// CallLimit holds a token bucket limit on API calls: a sustained
+// rate of calls per second, with bursts of up to the given size.
+type CallLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Validate validates the call limit.
+func (l CallLimit) Validate() error {
+	if l.Rate < 0 {
+		return errors.NotValidf("rate %v < 0", l.Rate)
+	}
+	if l.Rate > 0 && l.Burst < 1 {
+		return errors.NotValidf("burst %d < 1", l.Burst)
+	}
+	return nil
 }
 
 // DefaultRateLimitConfig returns a RateLimtConfig struct with
@@ -79,6 +116,20 @@ func (c RateLimitConfig) Validate() error {
 	if c.ConnLookbackWindow < 0 || c.ConnLookbackWindow > 5*time.Second {
 		return errors.NotValidf("conn-lookback-window %d < 0 or > 5s", c.ConnMaxPause)
 	}
+	if err := (CallLimit{c.UserCallRate, c.UserCallBurst}).Validate(); err != nil {
+		return errors.Annotate(err, "user call limit")
+	}
+	if err := (CallLimit{c.ConnCallRate, c.ConnCallBurst}).Validate(); err != nil {
+		return errors.Annotate(err, "connection call limit")
+	}
+	for group, limit := range c.FacadeGroupCallLimits {
+		if err := authentication.ValidateAPITokenFacadeGroups([]string{group}); err != nil {
+			return errors.Trace(err)
+		}
+		if err := limit.Validate(); err != nil {
+			return errors.Annotatef(err, "%s call limit", group)
+		}
+	}
 	return nil
 }
 
@@ -6,6 +6,7 @@ package apiserver
 import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -32,8 +33,9 @@ func APIHandlerWithEntity(entity state.Entity) *apiHandler {
 }
 
 const (
-	LoginRateLimit = defaultLoginRateLimit
-	LoginRetyPause = defaultLoginRetryPause
+	LoginRateLimit  = defaultLoginRateLimit
+	LoginRetyPause  = defaultLoginRetryPause
+	UserIdleTimeout = userIdleTimeout
 )
 
 func NewErrRoot(err error) *errRoot {
@@ -116,6 +118,13 @@ func TestingCAASModelOnlyRoot() rpc.Root {
 	return restrictRoot(r, caasModelFacadesOnly)
 }
 
+// TestingAPITokenRoot returns a restricted srvRoot as if logged
+// in with an API token restricted to the given facade groups.
+func TestingAPITokenRoot(groups ...string) rpc.Root {
+	r := TestingAPIRoot(AllFacades())
+	return restrictRoot(r, apiTokenMethodsOnly(groups))
+}
+
 // TestingRestrictedRoot returns a restricted srvRoot.
 func TestingRestrictedRoot(check func(string, string) error) rpc.Root {
 	r := TestingAPIRoot(AllFacades())
@@ -156,3 +165,13 @@ func AssertHasPermission(c *gc.C, handler *apiHandler, access permission.Access,
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(hasPermission, gc.Equals, expect)
 }
+
+// NewCallLimiterForTest returns the call limiter's metrics collector
+// and a function returning the call limiter's check for a new
+// connection for the given user.
+func NewCallLimiterForTest(
+	config RateLimitConfig, clock clock.Clock,
+) (prometheus.Collector, func(names.UserTag) func(string, string) error) {
+	limiter := newCallLimiter(config, clock)
+	return limiter, limiter.check
+}
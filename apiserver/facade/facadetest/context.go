@@ -10,13 +10,14 @@ import (
 
 // Context implements facade.Context in the simplest possible way.
 type Context struct {
-	Auth_      facade.Authorizer
-	Dispose_   func()
-	Hub_       facade.Hub
-	Resources_ facade.Resources
-	State_     *state.State
-	StatePool_ *state.StatePool
-	ID_        string
+	Auth_       facade.Authorizer
+	Dispose_    func()
+	Hub_        facade.Hub
+	RaftLeader_ string
+	Resources_  facade.Resources
+	State_      *state.State
+	StatePool_  *state.StatePool
+	ID_         string
 	// Identity is not part of the facade.Context interface, but is instead
 	// used to make sure that the context objects are the same.
 	Identity string
@@ -37,6 +38,11 @@ func (context Context) Hub() facade.Hub {
 	return context.Hub_
 }
 
+// RaftLeader is part of the facade.Context interface.
+func (context Context) RaftLeader() string {
+	return context.RaftLeader_
+}
+
 // Resources is part of the facade.Context interface.
 func (context Context) Resources() facade.Resources {
 	return context.Resources_
@@ -70,6 +70,11 @@ type Context interface {
 	// At least at this stage, facades only need to publish events.
 	Hub() Hub
 
+	// RaftLeader returns the id of the controller machine that is
+	// currently the raft leader, as last reported by the local raft
+	// worker, or "" if it is not known.
+	RaftLeader() string
+
 	// ID returns a string that should almost always be "", unless
 	// this is a watcher facade, in which case it exists in lieu of
 	// actual arguments in the Next() call, and is used as a key
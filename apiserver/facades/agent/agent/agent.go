@@ -40,6 +40,10 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 	if !auth.AuthMachineAgent() && !auth.AuthUnitAgent() {
 		return nil, common.ErrPerm
 	}
+	return newAgentAPIV2(st, resources, auth)
+}
+
+func newAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV2, error) {
 	getCanChange := func() (common.AuthFunc, error) {
 		return auth.AuthOwner, nil
 	}
@@ -188,3 +192,77 @@ func (api *AgentAPIV2) WatchCredentials(args params.Entities) (params.NotifyWatc
 	}
 	return results, nil
 }
+
+// AgentAPIV3 implements version 3 of the API provided to an agent,
+// which adds support for rotating the controller CA.
+type AgentAPIV3 struct {
+	*AgentAPIV2
+}
+
+// NewAgentAPIV3 returns an object implementing version 3 of the Agent
+// API with the given authorizer representing the currently logged in
+// client.
+func NewAgentAPIV3(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV3, error) {
+	// CAAS application operators are agents too, and need to follow
+	// the controller CA as it is rotated.
+	if !auth.AuthMachineAgent() && !auth.AuthUnitAgent() && !auth.AuthApplicationAgent() {
+		return nil, common.ErrPerm
+	}
+	v2, err := newAgentAPIV2(st, resources, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AgentAPIV3{v2}, nil
+}
+
+// ControllerCACert returns the controller CA certificates the agent
+// should trust. While the controller CA is being rotated, this is a
+// bundle holding both the old and the new CA certificates.
+func (api *AgentAPIV3) ControllerCACert() (params.StringResult, error) {
+	cfg, err := api.st.ControllerConfig()
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	caCert, ok := cfg.CACert()
+	if !ok {
+		return params.StringResult{}, errors.NotFoundf("controller CA certificate")
+	}
+	return params.StringResult{Result: caCert}, nil
+}
+
+// WatchControllerCACert returns a watcher which notifies when the
+// controller CA certificates trusted by agents might have changed.
+func (api *AgentAPIV3) WatchControllerCACert() (params.NotifyWatchResult, error) {
+	watch := api.st.WatchControllerConfig()
+	// Consume the initial event. Technically, API calls to Watch
+	// 'transmit' the initial event in the Watch response. But
+	// NotifyWatchers have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// SetControllerCATrusted records the controller CA certificates now
+// trusted by each agent. An agent may only report on itself.
+func (api *AgentAPIV3) SetControllerCATrusted(args params.EntityCACerts) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !api.auth.AuthOwner(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = api.st.SetControllerCATrusted(tag, entity.CACert)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
@@ -83,6 +83,15 @@ func (s *agentSuite) TestAgentSucceedsWithUnitAgent(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *agentSuite) TestAgentV3SucceedsWithApplicationAgent(c *gc.C) {
+	auth := s.authorizer
+	auth.Tag = names.NewApplicationTag("gitlab")
+	_, err := agent.NewAgentAPIV2(s.State, s.resources, auth)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = agent.NewAgentAPIV3(s.State, s.resources, auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *agentSuite) TestGetEntities(c *gc.C) {
 	err := s.container.Destroy()
 	c.Assert(err, jc.ErrorIsNil)
@@ -276,3 +285,58 @@ func (s *agentSuite) TestWatchAuthError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 }
+
+func (s *agentSuite) TestControllerCACert(c *gc.C) {
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.ControllerCACert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Result, gc.Equals, coretesting.CACert)
+}
+
+func (s *agentSuite) TestWatchControllerCACert(c *gc.C) {
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.WatchControllerCACert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+
+	w := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *agentSuite) TestSetControllerCATrusted(c *gc.C) {
+	err := s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	bundle, err := api.ControllerCACert()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.SetControllerCATrusted(params.EntityCACerts{
+		Entities: []params.EntityCACert{
+			{Tag: "machine-0", CACert: bundle.Result},
+			{Tag: "machine-1", CACert: bundle.Result},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+		},
+	})
+
+	untrusting, err := s.State.ControllerCAUntrustingAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(untrusting, jc.SameContents, []string{
+		s.State.ModelUUID() + ":" + s.machine0.Tag().String(),
+		s.State.ModelUUID() + ":" + s.container.Tag().String(),
+	})
+}
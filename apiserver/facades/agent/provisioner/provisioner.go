@@ -307,6 +307,11 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 		cfg[config.ContainerImageMetadataURLKey] = url
 	}
 	cfg[config.ContainerImageStreamKey] = mConfig.ContainerImageStream()
+	if args.Type == instance.LXD {
+		if profiles, _ := mConfig.AllAttrs()[config.LXDProfilesKey].(string); profiles != "" {
+			cfg[config.LXDProfilesKey] = profiles
+		}
+	}
 
 	result.ManagerConfig = cfg
 	return result, nil
@@ -360,7 +365,8 @@ func (p *ProvisionerAPIV5) ContainerConfig() (params.ContainerConfigV5, error) {
 }
 
 // MachinesWithTransientErrors returns status data for machines with provisioning
-// errors which are transient.
+// errors which are transient, and for machines whose replaced instance has been
+// cleared, which are waiting for a new instance.
 func (p *ProvisionerAPI) MachinesWithTransientErrors() (params.StatusResults, error) {
 	var results params.StatusResults
 	canAccessFunc, err := p.getAuthFunc()
@@ -389,6 +395,12 @@ func (p *ProvisionerAPI) MachinesWithTransientErrors() (params.StatusResults, er
 		result.Status = statusInfo.Status.String()
 		result.Info = statusInfo.Message
 		result.Data = statusInfo.Data
+		if statusInfo.Status == status.Replacing {
+			result.Id = machine.Id()
+			result.Life = params.Life(machine.Life().String())
+			results.Results = append(results.Results, result)
+			continue
+		}
 		if statusInfo.Status != status.Error && statusInfo.Status != status.ProvisioningError {
 			continue
 		}
@@ -1049,6 +1061,7 @@ func (ctx *hostChangesContext) ProcessOneContainer(env environs.Environ, callCon
 				HostDeviceName: bridgeInfo.DeviceName,
 				BridgeName:     bridgeInfo.BridgeName,
 				MACAddress:     bridgeInfo.MACAddress,
+				DeviceType:     string(bridgeInfo.DeviceType),
 			})
 	}
 	return nil
@@ -486,6 +486,22 @@ func (s *withoutControllerSuite) TestMachinesWithTransientErrors(c *gc.C) {
 	})
 }
 
+func (s *withoutControllerSuite) TestMachinesWithTransientErrorsReplacedInstance(c *gc.C) {
+	err := s.machines[0].SetProvisioned("i-am", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machines[0].ReplaceInstance()
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.provisioner.MachinesWithTransientErrors()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Id, gc.Equals, "0")
+	c.Assert(result.Results[0].Status, gc.Equals, "replacing")
+	c.Assert(result.Results[0].Data, jc.DeepEquals, map[string]interface{}{
+		state.ReplacedInstanceKey: "i-am",
+	})
+}
+
 func (s *withoutControllerSuite) TestMachinesWithTransientErrorsPermission(c *gc.C) {
 	// Machines where there's permission issues are omitted.
 	anAuthorizer := s.authorizer
@@ -1376,6 +1392,27 @@ func (s *withImageMetadataSuite) TestContainerManagerConfigImageMetadata(c *gc.C
 		config.ContainerImageMetadataURLKey: "https://images.linuxcontainers.org/",
 	})
 }
+
+func (s *withoutControllerSuite) TestContainerManagerConfigLXDProfiles(c *gc.C) {
+	profiles := "iscsi:\n  config:\n    linux.kernel_modules: iscsi_tcp\n"
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		config.LXDProfilesKey: profiles,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg := s.getManagerConfig(c, instance.LXD)
+	c.Assert(cfg, jc.DeepEquals, map[string]string{
+		container.ConfigModelUUID:      coretesting.ModelTag.Id(),
+		config.ContainerImageStreamKey: "released",
+		config.LXDProfilesKey:          profiles,
+	})
+
+	// The profiles are only for LXD container managers.
+	cfg = s.getManagerConfig(c, instance.KVM)
+	_, ok := cfg[config.LXDProfilesKey]
+	c.Assert(ok, jc.IsFalse)
+}
+
 func (s *withoutControllerSuite) TestContainerConfig(c *gc.C) {
 	attrs := map[string]interface{}{
 		"juju-http-proxy":              "http://proxy.example.com:9000",
@@ -4,7 +4,11 @@
 package provisioner
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"path"
 	"sort"
 	"strings"
 
@@ -17,13 +21,17 @@ import (
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/lxd/lxdnames"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/state/multiwatcher"
+	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/storage"
 )
 
@@ -96,6 +104,11 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		return nil, errors.Annotate(err, "cannot get controller configuration")
 	}
 
+	charmProfiles, err := p.machineCharmLXDProfiles(m, env)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get charm LXD profiles")
+	}
+
 	return &params.ProvisioningInfo{
 		Constraints:       cons,
 		Series:            m.Series(),
@@ -109,9 +122,104 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		ImageMetadata:     imageMetadata,
 		ControllerConfig:  controllerCfg,
 		CloudInitUserData: env.Config().CloudInitUserData(),
+		CharmLXDProfiles:  charmProfiles,
 	}, nil
 }
 
+// machineCharmLXDProfiles returns the LXD profiles defined by the charms
+// of the units assigned to the machine, if its instance is to be an LXD
+// container or virtual machine. Each is named for the unit's application.
+func (p *ProvisionerAPI) machineCharmLXDProfiles(m *state.Machine, env environs.Environ) (map[string]params.LXDProfile, error) {
+	switch m.ContainerType() {
+	case instance.LXD:
+	case instance.NONE, "":
+		if env.Config().Type() != lxdnames.ProviderType {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+	principals := m.Principals()
+	if len(principals) == 0 {
+		return nil, nil
+	}
+
+	store := statestorage.NewStorage(p.st.ModelUUID(), p.st.MongoSession())
+	profiles := make(map[string]params.LXDProfile)
+	for _, unitName := range principals {
+		appName, err := names.UnitApplication(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		profileName := "app-" + appName
+		if _, ok := profiles[profileName]; ok {
+			continue
+		}
+		app, err := p.st.Application(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := app.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		profile, err := readCharmLXDProfile(store, ch)
+		if err != nil {
+			return nil, errors.Annotatef(err, "charm %q", ch.URL())
+		}
+		if profile == nil {
+			continue
+		}
+		profiles[profileName] = params.LXDProfile{
+			Description: profile.Description,
+			Config:      profile.Config,
+			Devices:     profile.Devices,
+		}
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	return profiles, nil
+}
+
+// readCharmLXDProfile returns the LXD profile held in the charm's
+// archive, or nil if the charm does not define one.
+func readCharmLXDProfile(store statestorage.Storage, ch *state.Charm) (*lxdprofile.Profile, error) {
+	reader, _, err := store.Get(ch.StoragePath())
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get charm from model storage")
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read charm archive")
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read charm archive")
+	}
+	for _, file := range zipReader.File {
+		if path.Clean(file.Name) != lxdprofile.CharmProfileFile {
+			continue
+		}
+		fileReader, err := file.Open()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot open %s", lxdprofile.CharmProfileFile)
+		}
+		content, err := ioutil.ReadAll(fileReader)
+		fileReader.Close()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read %s", lxdprofile.CharmProfileFile)
+		}
+		profile, err := lxdprofile.ParseProfile(string(content))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &profile, nil
+	}
+	return nil, nil
+}
+
 // machineVolumeParams retrieves VolumeParams for the volumes that should be
 // provisioned with, and attached to, the machine. The client should ignore
 // parameters that it does not know how to handle.
@@ -127,6 +235,14 @@ func (p *ProvisionerAPI) machineVolumeParams(
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
+	// Volumes detached when the machine's previous instance was
+	// replaced will be reattached once the new instance is recorded;
+	// include them so the new instance is started alongside them.
+	reattachments, err := m.VolumesToReattach()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	volumeAttachments = append(volumeAttachments, reattachments...)
 	if len(volumeAttachments) == 0 {
 		return nil, nil, nil
 	}
@@ -76,6 +76,49 @@ func (a *ActionAPI) checkCanAdmin() error {
 	return nil
 }
 
+// checkCanOperateApplications returns nil if the user has the given
+// access to the model, or otherwise operate access on every one of the
+// named applications.
+func (a *ActionAPI) checkCanOperateApplications(modelAccess permission.Access, appNames []string) error {
+	canAccess, err := a.authorizer.HasPermission(modelAccess, a.model.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if canAccess {
+		return nil
+	}
+	if len(appNames) == 0 {
+		return common.ErrPerm
+	}
+	for _, appName := range appNames {
+		if !names.IsValidApplication(appName) {
+			return common.ErrPerm
+		}
+		canOperate, err := a.authorizer.HasPermission(permission.OperateAccess, names.NewApplicationTag(appName))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !canOperate {
+			return common.ErrPerm
+		}
+	}
+	return nil
+}
+
+// receiverApplicationName returns the name of the application owning
+// the given action receiver, which must be a unit.
+func receiverApplicationName(receiver string) (string, error) {
+	tag, err := names.ParseUnitTag(receiver)
+	if err != nil {
+		return "", common.ErrPerm
+	}
+	appName, err := names.UnitApplication(tag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return appName, nil
+}
+
 // Actions takes a list of ActionTags, and returns the full Action for
 // each ID.
 func (a *ActionAPI) Actions(arg params.Entities) (params.ActionResults, error) {
@@ -176,7 +219,7 @@ func (a *ActionAPI) FindActionsByNames(arg params.FindActionsByNames) (params.Ac
 // enqueued Action, or an error if there was a problem enqueueing the
 // Action.
 func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
-	if err := a.checkCanWrite(); err != nil {
+	if err := a.checkCanEnqueue(arg); err != nil {
 		return params.ActionResults{}, errors.Trace(err)
 	}
 
@@ -204,6 +247,26 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return response, nil
 }
 
+// checkCanEnqueue checks that the user may queue the given actions:
+// model writers may queue anything, while application operators may
+// only queue actions on units of the applications they operate.
+func (a *ActionAPI) checkCanEnqueue(arg params.Actions) error {
+	canWrite, err := a.authorizer.HasPermission(permission.WriteAccess, a.model.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if canWrite {
+		return nil
+	}
+	appNames := make([]string, len(arg.Actions))
+	for i, action := range arg.Actions {
+		if appNames[i], err = receiverApplicationName(action.Receiver); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return a.checkCanOperateApplications(permission.WriteAccess, appNames)
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and
 // returns all of the Actions that have been enqueued or run by each of
 // those Entities.
@@ -250,7 +313,13 @@ func (a *ActionAPI) ListCompleted(arg params.Entities) (params.ActionsByReceiver
 
 // Cancel attempts to cancel enqueued Actions from running.
 func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
-	if err := a.checkCanWrite(); err != nil {
+	if err := a.checkCanRead(); err != nil {
+		return params.ActionResults{}, errors.Trace(err)
+	}
+	// Users without write access to the model may still cancel actions
+	// on units of applications they operate; that is checked per action.
+	canWrite, err := a.authorizer.HasPermission(permission.WriteAccess, a.model.ModelTag())
+	if err != nil {
 		return params.ActionResults{}, errors.Trace(err)
 	}
 
@@ -284,6 +353,12 @@ func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
+		if !canWrite {
+			if err := a.checkCanOperateReceiver(action.Receiver()); err != nil {
+				currentResult.Error = common.ServerError(err)
+				continue
+			}
+		}
 		result, err := action.Finish(state.ActionResults{Status: state.ActionCancelled, Message: "action cancelled via the API"})
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
@@ -300,6 +375,20 @@ func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 	return response, nil
 }
 
+// checkCanOperateReceiver checks that the user has operate access on
+// the application of the unit identified by receiverId.
+func (a *ActionAPI) checkCanOperateReceiver(receiverId string) error {
+	receiverTag, err := names.ActionReceiverTag(receiverId)
+	if err != nil {
+		return common.ErrPerm
+	}
+	appName, err := receiverApplicationName(receiverTag.String())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return a.checkCanOperateApplications(permission.WriteAccess, []string{appName})
+}
+
 // ApplicationsCharmsActions returns a slice of charm Actions for a slice of
 // services.
 func (a *ActionAPI) ApplicationsCharmsActions(args params.Entities) (params.ApplicationsCharmActionsResults, error) {
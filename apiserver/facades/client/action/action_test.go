@@ -273,6 +273,34 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 	c.Assert(actions, gc.HasLen, 0)
 }
 
+func (s *actionSuite) TestEnqueueApplicationOperator(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("operate-application-wordpress"),
+	}
+	api, err := action.NewActionAPI(s.State, nil, auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := api.Enqueue(params.Actions{
+		Actions: []params.Action{{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 1)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+
+	// Operating wordpress does not allow actions on other applications,
+	// nor on machines.
+	for _, receiver := range []string{s.mysqlUnit.Tag().String(), s.machine0.Tag().String()} {
+		_, err = api.Enqueue(params.Actions{
+			Actions: []params.Action{{Receiver: receiver, Name: "fakeaction"}},
+		})
+		c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	}
+
+	actions, err := s.mysqlUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
 type testCaseAction struct {
 	Name       string
 	Parameters map[string]interface{}
@@ -10,8 +10,10 @@ import (
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/actions"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
@@ -41,10 +43,69 @@ func getAllUnitNames(st *state.State, units, services []string) (result []names.
 	return result, nil
 }
 
+// checkCanRun checks that the user may run commands on the given
+// targets: model admins may run anywhere, while application operators
+// may only run on units of the applications they operate. As commands
+// run as root, the operator must also operate every application
+// sharing a machine with those units.
+func (a *ActionAPI) checkCanRun(run params.RunParams) error {
+	canAdmin, err := a.authorizer.HasPermission(permission.AdminAccess, a.model.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if canAdmin {
+		return nil
+	}
+	if len(run.Machines) > 0 {
+		return common.ErrPerm
+	}
+	appNames := append([]string(nil), run.Applications...)
+	for _, unitName := range run.Units {
+		appName, err := names.UnitApplication(unitName)
+		if err != nil {
+			return common.ErrPerm
+		}
+		appNames = append(appNames, appName)
+	}
+	if err := a.checkCanOperateApplications(permission.AdminAccess, appNames); err != nil {
+		return errors.Trace(err)
+	}
+
+	units, err := getAllUnitNames(a.state, run.Units, run.Applications)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sharing := set.NewStrings()
+	for _, tag := range units {
+		unit, err := a.state.Unit(tag.Id())
+		if errors.IsNotFound(err) {
+			// Nothing runs on a unit that doesn't exist.
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		apps, err := common.ApplicationsSharingMachine(a.state, machineId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sharing = sharing.Union(set.NewStrings(apps...))
+	}
+	if sharing.IsEmpty() {
+		return nil
+	}
+	return a.checkCanOperateApplications(permission.AdminAccess, sharing.SortedValues())
+}
+
 // Run the commands specified on the machines identified through the
 // list of machines, units and services.
 func (a *ActionAPI) Run(run params.RunParams) (results params.ActionResults, err error) {
-	if err := a.checkCanAdmin(); err != nil {
+	if err := a.checkCanRun(run); err != nil {
 		return results, err
 	}
 	if err := a.check.ChangeAllowed(); err != nil {
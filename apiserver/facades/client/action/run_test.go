@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/action"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -261,6 +262,105 @@ func (s *runSuite) TestRunRequiresAdmin(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *runSuite) TestRunApplicationOperator(c *gc.C) {
+	called := false
+	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, args params.Actions) (params.ActionResults, error) {
+		called = true
+		return params.ActionResults{}, nil
+	})
+	s.addUnit(c, s.AddTestingApplication(c, "magic", s.AddTestingCharm(c, "dummy")))
+	s.AddTestingApplication(c, "other", s.AddTestingCharm(c, "dummy"))
+
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("operate-application-magic"),
+	}
+	client, err := action.NewActionAPI(s.State, nil, auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = client.Run(params.RunParams{Commands: "hostname", Applications: []string{"magic"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+
+	called = false
+	for _, run := range []params.RunParams{
+		{Commands: "hostname", Applications: []string{"other"}},
+		{Commands: "hostname", Units: []string{"other/0"}},
+		{Commands: "hostname", Applications: []string{"magic"}, Machines: []string{"0"}},
+	} {
+		_, err = client.Run(run)
+		c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	}
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *runSuite) TestRunApplicationOperatorSharedMachine(c *gc.C) {
+	called := false
+	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, args params.Actions) (params.ActionResults, error) {
+		called = true
+		return params.ActionResults{}, nil
+	})
+	magic := s.addUnit(c, s.AddTestingApplication(c, "magic", s.AddTestingCharm(c, "dummy")))
+	machineId, err := magic.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.AddTestingApplication(c, "other", s.AddTestingCharm(c, "dummy")).AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("operate-application-magic"),
+	}
+	client, err := action.NewActionAPI(s.State, nil, auth)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Running as root on magic/0 would reach other/0 too.
+	for _, run := range []params.RunParams{
+		{Commands: "hostname", Applications: []string{"magic"}},
+		{Commands: "hostname", Units: []string{"magic/0"}},
+	} {
+		_, err = client.Run(run)
+		c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	}
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *runSuite) TestRunApplicationOperatorContainerHost(c *gc.C) {
+	called := false
+	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, args params.Actions) (params.ActionResults, error) {
+		called = true
+		return params.ActionResults{}, nil
+	})
+	host := s.addMachine(c)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	magic, err := s.AddTestingApplication(c, "magic", s.AddTestingCharm(c, "dummy")).AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = magic.AssignToMachine(host)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.AddTestingApplication(c, "other", s.AddTestingCharm(c, "dummy")).AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The operator of either application can reach the other's unit
+	// through the container's host.
+	for _, appName := range []string{"magic", "other"} {
+		auth := apiservertesting.FakeAuthorizer{
+			Tag: names.NewUserTag("operate-application-" + appName),
+		}
+		client, err := action.NewActionAPI(s.State, nil, auth)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = client.Run(params.RunParams{Commands: "hostname", Applications: []string{appName}})
+		c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	}
+	c.Assert(called, jc.IsFalse)
+}
+
 func (s *runSuite) TestRunOnAllMachinesRequiresAdmin(c *gc.C) {
 	alpha := names.NewUserTag("alpha@bravo")
 	auth := apiservertesting.FakeAuthorizer{
@@ -0,0 +1,114 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+// ModifyApplicationAccess isn't on the v7 API.
+func (api *APIv7) ModifyApplicationAccess(_, _ struct{}) {}
+
+// ModifyApplicationAccess grants or revokes the access users have to
+// applications in the model. Model admins may change access to any
+// application; users with admin access to an application may change
+// access to that application.
+func (api *APIBase) ModifyApplicationAccess(args params.ModifyApplicationAccessRequest) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if len(args.Changes) == 0 {
+		return result, nil
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	canAdminModel, err := api.hasModelAdmin()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		err := api.modifyOneApplicationAccess(canAdminModel, arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *APIBase) hasModelAdmin() (bool, error) {
+	isSuperUser, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil || isSuperUser {
+		return isSuperUser, errors.Trace(err)
+	}
+	isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, api.modelTag)
+	return isAdmin, errors.Trace(err)
+}
+
+func (api *APIBase) modifyOneApplicationAccess(canAdminModel bool, arg params.ModifyApplicationAccess) error {
+	access := permission.Access(arg.Access)
+	if err := permission.ValidateApplicationAccess(access); err != nil {
+		return errors.Annotate(err, "could not modify application access")
+	}
+	appTag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return errors.Annotate(err, "could not modify application access")
+	}
+	if !canAdminModel {
+		if err := api.checkPermission(appTag, permission.AdminAccess); err != nil {
+			return err
+		}
+	}
+	userTag, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Annotate(err, "could not modify application access")
+	}
+	switch arg.Action {
+	case params.GrantApplicationAccess:
+		return api.grantApplicationAccess(appTag, userTag, access)
+	case params.RevokeApplicationAccess:
+		return api.revokeApplicationAccess(appTag, userTag, access)
+	default:
+		return errors.Errorf("unknown action %q", arg.Action)
+	}
+}
+
+func (api *APIBase) grantApplicationAccess(appTag names.ApplicationTag, userTag names.UserTag, access permission.Access) error {
+	err := api.backend.CreateApplicationAccess(appTag, userTag, access)
+	if !errors.IsAlreadyExists(err) {
+		return errors.Annotate(err, "could not grant application access")
+	}
+	current, err := api.backend.GetApplicationAccess(appTag.Id(), userTag)
+	if err != nil {
+		return errors.Annotate(err, "could not look up application access for user")
+	}
+	// Only set access if greater access is being granted.
+	if current.EqualOrGreaterApplicationAccessThan(access) {
+		return errors.Errorf("user already has %q access or greater", access)
+	}
+	err = api.backend.UpdateApplicationAccess(appTag, userTag, access)
+	return errors.Annotate(err, "could not set application access for user")
+}
+
+func (api *APIBase) revokeApplicationAccess(appTag names.ApplicationTag, userTag names.UserTag, access permission.Access) error {
+	switch access {
+	case permission.ReadAccess:
+		// Revoking read access removes all access.
+		err := api.backend.RemoveApplicationAccess(appTag, userTag)
+		return errors.Annotate(err, "could not revoke application access")
+	case permission.OperateAccess:
+		// Revoking operate access sets read-only.
+		err := api.backend.UpdateApplicationAccess(appTag, userTag, permission.ReadAccess)
+		return errors.Annotate(err, "could not set application access to read-only")
+	case permission.AdminAccess:
+		// Revoking admin access sets operate.
+		err := api.backend.UpdateApplicationAccess(appTag, userTag, permission.OperateAccess)
+		return errors.Annotate(err, "could not set application access to operate")
+	default:
+		return errors.Errorf("don't know how to revoke %q access", access)
+	}
+}
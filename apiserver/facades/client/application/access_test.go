@@ -0,0 +1,90 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+func (s *ApplicationSuite) modifyAccess(c *gc.C, action params.ApplicationAction, access params.ApplicationAccessPermission, app string) error {
+	results, err := s.api.ModifyApplicationAccess(params.ModifyApplicationAccessRequest{
+		Changes: []params.ModifyApplicationAccess{{
+			UserTag:        "user-bob",
+			Action:         action,
+			Access:         access,
+			ApplicationTag: names.NewApplicationTag(app).String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	return results.OneError()
+}
+
+func (s *ApplicationSuite) TestGrantApplicationAccess(c *gc.C) {
+	err := s.modifyAccess(c, params.GrantApplicationAccess, params.ApplicationOperateAccess, "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCall(c, 0, "CreateApplicationAccess",
+		names.NewApplicationTag("postgresql"), names.NewUserTag("bob"), permission.OperateAccess)
+
+	// Granting greater access upgrades the existing access.
+	s.backend.ResetCalls()
+	err = s.modifyAccess(c, params.GrantApplicationAccess, params.ApplicationAdminAccess, "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "CreateApplicationAccess", "GetApplicationAccess", "UpdateApplicationAccess")
+	s.backend.CheckCall(c, 2, "UpdateApplicationAccess",
+		names.NewApplicationTag("postgresql"), names.NewUserTag("bob"), permission.AdminAccess)
+
+	// Granting lesser access is an error.
+	err = s.modifyAccess(c, params.GrantApplicationAccess, params.ApplicationReadAccess, "postgresql")
+	c.Assert(err, gc.ErrorMatches, `user already has "read" access or greater`)
+}
+
+func (s *ApplicationSuite) TestRevokeApplicationAccess(c *gc.C) {
+	err := s.modifyAccess(c, params.RevokeApplicationAccess, params.ApplicationOperateAccess, "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCall(c, 0, "UpdateApplicationAccess",
+		names.NewApplicationTag("postgresql"), names.NewUserTag("bob"), permission.ReadAccess)
+
+	s.backend.ResetCalls()
+	err = s.modifyAccess(c, params.RevokeApplicationAccess, params.ApplicationReadAccess, "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCall(c, 0, "RemoveApplicationAccess",
+		names.NewApplicationTag("postgresql"), names.NewUserTag("bob"))
+}
+
+func (s *ApplicationSuite) TestModifyApplicationAccessInvalidAccess(c *gc.C) {
+	err := s.modifyAccess(c, params.GrantApplicationAccess, "write", "postgresql")
+	c.Assert(err, gc.ErrorMatches, `could not modify application access: "write" application access not valid`)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestModifyApplicationAccessApplicationAdmin(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("admin-application-postgresql"))
+	err := s.modifyAccess(c, params.GrantApplicationAccess, params.ApplicationReadAccess, "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.modifyAccess(c, params.GrantApplicationAccess, params.ApplicationReadAccess, "mysql")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "CreateApplicationAccess")
+}
+
+func (s *ApplicationSuite) TestBlockModifyApplicationAccess(c *gc.C) {
+	s.blockChecker.SetErrors(errors.New("blocked"))
+	_, err := s.api.ModifyApplicationAccess(params.ModifyApplicationAccessRequest{
+		Changes: []params.ModifyApplicationAccess{{
+			UserTag:        "user-bob",
+			Action:         params.GrantApplicationAccess,
+			Access:         params.ApplicationReadAccess,
+			ApplicationTag: "application-postgresql",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "blocked")
+	s.backend.CheckNoCalls(c)
+}
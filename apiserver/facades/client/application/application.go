@@ -57,6 +57,11 @@ type APIv6 struct {
 
 // APIv7 provides the Application API facade for version 7.
 type APIv7 struct {
+	*APIv8
+}
+
+// APIv8 provides the Application API facade for version 8.
+type APIv8 struct {
 	*APIBase
 }
 
@@ -117,13 +122,23 @@ func NewFacadeV6(ctx facade.Context) (*APIv6, error) {
 // NewFacadeV7 provides the signature required for facade registration
 // for version 7.
 func NewFacadeV7(ctx facade.Context) (*APIv7, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV8(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv7{api}, nil
 }
 
+// NewFacadeV8 provides the signature required for facade registration
+// for version 8.
+func NewFacadeV8(ctx facade.Context) (*APIv8, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv8{api}, nil
+}
+
 func newFacadeBase(ctx facade.Context) (*APIBase, error) {
 	model, err := ctx.State().Model()
 	if err != nil {
@@ -192,9 +207,59 @@ func (api *APIBase) checkCanWrite() error {
 	return api.checkPermission(api.modelTag, permission.WriteAccess)
 }
 
+// checkCanAdminApplications returns an error unless the user can write
+// to the model, or has been granted admin access to each of the named
+// applications.
+func (api *APIBase) checkCanAdminApplications(appNames ...string) error {
+	return api.checkApplicationsPermission(permission.AdminAccess, appNames)
+}
+
+// checkCanOperateApplications returns an error unless the user can write
+// to the model, or has been granted operate access to each of the named
+// applications.
+func (api *APIBase) checkCanOperateApplications(appNames ...string) error {
+	return api.checkApplicationsPermission(permission.OperateAccess, appNames)
+}
+
+func (api *APIBase) checkApplicationsPermission(perm permission.Access, appNames []string) error {
+	err := api.checkCanWrite()
+	if err != common.ErrPerm || len(appNames) == 0 {
+		return err
+	}
+	for _, name := range appNames {
+		if err := api.checkPermission(names.NewApplicationTag(name), perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unitApplicationNames returns the names of the applications of the
+// units with the given tags. Tags that are not valid unit tags are
+// skipped, to be reported when the units are processed.
+func unitApplicationNames(unitTags []string) []string {
+	var appNames []string
+	for _, tag := range unitTags {
+		unitTag, err := names.ParseUnitTag(tag)
+		if err != nil {
+			continue
+		}
+		appName, err := names.UnitApplication(unitTag.Id())
+		if err != nil {
+			continue
+		}
+		appNames = append(appNames, appName)
+	}
+	return appNames
+}
+
 // SetMetricCredentials sets credentials on the application.
 func (api *APIBase) SetMetricCredentials(args params.ApplicationMetricCredentials) (params.ErrorResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	appNames := make([]string, len(args.Creds))
+	for i, a := range args.Creds {
+		appNames[i] = a.ApplicationName
+	}
+	if err := api.checkCanAdminApplications(appNames...); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
 	result := params.ErrorResults{
@@ -311,7 +376,7 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 
 func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, schema.Defaults, error) {
 	if modelType != state.ModelTypeCAAS {
-		return trustFields, trustDefaults, nil
+		return addResourceTagsSchemaAndDefaults(trustFields, trustDefaults)
 	}
 	// TODO(caas) - get the schema from the provider
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
@@ -404,6 +469,9 @@ func deployApplication(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := validateResourceTags(appConfigAttrs); err != nil {
+		return errors.Trace(err)
+	}
 
 	var applicationConfig *application.Config
 	schema, defaults, err := applicationConfigSchema(modelType)
@@ -518,7 +586,7 @@ func parseSettingsCompatible(charmConfig *charm.Config, settings map[string]stri
 // minimum number of units, charm config and constraints.
 // All parameters in params.ApplicationUpdate except the application name are optional.
 func (api *APIBase) Update(args params.ApplicationUpdate) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(args.ApplicationName); err != nil {
 		return err
 	}
 	if !args.ForceCharmURL {
@@ -576,7 +644,13 @@ func (api *APIBase) Update(args params.ApplicationUpdate) error {
 // UpdateApplicationSeries updates the application series. Series for
 // subordinates updated too.
 func (api *APIBase) UpdateApplicationSeries(args params.UpdateSeriesArgs) (params.ErrorResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	var appNames []string
+	for _, arg := range args.Args {
+		if tag, err := names.ParseApplicationTag(arg.Entity.Tag); err == nil {
+			appNames = append(appNames, tag.Id())
+		}
+	}
+	if err := api.checkCanAdminApplications(appNames...); err != nil {
 		return params.ErrorResults{}, err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -621,7 +695,7 @@ func (api *APIBase) updateOneApplicationSeries(arg params.UpdateSeriesArg) error
 
 // SetCharm sets the charm for a given for the application.
 func (api *APIBase) SetCharm(args params.ApplicationSetCharm) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(args.ApplicationName); err != nil {
 		return err
 	}
 	// when forced units in error, don't block
@@ -807,7 +881,7 @@ func applicationSetCharmConfigYAML(appName string, application Application, sett
 // GetCharmURL returns the charm URL the given application is
 // running at present.
 func (api *APIBase) GetCharmURL(args params.ApplicationGet) (params.StringResult, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(args.ApplicationName); err != nil {
 		return params.StringResult{}, errors.Trace(err)
 	}
 	application, err := api.backend.Application(args.ApplicationName)
@@ -822,7 +896,7 @@ func (api *APIBase) GetCharmURL(args params.ApplicationGet) (params.StringResult
 // It does not unset values that are set to an empty string.
 // Unset should be used for that.
 func (api *APIBase) Set(p params.ApplicationSet) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(p.ApplicationName); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -848,7 +922,7 @@ func (api *APIBase) Set(p params.ApplicationSet) error {
 
 // Unset implements the server side of Client.Unset.
 func (api *APIBase) Unset(p params.ApplicationUnset) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(p.ApplicationName); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -890,7 +964,7 @@ func (api *APIBase) CharmRelations(p params.ApplicationCharmRelations) (params.A
 // Expose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (api *APIBase) Expose(args params.ApplicationExpose) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(args.ApplicationName); err != nil {
 		return errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -917,7 +991,7 @@ func (api *APIBase) Expose(args params.ApplicationExpose) error {
 // Unexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 func (api *APIBase) Unexpose(args params.ApplicationUnexpose) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(args.ApplicationName); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -944,7 +1018,7 @@ func (api *APIv5) AddUnits(args params.AddApplicationUnitsV5) (params.AddApplica
 
 // AddUnits adds a given number of units to an application.
 func (api *APIBase) AddUnits(args params.AddApplicationUnits) (params.AddApplicationUnitsResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(args.ApplicationName); err != nil {
 		return params.AddApplicationUnitsResults{}, errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -1063,7 +1137,11 @@ func (api *APIv4) DestroyUnit(args params.Entities) (params.DestroyUnitResults,
 
 // DestroyUnit removes a given set of application units.
 func (api *APIBase) DestroyUnit(args params.DestroyUnitsParams) (params.DestroyUnitResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	unitTags := make([]string, len(args.Units))
+	for i, arg := range args.Units {
+		unitTags[i] = arg.UnitTag
+	}
+	if err := api.checkCanAdminApplications(unitApplicationNames(unitTags)...); err != nil {
 		return params.DestroyUnitResults{}, errors.Trace(err)
 	}
 	if err := api.check.RemoveAllowed(); err != nil {
@@ -1171,7 +1249,13 @@ func (api *APIv4) DestroyApplication(args params.Entities) (params.DestroyApplic
 
 // DestroyApplication removes a given set of applications.
 func (api *APIBase) DestroyApplication(args params.DestroyApplicationsParams) (params.DestroyApplicationResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	var appNames []string
+	for _, arg := range args.Applications {
+		if tag, err := names.ParseApplicationTag(arg.ApplicationTag); err == nil {
+			appNames = append(appNames, tag.Id())
+		}
+	}
+	if err := api.checkCanAdminApplications(appNames...); err != nil {
 		return params.DestroyApplicationResults{}, err
 	}
 	if err := api.check.RemoveAllowed(); err != nil {
@@ -1319,7 +1403,7 @@ func (api *APIBase) getConstraints(entity string) (constraints.Value, error) {
 
 // SetConstraints sets the constraints for a given application.
 func (api *APIBase) SetConstraints(args params.SetConstraints) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanAdminApplications(args.ApplicationName); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -1691,7 +1775,11 @@ func (u *APIv5) SetApplicationsConfig(_, _ struct{}) {}
 // Unset should be used for that.
 func (api *APIBase) SetApplicationsConfig(args params.ApplicationConfigSetArgs) (params.ErrorResults, error) {
 	var result params.ErrorResults
-	if err := api.checkCanWrite(); err != nil {
+	appNames := make([]string, len(args.Args))
+	for i, arg := range args.Args {
+		appNames[i] = arg.ApplicationName
+	}
+	if err := api.checkCanAdminApplications(appNames...); err != nil {
 		return result, errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -1715,6 +1803,9 @@ func (api *APIBase) setApplicationConfig(arg params.ApplicationConfigSet) error
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := validateResourceTags(appConfigAttrs); err != nil {
+		return errors.Trace(err)
+	}
 	schema, defaults, err := applicationConfigSchema(api.modelType)
 	if err != nil {
 		return errors.Trace(err)
@@ -1748,7 +1839,11 @@ func (u *APIv5) UnsetApplicationsConfig(_, _ struct{}) {}
 // UnsetApplicationsConfig implements the server side of Application.UnsetApplicationsConfig.
 func (api *APIBase) UnsetApplicationsConfig(args params.ApplicationConfigUnsetArgs) (params.ErrorResults, error) {
 	var result params.ErrorResults
-	if err := api.checkCanWrite(); err != nil {
+	appNames := make([]string, len(args.Args))
+	for i, arg := range args.Args {
+		appNames[i] = arg.ApplicationName
+	}
+	if err := api.checkCanAdminApplications(appNames...); err != nil {
 		return result, errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -1802,6 +1897,25 @@ func (u *APIv5) ResolveUnitErrors(_, _ struct{}) {}
 
 // ResolveUnitErrors marks errors on the specified units as resolved.
 func (api *APIBase) ResolveUnitErrors(p params.UnitsResolved) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if p.All {
+		// Resolving all units requires access to the whole model.
+		if err := api.checkCanWrite(); err != nil {
+			return result, errors.Trace(err)
+		}
+	} else {
+		unitTags := make([]string, len(p.Tags.Entities))
+		for i, entity := range p.Tags.Entities {
+			unitTags[i] = entity.Tag
+		}
+		if err := api.checkCanOperateApplications(unitApplicationNames(unitTags)...); err != nil {
+			return result, errors.Trace(err)
+		}
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+
 	if p.All {
 		unitsWithErrors, err := api.backend.UnitsInError()
 		if err != nil {
@@ -1814,14 +1928,6 @@ func (api *APIBase) ResolveUnitErrors(p params.UnitsResolved) (params.ErrorResul
 		}
 	}
 
-	var result params.ErrorResults
-	if err := api.checkCanWrite(); err != nil {
-		return result, errors.Trace(err)
-	}
-	if err := api.check.ChangeAllowed(); err != nil {
-		return result, errors.Trace(err)
-	}
-
 	result.Results = make([]params.ErrorResult, len(p.Tags.Entities))
 	for i, entity := range p.Tags.Entities {
 		tag, err := names.ParseUnitTag(entity.Tag)
@@ -47,7 +47,7 @@ type applicationSuite struct {
 	apiservertesting.CharmStoreSuite
 	commontesting.BlockHelper
 
-	applicationAPI *application.APIv7
+	applicationAPI *application.APIv8
 	application    *state.Application
 	authorizer     *apiservertesting.FakeAuthorizer
 }
@@ -84,7 +84,7 @@ func (s *applicationSuite) TearDownTest(c *gc.C) {
 	s.JujuConnSuite.TearDownTest(c)
 }
 
-func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv7 {
+func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv8 {
 	resources := common.NewResources()
 	resources.RegisterNamed("dataDir", common.StringResource(c.MkDir()))
 	storageAccess, err := application.GetStorageState(s.State)
@@ -103,7 +103,7 @@ func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv7 {
 		application.DeployApplication,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	return &application.APIv7{api}
+	return &application.APIv8{api}
 }
 
 func (s *applicationSuite) TestGetConfig(c *gc.C) {
@@ -604,6 +604,56 @@ func (s *applicationSuite) TestApplicationDeploymentNoTrust(c *gc.C) {
 	c.Assert(trust, jc.IsFalse)
 }
 
+func (s *applicationSuite) TestApplicationDeploymentWithResourceTags(c *gc.C) {
+	curl, ch := s.UploadCharm(c, "precise/dummy-42", "dummy")
+	err := application.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{
+		URL: curl.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var cons constraints.Value
+	args := params.ApplicationDeploy{
+		ApplicationName: "application",
+		CharmURL:        curl.String(),
+		NumUnits:        1,
+		Config:          map[string]string{"resource-tags": "team=web cost-centre=42"},
+		Placement: []*instance.Placement{
+			{"deadbeef-0bad-400d-8000-4b1d0d06f00d", "valid"},
+		},
+	}
+	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{args}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+
+	app := apiservertesting.AssertPrincipalApplicationDeployed(c, s.State, "application", curl, false, ch, cons)
+	appConfig, err := app.ApplicationConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(appConfig.GetString(application.ResourceTagsConfigOptionName, ""), gc.Equals, "team=web cost-centre=42")
+}
+
+func (s *applicationSuite) TestApplicationDeploymentWithInvalidResourceTags(c *gc.C) {
+	curl, _ := s.UploadCharm(c, "precise/dummy-42", "dummy")
+	err := application.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{
+		URL: curl.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.ApplicationDeploy{
+		ApplicationName: "application",
+		CharmURL:        curl.String(),
+		NumUnits:        1,
+		Config:          map[string]string{"resource-tags": "juju-model-uuid=foo"},
+	}
+	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{args}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `invalid resource-tags: tag "juju-model-uuid" uses reserved prefix "juju-"`)
+}
+
 func (s *applicationSuite) testClientApplicationsDeployWithBindings(c *gc.C, endpointBindings, expected map[string]string) {
 	curl, _ := s.UploadCharm(c, "utopic/riak-42", "riak")
 	err := application.AddCharmWithAuthorization(s.State, params.AddCharmWithAuthorization{
@@ -39,7 +39,7 @@ type ApplicationSuite struct {
 	env          environs.Environ
 	blockChecker mockBlockChecker
 	authorizer   apiservertesting.FakeAuthorizer
-	api          *application.APIv7
+	api          *application.APIv8
 }
 
 var _ = gc.Suite(&ApplicationSuite{})
@@ -61,7 +61,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv7{api}
+	s.api = &application.APIv8{api}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -1184,6 +1184,53 @@ func (s *ApplicationSuite) TestResolveUnitErrorsPermissionDenied(c *gc.C) {
 	s.application.CheckNoCalls(c)
 }
 
+func (s *ApplicationSuite) TestResolveUnitErrorsApplicationOperator(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("operate-application-postgresql"))
+
+	p := params.UnitsResolved{
+		Tags: params.Entities{
+			Entities: []params.Entity{{Tag: "unit-postgresql-0"}},
+		},
+	}
+	result, err := s.api.ResolveUnitErrors(p)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.backend.applications["postgresql"].units[0].CheckCallNames(c, "Resolve")
+
+	// Resolving all units needs model write access.
+	_, err = s.api.ResolveUnitErrors(params.UnitsResolved{All: true})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ApplicationSuite) TestExposeApplicationAdmin(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("admin-application-postgresql"))
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.applications["postgresql"].CheckCallNames(c, "SetExposed")
+}
+
+func (s *ApplicationSuite) TestExposeApplicationOperator(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("operate-application-postgresql"))
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestDestroyApplicationOtherApplicationAdmin(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("admin-application-mysql"))
+	_, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestCAASExposeWithoutHostname(c *gc.C) {
 	application.SetModelType(s.api, state.ModelTypeCAAS)
 	err := s.api.Expose(params.ApplicationExpose{
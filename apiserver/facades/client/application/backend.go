@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
 )
@@ -43,6 +44,10 @@ type Backend interface {
 	Resources() (Resources, error)
 	OfferConnectionForRelation(string) (OfferConnection, error)
 	SaveEgressNetworks(relationKey string, cidrs []string) (state.RelationNetworks, error)
+	GetApplicationAccess(string, names.UserTag) (permission.Access, error)
+	CreateApplicationAccess(names.ApplicationTag, names.UserTag, permission.Access) error
+	UpdateApplicationAccess(names.ApplicationTag, names.UserTag, permission.Access) error
+	RemoveApplicationAccess(names.ApplicationTag, names.UserTag) error
 }
 
 // BlockChecker defines the block-checking functionality required by
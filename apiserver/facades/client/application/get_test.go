@@ -29,7 +29,7 @@ import (
 type getSuite struct {
 	jujutesting.JujuConnSuite
 
-	applicationAPI *application.APIv7
+	applicationAPI *application.APIv8
 	authorizer     apiservertesting.FakeAuthorizer
 }
 
@@ -57,7 +57,7 @@ func (s *getSuite) SetUpTest(c *gc.C) {
 		application.DeployApplication,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.applicationAPI = &application.APIv7{api}
+	s.applicationAPI = &application.APIv8{api}
 }
 
 func (s *getSuite) TestClientApplicationGetSmoketestV4(c *gc.C) {
@@ -119,6 +119,13 @@ func (s *getSuite) TestClientApplicationGetIAASModelSmoketest(c *gc.C) {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"resource-tags": map[string]interface{}{
+				"default":     "",
+				"description": "Space-separated list of k=v pairs, defining the tags to set on the application's machines and volumes, in addition to the model's resource-tags",
+				"source":      "default",
+				"type":        environschema.Tstring,
+				"value":       "",
+			},
 			"trust": map[string]interface{}{
 				"default":     false,
 				"description": "Does this application have access to trusted credentials",
@@ -194,7 +201,7 @@ func (s *getSuite) TestClientApplicationGetCAASModelSmoketest(c *gc.C) {
 		application.DeployApplication,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	apiV7 := &application.APIv7{api}
+	apiV7 := &application.APIv7{&application.APIv8{api}}
 
 	results, err := apiV7.Get(params.ApplicationGet{"wordpress"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -267,6 +274,13 @@ var getTests = []struct {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"resource-tags": map[string]interface{}{
+				"value":       "",
+				"default":     "",
+				"description": "Space-separated list of k=v pairs, defining the tags to set on the application's machines and volumes, in addition to the model's resource-tags",
+				"source":      "default",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -325,6 +339,13 @@ var getTests = []struct {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"resource-tags": map[string]interface{}{
+				"value":       "",
+				"default":     "",
+				"description": "Space-separated list of k=v pairs, defining the tags to set on the application's machines and volumes, in addition to the model's resource-tags",
+				"source":      "default",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -342,6 +363,13 @@ var getTests = []struct {
 		CharmConfig: map[string]interface{}{},
 		Series:      "quantal",
 		ApplicationConfig: map[string]interface{}{
+			"resource-tags": map[string]interface{}{
+				"value":       "",
+				"default":     "",
+				"description": "Space-separated list of k=v pairs, defining the tags to set on the application's machines and volumes, in addition to the model's resource-tags",
+				"source":      "default",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/status"
@@ -239,6 +240,7 @@ type mockBackend struct {
 	storageInstances           map[string]*mockStorage
 	storageInstanceFilesystems map[string]*mockFilesystem
 	controllers                map[string]crossmodel.ControllerInfo
+	applicationAccess          map[string]permission.Access
 }
 
 type mockFilesystemAccess struct {
@@ -254,6 +256,52 @@ func (m *mockBackend) FilesystemAccess() storagecommon.FilesystemAccess {
 	return &mockFilesystemAccess{mockBackend: m}
 }
 
+func (m *mockBackend) GetApplicationAccess(appName string, user names.UserTag) (permission.Access, error) {
+	m.MethodCall(m, "GetApplicationAccess", appName, user)
+	if err := m.NextErr(); err != nil {
+		return "", err
+	}
+	access, ok := m.applicationAccess[appName+":"+user.Id()]
+	if !ok {
+		return "", errors.NotFoundf("access for %q on %q", user.Id(), appName)
+	}
+	return access, nil
+}
+
+func (m *mockBackend) CreateApplicationAccess(app names.ApplicationTag, user names.UserTag, access permission.Access) error {
+	m.MethodCall(m, "CreateApplicationAccess", app, user, access)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	key := app.Id() + ":" + user.Id()
+	if _, ok := m.applicationAccess[key]; ok {
+		return errors.AlreadyExistsf("access for %q on %q", user.Id(), app.Id())
+	}
+	if m.applicationAccess == nil {
+		m.applicationAccess = make(map[string]permission.Access)
+	}
+	m.applicationAccess[key] = access
+	return nil
+}
+
+func (m *mockBackend) UpdateApplicationAccess(app names.ApplicationTag, user names.UserTag, access permission.Access) error {
+	m.MethodCall(m, "UpdateApplicationAccess", app, user, access)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	m.applicationAccess[app.Id()+":"+user.Id()] = access
+	return nil
+}
+
+func (m *mockBackend) RemoveApplicationAccess(app names.ApplicationTag, user names.UserTag) error {
+	m.MethodCall(m, "RemoveApplicationAccess", app, user)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	delete(m.applicationAccess, app.Id()+":"+user.Id())
+	return nil
+}
+
 func (m *mockBackend) ControllerTag() names.ControllerTag {
 	return coretesting.ControllerTag
 }
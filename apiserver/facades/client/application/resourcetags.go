@@ -0,0 +1,69 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/environs/tags"
+)
+
+// ResourceTagsConfigOptionName is the option name used to set the
+// tags for an application's machines and volumes in application
+// configuration.
+const ResourceTagsConfigOptionName = "resource-tags"
+
+var resourceTagsFields = environschema.Fields{
+	ResourceTagsConfigOptionName: {
+		Description: "Space-separated list of k=v pairs, defining the tags to set on the application's machines and volumes, in addition to the model's resource-tags",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+var resourceTagsDefaults = schema.Defaults{
+	ResourceTagsConfigOptionName: "",
+}
+
+// addResourceTagsSchemaAndDefaults adds resource tags schema fields and
+// defaults to an existing set of schema fields and defaults.
+func addResourceTagsSchemaAndDefaults(extra environschema.Fields, defaults schema.Defaults) (environschema.Fields, schema.Defaults, error) {
+	fields := make(environschema.Fields)
+	for name, field := range resourceTagsFields {
+		fields[name] = field
+	}
+	for name, field := range extra {
+		if _, ok := resourceTagsFields[name]; ok {
+			return nil, nil, errors.Errorf("config field %q clashes with common config", name)
+		}
+		fields[name] = field
+	}
+	newDefaults := make(schema.Defaults)
+	for key, value := range resourceTagsDefaults {
+		newDefaults[key] = value
+	}
+	for key, value := range defaults {
+		newDefaults[key] = value
+	}
+	return fields, newDefaults, nil
+}
+
+// validateResourceTags checks that the resource tags in the given
+// application config attributes, if any, are well formed.
+func validateResourceTags(attrs map[string]interface{}) error {
+	value, ok := attrs[ResourceTagsConfigOptionName]
+	if !ok {
+		return nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return errors.NotValidf("%s value %v", ResourceTagsConfigOptionName, value)
+	}
+	if _, err := tags.Parse(s); err != nil {
+		return errors.Annotatef(err, "invalid %s", ResourceTagsConfigOptionName)
+	}
+	return nil
+}
@@ -260,7 +260,7 @@ func (api *OffersAPI) modifyOneOfferAccess(modelUUID string, isControllerAdmin b
 		if err != nil {
 			return common.ErrPerm
 		}
-		access, err := backend.GetOfferAccess(offer.OfferUUID, apiUser)
+		access, err := common.EffectiveOfferAccess(backend, offer.OfferUUID, apiUser)
 		if err != nil && !errors.IsNotFound(err) {
 			return errors.Trace(err)
 		} else if err == nil {
@@ -247,7 +247,7 @@ func (api *BaseAPI) getOfferAdminDetails(backend Backend, app crossmodel.Applica
 // so long as it is greater than the requested perm.
 func (api *BaseAPI) checkOfferAccess(backend Backend, offerUUID string, perm permission.Access) (permission.Access, error) {
 	apiUser := api.Authorizer.GetAuthTag().(names.UserTag)
-	access, err := backend.GetOfferAccess(offerUUID, apiUser)
+	access, err := common.EffectiveOfferAccess(backend, offerUUID, apiUser)
 	if err != nil && !errors.IsNotFound(err) {
 		return permission.NoAccess, errors.Trace(err)
 	}
@@ -442,6 +442,10 @@ func (m *mockState) IngressNetworks(relationKey string) (state.RelationNetworks,
 	return m.relationNetworks, nil
 }
 
+func (m *mockState) UserGroups(user names.UserTag) ([]names.UserTag, error) {
+	return nil, nil
+}
+
 func (m *mockState) GetOfferAccess(offerUUID string, user names.UserTag) (permission.Access, error) {
 	access, ok := m.accessPerms[offerAccess{user: user, offerUUID: offerUUID}]
 	if !ok {
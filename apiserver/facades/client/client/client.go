@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/apiserver/facades/client/modelconfig"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
@@ -454,7 +455,13 @@ func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (param
 	}
 
 	var result params.ProvisioningScriptResult
-	icfg, err := InstanceConfig(c.api.state(), args.MachineId, args.Nonce, args.DataDir)
+	var icfg *instancecfg.InstanceConfig
+	var err error
+	if args.DryRun {
+		icfg, err = DryRunInstanceConfig(c.api.state(), args.MachineId, args.Nonce, args.Series, args.Arch, args.DataDir)
+	} else {
+		icfg, err = InstanceConfig(c.api.state(), args.MachineId, args.Nonce, args.DataDir)
+	}
 	if err != nil {
 		return result, common.ServerError(errors.Annotate(
 			err, "getting instance config",
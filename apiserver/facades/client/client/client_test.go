@@ -1309,6 +1309,31 @@ func (s *clientSuite) TestProvisioningScript(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestProvisioningScriptDryRun(c *gc.C) {
+	modelConfig, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	series := config.PreferredSeries(modelConfig)
+	script, err := s.APIState.Client().ProvisioningScript(params.ProvisioningScriptParams{
+		MachineId: "42",
+		Nonce:     "foo",
+		DryRun:    true,
+		Series:    series,
+		Arch:      "amd64",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	icfg, err := client.DryRunInstanceConfig(s.State, "42", "foo", series, "amd64", "")
+	c.Assert(err, jc.ErrorIsNil)
+	icfg.EnableOSUpgrade = modelConfig.EnableOSUpgrade()
+	icfg.EnableOSRefreshUpdate = modelConfig.EnableOSRefreshUpdate()
+	provisioningScript, err := sshprovisioner.ProvisioningScript(icfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(script, gc.Equals, provisioningScript)
+
+	// Nothing was added to the model.
+	_, err = s.State.Machine("42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *clientSuite) TestProvisioningScriptDisablePackageCommands(c *gc.C) {
 	apiParams := params.AddMachineParams{
 		Jobs:       []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
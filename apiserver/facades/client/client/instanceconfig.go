@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
@@ -23,15 +24,6 @@ import (
 // is exposed for testing purposes.
 // TODO(rog) fix environs/manual tests so they do not need to call this, or move this elsewhere.
 func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instancecfg.InstanceConfig, error) {
-	model, err := st.Model()
-	if err != nil {
-		return nil, errors.Annotate(err, "getting state model")
-	}
-	modelConfig, err := model.ModelConfig()
-	if err != nil {
-		return nil, errors.Annotate(err, "getting model config")
-	}
-
 	// Get the machine so we can get its series and arch.
 	// If the Arch is not set in hardware-characteristics,
 	// an error is returned.
@@ -46,6 +38,50 @@ func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instanc
 	if hc.Arch == nil {
 		return nil, fmt.Errorf("arch is not set for %q", machine.Tag())
 	}
+	return instanceConfig(st, machineId, nonce, machine.Series(), *hc.Arch, dataDir,
+		func(apiInfo *api.Info) (*api.Info, error) {
+			_, apiInfo, err := authentication.SetupAuthentication(machine, nil, apiInfo)
+			if err != nil {
+				return nil, errors.Annotate(err, "setting up machine authentication")
+			}
+			return apiInfo, nil
+		},
+	)
+}
+
+// DryRunInstanceConfig returns the instance config that InstanceConfig
+// would return for a machine with the given id, series and arch, but
+// without the machine being in the model. Nothing is recorded, so the
+// machine agent is given no API password.
+func DryRunInstanceConfig(st *state.State, machineId, nonce, series, arch, dataDir string) (*instancecfg.InstanceConfig, error) {
+	if !names.IsValidMachine(machineId) {
+		return nil, errors.NotValidf("machine id %q", machineId)
+	}
+	return instanceConfig(st, machineId, nonce, series, arch, dataDir,
+		func(apiInfo *api.Info) (*api.Info, error) {
+			apiInfoCopy := *apiInfo
+			apiInfoCopy.Tag = names.NewMachineTag(machineId)
+			return &apiInfoCopy, nil
+		},
+	)
+}
+
+// instanceConfig returns the instance config for a machine with the
+// given id, series and arch, using setupAuth to give the machine agent
+// its API credentials.
+func instanceConfig(
+	st *state.State,
+	machineId, nonce, series, arch, dataDir string,
+	setupAuth func(*api.Info) (*api.Info, error),
+) (*instancecfg.InstanceConfig, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting state model")
+	}
+	modelConfig, err := model.ModelConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model config")
+	}
 
 	// Find the appropriate tools information.
 	agentVersion, ok := modelConfig.AgentVersion()
@@ -59,8 +95,8 @@ func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instanc
 		Number:       agentVersion,
 		MajorVersion: -1,
 		MinorVersion: -1,
-		Series:       machine.Series(),
-		Arch:         *hc.Arch,
+		Series:       series,
+		Arch:         arch,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "finding agent binaries")
@@ -93,13 +129,13 @@ func InstanceConfig(st *state.State, machineId, nonce, dataDir string) (*instanc
 		ModelTag: model.ModelTag(),
 	}
 
-	_, apiInfo, err = authentication.SetupAuthentication(machine, nil, apiInfo)
+	apiInfo, err = setupAuth(apiInfo)
 	if err != nil {
-		return nil, errors.Annotate(err, "setting up machine authentication")
+		return nil, errors.Trace(err)
 	}
 
 	icfg, err := instancecfg.NewInstanceConfig(st.ControllerTag(), machineId, nonce, modelConfig.ImageStream(),
-		machine.Series(), apiInfo,
+		series, apiInfo,
 	)
 	if err != nil {
 		return nil, errors.Annotate(err, "initializing instance config")
@@ -8,12 +8,15 @@ import (
 	"net"
 	"strconv"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facades/client/client"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
@@ -59,6 +62,29 @@ func (s *machineConfigSuite) TestMachineConfig(c *gc.C) {
 	})
 }
 
+func (s *machineConfigSuite) TestDryRunMachineConfig(c *gc.C) {
+	modelConfig, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig, err := client.DryRunInstanceConfig(
+		s.State, "42", "foo", config.PreferredSeries(modelConfig), "amd64", "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(instanceConfig.MachineId, gc.Equals, "42")
+	c.Check(instanceConfig.MachineNonce, gc.Equals, "foo")
+	c.Check(instanceConfig.APIInfo.Tag, gc.Equals, names.NewMachineTag("42"))
+	c.Check(instanceConfig.APIInfo.Password, gc.Equals, "")
+	c.Check(instanceConfig.AgentVersion().Arch, gc.Equals, "amd64")
+
+	// Nothing was added to the model.
+	_, err = s.State.Machine("42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *machineConfigSuite) TestDryRunMachineConfigInvalidMachineId(c *gc.C) {
+	_, err := client.DryRunInstanceConfig(s.State, "foo", "foo", "quantal", "amd64", "")
+	c.Assert(err, gc.ErrorMatches, `machine id "foo" not valid`)
+}
+
 func (s *machineConfigSuite) TestMachineConfigNoArch(c *gc.C) {
 	apiParams := params.AddMachineParams{
 		Jobs:       []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
@@ -400,7 +400,7 @@ func (c *Client) modelStatus() (params.ModelStatusInfo, error) {
 		}
 	}
 
-	status, err := m.Status()
+	modelStatus, err := m.Status()
 	if err != nil {
 		return params.ModelStatusInfo{}, errors.Annotate(err, "cannot obtain model status info")
 	}
@@ -408,11 +408,12 @@ func (c *Client) modelStatus() (params.ModelStatusInfo, error) {
 	info.SLA = m.SLALevel()
 
 	info.ModelStatus = params.DetailedStatus{
-		Status: status.Status.String(),
-		Info:   status.Message,
-		Since:  status.Since,
-		Data:   status.Data,
+		Status: modelStatus.Status.String(),
+		Info:   modelStatus.Message,
+		Since:  modelStatus.Since,
+		Data:   modelStatus.Data,
 	}
+	info.Warnings = status.TagDriftWarnings(modelStatus.Data)
 	ms := m.MeterStatus()
 	if isColorStatus(ms.Code) {
 		info.MeterStatus = params.MeterStatus{Color: strings.ToLower(ms.Code.String()), Message: ms.Info}
@@ -1364,6 +1365,13 @@ func (c *statusContext) processMachine(machine *state.Machine) (out params.Detai
 	statusInfo, err := c.presence.MachineStatus(wrapped)
 	populateStatusFromStatusInfoAndErr(&out, statusInfo, err)
 
+	// A running machine that is in maintenance reports it in place of
+	// its agent status, so that it stands out from the others.
+	if machine.InMaintenance() && out.Status == status.Started.String() {
+		out.Status = status.Maintenance.String()
+		out.Info = machine.MaintenanceMessage()
+	}
+
 	out.Life = processLife(machine)
 
 	if t, err := machine.AgentTools(); err == nil {
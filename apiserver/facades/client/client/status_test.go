@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -62,6 +63,25 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusTagDriftWarnings(c *gc.C) {
+	m, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetStatus(status.StatusInfo{
+		Status: status.Available,
+		Data: map[string]interface{}{
+			status.TagDriftKey: []string{"resource tags corrected on machine 0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	fullStatus, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fullStatus.Model.ModelStatus.Status, gc.Equals, "available")
+	c.Check(fullStatus.Model.ModelStatus.Info, gc.Equals, "")
+	c.Check(fullStatus.Model.Warnings, jc.DeepEquals, []string{"resource tags corrected on machine 0"})
+}
+
 func (s *statusSuite) TestFullStatusUnitLeadership(c *gc.C) {
 	u := s.Factory.MakeUnit(c, nil)
 	s.State.LeadershipClaimer().ClaimLeadership(u.ApplicationName(), u.Name(), time.Minute)
@@ -119,6 +139,30 @@ func (s *statusUnitTestSuite) TestProcessMachinesWithEmbeddedContainers(c *gc.C)
 	c.Check(mStatus.Containers, gc.HasLen, 1)
 }
 
+func (s *statusUnitTestSuite) TestProcessMachineInMaintenance(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{InstanceId: instance.Id("0")})
+	now := time.Now()
+	err := machine.SetStatus(status.StatusInfo{Status: status.Started, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+	s.setAgentPresence(c, machine)
+	err = machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	fullStatus, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	mStatus, ok := fullStatus.Machines[machine.Id()]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(mStatus.AgentStatus.Status, gc.Equals, "maintenance")
+	c.Check(mStatus.AgentStatus.Info, gc.Equals, "draining")
+
+	err = machine.ClearMaintenance()
+	c.Assert(err, jc.ErrorIsNil)
+	fullStatus, err = client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fullStatus.Machines[machine.Id()].AgentStatus.Status, gc.Equals, "started")
+}
+
 var testUnits = []struct {
 	unitName       string
 	setStatus      *state.MeterStatus
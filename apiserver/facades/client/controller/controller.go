@@ -24,7 +24,7 @@ import (
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/permission"
-	"github.com/juju/juju/pubsub/controller"
+	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
 )
 
@@ -45,11 +45,25 @@ type ControllerAPI struct {
 	hub        facade.Hub
 }
 
+// ControllerAPIv6 provides the v6 Controller API. The only difference
+// between this and v7 is that v6 doesn't have the controller CA
+// rotation methods.
+type ControllerAPIv6 struct {
+	*ControllerAPI
+}
+
+// ControllerAPIv5 provides the v5 Controller API. The only difference
+// between this and v6 is that v5 doesn't have the PrecheckMigration
+// method.
+type ControllerAPIv5 struct {
+	*ControllerAPIv6
+}
+
 // ControllerAPIv4 provides the v4 Controller API. The only difference
 // between this and v5 is that v4 doesn't have the
 // UpdateControllerConfig method.
 type ControllerAPIv4 struct {
-	*ControllerAPI
+	*ControllerAPIv5
 }
 
 // ControllerAPIv3 provides the v3 Controller API.
@@ -57,8 +71,8 @@ type ControllerAPIv3 struct {
 	*ControllerAPIv4
 }
 
-// NewControllerAPIv5 creates a new ControllerAPIv5.
-func NewControllerAPIv5(ctx facade.Context) (*ControllerAPI, error) {
+// NewControllerAPIv7 creates a new ControllerAPIv7.
+func NewControllerAPIv7(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -76,6 +90,24 @@ func NewControllerAPIv5(ctx facade.Context) (*ControllerAPI, error) {
 	)
 }
 
+// NewControllerAPIv6 creates a new ControllerAPIv6.
+func NewControllerAPIv6(ctx facade.Context) (*ControllerAPIv6, error) {
+	v7, err := NewControllerAPIv7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv6{v7}, nil
+}
+
+// NewControllerAPIv5 creates a new ControllerAPIv5.
+func NewControllerAPIv5(ctx facade.Context) (*ControllerAPIv5, error) {
+	v6, err := NewControllerAPIv6(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv5{v6}, nil
+}
+
 // NewControllerAPIv4 creates a new ControllerAPIv4.
 func NewControllerAPIv4(ctx facade.Context) (*ControllerAPIv4, error) {
 	v5, err := NewControllerAPIv5(ctx)
@@ -428,38 +460,107 @@ func (c *ControllerAPI) InitiateMigration(reqArgs params.InitiateMigrationArgs)
 }
 
 func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string, error) {
-	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	hostedState, targetInfo, err := c.migrationSpecDetails(spec)
 	if err != nil {
-		return "", errors.Annotate(err, "model tag")
+		return "", errors.Trace(err)
 	}
+	defer hostedState.Release()
 
-	// Ensure the model exists.
-	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
-		return "", errors.Annotate(err, "reading model")
-	} else if !modelExists {
-		return "", errors.NotFoundf("model")
+	// Check if the migration is likely to succeed.
+	if err := runMigrationPrechecks(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence); err != nil {
+		return "", errors.Trace(err)
 	}
 
-	hostedState, err := c.statePool.Get(modelTag.Id())
+	// Trigger the migration.
+	mig, err := hostedState.CreateMigration(state.MigrationSpec{
+		InitiatedBy: c.apiUser,
+		TargetInfo:  targetInfo,
+	})
 	if err != nil {
 		return "", errors.Trace(err)
 	}
+	return mig.Id(), nil
+}
+
+// PrecheckMigration runs all of the prechecks for the migration of
+// one or more models to other controllers, without starting the
+// migrations. Rather than failing at the first problem, every problem
+// found is reported. The model is also exported and validated by the
+// target controller, but not imported.
+func (c *ControllerAPI) PrecheckMigration(reqArgs params.InitiateMigrationArgs) (
+	params.MigrationPrecheckResults, error,
+) {
+	out := params.MigrationPrecheckResults{
+		Results: make([]params.MigrationPrecheckResult, len(reqArgs.Specs)),
+	}
+	if err := c.checkHasAdmin(); err != nil {
+		return out, errors.Trace(err)
+	}
+
+	for i, spec := range reqArgs.Specs {
+		result := &out.Results[i]
+		result.ModelTag = spec.ModelTag
+		report, err := c.precheckOneMigration(spec)
+		if err != nil {
+			result.Error = common.ServerError(err)
+			continue
+		}
+		for _, p := range report.Problems {
+			result.Problems = append(result.Problems, params.MigrationPrecheckProblem{
+				Scope:   string(p.Scope),
+				Entity:  p.Entity,
+				Message: p.Message,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (c *ControllerAPI) precheckOneMigration(spec params.MigrationSpec) (coremigration.PrecheckReport, error) {
+	hostedState, targetInfo, err := c.migrationSpecDetails(spec)
+	if err != nil {
+		return coremigration.PrecheckReport{}, errors.Trace(err)
+	}
 	defer hostedState.Release()
 
+	report, err := runMigrationPrecheckReport(
+		hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence)
+	return report, errors.Trace(err)
+}
+
+// migrationSpecDetails returns the state for the model to be migrated
+// and the target controller details from a migration spec. The
+// returned state must be released by the caller.
+func (c *ControllerAPI) migrationSpecDetails(spec params.MigrationSpec) (
+	*state.PooledState, coremigration.TargetInfo, error,
+) {
+	var empty coremigration.TargetInfo
+	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	if err != nil {
+		return nil, empty, errors.Annotate(err, "model tag")
+	}
+
+	// Ensure the model exists.
+	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
+		return nil, empty, errors.Annotate(err, "reading model")
+	} else if !modelExists {
+		return nil, empty, errors.NotFoundf("model")
+	}
+
 	// Construct target info.
 	specTarget := spec.TargetInfo
 	controllerTag, err := names.ParseControllerTag(specTarget.ControllerTag)
 	if err != nil {
-		return "", errors.Annotate(err, "controller tag")
+		return nil, empty, errors.Annotate(err, "controller tag")
 	}
 	authTag, err := names.ParseUserTag(specTarget.AuthTag)
 	if err != nil {
-		return "", errors.Annotate(err, "auth tag")
+		return nil, empty, errors.Annotate(err, "auth tag")
 	}
 	var macs []macaroon.Slice
 	if specTarget.Macaroons != "" {
 		if err := json.Unmarshal([]byte(specTarget.Macaroons), &macs); err != nil {
-			return "", errors.Annotate(err, "invalid macaroons")
+			return nil, empty, errors.Annotate(err, "invalid macaroons")
 		}
 	}
 	targetInfo := coremigration.TargetInfo{
@@ -471,20 +572,11 @@ func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string,
 		Macaroons:     macs,
 	}
 
-	// Check if the migration is likely to succeed.
-	if err := runMigrationPrechecks(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence); err != nil {
-		return "", errors.Trace(err)
-	}
-
-	// Trigger the migration.
-	mig, err := hostedState.CreateMigration(state.MigrationSpec{
-		InitiatedBy: c.apiUser,
-		TargetInfo:  targetInfo,
-	})
+	hostedState, err := c.statePool.Get(modelTag.Id())
 	if err != nil {
-		return "", errors.Trace(err)
+		return nil, empty, errors.Trace(err)
 	}
-	return mig.Id(), nil
+	return hostedState, targetInfo, nil
 }
 
 // ModifyControllerAccess changes the model access granted to users.
@@ -538,16 +630,7 @@ func (c *ControllerAPI) ConfigSet(args params.ControllerConfigSet) error {
 	// TODO(thumper): add a version to controller config to allow for
 	// simultaneous updates and races in publishing, potentially across
 	// HA servers.
-	cfg, err := c.state.ControllerConfig()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	if _, err := c.hub.Publish(
-		controller.ConfigChanged,
-		controller.ConfigChangedMessage{cfg}); err != nil {
-		return errors.Trace(err)
-	}
-	return nil
+	return errors.Trace(c.publishConfigChanged())
 }
 
 // Mask the ConfigSet method from the v4 API. The API reflection code
@@ -557,6 +640,21 @@ func (c *ControllerAPI) ConfigSet(args params.ControllerConfigSet) error {
 // ConfigSet isn't on the v4 API.
 func (c *ControllerAPIv4) ConfigSet(_, _ struct{}) {}
 
+// PrecheckMigration isn't on the v5 API.
+func (c *ControllerAPIv5) PrecheckMigration(_, _ struct{}) {}
+
+// RotateControllerCA isn't on the v6 API.
+func (c *ControllerAPIv6) RotateControllerCA(_, _ struct{}) {}
+
+// ControllerCARotationStatus isn't on the v6 API.
+func (c *ControllerAPIv6) ControllerCARotationStatus(_, _ struct{}) {}
+
+// IssueControllerCertsFromNewCA isn't on the v6 API.
+func (c *ControllerAPIv6) IssueControllerCertsFromNewCA(_, _ struct{}) {}
+
+// RetireOldControllerCA isn't on the v6 API.
+func (c *ControllerAPIv6) RetireOldControllerCA(_, _ struct{}) {}
+
 // runMigrationPrechecks runs prechecks on the migration and updates
 // information in targetInfo as needed based on information
 // retrieved from the target controller.
@@ -583,21 +681,119 @@ var runMigrationPrechecks = func(st, ctlrSt *state.State, targetInfo *coremigrat
 		return errors.Trace(err)
 	}
 	client := migrationtarget.NewClient(conn)
-	if targetInfo.CACert == "" {
-		targetInfo.CACert, err = client.CACert()
-		if err != nil {
-			if !params.IsCodeNotImplemented(err) {
-				return errors.Annotatef(err, "cannot retrieve CA certificate")
-			}
-			// If the call's not implemented, it indicates an earlier version
-			// of the controller, which we can't migrate to.
-			return errors.New("controller API version is too old")
-		}
+	if err := fillTargetCACert(client, targetInfo); err != nil {
+		return errors.Trace(err)
 	}
 	err = client.Prechecks(modelInfo)
 	return errors.Annotate(err, "target prechecks failed")
 }
 
+// runMigrationPrecheckReport runs all of the migration prechecks,
+// collecting every problem found. It also exports the model and asks
+// the target controller to validate the exported description.
+var runMigrationPrecheckReport = func(
+	st, ctlrSt *state.State,
+	targetInfo *coremigration.TargetInfo,
+	presence facade.Presence,
+) (coremigration.PrecheckReport, error) {
+	var report coremigration.PrecheckReport
+
+	// Check model and source controller.
+	backend, err := migration.PrecheckShim(st, ctlrSt)
+	if err != nil {
+		return report, errors.Annotate(err, "creating backend")
+	}
+	modelPresence := presence.ModelPresence(st.ModelUUID())
+	controllerPresence := presence.ModelPresence(ctlrSt.ModelUUID())
+	report, err = migration.SourcePrecheckReport(backend, modelPresence, controllerPresence)
+	if err != nil {
+		return report, errors.Annotate(err, "source prechecks")
+	}
+
+	// Check target controller.
+	conn, err := api.Open(targetToAPIInfo(targetInfo), migration.ControllerDialOpts())
+	if err != nil {
+		return report, errors.Annotate(err, "connect to target controller")
+	}
+	defer conn.Close()
+	modelInfo, err := makeModelInfo(st, ctlrSt)
+	if err != nil {
+		return report, errors.Trace(err)
+	}
+	client := migrationtarget.NewClient(conn)
+	if err := fillTargetCACert(client, targetInfo); err != nil {
+		return report, errors.Trace(err)
+	}
+	targetReport, err := client.PrecheckReport(modelInfo)
+	if err != nil {
+		return report, errors.Annotate(err, "target prechecks")
+	}
+	report.Merge(targetReport)
+
+	// Check that the target controller would accept the model
+	// description, without importing it.
+	bytes, err := migration.ExportModel(st)
+	if err != nil {
+		return report, errors.Annotate(err, "exporting model")
+	}
+	modelTag := names.NewModelTag(st.ModelUUID())
+	if err := addImportValidation(&report, client, modelTag, bytes); err != nil {
+		return report, errors.Trace(err)
+	}
+	return report, nil
+}
+
+// importValidator is the part of the migration target client used to
+// validate an exported model.
+type importValidator interface {
+	ValidateImport(bytes []byte) error
+}
+
+// addImportValidation asks the target controller to validate the
+// exported model, recording a rejection as a problem in the report.
+// Failures to get an answer from the target are returned.
+func addImportValidation(
+	report *coremigration.PrecheckReport,
+	client importValidator,
+	modelTag names.ModelTag,
+	bytes []byte,
+) error {
+	err := client.ValidateImport(bytes)
+	if err == nil {
+		return nil
+	}
+	// A rejection by the target arrives as an error returned by the
+	// remote call; anything else means the call itself failed.
+	if _, ok := errors.Cause(err).(*rpc.RequestError); !ok {
+		return errors.Annotate(err, "validating model import")
+	}
+	report.Add(
+		coremigration.PrecheckScopeTargetController,
+		modelTag.String(),
+		"model import validation failed: "+err.Error(),
+	)
+	return nil
+}
+
+// fillTargetCACert retrieves the target controller's CA certificate
+// if it isn't already known.
+func fillTargetCACert(client *migrationtarget.Client, targetInfo *coremigration.TargetInfo) error {
+	if targetInfo.CACert != "" {
+		return nil
+	}
+	var err error
+	targetInfo.CACert, err = client.CACert()
+	if err != nil {
+		if !params.IsCodeNotImplemented(err) {
+			return errors.Annotatef(err, "cannot retrieve CA certificate")
+		}
+		// If the call's not implemented, it indicates an earlier version
+		// of the controller, which we can't migrate to.
+		return errors.New("controller API version is too old")
+	}
+	return nil
+}
+
 func makeModelInfo(st, ctlrSt *state.State) (coremigration.ModelInfo, error) {
 	var empty coremigration.ModelInfo
 
@@ -17,18 +17,23 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v2-unstable"
 
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/migrationtarget"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade/facadetest"
 	"github.com/juju/juju/apiserver/facades/client/controller"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujucert "github.com/juju/juju/cert"
 	"github.com/juju/juju/cloud"
 	corecontroller "github.com/juju/juju/controller"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/permission"
 	pscontroller "github.com/juju/juju/pubsub/controller"
+	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	statetesting "github.com/juju/juju/state/testing"
@@ -64,7 +69,7 @@ func (s *controllerSuite) SetUpTest(c *gc.C) {
 	}
 	s.hub = pubsub.NewStructuredHub(nil)
 
-	controller, err := controller.NewControllerAPIv5(
+	controller, err := controller.NewControllerAPIv7(
 		facadetest.Context{
 			State_:     s.State,
 			StatePool_: s.StatePool,
@@ -570,6 +575,124 @@ func (s *controllerSuite) TestInitiateMigrationPrecheckFail(c *gc.C) {
 	c.Check(active, jc.IsFalse)
 }
 
+func (s *controllerSuite) TestPrecheckMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var report coremigration.PrecheckReport
+	report.Add(coremigration.PrecheckScopeModel, "machine-0", "machine 0 is dying")
+	report.Add(coremigration.PrecheckScopeTargetController, m.ModelTag().String(), "model named \"foo\" already exists")
+	controller.SetPrecheckReportResult(s, report, nil)
+
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: m.ModelTag().String(),
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag: randomControllerTag(),
+				Addrs:         []string{"1.1.1.1:1111"},
+				CACert:        "cert1",
+				AuthTag:       names.NewUserTag("admin1").String(),
+				Password:      "secret1",
+			},
+		}},
+	}
+	out, err := s.controller.PrecheckMigration(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, params.MigrationPrecheckResults{
+		Results: []params.MigrationPrecheckResult{{
+			ModelTag: m.ModelTag().String(),
+			Problems: []params.MigrationPrecheckProblem{{
+				Scope:   "model",
+				Entity:  "machine-0",
+				Message: "machine 0 is dying",
+			}, {
+				Scope:   "target-controller",
+				Entity:  m.ModelTag().String(),
+				Message: "model named \"foo\" already exists",
+			}},
+		}},
+	})
+
+	// No migration should have been started.
+	active, err := st.IsMigrationActive()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+}
+
+func (s *controllerSuite) importValidationClient(err error) *migrationtarget.Client {
+	return migrationtarget.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			if request != "ValidateImport" {
+				return errors.Errorf("unexpected call to %s", request)
+			}
+			return err
+		},
+		BestVersion: 2,
+	})
+}
+
+func (s *controllerSuite) TestImportValidationRejected(c *gc.C) {
+	modelTag := names.NewModelTag(utils.MustNewUUID().String())
+	client := s.importValidationClient(errors.Trace(&rpc.RequestError{
+		Message: `unknown storage provider "foo"`,
+	}))
+	var report coremigration.PrecheckReport
+	err := controller.AddImportValidation(&report, client, modelTag, []byte("model"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Problems, jc.DeepEquals, []coremigration.PrecheckProblem{{
+		Scope:   coremigration.PrecheckScopeTargetController,
+		Entity:  modelTag.String(),
+		Message: `model import validation failed: unknown storage provider "foo"`,
+	}})
+}
+
+func (s *controllerSuite) TestImportValidationAccepted(c *gc.C) {
+	modelTag := names.NewModelTag(utils.MustNewUUID().String())
+	var report coremigration.PrecheckReport
+	err := controller.AddImportValidation(&report, s.importValidationClient(nil), modelTag, []byte("model"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Empty(), jc.IsTrue)
+}
+
+func (s *controllerSuite) TestImportValidationCallFailed(c *gc.C) {
+	modelTag := names.NewModelTag(utils.MustNewUUID().String())
+	client := s.importValidationClient(errors.New("connection is shut down"))
+	var report coremigration.PrecheckReport
+	err := controller.AddImportValidation(&report, client, modelTag, []byte("model"))
+	c.Assert(err, gc.ErrorMatches, "validating model import: connection is shut down")
+	c.Assert(report.Empty(), jc.IsTrue)
+}
+
+func (s *controllerSuite) TestPrecheckMigrationError(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	controller.SetPrecheckReportResult(s, coremigration.PrecheckReport{}, errors.New("boom"))
+
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: m.ModelTag().String(),
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag: randomControllerTag(),
+				Addrs:         []string{"1.1.1.1:1111"},
+				AuthTag:       names.NewUserTag("admin1").String(),
+				Password:      "secret1",
+			},
+		}, {
+			ModelTag: randomModelTag(),
+		}},
+	}
+	out, err := s.controller.PrecheckMigration(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 2)
+	c.Check(out.Results[0].Error, gc.ErrorMatches, "boom")
+	c.Check(out.Results[1].Error, gc.ErrorMatches, "model not found")
+}
+
 func randomControllerTag() string {
 	uuid := utils.MustNewUUID().String()
 	return names.NewControllerTag(uuid).String()
@@ -968,3 +1091,72 @@ func (s *controllerSuite) TestConfigSetPublishesEvent(c *gc.C) {
 
 	c.Assert(config.Features().SortedValues(), jc.DeepEquals, []string{"bar", "foo"})
 }
+
+func (s *controllerSuite) TestRotateControllerCA(c *gc.C) {
+	err := s.State.SetStateServingInfo(state.StateServingInfo{
+		APIPort:      1234,
+		StatePort:    4321,
+		Cert:         testing.ServerCert,
+		PrivateKey:   testing.ServerKey,
+		CAPrivateKey: testing.CAKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.Factory.MakeMachine(c, nil)
+
+	status, err := s.controller.ControllerCARotationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, params.ControllerCARotationStatus{CACert: testing.CACert})
+
+	caasSt := s.Factory.MakeCAASModel(c, nil)
+	defer caasSt.Close()
+	f := factory.NewFactory(caasSt)
+	ch := f.MakeCharm(c, &factory.CharmParams{Series: "kubernetes"})
+	app := f.MakeApplication(c, &factory.ApplicationParams{Charm: ch})
+
+	status, err = s.controller.RotateControllerCA()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Phase, gc.Equals, "trust")
+	c.Assert(status.NewCACert, gc.Not(gc.Equals), "")
+	bundle, err := jujucert.JoinCertBundle(testing.CACert, status.NewCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.CACert, gc.Equals, bundle)
+	c.Assert(status.UntrustingAgents, jc.SameContents, []string{
+		s.State.ModelUUID() + ":" + m.Tag().String(),
+		caasSt.ModelUUID() + ":" + app.Tag().String(),
+	})
+
+	err = s.controller.IssueControllerCertsFromNewCA()
+	c.Assert(err, gc.ErrorMatches, `cannot issue certificates from the new controller CA: 2 agent\(s\) do not trust the new CA yet`)
+
+	err = s.State.SetControllerCATrusted(m.Tag(), bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	err = caasSt.SetControllerCATrusted(app.Tag(), bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.controller.IssueControllerCertsFromNewCA()
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err = s.controller.ControllerCARotationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Phase, gc.Equals, "issue")
+	c.Assert(status.UntrustingAgents, gc.HasLen, 0)
+}
+
+func (s *controllerSuite) TestRotateControllerCARequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Access: permission.ReadAccess,
+	})
+	endpoint, err := controller.NewControllerAPIv7(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      apiservertesting.FakeAuthorizer{Tag: user.Tag()},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.RotateControllerCA()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = endpoint.ControllerCARotationStatus()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = endpoint.RetireOldControllerCA()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/pubsub/controller"
+	"github.com/juju/juju/state"
+)
+
+// RotateControllerCA generates a new controller CA and starts trusting
+// it alongside the current one. Server certificates continue to be
+// issued by the current CA until IssueControllerCertsFromNewCA is called.
+func (c *ControllerAPI) RotateControllerCA() (params.ControllerCARotationStatus, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.ControllerCARotationStatus{}, errors.Trace(err)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return params.ControllerCARotationStatus{}, errors.Annotate(err, "generating UUID for CA certificate")
+	}
+	expiry := time.Now().UTC().AddDate(10, 0, 0)
+	caCert, caKey, err := cert.NewCA("juju-ca", uuid.String(), expiry)
+	if err != nil {
+		return params.ControllerCARotationStatus{}, errors.Trace(err)
+	}
+	if err := c.state.StartControllerCARotation(caCert, caKey); err != nil {
+		return params.ControllerCARotationStatus{}, errors.Trace(err)
+	}
+	if err := c.publishConfigChanged(); err != nil {
+		return params.ControllerCARotationStatus{}, errors.Trace(err)
+	}
+	return c.controllerCARotationStatus()
+}
+
+// ControllerCARotationStatus reports the progress of the controller CA
+// rotation in progress, if any.
+func (c *ControllerAPI) ControllerCARotationStatus() (params.ControllerCARotationStatus, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.ControllerCARotationStatus{}, errors.Trace(err)
+	}
+	return c.controllerCARotationStatus()
+}
+
+// IssueControllerCertsFromNewCA switches the controllers to issuing
+// their server certificates from the new CA. Every agent must trust
+// the new CA first.
+func (c *ControllerAPI) IssueControllerCertsFromNewCA() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.state.IssueControllerCertsFromNewCA(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.publishConfigChanged())
+}
+
+// RetireOldControllerCA stops trusting the old controller CA, completing
+// the rotation. Every controller must be serving a certificate issued by
+// the new CA first.
+func (c *ControllerAPI) RetireOldControllerCA() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.state.RetireOldControllerCA(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.publishConfigChanged())
+}
+
+func (c *ControllerAPI) controllerCARotationStatus() (params.ControllerCARotationStatus, error) {
+	cfg, err := c.state.ControllerConfig()
+	if err != nil {
+		return params.ControllerCARotationStatus{}, errors.Trace(err)
+	}
+	caCert, _ := cfg.CACert()
+	status := params.ControllerCARotationStatus{CACert: caCert}
+
+	rotation, err := c.state.ControllerCARotation()
+	if errors.IsNotFound(err) {
+		return status, nil
+	} else if err != nil {
+		return params.ControllerCARotationStatus{}, errors.Trace(err)
+	}
+	status.Phase = string(rotation.Phase)
+	status.NewCACert = rotation.NewCACert
+
+	switch rotation.Phase {
+	case state.CARotationTrust:
+		status.UntrustingAgents, err = c.state.ControllerCAUntrustingAgents()
+		if err != nil {
+			return params.ControllerCARotationStatus{}, errors.Trace(err)
+		}
+	case state.CARotationIssue:
+		info, err := c.state.ControllerInfo()
+		if err != nil {
+			return params.ControllerCARotationStatus{}, errors.Trace(err)
+		}
+		pending := set.NewStrings(info.MachineIds...).Difference(set.NewStrings(rotation.Issued...))
+		status.PendingControllers = pending.SortedValues()
+	}
+	return status, nil
+}
+
+// publishConfigChanged lets the controllers know that the controller
+// config, including the trusted CA certificates, has changed.
+func (c *ControllerAPI) publishConfigChanged() error {
+	cfg, err := c.state.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := c.hub.Publish(
+		controller.ConfigChanged,
+		controller.ConfigChangedMessage{cfg}); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -18,3 +18,13 @@ func SetPrecheckResult(p patcher, err error) {
 		return err
 	})
 }
+
+func SetPrecheckReportResult(p patcher, report migration.PrecheckReport, err error) {
+	p.PatchValue(&runMigrationPrecheckReport, func(
+		*state.State, *state.State, *migration.TargetInfo, facade.Presence,
+	) (migration.PrecheckReport, error) {
+		return report, err
+	})
+}
+
+var AddImportValidation = addImportValidation
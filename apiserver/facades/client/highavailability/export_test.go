@@ -0,0 +1,16 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability
+
+import (
+	"github.com/juju/testing"
+
+	"github.com/juju/juju/state"
+)
+
+func PatchMongoSession(p testing.Patcher, session MongoSession) {
+	p.PatchValue(&newMongoSession, func(*state.State) MongoSession {
+		return session
+	})
+}
@@ -30,6 +30,9 @@ var logger = loggo.GetLogger("juju.apiserver.highavailability")
 // HighAvailability defines the methods on the highavailability API end point.
 type HighAvailability interface {
 	EnableHA(args params.ControllersSpecs) (params.ControllersChangeResults, error)
+	ControllerMembers() (params.ControllerMembersResult, error)
+	RemoveControllerMember(args params.Entities) (params.ErrorResults, error)
+	StepDown() error
 }
 
 // HighAvailabilityAPI implements the HighAvailability interface and is the concrete
@@ -38,10 +41,39 @@ type HighAvailabilityAPI struct {
 	state      *state.State
 	resources  facade.Resources
 	authorizer facade.Authorizer
+	raftLeader func() string
+}
+
+// HighAvailabilityAPIV2 implements version 2 of the highavailability
+// facade, which has no controller member management.
+type HighAvailabilityAPIV2 struct {
+	*HighAvailabilityAPI
 }
 
 var _ HighAvailability = (*HighAvailabilityAPI)(nil)
 
+// NewHighAvailabilityAPIV2 creates a new server-side highavailability API
+// end point, version 2.
+func NewHighAvailabilityAPIV2(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*HighAvailabilityAPIV2, error) {
+	api, err := NewHighAvailabilityAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &HighAvailabilityAPIV2{api}, nil
+}
+
+// NewFacade creates a new server-side highavailability API end point,
+// which reports the raft role of each controller from the facade
+// context.
+func NewFacade(ctx facade.Context) (*HighAvailabilityAPI, error) {
+	api, err := NewHighAvailabilityAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	api.raftLeader = ctx.RaftLeader
+	return api, nil
+}
+
 // NewHighAvailabilityAPI creates a new server-side highavailability API end point.
 func NewHighAvailabilityAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*HighAvailabilityAPI, error) {
 	// Only clients can access the high availability facade.
@@ -52,6 +84,7 @@ func NewHighAvailabilityAPI(st *state.State, resources facade.Resources, authori
 		state:      st,
 		resources:  resources,
 		authorizer: authorizer,
+		raftLeader: func() string { return "" },
 	}, nil
 }
 
@@ -317,3 +350,12 @@ func (api *HighAvailabilityAPI) StopHAReplicationForUpgrade(args params.UpgradeM
 func (api *HighAvailabilityAPI) ResumeHAReplicationAfterUpgrade(args params.ResumeReplicationParams) error {
 	return api.state.ResumeReplication(args.Members)
 }
+
+// ControllerMembers isn't on the V2 API.
+func (api *HighAvailabilityAPIV2) ControllerMembers(_, _ struct{}) {}
+
+// RemoveControllerMember isn't on the V2 API.
+func (api *HighAvailabilityAPIV2) RemoveControllerMember(_, _ struct{}) {}
+
+// StepDown isn't on the V2 API.
+func (api *HighAvailabilityAPIV2) StepDown(_, _ struct{}) {}
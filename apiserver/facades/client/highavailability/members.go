@@ -0,0 +1,312 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// jujuMachineKey is the replica-set member tag used by the peergrouper
+// to record which machine a mongo member belongs to.
+const jujuMachineKey = "juju-machine-id"
+
+// MongoSession exposes the replica-set operations needed to report on
+// and manage controller members.
+type MongoSession interface {
+	CurrentStatus() (*replicaset.Status, error)
+	CurrentMembers() ([]replicaset.Member, error)
+	MemberOptimes() (map[int]time.Time, error)
+	StepDownPrimary() error
+	Close()
+}
+
+// newMongoSession is patched out in tests.
+var newMongoSession = func(st *state.State) MongoSession {
+	return mongoSessionShim{st.MongoSession().Copy()}
+}
+
+type mongoSessionShim struct {
+	session *mgo.Session
+}
+
+func (s mongoSessionShim) CurrentStatus() (*replicaset.Status, error) {
+	return replicaset.CurrentStatus(s.session)
+}
+
+func (s mongoSessionShim) CurrentMembers() ([]replicaset.Member, error) {
+	return replicaset.CurrentMembers(s.session)
+}
+
+// MemberOptimes returns the time of the last operation applied by each
+// replica-set member, keyed on member id.
+func (s mongoSessionShim) MemberOptimes() (map[int]time.Time, error) {
+	var status struct {
+		Members []struct {
+			Id         int       `bson:"_id"`
+			OptimeDate time.Time `bson:"optimeDate"`
+		} `bson:"members"`
+	}
+	if err := s.session.Run("replSetGetStatus", &status); err != nil {
+		return nil, errors.Trace(err)
+	}
+	optimes := make(map[int]time.Time)
+	for _, m := range status.Members {
+		optimes[m.Id] = m.OptimeDate
+	}
+	return optimes, nil
+}
+
+func (s mongoSessionShim) StepDownPrimary() error {
+	return replicaset.StepDownPrimary(s.session)
+}
+
+func (s mongoSessionShim) Close() {
+	s.session.Close()
+}
+
+func (api *HighAvailabilityAPI) checkIsSuperuser() error {
+	admin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if !admin {
+		return common.ServerError(common.ErrPerm)
+	}
+	return nil
+}
+
+// memberStatus holds the replica-set view of a single controller machine.
+type memberStatus struct {
+	state   string
+	healthy bool
+	lag     time.Duration
+}
+
+// replicaSetStatus returns the replica-set status of each controller
+// machine, keyed on machine id.
+func replicaSetStatus(session MongoSession) (map[string]memberStatus, error) {
+	members, err := session.CurrentMembers()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get replica-set members")
+	}
+	status, err := session.CurrentStatus()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get replica-set status")
+	}
+	optimes, err := session.MemberOptimes()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get replica-set optimes")
+	}
+
+	var primaryOptime time.Time
+	statusById := make(map[int]replicaset.MemberStatus)
+	for _, s := range status.Members {
+		statusById[s.Id] = s
+		if s.State == replicaset.PrimaryState {
+			primaryOptime = optimes[s.Id]
+		}
+	}
+
+	result := make(map[string]memberStatus)
+	for _, m := range members {
+		machineId, ok := m.Tags[jujuMachineKey]
+		if !ok {
+			continue
+		}
+		s, ok := statusById[m.Id]
+		if !ok {
+			continue
+		}
+		var lag time.Duration
+		if optime, ok := optimes[m.Id]; ok && !primaryOptime.IsZero() && primaryOptime.After(optime) {
+			lag = primaryOptime.Sub(optime)
+		}
+		result[machineId] = memberStatus{
+			state:   s.State.String(),
+			healthy: s.Healthy,
+			lag:     lag,
+		}
+	}
+	return result, nil
+}
+
+// ControllerMembers reports the voting, replica-set and raft status of
+// each controller machine.
+func (api *HighAvailabilityAPI) ControllerMembers() (params.ControllerMembersResult, error) {
+	var result params.ControllerMembersResult
+	if err := api.checkIsSuperuser(); err != nil {
+		return result, errors.Trace(err)
+	}
+	cInfo, err := api.state.ControllerInfo()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	cfg, err := api.state.ControllerConfig()
+	if err != nil {
+		return result, errors.Annotate(err, "retrieving controller config")
+	}
+
+	session := newMongoSession(api.state)
+	defer session.Close()
+	rsStatus, err := replicaSetStatus(session)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	raftLeader := api.raftLeader()
+	result.Members = make([]params.ControllerMember, 0, len(cInfo.MachineIds))
+	for _, id := range cInfo.MachineIds {
+		m, err := api.state.Machine(id)
+		if err != nil {
+			return result, errors.Annotatef(err, "reading controller id %v", id)
+		}
+		member := params.ControllerMember{
+			Tag:       m.Tag().String(),
+			WantsVote: m.WantsVote(),
+			HasVote:   m.HasVote(),
+		}
+		if addr, ok := network.SelectInternalAddress(m.Addresses(), false); ok {
+			member.APIAddress = net.JoinHostPort(addr.Value, strconv.Itoa(cfg.APIPort()))
+		}
+		if s, ok := rsStatus[id]; ok {
+			member.MongoState = s.state
+			member.Healthy = s.healthy
+			member.OptimeLag = s.lag.Seconds()
+		}
+		switch {
+		case raftLeader == "":
+		case raftLeader == id:
+			member.RaftRole = "leader"
+		default:
+			member.RaftRole = "follower"
+		}
+		result.Members = append(result.Members, member)
+	}
+	return result, nil
+}
+
+// RemoveControllerMember removes the specified machines from the set of
+// controllers. A machine is only removed when the healthy voting members
+// left behind are a majority of the current voting members, counting
+// those already being removed, including earlier in the same call,
+// which keep their vote until the peergrouper takes it away. The
+// peergrouper takes care of removing the vote and the replica-set member
+// once the machine is dying.
+func (api *HighAvailabilityAPI) RemoveControllerMember(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := api.checkIsSuperuser(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := common.NewBlockChecker(api.state).RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if len(args.Entities) == 0 {
+		return results, nil
+	}
+
+	session := newMongoSession(api.state)
+	defer session.Close()
+	rsStatus, err := replicaSetStatus(session)
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	removed := set.NewStrings()
+	for i, entity := range args.Entities {
+		err := api.removeControllerMember(entity.Tag, rsStatus, removed)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// removeControllerMember destroys the controller machine with the given
+// tag, and records it in removed, if the healthy voting members that are
+// not being removed remain a majority of the voting members.
+func (api *HighAvailabilityAPI) removeControllerMember(
+	tagString string, rsStatus map[string]memberStatus, removed set.Strings,
+) error {
+	tag, err := names.ParseMachineTag(tagString)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m, err := api.state.Machine(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !m.IsManager() {
+		return errors.Errorf("machine %s is not a controller", tag.Id())
+	}
+	cInfo, err := api.state.ControllerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(cInfo.MachineIds) <= 1 {
+		return errors.Errorf("cannot remove machine %s: it is the only controller", tag.Id())
+	}
+
+	// Ensure that the voters left behind can still elect a primary.
+	// Members being removed keep their vote until the peergrouper
+	// removes it, so they count towards the majority required, but
+	// can't be relied on to make it up.
+	var voters, healthy int
+	for _, id := range cInfo.MachineIds {
+		other, err := api.state.Machine(id)
+		if err != nil {
+			return errors.Annotatef(err, "reading controller id %v", id)
+		}
+		if !other.HasVote() {
+			continue
+		}
+		voters++
+		if id == tag.Id() || removed.Contains(id) || other.Life() != state.Alive {
+			continue
+		}
+		if rsStatus[id].healthy {
+			healthy++
+		}
+	}
+	if healthy*2 <= voters {
+		return errors.Errorf(
+			"cannot remove machine %s: %d of the %d voting members would remain healthy, a majority is required",
+			tag.Id(), healthy, voters,
+		)
+	}
+	if err := m.Destroy(); err != nil {
+		return errors.Trace(err)
+	}
+	removed.Add(tag.Id())
+	return nil
+}
+
+// StepDown asks the current mongo primary to step down, causing a new
+// primary to be elected from the remaining voting members.
+func (api *HighAvailabilityAPI) StepDown() error {
+	if err := api.checkIsSuperuser(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := common.NewBlockChecker(api.state).ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	session := newMongoSession(api.state)
+	defer session.Close()
+	if err := session.StepDownPrimary(); err != nil {
+		return errors.Annotate(err, "cannot step down primary")
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	"github.com/juju/juju/apiserver/facades/client/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type fakeMongoSession struct {
+	members  []replicaset.Member
+	status   *replicaset.Status
+	optimes  map[int]time.Time
+	stepDown error
+
+	steppedDown bool
+}
+
+func (s *fakeMongoSession) CurrentStatus() (*replicaset.Status, error) {
+	return s.status, nil
+}
+
+func (s *fakeMongoSession) CurrentMembers() ([]replicaset.Member, error) {
+	return s.members, nil
+}
+
+func (s *fakeMongoSession) MemberOptimes() (map[int]time.Time, error) {
+	return s.optimes, nil
+}
+
+func (s *fakeMongoSession) StepDownPrimary() error {
+	s.steppedDown = true
+	return s.stepDown
+}
+
+func (s *fakeMongoSession) Close() {}
+
+// newFakeSession returns a session describing a replica-set with one
+// member per machine id, where machine "0" is primary. Members listed
+// in unhealthy are reported as unreachable.
+func newFakeSession(ids []string, unhealthy ...string) *fakeMongoSession {
+	now := time.Now()
+	session := &fakeMongoSession{
+		status:  &replicaset.Status{},
+		optimes: make(map[int]time.Time),
+	}
+	bad := make(map[string]bool)
+	for _, id := range unhealthy {
+		bad[id] = true
+	}
+	for i, id := range ids {
+		session.members = append(session.members, replicaset.Member{
+			Id:   i + 1,
+			Tags: map[string]string{"juju-machine-id": id},
+		})
+		memberState := replicaset.SecondaryState
+		if i == 0 {
+			memberState = replicaset.PrimaryState
+		}
+		if bad[id] {
+			memberState = replicaset.DownState
+		}
+		session.status.Members = append(session.status.Members, replicaset.MemberStatus{
+			Id:      i + 1,
+			Healthy: !bad[id],
+			State:   memberState,
+		})
+		session.optimes[i+1] = now.Add(-time.Duration(i) * time.Second)
+	}
+	return session
+}
+
+func (s *clientSuite) setUpThreeVoters(c *gc.C) {
+	_, err := s.enableHA(c, 3, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, id := range []string{"0", "1", "2"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(m.SetHasVote(true), jc.ErrorIsNil)
+	}
+}
+
+func (s *clientSuite) TestControllerMembers(c *gc.C) {
+	s.setUpThreeVoters(c)
+	s.setMachineAddresses(c, "1")
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0", "1", "2"}, "2"))
+
+	result, err := s.haServer.ControllerMembers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Members, gc.HasLen, 3)
+
+	c.Check(result.Members[0].Tag, gc.Equals, "machine-0")
+	c.Check(result.Members[0].HasVote, jc.IsTrue)
+	c.Check(result.Members[0].WantsVote, jc.IsTrue)
+	c.Check(result.Members[0].MongoState, gc.Equals, "PRIMARY")
+	c.Check(result.Members[0].Healthy, jc.IsTrue)
+	c.Check(result.Members[0].OptimeLag, gc.Equals, float64(0))
+	c.Check(result.Members[0].APIAddress, gc.Matches, `cloud-local0\.internal:\d+`)
+
+	c.Check(result.Members[1].Tag, gc.Equals, "machine-1")
+	c.Check(result.Members[1].MongoState, gc.Equals, "SECONDARY")
+	c.Check(result.Members[1].OptimeLag, gc.Equals, float64(1))
+	c.Check(result.Members[1].APIAddress, gc.Matches, `cloud-local1\.internal:\d+`)
+
+	c.Check(result.Members[2].Tag, gc.Equals, "machine-2")
+	c.Check(result.Members[2].MongoState, gc.Equals, "DOWN")
+	c.Check(result.Members[2].Healthy, jc.IsFalse)
+	c.Check(result.Members[2].APIAddress, gc.Equals, "")
+}
+
+func (s *clientSuite) TestControllerMembersRaftRole(c *gc.C) {
+	s.setUpThreeVoters(c)
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0", "1", "2"}))
+	api, err := highavailability.NewFacade(facadetest.Context{
+		State_:      s.State,
+		Resources_:  s.resources,
+		Auth_:       s.authoriser,
+		RaftLeader_: "1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := api.ControllerMembers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Members, gc.HasLen, 3)
+	c.Check(result.Members[0].RaftRole, gc.Equals, "follower")
+	c.Check(result.Members[1].RaftRole, gc.Equals, "leader")
+	c.Check(result.Members[2].RaftRole, gc.Equals, "follower")
+}
+
+func (s *clientSuite) TestControllerMembersRaftLeaderUnknown(c *gc.C) {
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0"}))
+
+	result, err := s.haServer.ControllerMembers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Members, gc.HasLen, 1)
+	c.Check(result.Members[0].RaftRole, gc.Equals, "")
+}
+
+func (s *clientSuite) TestControllerMembersPermission(c *gc.C) {
+	s.authoriser.Tag = s.Factory.MakeUser(c, nil).Tag()
+	api, err := highavailability.NewHighAvailabilityAPI(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.ControllerMembers()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *clientSuite) TestRemoveControllerMember(c *gc.C) {
+	s.setUpThreeVoters(c)
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0", "1", "2"}))
+
+	results, err := s.haServer.RemoveControllerMember(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+
+	m, err := s.State.Machine("2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Life(), gc.Equals, state.Dying)
+	c.Assert(m.WantsVote(), jc.IsFalse)
+}
+
+func (s *clientSuite) TestRemoveControllerMemberLosesQuorum(c *gc.C) {
+	s.setUpThreeVoters(c)
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0", "1", "2"}, "1"))
+
+	results, err := s.haServer.RemoveControllerMember(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		"cannot remove machine 2: 1 of the 3 voting members would remain healthy, a majority is required")
+
+	m, err := s.State.Machine("2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Life(), gc.Equals, state.Alive)
+}
+
+func (s *clientSuite) TestRemoveControllerMemberSeveralLosesQuorum(c *gc.C) {
+	s.setUpThreeVoters(c)
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0", "1", "2"}))
+
+	// Removing either machine alone is fine, but not both: the one
+	// removed first still votes until the peergrouper removes it.
+	results, err := s.haServer.RemoveControllerMember(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}, {Tag: "machine-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches,
+		"cannot remove machine 2: 1 of the 3 voting members would remain healthy, a majority is required")
+
+	m, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Life(), gc.Equals, state.Dying)
+	m, err = s.State.Machine("2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Life(), gc.Equals, state.Alive)
+
+	// A later call still counts the dying machine's vote.
+	results, err = s.haServer.RemoveControllerMember(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		"cannot remove machine 2: 1 of the 3 voting members would remain healthy, a majority is required")
+}
+
+func (s *clientSuite) TestRemoveControllerMemberLastController(c *gc.C) {
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0"}))
+
+	results, err := s.haServer.RemoveControllerMember(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "cannot remove machine 0: it is the only controller")
+}
+
+func (s *clientSuite) TestRemoveControllerMemberNotController(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0"}))
+
+	results, err := s.haServer.RemoveControllerMember(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}, {Tag: "unit-foo-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "machine 1 is not a controller")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `"unit-foo-0" is not a valid machine tag`)
+}
+
+func (s *clientSuite) TestBlockRemoveControllerMember(c *gc.C) {
+	s.setUpThreeVoters(c)
+	highavailability.PatchMongoSession(s, newFakeSession([]string{"0", "1", "2"}))
+	s.BlockRemoveObject(c, "TestBlockRemoveControllerMember")
+
+	_, err := s.haServer.RemoveControllerMember(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-2"}},
+	})
+	s.AssertBlocked(c, err, "TestBlockRemoveControllerMember")
+}
+
+func (s *clientSuite) TestStepDown(c *gc.C) {
+	session := newFakeSession([]string{"0"})
+	highavailability.PatchMongoSession(s, session)
+
+	err := s.haServer.StepDown()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.steppedDown, jc.IsTrue)
+}
+
+func (s *clientSuite) TestStepDownError(c *gc.C) {
+	session := newFakeSession([]string{"0"})
+	session.stepDown = errors.New("boom")
+	highavailability.PatchMongoSession(s, session)
+
+	err := s.haServer.StepDown()
+	c.Assert(err, gc.ErrorMatches, "cannot step down primary: boom")
+}
+
+func (s *clientSuite) TestStepDownPermission(c *gc.C) {
+	s.authoriser.Tag = s.Factory.MakeUser(c, nil).Tag()
+	api, err := highavailability.NewHighAvailabilityAPI(s.State, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	err = api.StepDown()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -79,7 +79,7 @@ func (api *KeyManagerAPI) checkCanRead(sshUser string) error {
 		return common.ErrPerm
 	}
 	ok, err := common.HasPermission(
-		api.state.UserPermission,
+		common.EffectiveUserAccess(api.state),
 		api.apiUser,
 		permission.ReadAccess,
 		api.model.ModelTag(),
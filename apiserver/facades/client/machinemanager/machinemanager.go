@@ -59,9 +59,20 @@ type MachineManagerAPIV4 struct {
 
 // Version 5 of Machine Manger API. Adds CreateUpgradeSeriesLock.
 type MachineManagerAPIV5 struct {
+	*MachineManagerAPIV6
+}
+
+// ReplaceMachines isn't on the v5 API.
+func (*MachineManagerAPIV5) ReplaceMachines(_, _ struct{}) {}
+
+// Version 6 of Machine Manager API. Adds ReplaceMachines.
+type MachineManagerAPIV6 struct {
 	*MachineManagerAPI
 }
 
+// SetMachineMaintenance isn't on the v6 API.
+func (*MachineManagerAPIV6) SetMachineMaintenance(_, _ struct{}) {}
+
 // NewFacadeV4 creates a new server-side MachineManager API facade.
 func NewFacadeV4(ctx facade.Context) (*MachineManagerAPIV4, error) {
 	machineManagerAPIV5, err := NewFacadeV5(ctx)
@@ -72,11 +83,19 @@ func NewFacadeV4(ctx facade.Context) (*MachineManagerAPIV4, error) {
 }
 
 func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
+	machineManagerAPIV6, err := NewFacadeV6(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV5{machineManagerAPIV6}, nil
+}
+
+func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
 	machineManagerAPI, err := NewFacade(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &MachineManagerAPIV5{machineManagerAPI}, nil
+	return &MachineManagerAPIV6{machineManagerAPI}, nil
 }
 
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
@@ -113,6 +132,85 @@ func (mm *MachineManagerAPI) checkCanWrite() error {
 	return nil
 }
 
+// ReplaceMachines replaces the instances of the specified machines,
+// keeping the machines' IDs, units and storage.
+func (mm *MachineManagerAPI) ReplaceMachines(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := mm.checkCanWrite(); err != nil {
+		return results, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		err := mm.replaceOneMachine(entity.Tag)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) replaceOneMachine(tag string) error {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machine, err := mm.st.Machine(machineTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return machine.ReplaceInstance()
+}
+
+// SetMachineMaintenance puts the specified machines into maintenance,
+// recording the supplied progress message and replacement units, or
+// takes them out of it when the message is empty. No units are assigned
+// to a machine, or to its containers, while it is in maintenance. The
+// replacement units recorded for each machine in maintenance are
+// returned.
+func (mm *MachineManagerAPI) SetMachineMaintenance(args params.MachineMaintenanceArgs) (params.MachineMaintenanceResults, error) {
+	results := params.MachineMaintenanceResults{
+		Results: make([]params.MachineMaintenanceResult, len(args.Args)),
+	}
+	if err := mm.checkCanWrite(); err != nil {
+		return results, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		replacements, err := mm.setOneMachineMaintenance(arg)
+		results.Results[i].Replacements = replacements
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) setOneMachineMaintenance(arg params.MachineMaintenanceArg) (map[string]string, error) {
+	machineTag, err := names.ParseMachineTag(arg.Entity.Tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine, err := mm.st.Machine(machineTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if arg.Message == "" {
+		if len(arg.Replacements) > 0 {
+			return nil, errors.NotValidf("replacement units for machine leaving maintenance")
+		}
+		return nil, machine.ClearMaintenance()
+	}
+	if err := machine.SetMaintenance(arg.Message); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := machine.AddMaintenanceReplacements(arg.Replacements); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machine.MaintenanceReplacements(), nil
+}
+
 // AddMachines adds new machines with the supplied parameters.
 func (mm *MachineManagerAPI) AddMachines(args params.AddMachines) (params.AddMachinesResults, error) {
 	results := params.AddMachinesResults{
@@ -181,6 +181,96 @@ func (s *MachineManagerSuite) TestDestroyMachineWithParams(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestReplaceMachines(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["1"] = &mockMachine{}
+	s.st.machines["1"].SetErrors(errors.NotSupportedf("replacing controller machine"))
+	results, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+			{Tag: "machine-2"},
+			{Tag: "unit-foo-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{
+				Message: "replacing controller machine not supported",
+				Code:    params.CodeNotSupported,
+			}},
+			{Error: &params.Error{
+				Message: "machine 2 not found",
+				Code:    params.CodeNotFound,
+			}},
+			{Error: &params.Error{
+				Message: `"unit-foo-0" is not a valid machine tag`,
+			}},
+		},
+	})
+	s.st.machines["0"].CheckCallNames(c, "ReplaceInstance")
+	s.st.machines["1"].CheckCallNames(c, "ReplaceInstance")
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	s.st.machines["0"] = &mockMachine{}
+	_, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.st.machines["0"].CheckNoCalls(c)
+}
+
+func (s *MachineManagerSuite) TestSetMachineMaintenance(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{replacements: map[string]string{"bar/0": "bar/1"}}
+	s.st.machines["1"] = &mockMachine{}
+	results, err := s.api.SetMachineMaintenance(params.MachineMaintenanceArgs{
+		Args: []params.MachineMaintenanceArg{
+			{
+				Entity:       params.Entity{Tag: "machine-0"},
+				Message:      "draining",
+				Replacements: map[string]string{"foo/0": "foo/1"},
+			},
+			{Entity: params.Entity{Tag: "machine-1"}},
+			{Entity: params.Entity{Tag: "machine-2"}, Message: "draining"},
+			{Entity: params.Entity{Tag: "unit-foo-0"}, Message: "draining"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.MachineMaintenanceResults{
+		Results: []params.MachineMaintenanceResult{
+			{Replacements: map[string]string{"foo/0": "foo/1", "bar/0": "bar/1"}},
+			{},
+			{Error: &params.Error{
+				Message: "machine 2 not found",
+				Code:    params.CodeNotFound,
+			}},
+			{Error: &params.Error{
+				Message: `"unit-foo-0" is not a valid machine tag`,
+			}},
+		},
+	})
+	s.st.machines["0"].CheckCallNames(c, "SetMaintenance", "AddMaintenanceReplacements")
+	s.st.machines["0"].CheckCall(c, 0, "SetMaintenance", "draining")
+	s.st.machines["0"].CheckCall(c, 1, "AddMaintenanceReplacements", map[string]string{"foo/0": "foo/1"})
+	s.st.machines["1"].CheckCallNames(c, "ClearMaintenance")
+}
+
+func (s *MachineManagerSuite) TestSetMachineMaintenancePermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	s.st.machines["0"] = &mockMachine{}
+	_, err := s.api.SetMachineMaintenance(params.MachineMaintenanceArgs{
+		Args: []params.MachineMaintenanceArg{
+			{Entity: params.Entity{Tag: "machine-0"}, Message: "draining"},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.st.machines["0"].CheckNoCalls(c)
+}
+
 func (s *MachineManagerSuite) setupUpdateMachineSeries(c *gc.C) {
 	s.st.machines = map[string]*mockMachine{
 		"0": {series: "trusty", units: []string{"foo/0", "test/0"}},
@@ -320,7 +410,7 @@ func (s *MachineManagerSuite) TestUpdateMachineSeriesPermissionDenied(c *gc.C) {
 
 func (s *MachineManagerSuite) TestUpgradeSeriesPrepare(c *gc.C) {
 	s.setupUpdateMachineSeries(c)
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	machineTag := names.NewMachineTag("0")
 	result, err := apiV5.UpgradeSeriesPrepare(
 		params.UpdateSeriesArg{
@@ -340,7 +430,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPrepare(c *gc.C) {
 
 func (s *MachineManagerSuite) TestUpgradeSeriesPrepareAlreadyRunningSeries(c *gc.C) {
 	s.setupUpdateMachineSeries(c)
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	machineTag := names.NewMachineTag("1")
 	result, err := apiV5.UpgradeSeriesPrepare(
 		params.UpdateSeriesArg{
@@ -354,7 +444,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPrepareAlreadyRunningSeries(c *gc
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesPrepareMachineNotFound(c *gc.C) {
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	machineTag := names.NewMachineTag("76")
 	result, err := apiV5.UpgradeSeriesPrepare(
 		params.UpdateSeriesArg{
@@ -368,7 +458,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPrepareMachineNotFound(c *gc.C) {
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesPrepareNotMachineTag(c *gc.C) {
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	unitTag := names.NewUnitTag("mysql/0")
 	result, err := apiV5.UpgradeSeriesPrepare(
 		params.UpdateSeriesArg{
@@ -384,7 +474,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPrepareNotMachineTag(c *gc.C) {
 func (s *MachineManagerSuite) TestUpgradeSeriesPreparePermissionDenied(c *gc.C) {
 	user := names.NewUserTag("fred")
 	s.setAPIUser(c, user)
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	machineTag := names.NewMachineTag("0")
 	_, err := apiV5.UpgradeSeriesPrepare(
 		params.UpdateSeriesArg{
@@ -397,7 +487,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPreparePermissionDenied(c *gc.C)
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesPrepareBlockedChanges(c *gc.C) {
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	s.st.blockMsg = "TestUpgradeSeriesPrepareBlockedChanges"
 	s.st.block = state.ChangeBlock
 	_, err := apiV5.UpgradeSeriesPrepare(
@@ -415,7 +505,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPrepareBlockedChanges(c *gc.C) {
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesPrepareNoSeries(c *gc.C) {
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	result, err := apiV5.UpgradeSeriesPrepare(
 		params.UpdateSeriesArg{
 			Entity: params.Entity{Tag: names.NewMachineTag("0").String()},
@@ -433,7 +523,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPrepareNoSeries(c *gc.C) {
 func (s *MachineManagerSuite) TestUpgradeSeriesPrepareIncompatibleSeries(c *gc.C) {
 	s.setupUpdateMachineSeries(c)
 	s.st.machines["0"].SetErrors(&state.ErrIncompatibleSeries{[]string{"yakkety", "zesty"}, "xenial"})
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	result, err := apiV5.UpgradeSeriesPrepare(
 		params.UpdateSeriesArg{
 			Entity: params.Entity{Tag: names.NewMachineTag("0").String()},
@@ -456,7 +546,7 @@ func (s *MachineManagerSuite) TestUpgradeSeriesPrepareRemoveLockAfterFail(c *gc.
 
 func (s *MachineManagerSuite) TestUpgradeSeriesComplete(c *gc.C) {
 	s.setupUpdateMachineSeries(c)
-	apiV5 := machinemanager.MachineManagerAPIV5{MachineManagerAPI: s.api}
+	apiV5 := s.machineManagerAPIV5()
 	_, err := apiV5.UpgradeSeriesComplete(
 		params.UpdateSeriesArg{
 			Entity: params.Entity{Tag: names.NewMachineTag("0").String()},
@@ -585,9 +675,10 @@ type mockMachine struct {
 	jtesting.Stub
 	machinemanager.Machine
 
-	keep   bool
-	series string
-	units  []string
+	keep         bool
+	series       string
+	units        []string
+	replacements map[string]string
 }
 
 func (m *mockMachine) Destroy() error {
@@ -603,6 +694,39 @@ func (m *mockMachine) Principals() []string {
 	return m.units
 }
 
+func (m *mockMachine) ReplaceInstance() error {
+	m.MethodCall(m, "ReplaceInstance")
+	return m.NextErr()
+}
+
+func (m *mockMachine) SetMaintenance(message string) error {
+	m.MethodCall(m, "SetMaintenance", message)
+	return m.NextErr()
+}
+
+func (m *mockMachine) ClearMaintenance() error {
+	m.MethodCall(m, "ClearMaintenance")
+	return m.NextErr()
+}
+
+func (m *mockMachine) AddMaintenanceReplacements(replacements map[string]string) error {
+	m.MethodCall(m, "AddMaintenanceReplacements", replacements)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	if m.replacements == nil {
+		m.replacements = make(map[string]string)
+	}
+	for replaced, replacement := range replacements {
+		m.replacements[replaced] = replacement
+	}
+	return nil
+}
+
+func (m *mockMachine) MaintenanceReplacements() map[string]string {
+	return m.replacements
+}
+
 func (m *mockMachine) SetKeepInstance(keep bool) error {
 	m.keep = keep
 	return nil
@@ -696,6 +820,11 @@ func (v *mockVolume) Detachable() bool {
 }
 
 func (s *MachineManagerSuite) machineManagerAPIV4() machinemanager.MachineManagerAPIV4 {
-	managerV5 := &machinemanager.MachineManagerAPIV5{s.api}
-	return machinemanager.MachineManagerAPIV4{managerV5}
+	managerV5 := s.machineManagerAPIV5()
+	return machinemanager.MachineManagerAPIV4{&managerV5}
+}
+
+func (s *MachineManagerSuite) machineManagerAPIV5() machinemanager.MachineManagerAPIV5 {
+	managerV6 := &machinemanager.MachineManagerAPIV6{s.api}
+	return machinemanager.MachineManagerAPIV5{managerV6}
 }
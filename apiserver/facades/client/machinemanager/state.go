@@ -49,6 +49,11 @@ type Machine interface {
 	CompleteUpgradeSeries() error
 	VerifyUnitsSeries(unitNames []string, series string, force bool) ([]Unit, error)
 	Principals() []string
+	ReplaceInstance() error
+	SetMaintenance(message string) error
+	ClearMaintenance() error
+	AddMaintenanceReplacements(map[string]string) error
+	MaintenanceReplacements() map[string]string
 }
 
 type stateShim struct {
@@ -5,6 +5,7 @@ package spaces
 
 import (
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/networkingcommon"
@@ -21,6 +22,16 @@ type API interface {
 	CreateSpaces(params.CreateSpacesParams) (params.ErrorResults, error)
 	ListSpaces() (params.ListSpacesResults, error)
 	ReloadSpaces() error
+	RenameSpaces(params.RenameSpacesParams) (params.ErrorResults, error)
+	RemoveSpaces(params.Entities) (params.ErrorResults, error)
+	UpdateSpaces(params.UpdateSpacesParams) (params.ErrorResults, error)
+}
+
+// APIV3 is missing the RenameSpaces, RemoveSpaces and UpdateSpaces methods.
+type APIV3 interface {
+	CreateSpaces(params.CreateSpacesParams) (params.ErrorResults, error)
+	ListSpaces() (params.ListSpacesResults, error)
+	ReloadSpaces() error
 }
 
 // APIV2 is missing ReloadSpaces method
@@ -62,25 +73,130 @@ func newAPIWithBacking(backing networkingcommon.NetworkBacking, ctx context.Prov
 	}, nil
 }
 
+// NewAPIV3 is a wrapper that creates a V3 spaces API.
+func NewAPIV3(st *state.State, res facade.Resources, auth facade.Authorizer) (APIV3, error) {
+	return NewAPI(st, res, auth)
+}
+
 // NewAPIV2 is a wrapper that creates a V2 spaces API.
 func NewAPIV2(st *state.State, res facade.Resources, auth facade.Authorizer) (APIV2, error) {
 	return NewAPI(st, res, auth)
 }
 
-// CreateSpaces creates a new Juju network space, associating the
-// specified subnets with it (optional; can be empty).
-func (api *spacesAPI) CreateSpaces(args params.CreateSpacesParams) (results params.ErrorResults, err error) {
+// checkCanAdmin returns an error if the user cannot administer the model.
+func (api *spacesAPI) checkCanAdmin() error {
 	isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, api.backing.ModelTag())
 	if err != nil && !errors.IsNotFound(err) {
-		return results, errors.Trace(err)
+		return errors.Trace(err)
 	}
 	if !isAdmin {
-		return results, common.ServerError(common.ErrPerm)
+		return common.ServerError(common.ErrPerm)
+	}
+	return nil
+}
+
+// CreateSpaces creates a new Juju network space, associating the
+// specified subnets with it (optional; can be empty).
+func (api *spacesAPI) CreateSpaces(args params.CreateSpacesParams) (results params.ErrorResults, err error) {
+	if err := api.checkCanAdmin(); err != nil {
+		return results, err
 	}
 
 	return networkingcommon.CreateSpaces(api.backing, api.context, args)
 }
 
+// checkCanChangeSpaces returns an error if the user cannot administer
+// the model or the model does not support spaces.
+func (api *spacesAPI) checkCanChangeSpaces() error {
+	if err := api.checkCanAdmin(); err != nil {
+		return err
+	}
+	if err := networkingcommon.SupportsSpaces(api.backing, api.context); err != nil {
+		return common.ServerError(errors.Trace(err))
+	}
+	return nil
+}
+
+// RenameSpaces renames spaces, updating the subnets, endpoint bindings
+// and constraints which refer to them.
+func (api *spacesAPI) RenameSpaces(args params.RenameSpacesParams) (params.ErrorResults, error) {
+	if err := api.checkCanChangeSpaces(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	for i, change := range args.Changes {
+		from, err := names.ParseSpaceTag(change.FromSpaceTag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(errors.Trace(err))
+			continue
+		}
+		to, err := names.ParseSpaceTag(change.ToSpaceTag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(errors.Trace(err))
+			continue
+		}
+		err = api.backing.RenameSpace(from.Id(), to.Id())
+		results.Results[i].Error = common.ServerError(errors.Trace(err))
+	}
+	return results, nil
+}
+
+// RemoveSpaces removes spaces which are not used by any endpoint
+// bindings, constraints or controller config. Their subnets are moved
+// to the default space.
+func (api *spacesAPI) RemoveSpaces(args params.Entities) (params.ErrorResults, error) {
+	if err := api.checkCanChangeSpaces(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseSpaceTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(errors.Trace(err))
+			continue
+		}
+		err = api.backing.RemoveSpace(tag.Id())
+		results.Results[i].Error = common.ServerError(errors.Trace(err))
+	}
+	return results, nil
+}
+
+// UpdateSpaces replaces the subnets of each space with the given ones.
+// Subnets which leave a space are moved to the default space.
+func (api *spacesAPI) UpdateSpaces(args params.UpdateSpacesParams) (params.ErrorResults, error) {
+	if err := api.checkCanChangeSpaces(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Spaces)),
+	}
+	for i, space := range args.Spaces {
+		err := api.updateSpace(space)
+		results.Results[i].Error = common.ServerError(errors.Trace(err))
+	}
+	return results, nil
+}
+
+func (api *spacesAPI) updateSpace(args params.UpdateSpaceParams) error {
+	spaceTag, err := names.ParseSpaceTag(args.SpaceTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subnets := make([]string, len(args.SubnetTags))
+	for i, tag := range args.SubnetTags {
+		subnetTag, err := names.ParseSubnetTag(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		subnets[i] = subnetTag.Id()
+	}
+	return api.backing.UpdateSpace(spaceTag.Id(), subnets)
+}
+
 // ListSpaces lists all the available spaces and their associated subnets.
 func (api *spacesAPI) ListSpaces() (results params.ListSpacesResults, err error) {
 	canRead, err := api.authorizer.HasPermission(permission.ReadAccess, api.backing.ModelTag())
@@ -393,3 +393,99 @@ func (s *SpacesSuite) TestReloadSpacesUserDenied(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "permission denied")
 	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
 }
+
+func (s *SpacesSuite) supportsSpacesCalls() []apiservertesting.StubMethodCall {
+	return []apiservertesting.StubMethodCall{
+		apiservertesting.BackingCall("ModelConfig"),
+		apiservertesting.BackingCall("CloudSpec"),
+		apiservertesting.ProviderCall("Open", apiservertesting.BackingInstance.EnvConfig),
+		apiservertesting.ZonedNetworkingEnvironCall("SupportsSpaces", s.callContext),
+	}
+}
+
+func (s *SpacesSuite) TestRenameSpaces(c *gc.C) {
+	results, err := s.facade.RenameSpaces(params.RenameSpacesParams{
+		Changes: []params.RenameSpaceParams{{
+			FromSpaceTag: "space-dmz",
+			ToSpaceTag:   "space-public",
+		}, {
+			FromSpaceTag: "space-dmz",
+			ToSpaceTag:   "subnet-10.0.0.0/24",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `"subnet-10.0.0.0/24" is not a valid space tag`)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		append(s.supportsSpacesCalls(), apiservertesting.BackingCall("RenameSpace", "dmz", "public"))...,
+	)
+}
+
+func (s *SpacesSuite) TestRemoveSpaces(c *gc.C) {
+	apiservertesting.SharedStub.SetErrors(
+		nil, // Backing.ModelConfig()
+		nil, // Backing.CloudSpec()
+		nil, // Provider.Open()
+		nil, // ZonedNetworkingEnviron.SupportsSpaces()
+		errors.New(`space is used by application mysql`), // Backing.RemoveSpace()
+	)
+	results, err := s.facade.RemoveSpaces(params.Entities{
+		Entities: []params.Entity{{Tag: "space-dmz"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "space is used by application mysql")
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		append(s.supportsSpacesCalls(), apiservertesting.BackingCall("RemoveSpace", "dmz"))...,
+	)
+}
+
+func (s *SpacesSuite) TestUpdateSpaces(c *gc.C) {
+	results, err := s.facade.UpdateSpaces(params.UpdateSpacesParams{
+		Spaces: []params.UpdateSpaceParams{{
+			SpaceTag:   "space-dmz",
+			SubnetTags: []string{"subnet-192.168.1.0/24", "subnet-192.168.3.0/24"},
+		}, {
+			SpaceTag:   "space-dmz",
+			SubnetTags: []string{"subnet-bar"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `"subnet-bar" is not a valid subnet tag`)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		append(s.supportsSpacesCalls(), apiservertesting.BackingCall(
+			"UpdateSpace", "dmz", []string{"192.168.1.0/24", "192.168.3.0/24"},
+		))...,
+	)
+}
+
+func (s *SpacesSuite) TestUpdateSpacesNotSupportedError(c *gc.C) {
+	apiservertesting.SharedStub.SetErrors(
+		nil, // Backing.ModelConfig()
+		nil, // Backing.CloudSpec()
+		nil, // Provider.Open()
+		errors.NotSupportedf("spaces"), // ZonedNetworkingEnviron.SupportsSpaces()
+	)
+	_, err := s.facade.UpdateSpaces(params.UpdateSpacesParams{})
+	c.Assert(err, gc.ErrorMatches, "spaces not supported")
+}
+
+func (s *SpacesSuite) TestRemoveSpacesUserDenied(c *gc.C) {
+	userAuthorizer := s.authorizer
+	userAuthorizer.Tag = names.NewUserTag("regular")
+	facade, err := spaces.NewAPIWithBacking(
+		apiservertesting.BackingInstance,
+		context.NewCloudCallContext(),
+		s.resources, userAuthorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = facade.RemoveSpaces(params.Entities{})
+	c.Check(err, gc.ErrorMatches, "permission denied")
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub)
+}
@@ -8,6 +8,7 @@ package sshclient
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
@@ -47,12 +48,69 @@ func internalFacade(backend Backend, auth facade.Authorizer, callCtx context.Pro
 	return &Facade{backend: backend, authorizer: auth, callContext: callCtx}, nil
 }
 
-func (facade *Facade) checkIsModelAdmin() error {
+func (facade *Facade) checkCanRead() error {
+	canRead, err := facade.authorizer.HasPermission(permission.ReadAccess, facade.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canRead {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// checkCanAccess checks that the user may connect to all of the given
+// entities: model admins may connect to any machine or unit, while
+// application operators may only connect to units of the applications
+// they operate. As a connection gives root on the unit's machine, the
+// operator must also operate every application sharing that machine.
+func (facade *Facade) checkCanAccess(args params.Entities) error {
 	isModelAdmin, err := facade.authorizer.HasPermission(permission.AdminAccess, facade.backend.ModelTag())
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if !isModelAdmin {
+	if isModelAdmin {
+		return nil
+	}
+	if len(args.Entities) == 0 {
+		return common.ErrPerm
+	}
+	for _, entity := range args.Entities {
+		unitTag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			return common.ErrPerm
+		}
+		appName, err := names.UnitApplication(unitTag.Id())
+		if err != nil {
+			return common.ErrPerm
+		}
+		if err := facade.checkCanOperate(appName); err != nil {
+			return errors.Trace(err)
+		}
+		sharing, err := facade.backend.ApplicationsSharingUnitMachine(unitTag)
+		if errors.IsNotFound(err) || errors.IsNotAssigned(err) {
+			// There is no machine to connect to; the lookup of
+			// the entity's addresses reports that.
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		for _, appName := range sharing {
+			if err := facade.checkCanOperate(appName); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkCanOperate checks that the user may operate the named application.
+func (facade *Facade) checkCanOperate(appName string) error {
+	canOperate, err := facade.authorizer.HasPermission(permission.OperateAccess, names.NewApplicationTag(appName))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canOperate {
 		return common.ErrPerm
 	}
 	return nil
@@ -61,7 +119,7 @@ func (facade *Facade) checkIsModelAdmin() error {
 // PublicAddress reports the preferred public network address for one
 // or more entities. Machines and units are suppored.
 func (facade *Facade) PublicAddress(args params.Entities) (params.SSHAddressResults, error) {
-	if err := facade.checkIsModelAdmin(); err != nil {
+	if err := facade.checkCanAccess(args); err != nil {
 		return params.SSHAddressResults{}, errors.Trace(err)
 	}
 
@@ -72,7 +130,7 @@ func (facade *Facade) PublicAddress(args params.Entities) (params.SSHAddressResu
 // PrivateAddress reports the preferred private network address for one or
 // more entities. Machines and units are supported.
 func (facade *Facade) PrivateAddress(args params.Entities) (params.SSHAddressResults, error) {
-	if err := facade.checkIsModelAdmin(); err != nil {
+	if err := facade.checkCanAccess(args); err != nil {
 		return params.SSHAddressResults{}, errors.Trace(err)
 	}
 
@@ -86,7 +144,7 @@ func (facade *Facade) PrivateAddress(args params.Entities) (params.SSHAddressRes
 // but get the addresses from state. We will be changing it since we want to have space-aware
 // SSH settings.
 func (facade *Facade) AllAddresses(args params.Entities) (params.SSHAddressesResults, error) {
-	if err := facade.checkIsModelAdmin(); err != nil {
+	if err := facade.checkCanAccess(args); err != nil {
 		return params.SSHAddressesResults{}, errors.Trace(err)
 	}
 	env, err := environs.GetEnviron(facade.backend, environs.New)
@@ -181,7 +239,7 @@ func (facade *Facade) getAddressPerEntity(args params.Entities, addressGetter fu
 // PublicKeys returns the public SSH hosts for one or more
 // entities. Machines and units are supported.
 func (facade *Facade) PublicKeys(args params.Entities) (params.SSHPublicKeysResults, error) {
-	if err := facade.checkIsModelAdmin(); err != nil {
+	if err := facade.checkCanAccess(args); err != nil {
 		return params.SSHPublicKeysResults{}, errors.Trace(err)
 	}
 
@@ -207,7 +265,9 @@ func (facade *Facade) PublicKeys(args params.Entities) (params.SSHPublicKeysResu
 // Proxy returns whether SSH connections should be proxied through the
 // controller hosts for the model associated with the API connection.
 func (facade *Facade) Proxy() (params.SSHProxyResult, error) {
-	if err := facade.checkIsModelAdmin(); err != nil {
+	// Application operators need to know this too, so model read
+	// access is sufficient.
+	if err := facade.checkCanRead(); err != nil {
 		return params.SSHProxyResult{}, errors.Trace(err)
 	}
 	config, err := facade.backend.ModelConfig()
@@ -162,6 +162,59 @@ func (s *facadeSuite) TestPublicKeys(c *gc.C) {
 	})
 }
 
+func (s *facadeSuite) TestPublicAddressApplicationOperator(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("operate-application-foo")
+	s.authorizer.AdminTag = names.UserTag{}
+
+	results, err := s.facade.PublicAddress(params.Entities{
+		Entities: []params.Entity{{s.uFoo}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results, gc.DeepEquals, params.SSHAddressResults{
+		Results: []params.SSHAddressResult{{Address: "3.3.3.3"}},
+	})
+
+	// Operators cannot reach machines, or units of other applications.
+	for _, tag := range []string{s.m0, s.uOther} {
+		_, err = s.facade.PublicAddress(params.Entities{
+			Entities: []params.Entity{{s.uFoo}, {tag}},
+		})
+		c.Check(errors.Cause(err), gc.Equals, common.ErrPerm)
+	}
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"ApplicationsSharingUnitMachine", []interface{}{names.NewUnitTag("foo/0")}},
+		{"GetMachineForEntity", []interface{}{s.uFoo}},
+		{"ApplicationsSharingUnitMachine", []interface{}{names.NewUnitTag("foo/0")}},
+		{"ApplicationsSharingUnitMachine", []interface{}{names.NewUnitTag("foo/0")}},
+	})
+}
+
+func (s *facadeSuite) TestPublicAddressApplicationOperatorSharedMachine(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("operate-application-foo")
+	s.authorizer.AdminTag = names.UserTag{}
+	s.backend.sharing = []string{"foo", "other"}
+
+	// Connecting to foo/0 would reach other's unit on the same machine.
+	_, err := s.facade.PublicAddress(params.Entities{
+		Entities: []params.Entity{{s.uFoo}},
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"ApplicationsSharingUnitMachine", []interface{}{names.NewUnitTag("foo/0")}},
+	})
+}
+
+func (s *facadeSuite) TestPublicKeysNoAccess(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("fred")
+	s.authorizer.AdminTag = names.UserTag{}
+
+	_, err := s.facade.PublicKeys(params.Entities{
+		Entities: []params.Entity{{s.uFoo}},
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+	s.backend.stub.CheckNoCalls(c)
+}
+
 func (s *facadeSuite) TestProxyTrue(c *gc.C) {
 	s.backend.proxySSH = true
 	result, err := s.facade.Proxy()
@@ -185,6 +238,19 @@ func (s *facadeSuite) TestProxyFalse(c *gc.C) {
 type mockBackend struct {
 	stub     jujutesting.Stub
 	proxySSH bool
+	sharing  []string
+}
+
+func (backend *mockBackend) ApplicationsSharingUnitMachine(tag names.UnitTag) ([]string, error) {
+	backend.stub.AddCall("ApplicationsSharingUnitMachine", tag)
+	if backend.sharing != nil {
+		return backend.sharing, nil
+	}
+	appName, err := names.UnitApplication(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []string{appName}, nil
 }
 
 func (backend *mockBackend) ModelTag() names.ModelTag {
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/network"
@@ -21,6 +22,7 @@ type Backend interface {
 	GetMachineForEntity(tag string) (SSHMachine, error)
 	GetSSHHostKeys(names.MachineTag) (state.SSHHostKeys, error)
 	ModelTag() names.ModelTag
+	ApplicationsSharingUnitMachine(names.UnitTag) ([]string, error)
 }
 
 // SSHMachine specifies the methods on State.Machine of interest to
@@ -70,3 +72,17 @@ func (b *backend) GetMachineForEntity(tagString string) (SSHMachine, error) {
 		return nil, errors.Errorf("unsupported entity: %q", tagString)
 	}
 }
+
+// ApplicationsSharingUnitMachine returns the names of the applications
+// reachable from the machine the given unit is assigned to.
+func (b *backend) ApplicationsSharingUnitMachine(tag names.UnitTag) ([]string, error) {
+	unit, err := b.State.Unit(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.ApplicationsSharingMachine(b.State, machineId)
+}
@@ -4,12 +4,14 @@
 package usermanager
 
 import (
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
@@ -58,6 +60,61 @@ func NewUserManagerAPI(
 	}, nil
 }
 
+// UserManagerAPIv3 implements version 3 of the user manager facade,
+// which does not support API tokens.
+type UserManagerAPIv3 struct {
+	*UserManagerAPI
+}
+
+// NewUserManagerAPIv3 returns a facade for version 3 of the user
+// manager API.
+func NewUserManagerAPIv3(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*UserManagerAPIv3, error) {
+	api, err := NewUserManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIv3{api}, nil
+}
+
+// AddAPIToken isn't on the v3 API.
+func (*UserManagerAPIv3) AddAPIToken(_, _ struct{}) {}
+
+// APITokens isn't on the v3 API.
+func (*UserManagerAPIv3) APITokens(_, _ struct{}) {}
+
+// RevokeAPIToken isn't on the v3 API.
+func (*UserManagerAPIv3) RevokeAPIToken(_, _ struct{}) {}
+
+// UserManagerAPIv2 implements version 2 of the user manager facade,
+// which does not support user groups.
+type UserManagerAPIv2 struct {
+	*UserManagerAPIv3
+}
+
+// NewUserManagerAPIv2 returns a facade for version 2 and earlier
+// of the user manager API.
+func NewUserManagerAPIv2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*UserManagerAPIv2, error) {
+	api, err := NewUserManagerAPIv3(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIv2{api}, nil
+}
+
+// AddGroups isn't on the v2 API.
+func (*UserManagerAPIv2) AddGroups(_, _ struct{}) {}
+
+// AddGroupMembers isn't on the v2 API.
+func (*UserManagerAPIv2) AddGroupMembers(_, _ struct{}) {}
+
 func (api *UserManagerAPI) hasControllerAdminAccess() (bool, error) {
 	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
 	if errors.IsNotFound(err) {
@@ -258,7 +315,7 @@ func (api *UserManagerAPI) UserInfo(request params.UserInfoRequest) (params.User
 
 	var accessForUser = func(userTag names.UserTag, result *params.UserInfoResult) {
 		// Lookup the access the specified user has to the controller.
-		access, err := common.GetPermission(api.state.UserPermission, userTag, api.state.ControllerTag())
+		access, err := common.GetPermission(common.EffectiveUserAccess(api.state), userTag, api.state.ControllerTag())
 		if err == nil {
 			result.Result.Access = string(access)
 		} else if err != nil && !errors.IsNotFound(err) {
@@ -430,3 +487,212 @@ func (api *UserManagerAPI) ResetPassword(args params.Entities) (params.AddUserRe
 	}
 	return result, nil
 }
+
+// AddGroups adds user groups to the controller. Access granted to
+// a group, as "<name>@group", applies to all of its members.
+func (api *UserManagerAPI) AddGroups(args params.AddGroups) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Groups)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !isSuperUser {
+		return result, common.ErrPerm
+	}
+	for i, arg := range args.Groups {
+		if _, err := api.state.AddUserGroup(arg.Name, api.apiUser.Id()); err != nil {
+			err = errors.Annotate(err, "failed to create group")
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// AddGroupMembers adds users to user groups.
+func (api *UserManagerAPI) AddGroupMembers(args params.AddGroupMembers) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Members)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !isSuperUser {
+		return result, common.ErrPerm
+	}
+	for i, arg := range args.Members {
+		userTag, err := names.ParseUserTag(arg.UserTag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err := api.state.AddUserGroupMember(arg.Group, userTag); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// AddAPIToken adds API tokens with which users may log in without a
+// password. Users may add tokens for themselves; superusers may add
+// tokens for any local user. The credentials for each token are only
+// returned here.
+func (api *UserManagerAPI) AddAPIToken(args params.AddAPITokens) (params.AddAPITokenResults, error) {
+	result := params.AddAPITokenResults{
+		Results: make([]params.AddAPITokenResult, len(args.Tokens)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Tokens {
+		token, secret, err := api.addAPIToken(arg, isSuperUser)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Id = token.Id()
+		result.Results[i].Credentials = authentication.APITokenCredentials(token.Id(), secret)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) addAPIToken(arg params.AddAPIToken, isSuperUser bool) (*state.APIToken, string, error) {
+	userTag, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if userTag != api.apiUser && !isSuperUser {
+		return nil, "", common.ErrPerm
+	}
+	if err := authentication.ValidateAPITokenFacadeGroups(arg.FacadeGroups); err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	models, err := api.resolveTokenModels(userTag, arg.Models)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	token, secret, err := api.state.AddAPIToken(state.AddAPITokenArgs{
+		User:         userTag,
+		CreatedBy:    api.apiUser,
+		Description:  arg.Description,
+		Expires:      arg.Expires,
+		Models:       models,
+		FacadeGroups: arg.FacadeGroups,
+	})
+	if err != nil {
+		return nil, "", errors.Annotate(err, "failed to add API token")
+	}
+	return token, secret, nil
+}
+
+// resolveTokenModels returns the UUIDs of the given models, which
+// are named by UUID, "<owner>/<name>" or, for models owned by the
+// token's user, just "<name>". The token's user must have access
+// to each of them.
+func (api *UserManagerAPI) resolveTokenModels(user names.UserTag, specs []string) ([]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	models, err := api.state.ModelBasicInfoForUser(user)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uuids := make([]string, len(specs))
+	for i, spec := range specs {
+		owner, name := user.Id(), spec
+		if parts := strings.SplitN(spec, "/", 2); len(parts) == 2 {
+			owner, name = names.NewUserTag(parts[0]).Id(), parts[1]
+		}
+		for _, model := range models {
+			if model.UUID == spec || (model.Owner == owner && model.Name == name) {
+				uuids[i] = model.UUID
+				break
+			}
+		}
+		if uuids[i] == "" {
+			return nil, errors.NotFoundf("model %q for user %q", spec, user.Id())
+		}
+	}
+	return uuids, nil
+}
+
+// APITokens returns information on the API tokens of the authenticated
+// user or, for superusers asking for all of them, of every user.
+func (api *UserManagerAPI) APITokens(args params.APITokensRequest) (params.APITokenInfoResults, error) {
+	var result params.APITokenInfoResults
+	user := api.apiUser
+	if args.All {
+		isSuperUser, err := api.hasControllerAdminAccess()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if !isSuperUser {
+			return result, common.ErrPerm
+		}
+		user = names.UserTag{}
+	}
+	tokens, err := api.state.APITokens(user)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.APITokenInfo, len(tokens))
+	for i, token := range tokens {
+		result.Results[i] = params.APITokenInfo{
+			Id:           token.Id(),
+			UserTag:      token.UserTag().String(),
+			Description:  token.Description(),
+			CreatedBy:    token.CreatedBy(),
+			DateCreated:  token.DateCreated(),
+			Expires:      token.Expires(),
+			Models:       token.Models(),
+			FacadeGroups: token.FacadeGroups(),
+		}
+	}
+	return result, nil
+}
+
+// RevokeAPIToken revokes API tokens, so that they can no longer be
+// used to log in. Users may revoke their own tokens; superusers may
+// revoke anyone's.
+func (api *UserManagerAPI) RevokeAPIToken(args params.RevokeAPITokens) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, id := range args.Ids {
+		if err := api.revokeAPIToken(id, isSuperUser); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) revokeAPIToken(id string, isSuperUser bool) error {
+	token, err := api.state.APIToken(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if token.UserTag() != api.apiUser && !isSuperUser {
+		// Don't reveal that other users' tokens exist.
+		return errors.NotFoundf("API token %q", id)
+	}
+	return errors.Trace(api.state.RevokeAPIToken(id))
+}
@@ -990,3 +990,209 @@ func (s *userManagerSuite) TestResetPasswordEmpty(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results.Results, gc.HasLen, 0)
 }
+
+func (s *userManagerSuite) TestAddGroups(c *gc.C) {
+	_, err := s.State.AddUserGroup("ops", s.adminName)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.usermanager.AddGroups(params.AddGroups{
+		Groups: []params.AddGroup{{Name: "devs"}, {Name: "ops"}, {Name: "bad@group"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `failed to create group: group "ops" already exists`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `failed to create group: group name "bad@group" not valid`)
+
+	group, err := s.State.UserGroup("devs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.CreatedBy(), gc.Equals, s.adminName)
+}
+
+func (s *userManagerSuite) TestAddGroupsAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = usermanager.AddGroups(params.AddGroups{
+		Groups: []params.AddGroup{{Name: "devs"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	_, err = s.State.UserGroup("devs")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestBlockAddGroups(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockAddGroups")
+	_, err := s.usermanager.AddGroups(params.AddGroups{
+		Groups: []params.AddGroup{{Name: "devs"}},
+	})
+	s.AssertBlocked(c, err, "TestBlockAddGroups")
+}
+
+func (s *userManagerSuite) TestAddGroupMembers(c *gc.C) {
+	_, err := s.State.AddUserGroup("devs", s.adminName)
+	c.Assert(err, jc.ErrorIsNil)
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+
+	results, err := s.usermanager.AddGroupMembers(params.AddGroupMembers{
+		Members: []params.AddGroupMember{
+			{Group: "devs", UserTag: alex.Tag().String()},
+			{Group: "devs", UserTag: "user-bob@oidc"},
+			{Group: "ops", UserTag: alex.Tag().String()},
+			{Group: "devs", UserTag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `group "ops" not found`)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `"machine-0" is not a valid user tag`)
+
+	groups, err := s.State.UserGroups(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []names.UserTag{state.UserGroupTag("devs")})
+}
+
+func (s *userManagerSuite) TestAddGroupMembersAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = usermanager.AddGroupMembers(params.AddGroupMembers{
+		Members: []params.AddGroupMember{{Group: "devs", UserTag: alex.Tag().String()}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestAddAPIToken(c *gc.C) {
+	ci := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	expires := time.Now().Add(time.Hour)
+
+	results, err := s.usermanager.AddAPIToken(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			UserTag:      ci.Tag().String(),
+			Description:  "deploy pipeline",
+			Expires:      expires,
+			Models:       []string{model.Owner().Id() + "/" + model.Name()},
+			FacadeGroups: []string{"deploy", "read-only"},
+		}, {
+			UserTag:      ci.Tag().String(),
+			Expires:      expires,
+			FacadeGroups: []string{"everything"},
+		}, {
+			UserTag: ci.Tag().String(),
+			Expires: expires,
+			Models:  []string{"elsewhere"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Credentials, gc.Matches, "juju-api-token:"+results.Results[0].Id+":.+")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `facade group "everything" not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `model "elsewhere" for user "ci" not found`)
+
+	token, err := s.State.APIToken(results.Results[0].Id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.UserTag(), gc.Equals, ci.UserTag())
+	c.Assert(token.CreatedBy(), gc.Equals, s.adminName)
+	c.Assert(token.Models(), jc.DeepEquals, []string{model.UUID()})
+	c.Assert(token.FacadeGroups(), jc.DeepEquals, []string{"deploy", "read-only"})
+}
+
+func (s *userManagerSuite) TestAddAPITokenAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	expires := time.Now().Add(time.Hour)
+
+	results, err := usermanager.AddAPIToken(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{
+			{UserTag: alex.Tag().String(), Expires: expires},
+			{UserTag: s.AdminUserTag(c).String(), Expires: expires},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestBlockAddAPIToken(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockAddAPIToken")
+	_, err := s.usermanager.AddAPIToken(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{UserTag: s.AdminUserTag(c).String(), Expires: time.Now().Add(time.Hour)}},
+	})
+	s.AssertBlocked(c, err, "TestBlockAddAPIToken")
+}
+
+func (s *userManagerSuite) addAPIToken(c *gc.C, user names.UserTag) *state.APIToken {
+	token, _, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		User:      user,
+		CreatedBy: s.AdminUserTag(c),
+		Expires:   time.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return token
+}
+
+func (s *userManagerSuite) TestAPITokens(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	alexToken := s.addAPIToken(c, alex.UserTag())
+	adminToken := s.addAPIToken(c, s.AdminUserTag(c))
+
+	results, err := s.usermanager.APITokens(params.APITokensRequest{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Id, gc.Equals, adminToken.Id())
+	c.Assert(results.Results[0].UserTag, gc.Equals, s.AdminUserTag(c).String())
+	c.Assert(results.Results[0].CreatedBy, gc.Equals, s.adminName)
+
+	results, err = s.usermanager.APITokens(params.APITokensRequest{All: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = usermanager.APITokens(params.APITokensRequest{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Id, gc.Equals, alexToken.Id())
+	_, err = usermanager.APITokens(params.APITokensRequest{All: true})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestRevokeAPIToken(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	alexToken := s.addAPIToken(c, alex.UserTag())
+	adminToken := s.addAPIToken(c, s.AdminUserTag(c))
+
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := usermanager.RevokeAPIToken(params.RevokeAPITokens{
+		Ids: []string{alexToken.Id(), adminToken.Id()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `API token ".*" not found`)
+
+	_, err = s.State.APIToken(alexToken.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.APIToken(adminToken.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err = s.usermanager.RevokeAPIToken(params.RevokeAPITokens{Ids: []string{adminToken.Id()}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+}
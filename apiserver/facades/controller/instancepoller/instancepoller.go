@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
 	"gopkg.in/juju/names.v2"
 
@@ -17,6 +18,8 @@ import (
 	"github.com/juju/juju/status"
 )
 
+var logger = loggo.GetLogger("juju.apiserver.instancepoller")
+
 // InstancePollerAPI provides access to the InstancePoller API facade.
 type InstancePollerAPI struct {
 	*common.LifeGetter
@@ -213,6 +216,7 @@ func (a *InstancePollerAPI) SetInstanceStatus(args params.SetStatus) (params.Err
 				Data:    arg.Data,
 				Since:   &now,
 			}
+			reclaimed := s.Status == status.Reclaimed && !wasReclaimed(machine)
 			err = machine.SetInstanceStatus(s)
 			if status.Status(arg.Status) == status.ProvisioningError {
 				s.Status = status.Error
@@ -220,12 +224,46 @@ func (a *InstancePollerAPI) SetInstanceStatus(args params.SetStatus) (params.Err
 					err = machine.SetStatus(s)
 				}
 			}
+			if err == nil && reclaimed {
+				a.maybeReplaceReclaimedMachine(machine)
+			}
 		}
 		result.Results[i].Error = common.ServerError(err)
 	}
 	return result, nil
 }
 
+// wasReclaimed reports whether the machine's instance was already
+// known to have been reclaimed by the cloud.
+func wasReclaimed(machine StateMachine) bool {
+	info, err := machine.InstanceStatus()
+	return err == nil && info.Status == status.Reclaimed
+}
+
+// maybeReplaceReclaimedMachine replaces the given machine, whose
+// instance has just been reclaimed by the cloud, if the model is
+// configured to do so. Failure to replace the machine is logged
+// rather than reported, as the instance status has been recorded.
+func (a *InstancePollerAPI) maybeReplaceReclaimedMachine(machine StateMachine) {
+	cfg, err := a.st.ModelConfig()
+	if err != nil {
+		logger.Errorf("cannot read model config: %v", err)
+		return
+	}
+	if !cfg.ReplaceReclaimedMachines() {
+		return
+	}
+	replacementId, err := a.st.ReplaceReclaimedMachine(machine.Id())
+	switch {
+	case errors.IsNotSupported(err):
+		logger.Infof("not replacing reclaimed machine %s: %v", machine.Id(), err)
+	case err != nil:
+		logger.Errorf("cannot replace reclaimed machine %s: %v", machine.Id(), err)
+	default:
+		logger.Infof("replaced reclaimed machine %s with machine %s", machine.Id(), replacementId)
+	}
+}
+
 // AreManuallyProvisioned returns whether each given entity is
 // manually provisioned or not. Only machine tags are accepted.
 func (a *InstancePollerAPI) AreManuallyProvisioned(args params.Entities) (params.BoolResults, error) {
@@ -657,6 +657,53 @@ func (s *InstancePollerSuite) TestSetInstanceStatusFailure(c *gc.C) {
 	s.st.CheckFindEntityCall(c, 3, "3")
 }
 
+func (s *InstancePollerSuite) TestSetInstanceStatusReclaimed(c *gc.C) {
+	modelConfig := coretesting.ModelConfig(c)
+	modelConfig, err := modelConfig.Apply(map[string]interface{}{
+		"replace-reclaimed-machines": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.SetConfig(c, modelConfig)
+	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: statusInfo("running")})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", instanceStatus: statusInfo("reclaimed")})
+
+	result, err := s.api.SetInstanceStatus(params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "machine-1", Status: "reclaimed"},
+			{Tag: "machine-2", Status: "reclaimed"},
+		}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {}},
+	})
+
+	// Only the newly reclaimed machine is replaced.
+	now := s.clock.Now()
+	s.st.CheckCallNames(c,
+		"FindEntity", "InstanceStatus", "SetInstanceStatus", "ModelConfig", "ReplaceReclaimedMachine",
+		"FindEntity", "InstanceStatus", "SetInstanceStatus",
+	)
+	s.st.CheckCall(c, 2, "SetInstanceStatus", status.StatusInfo{Status: status.Reclaimed, Since: &now})
+	s.st.CheckCall(c, 4, "ReplaceReclaimedMachine", "1")
+}
+
+func (s *InstancePollerSuite) TestSetInstanceStatusReclaimedNotReplaced(c *gc.C) {
+	s.st.SetConfig(c, coretesting.ModelConfig(c))
+	s.st.SetMachineInfo(c, machineInfo{id: "1", instanceStatus: statusInfo("running")})
+
+	result, err := s.api.SetInstanceStatus(params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "machine-1", Status: "reclaimed"},
+		}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	s.st.CheckCallNames(c, "FindEntity", "InstanceStatus", "SetInstanceStatus", "ModelConfig")
+}
+
 func (s *InstancePollerSuite) TestAreManuallyProvisionedSuccess(c *gc.C) {
 	s.st.SetMachineInfo(c, machineInfo{id: "1", isManual: true})
 	s.st.SetMachineInfo(c, machineInfo{id: "2", isManual: false})
@@ -200,6 +200,18 @@ func (m *mockState) Machine(id string) (instancepoller.StateMachine, error) {
 	return machine, nil
 }
 
+// ReplaceReclaimedMachine implements StateInterface.
+func (m *mockState) ReplaceReclaimedMachine(id string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "ReplaceReclaimedMachine", id)
+	if err := m.NextErr(); err != nil {
+		return "", err
+	}
+	return "99", nil
+}
+
 // StartSync implements statetesting.SyncStarter, so mockState can be
 // used with watcher helpers/checkers.
 func (m *mockState) StartSync() {}
@@ -225,6 +237,11 @@ type mockMachine struct {
 
 var _ instancepoller.StateMachine = (*mockMachine)(nil)
 
+// Id implements StateMachine.
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
 // InstanceId implements StateMachine.
 func (m *mockMachine) InstanceId() (instance.Id, error) {
 	m.mu.Lock()
@@ -34,6 +34,11 @@ type StateInterface interface {
 	state.EntityFinder
 
 	Machine(id string) (StateMachine, error)
+
+	// ReplaceReclaimedMachine replaces the machine with the given id,
+	// whose instance has been reclaimed by the cloud, returning the id
+	// of the new machine.
+	ReplaceReclaimedMachine(id string) (string, error)
 }
 
 // TODO - CAAS(ericclaudejones): This should contain state alone, model will be
@@ -47,6 +52,14 @@ func (s stateShim) Machine(id string) (StateMachine, error) {
 	return s.State.Machine(id)
 }
 
+func (s stateShim) ReplaceReclaimedMachine(id string) (string, error) {
+	m, err := s.State.ReplaceReclaimedMachine(id)
+	if err != nil {
+		return "", err
+	}
+	return m.Id(), nil
+}
+
 var getState = func(st *state.State, m *state.Model) StateInterface {
 	return stateShim{st, m}
 }
@@ -316,8 +316,11 @@ func getUsedTools(model description.Model) []params.SerializedModelTools {
 
 	for _, application := range model.Applications() {
 		for _, unit := range application.Units() {
-			tools := unit.Tools()
-			usedVersions[tools.Version()] = true
+			// Units in CAAS models have no agent binaries;
+			// their operators run from a docker image.
+			if tools := unit.Tools(); tools != nil {
+				usedVersions[tools.Version()] = true
+			}
 		}
 	}
 
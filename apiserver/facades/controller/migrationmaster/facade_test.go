@@ -303,6 +303,23 @@ func (s *Suite) TestExport(c *gc.C) {
 
 }
 
+func (s *Suite) TestExportUnitsWithoutTools(c *gc.C) {
+	// Units in CAAS models have no agent binaries.
+	app := s.model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("gitlab"),
+		CharmURL: "cs:gitlab-0",
+	})
+	app.AddUnit(description.UnitArgs{
+		Tag: names.NewUnitTag("gitlab/0"),
+	})
+
+	api := s.mustMakeAPI(c)
+	serialized, err := api.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(serialized.Charms, gc.DeepEquals, []string{"cs:gitlab-0"})
+	c.Check(serialized.Tools, gc.HasLen, 0)
+}
+
 func (s *Suite) TestReap(c *gc.C) {
 	api := s.mustMakeAPI(c)
 	s.backend.migration = &stubMigration{}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
@@ -26,36 +27,63 @@ import (
 // API implements the API required for the model migration
 // master worker when communicating with the target controller.
 type API struct {
-	state       *state.State
-	pool        *state.StatePool
-	authorizer  facade.Authorizer
-	resources   facade.Resources
-	presence    facade.Presence
-	getEnviron  stateenvirons.NewEnvironFunc
-	callContext context.ProviderCallContext
+	state         *state.State
+	pool          *state.StatePool
+	authorizer    facade.Authorizer
+	resources     facade.Resources
+	presence      facade.Presence
+	getEnviron    stateenvirons.NewEnvironFunc
+	getCAASBroker stateenvirons.NewCAASBrokerFunc
+	callContext   context.ProviderCallContext
+}
+
+// APIV1 implements the V1 API. It doesn't support the PrecheckReport
+// and ValidateImport methods.
+type APIV1 struct {
+	*API
 }
 
 // NewFacade is used for API registration.
 func NewFacade(ctx facade.Context) (*API, error) {
-	return NewAPI(ctx, stateenvirons.GetNewEnvironFunc(environs.New), state.CallContext(ctx.State()))
+	return NewAPI(
+		ctx,
+		stateenvirons.GetNewEnvironFunc(environs.New),
+		stateenvirons.GetNewCAASBrokerFunc(caas.New),
+		state.CallContext(ctx.State()),
+	)
 }
 
-// NewAPI returns a new API. Accepts a NewEnvironFunc and context.ProviderCallContext
-// for testing purposes.
-func NewAPI(ctx facade.Context, getEnviron stateenvirons.NewEnvironFunc, callCtx context.ProviderCallContext) (*API, error) {
+// NewFacadeV1 is used for V1 API registration.
+func NewFacadeV1(ctx facade.Context) (*APIV1, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIV1{api}, nil
+}
+
+// NewAPI returns a new API. Accepts a NewEnvironFunc, NewCAASBrokerFunc
+// and context.ProviderCallContext for testing purposes.
+func NewAPI(
+	ctx facade.Context,
+	getEnviron stateenvirons.NewEnvironFunc,
+	getCAASBroker stateenvirons.NewCAASBrokerFunc,
+	callCtx context.ProviderCallContext,
+) (*API, error) {
 	auth := ctx.Auth()
 	st := ctx.State()
 	if err := checkAuth(auth, st); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &API{
-		state:       st,
-		pool:        ctx.StatePool(),
-		authorizer:  auth,
-		resources:   ctx.Resources(),
-		presence:    ctx.Presence(),
-		getEnviron:  getEnviron,
-		callContext: callCtx,
+		state:         st,
+		pool:          ctx.StatePool(),
+		authorizer:    auth,
+		resources:     ctx.Resources(),
+		presence:      ctx.Presence(),
+		getEnviron:    getEnviron,
+		getCAASBroker: getCAASBroker,
+		callContext:   callCtx,
 	}, nil
 }
 
@@ -76,7 +104,7 @@ func checkAuth(authorizer facade.Authorizer, st *state.State) error {
 // Prechecks ensure that the target controller is ready to accept a
 // model migration.
 func (api *API) Prechecks(model params.MigrationModelInfo) error {
-	ownerTag, err := names.ParseUserTag(model.OwnerTag)
+	modelInfo, err := makeModelInfo(model)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -92,17 +120,58 @@ func (api *API) Prechecks(model params.MigrationModelInfo) error {
 	return migration.TargetPrecheck(
 		backend,
 		migration.PoolShim(api.pool),
-		coremigration.ModelInfo{
-			UUID:                   model.UUID,
-			Name:                   model.Name,
-			Owner:                  ownerTag,
-			AgentVersion:           model.AgentVersion,
-			ControllerAgentVersion: model.ControllerAgentVersion,
-		},
+		modelInfo,
 		api.presence.ModelPresence(controllerState.ModelUUID()),
 	)
 }
 
+// PrecheckReport runs the same checks as Prechecks, but reports every
+// problem found rather than failing at the first one.
+func (api *API) PrecheckReport(model params.MigrationModelInfo) (params.MigrationPrecheckReport, error) {
+	var result params.MigrationPrecheckReport
+	modelInfo, err := makeModelInfo(model)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	controllerState := api.pool.SystemState()
+	backend, err := migration.PrecheckShim(api.state, controllerState)
+	if err != nil {
+		return result, errors.Annotate(err, "creating backend")
+	}
+	report, err := migration.TargetPrecheckReport(
+		backend,
+		migration.PoolShim(api.pool),
+		modelInfo,
+		api.presence.ModelPresence(controllerState.ModelUUID()),
+	)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Problems = make([]params.MigrationPrecheckProblem, len(report.Problems))
+	for i, p := range report.Problems {
+		result.Problems[i] = params.MigrationPrecheckProblem{
+			Scope:   string(p.Scope),
+			Entity:  p.Entity,
+			Message: p.Message,
+		}
+	}
+	return result, nil
+}
+
+func makeModelInfo(model params.MigrationModelInfo) (coremigration.ModelInfo, error) {
+	ownerTag, err := names.ParseUserTag(model.OwnerTag)
+	if err != nil {
+		return coremigration.ModelInfo{}, errors.Trace(err)
+	}
+	return coremigration.ModelInfo{
+		UUID:                   model.UUID,
+		Name:                   model.Name,
+		Owner:                  ownerTag,
+		AgentVersion:           model.AgentVersion,
+		ControllerAgentVersion: model.ControllerAgentVersion,
+	}, nil
+}
+
 // Import takes a serialized Juju model, deserializes it, and
 // recreates it in the receiving controller.
 func (api *API) Import(serialized params.SerializedModel) error {
@@ -117,6 +186,13 @@ func (api *API) Import(serialized params.SerializedModel) error {
 	return err
 }
 
+// ValidateImport takes a serialized Juju model, deserializes it, and
+// checks that it could be imported into the receiving controller,
+// without importing it.
+func (api *API) ValidateImport(serialized params.SerializedModel) error {
+	return errors.Trace(migration.ValidateImport(api.state, serialized.Bytes))
+}
+
 func (api *API) getModel(modelTag string) (*state.Model, func(), error) {
 	tag, err := names.ParseModelTag(modelTag)
 	if err != nil {
@@ -225,6 +301,21 @@ func (api *API) AdoptResources(args params.AdoptResourcesArgs) error {
 		return errors.Trace(err)
 	}
 	defer st.Release()
+
+	m, err := st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if m.Type() == state.ModelTypeCAAS {
+		// The namespace and operators already exist in the
+		// cluster, so the broker takes them over as they are.
+		broker, err := api.getCAASBroker(st.State)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(broker.AdoptResources(api.callContext, st.ControllerUUID(), args.SourceControllerVersion))
+	}
+
 	env, err := api.getEnviron(st.State)
 	if err != nil {
 		return errors.Trace(err)
@@ -246,6 +337,15 @@ func (api *API) CheckMachines(args params.ModelArgs) (params.ErrorResults, error
 	}
 	defer st.Release()
 
+	m, err := st.Model()
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	if m.Type() == state.ModelTypeCAAS {
+		// CAAS models have no machines to check.
+		return empty, nil
+	}
+
 	machines, err := st.AllMachines()
 	if err != nil {
 		return empty, errors.Trace(err)
@@ -314,3 +414,14 @@ func (api *API) CACert() (params.BytesResult, error) {
 	caCert, _ := cfg.CACert()
 	return params.BytesResult{Result: []byte(caCert)}, nil
 }
+
+// Mask the PrecheckReport and ValidateImport methods from the v1 API.
+// The API reflection code in rpc/rpcreflect/type.go:newMethod skips
+// 2-argument methods, so this removes the methods as far as the RPC
+// machinery is concerned.
+
+// PrecheckReport isn't on the v1 API.
+func (api *APIV1) PrecheckReport(_, _ struct{}) {}
+
+// ValidateImport isn't on the v1 API.
+func (api *APIV1) ValidateImport(_, _ struct{}) {}
@@ -4,6 +4,7 @@
 package migrationtarget_test
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/juju/description"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/migrationtarget"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/caas"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/instance"
@@ -62,7 +64,7 @@ func (s *Suite) SetUpTest(c *gc.C) {
 }
 
 func (s *Suite) TestFacadeRegistered(c *gc.C) {
-	factory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 1)
+	factory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 2)
 	c.Assert(err, jc.ErrorIsNil)
 
 	api, err := factory(&facadetest.Context{
@@ -74,6 +76,19 @@ func (s *Suite) TestFacadeRegistered(c *gc.C) {
 	c.Assert(api, gc.FitsTypeOf, new(migrationtarget.API))
 }
 
+func (s *Suite) TestFacadeRegisteredV1(c *gc.C) {
+	factory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api, err := factory(&facadetest.Context{
+		State_:     s.State,
+		Resources_: s.resources,
+		Auth_:      s.authorizer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api, gc.FitsTypeOf, new(migrationtarget.APIV1))
+}
+
 func (s *Suite) TestNotUser(c *gc.C) {
 	s.authorizer.Tag = names.NewMachineTag("0")
 	_, err := s.newAPI(nil)
@@ -128,6 +143,66 @@ func (s *Suite) TestPrechecksFail(c *gc.C) {
 	c.Assert(err, gc.NotNil)
 }
 
+func (s *Suite) TestPrecheckReport(c *gc.C) {
+	api := s.mustNewAPI(c)
+	args := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "some-model",
+		OwnerTag:               names.NewUserTag("someone").String(),
+		AgentVersion:           s.controllerVersion(c),
+		ControllerAgentVersion: s.controllerVersion(c),
+	}
+	report, err := api.PrecheckReport(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Problems, gc.HasLen, 0)
+}
+
+func (s *Suite) TestPrecheckReportProblems(c *gc.C) {
+	controllerVersion := s.controllerVersion(c)
+
+	// Set the model version ahead of the controller.
+	modelVersion := controllerVersion
+	modelVersion.Minor++
+
+	api := s.mustNewAPI(c)
+	args := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "some-model",
+		OwnerTag:               names.NewUserTag("someone").String(),
+		AgentVersion:           modelVersion,
+		ControllerAgentVersion: controllerVersion,
+	}
+	report, err := api.PrecheckReport(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Problems, jc.DeepEquals, []params.MigrationPrecheckProblem{{
+		Scope:  "target-controller",
+		Entity: "model-uuid",
+		Message: fmt.Sprintf("model has higher version than target controller (%s > %s)",
+			modelVersion, controllerVersion),
+	}})
+}
+
+func (s *Suite) TestValidateImport(c *gc.C) {
+	api := s.mustNewAPI(c)
+	uuid, bytes := s.makeExportedModel(c)
+	err := api.ValidateImport(params.SerializedModel{Bytes: bytes})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing should have been imported.
+	_, _, err = s.StatePool.GetModel(uuid)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *Suite) TestValidateImportExisting(c *gc.C) {
+	api := s.mustNewAPI(c)
+	_, bytes := s.makeExportedModel(c)
+	err := api.Import(params.SerializedModel{Bytes: bytes})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = api.ValidateImport(params.SerializedModel{Bytes: bytes})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
 func (s *Suite) TestImport(c *gc.C) {
 	api := s.mustNewAPI(c)
 	tag := s.importModel(c, api)
@@ -267,6 +342,54 @@ func (s *Suite) TestAdoptResources(c *gc.C) {
 	model.Stub.CheckCall(c, 0, "AdoptResources", s.callContext, st.ControllerUUID(), version.MustParse("3.2.1"))
 }
 
+func (s *Suite) TestAdoptResourcesCAAS(c *gc.C) {
+	st := s.Factory.MakeCAASModel(c, nil)
+	defer st.Close()
+
+	broker := mockBroker{Stub: &testing.Stub{}}
+	api, err := s.newAPIWithBroker(
+		func(*state.State) (environs.Environ, error) {
+			c.Fatalf("unexpected call to get environ")
+			return nil, nil
+		},
+		func(modelSt *state.State) (caas.Broker, error) {
+			c.Assert(modelSt.ModelUUID(), gc.Equals, st.ModelUUID())
+			return &broker, nil
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = api.AdoptResources(params.AdoptResourcesArgs{
+		ModelTag:                m.ModelTag().String(),
+		SourceControllerVersion: version.MustParse("3.2.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(broker.Stub.Calls(), gc.HasLen, 1)
+	broker.Stub.CheckCall(c, 0, "AdoptResources", s.callContext, st.ControllerUUID(), version.MustParse("3.2.1"))
+}
+
+func (s *Suite) TestCheckMachinesCAAS(c *gc.C) {
+	st := s.Factory.MakeCAASModel(c, nil)
+	defer st.Close()
+
+	api, err := s.newAPI(func(*state.State) (environs.Environ, error) {
+		c.Fatalf("unexpected call to get environ")
+		return nil, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.CheckMachines(params.ModelArgs{ModelTag: m.ModelTag().String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{})
+}
+
 func (s *Suite) TestCheckMachinesInstancesMissing(c *gc.C) {
 	st := s.Factory.MakeModel(c, nil)
 	defer st.Close()
@@ -417,13 +540,20 @@ func (s *Suite) TestCheckMachinesHandlesManual(c *gc.C) {
 }
 
 func (s *Suite) newAPI(environFunc stateenvirons.NewEnvironFunc) (*migrationtarget.API, error) {
+	return s.newAPIWithBroker(environFunc, nil)
+}
+
+func (s *Suite) newAPIWithBroker(
+	environFunc stateenvirons.NewEnvironFunc,
+	brokerFunc stateenvirons.NewCAASBrokerFunc,
+) (*migrationtarget.API, error) {
 	ctx := facadetest.Context{
 		State_:     s.State,
 		StatePool_: s.StatePool,
 		Resources_: s.resources,
 		Auth_:      s.authorizer,
 	}
-	api, err := migrationtarget.NewAPI(ctx, environFunc, s.callContext)
+	api, err := migrationtarget.NewAPI(ctx, environFunc, brokerFunc, s.callContext)
 	return api, err
 }
 
@@ -485,6 +615,16 @@ func (e *mockModel) AllInstances(ctx context.ProviderCallContext) ([]instance.In
 	return results, e.NextErr()
 }
 
+type mockBroker struct {
+	caas.Broker
+	*testing.Stub
+}
+
+func (b *mockBroker) AdoptResources(ctx context.ProviderCallContext, controllerUUID string, sourceVersion version.Number) error {
+	b.MethodCall(b, "AdoptResources", ctx, controllerUUID, sourceVersion)
+	return b.NextErr()
+}
+
 type mockInstance struct {
 	instance.Instance
 	id string
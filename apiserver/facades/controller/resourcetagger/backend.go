@@ -0,0 +1,145 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+// Backend defines the methods the resource tagger facade needs from
+// state.State.
+type Backend interface {
+	state.ModelAccessor
+
+	// ControllerTag returns the tag of the controller.
+	ControllerTag() names.ControllerTag
+
+	// ModelTag returns the tag of the model.
+	ModelTag() names.ModelTag
+
+	// AllMachines returns all of the machines in the model.
+	AllMachines() ([]Machine, error)
+
+	// AllVolumes returns all of the volumes in the model.
+	AllVolumes() ([]Volume, error)
+
+	// StorageInstanceOwner returns the tag of the unit or
+	// application owning the specified storage instance.
+	StorageInstanceOwner(names.StorageTag) (names.Tag, error)
+
+	// WatchAllApplicationConfig returns a watcher that notifies
+	// of changes to the application config of any application.
+	WatchAllApplicationConfig() state.NotifyWatcher
+
+	// ApplicationResourceTags returns the value of the named
+	// application's resource-tags config.
+	ApplicationResourceTags(name string) (string, error)
+
+	// ModelStatus returns the status of the model.
+	ModelStatus() (status.StatusInfo, error)
+
+	// SetModelStatus sets the status of the model.
+	SetModelStatus(status.StatusInfo) error
+}
+
+// Machine defines the methods we need from state.Machine.
+type Machine interface {
+	Id() string
+	Life() state.Life
+	InstanceId() (instance.Id, error)
+	IsManual() (bool, error)
+	Principals() []string
+}
+
+// Volume defines the methods we need from state.Volume.
+type Volume interface {
+	VolumeTag() names.VolumeTag
+	Life() state.Life
+	Info() (state.VolumeInfo, error)
+	StorageInstance() (names.StorageTag, error)
+}
+
+type storageBackend interface {
+	AllVolumes() ([]state.Volume, error)
+	StorageInstance(names.StorageTag) (state.StorageInstance, error)
+}
+
+type backendShim struct {
+	*state.State
+	model *state.Model
+	sb    storageBackend
+}
+
+// ModelTag implements Backend.
+func (b *backendShim) ModelTag() names.ModelTag {
+	return b.model.ModelTag()
+}
+
+// AllMachines implements Backend.
+func (b *backendShim) AllMachines() ([]Machine, error) {
+	machines, err := b.State.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Machine, len(machines))
+	for i, m := range machines {
+		result[i] = m
+	}
+	return result, nil
+}
+
+// AllVolumes implements Backend.
+func (b *backendShim) AllVolumes() ([]Volume, error) {
+	volumes, err := b.sb.AllVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Volume, len(volumes))
+	for i, v := range volumes {
+		result[i] = v
+	}
+	return result, nil
+}
+
+// StorageInstanceOwner implements Backend.
+func (b *backendShim) StorageInstanceOwner(tag names.StorageTag) (names.Tag, error) {
+	si, err := b.sb.StorageInstance(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	owner, ok := si.Owner()
+	if !ok {
+		return nil, errors.NotFoundf("owner of %s", names.ReadableString(tag))
+	}
+	return owner, nil
+}
+
+// ApplicationResourceTags implements Backend.
+func (b *backendShim) ApplicationResourceTags(name string) (string, error) {
+	app, err := b.State.Application(name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	cfg, err := app.ApplicationConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return cfg.GetString(application.ResourceTagsConfigOptionName, ""), nil
+}
+
+// ModelStatus implements Backend.
+func (b *backendShim) ModelStatus() (status.StatusInfo, error) {
+	return b.model.Status()
+}
+
+// SetModelStatus implements Backend.
+func (b *backendShim) SetModelStatus(sInfo status.StatusInfo) error {
+	return b.model.SetStatus(sInfo)
+}
@@ -0,0 +1,281 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/status"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.resourcetagger")
+
+// API implements the API facade used by the resource tagger worker,
+// which reconciles the tags on a model's instances and volumes.
+type API struct {
+	*common.ModelWatcher
+
+	backend   Backend
+	resources facade.Resources
+}
+
+// NewAPI returns a new resource tagger API facade.
+func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		ModelWatcher: common.NewModelWatcher(backend, resources, authorizer),
+		backend:      backend,
+		resources:    resources,
+	}, nil
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	m, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sb, err := state.NewStorageBackend(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(&backendShim{st, m, sb}, resources, authorizer)
+}
+
+// WatchApplicationConfig returns a NotifyWatcher that notifies of
+// changes to the application config, and hence the resource-tags,
+// of the model's applications.
+func (api *API) WatchApplicationConfig() (params.NotifyWatchResult, error) {
+	watch := api.backend.WatchAllApplicationConfig()
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// TaggableResources returns the provisioned instances and volumes in
+// the model, along with the tags each should have: the model's
+// resource-tags, plus the resource-tags of the applications whose
+// units they host or whose storage they hold.
+func (api *API) TaggableResources() (params.TaggableResourcesResult, error) {
+	resources, err := api.taggableResources()
+	if err != nil {
+		return params.TaggableResourcesResult{Error: common.ServerError(err)}, nil
+	}
+	return params.TaggableResourcesResult{Resources: resources}, nil
+}
+
+func (api *API) taggableResources() ([]params.TaggableResource, error) {
+	cfg, err := api.backend.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelTags := tags.ResourceTags(api.backend.ModelTag(), api.backend.ControllerTag(), cfg)
+
+	appTags := make(map[string]map[string]string)
+	desiredTags := func(appNames ...string) (map[string]string, error) {
+		desired := make(map[string]string)
+		for _, name := range appNames {
+			resourceTags, ok := appTags[name]
+			if !ok {
+				value, err := api.backend.ApplicationResourceTags(name)
+				if errors.IsNotFound(err) {
+					// The application has gone away.
+				} else if err != nil {
+					return nil, errors.Trace(err)
+				}
+				if resourceTags, err = tags.Parse(value); err != nil {
+					logger.Warningf("ignoring invalid resource-tags for application %q: %v", name, err)
+				}
+				appTags[name] = resourceTags
+			}
+			for k, v := range resourceTags {
+				desired[k] = v
+			}
+		}
+		// Model tags are applied last so that the tags Juju relies
+		// upon can't be overridden.
+		for k, v := range modelTags {
+			desired[k] = v
+		}
+		return desired, nil
+	}
+
+	var result []params.TaggableResource
+	machines, err := api.backend.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, m := range machines {
+		if m.Life() == state.Dead || names.IsContainerMachine(m.Id()) {
+			continue
+		}
+		if manual, err := m.IsManual(); err != nil {
+			return nil, errors.Trace(err)
+		} else if manual {
+			continue
+		}
+		instId, err := m.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var appNames []string
+		for _, unitName := range m.Principals() {
+			appName, err := names.UnitApplication(unitName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			appNames = append(appNames, appName)
+		}
+		desired, err := desiredTags(appNames...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, params.TaggableResource{
+			Tag:        names.NewMachineTag(m.Id()).String(),
+			ProviderId: string(instId),
+			Tags:       desired,
+		})
+	}
+
+	volumes, err := api.backend.AllVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, v := range volumes {
+		if v.Life() == state.Dead {
+			continue
+		}
+		info, err := v.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		appName, err := api.volumeApplication(v)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var appNames []string
+		if appName != "" {
+			appNames = append(appNames, appName)
+		}
+		desired, err := desiredTags(appNames...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, params.TaggableResource{
+			Tag:        v.VolumeTag().String(),
+			ProviderId: info.VolumeId,
+			Tags:       desired,
+		})
+	}
+	return result, nil
+}
+
+// volumeApplication returns the name of the application owning the
+// storage held by the volume, or "" if there is none.
+func (api *API) volumeApplication(v Volume) (string, error) {
+	storageTag, err := v.StorageInstance()
+	if errors.IsNotAssigned(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	owner, err := api.backend.StorageInstanceOwner(storageTag)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	switch owner := owner.(type) {
+	case names.UnitTag:
+		return names.UnitApplication(owner.Id())
+	case names.ApplicationTag:
+		return owner.Id(), nil
+	}
+	return "", nil
+}
+
+// SetTagDrift records the machines and volumes whose tags were found to
+// have drifted from the desired tags in the most recent reconciliation,
+// along with the reason for any that could not be corrected. The drift
+// is reported as warnings in the model's status data, which are cleared
+// once a reconciliation finds no drift; the model's status and message
+// are left unchanged.
+func (api *API) SetTagDrift(args params.TagDriftArgs) (params.ErrorResult, error) {
+	err := api.setTagDrift(args.Drift)
+	return params.ErrorResult{Error: common.ServerError(err)}, nil
+}
+
+func (api *API) setTagDrift(drift []params.TagDrift) error {
+	warnings := make([]string, len(drift))
+	for i, d := range drift {
+		tag, err := names.ParseTag(d.Tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if d.Error != nil {
+			warnings[i] = fmt.Sprintf("cannot correct resource tags on %s: %s", names.ReadableString(tag), d.Error.Message)
+		} else {
+			warnings[i] = fmt.Sprintf("resource tags corrected on %s", names.ReadableString(tag))
+		}
+	}
+
+	current, err := api.backend.ModelStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !status.ValidModelStatus(current.Status) {
+		// The model's current status can't be set again, so the
+		// warnings can't be recorded alongside it; they will be
+		// recorded by a later reconciliation.
+		return nil
+	}
+	existing := status.TagDriftWarnings(current.Data)
+	if len(existing) == len(warnings) {
+		same := true
+		for i := range warnings {
+			if existing[i] != warnings[i] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return nil
+		}
+	}
+
+	data := make(map[string]interface{})
+	for k, v := range current.Data {
+		data[k] = v
+	}
+	delete(data, status.TagDriftKey)
+	if len(warnings) > 0 {
+		data[status.TagDriftKey] = warnings
+	}
+	return errors.Trace(api.backend.SetModelStatus(status.StatusInfo{
+		Status:  current.Status,
+		Message: current.Message,
+		Data:    data,
+		Since:   current.Since,
+	}))
+}
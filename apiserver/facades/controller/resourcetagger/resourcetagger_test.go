@@ -0,0 +1,403 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/controller/resourcetagger"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type resourceTaggerSuite struct {
+	testing.IsolationSuite
+
+	backend *mockBackend
+	api     *resourcetagger.API
+}
+
+var _ = gc.Suite(&resourceTaggerSuite{})
+
+func (s *resourceTaggerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		Stub: &testing.Stub{},
+		cfg: coretesting.CustomModelConfig(c, coretesting.Attrs{
+			"resource-tags": "owner=ops",
+		}),
+		appTags: map[string]string{
+			"wordpress": "team=web owner=me",
+			"mysql":     "tier=data",
+		},
+		status: status.StatusInfo{Status: status.Available},
+	}
+	api, err := resourcetagger.NewAPI(s.backend, nil, apiservertesting.FakeAuthorizer{Controller: true})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *resourceTaggerSuite) TestRequiresController(c *gc.C) {
+	_, err := resourcetagger.NewAPI(s.backend, nil, apiservertesting.FakeAuthorizer{Controller: false})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *resourceTaggerSuite) modelTags(extra map[string]string) map[string]string {
+	result := map[string]string{
+		"juju-model-uuid":      coretesting.ModelTag.Id(),
+		"juju-controller-uuid": coretesting.ControllerTag.Id(),
+		"owner":                "ops",
+	}
+	for k, v := range extra {
+		result[k] = v
+	}
+	return result
+}
+
+func (s *resourceTaggerSuite) TestTaggableResources(c *gc.C) {
+	s.backend.machines = []resourcetagger.Machine{
+		&mockMachine{id: "0", instId: "i-0", principals: []string{"wordpress/0", "mysql/0"}},
+		&mockMachine{id: "1"}, // not provisioned
+		&mockMachine{id: "0/lxd/0", instId: "juju-0-lxd-0", principals: []string{"mysql/1"}},
+		&mockMachine{id: "2", instId: "manual:10.0.0.1", manual: true},
+		&mockMachine{id: "3", instId: "i-3", life: state.Dead},
+		&mockMachine{id: "4", instId: "i-4"},
+	}
+	s.backend.volumes = []resourcetagger.Volume{
+		&mockVolume{tag: names.NewVolumeTag("0"), volumeId: "vol-0", storage: names.NewStorageTag("data/0")},
+		&mockVolume{tag: names.NewVolumeTag("1"), volumeId: "vol-1"},
+		&mockVolume{tag: names.NewVolumeTag("2")}, // not provisioned
+		&mockVolume{tag: names.NewVolumeTag("3"), volumeId: "vol-3", storage: names.NewStorageTag("logs/1")},
+	}
+	s.backend.owners = map[names.StorageTag]names.Tag{
+		names.NewStorageTag("data/0"): names.NewUnitTag("wordpress/0"),
+		names.NewStorageTag("logs/1"): names.NewApplicationTag("mysql"),
+	}
+
+	result, err := s.api.TaggableResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Resources, jc.DeepEquals, []params.TaggableResource{{
+		Tag:        "machine-0",
+		ProviderId: "i-0",
+		Tags:       s.modelTags(map[string]string{"team": "web", "tier": "data"}),
+	}, {
+		Tag:        "machine-4",
+		ProviderId: "i-4",
+		Tags:       s.modelTags(nil),
+	}, {
+		Tag:        "volume-0",
+		ProviderId: "vol-0",
+		Tags:       s.modelTags(map[string]string{"team": "web"}),
+	}, {
+		Tag:        "volume-1",
+		ProviderId: "vol-1",
+		Tags:       s.modelTags(nil),
+	}, {
+		Tag:        "volume-3",
+		ProviderId: "vol-3",
+		Tags:       s.modelTags(map[string]string{"tier": "data"}),
+	}})
+}
+
+func (s *resourceTaggerSuite) TestTaggableResourcesApplicationGone(c *gc.C) {
+	s.backend.machines = []resourcetagger.Machine{
+		&mockMachine{id: "0", instId: "i-0", principals: []string{"gone/0"}},
+	}
+
+	result, err := s.api.TaggableResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Resources, jc.DeepEquals, []params.TaggableResource{{
+		Tag:        "machine-0",
+		ProviderId: "i-0",
+		Tags:       s.modelTags(nil),
+	}})
+}
+
+func (s *resourceTaggerSuite) TestTaggableResourcesError(c *gc.C) {
+	s.backend.SetErrors(nil, errors.New("boom"))
+
+	result, err := s.api.TaggableResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+	s.backend.CheckCallNames(c, "ModelConfig", "AllMachines")
+}
+
+func (s *resourceTaggerSuite) TestWatchApplicationConfig(c *gc.C) {
+	changes := make(chan struct{}, 1)
+	changes <- struct{}{}
+	s.backend.appConfigWatcher = statetesting.NewMockNotifyWatcher(changes)
+	resources := common.NewResources()
+	s.AddCleanup(func(*gc.C) { resources.StopAll() })
+	api, err := resourcetagger.NewAPI(s.backend, resources, apiservertesting.FakeAuthorizer{Controller: true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := api.WatchApplicationConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+	c.Assert(resources.Get("1"), gc.Equals, s.backend.appConfigWatcher)
+	s.backend.CheckCallNames(c, "WatchAllApplicationConfig")
+}
+
+func (s *resourceTaggerSuite) TestSetTagDrift(c *gc.C) {
+	s.backend.status = status.StatusInfo{
+		Status:  status.Available,
+		Message: "something else",
+		Data:    map[string]interface{}{"other": "data"},
+	}
+
+	args := params.TagDriftArgs{Drift: []params.TagDrift{
+		{Tag: "machine-0"},
+		{Tag: "volume-1", Error: &params.Error{Message: "boom"}},
+	}}
+	result, err := s.api.SetTagDrift(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	s.backend.CheckCallNames(c, "ModelStatus", "SetModelStatus")
+	// The model's status and message are left alone.
+	c.Assert(s.backend.status, jc.DeepEquals, status.StatusInfo{
+		Status:  status.Available,
+		Message: "something else",
+		Data: map[string]interface{}{
+			"other": "data",
+			"tag-drift": []string{
+				"resource tags corrected on machine 0",
+				"cannot correct resource tags on volume 1: boom",
+			},
+		},
+	})
+
+	// Reporting the same drift again does not touch the status,
+	// even once the warnings have been read back from the database.
+	s.backend.status.Data["tag-drift"] = []interface{}{
+		"resource tags corrected on machine 0",
+		"cannot correct resource tags on volume 1: boom",
+	}
+	s.backend.ResetCalls()
+	result, err = s.api.SetTagDrift(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	s.backend.CheckCallNames(c, "ModelStatus")
+}
+
+func (s *resourceTaggerSuite) TestSetTagDriftCleared(c *gc.C) {
+	since := time.Date(2018, 9, 1, 10, 0, 0, 0, time.UTC)
+	s.backend.status = status.StatusInfo{
+		Status: status.Available,
+		Data:   map[string]interface{}{"tag-drift": []interface{}{"resource tags corrected on machine 0"}},
+		Since:  &since,
+	}
+
+	result, err := s.api.SetTagDrift(params.TagDriftArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	s.backend.CheckCallNames(c, "ModelStatus", "SetModelStatus")
+	c.Assert(s.backend.status, jc.DeepEquals, status.StatusInfo{
+		Status: status.Available,
+		Data:   map[string]interface{}{},
+		Since:  &since,
+	})
+}
+
+func (s *resourceTaggerSuite) TestSetTagDriftNoDrift(c *gc.C) {
+	s.backend.status = status.StatusInfo{
+		Status:  status.Available,
+		Message: "something else",
+	}
+
+	result, err := s.api.SetTagDrift(params.TagDriftArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	s.backend.CheckCallNames(c, "ModelStatus")
+}
+
+func (s *resourceTaggerSuite) TestSetTagDriftModelBusy(c *gc.C) {
+	s.backend.status = status.StatusInfo{Status: status.Busy, Message: "migrating"}
+
+	result, err := s.api.SetTagDrift(params.TagDriftArgs{Drift: []params.TagDrift{{Tag: "machine-0"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	s.backend.CheckCallNames(c, "ModelStatus", "SetModelStatus")
+	c.Assert(s.backend.status, jc.DeepEquals, status.StatusInfo{
+		Status:  status.Busy,
+		Message: "migrating",
+		Data: map[string]interface{}{
+			"tag-drift": []string{"resource tags corrected on machine 0"},
+		},
+	})
+}
+
+func (s *resourceTaggerSuite) TestSetTagDriftModelSuspended(c *gc.C) {
+	s.backend.status = status.StatusInfo{Status: status.Suspended}
+
+	result, err := s.api.SetTagDrift(params.TagDriftArgs{Drift: []params.TagDrift{{Tag: "machine-0"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	s.backend.CheckCallNames(c, "ModelStatus")
+}
+
+func (s *resourceTaggerSuite) TestSetTagDriftInvalidTag(c *gc.C) {
+	result, err := s.api.SetTagDrift(params.TagDriftArgs{Drift: []params.TagDrift{{Tag: "foo"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, `"foo" is not a valid tag`)
+	s.backend.CheckNoCalls(c)
+}
+
+type mockBackend struct {
+	*testing.Stub
+
+	cfg      *config.Config
+	machines []resourcetagger.Machine
+	volumes  []resourcetagger.Volume
+	owners   map[names.StorageTag]names.Tag
+	appTags  map[string]string
+	status   status.StatusInfo
+
+	appConfigWatcher state.NotifyWatcher
+}
+
+func (b *mockBackend) ModelConfig() (*config.Config, error) {
+	b.AddCall("ModelConfig")
+	return b.cfg, b.NextErr()
+}
+
+func (b *mockBackend) WatchForModelConfigChanges() state.NotifyWatcher {
+	b.AddCall("WatchForModelConfigChanges")
+	return nil
+}
+
+func (b *mockBackend) WatchAllApplicationConfig() state.NotifyWatcher {
+	b.AddCall("WatchAllApplicationConfig")
+	return b.appConfigWatcher
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) AllMachines() ([]resourcetagger.Machine, error) {
+	b.AddCall("AllMachines")
+	return b.machines, b.NextErr()
+}
+
+func (b *mockBackend) AllVolumes() ([]resourcetagger.Volume, error) {
+	b.AddCall("AllVolumes")
+	return b.volumes, b.NextErr()
+}
+
+func (b *mockBackend) StorageInstanceOwner(tag names.StorageTag) (names.Tag, error) {
+	b.AddCall("StorageInstanceOwner", tag)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	owner, ok := b.owners[tag]
+	if !ok {
+		return nil, errors.NotFoundf("owner of %s", names.ReadableString(tag))
+	}
+	return owner, nil
+}
+
+func (b *mockBackend) ApplicationResourceTags(name string) (string, error) {
+	b.AddCall("ApplicationResourceTags", name)
+	if err := b.NextErr(); err != nil {
+		return "", err
+	}
+	value, ok := b.appTags[name]
+	if !ok {
+		return "", errors.NotFoundf("application %q", name)
+	}
+	return value, nil
+}
+
+func (b *mockBackend) ModelStatus() (status.StatusInfo, error) {
+	b.AddCall("ModelStatus")
+	return b.status, b.NextErr()
+}
+
+func (b *mockBackend) SetModelStatus(sInfo status.StatusInfo) error {
+	b.AddCall("SetModelStatus", sInfo)
+	if err := b.NextErr(); err != nil {
+		return err
+	}
+	b.status = sInfo
+	return nil
+}
+
+type mockMachine struct {
+	id         string
+	life       state.Life
+	instId     instance.Id
+	manual     bool
+	principals []string
+}
+
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
+func (m *mockMachine) Life() state.Life {
+	return m.life
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if m.instId == "" {
+		return "", errors.NotProvisionedf("machine %v", m.id)
+	}
+	return m.instId, nil
+}
+
+func (m *mockMachine) IsManual() (bool, error) {
+	return m.manual, nil
+}
+
+func (m *mockMachine) Principals() []string {
+	return m.principals
+}
+
+type mockVolume struct {
+	tag      names.VolumeTag
+	life     state.Life
+	volumeId string
+	storage  names.StorageTag
+}
+
+func (v *mockVolume) VolumeTag() names.VolumeTag {
+	return v.tag
+}
+
+func (v *mockVolume) Life() state.Life {
+	return v.life
+}
+
+func (v *mockVolume) Info() (state.VolumeInfo, error) {
+	if v.volumeId == "" {
+		return state.VolumeInfo{}, errors.NotProvisionedf("volume %v", v.tag.Id())
+	}
+	return state.VolumeInfo{VolumeId: v.volumeId}, nil
+}
+
+func (v *mockVolume) StorageInstance() (names.StorageTag, error) {
+	if v.storage == (names.StorageTag{}) {
+		return names.StorageTag{}, errors.NotAssignedf("volume %v", v.tag.Id())
+	}
+	return v.storage, nil
+}
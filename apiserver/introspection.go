@@ -43,7 +43,7 @@ func (h introspectionHandler) checkAuth(r *http.Request) error {
 	// access these endpoints.
 
 	ok, err := common.HasPermission(
-		st.UserPermission,
+		common.EffectiveUserAccess(st),
 		entity.Tag(),
 		permission.SuperuserAccess,
 		st.ControllerTag(),
@@ -56,7 +56,7 @@ func (h introspectionHandler) checkAuth(r *http.Request) error {
 	}
 
 	ok, err = common.HasPermission(
-		st.UserPermission,
+		common.EffectiveUserAccess(st),
 		entity.Tag(),
 		permission.ReadAccess,
 		names.NewModelTag(st.ControllerModelUUID()),
@@ -127,6 +127,12 @@ func MakeInterestingRequestFilter(excludeMethods set.Strings) func(auditlog.Requ
 	}
 }
 
+// IsReadOnlyMethod reports whether the given facade method is one of
+// the fixed list of methods that don't change anything.
+func IsReadOnlyMethod(facadeName, methodName string) bool {
+	return readonlyMethods.Contains(facadeName + "." + methodName)
+}
+
 var readonlyMethods = set.NewStrings(
 	// Collected by running read-only commands.
 	"Action.Actions",
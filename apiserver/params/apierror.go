@@ -4,7 +4,9 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/macaroon.v2-unstable"
@@ -42,12 +44,34 @@ type ErrorInfo struct {
 	// If it is empty, the macaroon will be associated with
 	// the original URL from which the error was returned.
 	MacaroonPath string `json:"macaroon-path,omitempty"`
+
+	// RetryAfter holds how long the client should wait before
+	// retrying the call. This field is associated with the
+	// CodeRateLimitExceeded error code.
+	RetryAfter time.Duration `json:"retry-after,omitempty"`
 }
 
 func (e Error) Error() string {
 	return e.Message
 }
 
+// ErrorInfo implements rpc.ErrorInfoProvider, so that the
+// error's Info is sent to the client alongside its code.
+func (e Error) ErrorInfo() map[string]interface{} {
+	if e.Info == nil {
+		return nil
+	}
+	data, err := json.Marshal(e.Info)
+	if err != nil {
+		return nil
+	}
+	var info map[string]interface{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil
+	}
+	return info
+}
+
 func (e Error) ErrorCode() string {
 	return e.Code
 }
@@ -98,6 +122,7 @@ const (
 	CodeRedirect                  = "redirection required"
 	CodeRetry                     = "retry"
 	CodeIncompatibleSeries        = "incompatible series"
+	CodeRateLimitExceeded         = "rate limit exceeded"
 )
 
 // ErrCode returns the error code associated with
@@ -263,3 +288,7 @@ func IsCodeIncompatibleSeries(err error) bool {
 func IsCodeForbidden(err error) bool {
 	return ErrCode(err) == CodeForbidden
 }
+
+func IsCodeRateLimitExceeded(err error) bool {
+	return ErrCode(err) == CodeRateLimitExceeded
+}
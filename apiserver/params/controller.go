@@ -104,3 +104,24 @@ const (
 	GrantControllerAccess  ControllerAction = "grant"
 	RevokeControllerAccess ControllerAction = "revoke"
 )
+
+// ControllerCARotationStatus describes a controller CA rotation.
+type ControllerCARotationStatus struct {
+	// Phase is empty when no rotation is in progress.
+	Phase string `json:"phase,omitempty"`
+
+	// CACert holds the bundle of CA certificates currently
+	// trusted by the controller.
+	CACert string `json:"ca-cert"`
+
+	// NewCACert holds the CA certificate being introduced.
+	NewCACert string `json:"new-ca-cert,omitempty"`
+
+	// UntrustingAgents holds the agents, as "model-uuid:tag",
+	// that have yet to record the new CA as trusted.
+	UntrustingAgents []string `json:"untrusting-agents,omitempty"`
+
+	// PendingControllers holds the ids of the controller machines
+	// still serving a certificate issued by the old CA.
+	PendingControllers []string `json:"pending-controllers,omitempty"`
+}
@@ -354,6 +354,18 @@ type EntitiesCharmURL struct {
 	Entities []EntityCharmURL `json:"entities"`
 }
 
+// EntityCACert holds an agent tag and the CA certificates it trusts.
+type EntityCACert struct {
+	Tag    string `json:"tag"`
+	CACert string `json:"ca-cert"`
+}
+
+// EntityCACerts holds the parameters for reporting the CA certificates
+// trusted by agents.
+type EntityCACerts struct {
+	Entities []EntityCACert `json:"entities"`
+}
+
 // EntityWorkloadVersion holds the workload version for an entity.
 type EntityWorkloadVersion struct {
 	Tag             string `json:"tag"`
@@ -688,6 +700,15 @@ type ProvisioningInfo struct {
 	EndpointBindings  map[string]string         `json:"endpoint-bindings,omitempty"`
 	ControllerConfig  map[string]interface{}    `json:"controller-config,omitempty"`
 	CloudInitUserData map[string]interface{}    `json:"cloudinit-userdata,omitempty"`
+	CharmLXDProfiles  map[string]LXDProfile     `json:"charm-lxd-profiles,omitempty"`
+}
+
+// LXDProfile holds an LXD profile defined by a charm, to be applied to
+// the LXD containers and virtual machines hosting its units.
+type LXDProfile struct {
+	Description string                       `json:"description,omitempty"`
+	Config      map[string]string            `json:"config,omitempty"`
+	Devices     map[string]map[string]string `json:"devices,omitempty"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -861,3 +882,40 @@ type GoalState struct {
 	Units     UnitsGoalState            `json:"units"`
 	Relations map[string]UnitsGoalState `json:"relations"`
 }
+
+// TaggableResource holds the details of an instance or volume whose
+// tags should be reconciled with the tags Juju expects it to have.
+type TaggableResource struct {
+	// Tag is the tag of the machine or volume.
+	Tag string `json:"tag"`
+
+	// ProviderId is the provider's ID for the machine's instance,
+	// or for the volume.
+	ProviderId string `json:"provider-id"`
+
+	// Tags holds the tags the resource should have.
+	Tags map[string]string `json:"tags"`
+}
+
+// TaggableResourcesResult holds the result of a TaggableResources
+// API call.
+type TaggableResourcesResult struct {
+	Resources []TaggableResource `json:"resources"`
+	Error     *Error             `json:"error,omitempty"`
+}
+
+// TagDrift holds the outcome of reconciling the tags of a machine's
+// instance, or of a volume, whose tags had drifted.
+type TagDrift struct {
+	// Tag is the tag of the machine or volume.
+	Tag string `json:"tag"`
+
+	// Error holds the reason the tags could not be corrected,
+	// or nil if they were.
+	Error *Error `json:"error,omitempty"`
+}
+
+// TagDriftArgs holds the arguments for a SetTagDrift API call.
+type TagDriftArgs struct {
+	Drift []TagDrift `json:"drift"`
+}
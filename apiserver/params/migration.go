@@ -52,6 +52,41 @@ type InitiateMigrationResult struct {
 	MigrationId string `json:"migration-id"`
 }
 
+// MigrationPrecheckProblem describes a single failed migration
+// precheck.
+type MigrationPrecheckProblem struct {
+	// Scope indicates where the problem was found: the model, the
+	// source controller or the target controller.
+	Scope string `json:"scope"`
+
+	// Entity holds the tag of the entity the problem relates to.
+	Entity string `json:"entity"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// MigrationPrecheckReport holds all of the problems found when
+// running migration prechecks without stopping at the first failure.
+type MigrationPrecheckReport struct {
+	Problems []MigrationPrecheckProblem `json:"problems"`
+}
+
+// MigrationPrecheckResults is used to return the results of running
+// the prechecks for one or more model migrations without starting
+// them.
+type MigrationPrecheckResults struct {
+	Results []MigrationPrecheckResult `json:"results"`
+}
+
+// MigrationPrecheckResult is used to return the problems found when
+// running the prechecks for a single model migration.
+type MigrationPrecheckResult struct {
+	ModelTag string                     `json:"model-tag"`
+	Problems []MigrationPrecheckProblem `json:"problems,omitempty"`
+	Error    *Error                     `json:"error,omitempty"`
+}
+
 // SetMigrationPhaseArgs provides a migration phase to the
 // migrationmaster.SetPhase API method.
 type SetMigrationPhaseArgs struct {
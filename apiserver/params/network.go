@@ -167,6 +167,7 @@ type DeviceBridgeInfo struct {
 	HostDeviceName string `json:"host-device-name"`
 	BridgeName     string `json:"bridge-name"`
 	MACAddress     string `json:"mac-address"`
+	DeviceType     string `json:"device-type,omitempty"`
 }
 
 // ProviderInterfaceInfoResults holds the results of a
@@ -630,6 +631,29 @@ type CreateSpaceParams struct {
 	ProviderId string   `json:"provider-id,omitempty"`
 }
 
+// RenameSpacesParams holds the arguments of the RenameSpaces API call.
+type RenameSpacesParams struct {
+	Changes []RenameSpaceParams `json:"changes"`
+}
+
+// RenameSpaceParams holds the current and the new tag of a space.
+type RenameSpaceParams struct {
+	FromSpaceTag string `json:"from-space-tag"`
+	ToSpaceTag   string `json:"to-space-tag"`
+}
+
+// UpdateSpacesParams holds the arguments of the UpdateSpaces API call.
+type UpdateSpacesParams struct {
+	Spaces []UpdateSpaceParams `json:"spaces"`
+}
+
+// UpdateSpaceParams holds the space tag and the tags of the subnets
+// which replace its current subnets.
+type UpdateSpaceParams struct {
+	SpaceTag   string   `json:"space-tag"`
+	SubnetTags []string `json:"subnet-tags"`
+}
+
 // ListSpacesResults holds the list of all available spaces.
 type ListSpacesResults struct {
 	Results []Space `json:"results"`
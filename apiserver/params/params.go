@@ -347,6 +347,41 @@ type UpdateSeriesArgs struct {
 	Args []UpdateSeriesArg `json:"args"`
 }
 
+// MachineMaintenanceArg holds the parameters for putting a machine into,
+// or taking it out of, maintenance.
+type MachineMaintenanceArg struct {
+	Entity Entity `json:"tag"`
+
+	// Message describes the progress of maintenance on the machine.
+	// An empty message takes the machine out of maintenance.
+	Message string `json:"message,omitempty"`
+
+	// Replacements records units added elsewhere to replace units
+	// hosted by the machine, keyed by the name of the unit replaced.
+	Replacements map[string]string `json:"replacements,omitempty"`
+}
+
+// MachineMaintenanceArgs holds the parameters for putting one or more
+// machines into, or taking them out of, maintenance. Only known by
+// MachineManager facade version 7 or greater.
+type MachineMaintenanceArgs struct {
+	Args []MachineMaintenanceArg `json:"args"`
+}
+
+// MachineMaintenanceResult holds the units recorded as replacing those
+// hosted by a machine in maintenance, keyed by the name of the unit
+// replaced, or an error.
+type MachineMaintenanceResult struct {
+	Replacements map[string]string `json:"replacements,omitempty"`
+	Error        *Error            `json:"error,omitempty"`
+}
+
+// MachineMaintenanceResults holds the results of a SetMachineMaintenance
+// call.
+type MachineMaintenanceResults struct {
+	Results []MachineMaintenanceResult `json:"results"`
+}
+
 // ApplicationSetCharm sets the charm for a given application.
 type ApplicationSetCharm struct {
 	// ApplicationName is the name of the application to set the charm on.
@@ -406,6 +441,42 @@ type ApplicationUnset struct {
 	Options         []string `json:"options"`
 }
 
+// ModifyApplicationAccessRequest holds the parameters for making grant
+// and revoke application calls.
+type ModifyApplicationAccessRequest struct {
+	Changes []ModifyApplicationAccess `json:"changes"`
+}
+
+// ModifyApplicationAccess contains parameters to grant and revoke
+// access to an application.
+type ModifyApplicationAccess struct {
+	UserTag        string                      `json:"user-tag"`
+	Action         ApplicationAction           `json:"action"`
+	Access         ApplicationAccessPermission `json:"access"`
+	ApplicationTag string                      `json:"application-tag"`
+}
+
+// ApplicationAction is an action that can be performed on an
+// application's access.
+type ApplicationAction string
+
+// Actions that can be performed on an application's access.
+const (
+	GrantApplicationAccess  ApplicationAction = "grant"
+	RevokeApplicationAccess ApplicationAction = "revoke"
+)
+
+// ApplicationAccessPermission defines a type for an access permission
+// on an application.
+type ApplicationAccessPermission string
+
+// Access permissions that may be set on an application.
+const (
+	ApplicationAdminAccess   ApplicationAccessPermission = "admin"
+	ApplicationOperateAccess ApplicationAccessPermission = "operate"
+	ApplicationReadAccess    ApplicationAccessPermission = "read"
+)
+
 // ApplicationGet holds parameters for making the Get or
 // GetCharmURL calls.
 type ApplicationGet struct {
@@ -842,6 +913,14 @@ type ProvisioningScriptParams struct {
 	// provisioner to ensure that all the packages required by Juju
 	// are available.
 	DisablePackageCommands bool `json:"disable-package-commands"`
+
+	// DryRun, if true, causes the script to be generated for a
+	// machine with the given MachineId, Series and Arch that has
+	// not been added to the model. Nothing is recorded, so the
+	// script gives the machine agent no API password.
+	DryRun bool   `json:"dry-run,omitempty"`
+	Series string `json:"series,omitempty"`
+	Arch   string `json:"arch,omitempty"`
 }
 
 // ProvisioningScriptResult contains the result of the
@@ -940,6 +1019,16 @@ type LoginResult struct {
 	// required.
 	DischargeRequiredReason string `json:"discharge-required-error,omitempty"`
 
+	// OIDCLoginRequired implies that the login request has failed, and
+	// none of the other fields are populated. It describes the OpenID
+	// Connect issuer from which the client must obtain an ID token to
+	// pass as credentials on a subsequent call to Login.
+	OIDCLoginRequired *OIDCLoginInfo `json:"oidc-login-required,omitempty"`
+
+	// OIDCLoginRequiredReason holds the reason that the above login
+	// was required.
+	OIDCLoginRequiredReason string `json:"oidc-login-required-error,omitempty"`
+
 	// Servers is the list of API server addresses.
 	Servers [][]HostPort `json:"servers,omitempty"`
 
@@ -965,6 +1054,18 @@ type LoginResult struct {
 	// ServerVersion is the string representation of the server version
 	// if the server supports it.
 	ServerVersion string `json:"server-version,omitempty"`
+
+	// ControllerCACert holds the bundle of CA certificates trusted by
+	// the controller, so user clients keep up with CA rotation. It is
+	// only set for user logins.
+	ControllerCACert string `json:"controller-ca-cert,omitempty"`
+}
+
+// OIDCLoginInfo describes the OpenID Connect issuer that a user
+// must log in with.
+type OIDCLoginInfo struct {
+	IssuerURL string `json:"issuer-url"`
+	ClientID  string `json:"client-id"`
 }
 
 // ControllersServersSpec contains arguments for
@@ -1161,6 +1262,45 @@ type ResumeReplicationParams struct {
 	Members []replicaset.Member `json:"members"`
 }
 
+// ControllerMember holds the replica set details of one
+// controller machine.
+type ControllerMember struct {
+	// Tag is the tag of the controller machine.
+	Tag string `json:"tag"`
+
+	// WantsVote and HasVote report whether the machine wants,
+	// and has, a vote in the replica set.
+	WantsVote bool `json:"wants-vote"`
+	HasVote   bool `json:"has-vote"`
+
+	// MongoState is the state of the machine's replica set
+	// member (e.g. "PRIMARY" or "SECONDARY"). It is empty
+	// if the machine is not yet a member.
+	MongoState string `json:"mongo-state,omitempty"`
+
+	// Healthy reports whether the replica set considers the
+	// member healthy.
+	Healthy bool `json:"healthy"`
+
+	// OptimeLag is how far, in seconds, the member's oplog
+	// is behind the primary's.
+	OptimeLag float64 `json:"optime-lag"`
+
+	// APIAddress is the address of the machine's API server.
+	APIAddress string `json:"api-address,omitempty"`
+
+	// RaftRole is the machine's role in the raft cluster,
+	// "leader" or "follower". It is empty if the raft leader
+	// is not known.
+	RaftRole string `json:"raft-role,omitempty"`
+}
+
+// ControllerMembersResult holds the members of the
+// controller's replica set.
+type ControllerMembersResult struct {
+	Members []ControllerMember `json:"members"`
+}
+
 // MeterStatusParam holds meter status information to be set for the specified tag.
 type MeterStatusParam struct {
 	Tag  string `json:"tag"`
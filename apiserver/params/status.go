@@ -52,6 +52,7 @@ type ModelStatusInfo struct {
 	ModelStatus      DetailedStatus `json:"model-status"`
 	MeterStatus      MeterStatus    `json:"meter-status"`
 	SLA              string         `json:"sla"`
+	Warnings         []string       `json:"warnings,omitempty"`
 }
 
 // NetworkInterfaceStatus holds a /etc/network/interfaces-type data and the
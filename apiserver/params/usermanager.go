@@ -67,3 +67,87 @@ type AddUserResult struct {
 	SecretKey []byte `json:"secret-key,omitempty"`
 	Error     *Error `json:"error,omitempty"`
 }
+
+// AddGroups holds the parameters for adding user groups.
+type AddGroups struct {
+	Groups []AddGroup `json:"groups"`
+}
+
+// AddGroup stores the parameters to add one user group.
+type AddGroup struct {
+	Name string `json:"name"`
+}
+
+// AddGroupMembers holds the parameters for adding users to groups.
+type AddGroupMembers struct {
+	Members []AddGroupMember `json:"members"`
+}
+
+// AddGroupMember stores the parameters to add one user to a group.
+type AddGroupMember struct {
+	Group   string `json:"group"`
+	UserTag string `json:"user-tag"`
+}
+
+// AddAPITokens holds the parameters for adding API tokens.
+type AddAPITokens struct {
+	Tokens []AddAPIToken `json:"tokens"`
+}
+
+// AddAPIToken stores the parameters to add one API token.
+type AddAPIToken struct {
+	UserTag     string    `json:"user-tag"`
+	Description string    `json:"description,omitempty"`
+	Expires     time.Time `json:"expires"`
+
+	// Models optionally restricts the token to the models
+	// with the given names or UUIDs.
+	Models []string `json:"models,omitempty"`
+
+	// FacadeGroups optionally restricts the token to the
+	// facade method groups with the given names.
+	FacadeGroups []string `json:"facade-groups,omitempty"`
+}
+
+// AddAPITokenResults holds the results of the bulk AddAPIToken call.
+type AddAPITokenResults struct {
+	Results []AddAPITokenResult `json:"results"`
+}
+
+// AddAPITokenResult holds the id of a newly added API token and
+// the credentials to log in with it, or an error. The credentials
+// cannot be retrieved again later.
+type AddAPITokenResult struct {
+	Id          string `json:"id,omitempty"`
+	Credentials string `json:"credentials,omitempty"`
+	Error       *Error `json:"error,omitempty"`
+}
+
+// APITokensRequest defines the API tokens to return. If All is
+// set, the tokens of all users are returned, otherwise only those
+// of the authenticated user.
+type APITokensRequest struct {
+	All bool `json:"all,omitempty"`
+}
+
+// APITokenInfo holds information on an API token.
+type APITokenInfo struct {
+	Id           string    `json:"id"`
+	UserTag      string    `json:"user-tag"`
+	Description  string    `json:"description,omitempty"`
+	CreatedBy    string    `json:"created-by"`
+	DateCreated  time.Time `json:"date-created"`
+	Expires      time.Time `json:"expires"`
+	Models       []string  `json:"models,omitempty"`
+	FacadeGroups []string  `json:"facade-groups,omitempty"`
+}
+
+// APITokenInfoResults holds the result of an APITokens call.
+type APITokenInfoResults struct {
+	Results []APITokenInfo `json:"results"`
+}
+
+// RevokeAPITokens holds the ids of the API tokens to revoke.
+type RevokeAPITokens struct {
+	Ids []string `json:"ids"`
+}
@@ -0,0 +1,216 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/ratelimit"
+	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+)
+
+// userIdleTimeout is how long a user must have made no calls before
+// the call limiter forgets their buckets and call counts.
+const userIdleTimeout = 10 * time.Minute
+
+// callLimiter enforces the per user, per connection and per facade
+// method group limits on the API calls made by users. It is also a
+// prometheus.Collector, reporting the calls allowed and throttled
+// for each user.
+type callLimiter struct {
+	config RateLimitConfig
+	clock  ratelimit.Clock
+
+	// mu guards the fields below, and serialises checks
+	// so that each call takes from all of its buckets or
+	// from none of them.
+	mu     sync.Mutex
+	users  map[string]*userCallBuckets
+	pruned time.Time
+
+	calls *prometheus.CounterVec
+}
+
+// userCallBuckets holds the token buckets shared by all of
+// a user's connections.
+type userCallBuckets struct {
+	all      *ratelimit.Bucket
+	groups   map[string]*ratelimit.Bucket
+	lastCall time.Time
+}
+
+func newCallLimiter(config RateLimitConfig, clock clock.Clock) *callLimiter {
+	return &callLimiter{
+		config: config,
+		clock:  ratelimitClock{clock},
+		users:  make(map[string]*userCallBuckets),
+		pruned: clock.Now(),
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: apiserverMetricsNamespace,
+			Name:      "user_calls_total",
+			Help:      "Total number of API calls made by each user, by whether they were allowed or throttled",
+		}, []string{"user", "result"}),
+	}
+}
+
+// enabled reports whether any call limits are configured.
+func (l *callLimiter) enabled() bool {
+	return l.config.UserCallRate > 0 ||
+		l.config.ConnCallRate > 0 ||
+		len(l.config.FacadeGroupCallLimits) > 0
+}
+
+// check returns a check, for use with restrictRoot, that limits the
+// calls made on a new connection for the given user.
+func (l *callLimiter) check(user names.UserTag) func(string, string) error {
+	var conn *ratelimit.Bucket
+	if l.config.ConnCallRate > 0 {
+		conn = l.newBucket(CallLimit{l.config.ConnCallRate, l.config.ConnCallBurst})
+	}
+	return func(facadeName, methodName string) error {
+		if facadeName == "Pinger" {
+			// Pings keep the connection alive; never refuse them.
+			return nil
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		now := l.clock.Now()
+		if now.Sub(l.pruned) >= userIdleTimeout {
+			l.pruneIdleUsers(now)
+		}
+		buckets := []*ratelimit.Bucket{conn}
+		buckets = append(buckets, l.userBuckets(user, facadeName, methodName, now)...)
+
+		// Check every bucket before taking from any, so that
+		// a throttled call doesn't count against the limits
+		// it didn't exceed.
+		var retryAfter time.Duration
+		for _, bucket := range buckets {
+			if bucket == nil || bucket.Available() >= 1 {
+				continue
+			}
+			if wait := tokenInterval(bucket); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+		if retryAfter > 0 {
+			l.calls.WithLabelValues(user.Id(), "throttled").Inc()
+			logger.Debugf("throttling %s call to %s.%s", user.Id(), facadeName, methodName)
+			return &common.RateLimitExceededError{RetryAfter: roundRetryAfter(retryAfter)}
+		}
+		for _, bucket := range buckets {
+			if bucket != nil {
+				bucket.TakeAvailable(1)
+			}
+		}
+		l.calls.WithLabelValues(user.Id(), "allowed").Inc()
+		return nil
+	}
+}
+
+// userBuckets returns the user's buckets that apply to a call of
+// the given method, creating them if needed. It must be called
+// with l.mu held.
+func (l *callLimiter) userBuckets(user names.UserTag, facadeName, methodName string, now time.Time) []*ratelimit.Bucket {
+	ub, ok := l.users[user.Id()]
+	if !ok {
+		ub = &userCallBuckets{groups: make(map[string]*ratelimit.Bucket)}
+		if l.config.UserCallRate > 0 {
+			ub.all = l.newBucket(CallLimit{l.config.UserCallRate, l.config.UserCallBurst})
+		}
+		l.users[user.Id()] = ub
+	}
+	ub.lastCall = now
+	buckets := []*ratelimit.Bucket{ub.all}
+	if len(l.config.FacadeGroupCallLimits) == 0 {
+		return buckets
+	}
+	for _, group := range authentication.FacadeMethodGroups(facadeName, methodName) {
+		limit, ok := l.config.FacadeGroupCallLimits[group]
+		if !ok || limit.Rate <= 0 {
+			continue
+		}
+		bucket, ok := ub.groups[group]
+		if !ok {
+			bucket = l.newBucket(limit)
+			ub.groups[group] = bucket
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// pruneIdleUsers forgets the buckets and call counts of users who
+// have made no calls for userIdleTimeout, and whose buckets have
+// refilled, so that new buckets would allow them the same calls.
+// It must be called with l.mu held.
+func (l *callLimiter) pruneIdleUsers(now time.Time) {
+	l.pruned = now
+	for user, ub := range l.users {
+		if now.Sub(ub.lastCall) < userIdleTimeout || !ub.full() {
+			continue
+		}
+		delete(l.users, user)
+		l.calls.DeleteLabelValues(user, "allowed")
+		l.calls.DeleteLabelValues(user, "throttled")
+	}
+}
+
+// full reports whether all of the user's buckets are full.
+func (ub *userCallBuckets) full() bool {
+	if ub.all != nil && ub.all.Available() < ub.all.Capacity() {
+		return false
+	}
+	for _, bucket := range ub.groups {
+		if bucket.Available() < bucket.Capacity() {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *callLimiter) newBucket(limit CallLimit) *ratelimit.Bucket {
+	return ratelimit.NewBucketWithRateAndClock(limit.Rate, int64(limit.Burst), l.clock)
+}
+
+// tokenInterval returns the time the bucket takes to gain a token,
+// which bounds how long an empty bucket leaves a call waiting.
+func tokenInterval(bucket *ratelimit.Bucket) time.Duration {
+	return time.Duration(float64(time.Second) / bucket.Rate())
+}
+
+// roundRetryAfter rounds the time to wait before retrying a call
+// up to a whole number of milliseconds, so it reads well in errors.
+func roundRetryAfter(d time.Duration) time.Duration {
+	if rem := d % time.Millisecond; rem != 0 {
+		d += time.Millisecond - rem
+	}
+	return d
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (l *callLimiter) Describe(ch chan<- *prometheus.Desc) {
+	l.calls.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (l *callLimiter) Collect(ch chan<- prometheus.Metric) {
+	l.calls.Collect(ch)
+}
+
+// ratelimitClock adapts clock.Clock to ratelimit.Clock.
+type ratelimitClock struct {
+	clock.Clock
+}
+
+// Sleep is defined by the ratelimit.Clock interface.
+func (c ratelimitClock) Sleep(d time.Duration) {
+	<-c.Clock.After(d)
+}
@@ -0,0 +1,161 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type callLimiterSuite struct {
+	coretesting.BaseSuite
+	clock *testing.Clock
+	bob   names.UserTag
+}
+
+var _ = gc.Suite(&callLimiterSuite{})
+
+func (s *callLimiterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Now())
+	s.bob = names.NewUserTag("bob")
+}
+
+func (s *callLimiterSuite) assertThrottled(c *gc.C, err error, retryAfter time.Duration) {
+	c.Assert(err, jc.Satisfies, common.IsRateLimitExceededError)
+	c.Assert(errors.Cause(err).(*common.RateLimitExceededError).RetryAfter, gc.Equals, retryAfter)
+}
+
+func (s *callLimiterSuite) TestUserLimit(c *gc.C) {
+	_, newCheck := apiserver.NewCallLimiterForTest(apiserver.RateLimitConfig{
+		UserCallRate:  1,
+		UserCallBurst: 2,
+	}, s.clock)
+	conn1, conn2 := newCheck(s.bob), newCheck(s.bob)
+	c.Assert(conn1("Client", "FullStatus"), jc.ErrorIsNil)
+	c.Assert(conn2("Client", "FullStatus"), jc.ErrorIsNil)
+	s.assertThrottled(c, conn1("Client", "FullStatus"), time.Second)
+
+	// Pings are never throttled.
+	c.Assert(conn1("Pinger", "Ping"), jc.ErrorIsNil)
+
+	// Other users have their own limit.
+	c.Assert(newCheck(names.NewUserTag("alice"))("Client", "FullStatus"), jc.ErrorIsNil)
+
+	s.clock.Advance(time.Second)
+	c.Assert(conn2("Client", "FullStatus"), jc.ErrorIsNil)
+}
+
+func (s *callLimiterSuite) TestConnLimit(c *gc.C) {
+	_, newCheck := apiserver.NewCallLimiterForTest(apiserver.RateLimitConfig{
+		ConnCallRate:  2,
+		ConnCallBurst: 1,
+	}, s.clock)
+	conn1, conn2 := newCheck(s.bob), newCheck(s.bob)
+	c.Assert(conn1("Client", "FullStatus"), jc.ErrorIsNil)
+	s.assertThrottled(c, conn1("Client", "FullStatus"), 500*time.Millisecond)
+	c.Assert(conn2("Client", "FullStatus"), jc.ErrorIsNil)
+}
+
+func (s *callLimiterSuite) TestFacadeGroupLimit(c *gc.C) {
+	_, newCheck := apiserver.NewCallLimiterForTest(apiserver.RateLimitConfig{
+		FacadeGroupCallLimits: map[string]apiserver.CallLimit{
+			"status": {Rate: 0.5, Burst: 1},
+		},
+	}, s.clock)
+	conn := newCheck(s.bob)
+	c.Assert(conn("Client", "FullStatus"), jc.ErrorIsNil)
+	s.assertThrottled(c, conn("Client", "FullStatus"), 2*time.Second)
+	s.assertThrottled(c, newCheck(s.bob)("Client", "StatusHistory"), 2*time.Second)
+	c.Assert(conn("Action", "Enqueue"), jc.ErrorIsNil)
+	c.Assert(conn("Action", "Enqueue"), jc.ErrorIsNil)
+}
+
+func (s *callLimiterSuite) TestThrottledCallTakesNothing(c *gc.C) {
+	_, newCheck := apiserver.NewCallLimiterForTest(apiserver.RateLimitConfig{
+		UserCallRate:  1,
+		UserCallBurst: 2,
+		FacadeGroupCallLimits: map[string]apiserver.CallLimit{
+			"status": {Rate: 1, Burst: 1},
+		},
+	}, s.clock)
+	conn := newCheck(s.bob)
+	c.Assert(conn("Client", "FullStatus"), jc.ErrorIsNil)
+	s.assertThrottled(c, conn("Client", "FullStatus"), time.Second)
+
+	// The throttled status call didn't use up bob's user limit.
+	c.Assert(conn("Action", "Enqueue"), jc.ErrorIsNil)
+	s.assertThrottled(c, conn("Action", "Enqueue"), time.Second)
+}
+
+func (s *callLimiterSuite) TestMetrics(c *gc.C) {
+	collector, newCheck := apiserver.NewCallLimiterForTest(apiserver.RateLimitConfig{
+		UserCallRate:  1,
+		UserCallBurst: 1,
+	}, s.clock)
+	conn := newCheck(s.bob)
+	c.Assert(conn("Client", "FullStatus"), jc.ErrorIsNil)
+	c.Assert(conn("Client", "FullStatus"), gc.NotNil)
+	c.Assert(conn("Client", "FullStatus"), gc.NotNil)
+
+	c.Assert(s.collect(c, collector), jc.DeepEquals, map[string]float64{
+		"bob/allowed":   1,
+		"bob/throttled": 2,
+	})
+}
+
+func (s *callLimiterSuite) TestIdleUsersPruned(c *gc.C) {
+	collector, newCheck := apiserver.NewCallLimiterForTest(apiserver.RateLimitConfig{
+		UserCallRate:  1,
+		UserCallBurst: 1,
+	}, s.clock)
+	bob, alice := newCheck(s.bob), newCheck(names.NewUserTag("alice"))
+	c.Assert(bob("Client", "FullStatus"), jc.ErrorIsNil)
+	s.clock.Advance(apiserver.UserIdleTimeout / 2)
+	c.Assert(alice("Client", "FullStatus"), jc.ErrorIsNil)
+
+	// Only bob has been idle for long enough to be forgotten.
+	s.clock.Advance(apiserver.UserIdleTimeout / 2)
+	c.Assert(alice("Client", "FullStatus"), jc.ErrorIsNil)
+	c.Assert(s.collect(c, collector), jc.DeepEquals, map[string]float64{
+		"alice/allowed": 2,
+	})
+
+	// Bob starts afresh with full buckets.
+	c.Assert(bob("Client", "FullStatus"), jc.ErrorIsNil)
+	s.assertThrottled(c, bob("Client", "FullStatus"), time.Second)
+}
+
+// collect returns the call counts reported by the collector,
+// keyed by user and result.
+func (s *callLimiterSuite) collect(c *gc.C, collector prometheus.Collector) map[string]float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		defer close(ch)
+		collector.Collect(ch)
+	}()
+	counts := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		err := metric.Write(&m)
+		c.Assert(err, jc.ErrorIsNil)
+		labels := make(map[string]string)
+		for _, label := range m.Label {
+			labels[label.GetName()] = label.GetValue()
+		}
+		counts[labels["user"]+"/"+labels["result"]] = m.Counter.GetValue()
+	}
+	return counts
+}
@@ -0,0 +1,22 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/authentication"
+)
+
+// apiTokenMethodsOnly returns a check that only allows calls to the
+// methods in the given facade method groups, for connections that
+// logged in with an API token.
+func apiTokenMethodsOnly(groups []string) func(string, string) error {
+	return func(facadeName, methodName string) error {
+		if !authentication.IsAPITokenMethodAllowed(groups, facadeName, methodName) {
+			return errors.NewNotSupported(nil, facadeName+"."+methodName+" not allowed for this API token")
+		}
+		return nil
+	}
+}
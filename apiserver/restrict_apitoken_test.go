@@ -0,0 +1,49 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type RestrictAPITokenSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&RestrictAPITokenSuite{})
+
+func (s *RestrictAPITokenSuite) TestUnrestricted(c *gc.C) {
+	root := apiserver.TestingAPITokenRoot()
+	caller, err := root.FindMethod("Application", 8, "Deploy")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+	caller, err = root.FindMethod("UserManager", 4, "AddAPIToken")
+	c.Check(err, gc.ErrorMatches, `UserManager.AddAPIToken not allowed for this API token`)
+	c.Check(errors.IsNotSupported(err), jc.IsTrue)
+}
+
+func (s *RestrictAPITokenSuite) TestGroups(c *gc.C) {
+	root := apiserver.TestingAPITokenRoot("read-only", "actions")
+	for _, method := range []struct {
+		facade  string
+		version int
+		name    string
+	}{
+		{"Pinger", 1, "Ping"},
+		{"Client", 1, "FullStatus"},
+		{"Action", 2, "Enqueue"},
+	} {
+		caller, err := root.FindMethod(method.facade, method.version, method.name)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+	caller, err := root.FindMethod("Application", 8, "Deploy")
+	c.Check(err, gc.ErrorMatches, `Application.Deploy not allowed for this API token`)
+	c.Check(caller, gc.IsNil)
+}
@@ -189,6 +189,11 @@ func restrictAPIRoot(
 	model *state.Model,
 	auth authResult,
 ) (rpc.Root, error) {
+	if auth.userTag != (names.UserTag{}) && srv.callLimiter.enabled() {
+		// Limit the calls users make. This is applied first so
+		// that calls refused for other reasons aren't counted.
+		apiRoot = restrictRoot(apiRoot, srv.callLimiter.check(auth.userTag))
+	}
 	if !auth.controllerMachineLogin {
 		// Controller agents are allowed to
 		// connect even during maintenance.
@@ -208,6 +213,9 @@ func restrictAPIRoot(
 			apiRoot = restrictRoot(apiRoot, caasModelFacadesOnly)
 		}
 	}
+	if auth.apiToken != nil {
+		apiRoot = restrictRoot(apiRoot, apiTokenMethodsOnly(auth.apiToken.FacadeGroups()))
+	}
 	return apiRoot, nil
 }
 
@@ -417,6 +425,11 @@ func (ctx *facadeContext) Hub() facade.Hub {
 	return ctx.r.shared.centralHub
 }
 
+// RaftLeader implements facade.Context.
+func (ctx *facadeContext) RaftLeader() string {
+	return ctx.r.shared.raftLeader()
+}
+
 // State is part of of the facade.Context interface.
 func (ctx *facadeContext) State() *state.State {
 	return ctx.r.state
@@ -523,12 +536,12 @@ func (r *apiHandler) ConnectedModel() string {
 
 // HasPermission returns true if the logged in user can perform <operation> on <target>.
 func (r *apiHandler) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
-	return common.HasPermission(r.state.UserPermission, r.entity.Tag(), operation, target)
+	return common.HasPermission(common.EffectiveUserAccess(r.state), r.entity.Tag(), operation, target)
 }
 
 // UserHasPermission returns true if the passed in user can perform <operation> on <target>.
 func (r *apiHandler) UserHasPermission(user names.UserTag, operation permission.Access, target names.Tag) (bool, error) {
-	return common.HasPermission(r.state.UserPermission, user, operation, target)
+	return common.HasPermission(common.EffectiveUserAccess(r.state), user, operation, target)
 }
 
 // DescribeFacades returns the list of available Facades and their Versions
@@ -32,6 +32,9 @@ type sharedServerContext struct {
 	featuresMutex sync.RWMutex
 	features      set.Strings
 
+	raftLeaderMutex sync.RWMutex
+	raftLeaderId    string
+
 	unsubscribe func()
 }
 
@@ -74,11 +77,31 @@ func newSharedServerContex(config sharedServerConfig) (*sharedServerContext, err
 	// because the changes are only ever published in response to an API call, and
 	// this function is called in the newServer call to create the API server,
 	// and we know that we can't make any API calls until the server has started.
-	ctx.unsubscribe, err = ctx.centralHub.Subscribe(controller.ConfigChanged, ctx.onConfigChanged)
+	unsubscribeConfig, err := ctx.centralHub.Subscribe(controller.ConfigChanged, ctx.onConfigChanged)
+	if err != nil {
+		ctx.logger.Criticalf("programming error in subscribe function: %v", err)
+		return nil, errors.Trace(err)
+	}
+	unsubscribeRaftLeader, err := ctx.centralHub.Subscribe(controller.RaftLeaderTopic, ctx.onRaftLeaderChanged)
 	if err != nil {
+		unsubscribeConfig()
 		ctx.logger.Criticalf("programming error in subscribe function: %v", err)
 		return nil, errors.Trace(err)
 	}
+	ctx.unsubscribe = func() {
+		unsubscribeConfig()
+		unsubscribeRaftLeader()
+	}
+	// The raft worker may have published the leader before we
+	// subscribed, so ask for it now.
+	req := controller.RaftLeaderRequest{
+		Requester: "apiserver",
+		LocalOnly: true,
+	}
+	if _, err := ctx.centralHub.Publish(controller.RaftLeaderRequestTopic, req); err != nil {
+		ctx.unsubscribe()
+		return nil, errors.Annotate(err, "requesting raft leader")
+	}
 	return ctx, nil
 }
 
@@ -86,6 +109,24 @@ func (c *sharedServerContext) Close() {
 	c.unsubscribe()
 }
 
+func (c *sharedServerContext) onRaftLeaderChanged(topic string, data controller.RaftLeaderMessage, err error) {
+	if err != nil {
+		c.logger.Criticalf("programming error in %s message data: %v", topic, err)
+		return
+	}
+	c.raftLeaderMutex.Lock()
+	c.raftLeaderId = data.Leader
+	c.raftLeaderMutex.Unlock()
+}
+
+// raftLeader returns the id of the controller machine that the local
+// raft worker last reported as the raft leader, or "" if none is known.
+func (c *sharedServerContext) raftLeader() string {
+	c.raftLeaderMutex.RLock()
+	defer c.raftLeaderMutex.RUnlock()
+	return c.raftLeaderId
+}
+
 func (c *sharedServerContext) onConfigChanged(topic string, data controller.ConfigChangedMessage, err error) {
 	if err != nil {
 		c.logger.Criticalf("programming error in %s message data: %v", topic, err)
@@ -107,3 +107,44 @@ func (s *sharedServerContextSuite) TestControllerConfigChanged(c *gc.C) {
 	c.Check(ctx.featureEnabled("bar"), jc.IsTrue)
 	c.Check(ctx.featureEnabled("baz"), jc.IsFalse)
 }
+
+func (s *sharedServerContextSuite) TestRaftLeaderChanged(c *gc.C) {
+	ctx := s.newContext(c)
+	c.Check(ctx.raftLeader(), gc.Equals, "")
+
+	msg := controller.RaftLeaderMessage{Leader: "2", LocalOnly: true}
+	done, err := s.hub.Publish(controller.RaftLeaderTopic, msg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("handler didn't")
+	}
+
+	c.Check(ctx.raftLeader(), gc.Equals, "2")
+}
+
+func (s *sharedServerContextSuite) TestRequestsRaftLeader(c *gc.C) {
+	reqs := make(chan controller.RaftLeaderRequest, 1)
+	unsubscribe, err := s.hub.Subscribe(
+		controller.RaftLeaderRequestTopic,
+		func(topic string, req controller.RaftLeaderRequest, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			reqs <- req
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+
+	s.newContext(c)
+	select {
+	case req := <-reqs:
+		c.Assert(req, gc.Equals, controller.RaftLeaderRequest{
+			Requester: "apiserver",
+			LocalOnly: true,
+		})
+	case <-time.After(testing.LongWait):
+		c.Fatalf("raft leader not requested")
+	}
+}
@@ -84,6 +84,12 @@ func (a *Authenticator) Authenticate(req *http.Request) (httpcontext.AuthInfo, e
 	if err != nil {
 		return httpcontext.AuthInfo{}, errors.Trace(err)
 	}
+	if authentication.IsAPITokenCredentials(loginRequest.Credentials) {
+		// An API token's model and facade restrictions can only be
+		// enforced on API connections, so tokens may not be used
+		// for HTTP requests.
+		return httpcontext.AuthInfo{}, errors.NewUnauthorized(nil, "API tokens cannot be used for HTTP requests")
+	}
 	return a.AuthenticateLoginRequest(req.Host, modelUUID, loginRequest)
 }
 
@@ -113,7 +119,7 @@ func (a *Authenticator) AuthenticateLoginRequest(
 	authenticator := a.authContext.authenticator(serverHost)
 	authInfo, err := a.checkCreds(st.State, req, authTag, true, authenticator)
 	if err != nil {
-		if common.IsDischargeRequiredError(err) || errors.IsNotProvisioned(err) {
+		if common.IsDischargeRequiredError(err) || common.IsOIDCLoginRequiredError(err) || errors.IsNotProvisioned(err) {
 			// TODO(axw) move out of common?
 			return httpcontext.AuthInfo{}, errors.Trace(err)
 		}
@@ -4,14 +4,21 @@
 package stateauthenticator_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/httpcontext"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/stateauthenticator"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing/factory"
@@ -70,6 +77,84 @@ func (s *agentAuthenticatorSuite) TestNotSupportedTag(c *gc.C) {
 	c.Assert(authenticator, gc.IsNil)
 }
 
+func (s *agentAuthenticatorSuite) TestOIDCUserNotConfigured(c *gc.C) {
+	authenticator, err := stateauthenticator.EntityAuthenticator(s.authenticator, names.NewUserTag("alice@oidc"))
+	c.Assert(err, gc.ErrorMatches, "OIDC login is not configured: invalid request")
+	c.Assert(authenticator, gc.IsNil)
+}
+
+func (s *agentAuthenticatorSuite) TestOIDCUserGetsOIDCAuthenticator(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.OIDCIssuerURL: "https://issuer.example.com",
+		controller.OIDCClientID:  "juju",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	authenticator, err := stateauthenticator.EntityAuthenticator(s.authenticator, names.NewUserTag("alice@oidc"))
+	c.Assert(err, jc.ErrorIsNil)
+	oidcAuth, ok := authenticator.(*authentication.OIDCAuthenticator)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(oidcAuth.Verifier.Config(), jc.DeepEquals, authentication.OIDCConfig{
+		IssuerURL:     "https://issuer.example.com",
+		ClientID:      "juju",
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+	})
+
+	// Other external users are still authenticated as before.
+	authenticator, err = stateauthenticator.EntityAuthenticator(s.authenticator, names.NewUserTag("bob@external"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = authenticator.(*authentication.UserAuthenticator)
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *agentAuthenticatorSuite) authenticateHTTP(tag names.Tag, password string) (httpcontext.AuthInfo, error) {
+	var (
+		authInfo httpcontext.AuthInfo
+		err      error
+	)
+	handler := &httpcontext.ImpliedModelHandler{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			authInfo, err = s.authenticator.Authenticate(req)
+		}),
+		ModelUUID: s.State.ModelUUID(),
+	}
+	req := httptest.NewRequest("GET", "/charms", nil)
+	req.SetBasicAuth(tag.String(), password)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return authInfo, err
+}
+
+func (s *agentAuthenticatorSuite) TestHTTPPassword(c *gc.C) {
+	fact := factory.NewFactory(s.State)
+	user := fact.MakeUser(c, &factory.UserParams{Password: "password"})
+
+	authInfo, err := s.authenticateHTTP(user.Tag(), "password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authInfo.Entity.Tag(), gc.Equals, user.Tag())
+}
+
+func (s *agentAuthenticatorSuite) TestHTTPAPITokenRejected(c *gc.C) {
+	fact := factory.NewFactory(s.State)
+	user := fact.MakeUser(c, &factory.UserParams{Password: "password"})
+	token, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		User:      user.UserTag(),
+		CreatedBy: s.Owner,
+		Expires:   time.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.authenticateHTTP(user.Tag(), authentication.APITokenCredentials(token.Id(), secret))
+	c.Assert(err, gc.ErrorMatches, "API tokens cannot be used for HTTP requests")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	// The token was not used, so no login is recorded for the user.
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = user.LastLogin()
+	c.Assert(err, jc.Satisfies, state.IsNeverLoggedInError)
+}
+
 type userFinder struct {
 	user state.Entity
 }
@@ -4,8 +4,10 @@
 package stateauthenticator
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/juju/errors"
@@ -53,6 +55,11 @@ type authContext struct {
 	macaroonAuthOnce   sync.Once
 	_macaroonAuth      *authentication.ExternalMacaroonAuthenticator
 	_macaroonAuthError error
+
+	// oidcMu guards the OIDC verifier, which is replaced
+	// whenever the controller's OIDC configuration changes.
+	oidcMu        sync.Mutex
+	_oidcVerifier *authentication.OIDCVerifier
 }
 
 // newAuthContext creates a new authentication context for st.
@@ -140,6 +147,9 @@ func (a authenticator) Authenticate(
 	tag names.Tag,
 	req params.LoginRequest,
 ) (state.Entity, error) {
+	if _, ok := tag.(names.UserTag); ok && authentication.IsAPITokenCredentials(req.Credentials) {
+		return a.ctxt.apiTokenAuth().Authenticate(entityFinder, tag, req)
+	}
 	auth, err := a.authenticatorForTag(tag)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -164,6 +174,13 @@ func (a authenticator) authenticatorForTag(tag names.Tag) (authentication.Entity
 	case names.UnitTagKind, names.MachineTagKind, names.ApplicationTagKind:
 		return &a.ctxt.agentAuth, nil
 	case names.UserTagKind:
+		if tag.(names.UserTag).Domain() == authentication.OIDCUserDomain {
+			auth, err := a.ctxt.oidcAuth()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return auth, nil
+		}
 		return a.localUserAuth(), nil
 	default:
 		return nil, errors.Annotatef(common.ErrBadRequest, "unexpected login entity tag")
@@ -197,6 +214,43 @@ func (ctxt *authContext) externalMacaroonAuth() (authentication.EntityAuthentica
 	return ctxt._macaroonAuth, nil
 }
 
+// oidcAuth returns an authenticator that can authenticate logins for
+// users whose identity is asserted by the controller's OpenID Connect
+// issuer.
+func (ctxt *authContext) oidcAuth() (authentication.EntityAuthenticator, error) {
+	controllerCfg, err := ctxt.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller config")
+	}
+	if controllerCfg.OIDCIssuerURL() == "" {
+		return nil, errors.Annotatef(common.ErrBadRequest, "OIDC login is not configured")
+	}
+	config := authentication.OIDCConfig{
+		IssuerURL:     controllerCfg.OIDCIssuerURL(),
+		ClientID:      controllerCfg.OIDCClientID(),
+		UsernameClaim: controllerCfg.OIDCUsernameClaim(),
+		GroupsClaim:   controllerCfg.OIDCGroupsClaim(),
+	}
+	ctxt.oidcMu.Lock()
+	defer ctxt.oidcMu.Unlock()
+	if ctxt._oidcVerifier == nil || ctxt._oidcVerifier.Config() != config {
+		ctxt._oidcVerifier = authentication.NewOIDCVerifier(config, nil, ctxt.clock)
+	}
+	return &authentication.OIDCAuthenticator{
+		Verifier:     ctxt._oidcVerifier,
+		RecordGroups: ctxt.st.SetUserGroupClaims,
+	}, nil
+}
+
+// apiTokenAuth returns an authenticator that can authenticate logins
+// for local users presenting an API token issued by the controller.
+func (ctxt *authContext) apiTokenAuth() *authentication.APITokenAuthenticator {
+	return &authentication.APITokenAuthenticator{
+		Backend: ctxt.st,
+		Clock:   ctxt.clock,
+	}
+}
+
 var errMacaroonAuthNotConfigured = errors.New("macaroon authentication is not configured")
 
 // newExternalMacaroonAuth returns an authenticator that can authenticate
@@ -242,5 +296,33 @@ func newExternalMacaroonAuth(st *state.State) (*authentication.ExternalMacaroonA
 		return nil, errors.Annotate(err, "cannot make macaroon")
 	}
 	auth.IdentityLocation = idURL
+	auth.GetGroups = identityGroupsGetter(idURL, httpbakery.NewClient())
+	auth.RecordGroups = st.SetUserGroupClaims
 	return &auth, nil
 }
+
+// identityGroupsGetter returns a function that gets the groups a user
+// belongs to from the identity service at the given URL. The identity
+// service must allow the controller to list its users' groups.
+func identityGroupsGetter(idURL string, client *httpbakery.Client) func(string) ([]string, error) {
+	return func(username string) ([]string, error) {
+		groupsURL := strings.TrimSuffix(idURL, "/") + "/v1/u/" + url.PathEscape(username) + "/groups"
+		req, err := http.NewRequest("GET", groupsURL, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("GET %s: %s", groupsURL, resp.Status)
+		}
+		var groups []string
+		if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+			return nil, errors.Annotate(err, "cannot decode groups")
+		}
+		return groups, nil
+	}
+}
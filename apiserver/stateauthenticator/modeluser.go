@@ -63,7 +63,13 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 			}
 		}
 		if permission.IsEmptyUserAccess(controllerUser) {
-			return nil, errors.NotFoundf("model or controller user")
+			hasAccess, err := f.hasGroupAccess(utag, model.ModelTag())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !hasAccess {
+				return nil, errors.NotFoundf("model or controller user")
+			}
 		}
 	}
 
@@ -82,6 +88,27 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 	return u, nil
 }
 
+// hasGroupAccess reports whether any of the groups the user is a
+// member of has been granted access to the model or the controller.
+func (f modelUserEntityFinder) hasGroupAccess(utag names.UserTag, modelTag names.ModelTag) (bool, error) {
+	groups, err := f.st.UserGroups(utag)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, group := range groups {
+		for _, target := range []names.Tag{modelTag, f.st.ControllerTag()} {
+			access, err := f.st.UserAccess(group, target)
+			if err != nil && !errors.IsNotFound(err) {
+				return false, errors.Trace(err)
+			}
+			if !permission.IsEmptyUserAccess(access) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // modelUserEntity encapsulates an model user
 // and, if the user is local, the local state user
 // as well. This enables us to implement FindEntity
@@ -94,6 +94,8 @@ func nameBasedHasPermission(name string, operation permission.Access, target nam
 		perm = permission.AdminAccess
 	case strings.HasPrefix(name, string(permission.WriteAccess)):
 		perm = permission.WriteAccess
+	case strings.HasPrefix(name, string(permission.OperateAccess)):
+		perm = permission.OperateAccess
 	case strings.HasPrefix(name, string(permission.ConsumeAccess)):
 		perm = permission.ConsumeAccess
 	case strings.HasPrefix(name, string(permission.ReadAccess)):
@@ -527,6 +527,38 @@ func (sb *StubBacking) AddSpace(name string, providerId network.Id, subnets []st
 	return nil
 }
 
+func (sb *StubBacking) RenameSpace(from, to string) error {
+	sb.MethodCall(sb, "RenameSpace", from, to)
+	if err := sb.NextErr(); err != nil {
+		return err
+	}
+	for _, space := range sb.Spaces {
+		if fs, ok := space.(*FakeSpace); ok && fs.SpaceName == from {
+			fs.SpaceName = to
+		}
+	}
+	return nil
+}
+
+func (sb *StubBacking) RemoveSpace(name string) error {
+	sb.MethodCall(sb, "RemoveSpace", name)
+	if err := sb.NextErr(); err != nil {
+		return err
+	}
+	for i, space := range sb.Spaces {
+		if space.Name() == name {
+			sb.Spaces = append(sb.Spaces[:i], sb.Spaces[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (sb *StubBacking) UpdateSpace(name string, subnets []string) error {
+	sb.MethodCall(sb, "UpdateSpace", name, subnets)
+	return sb.NextErr()
+}
+
 func (sb *StubBacking) ReloadSpaces(environ environs.Environ) error {
 	sb.MethodCall(sb, "ReloadSpaces", environ)
 	if err := sb.NextErr(); err != nil {
@@ -99,6 +99,12 @@ type Broker interface {
 	// EnsureNamespace ensures this broker's namespace is created.
 	EnsureNamespace() error
 
+	// AdoptResources is called when the model is moved from one
+	// controller to another. The broker takes ownership of the
+	// existing namespace and operators, rather than creating new
+	// ones, by updating the controller labels on them.
+	AdoptResources(ctx context.ProviderCallContext, controllerUUID string, fromVersion version.Number) error
+
 	// EnsureOperator creates or updates an operator pod for running
 	// a charm for the specified application.
 	EnsureOperator(appName, agentPath string, config *OperatorConfig) error
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/retry"
 	"github.com/juju/utils/clock"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
@@ -36,6 +37,7 @@ import (
 	"github.com/juju/juju/core/devices"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/status"
@@ -126,16 +128,70 @@ func (k *kubernetesClient) Destroy(context.ProviderCallContext) error {
 }
 
 // EnsureNamespace ensures this broker's namespace is created.
+// An existing namespace is left untouched so that any labels
+// added when the model was adopted are preserved.
 func (k *kubernetesClient) EnsureNamespace() error {
-	ns := &core.Namespace{ObjectMeta: v1.ObjectMeta{Name: k.namespace}}
 	namespaces := k.CoreV1().Namespaces()
-	_, err := namespaces.Update(ns)
+	_, err := namespaces.Get(k.namespace, v1.GetOptions{})
 	if k8serrors.IsNotFound(err) {
+		ns := &core.Namespace{ObjectMeta: v1.ObjectMeta{Name: k.namespace}}
 		_, err = namespaces.Create(ns)
 	}
 	return errors.Trace(err)
 }
 
+// AdoptResources is part of the Broker interface. When a model is
+// migrated, its namespace, operator pods and persistent volume claims
+// are already running in the cluster; rather than recreating them, we
+// label them with the new controller's UUID.
+func (k *kubernetesClient) AdoptResources(ctx context.ProviderCallContext, controllerUUID string, fromVersion version.Number) error {
+	logger.Debugf("adopting resources in namespace %q (migrated from %v)", k.namespace, fromVersion)
+	namespaces := k.CoreV1().Namespaces()
+	ns, err := namespaces.Get(k.namespace, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.NotFoundf("namespace %q", k.namespace)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	ns.Labels = withControllerLabel(ns.Labels, controllerUUID)
+	if _, err := namespaces.Update(ns); err != nil {
+		return errors.Annotatef(err, "adopting namespace %q", k.namespace)
+	}
+
+	pods := k.CoreV1().Pods(k.namespace)
+	podList, err := pods.List(v1.ListOptions{LabelSelector: labelOperator})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, pod := range podList.Items {
+		pod.Labels = withControllerLabel(pod.Labels, controllerUUID)
+		if _, err := pods.Update(&pod); err != nil {
+			return errors.Annotatef(err, "adopting operator pod %q", pod.Name)
+		}
+	}
+
+	pvClaims := k.CoreV1().PersistentVolumeClaims(k.namespace)
+	pvcList, err := pvClaims.List(v1.ListOptions{LabelSelector: labelApplication})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, pvc := range pvcList.Items {
+		pvc.Labels = withControllerLabel(pvc.Labels, controllerUUID)
+		if _, err := pvClaims.Update(&pvc); err != nil {
+			return errors.Annotatef(err, "adopting volume claim %q", pvc.Name)
+		}
+	}
+	return nil
+}
+
+func withControllerLabel(labels map[string]string, controllerUUID string) map[string]string {
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[tags.JujuController] = controllerUUID
+	return labels
+}
+
 func (k *kubernetesClient) deleteNamespace() error {
 	// deleteNamespace is used as a means to implement Destroy().
 	// All model resources are provisioned in the namespace;
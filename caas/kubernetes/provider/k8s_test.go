@@ -5,7 +5,9 @@ package provider_test
 
 import (
 	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	appsv1 "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
@@ -18,6 +20,7 @@ import (
 	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/devices"
+	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testing"
 )
@@ -144,11 +147,12 @@ func (s *K8sBrokerSuite) TestEnsureNamespace(c *gc.C) {
 
 	ns := &core.Namespace{ObjectMeta: v1.ObjectMeta{Name: "test"}}
 	gomock.InOrder(
-		s.mockNamespaces.EXPECT().Update(ns).Times(1).
+		s.mockNamespaces.EXPECT().Get("test", v1.GetOptions{}).Times(1).
 			Return(nil, s.k8sNotFoundError()),
 		s.mockNamespaces.EXPECT().Create(ns).Times(1),
 		// Idempotent check.
-		s.mockNamespaces.EXPECT().Update(ns).Times(1),
+		s.mockNamespaces.EXPECT().Get("test", v1.GetOptions{}).Times(1).
+			Return(ns, nil),
 	)
 
 	err := s.broker.EnsureNamespace()
@@ -159,6 +163,69 @@ func (s *K8sBrokerSuite) TestEnsureNamespace(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *K8sBrokerSuite) TestAdoptResources(c *gc.C) {
+	ctrl := s.setupBroker(c)
+	defer ctrl.Finish()
+
+	ns := &core.Namespace{ObjectMeta: v1.ObjectMeta{Name: "test"}}
+	adoptedNs := &core.Namespace{ObjectMeta: v1.ObjectMeta{
+		Name:   "test",
+		Labels: map[string]string{"juju-controller-uuid": "new-controller"},
+	}}
+	pod := core.Pod{ObjectMeta: v1.ObjectMeta{
+		Name:   "juju-operator-gitlab",
+		Labels: map[string]string{"juju-operator": "gitlab", "juju-version": "2.99.0"},
+	}}
+	adoptedPod := core.Pod{ObjectMeta: v1.ObjectMeta{
+		Name: "juju-operator-gitlab",
+		Labels: map[string]string{
+			"juju-operator":        "gitlab",
+			"juju-version":         "2.99.0",
+			"juju-controller-uuid": "new-controller",
+		},
+	}}
+	pvc := core.PersistentVolumeClaim{ObjectMeta: v1.ObjectMeta{
+		Name:   "gitlab-operator-volume",
+		Labels: map[string]string{"juju-application": "gitlab"},
+	}}
+	adoptedPVC := core.PersistentVolumeClaim{ObjectMeta: v1.ObjectMeta{
+		Name: "gitlab-operator-volume",
+		Labels: map[string]string{
+			"juju-application":     "gitlab",
+			"juju-controller-uuid": "new-controller",
+		},
+	}}
+	gomock.InOrder(
+		s.mockNamespaces.EXPECT().Get("test", v1.GetOptions{}).Times(1).
+			Return(ns, nil),
+		s.mockNamespaces.EXPECT().Update(adoptedNs).Times(1).
+			Return(adoptedNs, nil),
+		s.mockPods.EXPECT().List(v1.ListOptions{LabelSelector: "juju-operator"}).Times(1).
+			Return(&core.PodList{Items: []core.Pod{pod}}, nil),
+		s.mockPods.EXPECT().Update(&adoptedPod).Times(1).
+			Return(&adoptedPod, nil),
+		s.mockPersistentVolumeClaims.EXPECT().List(v1.ListOptions{LabelSelector: "juju-application"}).Times(1).
+			Return(&core.PersistentVolumeClaimList{Items: []core.PersistentVolumeClaim{pvc}}, nil),
+		s.mockPersistentVolumeClaims.EXPECT().Update(&adoptedPVC).Times(1).
+			Return(&adoptedPVC, nil),
+	)
+
+	err := s.broker.AdoptResources(context.NewCloudCallContext(), "new-controller", version.MustParse("2.5.0"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *K8sBrokerSuite) TestAdoptResourcesMissingNamespace(c *gc.C) {
+	ctrl := s.setupBroker(c)
+	defer ctrl.Finish()
+
+	s.mockNamespaces.EXPECT().Get("test", v1.GetOptions{}).Times(1).
+		Return(nil, s.k8sNotFoundError())
+
+	err := s.broker.AdoptResources(context.NewCloudCallContext(), "new-controller", version.MustParse("2.5.0"))
+	c.Assert(err, gc.ErrorMatches, `namespace "test" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *K8sBrokerSuite) TestDeleteService(c *gc.C) {
 	ctrl := s.setupBroker(c)
 	defer ctrl.Finish()
@@ -4,7 +4,9 @@
 package cert
 
 import (
+	"bytes"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"time"
 
@@ -13,9 +15,11 @@ import (
 )
 
 // Verify verifies that the given server certificate is valid with
-// respect to the given CA certificate at the given time.
+// respect to the given CA certificate at the given time. The CA
+// certificate may be a bundle of several CA certificates, any of
+// which may have signed the server certificate.
 func Verify(srvCertPEM, caCertPEM string, when time.Time) error {
-	caCert, err := cert.ParseCert(caCertPEM)
+	caCerts, err := ParseCertBundle(caCertPEM)
 	if err != nil {
 		return errors.Annotate(err, "cannot parse CA certificate")
 	}
@@ -24,7 +28,9 @@ func Verify(srvCertPEM, caCertPEM string, when time.Time) error {
 		return errors.Annotate(err, "cannot parse server certificate")
 	}
 	pool := x509.NewCertPool()
-	pool.AddCert(caCert)
+	for _, caCert := range caCerts {
+		pool.AddCert(caCert)
+	}
 	opts := x509.VerifyOptions{
 		Roots:       pool,
 		CurrentTime: when,
@@ -33,6 +39,56 @@ func Verify(srvCertPEM, caCertPEM string, when time.Time) error {
 	return err
 }
 
+// ParseCertBundle parses all of the PEM encoded certificates in the
+// bundle. While the controller CA is being rotated, both the old and
+// the new CA certificates are trusted, and are held in a bundle.
+func ParseCertBundle(bundlePEM string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	data := []byte(bundlePEM)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certs, nil
+}
+
+// JoinCertBundle returns a PEM bundle holding the certificates in each
+// of the given bundles, in order, with duplicates removed.
+func JoinCertBundle(bundlePEMs ...string) (string, error) {
+	var buf bytes.Buffer
+	seen := make(map[string]bool)
+	for _, bundlePEM := range bundlePEMs {
+		certs, err := ParseCertBundle(bundlePEM)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		for _, cert := range certs {
+			if seen[string(cert.Raw)] {
+				continue
+			}
+			seen[string(cert.Raw)] = true
+			if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+				return "", errors.Trace(err)
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
 // NewLeafKeyBits is the number of bits used for the cert.NewLeaf call.
 var NewLeafKeyBits = 2048
 
@@ -163,6 +163,41 @@ func (certSuite) TestVerify(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "x509: certificate signed by unknown authority")
 }
 
+func (certSuite) TestVerifyWithBundle(c *gc.C) {
+	now := time.Now()
+	caCert, caKey, err := cert.NewCA("foo", "1", now.Add(1*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	caCert2, _, err := cert.NewCA("bar", "1", now.Add(1*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+
+	srvCert, _, err := cert.NewServer(caCert, caKey, now.Add(1*time.Minute), nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	bundle, err := cert.JoinCertBundle(caCert2, caCert)
+	c.Assert(err, jc.ErrorIsNil)
+	err = cert.Verify(srvCert, bundle, now)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (certSuite) TestParseCertBundle(c *gc.C) {
+	now := time.Now()
+	caCert, _, err := cert.NewCA("foo", "1", now.Add(1*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	caCert2, _, err := cert.NewCA("bar", "1", now.Add(1*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+
+	bundle, err := cert.JoinCertBundle(caCert, caCert2, caCert)
+	c.Assert(err, jc.ErrorIsNil)
+	certs, err := cert.ParseCertBundle(bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs, gc.HasLen, 2)
+	c.Assert(certs[0].Subject.CommonName, gc.Equals, `juju-generated CA for model "foo"`)
+	c.Assert(certs[1].Subject.CommonName, gc.Equals, `juju-generated CA for model "bar"`)
+
+	_, err = cert.ParseCertBundle("not a cert")
+	c.Assert(err, gc.ErrorMatches, "no certificates found")
+}
+
 func (certSuite) TestNewServer(c *gc.C) {
 	now := time.Now()
 	expiry := roundTime(now.AddDate(1, 0, 0))
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/os/series"
 
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/network/netplan"
@@ -137,6 +138,9 @@ func GenerateENITemplate(interfaces []network.InterfaceInfo) (string, error) {
 
 // GenerateNetplan renders a netplan file for one or more network
 // interfaces, using the given non-empty list of interfaces.
+// Bonds, VLANs and bridges are rendered as such, on top of the interfaces
+// that name them as their parent; all others are rendered as ethernets.
+// It is an error for devices to depend on each other in a cycle.
 func GenerateNetplan(interfaces []network.InterfaceInfo) (string, error) {
 	if len(interfaces) == 0 {
 		return "", errors.Errorf("missing container network config")
@@ -145,47 +149,45 @@ func GenerateNetplan(interfaces []network.InterfaceInfo) (string, error) {
 	var netPlan netplan.Netplan
 	netPlan.Network.Ethernets = make(map[string]netplan.Ethernet)
 	netPlan.Network.Version = 2
-	for _, info := range interfaces {
-		var iface netplan.Ethernet
-		if cidr := info.CIDRAddress(); cidr != "" {
-			iface.Addresses = append(iface.Addresses, cidr)
-		} else if info.ConfigType == network.ConfigDHCP {
-			t := true
-			iface.DHCP4 = &t
-		}
 
-		for _, dns := range info.DNSServers {
-			iface.Nameservers.Addresses = append(iface.Nameservers.Addresses, dns.Value)
-		}
-		iface.Nameservers.Search = append(iface.Nameservers.Search, info.DNSSearchDomains...)
-
-		if info.GatewayAddress.Value != "" {
-			switch {
-			case info.GatewayAddress.Type == network.IPv4Address:
-				iface.Gateway4 = info.GatewayAddress.Value
-			case info.GatewayAddress.Type == network.IPv6Address:
-				iface.Gateway6 = info.GatewayAddress.Value
+	// Bonds, VLANs and bridges can be stacked on each other, and each must
+	// be added after the devices it uses. Ethernets use no other devices,
+	// so they are added straight away.
+	var pending []network.InterfaceInfo
+	pendingNames := set.NewStrings()
+	for _, info := range interfaces {
+		switch info.InterfaceType {
+		case network.BondInterface, network.VLAN_8021QInterface, network.BridgeInterface:
+			pending = append(pending, info)
+			pendingNames.Add(info.InterfaceName)
+		default:
+			iface := netplan.Ethernet{Interface: netplanInterface(info)}
+			if info.MACAddress != "" {
+				iface.Match = map[string]string{"macaddress": info.MACAddress}
+			} else {
+				iface.Match = map[string]string{"name": info.InterfaceName}
 			}
+			netPlan.Network.Ethernets[info.InterfaceName] = iface
 		}
-
-		if info.MTU != 0 && info.MTU != 1500 {
-			iface.MTU = info.MTU
-		}
-		if info.MACAddress != "" {
-			iface.Match = map[string]string{"macaddress": info.MACAddress}
-		} else {
-			iface.Match = map[string]string{"name": info.InterfaceName}
-		}
-		for _, route := range info.Routes {
-			route := netplan.Route{
-				To:     route.DestinationCIDR,
-				Via:    route.GatewayIP,
-				Metric: &route.Metric,
+	}
+	for len(pending) > 0 {
+		var deferred []network.InterfaceInfo
+		for _, info := range pending {
+			if usesAnyOf(info, interfaces, pendingNames) {
+				deferred = append(deferred, info)
+				continue
+			}
+			if err := addNetplanDevice(&netPlan, info, interfaces); err != nil {
+				return "", errors.Trace(err)
 			}
-			iface.Routes = append(iface.Routes, route)
+			pendingNames.Remove(info.InterfaceName)
 		}
-		netPlan.Network.Ethernets[info.InterfaceName] = iface
+		if len(deferred) == len(pending) {
+			return "", errors.Errorf("cannot order devices %q: circular dependency", pendingNames.SortedValues())
+		}
+		pending = deferred
 	}
+
 	out, err := netplan.Marshal(netPlan)
 	if err != nil {
 		return "", errors.Trace(err)
@@ -193,6 +195,90 @@ func GenerateNetplan(interfaces []network.InterfaceInfo) (string, error) {
 	return string(out), nil
 }
 
+// addNetplanDevice adds the input bond, VLAN or bridge to the netplan.
+func addNetplanDevice(netPlan *netplan.Netplan, info network.InterfaceInfo, interfaces []network.InterfaceInfo) error {
+	switch info.InterfaceType {
+	case network.BondInterface:
+		members := childInterfaceNames(info.InterfaceName, interfaces)
+		return netPlan.AddBond(info.InterfaceName, members, netplan.BondParameters{}, netplanInterface(info))
+	case network.VLAN_8021QInterface:
+		return netPlan.AddVLAN(info.InterfaceName, info.VLANTag, info.ParentInterfaceName, netplanInterface(info))
+	case network.BridgeInterface:
+		members := childInterfaceNames(info.InterfaceName, interfaces)
+		return netPlan.AddBridge(info.InterfaceName, members, netplanInterface(info))
+	}
+	return errors.NotValidf("interface %q type %q", info.InterfaceName, info.InterfaceType)
+}
+
+// usesAnyOf returns true if the input interface uses any of the named
+// devices, either as the link of a VLAN or as a member of a bond or bridge.
+func usesAnyOf(info network.InterfaceInfo, interfaces []network.InterfaceInfo, names set.Strings) bool {
+	if info.InterfaceType == network.VLAN_8021QInterface {
+		return names.Contains(info.ParentInterfaceName)
+	}
+	for _, member := range childInterfaceNames(info.InterfaceName, interfaces) {
+		if names.Contains(member) {
+			return true
+		}
+	}
+	return false
+}
+
+// netplanInterface returns the netplan configuration common to all device
+// types for the input interface.
+func netplanInterface(info network.InterfaceInfo) netplan.Interface {
+	var iface netplan.Interface
+	if cidr := info.CIDRAddress(); cidr != "" {
+		iface.Addresses = append(iface.Addresses, cidr)
+	} else if info.ConfigType == network.ConfigDHCP {
+		t := true
+		iface.DHCP4 = &t
+	}
+
+	for _, dns := range info.DNSServers {
+		iface.Nameservers.Addresses = append(iface.Nameservers.Addresses, dns.Value)
+	}
+	iface.Nameservers.Search = append(iface.Nameservers.Search, info.DNSSearchDomains...)
+
+	if info.GatewayAddress.Value != "" {
+		switch {
+		case info.GatewayAddress.Type == network.IPv4Address:
+			iface.Gateway4 = info.GatewayAddress.Value
+		case info.GatewayAddress.Type == network.IPv6Address:
+			iface.Gateway6 = info.GatewayAddress.Value
+		}
+	}
+
+	if info.MTU != 0 && info.MTU != 1500 {
+		iface.MTU = info.MTU
+	}
+	for _, route := range info.Routes {
+		route := netplan.Route{
+			To:     route.DestinationCIDR,
+			Via:    route.GatewayIP,
+			Metric: &route.Metric,
+		}
+		iface.Routes = append(iface.Routes, route)
+	}
+	return iface
+}
+
+// childInterfaceNames returns the names of the interfaces whose parent is
+// the named interface, which are the members of a bond or bridge.
+// VLANs are linked to their parent rather than being members of it.
+func childInterfaceNames(parent string, interfaces []network.InterfaceInfo) []string {
+	var names []string
+	for _, info := range interfaces {
+		if info.InterfaceType == network.VLAN_8021QInterface {
+			continue
+		}
+		if info.ParentInterfaceName == parent && info.InterfaceName != parent {
+			names = append(names, info.InterfaceName)
+		}
+	}
+	return names
+}
+
 // PreparedConfig holds all the necessary information to render a persistent
 // network config to a file.
 type PreparedConfig struct {
@@ -295,18 +381,24 @@ func PrepareNetworkConfigFromInterfaces(interfaces []network.InterfaceInfo) *Pre
 
 // AddNetworkConfig adds configuration scripts for specified interfaces
 // to cloudconfig - using boot textfiles and boot commands. It currently
-// supports e/n/i and netplan.
+// supports e/n/i and netplan. Series that use netplan natively are only
+// given netplan configuration.
 func (cfg *ubuntuCloudConfig) AddNetworkConfig(interfaces []network.InterfaceInfo) error {
 	if len(interfaces) != 0 {
-		eni, err := GenerateENITemplate(interfaces)
+		netPlan, err := GenerateNetplan(interfaces)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		netPlan, err := GenerateNetplan(interfaces)
+		cfg.AddBootTextFile(jujuNetplanFile, netPlan, 0644)
+		if usesNetplan(cfg.series) {
+			cfg.AddBootCmd(applyNetplan)
+			return nil
+		}
+
+		eni, err := GenerateENITemplate(interfaces)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		cfg.AddBootTextFile(jujuNetplanFile, netPlan, 0644)
 		cfg.AddBootTextFile(systemNetworkInterfacesFile+".templ", eni, 0644)
 		cfg.AddBootTextFile(systemNetworkInterfacesFile+".py", NetworkInterfacesScript, 0744)
 		cfg.AddBootCmd(populateNetworkInterfaces(systemNetworkInterfacesFile))
@@ -314,6 +406,24 @@ func (cfg *ubuntuCloudConfig) AddNetworkConfig(interfaces []network.InterfaceInf
 	return nil
 }
 
+// firstNetplanVersion is the first Ubuntu release that configures
+// its network with netplan by default.
+const firstNetplanVersion = "17.10"
+
+// usesNetplan returns true if the input series configures its network
+// with netplan rather than ifupdown by default.
+func usesNetplan(ser string) bool {
+	version, err := series.SeriesVersion(ser)
+	if err != nil {
+		return false
+	}
+	return version >= firstNetplanVersion
+}
+
+// applyNetplan is the boot command that applies the netplan configuration
+// on series that use netplan natively.
+const applyNetplan = "netplan generate && netplan apply"
+
 // Note: we sleep to mitigate against LP #1337873 and LP #1269921.
 // Note2: wait with anything that's hard to revert for as long as possible,
 // we've seen weird failure modes and IMHO it's impossible to avoid them all,
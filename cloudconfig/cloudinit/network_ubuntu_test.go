@@ -437,6 +437,194 @@ func (s *NetworkUbuntuSuite) TestGenerateNetplan(c *gc.C) {
 	c.Check(data, gc.Equals, s.expectedFullNetplan)
 }
 
+func (s *NetworkUbuntuSuite) TestGenerateNetplanBondAndVLAN(c *gc.C) {
+	interfaces := []network.InterfaceInfo{{
+		InterfaceName:       "eth0",
+		MACAddress:          "aa:bb:cc:dd:ee:f0",
+		ParentInterfaceName: "bond0",
+		ConfigType:          network.ConfigManual,
+	}, {
+		InterfaceName:       "eth1",
+		MACAddress:          "aa:bb:cc:dd:ee:f1",
+		ParentInterfaceName: "bond0",
+		ConfigType:          network.ConfigManual,
+	}, {
+		InterfaceName:  "bond0",
+		InterfaceType:  network.BondInterface,
+		ConfigType:     network.ConfigStatic,
+		CIDR:           "10.0.0.0/24",
+		Address:        network.NewAddress("10.0.0.2"),
+		GatewayAddress: network.NewAddress("10.0.0.1"),
+	}, {
+		InterfaceName:       "bond0.100",
+		InterfaceType:       network.VLAN_8021QInterface,
+		ParentInterfaceName: "bond0",
+		VLANTag:             100,
+		ConfigType:          network.ConfigDHCP,
+	}}
+	data, err := cloudinit.GenerateNetplan(interfaces)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, gc.Equals, `
+network:
+  version: 2
+  ethernets:
+    eth0:
+      match:
+        macaddress: aa:bb:cc:dd:ee:f0
+    eth1:
+      match:
+        macaddress: aa:bb:cc:dd:ee:f1
+  bonds:
+    bond0:
+      interfaces: [eth0, eth1]
+      addresses:
+      - 10.0.0.2/24
+      gateway4: 10.0.0.1
+  vlans:
+    bond0.100:
+      id: 100
+      link: bond0
+      dhcp4: true
+`[1:])
+}
+
+func (s *NetworkUbuntuSuite) TestGenerateNetplanBridge(c *gc.C) {
+	interfaces := []network.InterfaceInfo{{
+		InterfaceName:       "eth0",
+		MACAddress:          "aa:bb:cc:dd:ee:f0",
+		ParentInterfaceName: "br0",
+		ConfigType:          network.ConfigManual,
+	}, {
+		InterfaceName: "br0",
+		InterfaceType: network.BridgeInterface,
+		ConfigType:    network.ConfigDHCP,
+	}}
+	data, err := cloudinit.GenerateNetplan(interfaces)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, gc.Equals, `
+network:
+  version: 2
+  ethernets:
+    eth0:
+      match:
+        macaddress: aa:bb:cc:dd:ee:f0
+  bridges:
+    br0:
+      interfaces: [eth0]
+      dhcp4: true
+`[1:])
+}
+
+func (s *NetworkUbuntuSuite) TestGenerateNetplanVLANMissingLink(c *gc.C) {
+	interfaces := []network.InterfaceInfo{{
+		InterfaceName:       "eth0.100",
+		InterfaceType:       network.VLAN_8021QInterface,
+		ParentInterfaceName: "eth0",
+		VLANTag:             100,
+	}}
+	_, err := cloudinit.GenerateNetplan(interfaces)
+	c.Assert(err, gc.ErrorMatches, `link device "eth0" for VLAN "eth0.100" not found`)
+}
+
+// generateNetplanGoldenTests list the interfaces stacked on each other,
+// with the dependent devices before the devices they use.
+var generateNetplanGoldenTests = []struct {
+	name       string
+	interfaces []network.InterfaceInfo
+}{{
+	name: "vlan-on-bridge",
+	interfaces: []network.InterfaceInfo{{
+		InterfaceName:       "br0.100",
+		InterfaceType:       network.VLAN_8021QInterface,
+		ParentInterfaceName: "br0",
+		VLANTag:             100,
+		ConfigType:          network.ConfigDHCP,
+	}, {
+		InterfaceName:  "br0",
+		InterfaceType:  network.BridgeInterface,
+		ConfigType:     network.ConfigStatic,
+		CIDR:           "10.0.0.0/24",
+		Address:        network.NewAddress("10.0.0.2"),
+		GatewayAddress: network.NewAddress("10.0.0.1"),
+	}, {
+		InterfaceName:       "eth0",
+		MACAddress:          "aa:bb:cc:dd:ee:f0",
+		ParentInterfaceName: "br0",
+		ConfigType:          network.ConfigManual,
+	}},
+}, {
+	name: "vlan-on-bridged-bond",
+	interfaces: []network.InterfaceInfo{{
+		InterfaceName:       "br0.100",
+		InterfaceType:       network.VLAN_8021QInterface,
+		ParentInterfaceName: "br0",
+		VLANTag:             100,
+		ConfigType:          network.ConfigDHCP,
+	}, {
+		InterfaceName:  "br0",
+		InterfaceType:  network.BridgeInterface,
+		ConfigType:     network.ConfigStatic,
+		CIDR:           "10.0.0.0/24",
+		Address:        network.NewAddress("10.0.0.2"),
+		GatewayAddress: network.NewAddress("10.0.0.1"),
+	}, {
+		InterfaceName:       "bond0",
+		InterfaceType:       network.BondInterface,
+		ParentInterfaceName: "br0",
+		ConfigType:          network.ConfigManual,
+	}, {
+		InterfaceName:       "eth0",
+		MACAddress:          "aa:bb:cc:dd:ee:f0",
+		ParentInterfaceName: "bond0",
+		ConfigType:          network.ConfigManual,
+	}, {
+		InterfaceName:       "eth1",
+		MACAddress:          "aa:bb:cc:dd:ee:f1",
+		ParentInterfaceName: "bond0",
+		ConfigType:          network.ConfigManual,
+	}},
+}}
+
+// TestGenerateNetplanGolden checks the netplan generated for each test
+// against testdata/TestGenerateNetplanGolden/<name>.yaml.
+func (s *NetworkUbuntuSuite) TestGenerateNetplanGolden(c *gc.C) {
+	for i, test := range generateNetplanGoldenTests {
+		c.Logf("test %d: %s", i, test.name)
+		data, err := cloudinit.GenerateNetplan(test.interfaces)
+		c.Assert(err, jc.ErrorIsNil)
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "TestGenerateNetplanGolden", test.name+".yaml"))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(data, gc.Equals, string(expected))
+	}
+}
+
+func (s *NetworkUbuntuSuite) TestGenerateNetplanCircularDependency(c *gc.C) {
+	interfaces := []network.InterfaceInfo{{
+		InterfaceName:       "bond0",
+		InterfaceType:       network.BondInterface,
+		ParentInterfaceName: "br0",
+	}, {
+		InterfaceName:       "br0",
+		InterfaceType:       network.BridgeInterface,
+		ParentInterfaceName: "bond0",
+	}}
+	_, err := cloudinit.GenerateNetplan(interfaces)
+	c.Assert(err, gc.ErrorMatches, `cannot order devices \["bond0" "br0"\]: circular dependency`)
+}
+
+func (s *NetworkUbuntuSuite) TestAddNetworkConfigNetplanSeries(c *gc.C) {
+	netConfig := container.BridgeNetworkConfig("foo", 0, s.fakeInterfaces)
+	cloudConf, err := cloudinit.New("bionic")
+	c.Assert(err, jc.ErrorIsNil)
+	err = cloudConf.AddNetworkConfig(netConfig.Interfaces)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := s.expectedSampleConfigHeader
+	expected += fmt.Sprintf(s.expectedFullNetplanYaml, s.jujuNetplanFile)
+	expected += "- netplan generate && netplan apply\n"
+	assertUserData(c, cloudConf, expected)
+}
+
 func (s *NetworkUbuntuSuite) TestAddNetworkConfigSampleConfig(c *gc.C) {
 	netConfig := container.BridgeNetworkConfig("foo", 0, s.fakeInterfaces)
 	cloudConf, err := cloudinit.New("xenial")
@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/tags"
@@ -98,6 +99,11 @@ type InstanceConfig struct {
 	// specified by the user.
 	CloudInitUserData map[string]interface{}
 
+	// CharmLXDProfiles holds the LXD profiles defined by the charms of
+	// the units to be deployed to the machine, by name. They are applied
+	// when the machine is an LXD container or virtual machine.
+	CharmLXDProfiles lxdprofile.Profiles
+
 	// MachineId identifies the new machine.
 	MachineId string
 
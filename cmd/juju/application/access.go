@@ -0,0 +1,185 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/permission"
+)
+
+var usageGrantApplicationSummary = `
+Grants access level to a Juju user for one or more applications.`[1:]
+
+var usageGrantApplicationDetails = `
+Application access lets a user look after individual applications in a
+model without needing write access to the whole model. The user also
+needs read access to the model itself; see grant.
+
+Access may be granted to a user group by naming it as "<group>@group".
+
+Application access is not carried over when the model is migrated to
+another controller, and must be granted again there.
+
+Valid access levels for applications are:
+    read     see the application's configuration
+    operate  run actions and commands on, ssh to, and resolve
+             errors on the application's units
+    admin    configure, upgrade, scale, expose and remove the
+             application, and grant others access to it
+
+Examples:
+Grant user 'joe' 'operate' access to application 'postgresql':
+
+    juju grant-application joe operate postgresql
+
+Grant the members of group 'web' 'admin' access to applications
+'wordpress' and 'haproxy':
+
+    juju grant-application web@group admin wordpress haproxy
+
+See also:
+    revoke-application
+    grant`[1:]
+
+var usageRevokeApplicationSummary = `
+Revokes access from a Juju user for one or more applications.`[1:]
+
+var usageRevokeApplicationDetails = `
+Revoking admin access, from a user who has that permission, will leave
+that user with operate access. Revoking operate access will leave the
+user with read access, and revoking read access removes all access to
+the application.
+
+Examples:
+Revoke 'admin' access from user 'joe' for application 'postgresql':
+
+    juju revoke-application joe admin postgresql
+
+See also:
+    grant-application
+    revoke`[1:]
+
+// ApplicationAccessAPI defines the API functions used by the
+// grant-application and revoke-application commands.
+type ApplicationAccessAPI interface {
+	Close() error
+	GrantApplication(user, access string, applications ...string) error
+	RevokeApplication(user, access string, applications ...string) error
+}
+
+type applicationAccessCommand struct {
+	modelcmd.ModelCommandBase
+	newAPIFunc func() (ApplicationAccessAPI, error)
+
+	User         string
+	Access       string
+	Applications []string
+}
+
+// Init implements cmd.Command.
+func (c *applicationAccessCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no user specified")
+	}
+	if len(args) < 2 {
+		return errors.New("no permission level specified")
+	}
+	if len(args) < 3 {
+		return errors.New("no application specified")
+	}
+	c.User = args[0]
+	c.Access = args[1]
+	if err := permission.ValidateApplicationAccess(permission.Access(c.Access)); err != nil {
+		return errors.Trace(err)
+	}
+	for _, arg := range args[2:] {
+		if !names.IsValidApplication(arg) {
+			return errors.NotValidf("application name %q", arg)
+		}
+		c.Applications = append(c.Applications, arg)
+	}
+	return nil
+}
+
+func newApplicationAccessAPIFunc(c *applicationAccessCommand) func() (ApplicationAccessAPI, error) {
+	return func() (ApplicationAccessAPI, error) {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return application.NewClient(root), nil
+	}
+}
+
+// NewGrantApplicationCommand returns a command to grant a user access
+// to applications.
+func NewGrantApplicationCommand() cmd.Command {
+	c := &grantApplicationCommand{}
+	c.newAPIFunc = newApplicationAccessAPIFunc(&c.applicationAccessCommand)
+	return modelcmd.Wrap(c)
+}
+
+type grantApplicationCommand struct {
+	applicationAccessCommand
+}
+
+// Info implements cmd.Command.
+func (c *grantApplicationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "grant-application",
+		Args:    "<user name> <permission> <application name> ...",
+		Purpose: usageGrantApplicationSummary,
+		Doc:     usageGrantApplicationDetails,
+	}
+}
+
+// Run implements cmd.Command.
+func (c *grantApplicationCommand) Run(_ *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.GrantApplication(c.User, c.Access, c.Applications...)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+// NewRevokeApplicationCommand returns a command to revoke a user's
+// access to applications.
+func NewRevokeApplicationCommand() cmd.Command {
+	c := &revokeApplicationCommand{}
+	c.newAPIFunc = newApplicationAccessAPIFunc(&c.applicationAccessCommand)
+	return modelcmd.Wrap(c)
+}
+
+type revokeApplicationCommand struct {
+	applicationAccessCommand
+}
+
+// Info implements cmd.Command.
+func (c *revokeApplicationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke-application",
+		Args:    "<user name> <permission> <application name> ...",
+		Purpose: usageRevokeApplicationSummary,
+		Doc:     usageRevokeApplicationDetails,
+	}
+}
+
+// Run implements cmd.Command.
+func (c *revokeApplicationCommand) Run(_ *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.RevokeApplication(c.User, c.Access, c.Applications...)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
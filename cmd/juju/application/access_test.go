@@ -0,0 +1,115 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ApplicationAccessSuite struct {
+	testing.IsolationSuite
+	mockAPI *mockApplicationAccessAPI
+}
+
+var _ = gc.Suite(&ApplicationAccessSuite{})
+
+func (s *ApplicationAccessSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockApplicationAccessAPI{Stub: &testing.Stub{}}
+}
+
+func (s *ApplicationAccessSuite) runGrant(c *gc.C, args ...string) error {
+	store := jujuclienttesting.MinimalStore()
+	_, err := cmdtesting.RunCommand(c, NewGrantApplicationCommandForTest(s.mockAPI, store), args...)
+	return err
+}
+
+func (s *ApplicationAccessSuite) runRevoke(c *gc.C, args ...string) error {
+	store := jujuclienttesting.MinimalStore()
+	_, err := cmdtesting.RunCommand(c, NewRevokeApplicationCommandForTest(s.mockAPI, store), args...)
+	return err
+}
+
+func (s *ApplicationAccessSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no user specified",
+	}, {
+		args: []string{"bob"},
+		err:  "no permission level specified",
+	}, {
+		args: []string{"bob", "operate"},
+		err:  "no application specified",
+	}, {
+		args: []string{"bob", "write", "postgresql"},
+		err:  `"write" application access not valid`,
+	}, {
+		args: []string{"bob", "read", "Postgres!"},
+		err:  `application name "Postgres!" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		c.Check(s.runGrant(c, test.args...), gc.ErrorMatches, test.err)
+		c.Check(s.runRevoke(c, test.args...), gc.ErrorMatches, test.err)
+	}
+	s.mockAPI.CheckNoCalls(c)
+}
+
+func (s *ApplicationAccessSuite) TestGrant(c *gc.C) {
+	err := s.runGrant(c, "web@group", "admin", "wordpress", "haproxy")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"GrantApplication", []interface{}{"web@group", "admin", []string{"wordpress", "haproxy"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *ApplicationAccessSuite) TestRevoke(c *gc.C) {
+	err := s.runRevoke(c, "bob", "operate", "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"RevokeApplication", []interface{}{"bob", "operate", []string{"postgresql"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *ApplicationAccessSuite) TestGrantFails(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	err := s.runGrant(c, "bob", "read", "postgresql")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ApplicationAccessSuite) TestGrantBlocked(c *gc.C) {
+	s.mockAPI.SetErrors(common.OperationBlockedError("TestGrantBlocked"))
+	err := s.runGrant(c, "bob", "read", "postgresql")
+	coretesting.AssertOperationWasBlocked(c, err, ".*TestGrantBlocked.*")
+}
+
+type mockApplicationAccessAPI struct {
+	*testing.Stub
+}
+
+func (m *mockApplicationAccessAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockApplicationAccessAPI) GrantApplication(user, access string, applications ...string) error {
+	m.MethodCall(m, "GrantApplication", user, access, applications)
+	return m.NextErr()
+}
+
+func (m *mockApplicationAccessAPI) RevokeApplication(user, access string, applications ...string) error {
+	m.MethodCall(m, "RevokeApplication", user, access, applications)
+	return m.NextErr()
+}
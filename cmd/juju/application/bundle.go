@@ -118,6 +118,9 @@ func deployBundle(
 	if err := h.makeModel(useExistingMachines, bundleMachines); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := h.verifyPlacementPolicies(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if err := h.resolveCharmsAndEndpoints(); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -267,6 +270,50 @@ func (h *bundleHandler) makeModel(
 	return nil
 }
 
+// verifyPlacementPolicies checks that the applications named in the
+// affinity and anti-affinity constraints of each application in the
+// bundle are defined in the bundle or already deployed in the model.
+func (h *bundleHandler) verifyPlacementPolicies() error {
+	var errs []string
+	for _, name := range h.applications.SortedValues() {
+		spec := h.data.Applications[name]
+		cons, err := constraints.Parse(spec.Constraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, policy := range []struct {
+			attr string
+			apps *[]string
+		}{
+			{constraints.Affinity, cons.Affinity},
+			{constraints.AntiAffinity, cons.AntiAffinity},
+		} {
+			if policy.apps == nil {
+				continue
+			}
+			for _, app := range *policy.apps {
+				switch {
+				case app == name:
+					errs = append(errs, fmt.Sprintf(
+						"%q constraint in application %q names the application itself",
+						policy.attr, name,
+					))
+				case h.applications.Contains(app), h.model.GetApplication(app) != nil:
+				default:
+					errs = append(errs, fmt.Sprintf(
+						"%q constraint in application %q names unknown application %q",
+						policy.attr, name, app,
+					))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New("the provided bundle has the following errors:\n" + strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
 // resolveCharmsAndEndpoints will go through the bundle and
 // resolve the charm URLs. From the model the charm names are
 // fully qualified, meaning they have a source and revision id.
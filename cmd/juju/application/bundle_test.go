@@ -688,6 +688,42 @@ invalid constraints "bad-wolf" in application "mysql": malformed constraint "bad
 	err: `the provided bundle has the following errors:
 invalid constraints "bad-wolf" in application "mysql": malformed constraint "bad-wolf"
 negative number of units specified on application "mysql"`,
+}, {
+	about: "anti-affinity with unknown application",
+	content: `
+        applications:
+            mysql:
+                charm: mysql
+                num_units: 1
+                constraints: anti-affinity=mongodb
+    `,
+	err: `the provided bundle has the following errors:
+"anti-affinity" constraint in application "mysql" names unknown application "mongodb"`,
+}, {
+	about: "affinity with itself",
+	content: `
+        applications:
+            mysql:
+                charm: mysql
+                num_units: 1
+                constraints: affinity=mysql
+    `,
+	err: `the provided bundle has the following errors:
+"affinity" constraint in application "mysql" names the application itself`,
+}, {
+	about: "invalid anti-affinity",
+	content: `
+        applications:
+            mysql:
+                charm: mysql
+                num_units: 1
+                constraints: affinity=wordpress anti-affinity=wordpress
+            wordpress:
+                charm: wordpress
+                num_units: 1
+    `,
+	err: `the provided bundle has the following errors:
+invalid constraints "affinity=wordpress anti-affinity=wordpress" in application "mysql": application "wordpress" cannot be in both "affinity" and "anti-affinity" constraints`,
 }, {
 	about: "bundle inception",
 	content: `
@@ -429,6 +429,15 @@ Constraints can be specified by specifying the '--constraints' option. If the
 application is later scaled out with ` + "`juju add-unit`" + `, provisioned machines
 will use the same constraints (unless changed by ` + "`juju set-constraints`" + `).
 
+Placement policies are also given as constraints. The 'anti-affinity'
+constraint lists applications whose units must never share a machine with
+the application's units, 'affinity' lists applications alongside whose
+units they should be placed when possible, and 'placement-strategy' is
+either 'spread' (the default) or 'pack', to spread units across
+availability zones or keep them together. For example:
+
+    juju deploy mysql --constraints "anti-affinity=mongodb"
+
 Devices can be specified by specifying the '--device' option to deploy charms to a
 k8s cluster which require the use of a GPU (or many).
 Devices provided should be in format:
@@ -130,3 +130,25 @@ func NewRemoveSaasCommandForTest(api RemoveSaasAPI, store jujuclient.ClientStore
 	cmd.SetClientStore(store)
 	return modelcmd.Wrap(cmd)
 }
+
+// NewGrantApplicationCommandForTest returns a grant-application command
+// with the api provided as specified.
+func NewGrantApplicationCommandForTest(api ApplicationAccessAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	c := &grantApplicationCommand{}
+	c.newAPIFunc = func() (ApplicationAccessAPI, error) {
+		return api, nil
+	}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+// NewRevokeApplicationCommandForTest returns a revoke-application command
+// with the api provided as specified.
+func NewRevokeApplicationCommandForTest(api ApplicationAccessAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	c := &revokeApplicationCommand{}
+	c.newAPIFunc = func() (ApplicationAccessAPI, error) {
+		return api, nil
+	}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
@@ -6,6 +6,7 @@ package backups
 import (
 	"github.com/juju/cmd"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/jujuclient"
 )
@@ -16,9 +17,13 @@ const (
 )
 
 var (
-	NewAPIClient = &newAPIClient
-	NewGetAPI    = &getAPI
-	GetArchive   = &getArchive
+	NewAPIClient               = &newAPIClient
+	NewGetAPI                  = &getAPI
+	GetArchive                 = &getArchive
+	PrepareEnviron             = &prepareEnviron
+	BootstrapFunc              = &bootstrapFunc
+	BootstrapEndpointAddresses = &bootstrapEndpointAddresses
+	WaitForAgentInitialisation = &waitForAgentInitialisation
 )
 
 type CreateCommand struct {
@@ -81,9 +86,14 @@ func NewRestoreCommandForTest(
 	c := &restoreCommand{}
 	c.Log = &cmd.Log{}
 	c.SetClientStore(store)
+	c.rebootstrap = c.rebootstrapController
 	return modelcmd.Wrap(c), &RestoreCommand{c}
 }
 
+func (r *RestoreCommand) AssignRebootstrap(f func(*cmd.Context, *params.BackupsMetadataResult) error) {
+	r.rebootstrap = f
+}
+
 func (r *RestoreCommand) AssignGetModelStatusAPI(apiFunc func() (ModelStatusAPI, error)) {
 	r.getModelStatusAPI = apiFunc
 }
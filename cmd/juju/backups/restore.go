@@ -17,15 +17,26 @@ import (
 	"github.com/juju/juju/api/backups"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/controller"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/constraints"
+	jujucontroller "github.com/juju/juju/controller"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/sync"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/network"
 )
 
 // NewRestoreCommand returns a command used to restore a backup.
 func NewRestoreCommand() cmd.Command {
 	c := &restoreCommand{}
 	c.getModelStatusAPI = func() (ModelStatusAPI, error) { return c.NewModelManagerAPIClient() }
+	c.rebootstrap = c.rebootstrapController
 	return modelcmd.Wrap(c)
 }
 
@@ -35,8 +46,22 @@ type restoreCommand struct {
 	CommandBase
 	getModelStatusAPI func() (ModelStatusAPI, error)
 
+	// rebootstrap bootstraps a new controller instance that the
+	// backup will be restored onto.
+	rebootstrap func(*cmd.Context, *params.BackupsMetadataResult) error
+
 	Filename string
 	BackupId string
+
+	// BuildController indicates that a new controller instance should
+	// be bootstrapped to restore the backup onto, replacing a controller
+	// that has been lost entirely.
+	BuildController bool
+
+	// ConstraintsStr holds the bootstrap constraints for the new
+	// controller instance.
+	ConstraintsStr string
+	Constraints    constraints.Value
 }
 
 // RestoreAPI is used to invoke various API calls.
@@ -71,8 +96,23 @@ machines will be added or removed during the restore process.
 Note: Extra care is needed to restore in an HA environment, please see
 https://docs.jujucharms.com/devel/en/controllers-backup for more information.
 
+If every controller machine has been lost, --build-controller bootstraps a
+new controller instance, using the cloud details and credentials recorded
+when the controller was first bootstrapped, and restores the backup file
+onto it. The new controller keeps the CA of the original, so existing
+agents continue to trust it; they are then pointed at its new addresses.
+Only the restored machine is a member of the new controller's replica-set;
+use "juju enable-ha" to make the controller highly available again.
+--build-controller logs in to the new controller with the password stored
+locally for the controller's account, so it cannot be used by accounts that
+log in with macaroons or an external identity provider.
+
 If the provided state cannot be restored, this command will fail with
 an explanation.
+
+Examples:
+    juju restore-backup --file juju-backup-20180708-161900.tar.gz
+    juju restore-backup --build-controller --file juju-backup-20180708-161900.tar.gz
 `
 
 // Info returns the content for --help.
@@ -90,6 +130,8 @@ func (c *restoreCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
 	f.StringVar(&c.Filename, "file", "", "Provide a file to be used as the backup")
 	f.StringVar(&c.BackupId, "id", "", "Provide the name of the backup to be restored")
+	f.BoolVar(&c.BuildController, "build-controller", false, "Bootstrap a new controller instance to restore the backup onto")
+	f.StringVar(&c.ConstraintsStr, "constraints", "", "Constraints for the new controller instance (with --build-controller)")
 }
 
 // Init is where the preconditions for this command can be checked.
@@ -100,6 +142,12 @@ func (c *restoreCommand) Init(args []string) error {
 	if c.Filename != "" && c.BackupId != "" {
 		return errors.Errorf("you must specify either a file or a backup id but not both.")
 	}
+	if c.BuildController && c.Filename == "" {
+		return errors.Errorf("--build-controller requires a backup file, as backups stored on the lost controller are not available.")
+	}
+	if c.ConstraintsStr != "" && !c.BuildController {
+		return errors.Errorf("--constraints can only be used with --build-controller.")
+	}
 
 	if c.Filename != "" {
 		var err error
@@ -159,23 +207,26 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	// Don't allow restore in an HA environment
-	controllerModelUUID, modelStatus, err := c.modelStatus()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	activeCount, _ := controller.ControllerMachineCounts(controllerModelUUID, modelStatus)
-	if activeCount > 1 {
-		return errors.Errorf("unable to restore backup in HA configuration.  For help see https://docs.jujucharms.com/devel/en/controllers-backup")
+	// Don't allow restore in an HA environment. There is nothing to
+	// check when building a new controller, as the old one is gone.
+	if !c.BuildController {
+		controllerModelUUID, modelStatus, err := c.modelStatus()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		activeCount, _ := controller.ControllerMachineCounts(controllerModelUUID, modelStatus)
+		if activeCount > 1 {
+			return errors.Errorf("unable to restore backup in HA configuration.  For help see https://docs.jujucharms.com/devel/en/controllers-backup")
+		}
 	}
 
+	var err error
 	var archive ArchiveReader
 	var meta *params.BackupsMetadataResult
 	target := c.BackupId
 	if c.Filename != "" {
 		// Read archive specified by the Filename
 		target = c.Filename
-		var err error
 		archive, meta, err = getArchive(c.Filename)
 		if err != nil {
 			return errors.Trace(err)
@@ -183,6 +234,16 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 		defer archive.Close()
 	}
 
+	if c.BuildController {
+		c.Constraints, err = common.ParseConstraints(ctx, c.ConstraintsStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := c.rebootstrap(ctx, meta); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return errors.Trace(err)
@@ -202,3 +263,167 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	fmt.Fprintf(ctx.Stdout, "restore from %q completed\n", target)
 	return nil
 }
+
+// These are patched out in tests.
+var (
+	prepareEnviron             = prepareControllerEnviron
+	bootstrapFunc              = bootstrap.Bootstrap
+	bootstrapEndpointAddresses = common.BootstrapEndpointAddresses
+	waitForAgentInitialisation = common.WaitForAgentInitialisation
+)
+
+// rebootstrapController bootstraps a new controller instance in place of
+// the lost controller, using the CA recorded in the backup so that the
+// restored agents and clients continue to trust it.
+func (c *restoreCommand) rebootstrapController(ctx *cmd.Context, meta *params.BackupsMetadataResult) error {
+	if meta.CACert == "" || meta.CAPrivateKey == "" {
+		return errors.New("backup does not contain the controller CA, cannot build a new controller")
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := c.ClientStore()
+
+	// The new controller is bootstrapped with the local password as its
+	// admin secret, which the restore then replaces with the one in the
+	// backup. A controller that has never seen the local macaroons cannot
+	// be logged into without it, either before or after the restore.
+	account, err := store.AccountDetails(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if account.Password == "" {
+		return errors.Errorf(
+			"cannot build a new controller: no password is stored for user %q on controller %q",
+			account.User, controllerName,
+		)
+	}
+
+	env, bootstrapConfig, spec, err := prepareEnviron(ctx, store, controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	controllerConfig := make(jujucontroller.Config)
+	for k, v := range bootstrapConfig.ControllerConfig {
+		controllerConfig[k] = v
+	}
+	controllerConfig[jujucontroller.CACertKey] = meta.CACert
+
+	callCtx := context.NewCloudCallContext()
+	if err := checkControllerInstancesGone(env, callCtx, controllerConfig.ControllerUUID()); err != nil {
+		return errors.Trace(err)
+	}
+
+	bootstrapCloud := jujucloud.Cloud{
+		Name:             spec.Name,
+		Type:             spec.Type,
+		Endpoint:         spec.Endpoint,
+		IdentityEndpoint: spec.IdentityEndpoint,
+		StorageEndpoint:  spec.StorageEndpoint,
+	}
+	if spec.Region != "" {
+		bootstrapCloud.Regions = []jujucloud.Region{{
+			Name:             spec.Region,
+			Endpoint:         spec.Endpoint,
+			IdentityEndpoint: spec.IdentityEndpoint,
+			StorageEndpoint:  spec.StorageEndpoint,
+		}}
+	}
+
+	ctx.Infof("Bootstrapping a new controller instance to restore %q onto", controllerName)
+	err = bootstrapFunc(modelcmd.BootstrapContext(ctx), env, callCtx, bootstrap.BootstrapParams{
+		ModelConstraints:     c.Constraints,
+		BootstrapConstraints: c.Constraints,
+		// Restore requires the series of the new instance to match.
+		BootstrapSeries:     meta.Series,
+		BuildAgentTarball:   sync.BuildAgentTarball,
+		Cloud:               bootstrapCloud,
+		CloudRegion:         spec.Region,
+		CloudCredential:     spec.Credential,
+		CloudCredentialName: bootstrapConfig.Credential,
+		ControllerConfig:    controllerConfig,
+		AdminSecret:         account.Password,
+		CAPrivateKey:        meta.CAPrivateKey,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot bootstrap new controller instance")
+	}
+
+	// Record the new controller addresses so that the restore can
+	// connect to it.
+	addrs, err := bootstrapEndpointAddresses(env, callCtx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hostPorts := network.AddressesWithPort(addrs, controllerConfig.APIPort())
+	one := 1
+	if err := juju.UpdateControllerDetailsFromLogin(store, controllerName, juju.UpdateControllerParams{
+		CurrentHostPorts:       [][]network.HostPort{hostPorts},
+		ControllerMachineCount: &one,
+	}); err != nil {
+		return errors.Annotate(err, "saving new controller address")
+	}
+	return waitForAgentInitialisation(ctx, &c.ModelCommandBase, controllerName, bootstrap.ControllerModelName)
+}
+
+// prepareControllerEnviron opens the environ that the named controller
+// was bootstrapped into, from the bootstrap config in the client store.
+func prepareControllerEnviron(
+	ctx *cmd.Context, store jujuclient.ClientStore, controllerName string,
+) (environs.Environ, *jujuclient.BootstrapConfig, environs.CloudSpec, error) {
+	bootstrapConfig, prepareParams, err := modelcmd.NewGetBootstrapConfigParamsFunc(
+		ctx, store, environs.GlobalProviderRegistry(),
+	)(controllerName)
+	if err != nil {
+		return nil, nil, environs.CloudSpec{}, errors.Annotate(err, "cannot get bootstrap config for the lost controller")
+	}
+	provider, err := environs.Provider(bootstrapConfig.CloudType)
+	if err != nil {
+		return nil, nil, environs.CloudSpec{}, errors.Trace(err)
+	}
+	modelConfig, err := provider.PrepareConfig(*prepareParams)
+	if err != nil {
+		return nil, nil, environs.CloudSpec{}, errors.Trace(err)
+	}
+	env, err := environs.New(environs.OpenParams{
+		Cloud:  prepareParams.Cloud,
+		Config: modelConfig,
+	})
+	if err != nil {
+		return nil, nil, environs.CloudSpec{}, errors.Trace(err)
+	}
+	return env, bootstrapConfig, prepareParams.Cloud, nil
+}
+
+// checkControllerInstancesGone returns an error if any instance of the
+// controller being rebuilt is still running, as restoring onto a new
+// instance would then leave two controllers managing the same models.
+func checkControllerInstancesGone(env environs.Environ, callCtx context.ProviderCallContext, controllerUUID string) error {
+	ids, err := env.ControllerInstances(callCtx, controllerUUID)
+	if errors.Cause(err) == environs.ErrNoInstances {
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot check for existing controller instances")
+	}
+	insts, err := env.Instances(callCtx, ids)
+	if errors.Cause(err) == environs.ErrNoInstances {
+		return nil
+	} else if err != nil && err != environs.ErrPartialInstances {
+		return errors.Annotate(err, "cannot check for existing controller instances")
+	}
+	var running []string
+	for _, inst := range insts {
+		if inst != nil {
+			running = append(running, string(inst.Id()))
+		}
+	}
+	if len(running) > 0 {
+		return errors.Errorf(
+			"controller instances %s still exist; will not build a new controller",
+			strings.Join(running, ", "),
+		)
+	}
+	return nil
+}
@@ -18,8 +18,16 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/constraints"
+	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/network"
 	_ "github.com/juju/juju/provider/dummy"
 	_ "github.com/juju/juju/provider/lxd"
 	"github.com/juju/juju/status"
@@ -138,6 +146,21 @@ var testRestoreBackupArgParsing = []restoreBackupArgParsing{
 		args:     []string{"--file", "afile"},
 		filename: "afile",
 	},
+	{
+		title:    "build controller with file",
+		args:     []string{"--build-controller", "--file", "afile"},
+		filename: "afile",
+	},
+	{
+		title:    "build controller without file",
+		args:     []string{"--build-controller", "--id", "anid"},
+		errMatch: "--build-controller requires a backup file, as backups stored on the lost controller are not available.",
+	},
+	{
+		title:    "constraints without build controller",
+		args:     []string{"--file", "afile", "--constraints", "mem=8G"},
+		errMatch: "--constraints can only be used with --build-controller.",
+	},
 }
 
 func (s *restoreSuite) TestArgParsing(c *gc.C) {
@@ -265,3 +288,204 @@ func (s *restoreSuite) TestRestoreFromBackupHAFail(c *gc.C) {
 	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "restore", "--id", "an_id")
 	c.Assert(err, gc.ErrorMatches, "unable to restore backup in HA configuration.  For help see https://docs.jujucharms.com/devel/en/controllers-backup")
 }
+
+func (s *restoreSuite) TestRestoreBuildController(c *gc.C) {
+	ctlr, apiClient, archiveReader, _ := s.patch(c, nil)
+	defer ctlr.Finish()
+	var rebootstrapMeta *params.BackupsMetadataResult
+	s.command.AssignRebootstrap(func(_ *cmd.Context, meta *params.BackupsMetadataResult) error {
+		rebootstrapMeta = meta
+		return nil
+	})
+	// No model status is requested, as the old controller is gone.
+	gomock.InOrder(
+		apiClient.EXPECT().RestoreReader(archiveReader, &params.BackupsMetadataResult{}, gomock.Any()).Return(
+			nil,
+		),
+		apiClient.EXPECT().Close(),
+		archiveReader.EXPECT().Close(),
+	)
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "restore", "--build-controller", "--file", "afile",
+		"--constraints", "mem=8G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rebootstrapMeta, jc.DeepEquals, &params.BackupsMetadataResult{})
+	c.Assert(s.command.Constraints.String(), gc.Equals, "mem=8192M")
+}
+
+func (s *restoreSuite) TestRestoreBuildControllerBootstrapFail(c *gc.C) {
+	ctlr, _, archiveReader, _ := s.patch(c, nil)
+	defer ctlr.Finish()
+	s.command.AssignRebootstrap(func(*cmd.Context, *params.BackupsMetadataResult) error {
+		return errors.New("bootstrap failed")
+	})
+	archiveReader.EXPECT().Close()
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "restore", "--build-controller", "--file", "afile")
+	c.Assert(err, gc.ErrorMatches, "bootstrap failed")
+}
+
+func (s *restoreSuite) patchBuildController(c *gc.C, env environs.Environ) (*gomock.Controller, *MockAPIClient, *MockArchiveReader, *params.BackupsMetadataResult) {
+	ctlr, apiClient, archiveReader, _ := s.patch(c, nil)
+	meta := &params.BackupsMetadataResult{
+		Series:       "xenial",
+		CACert:       testing.CACert,
+		CAPrivateKey: testing.CAKey,
+	}
+	s.PatchValue(backups.GetArchive,
+		func(string) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+			return archiveReader, meta, nil
+		},
+	)
+	s.store.Controllers["test-master"] = jujuclient.ControllerDetails{
+		ControllerUUID: controllerUUID,
+		CACert:         testing.CACert,
+		Cloud:          "mycloud",
+		CloudRegion:    "a-region",
+		APIEndpoints:   []string{"10.0.1.1:17777"},
+	}
+	s.store.Accounts["test-master"] = jujuclient.AccountDetails{
+		User:     "admin",
+		Password: "sekrit",
+	}
+	s.PatchValue(backups.PrepareEnviron,
+		func(*cmd.Context, jujuclient.ClientStore, string) (environs.Environ, *jujuclient.BootstrapConfig, environs.CloudSpec, error) {
+			return env, &jujuclient.BootstrapConfig{
+				ControllerConfig: jujucontroller.Config{
+					jujucontroller.ControllerUUIDKey: controllerUUID,
+					jujucontroller.APIPort:           17070,
+				},
+				Credential: "cred",
+			}, environs.CloudSpec{
+				Type:   "dummy",
+				Name:   "mycloud",
+				Region: "a-region",
+			}, nil
+		},
+	)
+	s.PatchValue(backups.BootstrapFunc,
+		func(environs.BootstrapContext, environs.Environ, context.ProviderCallContext, bootstrap.BootstrapParams) error {
+			return errors.New("unexpected bootstrap")
+		},
+	)
+	s.PatchValue(backups.BootstrapEndpointAddresses,
+		func(environs.Environ, context.ProviderCallContext) ([]network.Address, error) {
+			return network.NewAddresses("54.0.0.1"), nil
+		},
+	)
+	s.PatchValue(backups.WaitForAgentInitialisation,
+		func(*cmd.Context, *modelcmd.ModelCommandBase, string, string) error {
+			return errors.New("unexpected wait for agents")
+		},
+	)
+	return ctlr, apiClient, archiveReader, meta
+}
+
+func (s *restoreSuite) TestBuildController(c *gc.C) {
+	env := &fakeEnviron{}
+	ctlr, apiClient, archiveReader, meta := s.patchBuildController(c, env)
+	defer ctlr.Finish()
+
+	var bootstrapParams bootstrap.BootstrapParams
+	s.PatchValue(backups.BootstrapFunc,
+		func(_ environs.BootstrapContext, bootstrapEnv environs.Environ, _ context.ProviderCallContext, args bootstrap.BootstrapParams) error {
+			c.Check(bootstrapEnv, gc.Equals, env)
+			bootstrapParams = args
+			return nil
+		},
+	)
+	var waitedFor []string
+	s.PatchValue(backups.WaitForAgentInitialisation,
+		func(_ *cmd.Context, _ *modelcmd.ModelCommandBase, controllerName, modelName string) error {
+			waitedFor = []string{controllerName, modelName}
+			return nil
+		},
+	)
+	gomock.InOrder(
+		apiClient.EXPECT().RestoreReader(archiveReader, meta, gomock.Any()).Return(nil),
+		apiClient.EXPECT().Close(),
+		archiveReader.EXPECT().Close(),
+	)
+
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "restore", "--build-controller", "--file", "afile",
+		"--constraints", "mem=8G")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(bootstrapParams.CAPrivateKey, gc.Equals, testing.CAKey)
+	caCert, _ := bootstrapParams.ControllerConfig.CACert()
+	c.Check(caCert, gc.Equals, testing.CACert)
+	c.Check(bootstrapParams.ControllerConfig.ControllerUUID(), gc.Equals, controllerUUID)
+	c.Check(bootstrapParams.BootstrapSeries, gc.Equals, "xenial")
+	c.Check(bootstrapParams.BootstrapConstraints, jc.DeepEquals, constraints.MustParse("mem=8G"))
+	c.Check(bootstrapParams.ModelConstraints, jc.DeepEquals, constraints.MustParse("mem=8G"))
+	c.Check(bootstrapParams.AdminSecret, gc.Equals, "sekrit")
+	c.Check(bootstrapParams.CloudRegion, gc.Equals, "a-region")
+	c.Check(bootstrapParams.CloudCredentialName, gc.Equals, "cred")
+
+	details, err := s.store.ControllerByName("test-master")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.APIEndpoints, jc.DeepEquals, []string{"54.0.0.1:17070"})
+	c.Check(waitedFor, jc.DeepEquals, []string{"test-master", "controller"})
+}
+
+func (s *restoreSuite) TestBuildControllerNoCA(c *gc.C) {
+	ctlr, _, archiveReader, meta := s.patchBuildController(c, &fakeEnviron{})
+	defer ctlr.Finish()
+	meta.CAPrivateKey = ""
+	archiveReader.EXPECT().Close()
+
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "restore", "--build-controller", "--file", "afile")
+	c.Assert(err, gc.ErrorMatches, "backup does not contain the controller CA, cannot build a new controller")
+}
+
+func (s *restoreSuite) TestBuildControllerNoPassword(c *gc.C) {
+	ctlr, _, archiveReader, _ := s.patchBuildController(c, &fakeEnviron{})
+	defer ctlr.Finish()
+	s.store.Accounts["test-master"] = jujuclient.AccountDetails{User: "admin"}
+	archiveReader.EXPECT().Close()
+
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "restore", "--build-controller", "--file", "afile")
+	c.Assert(err, gc.ErrorMatches,
+		`cannot build a new controller: no password is stored for user "admin" on controller "test-master"`)
+}
+
+func (s *restoreSuite) TestBuildControllerInstancesStillExist(c *gc.C) {
+	env := &fakeEnviron{instances: []instance.Instance{&fakeInstance{id: "inst-0"}}}
+	ctlr, _, archiveReader, _ := s.patchBuildController(c, env)
+	defer ctlr.Finish()
+	archiveReader.EXPECT().Close()
+
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "restore", "--build-controller", "--file", "afile")
+	c.Assert(err, gc.ErrorMatches, "controller instances inst-0 still exist; will not build a new controller")
+	c.Check(env.controllerUUID, gc.Equals, controllerUUID)
+}
+
+// fakeEnviron is an environ with the given controller instances.
+type fakeEnviron struct {
+	environs.Environ
+	instances      []instance.Instance
+	controllerUUID string
+}
+
+func (e *fakeEnviron) ControllerInstances(_ context.ProviderCallContext, controllerUUID string) ([]instance.Id, error) {
+	e.controllerUUID = controllerUUID
+	if len(e.instances) == 0 {
+		return nil, environs.ErrNoInstances
+	}
+	ids := make([]instance.Id, len(e.instances))
+	for i, inst := range e.instances {
+		ids[i] = inst.Id()
+	}
+	return ids, nil
+}
+
+func (e *fakeEnviron) Instances(_ context.ProviderCallContext, ids []instance.Id) ([]instance.Instance, error) {
+	return e.instances, nil
+}
+
+type fakeInstance struct {
+	instance.Instance
+	id instance.Id
+}
+
+func (i *fakeInstance) Id() instance.Id {
+	return i.id
+}
@@ -0,0 +1,281 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// ControllerMembersAPI defines the methods on the highavailability
+// API used by the controller member commands.
+type ControllerMembersAPI interface {
+	Close() error
+	ControllerMembers() ([]params.ControllerMember, error)
+	RemoveControllerMember(machineIds ...string) error
+	StepDown() error
+}
+
+// controllerMembersBase holds what is common to the controller member
+// commands.
+type controllerMembersBase struct {
+	modelcmd.ControllerCommandBase
+
+	newAPIFunc func() (ControllerMembersAPI, error)
+}
+
+func (c *controllerMembersBase) newAPI() (ControllerMembersAPI, error) {
+	if c.newAPIFunc != nil {
+		return c.newAPIFunc()
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return highavailability.NewClient(root), nil
+}
+
+func newControllerMembersCommand() cmd.Command {
+	return modelcmd.WrapController(&controllerMembersCommand{})
+}
+
+// controllerMembersCommand shows the status of each controller machine.
+type controllerMembersCommand struct {
+	controllerMembersBase
+	out cmd.Output
+}
+
+const controllerMembersDoc = `
+Shows each controller machine along with its replica-set state, whether
+it holds (or wants) a vote, how far it lags behind the primary, its role
+in the raft cluster, and the address on which it serves the API.
+
+A vote of "adding" or "removing" indicates that the peergrouper has yet
+to catch up with a requested change.
+
+Examples:
+    juju controller-members
+    juju controller-members --format yaml
+
+See also:
+    enable-ha
+    remove-controller-member
+    step-down
+`
+
+func (c *controllerMembersCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "controller-members",
+		Purpose: "Shows the status of the controller machines.",
+		Doc:     controllerMembersDoc,
+	}
+}
+
+func (c *controllerMembersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatControllerMembersTabular,
+	})
+}
+
+func (c *controllerMembersCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+type controllerMember struct {
+	Machine    string  `json:"machine" yaml:"machine"`
+	Vote       string  `json:"vote" yaml:"vote"`
+	MongoState string  `json:"mongo-state,omitempty" yaml:"mongo-state,omitempty"`
+	Healthy    bool    `json:"healthy" yaml:"healthy"`
+	OptimeLag  float64 `json:"optime-lag" yaml:"optime-lag"`
+	RaftRole   string  `json:"raft-role,omitempty" yaml:"raft-role,omitempty"`
+	APIAddress string  `json:"api-address,omitempty" yaml:"api-address,omitempty"`
+}
+
+func memberVote(m params.ControllerMember) string {
+	switch {
+	case m.HasVote && m.WantsVote:
+		return "yes"
+	case m.HasVote:
+		return "removing"
+	case m.WantsVote:
+		return "adding"
+	}
+	return "no"
+}
+
+func (c *controllerMembersCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	members, err := client.ControllerMembers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make([]controllerMember, len(members))
+	for i, m := range members {
+		machine := m.Tag
+		if tag, err := names.ParseMachineTag(m.Tag); err == nil {
+			machine = tag.Id()
+		}
+		result[i] = controllerMember{
+			Machine:    machine,
+			Vote:       memberVote(m),
+			MongoState: m.MongoState,
+			Healthy:    m.Healthy,
+			OptimeLag:  m.OptimeLag,
+			RaftRole:   m.RaftRole,
+			APIAddress: m.APIAddress,
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func formatControllerMembersTabular(writer io.Writer, value interface{}) error {
+	members, ok := value.([]controllerMember)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", members, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Machine", "Vote", "Mongo", "Healthy", "Lag", "Raft", "API address")
+	for _, m := range members {
+		mongoState, raftRole, apiAddress := m.MongoState, m.RaftRole, m.APIAddress
+		if mongoState == "" {
+			mongoState = "-"
+		}
+		if raftRole == "" {
+			raftRole = "-"
+		}
+		if apiAddress == "" {
+			apiAddress = "-"
+		}
+		w.Println(m.Machine, m.Vote, mongoState, m.Healthy, fmt.Sprintf("%.0fs", m.OptimeLag), raftRole, apiAddress)
+	}
+	tw.Flush()
+	return nil
+}
+
+func newRemoveControllerMemberCommand() cmd.Command {
+	return modelcmd.WrapController(&removeControllerMemberCommand{})
+}
+
+// removeControllerMemberCommand removes machines from the set of
+// controllers.
+type removeControllerMemberCommand struct {
+	controllerMembersBase
+	machineIds []string
+}
+
+const removeControllerMemberDoc = `
+Removes the specified machines from the set of controllers. The machine
+first loses its vote, then leaves the replica-set, and is then removed.
+
+A machine is only removed if the remaining voting members are healthy
+enough to keep a majority; the last controller can never be removed.
+
+Examples:
+    juju remove-controller-member 2
+
+See also:
+    controller-members
+    enable-ha
+`
+
+func (c *removeControllerMemberCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-controller-member",
+		Args:    "<machine> ...",
+		Purpose: "Removes machines from the set of controllers.",
+		Doc:     removeControllerMemberDoc,
+	}
+}
+
+func (c *removeControllerMemberCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.machineIds = args
+	return nil
+}
+
+func (c *removeControllerMemberCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.RemoveControllerMember(c.machineIds...); err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	return nil
+}
+
+func newStepDownCommand() cmd.Command {
+	return modelcmd.WrapController(&stepDownCommand{})
+}
+
+// stepDownCommand forces the election of a new mongo primary.
+type stepDownCommand struct {
+	controllerMembersBase
+}
+
+const stepDownDoc = `
+Asks the current primary controller to step down, forcing a new primary
+to be elected from the remaining voting members. This is useful before
+taking the primary's machine down for maintenance.
+
+Examples:
+    juju step-down
+
+See also:
+    controller-members
+`
+
+func (c *stepDownCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "step-down",
+		Purpose: "Forces the election of a new primary controller.",
+		Doc:     stepDownDoc,
+	}
+}
+
+func (c *stepDownCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *stepDownCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.StepDown(); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ControllerMembersSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	api *fakeControllerMembersAPI
+}
+
+var _ = gc.Suite(&ControllerMembersSuite{})
+
+func (s *ControllerMembersSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeControllerMembersAPI{
+		members: []params.ControllerMember{{
+			Tag:        "machine-0",
+			WantsVote:  true,
+			HasVote:    true,
+			MongoState: "PRIMARY",
+			Healthy:    true,
+			RaftRole:   "follower",
+			APIAddress: "10.0.0.1:17070",
+		}, {
+			Tag:        "machine-1",
+			WantsVote:  true,
+			HasVote:    true,
+			MongoState: "SECONDARY",
+			Healthy:    true,
+			OptimeLag:  2,
+			RaftRole:   "leader",
+			APIAddress: "10.0.0.2:17070",
+		}, {
+			Tag:       "machine-2",
+			WantsVote: true,
+		}},
+	}
+}
+
+type fakeControllerMembersAPI struct {
+	jujutesting.Stub
+	members []params.ControllerMember
+}
+
+func (f *fakeControllerMembersAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeControllerMembersAPI) ControllerMembers() ([]params.ControllerMember, error) {
+	f.MethodCall(f, "ControllerMembers")
+	return f.members, f.NextErr()
+}
+
+func (f *fakeControllerMembersAPI) RemoveControllerMember(machineIds ...string) error {
+	f.MethodCall(f, "RemoveControllerMember", machineIds)
+	return f.NextErr()
+}
+
+func (f *fakeControllerMembersAPI) StepDown() error {
+	f.MethodCall(f, "StepDown")
+	return f.NextErr()
+}
+
+func (s *ControllerMembersSuite) run(c *gc.C, command cmd.Command, args ...string) (*cmd.Context, error) {
+	wrapped := modelcmd.WrapController(command)
+	wrapped.SetClientStore(jujuclienttesting.MinimalStore())
+	return cmdtesting.RunCommand(c, wrapped, args...)
+}
+
+func (s *ControllerMembersSuite) base() controllerMembersBase {
+	return controllerMembersBase{
+		newAPIFunc: func() (ControllerMembersAPI, error) {
+			return s.api, nil
+		},
+	}
+}
+
+func (s *ControllerMembersSuite) TestControllerMembersTabular(c *gc.C) {
+	ctx, err := s.run(c, &controllerMembersCommand{controllerMembersBase: s.base()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Machine  Vote    Mongo      Healthy  Lag  Raft      API address\n"+
+		"0        yes     PRIMARY    true     0s   follower  10.0.0.1:17070\n"+
+		"1        yes     SECONDARY  true     2s   leader    10.0.0.2:17070\n"+
+		"2        adding  -          false    0s   -         -\n")
+	s.api.CheckCallNames(c, "ControllerMembers", "Close")
+}
+
+func (s *ControllerMembersSuite) TestControllerMembersYAML(c *gc.C) {
+	ctx, err := s.run(c, &controllerMembersCommand{controllerMembersBase: s.base()}, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"- machine: \"0\"\n"+
+		"  vote: \"yes\"\n"+
+		"  mongo-state: PRIMARY\n"+
+		"  healthy: true\n"+
+		"  optime-lag: 0\n"+
+		"  raft-role: follower\n"+
+		"  api-address: 10.0.0.1:17070\n"+
+		"- machine: \"1\"\n"+
+		"  vote: \"yes\"\n"+
+		"  mongo-state: SECONDARY\n"+
+		"  healthy: true\n"+
+		"  optime-lag: 2\n"+
+		"  raft-role: leader\n"+
+		"  api-address: 10.0.0.2:17070\n"+
+		"- machine: \"2\"\n"+
+		"  vote: adding\n"+
+		"  healthy: false\n"+
+		"  optime-lag: 0\n")
+}
+
+func (s *ControllerMembersSuite) TestControllerMembersError(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	_, err := s.run(c, &controllerMembersCommand{controllerMembersBase: s.base()})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ControllerMembersSuite) TestRemoveControllerMember(c *gc.C) {
+	_, err := s.run(c, &removeControllerMemberCommand{controllerMembersBase: s.base()}, "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"RemoveControllerMember", []interface{}{[]string{"1", "2"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *ControllerMembersSuite) TestRemoveControllerMemberInit(c *gc.C) {
+	_, err := s.run(c, &removeControllerMemberCommand{controllerMembersBase: s.base()})
+	c.Assert(err, gc.ErrorMatches, "no machines specified")
+	_, err = s.run(c, &removeControllerMemberCommand{controllerMembersBase: s.base()}, "foo")
+	c.Assert(err, gc.ErrorMatches, `invalid machine id "foo"`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *ControllerMembersSuite) TestRemoveControllerMemberBlocked(c *gc.C) {
+	s.api.SetErrors(common.OperationBlockedError("TestRemoveControllerMemberBlocked"))
+	_, err := s.run(c, &removeControllerMemberCommand{controllerMembersBase: s.base()}, "1")
+	coretesting.AssertOperationWasBlocked(c, err, ".*TestRemoveControllerMemberBlocked.*")
+}
+
+func (s *ControllerMembersSuite) TestStepDown(c *gc.C) {
+	_, err := s.run(c, &stepDownCommand{controllerMembersBase: s.base()})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "StepDown", "Close")
+}
+
+func (s *ControllerMembersSuite) TestStepDownArgs(c *gc.C) {
+	_, err := s.run(c, &stepDownCommand{controllerMembersBase: s.base()}, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
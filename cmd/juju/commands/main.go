@@ -339,6 +339,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage users and access
 	r.Register(user.NewAddCommand())
+	r.Register(user.NewAddGroupCommand())
+	r.Register(user.NewAddGroupMemberCommand())
 	r.Register(user.NewChangePasswordCommand())
 	r.Register(user.NewShowUserCommand())
 	r.Register(user.NewListCommand())
@@ -348,6 +350,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(user.NewLogoutCommand())
 	r.Register(user.NewRemoveCommand())
 	r.Register(user.NewWhoAmICommand())
+	r.Register(user.NewAddTokenCommand())
+	r.Register(user.NewListTokensCommand())
+	r.Register(user.NewRevokeTokenCommand())
 
 	// Manage cached images
 	r.Register(cachedimages.NewRemoveCommand())
@@ -356,6 +361,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage machines
 	r.Register(machine.NewAddCommand())
 	r.Register(machine.NewRemoveCommand())
+	r.Register(machine.NewReplaceCommand())
+	r.Register(machine.NewMaintenanceCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
 
@@ -370,7 +377,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewDestroyCommand())
 	r.Register(model.NewGrantCommand())
 	r.Register(model.NewRevokeCommand())
+	r.Register(application.NewGrantApplicationCommand())
+	r.Register(application.NewRevokeApplicationCommand())
 	r.Register(model.NewShowCommand())
+	r.Register(model.NewEstimateCostCommand())
 
 	r.Register(newMigrateCommand())
 	if featureflag.Enabled(feature.DeveloperMode) {
@@ -387,6 +397,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage controller availability
 	r.Register(newEnableHACommand())
+	r.Register(newControllerMembersCommand())
+	r.Register(newRemoveControllerMemberCommand())
+	r.Register(newStepDownCommand())
 
 	// Manage and control applications
 	r.Register(application.NewAddUnitCommand())
@@ -442,6 +455,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(controller.NewEnableDestroyControllerCommand())
 	r.Register(controller.NewShowControllerCommand())
 	r.Register(controller.NewConfigCommand())
+	r.Register(controller.NewRotateControllerCACommand())
 
 	// Debug Metrics
 	r.Register(metricsdebug.New())
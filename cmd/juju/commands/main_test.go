@@ -392,6 +392,8 @@ var commandNames = []string{
 	"actions",
 	"add-cloud",
 	"add-credential",
+	"add-group",
+	"add-group-member",
 	"add-k8s",
 	"add-machine",
 	"add-model",
@@ -400,6 +402,7 @@ var commandNames = []string{
 	"add-ssh-key",
 	"add-storage",
 	"add-subnet",
+	"add-token",
 	"add-unit",
 	"add-user",
 	"agree",
@@ -421,6 +424,7 @@ var commandNames = []string{
 	"config",
 	"consume",
 	"controller-config",
+	"controller-members",
 	"controllers",
 	"create-backup",
 	"create-storage-pool",
@@ -440,12 +444,14 @@ var commandNames = []string{
 	"enable-destroy-controller",
 	"enable-ha",
 	"enable-user",
+	"estimate-cost",
 	"expose",
 	"find-offers",
 	"firewall-rules",
 	"get-constraints",
 	"get-model-constraints",
 	"grant",
+	"grant-application",
 	"gui",
 	"help",
 	"help-tool",
@@ -476,11 +482,13 @@ var commandNames = []string{
 	"list-storage",
 	"list-storage-pools",
 	"list-subnets",
+	"list-tokens",
 	"list-users",
 	"list-wallets",
 	"login",
 	"logout",
 	"machines",
+	"maintenance-machine",
 	"metrics",
 	"migrate",
 	"model-config",
@@ -500,6 +508,7 @@ var commandNames = []string{
 	"remove-cached-images",
 	"remove-cloud",
 	"remove-consumed-application",
+	"remove-controller-member",
 	"remove-credential",
 	"remove-k8s",
 	"remove-machine",
@@ -510,6 +519,7 @@ var commandNames = []string{
 	"remove-storage",
 	"remove-unit",
 	"remove-user",
+	"replace-machine",
 	"resolved",
 	"resolve",
 	"resources",
@@ -517,6 +527,9 @@ var commandNames = []string{
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
+	"revoke-application",
+	"revoke-token",
+	"rotate-controller-ca",
 	"run",
 	"run-action",
 	"scp",
@@ -549,6 +562,7 @@ var commandNames = []string{
 	"ssh",
 	"ssh-keys",
 	"status",
+	"step-down",
 	"storage",
 	"storage-pools",
 	"subnets",
@@ -556,6 +570,7 @@ var commandNames = []string{
 	"switch",
 	"sync-agent-binaries",
 	"sync-tools",
+	"tokens",
 	"trust",
 	"unexpose",
 	"unregister",
@@ -4,14 +4,18 @@
 package commands
 
 import (
+	"fmt"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"gopkg.in/macaroon-bakery.v2-unstable/httpbakery"
 	"gopkg.in/macaroon.v2-unstable"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/controller"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/jujuclient"
 )
 
@@ -24,14 +28,15 @@ func newMigrateCommand() modelcmd.ModelCommand {
 // migrateCommand initiates a model migration.
 type migrateCommand struct {
 	modelcmd.ModelCommandBase
-	modelcmd.IAASOnlyCommand
 	newAPIRoot       func(jujuclient.ClientStore, string, string) (api.Connection, error)
 	api              migrateAPI
 	targetController string
+	dryRun           bool
 }
 
 type migrateAPI interface {
 	InitiateMigration(spec controller.MigrationSpec) (string, error)
+	PrecheckMigration(spec controller.MigrationSpec) (migration.PrecheckReport, error)
 }
 
 const migrateDoc = `
@@ -57,6 +62,13 @@ This command only starts a model migration - it does not wait for its
 completion. The progress of a migration can be tracked using the
 "status" command and by consulting the logs.
 
+With --dry-run, the migration is not started. Instead all of the checks
+that are made before a migration starts are run against the model, the
+source controller and the target controller, and every problem found
+is reported, grouped by the entity it relates to. The model is also
+exported and validated by the target controller, without being
+imported.
+
 See also:
     login
     controllers
@@ -73,6 +85,12 @@ func (c *migrateCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements cmd.Command.
+func (c *migrateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Check whether the model could be migrated, without migrating it")
+}
+
 // Init implements cmd.Command.
 func (c *migrateCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -141,6 +159,13 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	if c.dryRun {
+		report, err := api.PrecheckMigration(*spec)
+		if err != nil {
+			return err
+		}
+		return c.printReport(ctx, modelName, report)
+	}
 	id, err := api.InitiateMigration(*spec)
 	if err != nil {
 		return err
@@ -149,6 +174,22 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+func (c *migrateCommand) printReport(ctx *cmd.Context, modelName string, report migration.PrecheckReport) error {
+	if report.Empty() {
+		fmt.Fprintf(ctx.Stdout, "No problems found, model %q can be migrated to %q\n", modelName, c.targetController)
+		return nil
+	}
+	fmt.Fprintf(ctx.Stdout, "Model %q can't be migrated to %q:\n", modelName, c.targetController)
+	keys, grouped := report.ByEntity()
+	for _, key := range keys {
+		fmt.Fprintf(ctx.Stdout, "\n%s\n", key)
+		for _, problem := range grouped[key] {
+			fmt.Fprintf(ctx.Stdout, "  - %s\n", problem.Message)
+		}
+	}
+	return cmd.ErrSilent
+}
+
 func (c *migrateCommand) getAPI() (migrateAPI, error) {
 	if c.api != nil {
 		return c.api, nil
@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/api/controller"
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/testing"
@@ -91,6 +92,11 @@ func (s *MigrateSuite) SetUpTest(c *gc.C) {
 			UUID:  "prod-2-uuid",
 			Type:  model.IAAS,
 			Owner: "sourceuser",
+		}, {
+			Name:  "k8s-model",
+			UUID:  "k8s-uuid",
+			Type:  model.CAAS,
+			Owner: "sourceuser",
 		}},
 	}
 
@@ -181,6 +187,45 @@ func (s *MigrateSuite) TestSuccessMacaroons(c *gc.C) {
 	})
 }
 
+func (s *MigrateSuite) TestCAASModel(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "k8s-model", "target")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(cmdtesting.Stderr(ctx), gc.Matches, "Migration started with ID \"uuid:0\"\n")
+	c.Check(s.api.specSeen.ModelUUID, gc.Equals, "k8s-uuid")
+}
+
+func (s *MigrateSuite) TestDryRunNoProblems(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "model", "target", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.api.dryRun, jc.IsTrue)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "No problems found, model \"model\" can be migrated to \"target\"\n")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "")
+	c.Check(s.api.specSeen.ModelUUID, gc.Equals, modelUUID)
+}
+
+func (s *MigrateSuite) TestDryRunProblems(c *gc.C) {
+	s.api.report.Add(migration.PrecheckScopeModel, "unit-foo-0", "unit foo/0 is dead")
+	s.api.report.Add(migration.PrecheckScopeSourceController, "machine-0", "machine 0 is scheduled to reboot")
+	s.api.report.Add(migration.PrecheckScopeModel, "unit-foo-0", "unit foo/0 is upgrading")
+
+	ctx, err := s.makeAndRun(c, "model", "target", "--dry-run")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+
+	c.Check(s.api.dryRun, jc.IsTrue)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Model "model" can't be migrated to "target":
+
+model unit-foo-0
+  - unit foo/0 is dead
+  - unit foo/0 is upgrading
+
+source-controller machine-0
+  - machine 0 is scheduled to reboot
+`[1:])
+}
+
 func (s *MigrateSuite) TestModelDoesntExist(c *gc.C) {
 	cmd := s.makeCommand()
 	_, err := cmdtesting.RunCommand(c, cmd, "wat", "target")
@@ -237,6 +282,14 @@ func (s *MigrateSuite) makeCommand() modelcmd.ModelCommand {
 
 type fakeMigrateAPI struct {
 	specSeen *controller.MigrationSpec
+	report   migration.PrecheckReport
+	dryRun   bool
+}
+
+func (a *fakeMigrateAPI) PrecheckMigration(spec controller.MigrationSpec) (migration.PrecheckReport, error) {
+	a.specSeen = &spec
+	a.dryRun = true
+	return a.report, nil
 }
 
 func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (string, error) {
@@ -39,6 +39,7 @@ type ModelInfo struct {
 	SLAOwner       string                      `json:"sla-owner,omitempty" yaml:"sla-owner,omitempty"`
 	AgentVersion   string                      `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
 	Credential     *ModelCredential            `json:"credential,omitempty" yaml:"credential,omitempty"`
+	Cost           *ModelCost                  `json:"cost,omitempty" yaml:"cost,omitempty"`
 }
 
 // ModelMachineInfo contains information about a machine in a model.
@@ -48,6 +49,16 @@ type ModelMachineInfo struct {
 	Cores uint64 `json:"cores" yaml:"cores"`
 }
 
+// ModelCost contains the estimated monthly cost of running a model.
+type ModelCost struct {
+	Currency string  `json:"currency" yaml:"currency"`
+	Monthly  float64 `json:"monthly" yaml:"monthly"`
+
+	// Unpriced holds the names of the machines and volumes
+	// whose cost could not be estimated.
+	Unpriced []string `json:"unpriced,omitempty" yaml:"unpriced,omitempty"`
+}
+
 // ModelStatus contains the current status of a model.
 type ModelStatus struct {
 	Current        status.Status `json:"current,omitempty" yaml:"current,omitempty"`
@@ -189,3 +189,13 @@ func NewData(api destroyControllerAPI, ctrUUID string) (ctrData, []modelData, er
 var (
 	NoModelsMessage = noModelsMessage
 )
+
+// NewRotateControllerCACommandForTest returns a rotateControllerCACommand
+// with the API client mocked out.
+func NewRotateControllerCACommandForTest(api rotateControllerCAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &rotateControllerCACommand{
+		api: api,
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
@@ -0,0 +1,183 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewRotateControllerCACommand returns a command that rotates the
+// controller's CA certificate.
+func NewRotateControllerCACommand() cmd.Command {
+	return modelcmd.WrapController(&rotateControllerCACommand{})
+}
+
+type rotateControllerCACommand struct {
+	modelcmd.ControllerCommandBase
+	api rotateControllerCAAPI
+
+	status bool
+	issue  bool
+	retire bool
+}
+
+type rotateControllerCAAPI interface {
+	Close() error
+	RotateControllerCA() (controller.CARotationStatus, error)
+	ControllerCARotationStatus() (controller.CARotationStatus, error)
+	IssueControllerCertsFromNewCA() error
+	RetireOldControllerCA() error
+}
+
+const rotateControllerCADoc = `
+Rotating the controller CA replaces the certificate authority that issues
+the controller's server certificates, without losing contact with any
+agent or client. It happens in three steps:
+
+Running the command without options generates a new CA, which the
+controller trusts alongside the current one. Every machine and unit agent,
+and every application operator in a Kubernetes model, records the new CA
+in its agent configuration. Clients record it when they next log in.
+
+Once the status reports that every agent trusts the new CA, --issue has
+each controller re-issue its API server and database certificates from
+the new CA, restarting the database to load its certificate.
+
+Once the status reports that no controller is pending, --retire stops
+trusting the old CA. Clients are not tracked, so the status does not
+report which have yet to record the new CA. Clients that have not logged
+in since the rotation started will need the new CA certificate, as shown
+by show-controller.
+
+Examples:
+
+    juju rotate-controller-ca
+    juju rotate-controller-ca --status
+    juju rotate-controller-ca --issue
+    juju rotate-controller-ca --retire
+
+See also:
+    show-controller
+`
+
+// Info implements Command.Info.
+func (c *rotateControllerCACommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rotate-controller-ca",
+		Purpose: "Rotates the controller's CA certificate.",
+		Doc:     rotateControllerCADoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *rotateControllerCACommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.status, "status", false, "Show the progress of the rotation in progress")
+	f.BoolVar(&c.issue, "issue", false, "Issue server certificates from the new CA")
+	f.BoolVar(&c.retire, "retire", false, "Stop trusting the old CA")
+}
+
+// Init implements Command.Init.
+func (c *rotateControllerCACommand) Init(args []string) error {
+	steps := 0
+	for _, set := range []bool{c.status, c.issue, c.retire} {
+		if set {
+			steps++
+		}
+	}
+	if steps > 1 {
+		return errors.New("only one of --status, --issue and --retire may be specified")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *rotateControllerCACommand) getAPI() (rotateControllerCAAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewControllerAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *rotateControllerCACommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	var status controller.CARotationStatus
+	switch {
+	case c.status:
+		status, err = client.ControllerCARotationStatus()
+	case c.issue:
+		if err := client.IssueControllerCertsFromNewCA(); err != nil {
+			return errors.Trace(err)
+		}
+		status, err = client.ControllerCARotationStatus()
+	case c.retire:
+		if err := client.RetireOldControllerCA(); err != nil {
+			return errors.Trace(err)
+		}
+		status, err = client.ControllerCARotationStatus()
+	default:
+		status, err = client.RotateControllerCA()
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.updateCACert(status.CACert); err != nil {
+		return errors.Trace(err)
+	}
+	printCARotationStatus(ctx, status)
+	return nil
+}
+
+// updateCACert records the controller's current CA certificates
+// locally, so this client keeps trusting the controller.
+func (c *rotateControllerCACommand) updateCACert(caCert string) error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := c.ClientStore()
+	details, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if details.CACert == caCert {
+		return nil
+	}
+	details.CACert = caCert
+	return errors.Trace(store.UpdateController(controllerName, *details))
+}
+
+func printCARotationStatus(ctx *cmd.Context, status controller.CARotationStatus) {
+	switch status.Phase {
+	case "":
+		fmt.Fprintln(ctx.Stdout, "No controller CA rotation in progress.")
+	case "trust":
+		if len(status.UntrustingAgents) == 0 {
+			fmt.Fprintln(ctx.Stdout, "All agents trust the new CA; run with --issue to continue.")
+			return
+		}
+		fmt.Fprintf(ctx.Stdout, "Waiting for %d agent(s) to trust the new CA:\n", len(status.UntrustingAgents))
+		fmt.Fprintln(ctx.Stdout, "  "+strings.Join(status.UntrustingAgents, "\n  "))
+	case "issue":
+		if len(status.PendingControllers) == 0 {
+			fmt.Fprintln(ctx.Stdout, "All controllers use certificates from the new CA; run with --retire to finish.")
+			return
+		}
+		fmt.Fprintf(ctx.Stdout, "Waiting for controller machine(s) %s to re-issue their certificates.\n",
+			strings.Join(status.PendingControllers, ", "))
+	}
+}
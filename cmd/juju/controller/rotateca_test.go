@@ -0,0 +1,122 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apicontroller "github.com/juju/juju/api/controller"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/jujuclient"
+)
+
+type rotateControllerCASuite struct {
+	baseControllerSuite
+	api   *fakeRotateControllerCAAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&rotateControllerCASuite{})
+
+func (s *rotateControllerCASuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	s.api = &fakeRotateControllerCAAPI{
+		status: apicontroller.CARotationStatus{
+			Phase:            "trust",
+			CACert:           "old+new",
+			NewCACert:        "new",
+			UntrustingAgents: []string{"uuid:unit-mysql-0"},
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{CACert: "old"}
+}
+
+func (s *rotateControllerCASuite) newCommand() cmd.Command {
+	return controller.NewRotateControllerCACommandForTest(s.api, s.store)
+}
+
+func (s *rotateControllerCASuite) TestStart(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "RotateControllerCA", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Waiting for 1 agent(s) to trust the new CA:
+  uuid:unit-mysql-0
+`[1:])
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "old+new")
+}
+
+func (s *rotateControllerCASuite) TestIssue(c *gc.C) {
+	s.api.status = apicontroller.CARotationStatus{
+		Phase:              "issue",
+		CACert:             "new+old",
+		NewCACert:          "new",
+		PendingControllers: []string{"0", "1"},
+	}
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--issue")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "IssueControllerCertsFromNewCA", "ControllerCARotationStatus", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		"Waiting for controller machine(s) 0, 1 to re-issue their certificates.\n")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "new+old")
+}
+
+func (s *rotateControllerCASuite) TestRetire(c *gc.C) {
+	s.api.status = apicontroller.CARotationStatus{CACert: "new"}
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--retire")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "RetireOldControllerCA", "ControllerCARotationStatus", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No controller CA rotation in progress.\n")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "new")
+}
+
+func (s *rotateControllerCASuite) TestRetireError(c *gc.C) {
+	s.api.SetErrors(errors.New("cannot retire the old controller CA: boom"))
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--retire")
+	c.Assert(err, gc.ErrorMatches, "cannot retire the old controller CA: boom")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "old")
+}
+
+func (s *rotateControllerCASuite) TestConflictingFlags(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--issue", "--retire")
+	c.Assert(err, gc.ErrorMatches, "only one of --status, --issue and --retire may be specified")
+}
+
+type fakeRotateControllerCAAPI struct {
+	jujutesting.Stub
+	status apicontroller.CARotationStatus
+}
+
+func (f *fakeRotateControllerCAAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeRotateControllerCAAPI) RotateControllerCA() (apicontroller.CARotationStatus, error) {
+	f.MethodCall(f, "RotateControllerCA")
+	return f.status, f.NextErr()
+}
+
+func (f *fakeRotateControllerCAAPI) ControllerCARotationStatus() (apicontroller.CARotationStatus, error) {
+	f.MethodCall(f, "ControllerCARotationStatus")
+	return f.status, f.NextErr()
+}
+
+func (f *fakeRotateControllerCAAPI) IssueControllerCertsFromNewCA() error {
+	f.MethodCall(f, "IssueControllerCertsFromNewCA")
+	return f.NextErr()
+}
+
+func (f *fakeRotateControllerCAAPI) RetireOldControllerCA() error {
+	f.MethodCall(f, "RetireOldControllerCA")
+	return f.NextErr()
+}
@@ -5,6 +5,7 @@ package machine
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"time"
@@ -57,6 +58,16 @@ and bringing it under Juju's management; currently this requires that the
 machine be running Ubuntu, that it be accessible via SSH, and be running on
 the same network as the API server.
 
+A machine that is only reachable through an SSH jump host (bastion) may be
+added with --jump-host, giving the jump host as [user@]host[:port]. The jump
+host must accept key based authentication, and the machine must be given by
+an address or a name that resolves locally. If the login user needs a
+password for sudo, sudo prompts for it; alternatively, the password may be
+read from a file with --sudo-password-file. With --dry-run, the machine is
+inspected, and the details it would be added with, and the scripts that would
+prepare it and install its machine agent, are shown, without changing the
+machine or the model.
+
 It is possible to override or augment constraints by passing provider-specific
 "placement directives" as an argument; these give the provider additional
 information about how to allocate the machine. For example, one can direct the
@@ -70,9 +81,15 @@ Examples:
    juju add-machine lxd:4                (starts a new lxd container on machine 4)
    juju add-machine --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju add-machine ssh:user@10.10.0.3   (manually provisions machine with ssh)
+   juju add-machine ssh:user@10.10.0.3 --jump-host admin@bastion
+                                         (manually provisions machine through a bastion)
+   juju add-machine ssh:user@10.10.0.3 --dry-run
+                                         (shows what manual provisioning would do)
    juju add-machine winrm:user@10.10.0.3 (manually provisions machine with winrm)
    juju add-machine zone=us-east-1a      (start a machine in zone us-east-1a on AWS)
    juju add-machine maas2.name           (acquire machine maas2.name on MAAS)
+   juju add-machine zone=z1,storage-layout=lvm
+                                         (acquire a machine in zone z1 on MAAS, with LVM storage)
 
 See also:
     remove-machine
@@ -114,6 +131,15 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// JumpHost is the SSH jump host through which a manually
+	// provisioned machine is reached.
+	JumpHost string
+	// SudoPasswordFile is the file holding the sudo password of the
+	// user that manually provisions a machine.
+	SudoPasswordFile string
+	// DryRun reports what manually provisioning a machine would do,
+	// without doing it.
+	DryRun bool
 }
 
 func (c *addCommand) Info() *cmd.Info {
@@ -131,6 +157,9 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Additional machine constraints")
 	f.Var(disksFlag{&c.Disks}, "disks", "Constraints for disks to attach to the machine")
+	f.StringVar(&c.JumpHost, "jump-host", "", "The SSH jump host, as [user@]host[:port], through which to reach an ssh: machine")
+	f.StringVar(&c.SudoPasswordFile, "sudo-password-file", "", "A file holding the sudo password for an ssh: machine")
+	f.BoolVar(&c.DryRun, "dry-run", false, "Show what adding an ssh: machine would do, without doing it")
 }
 
 func (c *addCommand) Init(args []string) error {
@@ -152,6 +181,16 @@ func (c *addCommand) Init(args []string) error {
 	if c.NumMachines > 1 && c.Placement != nil && c.Placement.Directive != "" {
 		return errors.New("cannot use -n when specifying a placement directive")
 	}
+	if c.Placement == nil || c.Placement.Scope != sshScope {
+		switch {
+		case c.JumpHost != "":
+			return errors.New("--jump-host can only be used with ssh: placement")
+		case c.SudoPasswordFile != "":
+			return errors.New("--sudo-password-file can only be used with ssh: placement")
+		case c.DryRun:
+			return errors.New("--dry-run can only be used with ssh: placement")
+		}
+	}
 	return nil
 }
 
@@ -363,6 +402,15 @@ func (c *addCommand) tryManualProvision(client AddMachineAPI, config *config.Con
 		return errors.Annotatef(err, "cannot reading authorized-keys")
 	}
 
+	var sudoPassword string
+	if c.SudoPasswordFile != "" {
+		data, err := ioutil.ReadFile(ctx.AbsPath(c.SudoPasswordFile))
+		if err != nil {
+			return errors.Annotate(err, "cannot read sudo password")
+		}
+		sudoPassword = strings.TrimRight(string(data), "\r\n")
+	}
+
 	user, host := splitUserHost(c.Placement.Directive)
 	args := manual.ProvisionMachineArgs{
 		Host:           host,
@@ -372,6 +420,9 @@ func (c *addCommand) tryManualProvision(client AddMachineAPI, config *config.Con
 		Stdout:         ctx.Stdout,
 		Stderr:         ctx.Stderr,
 		AuthorizedKeys: authKeys,
+		JumpHost:       c.JumpHost,
+		SudoPassword:   sudoPassword,
+		DryRun:         c.DryRun,
 		UpdateBehavior: &params.UpdateBehavior{
 			EnableOSRefreshUpdate: config.EnableOSRefreshUpdate(),
 			EnableOSUpgrade:       config.EnableOSUpgrade(),
@@ -382,6 +433,9 @@ func (c *addCommand) tryManualProvision(client AddMachineAPI, config *config.Con
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.DryRun {
+		return nil
+	}
 	ctx.Infof("created machine %v", machineId)
 	return nil
 }
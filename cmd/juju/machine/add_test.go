@@ -4,6 +4,9 @@
 package machine_test
 
 import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -90,6 +93,19 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"something:special"},
 			count:     1,
 			placement: "something:special",
+		}, {
+			args:      []string{"ssh:user@10.10.0.3", "--jump-host", "admin@bastion", "--dry-run"},
+			count:     1,
+			placement: "ssh:user@10.10.0.3",
+		}, {
+			args:        []string{"--jump-host", "admin@bastion"},
+			errorString: "--jump-host can only be used with ssh: placement",
+		}, {
+			args:        []string{"winrm:user@10.10.0.3", "--sudo-password-file", "pw"},
+			errorString: "--sudo-password-file can only be used with ssh: placement",
+		}, {
+			args:        []string{"lxd", "--dry-run"},
+			errorString: "--dry-run can only be used with ssh: placement",
 		},
 	} {
 		c.Logf("test %d", i)
@@ -156,6 +172,48 @@ func (s *AddMachineSuite) TestSSHPlacementError(c *gc.C) {
 	c.Assert(cmdtesting.Stderr(context), gc.Equals, "")
 }
 
+func (s *AddMachineSuite) TestSSHPlacementJumpHostAndSudoPassword(c *gc.C) {
+	var provisionArgs manual.ProvisionMachineArgs
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		provisionArgs = args
+		return "42", nil
+	})
+	passwordFile := filepath.Join(c.MkDir(), "password")
+	err := ioutil.WriteFile(passwordFile, []byte("sekrit\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	context, err := s.run(c, "ssh:user@10.1.2.3", "--jump-host", "admin@bastion:2222", "--sudo-password-file", passwordFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(context), gc.Equals, "created machine 42\n")
+	c.Assert(provisionArgs.User, gc.Equals, "user")
+	c.Assert(provisionArgs.Host, gc.Equals, "10.1.2.3")
+	c.Assert(provisionArgs.JumpHost, gc.Equals, "admin@bastion:2222")
+	c.Assert(provisionArgs.SudoPassword, gc.Equals, "sekrit")
+	c.Assert(provisionArgs.DryRun, jc.IsFalse)
+}
+
+func (s *AddMachineSuite) TestSSHPlacementSudoPasswordFileMissing(c *gc.C) {
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		c.Fatalf("unexpected call")
+		return "", nil
+	})
+	_, err := s.run(c, "ssh:10.1.2.3", "--sudo-password-file", filepath.Join(c.MkDir(), "missing"))
+	c.Assert(err, gc.ErrorMatches, "cannot read sudo password: .*")
+}
+
+func (s *AddMachineSuite) TestSSHPlacementDryRun(c *gc.C) {
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		c.Check(args.DryRun, jc.IsTrue)
+		fmt.Fprintln(args.Stdout, "would add a machine")
+		return "", nil
+	})
+	context, err := s.run(c, "ssh:10.1.2.3", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, "would add a machine\n")
+	c.Assert(cmdtesting.Stderr(context), gc.Equals, "")
+	c.Assert(s.fakeAddMachine.args, gc.HasLen, 0)
+}
+
 func (s *AddMachineSuite) TestParamsPassedOn(c *gc.C) {
 	_, err := s.run(c, "--constraints", "mem=8G", "--series=special", "zone=nz")
 	c.Assert(err, jc.ErrorIsNil)
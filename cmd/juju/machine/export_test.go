@@ -5,6 +5,7 @@ package machine
 
 import (
 	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -69,3 +70,33 @@ func NewUpgradeSeriesCommandForTest(api UpgradeMachineSeriesAPI) cmd.Command {
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
+
+type ReplaceCommand struct {
+	*replaceCommand
+}
+
+// NewReplaceCommandForTest returns a ReplaceCommand with the api provided as specified.
+func NewReplaceCommandForTest(apiRoot api.Connection, machineAPI ReplaceMachineAPI) (cmd.Command, *ReplaceCommand) {
+	cmd := &replaceCommand{
+		apiRoot:    apiRoot,
+		machineAPI: machineAPI,
+	}
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(cmd), &ReplaceCommand{cmd}
+}
+
+type MaintenanceCommand struct {
+	*maintenanceCommand
+}
+
+// NewMaintenanceCommandForTest returns a MaintenanceCommand with the api
+// and clock provided as specified.
+func NewMaintenanceCommandForTest(apiRoot api.Connection, machineAPI MaintenanceMachineAPI, clock clock.Clock) (cmd.Command, *MaintenanceCommand) {
+	cmd := &maintenanceCommand{
+		apiRoot:    apiRoot,
+		machineAPI: machineAPI,
+		clock:      clock,
+	}
+	cmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(cmd), &MaintenanceCommand{cmd}
+}
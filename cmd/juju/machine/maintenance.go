@@ -0,0 +1,467 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/action"
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/status"
+)
+
+// PreMaintenanceAction is the name of the charm action run on the units
+// of a machine once it has been put into maintenance.
+const PreMaintenanceAction = "pre-maintenance"
+
+// maintenancePollInterval is how often maintenance-machine checks on the
+// progress of replacement units and pre-maintenance actions.
+const maintenancePollInterval = 5 * time.Second
+
+// NewMaintenanceCommand returns a command used to prepare a machine for
+// maintenance.
+func NewMaintenanceCommand() cmd.Command {
+	return modelcmd.Wrap(&maintenanceCommand{})
+}
+
+// maintenanceCommand puts a machine into maintenance, optionally moving
+// its workload elsewhere first.
+type maintenanceCommand struct {
+	baseMachinesCommand
+	apiRoot    api.Connection
+	machineAPI MaintenanceMachineAPI
+	clock      clock.Clock
+
+	MachineId string
+	Evacuate  bool
+	Done      bool
+	Timeout   time.Duration
+}
+
+const maintenanceMachineDoc = `
+The machine is marked as being in maintenance, and no new units will be
+assigned to it, or to any container on it, until maintenance is done.
+Units already on the machine are left running.
+
+With --evacuate, a replacement unit is added elsewhere for each unit on the
+machine and its containers, and the command waits for the replacements'
+workloads to become active. Replacement units are recorded against the
+machine until maintenance is done, so running the command again does not
+add further replacements for units that already have one.
+
+Finally, the "` + PreMaintenanceAction + `" action is run on each unit on the
+machine whose charm defines it, and the command waits for the actions to
+complete. The machine is then reported as ready for maintenance.
+
+While this is in progress, and until maintenance is done, ` + "`juju status`" + `
+shows the machine's state as "maintenance" along with its progress.
+If a step fails or times out, the machine remains in maintenance; the
+command may be run again, or maintenance ended with --done.
+
+Examples:
+
+Prepare machine 3 for maintenance, moving its workload elsewhere:
+
+    juju maintenance-machine 3 --evacuate
+
+End maintenance on machine 3, allowing units to be assigned to it again:
+
+    juju maintenance-machine 3 --done
+
+See also:
+    add-unit
+    remove-unit
+    run-action
+    status
+`
+
+// Info implements Command.Info.
+func (c *maintenanceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "maintenance-machine",
+		Args:    "<machine>",
+		Purpose: "Prepares a machine for maintenance.",
+		Doc:     maintenanceMachineDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *maintenanceCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.baseMachinesCommand.SetFlags(f)
+	f.BoolVar(&c.Evacuate, "evacuate", false, "Add replacement units elsewhere and wait for them to become active")
+	f.BoolVar(&c.Done, "done", false, "End maintenance on the machine")
+	f.DurationVar(&c.Timeout, "timeout", 30*time.Minute, "How long to wait for replacement units and actions")
+}
+
+// Init implements Command.Init.
+func (c *maintenanceCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no machine specified")
+	}
+	if !names.IsValidMachine(args[0]) {
+		return errors.Errorf("invalid machine id %q", args[0])
+	}
+	if c.Done && c.Evacuate {
+		return errors.Errorf("--done and --evacuate cannot be used together")
+	}
+	if c.Timeout <= 0 {
+		return errors.Errorf("timeout must be positive")
+	}
+	c.MachineId = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// MaintenanceMachineAPI defines the API methods used by the
+// maintenance-machine command.
+type MaintenanceMachineAPI interface {
+	SetMachineMaintenance(machine, message string, replacements map[string]string) (map[string]string, error)
+	Status(patterns []string) (*params.FullStatus, error)
+	AddUnits(application.AddUnitsParams) ([]string, error)
+	ApplicationCharmActions(params.Entity) (map[string]params.ActionSpec, error)
+	Enqueue(params.Actions) (params.ActionResults, error)
+	Actions(params.Entities) (params.ActionResults, error)
+	Close() error
+}
+
+// maintenanceMachineAPI implements MaintenanceMachineAPI using the
+// clients for the facades involved, all sharing one connection.
+type maintenanceMachineAPI struct {
+	root              api.Connection
+	machineClient     *machinemanager.Client
+	applicationClient *application.Client
+	actionClient      *action.Client
+}
+
+func (a *maintenanceMachineAPI) SetMachineMaintenance(machine, message string, replacements map[string]string) (map[string]string, error) {
+	return a.machineClient.SetMachineMaintenance(machine, message, replacements)
+}
+
+func (a *maintenanceMachineAPI) Status(patterns []string) (*params.FullStatus, error) {
+	return a.root.Client().Status(patterns)
+}
+
+func (a *maintenanceMachineAPI) AddUnits(args application.AddUnitsParams) ([]string, error) {
+	return a.applicationClient.AddUnits(args)
+}
+
+func (a *maintenanceMachineAPI) ApplicationCharmActions(arg params.Entity) (map[string]params.ActionSpec, error) {
+	return a.actionClient.ApplicationCharmActions(arg)
+}
+
+func (a *maintenanceMachineAPI) Enqueue(args params.Actions) (params.ActionResults, error) {
+	return a.actionClient.Enqueue(args)
+}
+
+func (a *maintenanceMachineAPI) Actions(args params.Entities) (params.ActionResults, error) {
+	return a.actionClient.Actions(args)
+}
+
+func (a *maintenanceMachineAPI) Close() error {
+	return a.root.Close()
+}
+
+func (c *maintenanceCommand) getAPIRoot() (api.Connection, error) {
+	if c.apiRoot != nil {
+		return c.apiRoot, nil
+	}
+	return c.NewAPIRoot()
+}
+
+func (c *maintenanceCommand) getMaintenanceMachineAPI() (MaintenanceMachineAPI, error) {
+	root, err := c.getAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	if root.BestFacadeVersion("MachineManager") < 7 {
+		return nil, errors.New("this version of Juju doesn't support maintenance-machine")
+	}
+	if c.machineAPI != nil {
+		return c.machineAPI, nil
+	}
+	return &maintenanceMachineAPI{
+		root:              root,
+		machineClient:     machinemanager.NewClient(root),
+		applicationClient: application.NewClient(root),
+		actionClient:      action.NewClient(root),
+	}, nil
+}
+
+// Run implements Command.Run.
+func (c *maintenanceCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getMaintenanceMachineAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.Done {
+		_, err := client.SetMachineMaintenance(c.MachineId, "", nil)
+		if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+			return err
+		}
+		ctx.Infof("machine %s is no longer in maintenance", c.MachineId)
+		return nil
+	}
+
+	setProgress := func(message string) error {
+		_, err := client.SetMachineMaintenance(c.MachineId, message, nil)
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	replaced, err := client.SetMachineMaintenance(c.MachineId, "preparing for maintenance", nil)
+	if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+		return err
+	}
+	ctx.Infof("machine %s is in maintenance; no new units will be assigned to it", c.MachineId)
+
+	fullStatus, err := client.Status(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hosted := hostedUnits(fullStatus, c.MachineId)
+	deadline := c.getClock().After(c.Timeout)
+
+	if c.Evacuate && len(hosted) > 0 {
+		if err := setProgress("waiting for replacement units"); err != nil {
+			return err
+		}
+		if err := c.evacuate(ctx, client, fullStatus, hosted, replaced, deadline); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if err := setProgress(fmt.Sprintf("running %s actions", PreMaintenanceAction)); err != nil {
+		return err
+	}
+	if err := c.runPreMaintenance(ctx, client, hosted, deadline); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := setProgress("ready for maintenance"); err != nil {
+		return err
+	}
+	ctx.Infof("machine %s is ready for maintenance", c.MachineId)
+	return nil
+}
+
+func (c *maintenanceCommand) getClock() clock.Clock {
+	if c.clock != nil {
+		return c.clock
+	}
+	return clock.WallClock
+}
+
+// evacuate adds a replacement unit for each of the hosted units, and
+// waits for their workloads to become active. Hosted units already
+// replaced, according to the replacements recorded for the machine,
+// are not replaced again unless the replacement no longer exists.
+func (c *maintenanceCommand) evacuate(
+	ctx *cmd.Context,
+	client MaintenanceMachineAPI,
+	fullStatus *params.FullStatus,
+	hosted map[string][]string,
+	replaced map[string]string,
+	deadline <-chan time.Time,
+) error {
+	replacements := make(map[string][]string)
+	for _, appName := range sortedKeys(hosted) {
+		appUnits := fullStatus.Applications[appName].Units
+		var unreplaced []string
+		for _, unitName := range hosted[appName] {
+			replacement, ok := replaced[unitName]
+			if _, exists := appUnits[replacement]; ok && exists {
+				ctx.Infof("%s is already replaced by %s", unitName, replacement)
+				replacements[appName] = append(replacements[appName], replacement)
+				continue
+			}
+			unreplaced = append(unreplaced, unitName)
+		}
+		if len(unreplaced) == 0 {
+			continue
+		}
+
+		units, err := client.AddUnits(application.AddUnitsParams{
+			ApplicationName: appName,
+			NumUnits:        len(unreplaced),
+		})
+		if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+			return errors.Annotatef(err, "adding replacement units of %q", appName)
+		}
+		if len(units) != len(unreplaced) {
+			return errors.Errorf("expected %d replacement unit(s) of %q, got %d", len(unreplaced), appName, len(units))
+		}
+		// Record the replacements straight away, so that they are not
+		// added again if the command is interrupted and run again.
+		record := make(map[string]string)
+		for i, unitName := range unreplaced {
+			record[unitName] = units[i]
+		}
+		_, err = client.SetMachineMaintenance(c.MachineId, "waiting for replacement units", record)
+		if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+			return errors.Annotatef(err, "recording replacement units of %q", appName)
+		}
+		ctx.Infof("added %s to replace %s", strings.Join(units, ", "), strings.Join(unreplaced, ", "))
+		replacements[appName] = append(replacements[appName], units...)
+	}
+
+	return c.waitFor(deadline, "replacement units to become active", func() (bool, error) {
+		fullStatus, err := client.Status(nil)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		allActive := true
+		for _, appName := range sortedKeys(replacements) {
+			app := fullStatus.Applications[appName]
+			for _, unitName := range replacements[appName] {
+				workload := app.Units[unitName].WorkloadStatus
+				switch workload.Status {
+				case status.Active.String():
+				case status.Error.String():
+					return false, errors.Errorf("replacement unit %s failed: %s", unitName, workload.Info)
+				default:
+					allActive = false
+				}
+			}
+		}
+		return allActive, nil
+	})
+}
+
+// runPreMaintenance runs the pre-maintenance action on each of the
+// hosted units whose charm defines it, and waits for them to complete.
+func (c *maintenanceCommand) runPreMaintenance(
+	ctx *cmd.Context,
+	client MaintenanceMachineAPI,
+	hosted map[string][]string,
+	deadline <-chan time.Time,
+) error {
+	var actions []params.Action
+	for _, appName := range sortedKeys(hosted) {
+		specs, err := client.ApplicationCharmActions(params.Entity{
+			Tag: names.NewApplicationTag(appName).String(),
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := specs[PreMaintenanceAction]; !ok {
+			continue
+		}
+		for _, unitName := range hosted[appName] {
+			actions = append(actions, params.Action{
+				Receiver: names.NewUnitTag(unitName).String(),
+				Name:     PreMaintenanceAction,
+			})
+		}
+	}
+	if len(actions) == 0 {
+		ctx.Infof("no charm on machine %s defines a %q action", c.MachineId, PreMaintenanceAction)
+		return nil
+	}
+
+	results, err := client.Enqueue(params.Actions{Actions: actions})
+	if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+		return err
+	}
+	if len(results.Results) != len(actions) {
+		return errors.Errorf("expected %d result(s), got %d", len(actions), len(results.Results))
+	}
+	entities := params.Entities{Entities: make([]params.Entity, len(actions))}
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "queueing %s on %s", PreMaintenanceAction, actions[i].Receiver)
+		}
+		if result.Action == nil {
+			return errors.Errorf("action failed to enqueue on %q", actions[i].Receiver)
+		}
+		entities.Entities[i].Tag = result.Action.Tag
+		ctx.Infof("running %s on %s", PreMaintenanceAction, unitIdFromTag(actions[i].Receiver))
+	}
+
+	return c.waitFor(deadline, PreMaintenanceAction+" actions to complete", func() (bool, error) {
+		results, err := client.Actions(entities)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		allDone := true
+		for i, result := range results.Results {
+			if result.Error != nil {
+				return false, errors.Trace(result.Error)
+			}
+			switch result.Status {
+			case params.ActionCompleted:
+			case params.ActionPending, params.ActionRunning:
+				allDone = false
+			default:
+				return false, errors.Errorf(
+					"%s on %s %s: %s", PreMaintenanceAction,
+					unitIdFromTag(actions[i].Receiver), result.Status, result.Message,
+				)
+			}
+		}
+		return allDone, nil
+	})
+}
+
+// waitFor calls check until it reports true or fails, pausing between
+// calls, and gives up if the deadline passes first.
+func (c *maintenanceCommand) waitFor(deadline <-chan time.Time, what string, check func() (bool, error)) error {
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-deadline:
+			return errors.Errorf("timed out waiting for %s", what)
+		case <-c.getClock().After(maintenancePollInterval):
+		}
+	}
+}
+
+// hostedUnits returns the names of the principal units on the specified
+// machine and any containers within it, keyed by application name.
+func hostedUnits(fullStatus *params.FullStatus, machineId string) map[string][]string {
+	result := make(map[string][]string)
+	for appName, app := range fullStatus.Applications {
+		for unitName, unit := range app.Units {
+			if unit.Machine == machineId || strings.HasPrefix(unit.Machine, machineId+"/") {
+				result[appName] = append(result[appName], unitName)
+			}
+		}
+	}
+	for _, units := range result {
+		sort.Strings(units)
+	}
+	return result
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unitIdFromTag(tag string) string {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return tag
+	}
+	return unitTag.Id()
+}
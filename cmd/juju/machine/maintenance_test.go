@@ -0,0 +1,362 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type MaintenanceMachineSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake          *fakeMaintenanceMachineAPI
+	apiConnection *mockAPIConnection
+	clock         *jujutesting.Clock
+}
+
+var _ = gc.Suite(&MaintenanceMachineSuite{})
+
+func (s *MaintenanceMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = newFakeMaintenanceMachineAPI()
+	s.apiConnection = &mockAPIConnection{
+		bestFacadeVersion: 7,
+	}
+	s.clock = jujutesting.NewClock(time.Now())
+}
+
+func (s *MaintenanceMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	maintenance, _ := machine.NewMaintenanceCommandForTest(s.apiConnection, s.fake, s.clock)
+	return cmdtesting.RunCommand(c, maintenance, args...)
+}
+
+func (s *MaintenanceMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machine     string
+		evacuate    bool
+		done        bool
+		errorString string
+	}{
+		{
+			errorString: "no machine specified",
+		}, {
+			args:    []string{"1"},
+			machine: "1",
+		}, {
+			args:     []string{"1", "--evacuate"},
+			machine:  "1",
+			evacuate: true,
+		}, {
+			args:    []string{"1/lxd/0", "--done"},
+			machine: "1/lxd/0",
+			done:    true,
+		}, {
+			args:        []string{"1", "--done", "--evacuate"},
+			errorString: "--done and --evacuate cannot be used together",
+		}, {
+			args:        []string{"1", "--timeout", "0s"},
+			errorString: "timeout must be positive",
+		}, {
+			args:        []string{"lxd"},
+			errorString: `invalid machine id "lxd"`,
+		}, {
+			args:        []string{"1", "2"},
+			errorString: `unrecognized args: \["2"\]`,
+		},
+	} {
+		c.Logf("test %d", i)
+		wrappedCommand, maintenanceCmd := machine.NewMaintenanceCommandForTest(s.apiConnection, s.fake, s.clock)
+		err := cmdtesting.InitCommand(wrappedCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(maintenanceCmd.MachineId, gc.Equals, test.machine)
+			c.Check(maintenanceCmd.Evacuate, gc.Equals, test.evacuate)
+			c.Check(maintenanceCmd.Done, gc.Equals, test.done)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *MaintenanceMachineSuite) TestMaintenance(c *gc.C) {
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.messages, jc.DeepEquals, []string{
+		"preparing for maintenance",
+		"running pre-maintenance actions",
+		"ready for maintenance",
+	})
+	c.Assert(s.fake.added, gc.HasLen, 0)
+	c.Assert(s.fake.enqueued, jc.DeepEquals, []params.Action{{
+		Receiver: "unit-mysql-0",
+		Name:     "pre-maintenance",
+	}})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+machine 1 is in maintenance; no new units will be assigned to it
+running pre-maintenance on mysql/0
+machine 1 is ready for maintenance
+`[1:])
+}
+
+func (s *MaintenanceMachineSuite) TestMaintenanceEvacuate(c *gc.C) {
+	ctx, err := s.run(c, "1", "--evacuate")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.messages, jc.DeepEquals, []string{
+		"preparing for maintenance",
+		"waiting for replacement units",
+		"waiting for replacement units",
+		"waiting for replacement units",
+		"running pre-maintenance actions",
+		"ready for maintenance",
+	})
+	c.Assert(s.fake.added, jc.DeepEquals, []application.AddUnitsParams{
+		{ApplicationName: "mysql", NumUnits: 1},
+		{ApplicationName: "wordpress", NumUnits: 1},
+	})
+	c.Assert(s.fake.replacements, jc.DeepEquals, map[string]string{
+		"mysql/0":     "mysql/2",
+		"wordpress/0": "wordpress/1",
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+machine 1 is in maintenance; no new units will be assigned to it
+added mysql/2 to replace mysql/0
+added wordpress/1 to replace wordpress/0
+running pre-maintenance on mysql/0
+machine 1 is ready for maintenance
+`[1:])
+}
+
+func (s *MaintenanceMachineSuite) TestMaintenanceEvacuateAgain(c *gc.C) {
+	// An earlier run replaced mysql/0 with mysql/2, and wordpress/0
+	// with wordpress/5, which has since been removed.
+	s.fake.replacements = map[string]string{
+		"mysql/0":     "mysql/2",
+		"wordpress/0": "wordpress/5",
+	}
+	s.fake.applications["mysql"].Units["mysql/2"] = params.UnitStatus{
+		Machine:        "3",
+		WorkloadStatus: params.DetailedStatus{Status: "active"},
+	}
+	ctx, err := s.run(c, "1", "--evacuate")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.added, jc.DeepEquals, []application.AddUnitsParams{
+		{ApplicationName: "wordpress", NumUnits: 1},
+	})
+	c.Assert(s.fake.replacements, jc.DeepEquals, map[string]string{
+		"mysql/0":     "mysql/2",
+		"wordpress/0": "wordpress/1",
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+machine 1 is in maintenance; no new units will be assigned to it
+mysql/0 is already replaced by mysql/2
+added wordpress/1 to replace wordpress/0
+running pre-maintenance on mysql/0
+machine 1 is ready for maintenance
+`[1:])
+}
+
+func (s *MaintenanceMachineSuite) TestMaintenanceEvacuateTimeout(c *gc.C) {
+	s.fake.replacementStatus = "waiting"
+	errc := make(chan error, 1)
+	go func() {
+		_, err := s.run(c, "1", "--evacuate", "--timeout", "10m")
+		errc <- err
+	}()
+	err := s.clock.WaitAdvance(10*time.Minute, testing.LongWait, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-errc:
+		c.Assert(err, gc.ErrorMatches, "timed out waiting for replacement units to become active")
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for command")
+	}
+	// The machine remains in maintenance, and no actions were run.
+	c.Assert(s.fake.messages, jc.DeepEquals, []string{
+		"preparing for maintenance",
+		"waiting for replacement units",
+		"waiting for replacement units",
+		"waiting for replacement units",
+	})
+	c.Assert(s.fake.enqueued, gc.HasLen, 0)
+}
+
+func (s *MaintenanceMachineSuite) TestMaintenanceEvacuateReplacementFailed(c *gc.C) {
+	s.fake.replacementStatus = "error"
+	_, err := s.run(c, "1", "--evacuate")
+	c.Assert(err, gc.ErrorMatches, `replacement unit mysql/2 failed: hook failed: "install"`)
+	c.Assert(s.fake.enqueued, gc.HasLen, 0)
+}
+
+func (s *MaintenanceMachineSuite) TestMaintenanceActionFailed(c *gc.C) {
+	s.fake.actionStatus = params.ActionFailed
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, "pre-maintenance on mysql/0 failed: oops")
+	c.Assert(s.fake.messages, jc.DeepEquals, []string{
+		"preparing for maintenance",
+		"running pre-maintenance actions",
+	})
+}
+
+func (s *MaintenanceMachineSuite) TestMaintenanceNoAction(c *gc.C) {
+	delete(s.fake.charmActions, "mysql")
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.enqueued, gc.HasLen, 0)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+machine 1 is in maintenance; no new units will be assigned to it
+no charm on machine 1 defines a "pre-maintenance" action
+machine 1 is ready for maintenance
+`[1:])
+}
+
+func (s *MaintenanceMachineSuite) TestDone(c *gc.C) {
+	ctx, err := s.run(c, "1", "--done")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.messages, jc.DeepEquals, []string{""})
+	c.Assert(s.fake.replacements, gc.HasLen, 0)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "machine 1 is no longer in maintenance\n")
+}
+
+func (s *MaintenanceMachineSuite) TestBlockedError(c *gc.C) {
+	s.fake.maintenanceError = common.OperationBlockedError("TestBlockedError")
+	_, err := s.run(c, "1")
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockedError.*")
+}
+
+func (s *MaintenanceMachineSuite) TestOldFacade(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 6
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support maintenance-machine")
+	c.Assert(s.fake.messages, gc.HasLen, 0)
+}
+
+type fakeMaintenanceMachineAPI struct {
+	messages          []string
+	replacements      map[string]string
+	maintenanceError  error
+	added             []application.AddUnitsParams
+	enqueued          []params.Action
+	applications      map[string]params.ApplicationStatus
+	charmActions      map[string]map[string]params.ActionSpec
+	replacementStatus string
+	actionStatus      string
+}
+
+// newFakeMaintenanceMachineAPI returns a fake API for a model in which
+// machine 1 hosts mysql/0, and its container 1/lxd/0 hosts wordpress/0.
+// Only the mysql charm defines a pre-maintenance action.
+func newFakeMaintenanceMachineAPI() *fakeMaintenanceMachineAPI {
+	return &fakeMaintenanceMachineAPI{
+		applications: map[string]params.ApplicationStatus{
+			"mysql": {Units: map[string]params.UnitStatus{
+				"mysql/0": {Machine: "1"},
+				"mysql/1": {Machine: "2"},
+			}},
+			"wordpress": {Units: map[string]params.UnitStatus{
+				"wordpress/0": {Machine: "1/lxd/0"},
+			}},
+			"varnish": {Units: map[string]params.UnitStatus{
+				"varnish/0": {Machine: "10"},
+			}},
+		},
+		charmActions: map[string]map[string]params.ActionSpec{
+			"mysql": {"pre-maintenance": {}, "backup": {}},
+		},
+		replacementStatus: "active",
+		actionStatus:      params.ActionCompleted,
+	}
+}
+
+func (f *fakeMaintenanceMachineAPI) Close() error {
+	return nil
+}
+
+func (f *fakeMaintenanceMachineAPI) SetMachineMaintenance(machine, message string, replacements map[string]string) (map[string]string, error) {
+	if f.maintenanceError != nil {
+		return nil, f.maintenanceError
+	}
+	f.messages = append(f.messages, message)
+	if message == "" {
+		f.replacements = nil
+		return nil, nil
+	}
+	if f.replacements == nil {
+		f.replacements = make(map[string]string)
+	}
+	for replaced, replacement := range replacements {
+		f.replacements[replaced] = replacement
+	}
+	result := make(map[string]string)
+	for replaced, replacement := range f.replacements {
+		result[replaced] = replacement
+	}
+	return result, nil
+}
+
+func (f *fakeMaintenanceMachineAPI) Status(patterns []string) (*params.FullStatus, error) {
+	return &params.FullStatus{Applications: f.applications}, nil
+}
+
+func (f *fakeMaintenanceMachineAPI) AddUnits(args application.AddUnitsParams) ([]string, error) {
+	f.added = append(f.added, args)
+	app := f.applications[args.ApplicationName]
+	var added []string
+	for i := 0; i < args.NumUnits; i++ {
+		name := fmt.Sprintf("%s/%d", args.ApplicationName, len(app.Units))
+		workload := params.DetailedStatus{Status: f.replacementStatus}
+		if f.replacementStatus == "error" {
+			workload.Info = `hook failed: "install"`
+		}
+		app.Units[name] = params.UnitStatus{Machine: "3", WorkloadStatus: workload}
+		added = append(added, name)
+	}
+	return added, nil
+}
+
+func (f *fakeMaintenanceMachineAPI) ApplicationCharmActions(arg params.Entity) (map[string]params.ActionSpec, error) {
+	tag, err := names.ParseApplicationTag(arg.Tag)
+	if err != nil {
+		return nil, err
+	}
+	return f.charmActions[tag.Id()], nil
+}
+
+func (f *fakeMaintenanceMachineAPI) Enqueue(args params.Actions) (params.ActionResults, error) {
+	f.enqueued = append(f.enqueued, args.Actions...)
+	results := params.ActionResults{Results: make([]params.ActionResult, len(args.Actions))}
+	for i, a := range args.Actions {
+		results.Results[i].Action = &params.Action{
+			Tag:      names.NewActionTag(fmt.Sprintf("f47ac10b-58cc-4372-a567-0e02b2c3d48%d", i)).String(),
+			Receiver: a.Receiver,
+			Name:     a.Name,
+		}
+		results.Results[i].Status = params.ActionPending
+	}
+	return results, nil
+}
+
+func (f *fakeMaintenanceMachineAPI) Actions(args params.Entities) (params.ActionResults, error) {
+	results := params.ActionResults{Results: make([]params.ActionResult, len(args.Entities))}
+	for i := range args.Entities {
+		results.Results[i].Status = f.actionStatus
+		if f.actionStatus == params.ActionFailed {
+			results.Results[i].Message = "oops"
+		}
+	}
+	return results, nil
+}
@@ -56,6 +56,7 @@ the corresponding cloud instance:
 
 See also:
     add-machine
+    replace-machine
 `
 
 // Info implements Command.Info.
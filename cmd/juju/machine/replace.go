@@ -0,0 +1,140 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewReplaceCommand returns a command used to provision a new cloud
+// instance for an existing machine.
+func NewReplaceCommand() cmd.Command {
+	return modelcmd.Wrap(&replaceCommand{})
+}
+
+// replaceCommand causes the cloud instance of an existing machine to
+// be replaced with a freshly provisioned one.
+type replaceCommand struct {
+	baseMachinesCommand
+	apiRoot    api.Connection
+	machineAPI ReplaceMachineAPI
+	MachineIds []string
+}
+
+const replaceMachineDoc = `
+Machines are specified by their numbers, which may be retrieved from the
+output of ` + "`juju status`." + `
+A new cloud instance is provisioned for each machine, keeping the machine
+number, its units and their storage. The units are deployed afresh on the
+new instance, and so run their install, config-changed and relation hooks
+again. Detachable volumes are detached from the old instance, and the
+new instance is not provisioned until they have been; they are then
+reattached to the new instance. Volumes and filesystems bound to the old
+instance are created again, empty. While this happens, the machine's
+instance status is "replacing". If the old instance still exists, it is
+stopped unless the model's provisioner-harvest-mode prevents it.
+
+Controller machines, containers, machines hosting containers and manually
+provisioned machines cannot be replaced.
+
+Examples:
+
+Replace the instance of machine 5, which has died:
+
+    juju replace-machine 5
+
+See also:
+    add-machine
+    remove-machine
+    retry-provisioning
+`
+
+// Info implements Command.Info.
+func (c *replaceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "replace-machine",
+		Args:    "<machine number> ...",
+		Purpose: "Provisions a new cloud instance for one or more machines.",
+		Doc:     replaceMachineDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *replaceCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+// ReplaceMachineAPI defines the API methods used by the replace-machine
+// command.
+type ReplaceMachineAPI interface {
+	ReplaceMachines(machines ...string) ([]params.ErrorResult, error)
+	Close() error
+}
+
+func (c *replaceCommand) getAPIRoot() (api.Connection, error) {
+	if c.apiRoot != nil {
+		return c.apiRoot, nil
+	}
+	return c.NewAPIRoot()
+}
+
+func (c *replaceCommand) getReplaceMachineAPI() (ReplaceMachineAPI, error) {
+	root, err := c.getAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	if root.BestFacadeVersion("MachineManager") < 6 {
+		return nil, errors.New("this version of Juju doesn't support replace-machine")
+	}
+	if c.machineAPI != nil {
+		return c.machineAPI, nil
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *replaceCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getReplaceMachineAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ReplaceMachines(c.MachineIds...)
+	if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+		return err
+	}
+
+	anyFailed := false
+	for i, id := range c.MachineIds {
+		result := results[i]
+		if result.Error != nil {
+			anyFailed = true
+			ctx.Infof("replacing machine %s failed: %s", id, result.Error)
+			continue
+		}
+		ctx.Infof("replacing machine %s", id)
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type ReplaceMachineSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake          *fakeReplaceMachineAPI
+	apiConnection *mockAPIConnection
+}
+
+var _ = gc.Suite(&ReplaceMachineSuite{})
+
+func (s *ReplaceMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeReplaceMachineAPI{}
+	s.apiConnection = &mockAPIConnection{
+		bestFacadeVersion: 6,
+	}
+}
+
+func (s *ReplaceMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	replace, _ := machine.NewReplaceCommandForTest(s.apiConnection, s.fake)
+	return cmdtesting.RunCommand(c, replace, args...)
+}
+
+func (s *ReplaceMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machines    []string
+		errorString string
+	}{
+		{
+			errorString: "no machines specified",
+		}, {
+			args:     []string{"1"},
+			machines: []string{"1"},
+		}, {
+			args:     []string{"1", "2"},
+			machines: []string{"1", "2"},
+		}, {
+			args:        []string{"lxd"},
+			errorString: `invalid machine id "lxd"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		wrappedCommand, replaceCmd := machine.NewReplaceCommandForTest(s.apiConnection, s.fake)
+		err := cmdtesting.InitCommand(wrappedCommand, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(replaceCmd.MachineIds, jc.DeepEquals, test.machines)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *ReplaceMachineSuite) TestReplace(c *gc.C) {
+	ctx, err := s.run(c, "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+replacing machine 1
+replacing machine 2
+`[1:])
+}
+
+func (s *ReplaceMachineSuite) TestReplaceOutput(c *gc.C) {
+	s.fake.results = []params.ErrorResult{{
+		Error: &params.Error{Message: "oy vey"},
+	}, {}}
+	ctx, err := s.run(c, "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+replacing machine 1 failed: oy vey
+replacing machine 2
+`[1:])
+}
+
+func (s *ReplaceMachineSuite) TestBlockedError(c *gc.C) {
+	s.fake.replaceError = common.OperationBlockedError("TestBlockedError")
+	_, err := s.run(c, "1")
+	testing.AssertOperationWasBlocked(c, err, ".*TestBlockedError.*")
+}
+
+func (s *ReplaceMachineSuite) TestOldFacade(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 5
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support replace-machine")
+	c.Assert(s.fake.machines, gc.HasLen, 0)
+}
+
+type fakeReplaceMachineAPI struct {
+	machines     []string
+	replaceError error
+	results      []params.ErrorResult
+}
+
+func (f *fakeReplaceMachineAPI) Close() error {
+	return nil
+}
+
+func (f *fakeReplaceMachineAPI) ReplaceMachines(machines ...string) ([]params.ErrorResult, error) {
+	f.machines = machines
+	if f.replaceError != nil || f.results != nil {
+		return f.results, f.replaceError
+	}
+	return make([]params.ErrorResult, len(machines)), nil
+}
@@ -0,0 +1,493 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/errors"
+	"github.com/juju/naturalsort"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/machinemanager"
+	storageapi "github.com/juju/juju/api/storage"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/pricing"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+)
+
+// defaultPricingFile is the name of the price list used when none is
+// specified, relative to the Juju data directory.
+const defaultPricingFile = "pricing.json"
+
+// defaultVolumeSize is the size, in MiB, assumed for bundle storage
+// that does not specify one. It matches the size Juju uses when
+// neither the charm nor the user specify one.
+const defaultVolumeSize = 1024
+
+// CostAPI defines the model API methods used to estimate the cost
+// of running a model or bundle.
+type CostAPI interface {
+	Close() error
+	InstanceTypes([]constraints.Value) ([]params.InstanceTypesResult, error)
+	ListVolumes(machines []string) ([]params.VolumeDetailsListResult, error)
+	ListPools(providers, names []string) ([]params.StoragePool, error)
+}
+
+type costAPI struct {
+	*machinemanager.Client
+	storage *storageapi.Client
+}
+
+func newCostAPI(root api.Connection) CostAPI {
+	return costAPI{
+		Client:  machinemanager.NewClient(root),
+		storage: storageapi.NewClient(root),
+	}
+}
+
+// ListVolumes is part of the CostAPI interface.
+func (a costAPI) ListVolumes(machines []string) ([]params.VolumeDetailsListResult, error) {
+	return a.storage.ListVolumes(machines)
+}
+
+// ListPools is part of the CostAPI interface.
+func (a costAPI) ListPools(providers, names []string) ([]params.StoragePool, error) {
+	return a.storage.ListPools(providers, names)
+}
+
+// readPriceList reads the price list at the specified path, or the
+// default price list if the path is empty.
+func readPriceList(path string) (*pricing.Catalog, error) {
+	if path == "" {
+		path = osenv.JujuXDGDataHomePath(defaultPricingFile)
+	}
+	catalog, err := pricing.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read price list")
+	}
+	return catalog, nil
+}
+
+// costEstimate holds the estimated monthly cost of running
+// a model or bundle.
+type costEstimate struct {
+	Currency string                 `yaml:"currency" json:"currency"`
+	Machines map[string]machineCost `yaml:"machines,omitempty" json:"machines,omitempty"`
+	Volumes  map[string]volumeCost  `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	Monthly  float64                `yaml:"monthly" json:"monthly"`
+
+	// Unpriced holds the names of the machines and volumes
+	// whose cost could not be estimated.
+	Unpriced []string `yaml:"unpriced,omitempty" json:"unpriced,omitempty"`
+}
+
+// machineCost holds the estimated monthly cost of running a machine.
+type machineCost struct {
+	InstanceType string  `yaml:"instance-type,omitempty" json:"instance-type,omitempty"`
+	Monthly      float64 `yaml:"monthly" json:"monthly"`
+	Message      string  `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// volumeCost holds the estimated monthly cost of a volume.
+type volumeCost struct {
+	Pool    string  `yaml:"pool,omitempty" json:"pool,omitempty"`
+	Size    uint64  `yaml:"size" json:"size"`
+	Monthly float64 `yaml:"monthly" json:"monthly"`
+	Message string  `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// machineRequest describes a machine to be priced.
+type machineRequest struct {
+	name        string
+	constraints constraints.Value
+
+	// message, if set, explains why the machine cannot be priced.
+	message string
+}
+
+// volumeRequest describes a volume to be priced.
+type volumeRequest struct {
+	name string
+	pool string
+	size uint64
+}
+
+// costEstimator estimates the cost of running machines and volumes
+// in a cloud region.
+type costEstimator struct {
+	api      CostAPI
+	currency string
+	prices   pricing.RegionPrices
+}
+
+func newCostEstimator(api CostAPI, catalog *pricing.Catalog, cloud, region string) (*costEstimator, error) {
+	prices, err := catalog.Region(cloud, region)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &costEstimator{
+		api:      api,
+		currency: catalog.Currency,
+		prices:   prices,
+	}, nil
+}
+
+// estimate returns the estimated monthly cost of running the
+// given machines and volumes.
+func (e *costEstimator) estimate(machines []machineRequest, volumes []volumeRequest) (*costEstimate, error) {
+	result := &costEstimate{Currency: e.currency}
+	machineCosts, err := e.machineCosts(machines)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeCosts, err := e.volumeCosts(volumes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var total float64
+	if len(machineCosts) > 0 {
+		result.Machines = make(map[string]machineCost)
+	}
+	for i, cost := range machineCosts {
+		name := machines[i].name
+		if cost.Message != "" {
+			result.Unpriced = append(result.Unpriced, "machine "+name)
+		}
+		total += cost.Monthly
+		cost.Monthly = roundCents(cost.Monthly)
+		result.Machines[name] = cost
+	}
+	if len(volumeCosts) > 0 {
+		result.Volumes = make(map[string]volumeCost)
+	}
+	for i, cost := range volumeCosts {
+		name := volumes[i].name
+		if cost.Message != "" {
+			result.Unpriced = append(result.Unpriced, "volume "+name)
+		}
+		total += cost.Monthly
+		cost.Monthly = roundCents(cost.Monthly)
+		result.Volumes[name] = cost
+	}
+	result.Monthly = roundCents(total)
+	naturalsort.Sort(result.Unpriced)
+	return result, nil
+}
+
+func (e *costEstimator) machineCosts(machines []machineRequest) ([]machineCost, error) {
+	costs := make([]machineCost, len(machines))
+
+	// Machines constrained to a specific instance type can be
+	// priced directly; the provider is asked which instance types
+	// match the constraints of the others.
+	var query []constraints.Value
+	var queried []int
+	for i, m := range machines {
+		switch {
+		case m.message != "":
+			costs[i].Message = m.message
+		case m.constraints.HasInstanceType():
+			costs[i] = e.instanceTypeCost(*m.constraints.InstanceType)
+		default:
+			query = append(query, m.constraints)
+			queried = append(queried, i)
+		}
+	}
+	if len(query) == 0 {
+		return costs, nil
+	}
+	results, err := e.api.InstanceTypes(query)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting instance types")
+	}
+	for i, result := range results {
+		costs[queried[i]] = e.cheapestInstanceTypeCost(result)
+	}
+	return costs, nil
+}
+
+func (e *costEstimator) instanceTypeCost(instanceType string) machineCost {
+	cost := machineCost{InstanceType: instanceType}
+	monthly, err := e.prices.InstanceTypeMonthly(instanceType)
+	if err != nil {
+		cost.Message = err.Error()
+	}
+	cost.Monthly = monthly
+	return cost
+}
+
+// cheapestInstanceTypeCost returns the cost of the cheapest of the
+// matching instance types. This is the instance type the provider
+// would most likely choose for the machine.
+func (e *costEstimator) cheapestInstanceTypeCost(result params.InstanceTypesResult) machineCost {
+	if result.Error != nil {
+		return machineCost{Message: result.Error.Error()}
+	}
+	var cheapest *machineCost
+	for _, it := range result.InstanceTypes {
+		monthly, err := e.prices.InstanceTypeMonthly(it.Name)
+		if err != nil {
+			continue
+		}
+		if cheapest == nil || monthly < cheapest.Monthly {
+			cheapest = &machineCost{InstanceType: it.Name, Monthly: monthly}
+		}
+	}
+	if cheapest == nil {
+		return machineCost{Message: "no priced instance type matches constraints"}
+	}
+	return *cheapest
+}
+
+func (e *costEstimator) volumeCosts(volumes []volumeRequest) ([]volumeCost, error) {
+	var poolTypes map[string]string
+	costs := make([]volumeCost, len(volumes))
+	for i, v := range volumes {
+		costs[i] = volumeCost{Pool: v.pool, Size: v.size}
+		pool := v.pool
+		if pool == "" {
+			pool = pricing.DefaultStorage
+		}
+		if isLocalStorage(pool) {
+			continue
+		}
+		monthly, err := e.prices.StorageMonthly(v.size, pool)
+		if errors.IsNotFound(err) && v.pool != "" {
+			// The pool may be a user-defined one, in which case
+			// it is priced by its storage provider type.
+			if poolTypes == nil {
+				if poolTypes, err = e.storagePoolTypes(); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+			providerType := poolTypes[pool]
+			if isLocalStorage(providerType) {
+				continue
+			}
+			monthly, err = e.prices.StorageMonthly(v.size, pool, providerType)
+		}
+		if err != nil {
+			costs[i].Message = err.Error()
+		}
+		costs[i].Monthly = monthly
+	}
+	return costs, nil
+}
+
+func (e *costEstimator) storagePoolTypes() (map[string]string, error) {
+	pools, err := e.api.ListPools(nil, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting storage pools")
+	}
+	poolTypes := make(map[string]string, len(pools))
+	for _, pool := range pools {
+		poolTypes[pool.Name] = pool.Provider
+	}
+	return poolTypes, nil
+}
+
+// isLocalStorage reports whether storage of the given type is
+// carved out of a machine's own disks or memory, and so has no
+// cost of its own.
+func isLocalStorage(providerType string) bool {
+	switch storage.ProviderType(providerType) {
+	case provider.LoopProviderType, provider.RootfsProviderType, provider.TmpfsProviderType:
+		return true
+	}
+	return false
+}
+
+// roundCents rounds the given price to two decimal places.
+func roundCents(price float64) float64 {
+	return math.Floor(price*100+0.5) / 100
+}
+
+// modelCostRequests returns the machines and volumes in a model
+// that should be priced.
+func modelCostRequests(
+	machines []params.ModelMachineInfo,
+	volumes []params.VolumeDetailsListResult,
+) ([]machineRequest, []volumeRequest, error) {
+	var machineRequests []machineRequest
+	for _, m := range machines {
+		if names.IsContainerMachine(m.Id) {
+			// Containers run on their host machines,
+			// and so cost nothing extra.
+			continue
+		}
+		// The API does not report a machine's instance type, so
+		// the machine is priced as the cheapest instance type that
+		// fits its hardware, which may not be the one it runs on.
+		request := machineRequest{name: m.Id}
+		switch {
+		case strings.HasPrefix(m.InstanceId, "manual:"):
+			request.message = "manually provisioned"
+		case m.Hardware == nil:
+			request.message = "not provisioned"
+		default:
+			request.constraints = constraints.Value{
+				Arch:     m.Hardware.Arch,
+				CpuCores: m.Hardware.Cores,
+				Mem:      m.Hardware.Mem,
+			}
+		}
+		machineRequests = append(machineRequests, request)
+	}
+
+	var volumeRequests []volumeRequest
+	for _, result := range volumes {
+		if result.Error != nil {
+			return nil, nil, errors.Annotate(result.Error, "getting volumes")
+		}
+		for _, v := range result.Result {
+			if v.Info.VolumeId == "" {
+				// The volume has not been provisioned yet.
+				continue
+			}
+			tag, err := names.ParseVolumeTag(v.VolumeTag)
+			if err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			volumeRequests = append(volumeRequests, volumeRequest{
+				name: tag.Id(),
+				pool: v.Info.Pool,
+				size: v.Info.Size,
+			})
+		}
+	}
+	return machineRequests, volumeRequests, nil
+}
+
+// bundleCostRequests returns the machines and volumes that deploying
+// the given bundle would create.
+func bundleCostRequests(data *charm.BundleData) ([]machineRequest, []volumeRequest, error) {
+	var machineRequests []machineRequest
+	for id, m := range data.Machines {
+		var cons constraints.Value
+		if m != nil {
+			var err error
+			if cons, err = constraints.Parse(m.Constraints); err != nil {
+				return nil, nil, errors.Annotatef(err, "machine %q", id)
+			}
+		}
+		machineRequests = append(machineRequests, machineRequest{
+			name:        id,
+			constraints: cons,
+		})
+	}
+
+	var volumeRequests []volumeRequest
+	for name, app := range data.Applications {
+		cons, err := constraints.Parse(app.Constraints)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "application %q", name)
+		}
+		for i := 0; i < app.NumUnits; i++ {
+			unit := fmt.Sprintf("%s/%d", name, i)
+			// Units placed on machines declared in the bundle,
+			// in containers on existing machines, or alongside
+			// other units do not need machines of their own.
+			// As when deploying, the last placement applies to
+			// any remaining units.
+			placement := "new"
+			if n := len(app.To); n > 0 {
+				placement = app.To[n-1]
+				if i < n {
+					placement = app.To[i]
+				}
+			}
+			if placesOnNewMachine(placement) {
+				machineRequests = append(machineRequests, machineRequest{
+					name:        unit,
+					constraints: cons,
+				})
+			}
+		}
+		for storageName, directive := range app.Storage {
+			storageCons, err := storage.ParseConstraints(directive)
+			if err != nil {
+				return nil, nil, errors.Annotatef(err, "application %q storage %q", name, storageName)
+			}
+			size := storageCons.Size
+			if size == 0 {
+				size = defaultVolumeSize
+			}
+			for i := 0; i < app.NumUnits; i++ {
+				volumeRequests = append(volumeRequests, volumeRequest{
+					name: fmt.Sprintf("%s/%d:%s", name, i, storageName),
+					pool: storageCons.Pool,
+					size: size * storageCons.Count,
+				})
+			}
+		}
+	}
+	return machineRequests, volumeRequests, nil
+}
+
+// placesOnNewMachine reports whether the given bundle placement
+// directive requires a new machine, either for the unit itself or
+// for a new container (e.g. "lxd:new") to host it.
+func placesOnNewMachine(placement string) bool {
+	if i := strings.Index(placement, ":"); i >= 0 {
+		placement = placement[i+1:]
+	}
+	return placement == "new"
+}
+
+// formatCostEstimateTabular writes a tabular summary of a cost estimate.
+func formatCostEstimateTabular(writer io.Writer, value interface{}) error {
+	estimate, ok := value.(*costEstimate)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", estimate, value)
+	}
+	tw := output.TabWriter(writer)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	price := func(monthly float64) string {
+		return fmt.Sprintf("%.2f", monthly)
+	}
+
+	if len(estimate.Machines) > 0 {
+		print("Machine", "Instance type", "Monthly", "Notes")
+		ids := make([]string, 0, len(estimate.Machines))
+		for id := range estimate.Machines {
+			ids = append(ids, id)
+		}
+		naturalsort.Sort(ids)
+		for _, id := range ids {
+			m := estimate.Machines[id]
+			print(id, m.InstanceType, price(m.Monthly), m.Message)
+		}
+		print()
+	}
+	if len(estimate.Volumes) > 0 {
+		print("Volume", "Pool", "Size", "Monthly", "Notes")
+		ids := make([]string, 0, len(estimate.Volumes))
+		for id := range estimate.Volumes {
+			ids = append(ids, id)
+		}
+		naturalsort.Sort(ids)
+		for _, id := range ids {
+			v := estimate.Volumes[id]
+			size := humanize.IBytes(v.Size * humanize.MiByte)
+			print(id, v.Pool, size, price(v.Monthly), v.Message)
+		}
+		print()
+	}
+	print(fmt.Sprintf("Total: %s %s/month", price(estimate.Monthly), estimate.Currency))
+	tw.Flush()
+	return nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/charmrepo.v3"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/modelmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const estimateCostDoc = `
+Estimates the monthly cost of running the current model or, if a
+local bundle is specified, of deploying that bundle to the current
+model's cloud region.
+
+Prices are read from an offline price list, by default the
+pricing.json file in the Juju data directory. Machines are priced by
+the cheapest instance type that satisfies their hardware or
+constraints, and volumes by their size and storage pool. Machines
+already running in the model are priced by their hardware, so the
+estimate may differ from their actual cost if the provider chose a
+more expensive instance type for them. Units placed in containers on
+new machines (e.g. "lxd:new") are priced as new machines. Machines and
+volumes with no price in the price list are reported as unpriced, and
+do not contribute to the total.
+
+The price list is a JSON document of the following form, with
+instance types priced per hour and storage per GiB per month:
+
+    {
+        "format": "pricing:1.0",
+        "currency": "USD",
+        "clouds": {
+            "aws": {
+                "us-east-1": {
+                    "instance-types": {"m3.medium": 0.067},
+                    "storage": {"ebs": 0.1, "default": 0.1}
+                }
+            }
+        }
+    }
+
+Examples:
+    juju estimate-cost
+    juju estimate-cost ./bundle.yaml
+    juju estimate-cost --pricing ./pricing.json --format yaml
+
+See also:
+    show-model
+`
+
+// NewEstimateCostCommand returns a command that estimates the
+// monthly cost of running a model or bundle.
+func NewEstimateCostCommand() cmd.Command {
+	return modelcmd.Wrap(&estimateCostCommand{})
+}
+
+// estimateCostCommand estimates the monthly cost of running
+// a model or bundle.
+type estimateCostCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+
+	bundle      string
+	pricingFile string
+
+	modelInfoAPI ShowModelAPI
+	costAPI      CostAPI
+}
+
+// Info implements Command.Info.
+func (c *estimateCostCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "estimate-cost",
+		Args:    "[<bundle>]",
+		Purpose: "Estimates the monthly cost of running a model or bundle.",
+		Doc:     estimateCostDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *estimateCostCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.pricingFile, "pricing", "", "Path to the price list to use")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCostEstimateTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *estimateCostCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.bundle = args[0]
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *estimateCostCommand) getModelInfoAPI() (ShowModelAPI, error) {
+	if c.modelInfoAPI != nil {
+		return c.modelInfoAPI, nil
+	}
+	root, err := c.NewControllerAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelmanager.NewClient(root), nil
+}
+
+func (c *estimateCostCommand) getCostAPI() (CostAPI, error) {
+	if c.costAPI != nil {
+		return c.costAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newCostAPI(root), nil
+}
+
+// Run implements Command.Run.
+func (c *estimateCostCommand) Run(ctx *cmd.Context) error {
+	// Read the inputs before connecting, so that
+	// mistakes in them are reported quickly.
+	catalog, err := readPriceList(c.pricingFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var bundleData *charm.BundleData
+	if c.bundle != "" {
+		if bundleData, err = readLocalBundle(c.bundle); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	_, modelDetails, err := c.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := c.modelInfo(names.NewModelTag(modelDetails.ModelUUID))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cloudTag, err := names.ParseCloudTag(info.CloudTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	api, err := c.getCostAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	estimator, err := newCostEstimator(api, catalog, cloudTag.Id(), info.CloudRegion)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var machines []machineRequest
+	var volumes []volumeRequest
+	if bundleData != nil {
+		machines, volumes, err = bundleCostRequests(bundleData)
+	} else {
+		machines, volumes, err = modelCosts(api, info)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	estimate, err := estimator.estimate(machines, volumes)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, estimate)
+}
+
+func (c *estimateCostCommand) modelInfo(modelTag names.ModelTag) (*params.ModelInfo, error) {
+	api, err := c.getModelInfoAPI()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer api.Close()
+	results, err := api.ModelInfo([]names.ModelTag{modelTag})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if results[0].Error != nil {
+		return nil, results[0].Error
+	}
+	return results[0].Result, nil
+}
+
+// modelCosts returns the machines and volumes in the model
+// described by info that should be priced.
+func modelCosts(api CostAPI, info *params.ModelInfo) ([]machineRequest, []volumeRequest, error) {
+	volumes, err := api.ListVolumes(nil)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "getting volumes")
+	}
+	return modelCostRequests(info.Machines, volumes)
+}
+
+// readLocalBundle reads the bundle in the specified file, directory
+// or archive.
+func readLocalBundle(path string) (*charm.BundleData, error) {
+	data, err := charmrepo.ReadBundleFile(path)
+	if err == nil {
+		return data, nil
+	}
+	bundle, _, pathErr := charmrepo.NewBundleAtPath(path)
+	if pathErr != nil {
+		return nil, errors.Annotatef(err, "cannot read bundle %q", path)
+	}
+	return bundle.Data(), nil
+}
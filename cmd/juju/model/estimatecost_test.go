@@ -0,0 +1,274 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd/cmdtesting"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/constraints"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+const testPriceList = `{
+    "format": "pricing:1.0",
+    "currency": "USD",
+    "clouds": {
+        "aws": {
+            "us-east-1": {
+                "instance-types": {"m3.medium": 0.067, "m3.large": 0.133},
+                "storage": {"ebs": 0.1, "ebs-ssd": 0.125}
+            }
+        }
+    }
+}`
+
+type EstimateCostSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake    fakeModelShowClient
+	costAPI *fakeCostAPI
+	store   *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&EstimateCostSuite{})
+
+func (s *EstimateCostSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+
+	arch := "amd64"
+	cores := uint64(1)
+	mem := uint64(3840)
+	s.fake = fakeModelShowClient{info: *createBasicModelInfo()}
+	s.fake.info.CloudTag = "cloud-aws"
+	s.fake.info.CloudRegion = "us-east-1"
+	s.fake.info.Machines = []params.ModelMachineInfo{{
+		Id:         "0",
+		InstanceId: "i-0",
+		Hardware:   &params.MachineHardware{Arch: &arch, Cores: &cores, Mem: &mem},
+	}, {
+		Id:         "0/lxd/0",
+		InstanceId: "juju-0-lxd-0",
+		Hardware:   &params.MachineHardware{Arch: &arch, Cores: &cores, Mem: &mem},
+	}, {
+		Id: "1",
+	}, {
+		Id:         "2",
+		InstanceId: "manual:10.0.0.2",
+		Hardware:   &params.MachineHardware{Arch: &arch, Cores: &cores, Mem: &mem},
+	}}
+
+	s.costAPI = &fakeCostAPI{
+		volumes: []params.VolumeDetails{{
+			VolumeTag: "volume-0",
+			Info:      params.VolumeInfo{VolumeId: "vol-0", Pool: "ebs", Size: 10240},
+		}, {
+			VolumeTag: "volume-1",
+			Info:      params.VolumeInfo{VolumeId: "vol-1", Pool: "fast", Size: 2048},
+		}, {
+			VolumeTag: "volume-2",
+			Info:      params.VolumeInfo{VolumeId: "loop0", Pool: "loop", Size: 1024},
+		}, {
+			VolumeTag: "volume-3",
+			Info:      params.VolumeInfo{Pool: "ebs", Size: 1024},
+		}},
+		pools: []params.StoragePool{{Name: "fast", Provider: "ebs-ssd"}},
+	}
+
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+
+	err = ioutil.WriteFile(osenv.JujuXDGDataHomePath("pricing.json"), []byte(testPriceList), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EstimateCostSuite) TestEstimateModel(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewEstimateCostCommandForTest(&s.fake, s.costAPI, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.YAMLEquals, attrs{
+		"currency": "USD",
+		"machines": attrs{
+			"0": attrs{"instance-type": "m3.medium", "monthly": 48.91},
+			"1": attrs{"monthly": 0, "message": "not provisioned"},
+			"2": attrs{"monthly": 0, "message": "manually provisioned"},
+		},
+		"volumes": attrs{
+			"0": attrs{"pool": "ebs", "size": 10240, "monthly": 1},
+			"1": attrs{"pool": "fast", "size": 2048, "monthly": 0.25},
+			"2": attrs{"pool": "loop", "size": 1024, "monthly": 0},
+		},
+		"monthly":  50.16,
+		"unpriced": []string{"machine 1", "machine 2"},
+	})
+	s.costAPI.CheckCallNames(c, "ListVolumes", "InstanceTypes", "ListPools", "Close")
+	s.costAPI.CheckCall(c, 1, "InstanceTypes", []constraints.Value{
+		constraints.MustParse("arch=amd64 cores=1 mem=3840M"),
+	})
+}
+
+func (s *EstimateCostSuite) TestEstimateBundle(c *gc.C) {
+	bundlePath := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(bundlePath, []byte(`
+applications:
+  mysql:
+    charm: cs:mysql
+    num_units: 2
+    constraints: mem=4G
+    storage:
+      data: ebs,10G
+  wordpress:
+    charm: cs:wordpress
+    num_units: 2
+    to: ["0", "new"]
+machines:
+  "0":
+    constraints: instance-type=m3.large
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, model.NewEstimateCostCommandForTest(&s.fake, s.costAPI, s.store), bundlePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Machine      Instance type  Monthly  Notes
+0            m3.large       97.09    
+mysql/0      m3.large       97.09    
+mysql/1      m3.large       97.09    
+wordpress/1  m3.medium      48.91    
+
+Volume        Pool  Size   Monthly  Notes
+mysql/0:data  ebs   10GiB  1.00     
+mysql/1:data  ebs   10GiB  1.00     
+
+Total: 342.18 USD/month
+`[1:])
+	s.costAPI.CheckCallNames(c, "InstanceTypes", "Close")
+}
+
+func (s *EstimateCostSuite) TestEstimateBundleContainerPlacement(c *gc.C) {
+	bundlePath := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(bundlePath, []byte(`
+applications:
+  haproxy:
+    charm: cs:haproxy
+    num_units: 4
+    to: ["lxd:new", "kvm:new", "lxd:0"]
+machines:
+  "0":
+    constraints: instance-type=m3.large
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, model.NewEstimateCostCommandForTest(&s.fake, s.costAPI, s.store), bundlePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Machine    Instance type  Monthly  Notes
+0          m3.large       97.09    
+haproxy/0  m3.medium      48.91    
+haproxy/1  m3.medium      48.91    
+
+Total: 194.91 USD/month
+`[1:])
+	s.costAPI.CheckCallNames(c, "InstanceTypes", "Close")
+}
+
+func (s *EstimateCostSuite) TestEstimatePricingFile(c *gc.C) {
+	pricingPath := filepath.Join(c.MkDir(), "pricing.json")
+	err := ioutil.WriteFile(pricingPath, []byte(`{"format": "pricing:1.0", "currency": "EUR", "clouds": {}}`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cmdtesting.RunCommand(c, model.NewEstimateCostCommandForTest(&s.fake, s.costAPI, s.store), "--pricing", pricingPath)
+	c.Assert(err, gc.ErrorMatches, `prices for cloud "aws" region "us-east-1" not found`)
+}
+
+func (s *EstimateCostSuite) TestEstimateNoPriceList(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewEstimateCostCommandForTest(&s.fake, s.costAPI, s.store), "--pricing", "/no/such/file")
+	c.Assert(err, gc.ErrorMatches, "cannot read price list: .*")
+	s.fake.CheckNoCalls(c)
+	s.costAPI.CheckNoCalls(c)
+}
+
+func (s *EstimateCostSuite) TestEstimateTooManyArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewEstimateCostCommandForTest(&s.fake, s.costAPI, s.store), "a", "b")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *EstimateCostSuite) TestShowModelCost(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewShowCommandWithCostAPIForTest(&s.fake, s.costAPI, s.store), "--cost", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	var out map[string]map[string]interface{}
+	err = goyaml.Unmarshal([]byte(cmdtesting.Stdout(ctx)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out["basic-model"]["cost"], jc.DeepEquals, map[interface{}]interface{}{
+		"currency": "USD",
+		"monthly":  50.16,
+		"unpriced": []interface{}{"machine 1", "machine 2"},
+	})
+}
+
+func (s *EstimateCostSuite) TestShowModelWithoutCost(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewShowCommandWithCostAPIForTest(&s.fake, s.costAPI, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Not(jc.Contains), "cost:")
+	s.costAPI.CheckNoCalls(c)
+}
+
+type fakeCostAPI struct {
+	gitjujutesting.Stub
+	volumes []params.VolumeDetails
+	pools   []params.StoragePool
+}
+
+func (f *fakeCostAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeCostAPI) InstanceTypes(cons []constraints.Value) ([]params.InstanceTypesResult, error) {
+	f.MethodCall(f, "InstanceTypes", cons)
+	results := make([]params.InstanceTypesResult, len(cons))
+	for i, value := range cons {
+		if value.Mem != nil && *value.Mem > 3840 {
+			results[i].InstanceTypes = []params.InstanceType{
+				{Name: "m3.large", CPUCores: 2, Memory: 7680},
+				{Name: "m3.xlarge", CPUCores: 4, Memory: 15360},
+			}
+			continue
+		}
+		results[i].InstanceTypes = []params.InstanceType{
+			{Name: "m3.large", CPUCores: 2, Memory: 7680},
+			{Name: "m3.medium", CPUCores: 1, Memory: 3840},
+		}
+	}
+	return results, f.NextErr()
+}
+
+func (f *fakeCostAPI) ListVolumes(machines []string) ([]params.VolumeDetailsListResult, error) {
+	f.MethodCall(f, "ListVolumes", machines)
+	return []params.VolumeDetailsListResult{{Result: f.volumes}}, f.NextErr()
+}
+
+func (f *fakeCostAPI) ListPools(providers, names []string) ([]params.StoragePool, error) {
+	f.MethodCall(f, "ListPools", providers, names)
+	return f.pools, f.NextErr()
+}
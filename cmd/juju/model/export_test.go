@@ -53,6 +53,22 @@ func NewShowCommandForTest(api ShowModelAPI, refreshFunc func(jujuclient.ClientS
 	return modelcmd.Wrap(cmd, modelcmd.WrapSkipModelFlags)
 }
 
+// NewShowCommandWithCostAPIForTest returns a ShowCommand with the APIs
+// provided as specified.
+func NewShowCommandWithCostAPIForTest(api ShowModelAPI, costAPI CostAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &showModelCommand{api: api, costAPI: costAPI}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd, modelcmd.WrapSkipModelFlags)
+}
+
+// NewEstimateCostCommandForTest returns an EstimateCostCommand with the
+// APIs provided as specified.
+func NewEstimateCostCommandForTest(modelInfoAPI ShowModelAPI, costAPI CostAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &estimateCostCommand{modelInfoAPI: modelInfoAPI, costAPI: costAPI}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewDumpCommandForTest returns a DumpCommand with the api provided as specified.
 func NewDumpCommandForTest(api DumpModelAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &dumpCommand{api: api}
@@ -25,6 +25,9 @@ By default, the controller is the current controller.
 Users with read access are limited in what they can do with models:
 ` + "`juju models`, `juju machines`, and `juju status`" + `.
 
+Access may be granted to a user group by naming it as "<group>@group";
+every member of the group then has that access. See add-group.
+
 Valid access levels for models are:
     read
     write
@@ -40,6 +43,9 @@ Valid access levels for application offers are:
     consume
     admin
 
+Access to individual applications within a model is managed with
+grant-application.
+
 Examples:
 Grant user 'joe' 'read' access to model 'mymodel':
 
@@ -69,9 +75,15 @@ Grant user 'sam' 'read' access to application offers 'fred/prod.hosted-mysql' an
 
     juju grant sam read fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Grant the members of group 'devs' 'write' access to model 'mymodel':
+
+    juju grant devs@group write mymodel
+
 See also: 
     revoke
-    add-user`[1:]
+    add-user
+    add-group
+    grant-application`[1:]
 
 var usageRevokeSummary = `
 Revokes access from a Juju user for a model, controller, or application offer.`[1:]
@@ -105,7 +117,8 @@ Revoke 'consume' access from user 'sam' for models 'fred/prod.hosted-mysql' and
     juju revoke sam consume fred/prod.hosted-mysql mary/test.hosted-mysql
 
 See also: 
-    grant`[1:]
+    grant
+    revoke-application`[1:]
 
 type accessCommand struct {
 	modelcmd.ControllerCommandBase
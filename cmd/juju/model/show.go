@@ -18,7 +18,20 @@ import (
 	"github.com/juju/juju/cmd/output"
 )
 
-const showModelCommandDoc = `Show information about the current or specified model.`
+const showModelCommandDoc = `
+Show information about the current or specified model.
+
+With --cost, the estimated monthly cost of running the model is also
+shown. See "juju help estimate-cost" for details of how the cost is
+estimated, and of the price list it is estimated from.
+
+Examples:
+    juju show-model
+    juju show-model mymodel --cost
+
+See also:
+    estimate-cost
+`
 
 func NewShowCommand() cmd.Command {
 	showCmd := &showModelCommand{}
@@ -30,6 +43,10 @@ type showModelCommand struct {
 	modelcmd.ModelCommandBase
 	out cmd.Output
 	api ShowModelAPI
+
+	cost        bool
+	pricingFile string
+	costAPI     CostAPI
 }
 
 // ShowModelAPI defines the methods on the client API that the
@@ -50,6 +67,17 @@ func (c *showModelCommand) getAPI() (ShowModelAPI, error) {
 	return modelmanager.NewClient(api), nil
 }
 
+func (c *showModelCommand) getCostAPI() (CostAPI, error) {
+	if c.costAPI != nil {
+		return c.costAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newCostAPI(root), nil
+}
+
 // Info implements Command.Info.
 func (c *showModelCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -63,6 +91,8 @@ func (c *showModelCommand) Info() *cmd.Info {
 // SetFlags implements Command.SetFlags.
 func (c *showModelCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.cost, "cost", false, "Show the estimated monthly cost of running the model")
+	f.StringVar(&c.pricingFile, "pricing", "", "Path to the price list to estimate the cost from")
 	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
 }
 
@@ -111,9 +141,53 @@ func (c *showModelCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.cost {
+		cost, err := c.modelCost(results[0].Result)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for name, info := range infoMap {
+			info.Cost = cost
+			infoMap[name] = info
+		}
+	}
 	return c.out.Write(ctx, infoMap)
 }
 
+// modelCost returns the estimated monthly cost of running the model.
+func (c *showModelCommand) modelCost(info *params.ModelInfo) (*common.ModelCost, error) {
+	catalog, err := readPriceList(c.pricingFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudTag, err := names.ParseCloudTag(info.CloudTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	api, err := c.getCostAPI()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer api.Close()
+	estimator, err := newCostEstimator(api, catalog, cloudTag.Id(), info.CloudRegion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machines, volumes, err := modelCosts(api, info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	estimate, err := estimator.estimate(machines, volumes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &common.ModelCost{
+		Currency: estimate.Currency,
+		Monthly:  estimate.Monthly,
+		Unpriced: estimate.Unpriced,
+	}, nil
+}
+
 func (c *showModelCommand) apiModelInfoToModelInfoMap(modelInfo []params.ModelInfo, controllerName string) (map[string]common.ModelInfo, error) {
 	// TODO(perrito666) 2016-05-02 lp:1558657
 	now := time.Now()
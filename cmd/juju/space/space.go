@@ -145,6 +145,18 @@ func (m *mvpAPIShim) ReloadSpaces() error {
 	return m.facade.ReloadSpaces()
 }
 
+func (m *mvpAPIShim) RemoveSpace(name string) error {
+	return m.facade.RemoveSpace(name)
+}
+
+func (m *mvpAPIShim) UpdateSpace(name string, subnetIds []string) error {
+	return m.facade.UpdateSpace(name, subnetIds)
+}
+
+func (m *mvpAPIShim) RenameSpace(name, newName string) error {
+	return m.facade.RenameSpace(name, newName)
+}
+
 // NewAPI returns a SpaceAPI for the root api endpoint that the
 // environment command returns.
 func (c *SpaceCommandBase) NewAPI() (SpaceAPI, error) {
@@ -43,6 +43,7 @@ type modelStatus struct {
 	Status           statusInfoContents `json:"model-status,omitempty" yaml:"model-status,omitempty"`
 	MeterStatus      *meterStatus       `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
 	SLA              string             `json:"sla,omitempty" yaml:"sla,omitempty"`
+	Warnings         []string           `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 }
 
 type controllerStatus struct {
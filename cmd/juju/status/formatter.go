@@ -67,6 +67,7 @@ func (sf *statusFormatter) format() (formattedStatus, error) {
 			AvailableVersion: sf.status.Model.AvailableVersion,
 			Status:           sf.getStatusInfoContents(sf.status.Model.ModelStatus),
 			SLA:              sf.status.Model.SLA,
+			Warnings:         sf.status.Model.Warnings,
 		},
 		Machines:           make(map[string]machineStatus),
 		Applications:       make(map[string]applicationStatus),
@@ -74,6 +74,10 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 	w := startSection(tw, true, header...)
 	w.Println(values...)
 
+	if len(fs.Model.Warnings) > 0 {
+		printWarnings(tw, fs.Model.Warnings)
+	}
+
 	if len(fs.RemoteApplications) > 0 {
 		printRemoteApplications(tw, fs.RemoteApplications)
 	}
@@ -111,6 +115,15 @@ func endSection(tw *ansiterm.TabWriter) {
 	tw.Flush()
 }
 
+func printWarnings(tw *ansiterm.TabWriter, warnings []string) {
+	w := startSection(tw, false, "Warning")
+	for _, warning := range warnings {
+		w.PrintColor(output.WarningHighlight, warning)
+		w.Println()
+	}
+	endSection(tw)
+}
+
 func printApplications(tw *ansiterm.TabWriter, fs formattedStatus) {
 	maxVersionWidth := iaasMaxVersionWidth
 	if fs.Model.Type == caasModelType {
@@ -386,9 +399,15 @@ func printMachine(w output.Wrapper, m machineStatus) {
 	if hw.AvailabilityZone != nil {
 		az = *hw.AvailabilityZone
 	}
+	message := m.MachineStatus.Message
+	if m.JujuStatus.Current == status.Maintenance {
+		// Show the progress of maintenance in place of the
+		// instance status while the machine is in maintenance.
+		message = m.JujuStatus.Message
+	}
 	w.Print(m.Id)
 	w.PrintStatus(m.JujuStatus.Current)
-	w.Println(m.DNSName, m.InstanceId, m.Series, az, m.MachineStatus.Message)
+	w.Println(m.DNSName, m.InstanceId, m.Series, az, message)
 	for _, name := range naturalsort.Sort(stringKeysFromMap(m.Containers)) {
 		printMachine(w, m.Containers[name])
 	}
@@ -4898,6 +4898,28 @@ func (s *StatusSuite) TestStatusWithNilStatusAPI(c *gc.C) {
 	c.Check(string(stderr), gc.Equals, "ERROR unable to obtain the current status\n")
 }
 
+func (s *StatusSuite) TestFormatTabularWarnings(c *gc.C) {
+	status := formattedStatus{
+		Model: modelStatus{
+			Warnings: []string{
+				"resource tags corrected on machine 0",
+				"cannot correct resource tags on volume 1: boom",
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	err := FormatTabular(out, false, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), gc.Equals, `
+Model  Controller  Cloud/Region  Version
+                                 
+
+Warning
+resource tags corrected on machine 0            
+cannot correct resource tags on volume 1: boom  
+`[1:])
+}
+
 func (s *StatusSuite) TestFormatTabularMetering(c *gc.C) {
 	status := formattedStatus{
 		Applications: map[string]applicationStatus{
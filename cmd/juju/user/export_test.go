@@ -42,6 +42,10 @@ type LogoutCommand struct {
 	*logoutCommand
 }
 
+type AddGroupMemberCommand struct {
+	*addGroupMemberCommand
+}
+
 type DisenableUserBase struct {
 	*disenableUserBase
 }
@@ -90,6 +94,18 @@ func NewLogoutCommandForTest(store jujuclient.ClientStore) (cmd.Command, *Logout
 	return modelcmd.WrapController(c), &LogoutCommand{c}
 }
 
+func NewAddGroupCommandForTest(api GroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addGroupCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewAddGroupMemberCommandForTest(api GroupAPI, store jujuclient.ClientStore) (cmd.Command, *AddGroupMemberCommand) {
+	c := &addGroupMemberCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c), &AddGroupMemberCommand{c}
+}
+
 // NewDisableCommand returns a DisableCommand with the api provided as
 // specified.
 func NewDisableCommandForTest(api disenableUserAPI, store jujuclient.ClientStore) (cmd.Command, *DisenableUserBase) {
@@ -124,3 +140,21 @@ func NewWhoAmICommandForTest(store jujuclient.ClientStore) cmd.Command {
 	c := &whoAmICommand{store: store}
 	return c
 }
+
+func NewAddTokenCommandForTest(api TokenAPI, store jujuclient.ClientStore, clock clock.Clock) cmd.Command {
+	c := &addTokenCommand{tokenCommandBase: tokenCommandBase{api: api}, clock: clock}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewListTokensCommandForTest(api TokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listTokensCommand{tokenCommandBase: tokenCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewRevokeTokenCommandForTest(api TokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &revokeTokenCommand{tokenCommandBase: tokenCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
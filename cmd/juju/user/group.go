@@ -0,0 +1,165 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageAddGroupSummary = `
+Adds a user group to a controller.`[1:]
+
+var usageAddGroupDetails = `
+A user group is a named set of users. Access to models and offers may
+be granted to a group by naming it as "<group>@group"; every member of
+the group then has that access, in addition to any access granted to
+them individually.
+
+Users whose identity is asserted by the controller's OpenID Connect
+issuer are also members of any existing groups named in the issuer's
+groups claim when they log in.
+
+Examples:
+    juju add-group devs
+    juju add-group-member devs bob
+    juju grant devs@group write mymodel
+
+See also: 
+    add-group-member
+    grant
+    revoke`[1:]
+
+var usageAddGroupMemberSummary = `
+Adds a user to a user group.`[1:]
+
+var usageAddGroupMemberDetails = `
+Local users must already exist in the controller. External users may
+be added before they first log in.
+
+Examples:
+    juju add-group-member devs bob
+    juju add-group-member devs alice@oidc
+
+See also: 
+    add-group
+    grant`[1:]
+
+// GroupAPI defines the usermanager API methods that the group
+// commands use.
+type GroupAPI interface {
+	AddGroup(name string) error
+	AddGroupMember(group, username string) error
+	Close() error
+}
+
+// NewAddGroupCommand returns a command to add a user group.
+func NewAddGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&addGroupCommand{})
+}
+
+// addGroupCommand adds a user group to a controller.
+type addGroupCommand struct {
+	modelcmd.ControllerCommandBase
+	api   GroupAPI
+	Group string
+}
+
+// Info implements Command.Info.
+func (c *addGroupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-group",
+		Args:    "<group name>",
+		Purpose: usageAddGroupSummary,
+		Doc:     usageAddGroupDetails,
+	}
+}
+
+// Init implements Command.Init.
+func (c *addGroupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name supplied")
+	}
+	c.Group = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *addGroupCommand) Run(ctx *cmd.Context) error {
+	api := c.api
+	if api == nil {
+		client, err := c.NewUserManagerAPIClient()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		api = client
+	}
+	defer api.Close()
+
+	if err := api.AddGroup(c.Group); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintf(ctx.Stdout, "Group %q added\n", c.Group)
+	return nil
+}
+
+// NewAddGroupMemberCommand returns a command to add a user to a
+// user group.
+func NewAddGroupMemberCommand() cmd.Command {
+	return modelcmd.WrapController(&addGroupMemberCommand{})
+}
+
+// addGroupMemberCommand adds a user to a user group.
+type addGroupMemberCommand struct {
+	modelcmd.ControllerCommandBase
+	api   GroupAPI
+	Group string
+	User  string
+}
+
+// Info implements Command.Info.
+func (c *addGroupMemberCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-group-member",
+		Args:    "<group name> <user name>",
+		Purpose: usageAddGroupMemberSummary,
+		Doc:     usageAddGroupMemberDetails,
+	}
+}
+
+// Init implements Command.Init.
+func (c *addGroupMemberCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no group name supplied")
+	case 1:
+		return errors.New("no username supplied")
+	}
+	c.Group, c.User = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run implements Command.Run.
+func (c *addGroupMemberCommand) Run(ctx *cmd.Context) error {
+	api := c.api
+	if api == nil {
+		client, err := c.NewUserManagerAPIClient()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		api = client
+	}
+	defer api.Close()
+
+	if err := api.AddGroupMember(c.Group, c.User); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintf(ctx.Stdout, "User %q added to group %q\n", c.User, c.Group)
+	return nil
+}
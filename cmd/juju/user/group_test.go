@@ -0,0 +1,104 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/user"
+)
+
+type GroupCommandSuite struct {
+	BaseSuite
+	mockAPI *mockGroupAPI
+}
+
+var _ = gc.Suite(&GroupCommandSuite{})
+
+func (s *GroupCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockGroupAPI{}
+}
+
+type mockGroupAPI struct {
+	group string
+	user  string
+	err   error
+}
+
+func (*mockGroupAPI) Close() error { return nil }
+
+func (m *mockGroupAPI) AddGroup(name string) error {
+	m.group = name
+	return m.err
+}
+
+func (m *mockGroupAPI) AddGroupMember(group, username string) error {
+	m.group, m.user = group, username
+	return m.err
+}
+
+func (s *GroupCommandSuite) TestAddGroupInit(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewAddGroupCommandForTest(s.mockAPI, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no group name supplied")
+	err = cmdtesting.InitCommand(user.NewAddGroupCommandForTest(s.mockAPI, s.store), []string{"devs", "ops"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["ops"\]`)
+}
+
+func (s *GroupCommandSuite) TestAddGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mockAPI, s.store), "devs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.group, gc.Equals, "devs")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "Group \"devs\" added\n")
+}
+
+func (s *GroupCommandSuite) TestAddGroupError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mockAPI, s.store), "devs")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *GroupCommandSuite) TestAddGroupMemberInit(c *gc.C) {
+	for i, test := range []struct {
+		args  []string
+		group string
+		user  string
+		err   string
+	}{{
+		err: "no group name supplied",
+	}, {
+		args: []string{"devs"},
+		err:  "no username supplied",
+	}, {
+		args:  []string{"devs", "bob@oidc"},
+		group: "devs",
+		user:  "bob@oidc",
+	}, {
+		args: []string{"devs", "bob", "alice"},
+		err:  `unrecognized args: \["alice"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		wrapped, command := user.NewAddGroupMemberCommandForTest(s.mockAPI, s.store)
+		err := cmdtesting.InitCommand(wrapped, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.Group, gc.Equals, test.group)
+		c.Check(command.User, gc.Equals, test.user)
+	}
+}
+
+func (s *GroupCommandSuite) TestAddGroupMember(c *gc.C) {
+	command, _ := user.NewAddGroupMemberCommandForTest(s.mockAPI, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, "devs", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.group, gc.Equals, "devs")
+	c.Assert(s.mockAPI.user, gc.Equals, "bob")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "User \"bob\" added to group \"devs\"\n")
+}
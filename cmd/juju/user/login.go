@@ -48,6 +48,12 @@ After login, a token ("macaroon") will become active. It has an expiration
 time of 24 hours. Upon expiration, no further Juju commands can be issued
 and the user will be prompted to log in again.
 
+If the controller is configured with an OpenID Connect issuer (see the
+oidc-issuer-url controller configuration), users known to that issuer
+log in with the "@oidc" domain. Juju prints a URL and a code to enter
+there; once the login is approved with the issuer, the resulting ID
+token is used until it expires.
+
 Aliases
 -------
 
@@ -61,6 +67,7 @@ Examples:
     juju login somepubliccontroller
     juju login jimm.jujucharms.com
     juju login -u bob
+    juju login -u alice@oidc
 
 See also:
     disable-user
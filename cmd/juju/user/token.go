@@ -0,0 +1,362 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var usageAddTokenSummary = `
+Adds an API token with which a user can log in without a password.`[1:]
+
+var usageAddTokenDetails = `
+API tokens let automation, such as CI pipelines, use the Juju API as a
+local user without knowing that user's password. A dedicated user may
+be added for the purpose with add-user. Tokens expire, and may be
+revoked at any time with revoke-token; removing the user revokes all
+of its tokens. Every use of a token is recorded in the audit log.
+
+If no user is given, the token is for the current user. Only
+controller superusers may add tokens for other users.
+
+A token may be restricted to particular models, named as "<model>" or
+"<owner>/<model>", and to particular groups of API methods. The
+method groups are:
+    read-only  methods that don't change anything, such as status
+    status     model status and status history
+    deploy     deploying, configuring and upgrading applications
+    actions    running and querying actions
+    machines   adding and removing machines
+    config     model configuration
+    backups    creating and listing backups
+A token can never be used to add further tokens or change a password.
+Tokens may only be used for API connections, and not for HTTP requests
+such as uploading local charms or resources, or downloading backups.
+
+The credentials for the token are only shown once. To use them, log in
+as the token's user with the credentials as the password, or set the
+user's password in accounts.yaml to the credentials.
+
+Examples:
+    juju add-token ci-bot --expires 720h --models prod --facades deploy,status
+    juju add-token --expires 24h --description "backup job" --facades backups
+
+See also:
+    list-tokens
+    revoke-token
+    add-user`[1:]
+
+var usageListTokensSummary = `
+Lists API tokens.`[1:]
+
+var usageListTokensDetails = `
+Lists the API tokens of the current user. Controller superusers may list
+the tokens of every user with --all. Token credentials are never shown.
+
+Examples:
+    juju list-tokens
+    juju list-tokens --all --format yaml
+
+See also:
+    add-token
+    revoke-token`[1:]
+
+var usageRevokeTokenSummary = `
+Revokes an API token.`[1:]
+
+var usageRevokeTokenDetails = `
+Once revoked, a token can no longer be used to log in. Connections
+already made with the token are not affected. Users may revoke their
+own tokens; controller superusers may revoke any token.
+
+Examples:
+    juju revoke-token 2bd8ad5e-1f8e-4e8b-8a61-1b3c3e3bd1e2
+
+See also:
+    add-token
+    list-tokens`[1:]
+
+// TokenAPI defines the usermanager API methods that the token
+// commands use.
+type TokenAPI interface {
+	AddAPIToken(args params.AddAPIToken) (id, credentials string, _ error)
+	APITokens(all bool) ([]params.APITokenInfo, error)
+	RevokeAPIToken(id string) error
+	Close() error
+}
+
+// tokenCommandBase holds what is common to the token commands.
+type tokenCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api TokenAPI
+}
+
+func (c *tokenCommandBase) getAPI() (TokenAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// NewAddTokenCommand returns a command to add an API token.
+func NewAddTokenCommand() cmd.Command {
+	return modelcmd.WrapController(&addTokenCommand{clock: clock.WallClock})
+}
+
+// addTokenCommand adds an API token for a user.
+type addTokenCommand struct {
+	tokenCommandBase
+	clock clock.Clock
+
+	User         string
+	Description  string
+	Expires      time.Duration
+	Models       string
+	FacadeGroups string
+}
+
+// Info implements Command.Info.
+func (c *addTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-token",
+		Args:    "[<user name>]",
+		Purpose: usageAddTokenSummary,
+		Doc:     usageAddTokenDetails,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addTokenCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.tokenCommandBase.SetFlags(f)
+	f.DurationVar(&c.Expires, "expires", 30*24*time.Hour, "How long until the token expires")
+	f.StringVar(&c.Description, "description", "", "A description of the token's purpose")
+	f.StringVar(&c.Models, "models", "", "Comma-separated list of models the token is restricted to")
+	f.StringVar(&c.FacadeGroups, "facades", "", "Comma-separated list of API method groups the token is restricted to")
+}
+
+// Init implements Command.Init.
+func (c *addTokenCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.User = args[0]
+		if !names.IsValidUser(c.User) {
+			return errors.NotValidf("user name %q", c.User)
+		}
+		args = args[1:]
+	}
+	if c.Expires <= 0 {
+		return errors.New("--expires must be positive")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *addTokenCommand) Run(ctx *cmd.Context) error {
+	user := c.User
+	if user == "" {
+		accountDetails, err := c.CurrentAccountDetails()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		user = accountDetails.User
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	id, credentials, err := api.AddAPIToken(params.AddAPIToken{
+		UserTag:      names.NewUserTag(user).String(),
+		Description:  c.Description,
+		Expires:      c.clock.Now().Add(c.Expires).UTC(),
+		Models:       splitList(c.Models),
+		FacadeGroups: splitList(c.FacadeGroups),
+	})
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Added API token %s for user %q.", id, user)
+	ctx.Infof("Log in as %q with the following as the password; it will not be shown again:", user)
+	fmt.Fprintln(ctx.Stdout, credentials)
+	return nil
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// NewListTokensCommand returns a command to list API tokens.
+func NewListTokensCommand() cmd.Command {
+	return modelcmd.WrapController(&listTokensCommand{})
+}
+
+// listTokensCommand lists API tokens.
+type listTokensCommand struct {
+	tokenCommandBase
+	out output.Output
+
+	All bool
+}
+
+// Info implements Command.Info.
+func (c *listTokensCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-tokens",
+		Purpose: usageListTokensSummary,
+		Doc:     usageListTokensDetails,
+		Aliases: []string{"tokens"},
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listTokensCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.tokenCommandBase.SetFlags(f)
+	f.BoolVar(&c.All, "all", false, "List the tokens of all users")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTokensTabular,
+	})
+}
+
+// tokenInfo holds the information on an API token that is output.
+type tokenInfo struct {
+	Id           string    `yaml:"id" json:"id"`
+	User         string    `yaml:"user" json:"user"`
+	Description  string    `yaml:"description,omitempty" json:"description,omitempty"`
+	CreatedBy    string    `yaml:"created-by" json:"created-by"`
+	DateCreated  time.Time `yaml:"date-created" json:"date-created"`
+	Expires      time.Time `yaml:"expires" json:"expires"`
+	Models       []string  `yaml:"models,omitempty" json:"models,omitempty"`
+	FacadeGroups []string  `yaml:"facade-groups,omitempty" json:"facade-groups,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *listTokensCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	tokens, err := api.APITokens(c.All)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(tokens) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No API tokens to display.")
+		return nil
+	}
+	result := make([]tokenInfo, len(tokens))
+	for i, token := range tokens {
+		var user string
+		if tag, err := names.ParseUserTag(token.UserTag); err == nil {
+			user = tag.Id()
+		}
+		result[i] = tokenInfo{
+			Id:           token.Id,
+			User:         user,
+			Description:  token.Description,
+			CreatedBy:    token.CreatedBy,
+			DateCreated:  token.DateCreated,
+			Expires:      token.Expires,
+			Models:       token.Models,
+			FacadeGroups: token.FacadeGroups,
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func formatTokensTabular(writer io.Writer, value interface{}) error {
+	tokens, ok := value.([]tokenInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", tokens, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Id", "User", "Expires", "Models", "Facades", "Description")
+	for _, token := range tokens {
+		w.Println(
+			token.Id,
+			token.User,
+			token.Expires.Format(time.RFC3339),
+			joinOrAll(token.Models),
+			joinOrAll(token.FacadeGroups),
+			token.Description,
+		)
+	}
+	return tw.Flush()
+}
+
+func joinOrAll(items []string) string {
+	if len(items) == 0 {
+		return "all"
+	}
+	return strings.Join(items, ",")
+}
+
+// NewRevokeTokenCommand returns a command to revoke an API token.
+func NewRevokeTokenCommand() cmd.Command {
+	return modelcmd.WrapController(&revokeTokenCommand{})
+}
+
+// revokeTokenCommand revokes an API token.
+type revokeTokenCommand struct {
+	tokenCommandBase
+	Id string
+}
+
+// Info implements Command.Info.
+func (c *revokeTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke-token",
+		Args:    "<token id>",
+		Purpose: usageRevokeTokenSummary,
+		Doc:     usageRevokeTokenDetails,
+	}
+}
+
+// Init implements Command.Init.
+func (c *revokeTokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no token id supplied")
+	}
+	c.Id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *revokeTokenCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RevokeAPIToken(c.Id); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("API token %s revoked", c.Id)
+	return nil
+}
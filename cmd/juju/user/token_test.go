@@ -0,0 +1,140 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+)
+
+type TokenCommandSuite struct {
+	BaseSuite
+	mockAPI *mockTokenAPI
+	clock   *testing.Clock
+}
+
+var _ = gc.Suite(&TokenCommandSuite{})
+
+func (s *TokenCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockTokenAPI{}
+	s.clock = testing.NewClock(time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC))
+}
+
+type mockTokenAPI struct {
+	testing.Stub
+	tokens []params.APITokenInfo
+}
+
+func (*mockTokenAPI) Close() error { return nil }
+
+func (m *mockTokenAPI) AddAPIToken(args params.AddAPIToken) (string, string, error) {
+	m.MethodCall(m, "AddAPIToken", args)
+	return "token-id", "juju-api-token:token-id:secret", m.NextErr()
+}
+
+func (m *mockTokenAPI) APITokens(all bool) ([]params.APITokenInfo, error) {
+	m.MethodCall(m, "APITokens", all)
+	return m.tokens, m.NextErr()
+}
+
+func (m *mockTokenAPI) RevokeAPIToken(id string) error {
+	m.MethodCall(m, "RevokeAPIToken", id)
+	return m.NextErr()
+}
+
+func (s *TokenCommandSuite) TestAddTokenInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"not/valid"},
+		err:  `user name "not/valid" not valid`,
+	}, {
+		args: []string{"ci", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"--expires", "-1h"},
+		err:  `--expires must be positive`,
+	}} {
+		c.Logf("test %d", i)
+		err := cmdtesting.InitCommand(user.NewAddTokenCommandForTest(s.mockAPI, s.store, s.clock), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *TokenCommandSuite) TestAddToken(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddTokenCommandForTest(s.mockAPI, s.store, s.clock),
+		"ci-bot", "--expires", "24h", "--models", "prod, admin/staging", "--facades", "deploy,status",
+		"--description", "pipeline")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "AddAPIToken", params.AddAPIToken{
+		UserTag:      "user-ci-bot",
+		Description:  "pipeline",
+		Expires:      time.Date(2018, 6, 2, 12, 0, 0, 0, time.UTC),
+		Models:       []string{"prod", "admin/staging"},
+		FacadeGroups: []string{"deploy", "status"},
+	})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "juju-api-token:token-id:secret\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Matches, `(?s)Added API token token-id for user "ci-bot".*`)
+}
+
+func (s *TokenCommandSuite) TestAddTokenCurrentUser(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAddTokenCommandForTest(s.mockAPI, s.store, s.clock))
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "AddAPIToken", params.AddAPIToken{
+		UserTag: "user-current-user",
+		Expires: time.Date(2018, 7, 1, 12, 0, 0, 0, time.UTC),
+	})
+}
+
+func (s *TokenCommandSuite) TestAddTokenError(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, user.NewAddTokenCommandForTest(s.mockAPI, s.store, s.clock), "ci-bot")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *TokenCommandSuite) TestListTokens(c *gc.C) {
+	s.mockAPI.tokens = []params.APITokenInfo{{
+		Id:           "token-id",
+		UserTag:      "user-ci-bot",
+		Description:  "pipeline",
+		CreatedBy:    "admin",
+		DateCreated:  time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+		Expires:      time.Date(2018, 7, 1, 12, 0, 0, 0, time.UTC),
+		FacadeGroups: []string{"deploy"},
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListTokensCommandForTest(s.mockAPI, s.store), "--all")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "APITokens", true)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Id        User    Expires               Models  Facades  Description\n"+
+		"token-id  ci-bot  2018-07-01T12:00:00Z  all     deploy   pipeline\n")
+}
+
+func (s *TokenCommandSuite) TestListTokensNone(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewListTokensCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "APITokens", false)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No API tokens to display.\n")
+}
+
+func (s *TokenCommandSuite) TestRevokeToken(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewRevokeTokenCommandForTest(s.mockAPI, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no token id supplied")
+
+	ctx, err := cmdtesting.RunCommand(c, user.NewRevokeTokenCommandForTest(s.mockAPI, s.store), "token-id")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 0, "RevokeAPIToken", "token-id")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "API token token-id revoked\n")
+}
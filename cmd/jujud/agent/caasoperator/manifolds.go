@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/worker/agent"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/caasoperator"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/gate"
@@ -132,6 +133,13 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:     retrystrategy.NewRetryStrategyWorker,
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent config
+		// as the controller's CA certificates are rotated.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
 		// The operator installs and deploys charm containers;
 		// manages the unit's presence in its relations;
 		// creates suboordinate units; runs all the hooks;
@@ -188,6 +196,7 @@ const (
 
 	charmDirName          = "charm-dir"
 	hookRetryStrategyName = "hook-retry-strategy"
+	caCertUpdaterName     = "ca-cert-updater"
 
 	upgradeStepsGateName = "upgrade-steps-gate"
 	upgradeStepsFlagName = "upgrade-steps-flag"
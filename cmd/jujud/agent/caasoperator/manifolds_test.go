@@ -35,6 +35,7 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 	expectedKeys := []string{
 		"agent",
 		"api-caller",
+		"ca-cert-updater",
 		"charm-dir",
 		"clock",
 		"hook-retry-strategy",
@@ -103,6 +103,7 @@ var (
 	}
 	notMigratingUnitWorkers = []string{
 		"api-address-updater",
+		"ca-cert-updater",
 		"charm-dir",
 		"hook-retry-strategy",
 		"leadership-tracker",
@@ -134,6 +135,7 @@ var (
 	}
 	notMigratingMachineWorkers = []string{
 		"api-address-updater",
+		"ca-cert-updater",
 		"disk-manager",
 		"fan-configurer",
 		// "host-key-reporter", not stable, exits when done
@@ -991,6 +991,7 @@ func (a *MachineAgent) startModelWorkers(modelUUID string, modelType state.Model
 		RunFlagDuration:             time.Minute,
 		CharmRevisionUpdateInterval: 24 * time.Hour,
 		InstPollerAggregationDelay:  3 * time.Second,
+		ResourceTaggerInterval:      30 * time.Minute,
 		StatusHistoryPrunerInterval: 5 * time.Minute,
 		ActionPrunerInterval:        24 * time.Hour,
 		NewEnvironFunc:              newEnvirons,
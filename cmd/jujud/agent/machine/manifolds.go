@@ -39,6 +39,7 @@ import (
 	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/auditconfigupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/common"
@@ -71,6 +72,7 @@ import (
 	"github.com/juju/juju/worker/raft/raftbackstop"
 	"github.com/juju/juju/worker/raft/raftclusterer"
 	"github.com/juju/juju/worker/raft/raftflag"
+	"github.com/juju/juju/worker/raft/raftleader"
 	"github.com/juju/juju/worker/raft/rafttransport"
 	"github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/restorewatcher"
@@ -562,6 +564,13 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent config
+		// as the controller's CA certificates are rotated.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
 		fanConfigurerName: ifNotMigrating(fanconfigurer.Manifold(fanconfigurer.ManifoldConfig{
 			APICallerName: apiCallerName,
 			Clock:         config.Clock,
@@ -786,6 +795,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:      raftbackstop.NewWorker,
 		}),
 
+		// The raft leader publisher tells the API server which
+		// controller is the raft leader, for reporting.
+		raftLeaderName: raftleader.Manifold(raftleader.ManifoldConfig{
+			RaftName:       raftName,
+			CentralHubName: centralHubName,
+			NewWorker:      raftleader.NewWorker,
+		}),
+
 		validCredentialFlagName: credentialvalidator.Manifold(credentialvalidator.ManifoldConfig{
 			APICallerName: apiCallerName,
 			NewFacade:     credentialvalidator.NewFacade,
@@ -878,6 +895,7 @@ const (
 	diskManagerName               = "disk-manager"
 	proxyConfigUpdater            = "proxy-config-updater"
 	apiAddressUpdaterName         = "api-address-updater"
+	caCertUpdaterName             = "ca-cert-updater"
 	machinerName                  = "machiner"
 	logSenderName                 = "log-sender"
 	deployerName                  = "unit-agent-deployer"
@@ -911,6 +929,7 @@ const (
 	raftFlagName      = "raft-leader-flag"
 	raftEnabledName   = "raft-enabled-flag"
 	raftBackstopName  = "raft-backstop"
+	raftLeaderName    = "raft-leader-publisher"
 
 	validCredentialFlagName = "valid-credential-flag"
 )
@@ -50,6 +50,7 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"api-config-watcher",
 		"api-server",
 		"audit-config-updater",
+		"ca-cert-updater",
 		"central-hub",
 		"certificate-updater",
 		"certificate-watcher",
@@ -81,6 +82,7 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"raft-clusterer",
 		"raft-enabled-flag",
 		"raft-leader-flag",
+		"raft-leader-publisher",
 		"raft-transport",
 		"reboot-executor",
 		"restore-watcher",
@@ -155,6 +157,7 @@ func (*ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 		"raft-clusterer",
 		"raft-enabled-flag",
 		"raft-leader-flag",
+		"raft-leader-publisher",
 		"raft-transport",
 		"valid-credential-flag",
 	)
@@ -301,6 +304,17 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"state",
 		"state-config-watcher"},
 
+	"ca-cert-updater": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate"},
+
 	"central-hub": {"agent", "state-config-watcher"},
 
 	"certificate-updater": {
@@ -584,6 +598,24 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"state",
 		"state-config-watcher"},
 
+	"raft-leader-publisher": {
+		"agent",
+		"central-hub",
+		"certificate-watcher",
+		"clock",
+		"http-server",
+		"is-controller-flag",
+		"raft",
+		"raft-enabled-flag",
+		"raft-transport",
+		"state",
+		"state-config-watcher",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"raft-leader-flag": {
 		"agent",
 		"central-hub",
@@ -200,12 +200,26 @@ func (c *CommandBase) NewAPIConnectionParams(
 		}
 	}
 
+	var oidcNotify func(verificationURI, userCode string) error
+	if c.cmdContext != nil {
+		oidcNotify = func(verificationURI, userCode string) error {
+			fmt.Fprintf(c.cmdContext.Stderr, "Please visit %s and enter code %s to log in to %s\n",
+				verificationURI, userCode, controllerName)
+			return nil
+		}
+	} else {
+		oidcNotify = func(verificationURI, userCode string) error {
+			return errors.New("no context to prompt for OIDC login")
+		}
+	}
+
 	return newAPIConnectionParams(
 		store, controllerName, modelName,
 		accountDetails,
 		bakeryClient,
 		c.apiOpen,
 		getPassword,
+		oidcNotify,
 	)
 }
 
@@ -397,6 +411,7 @@ func newAPIConnectionParams(
 	bakery *httpbakery.Client,
 	apiOpen api.OpenFunc,
 	getPassword func(string) (string, error),
+	oidcNotify func(verificationURI, userCode string) error,
 ) (juju.NewAPIConnectionParams, error) {
 	if controllerName == "" {
 		return juju.NewAPIConnectionParams{}, errors.Trace(errNoNameSpecified)
@@ -418,6 +433,22 @@ func newAPIConnectionParams(
 			bakery.WebPageVisitor,
 		)
 	}
+	if accountDetails != nil && isOIDCUser(accountDetails.User) {
+		dialOpts.OIDCLogin = func(issuerURL, clientID string) (string, error) {
+			login := &authentication.OIDCDeviceLogin{Notify: oidcNotify}
+			token, err := login.Login(issuerURL, clientID)
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			// Record the ID token so that subsequent commands can
+			// log in without visiting the issuer until it expires.
+			accountDetails.Password = token
+			if err := store.UpdateAccount(controllerName, *accountDetails); err != nil {
+				logger.Warningf("cannot record OIDC ID token: %v", err)
+			}
+			return token, nil
+		}
+	}
 
 	return juju.NewAPIConnectionParams{
 		Store:          store,
@@ -429,6 +460,16 @@ func newAPIConnectionParams(
 	}, nil
 }
 
+// isOIDCUser reports whether the given user name refers
+// to a user whose identity is asserted by the controller's
+// OpenID Connect issuer.
+func isOIDCUser(user string) bool {
+	if !names.IsValidUser(user) {
+		return false
+	}
+	return names.NewUserTag(user).Domain() == authentication.OIDCUserDomain
+}
+
 // NewGetBootstrapConfigParamsFunc returns a function that, given a controller name,
 // returns the params needed to bootstrap a fresh copy of that controller in the given client store.
 func NewGetBootstrapConfigParamsFunc(
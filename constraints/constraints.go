@@ -32,6 +32,39 @@ const (
 	InstanceType = "instance-type"
 	Spaces       = "spaces"
 	VirtType     = "virt-type"
+
+	InstanceLifecycle = "instance-lifecycle"
+	SpotMaxPrice      = "spot-max-price"
+
+	Affinity          = "affinity"
+	AntiAffinity      = "anti-affinity"
+	PlacementStrategy = "placement-strategy"
+)
+
+// The following constants list the values accepted for the
+// instance-lifecycle constraint.
+const (
+	// InstanceLifecycleSpot requests a spot instance, which runs on
+	// spare capacity and may be reclaimed by the cloud at any time.
+	InstanceLifecycleSpot = "spot"
+
+	// InstanceLifecyclePreemptible requests a preemptible instance,
+	// which runs on spare capacity and may be reclaimed by the cloud
+	// at any time.
+	InstanceLifecyclePreemptible = "preemptible"
+)
+
+// The following constants list the values accepted for the
+// placement-strategy constraint.
+const (
+	// PlacementSpread distributes an application's units across
+	// availability zones when choosing between existing clean
+	// machines. This is the default strategy.
+	PlacementSpread = "spread"
+
+	// PlacementPack prefers existing clean machines in the
+	// availability zones that already host the application's units.
+	PlacementPack = "pack"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -85,6 +118,33 @@ type Value struct {
 	// VirtType, if not nil or empty, indicates that a machine must run the named
 	// virtual type. Only valid for clouds with multi-hypervisor support.
 	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// InstanceLifecycle, if not nil or empty, indicates that a machine
+	// must be provisioned on interruptible capacity, which is cheaper
+	// but may be reclaimed by the cloud at any time. Which values are
+	// accepted depends on the cloud.
+	InstanceLifecycle *string `json:"instance-lifecycle,omitempty" yaml:"instance-lifecycle,omitempty"`
+
+	// SpotMaxPrice, if not nil or empty, indicates the maximum hourly
+	// price, in the cloud's currency, to pay for a spot instance. If it
+	// is not specified then the cloud's on-demand price is the maximum.
+	SpotMaxPrice *string `json:"spot-max-price,omitempty" yaml:"spot-max-price,omitempty"`
+
+	// Affinity, if not nil, holds the names of applications alongside
+	// whose units a unit should be placed, on the same machine, when
+	// no placement directive is given. If none of their machines is
+	// suitable the unit is placed as it would be without affinity.
+	Affinity *[]string `json:"affinity,omitempty" yaml:"affinity,omitempty"`
+
+	// AntiAffinity, if not nil, holds the names of applications with
+	// whose units a unit must never share a host machine, either
+	// directly or in containers. Anti-affinity applies in both
+	// directions.
+	AntiAffinity *[]string `json:"anti-affinity,omitempty" yaml:"anti-affinity,omitempty"`
+
+	// PlacementStrategy, if not nil or empty, indicates how units are
+	// placed when choosing between existing machines.
+	PlacementStrategy *string `json:"placement-strategy,omitempty" yaml:"placement-strategy,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -180,6 +240,36 @@ func (v *Value) HasVirtType() bool {
 	return v.VirtType != nil && *v.VirtType != ""
 }
 
+// HasInstanceLifecycle returns true if the constraints.Value specifies
+// an instance lifecycle.
+func (v *Value) HasInstanceLifecycle() bool {
+	return v.InstanceLifecycle != nil && *v.InstanceLifecycle != ""
+}
+
+// HasSpotMaxPrice returns true if the constraints.Value specifies a
+// maximum spot price.
+func (v *Value) HasSpotMaxPrice() bool {
+	return v.SpotMaxPrice != nil && *v.SpotMaxPrice != ""
+}
+
+// HasAffinity returns true if the constraints.Value specifies any
+// applications to place units alongside.
+func (v *Value) HasAffinity() bool {
+	return v.Affinity != nil && len(*v.Affinity) > 0
+}
+
+// HasAntiAffinity returns true if the constraints.Value specifies any
+// applications never to place units alongside.
+func (v *Value) HasAntiAffinity() bool {
+	return v.AntiAffinity != nil && len(*v.AntiAffinity) > 0
+}
+
+// HasPlacementStrategy returns true if the constraints.Value specifies
+// a placement strategy.
+func (v *Value) HasPlacementStrategy() bool {
+	return v.PlacementStrategy != nil && *v.PlacementStrategy != ""
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -223,6 +313,23 @@ func (v Value) String() string {
 	if v.VirtType != nil {
 		strs = append(strs, "virt-type="+(*v.VirtType))
 	}
+	if v.InstanceLifecycle != nil {
+		strs = append(strs, "instance-lifecycle="+(*v.InstanceLifecycle))
+	}
+	if v.SpotMaxPrice != nil {
+		strs = append(strs, "spot-max-price="+(*v.SpotMaxPrice))
+	}
+	if v.Affinity != nil {
+		s := strings.Join(*v.Affinity, ",")
+		strs = append(strs, "affinity="+s)
+	}
+	if v.AntiAffinity != nil {
+		s := strings.Join(*v.AntiAffinity, ",")
+		strs = append(strs, "anti-affinity="+s)
+	}
+	if v.PlacementStrategy != nil {
+		strs = append(strs, "placement-strategy="+(*v.PlacementStrategy))
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -264,6 +371,25 @@ func (v Value) GoString() string {
 	if v.VirtType != nil {
 		values = append(values, fmt.Sprintf("VirtType: %q", *v.VirtType))
 	}
+	if v.InstanceLifecycle != nil {
+		values = append(values, fmt.Sprintf("InstanceLifecycle: %q", *v.InstanceLifecycle))
+	}
+	if v.SpotMaxPrice != nil {
+		values = append(values, fmt.Sprintf("SpotMaxPrice: %q", *v.SpotMaxPrice))
+	}
+	if v.Affinity != nil && *v.Affinity != nil {
+		values = append(values, fmt.Sprintf("Affinity: %q", *v.Affinity))
+	} else if v.Affinity != nil {
+		values = append(values, "Affinity: (*[]string)(nil)")
+	}
+	if v.AntiAffinity != nil && *v.AntiAffinity != nil {
+		values = append(values, fmt.Sprintf("AntiAffinity: %q", *v.AntiAffinity))
+	} else if v.AntiAffinity != nil {
+		values = append(values, "AntiAffinity: (*[]string)(nil)")
+	}
+	if v.PlacementStrategy != nil {
+		values = append(values, fmt.Sprintf("PlacementStrategy: %q", *v.PlacementStrategy))
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -307,6 +433,9 @@ func ParseWithAliases(args ...string) (cons Value, aliases map[string]string, er
 			}
 		}
 	}
+	if err := cons.validateAffinity(); err != nil {
+		return Value{}, aliases, errors.Trace(err)
+	}
 	return cons, aliases, nil
 }
 
@@ -420,6 +549,16 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setSpaces(str)
 	case VirtType:
 		err = v.setVirtType(str)
+	case InstanceLifecycle:
+		err = v.setInstanceLifecycle(str)
+	case SpotMaxPrice:
+		err = v.setSpotMaxPrice(str)
+	case Affinity:
+		err = v.setAffinity(str)
+	case AntiAffinity:
+		err = v.setAntiAffinity(str)
+	case PlacementStrategy:
+		err = v.setPlacementStrategy(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -483,6 +622,39 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			}
 		case VirtType:
 			v.VirtType = &vstr
+		case InstanceLifecycle:
+			err = validateInstanceLifecycle(vstr)
+			if err == nil {
+				v.InstanceLifecycle = &vstr
+			}
+		case SpotMaxPrice:
+			err = validateSpotMaxPrice(vstr)
+			if err == nil {
+				v.SpotMaxPrice = &vstr
+			}
+		case Affinity:
+			var apps *[]string
+			apps, err = parseYamlStrings("affinity", val)
+			if err == nil {
+				err = validateApplicationNames(apps)
+			}
+			if err == nil {
+				v.Affinity = apps
+			}
+		case AntiAffinity:
+			var apps *[]string
+			apps, err = parseYamlStrings("anti-affinity", val)
+			if err == nil {
+				err = validateApplicationNames(apps)
+			}
+			if err == nil {
+				v.AntiAffinity = apps
+			}
+		case PlacementStrategy:
+			err = validatePlacementStrategy(vstr)
+			if err == nil {
+				v.PlacementStrategy = &vstr
+			}
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -490,7 +662,7 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			return errors.Trace(err)
 		}
 	}
-	return nil
+	return errors.Trace(v.validateAffinity())
 }
 
 func (v *Value) setContainer(str string) error {
@@ -607,6 +779,118 @@ func (v *Value) setVirtType(str string) error {
 	return nil
 }
 
+func (v *Value) setInstanceLifecycle(str string) error {
+	if v.InstanceLifecycle != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validateInstanceLifecycle(str); err != nil {
+		return err
+	}
+	v.InstanceLifecycle = &str
+	return nil
+}
+
+func validateInstanceLifecycle(str string) error {
+	switch str {
+	case "", InstanceLifecycleSpot, InstanceLifecyclePreemptible:
+		return nil
+	}
+	return errors.Errorf("%q not recognized", str)
+}
+
+func (v *Value) setSpotMaxPrice(str string) error {
+	if v.SpotMaxPrice != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validateSpotMaxPrice(str); err != nil {
+		return err
+	}
+	v.SpotMaxPrice = &str
+	return nil
+}
+
+func validateSpotMaxPrice(str string) error {
+	if str == "" {
+		return nil
+	}
+	price, err := strconv.ParseFloat(str, 64)
+	if err != nil || price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return errors.Errorf("must be a positive decimal number")
+	}
+	return nil
+}
+
+func (v *Value) setAffinity(str string) error {
+	if v.Affinity != nil {
+		return errors.Errorf("already set")
+	}
+	apps := parseCommaDelimited(str)
+	if err := validateApplicationNames(apps); err != nil {
+		return err
+	}
+	v.Affinity = apps
+	return nil
+}
+
+func (v *Value) setAntiAffinity(str string) error {
+	if v.AntiAffinity != nil {
+		return errors.Errorf("already set")
+	}
+	apps := parseCommaDelimited(str)
+	if err := validateApplicationNames(apps); err != nil {
+		return err
+	}
+	v.AntiAffinity = apps
+	return nil
+}
+
+func validateApplicationNames(apps *[]string) error {
+	for _, name := range *apps {
+		if !names.IsValidApplication(name) {
+			return errors.Errorf("%q is not a valid application name", name)
+		}
+	}
+	return nil
+}
+
+// validateAffinity returns an error if an application is named in
+// both the affinity and anti-affinity constraints.
+func (v *Value) validateAffinity() error {
+	if v.Affinity == nil || v.AntiAffinity == nil {
+		return nil
+	}
+	for _, affine := range *v.Affinity {
+		for _, antiAffine := range *v.AntiAffinity {
+			if affine == antiAffine {
+				return errors.Errorf(
+					"application %q cannot be in both %q and %q constraints",
+					affine, Affinity, AntiAffinity,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Value) setPlacementStrategy(str string) error {
+	if v.PlacementStrategy != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validatePlacementStrategy(str); err != nil {
+		return err
+	}
+	v.PlacementStrategy = &str
+	return nil
+}
+
+func validatePlacementStrategy(str string) error {
+	switch str {
+	case "", PlacementSpread, PlacementPack:
+		return nil
+	}
+	return errors.Errorf("%q not recognized", str)
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -313,6 +313,109 @@ var parseConstraintsTests = []struct {
 		err:     `bad "virt-type" constraint: already set`,
 	},
 
+	// "instance-lifecycle" in detail.
+	{
+		summary: "set instance-lifecycle empty",
+		args:    []string{"instance-lifecycle="},
+	}, {
+		summary: "set instance-lifecycle spot",
+		args:    []string{"instance-lifecycle=spot"},
+	}, {
+		summary: "set instance-lifecycle preemptible",
+		args:    []string{"instance-lifecycle=preemptible"},
+	}, {
+		summary: "set invalid instance-lifecycle",
+		args:    []string{"instance-lifecycle=cheap"},
+		err:     `bad "instance-lifecycle" constraint: "cheap" not recognized`,
+	}, {
+		summary: "double set instance-lifecycle separately",
+		args:    []string{"instance-lifecycle=spot", "instance-lifecycle="},
+		err:     `bad "instance-lifecycle" constraint: already set`,
+	},
+
+	// "spot-max-price" in detail.
+	{
+		summary: "set spot-max-price empty",
+		args:    []string{"spot-max-price="},
+	}, {
+		summary: "set spot-max-price",
+		args:    []string{"spot-max-price=0.0125"},
+	}, {
+		summary: "set zero spot-max-price",
+		args:    []string{"spot-max-price=0"},
+		err:     `bad "spot-max-price" constraint: must be a positive decimal number`,
+	}, {
+		summary: "set negative spot-max-price",
+		args:    []string{"spot-max-price=-1"},
+		err:     `bad "spot-max-price" constraint: must be a positive decimal number`,
+	}, {
+		summary: "set non-numeric spot-max-price",
+		args:    []string{"spot-max-price=cheap"},
+		err:     `bad "spot-max-price" constraint: must be a positive decimal number`,
+	}, {
+		summary: "double set spot-max-price together",
+		args:    []string{"spot-max-price=1 spot-max-price=2"},
+		err:     `bad "spot-max-price" constraint: already set`,
+	},
+
+	// "affinity" and "anti-affinity" in detail.
+	{
+		summary: "set affinity empty",
+		args:    []string{"affinity="},
+	}, {
+		summary: "set affinity",
+		args:    []string{"affinity=mysql,mongodb"},
+	}, {
+		summary: "set invalid affinity",
+		args:    []string{"affinity=mysql,Mongo_DB"},
+		err:     `bad "affinity" constraint: "Mongo_DB" is not a valid application name`,
+	}, {
+		summary: "double set affinity separately",
+		args:    []string{"affinity=mysql", "affinity="},
+		err:     `bad "affinity" constraint: already set`,
+	}, {
+		summary: "set anti-affinity empty",
+		args:    []string{"anti-affinity="},
+	}, {
+		summary: "set anti-affinity",
+		args:    []string{"anti-affinity=mysql,mongodb"},
+	}, {
+		summary: "set invalid anti-affinity",
+		args:    []string{"anti-affinity=mysql,"},
+		err:     `bad "anti-affinity" constraint: "" is not a valid application name`,
+	}, {
+		summary: "double set anti-affinity together",
+		args:    []string{"anti-affinity=mysql anti-affinity=mongodb"},
+		err:     `bad "anti-affinity" constraint: already set`,
+	}, {
+		summary: "set affinity and anti-affinity",
+		args:    []string{"affinity=mysql anti-affinity=mongodb"},
+	}, {
+		summary: "set conflicting affinity and anti-affinity",
+		args:    []string{"affinity=mysql", "anti-affinity=mongodb,mysql"},
+		err:     `application "mysql" cannot be in both "affinity" and "anti-affinity" constraints`,
+	},
+
+	// "placement-strategy" in detail.
+	{
+		summary: "set placement-strategy empty",
+		args:    []string{"placement-strategy="},
+	}, {
+		summary: "set placement-strategy spread",
+		args:    []string{"placement-strategy=spread"},
+	}, {
+		summary: "set placement-strategy pack",
+		args:    []string{"placement-strategy=pack"},
+	}, {
+		summary: "set invalid placement-strategy",
+		args:    []string{"placement-strategy=scatter"},
+		err:     `bad "placement-strategy" constraint: "scatter" not recognized`,
+	}, {
+		summary: "double set placement-strategy separately",
+		args:    []string{"placement-strategy=pack", "placement-strategy=spread"},
+		err:     `bad "placement-strategy" constraint: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -510,16 +613,31 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Spaces3", constraints.Value{Spaces: &[]string{"space1", "^space2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"InstanceLifecycle1", constraints.Value{InstanceLifecycle: strp("")}},
+	{"InstanceLifecycle2", constraints.Value{InstanceLifecycle: strp("spot")}},
+	{"SpotMaxPrice1", constraints.Value{SpotMaxPrice: strp("")}},
+	{"SpotMaxPrice2", constraints.Value{SpotMaxPrice: strp("0.05")}},
+	{"Affinity1", constraints.Value{Affinity: &[]string{}}},
+	{"Affinity2", constraints.Value{Affinity: &[]string{"mysql", "mongodb"}}},
+	{"AntiAffinity1", constraints.Value{AntiAffinity: &[]string{}}},
+	{"AntiAffinity2", constraints.Value{AntiAffinity: &[]string{"mysql", "mongodb"}}},
+	{"PlacementStrategy1", constraints.Value{PlacementStrategy: strp("")}},
+	{"PlacementStrategy2", constraints.Value{PlacementStrategy: strp("pack")}},
 	{"All", constraints.Value{
-		Arch:         strp("i386"),
-		Container:    ctypep("lxd"),
-		CpuCores:     uint64p(4096),
-		CpuPower:     uint64p(9001),
-		Mem:          uint64p(18000000000),
-		RootDisk:     uint64p(24000000000),
-		Tags:         &[]string{"foo", "bar"},
-		Spaces:       &[]string{"space1", "^space2"},
-		InstanceType: strp("foo"),
+		Arch:              strp("i386"),
+		Container:         ctypep("lxd"),
+		CpuCores:          uint64p(4096),
+		CpuPower:          uint64p(9001),
+		Mem:               uint64p(18000000000),
+		RootDisk:          uint64p(24000000000),
+		Tags:              &[]string{"foo", "bar"},
+		Spaces:            &[]string{"space1", "^space2"},
+		InstanceType:      strp("foo"),
+		InstanceLifecycle: strp("spot"),
+		SpotMaxPrice:      strp("0.05"),
+		Affinity:          &[]string{"wordpress"},
+		AntiAffinity:      &[]string{"mysql", "mongodb"},
+		PlacementStrategy: strp("spread"),
 	}},
 }
 
@@ -5,6 +5,7 @@ package kvm
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/arch"
@@ -27,10 +28,15 @@ type kvmContainer struct {
 
 	pathfinder func(string) (string, error)
 	runCmd     runFunc
+	dial       dialFunc
 }
 
 var _ Container = (*kvmContainer)(nil)
 
+// syncMutex serialises image synchronisation, so that machines created
+// concurrently do not race to download the same backing image.
+var syncMutex sync.Mutex
+
 func (c *kvmContainer) Name() string {
 	return c.name
 }
@@ -61,7 +67,10 @@ func (c *kvmContainer) Start(params StartParams) error {
 			params.StatusCallback(status.Provisioning, msg, nil)
 		}
 	}
-	if err := Sync(sp, nil, callback); err != nil {
+	syncMutex.Lock()
+	err := Sync(sp, nil, callback)
+	syncMutex.Unlock()
+	if err != nil {
 		if !errors.IsAlreadyExists(err) {
 			return errors.Trace(err)
 		}
@@ -95,6 +104,7 @@ func (c *kvmContainer) Start(params StartParams) error {
 		CpuCores:          params.CpuCores,
 		RootDisk:          params.RootDisk,
 		Interfaces:        interfaces,
+		dial:              c.dial,
 	}); err != nil {
 		return err
 	}
@@ -122,11 +132,11 @@ func (c *kvmContainer) IsRunning() bool {
 	if c.started != nil {
 		return *c.started
 	}
-	machines, err := ListMachines(run)
+	state, err := machineStatus(c.dial, run, c.name)
 	if err != nil {
 		return false
 	}
-	c.started = isRunning(machines[c.name])
+	c.started = isRunning(state)
 	return *c.started
 }
 
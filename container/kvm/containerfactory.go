@@ -24,7 +24,7 @@ func isRunning(value string) *bool {
 }
 
 func (factory *containerFactory) List() (result []Container, err error) {
-	machines, err := ListMachines(run)
+	machines, err := listMachines(dialLibvirt, run)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +35,19 @@ those calls. The wrapped commands exist, shockingly, in wrappedcmds.go with
 the exception of libvirt pool initialisation bits which are in
 initialization.go.
 
+Domains themselves are now managed through the libvirt daemon's RPC protocol,
+using the small client in juju/container/kvm/libvirt/remote.go, rather than by
+parsing virsh output. This gives us libvirt's own errors, real domain states
+and hotplugging of disks and network interfaces into running guests. Each
+operation uses its own connection, so guests can be created concurrently. If
+the daemon's socket cannot be reached, the virsh commands in wrappedcmds.go are
+still used; see domains.go.
+
+Disk hotplugging backs the "kvm" storage provider in storage.go, which lets
+juju add-storage add volumes to running guests. Its volumes are qcow2 images in
+the guest pool, scoped to the guest's host so that the host's storage
+provisioner creates them and attaches them to the guest.
+
 After the provisioner initializes the kvm environment, we synchronise (fetch if
 we don't have one) an ubuntu qcow image for the appropriate series and
 architecture. This happens in sync.go and uses Juju's simplestreams
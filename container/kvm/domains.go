@@ -0,0 +1,196 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+// This file contains the libvirt API backed implementations of the
+// operations in wrappedcmds.go. Each operation uses its own connection to
+// the libvirt daemon, so that guests can be managed concurrently. When the
+// daemon cannot be reached, the operations fall back to running virsh.
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/network"
+)
+
+// libvirtConn is the subset of the libvirt API used to manage guests.
+type libvirtConn interface {
+	DefineDomain(domainXML string) error
+	StartDomain(name string) error
+	DestroyDomain(name string) error
+	UndefineDomain(name string) error
+	SetAutostart(name string, autostart bool) error
+	DomainState(name string) (libvirt.DomainState, error)
+	ListDomains() (map[string]libvirt.DomainState, error)
+	AttachDevice(name, deviceXML string) error
+	DetachDevice(name, deviceXML string) error
+	Close() error
+}
+
+// dialFunc returns a new connection to the libvirt daemon.
+type dialFunc func() (libvirtConn, error)
+
+// dialLibvirt connects to the local libvirt daemon.
+func dialLibvirt() (libvirtConn, error) {
+	conn, err := libvirt.Dial(libvirt.DefaultSocket)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return conn, nil
+}
+
+// withLibvirt calls f with a new connection from dial. If no connection can
+// be made, f is not called and false is returned so that the caller can fall
+// back to virsh.
+func withLibvirt(dial dialFunc, f func(libvirtConn) error) (bool, error) {
+	if dial == nil {
+		dial = dialLibvirt
+	}
+	conn, err := dial()
+	if err != nil {
+		logger.Debugf("libvirt API unavailable, falling back to virsh: %v", err)
+		return false, nil
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Debugf("failed to close libvirt connection: %v", err)
+		}
+	}()
+	return true, f(conn)
+}
+
+// listMachines returns a map of machine name to state, as reported by
+// the libvirt API or, failing that, by `virsh list`.
+func listMachines(dial dialFunc, runCmd runFunc) (map[string]string, error) {
+	var result map[string]string
+	ok, err := withLibvirt(dial, func(conn libvirtConn) error {
+		domains, err := conn.ListDomains()
+		if err != nil {
+			return errors.Annotate(err, "listing domains")
+		}
+		result = make(map[string]string, len(domains))
+		for name, state := range domains {
+			result[name] = state.String()
+		}
+		return nil
+	})
+	if ok {
+		return result, errors.Trace(err)
+	}
+	return ListMachines(runCmd)
+}
+
+// machineStatus returns the state of the named machine.
+func machineStatus(dial dialFunc, runCmd runFunc, name string) (string, error) {
+	var status string
+	ok, err := withLibvirt(dial, func(conn libvirtConn) error {
+		state, err := conn.DomainState(name)
+		if libvirt.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Annotatef(err, "getting state of domain %q", name)
+		}
+		status = state.String()
+		return nil
+	})
+	if ok {
+		return status, errors.Trace(err)
+	}
+	machines, err := ListMachines(runCmd)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return machines[name], nil
+}
+
+// AttachDisk hot-plugs the disk image at the input path, of the input
+// driver type (raw or qcow2), into the named running machine as the input
+// guest device, such as "vdc". If serial is not empty, the guest sees it
+// as the disk's serial number. The disk is also added to the machine's
+// persistent definition.
+func AttachDisk(hostname, source, driver, dev, serial string) error {
+	return attachDisk(dialLibvirt, run, hostname, source, driver, dev, serial)
+}
+
+// DetachDisk hot-unplugs a disk previously added with AttachDisk.
+func DetachDisk(hostname, source, driver, dev string) error {
+	return detachDisk(dialLibvirt, run, hostname, source, driver, dev)
+}
+
+// AttachInterface hot-plugs a network interface with the input config into
+// the named running machine, bridged to the interface's parent device.
+func AttachInterface(hostname string, iface network.InterfaceInfo) error {
+	return attachInterface(dialLibvirt, run, hostname, iface)
+}
+
+// DetachInterface hot-unplugs a network interface previously added with
+// AttachInterface.
+func DetachInterface(hostname string, iface network.InterfaceInfo) error {
+	return detachInterface(dialLibvirt, run, hostname, iface)
+}
+
+func attachDisk(dial dialFunc, runCmd runFunc, hostname, source, driver, dev, serial string) error {
+	diskXML, err := libvirt.DiskXML(source, driver, dev, serial)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ok, err := withLibvirt(dial, func(conn libvirtConn) error {
+		return conn.AttachDevice(hostname, diskXML)
+	})
+	if !ok {
+		args := []string{"attach-disk", hostname, source, dev,
+			"--driver", "qemu", "--subdriver", driver, "--live", "--persistent"}
+		if serial != "" {
+			args = append(args, "--serial", serial)
+		}
+		_, err = runCmd("virsh", args...)
+	}
+	return errors.Annotatef(err, "failed to attach disk %q to domain %q", source, hostname)
+}
+
+func detachDisk(dial dialFunc, runCmd runFunc, hostname, source, driver, dev string) error {
+	diskXML, err := libvirt.DiskXML(source, driver, dev, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ok, err := withLibvirt(dial, func(conn libvirtConn) error {
+		return conn.DetachDevice(hostname, diskXML)
+	})
+	if !ok {
+		_, err = runCmd("virsh", "detach-disk", hostname, dev, "--live", "--persistent")
+	}
+	return errors.Annotatef(err, "failed to detach disk %q from domain %q", source, hostname)
+}
+
+func attachInterface(dial dialFunc, runCmd runFunc, hostname string, iface network.InterfaceInfo) error {
+	ifaceXML, err := libvirt.InterfaceXML(interfaceInfo{config: iface})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ok, err := withLibvirt(dial, func(conn libvirtConn) error {
+		return conn.AttachDevice(hostname, ifaceXML)
+	})
+	if !ok {
+		_, err = runCmd("virsh", "attach-interface", hostname, "bridge", iface.ParentInterfaceName,
+			"--mac", iface.MACAddress, "--model", "virtio", "--live", "--persistent")
+	}
+	return errors.Annotatef(err, "failed to attach interface %q to domain %q", iface.MACAddress, hostname)
+}
+
+func detachInterface(dial dialFunc, runCmd runFunc, hostname string, iface network.InterfaceInfo) error {
+	ifaceXML, err := libvirt.InterfaceXML(interfaceInfo{config: iface})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ok, err := withLibvirt(dial, func(conn libvirtConn) error {
+		return conn.DetachDevice(hostname, ifaceXML)
+	})
+	if !ok {
+		_, err = runCmd("virsh", "detach-interface", hostname, "bridge",
+			"--mac", iface.MACAddress, "--live", "--persistent")
+	}
+	return errors.Annotatef(err, "failed to detach interface %q from domain %q", iface.MACAddress, hostname)
+}
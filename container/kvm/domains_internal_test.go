@@ -0,0 +1,277 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/network"
+)
+
+type domainsInternalSuite struct {
+	testing.IsolationSuite
+
+	conn *fakeLibvirtConn
+	stub *runStub
+}
+
+var _ = gc.Suite(&domainsInternalSuite{})
+
+func (s *domainsInternalSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.conn = &fakeLibvirtConn{}
+	s.stub = &runStub{output: "success"}
+}
+
+func (s *domainsInternalSuite) dial() (libvirtConn, error) {
+	return s.conn, nil
+}
+
+func (s *domainsInternalSuite) TestCreateMachineUsesLibvirt(c *gc.C) {
+	tmpDir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudInitPath := filepath.Join(tmpDir, "cloud-init")
+	err = ioutil.WriteFile(cloudInitPath, []byte("#cloud-init\nEOF\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := CreateMachineParams{
+		Hostname:     "host00",
+		Series:       "xenial",
+		UserDataFile: cloudInitPath,
+		RootDisk:     8,
+		findPath:     func(string) (string, error) { return tmpDir, nil },
+		runCmd:       s.stub.Run,
+		runCmdAsRoot: s.stub.Run,
+		dial:         s.dial,
+		arch:         "amd64",
+	}
+	err = CreateMachine(params)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the disk images are created by running commands.
+	c.Assert(s.stub.Calls(), gc.HasLen, 2)
+	c.Check(s.stub.Calls()[0], jc.HasPrefix, "genisoimage ")
+	c.Check(s.stub.Calls()[1], jc.HasPrefix, "qemu-img ")
+
+	c.Assert(s.conn.calls, gc.HasLen, 3)
+	c.Check(s.conn.calls[0], jc.HasPrefix, "define <domain type=\"kvm\">")
+	c.Check(s.conn.calls[1:], jc.DeepEquals, []string{"start host00", "close"})
+}
+
+func (s *domainsInternalSuite) TestCreateMachineStartFails(c *gc.C) {
+	tmpDir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudInitPath := filepath.Join(tmpDir, "cloud-init")
+	err = ioutil.WriteFile(cloudInitPath, []byte("#cloud-init\nEOF\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.conn.err = errors.New("boom")
+
+	err = CreateMachine(CreateMachineParams{
+		Hostname:     "host00",
+		UserDataFile: cloudInitPath,
+		findPath:     func(string) (string, error) { return tmpDir, nil },
+		runCmd:       s.stub.Run,
+		runCmdAsRoot: s.stub.Run,
+		dial:         s.dial,
+	})
+	c.Check(err, gc.ErrorMatches, `failed to define the domain for "host00": boom`)
+	c.Check(s.stub.Calls(), gc.HasLen, 2)
+}
+
+func (s *domainsInternalSuite) TestDestroyMachineUsesLibvirt(c *gc.C) {
+	tmpDir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.conn.err = errors.New("boom")
+
+	container := &kvmContainer{
+		name:       "aname",
+		runCmd:     s.stub.Run,
+		pathfinder: func(string) (string, error) { return tmpDir, nil },
+		dial:       s.dial,
+	}
+	err = DestroyMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+	c.Check(s.conn.calls, jc.DeepEquals, []string{"destroy aname", "undefine aname", "close"})
+}
+
+func (s *domainsInternalSuite) TestAutostartMachineUsesLibvirt(c *gc.C) {
+	container := &kvmContainer{name: "aname", runCmd: s.stub.Run, dial: s.dial}
+	err := AutostartMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+	c.Check(s.conn.calls, jc.DeepEquals, []string{"autostart aname true", "close"})
+}
+
+func (s *domainsInternalSuite) TestListMachinesUsesLibvirt(c *gc.C) {
+	s.conn.domains = map[string]libvirt.DomainState{
+		"juju-06f00d-0": libvirt.DomainRunning,
+		"juju-06f00d-1": libvirt.DomainShutoff,
+	}
+	machines, err := listMachines(s.dial, s.stub.Run)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(machines, jc.DeepEquals, map[string]string{
+		"juju-06f00d-0": "running",
+		"juju-06f00d-1": "shut off",
+	})
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *domainsInternalSuite) TestListMachinesFallsBackToVirsh(c *gc.C) {
+	s.stub.output = " 2     juju-06f00d-0                  running\n"
+	machines, err := listMachines(noLibvirt, s.stub.Run)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(machines, jc.DeepEquals, map[string]string{"juju-06f00d-0": "running"})
+	c.Check(s.stub.Calls(), jc.DeepEquals, []string{"virsh -q list --all"})
+}
+
+func (s *domainsInternalSuite) TestIsRunningPollsDomainState(c *gc.C) {
+	s.conn.domains = map[string]libvirt.DomainState{"juju-06f00d-0": libvirt.DomainRunning}
+
+	container := &kvmContainer{name: "juju-06f00d-0", dial: s.dial}
+	c.Check(container.IsRunning(), jc.IsTrue)
+
+	container = &kvmContainer{name: "juju-06f00d-1", dial: s.dial}
+	c.Check(container.IsRunning(), jc.IsFalse)
+}
+
+func (s *domainsInternalSuite) TestAttachDiskUsesLibvirt(c *gc.C) {
+	err := attachDisk(s.dial, s.stub.Run, "aname", "/path/to/disk.img", "raw", "vdc", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+	c.Assert(s.conn.calls, gc.HasLen, 2)
+	c.Check(s.conn.calls[0], jc.HasPrefix, `attach aname <disk device="disk" type="file">`)
+	c.Check(s.conn.calls[0], jc.Contains, `<source file="/path/to/disk.img"></source>`)
+	c.Check(s.conn.calls[0], jc.Contains, `<target dev="vdc"></target>`)
+}
+
+func (s *domainsInternalSuite) TestAttachDiskFails(c *gc.C) {
+	s.conn.err = errors.New("boom")
+	err := attachDisk(s.dial, s.stub.Run, "aname", "/path/to/disk.img", "raw", "vdc", "")
+	c.Check(err, gc.ErrorMatches, `failed to attach disk "/path/to/disk.img" to domain "aname": boom`)
+}
+
+func (s *domainsInternalSuite) TestAttachDiskFallsBackToVirsh(c *gc.C) {
+	err := attachDisk(noLibvirt, s.stub.Run, "aname", "/path/to/disk.img", "qcow2", "vdc", "volume-0-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls(), jc.DeepEquals, []string{
+		"virsh attach-disk aname /path/to/disk.img vdc --driver qemu --subdriver qcow2 --live --persistent --serial volume-0-1",
+	})
+}
+
+func (s *domainsInternalSuite) TestDetachDiskFallsBackToVirsh(c *gc.C) {
+	err := detachDisk(noLibvirt, s.stub.Run, "aname", "/path/to/disk.img", "qcow2", "vdc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls(), jc.DeepEquals, []string{
+		"virsh detach-disk aname vdc --live --persistent",
+	})
+}
+
+func (s *domainsInternalSuite) TestAttachInterfaceUsesLibvirt(c *gc.C) {
+	iface := network.InterfaceInfo{
+		MACAddress:          "00:16:3e:00:00:01",
+		InterfaceName:       "eth1",
+		ParentInterfaceName: "br-eth1",
+	}
+	err := attachInterface(s.dial, s.stub.Run, "aname", iface)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.conn.calls, gc.HasLen, 2)
+	c.Check(s.conn.calls[0], jc.HasPrefix, `attach aname <interface type="bridge">`)
+	c.Check(s.conn.calls[0], jc.Contains, `<source bridge="br-eth1"></source>`)
+
+	err = detachInterface(s.dial, s.stub.Run, "aname", iface)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.conn.calls[2], jc.HasPrefix, `detach aname <interface type="bridge">`)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *domainsInternalSuite) TestAttachInterfaceFallsBackToVirsh(c *gc.C) {
+	iface := network.InterfaceInfo{
+		MACAddress:          "00:16:3e:00:00:01",
+		ParentInterfaceName: "br-eth1",
+	}
+	err := attachInterface(noLibvirt, s.stub.Run, "aname", iface)
+	c.Assert(err, jc.ErrorIsNil)
+	err = detachInterface(noLibvirt, s.stub.Run, "aname", iface)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls(), jc.DeepEquals, []string{
+		"virsh attach-interface aname bridge br-eth1 --mac 00:16:3e:00:00:01 --model virtio --live --persistent",
+		"virsh detach-interface aname bridge --mac 00:16:3e:00:00:01 --live --persistent",
+	})
+}
+
+// fakeLibvirtConn records the calls made on it, and returns err
+// from all calls that change a domain.
+type fakeLibvirtConn struct {
+	calls   []string
+	domains map[string]libvirt.DomainState
+	err     error
+}
+
+func (f *fakeLibvirtConn) DefineDomain(domainXML string) error {
+	f.calls = append(f.calls, "define "+domainXML)
+	return f.err
+}
+
+func (f *fakeLibvirtConn) StartDomain(name string) error {
+	f.calls = append(f.calls, "start "+name)
+	return f.err
+}
+
+func (f *fakeLibvirtConn) DestroyDomain(name string) error {
+	f.calls = append(f.calls, "destroy "+name)
+	return f.err
+}
+
+func (f *fakeLibvirtConn) UndefineDomain(name string) error {
+	f.calls = append(f.calls, "undefine "+name)
+	return f.err
+}
+
+func (f *fakeLibvirtConn) SetAutostart(name string, autostart bool) error {
+	if autostart {
+		f.calls = append(f.calls, "autostart "+name+" true")
+	} else {
+		f.calls = append(f.calls, "autostart "+name+" false")
+	}
+	return f.err
+}
+
+func (f *fakeLibvirtConn) DomainState(name string) (libvirt.DomainState, error) {
+	state, ok := f.domains[name]
+	if !ok {
+		return libvirt.DomainNoState, &libvirt.Error{Code: 42, Message: "Domain not found"}
+	}
+	return state, nil
+}
+
+func (f *fakeLibvirtConn) ListDomains() (map[string]libvirt.DomainState, error) {
+	return f.domains, nil
+}
+
+func (f *fakeLibvirtConn) AttachDevice(name, deviceXML string) error {
+	f.calls = append(f.calls, "attach "+name+" "+deviceXML)
+	return f.err
+}
+
+func (f *fakeLibvirtConn) DetachDevice(name, deviceXML string) error {
+	f.calls = append(f.calls, "detach "+name+" "+deviceXML)
+	return f.err
+}
+
+func (f *fakeLibvirtConn) Close() error {
+	f.calls = append(f.calls, "close")
+	return nil
+}
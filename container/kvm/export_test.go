@@ -3,7 +3,11 @@
 
 package kvm
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
 
 // This file exports internal package implementations so that tests
 // can utilize them to mock behavior.
@@ -22,6 +26,7 @@ func MakeCreateMachineParamsTestable(params *CreateMachineParams, pathfinder fun
 	params.findPath = pathfinder
 	params.runCmd = runCmd
 	params.runCmdAsRoot = runCmd
+	params.dial = noLibvirt
 	params.arch = arch
 	return
 }
@@ -33,7 +38,13 @@ func NewEmptyKvmContainer() *kvmContainer {
 
 // NewTestContainer returns a new container for testing.
 func NewTestContainer(name string, runCmd runFunc, pathfinder func(string) (string, error)) *kvmContainer {
-	return &kvmContainer{name: name, runCmd: runCmd, pathfinder: pathfinder}
+	return &kvmContainer{name: name, runCmd: runCmd, pathfinder: pathfinder, dial: noLibvirt}
+}
+
+// noLibvirt fails to connect to the libvirt daemon, so that tests exercise
+// the virsh fallback.
+func noLibvirt() (libvirtConn, error) {
+	return nil, errors.New("libvirt unavailable")
 }
 
 // NewRunStub is a stub to fake shelling out to os.Exec or utils.RunCommand.
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -137,8 +138,14 @@ func (manager *containerManager) Namespace() instance.Namespace {
 	return manager.namespace
 }
 
-// Exposed so tests can observe our side-effects
-var startParams StartParams
+var (
+	// Exposed so tests can observe our side-effects
+	startParams StartParams
+
+	// startParamsMutex guards startParams, as containers
+	// may be created concurrently.
+	startParamsMutex sync.Mutex
+)
 
 func (manager *containerManager) CreateContainer(
 	instanceConfig *instancecfg.InstanceConfig,
@@ -184,14 +191,14 @@ func (manager *containerManager) CreateContainer(
 		return nil, nil, err
 	}
 	// Create the container.
-	startParams = ParseConstraintsToStartParams(cons)
-	startParams.Arch = arch.HostArch()
-	startParams.Series = series
-	startParams.Network = networkConfig
-	startParams.UserDataFile = userDataFilename
-	startParams.NetworkConfigData = cloudinit.CloudInitNetworkConfigDisabled
-	startParams.StatusCallback = callback
-	startParams.Stream = manager.imageStream
+	params := ParseConstraintsToStartParams(cons)
+	params.Arch = arch.HostArch()
+	params.Series = series
+	params.Network = networkConfig
+	params.UserDataFile = userDataFilename
+	params.NetworkConfigData = cloudinit.CloudInitNetworkConfigDisabled
+	params.StatusCallback = callback
+	params.Stream = manager.imageStream
 
 	// Check whether a container image metadata URL was configured.
 	// Default to Ubuntu cloud images if configured stream is not "released".
@@ -203,19 +210,22 @@ func (manager *containerManager) CreateContainer(
 			return nil, nil, errors.Annotate(err, "generating image metadata source")
 		}
 	}
-	startParams.ImageDownloadURL = imURL
+	params.ImageDownloadURL = imURL
 
 	var hardware instance.HardwareCharacteristics
 	hardware, err = instance.ParseHardware(
 		fmt.Sprintf("arch=%s mem=%vM root-disk=%vG cores=%v",
-			startParams.Arch, startParams.Memory, startParams.RootDisk, startParams.CpuCores))
+			params.Arch, params.Memory, params.RootDisk, params.CpuCores))
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to parse hardware")
 	}
 
 	callback(status.Provisioning, "Creating container; it might take some time", nil)
 	logger.Tracef("create the container, constraints: %v", cons)
-	if err := kvmContainer.Start(startParams); err != nil {
+	startParamsMutex.Lock()
+	startParams = params
+	startParamsMutex.Unlock()
+	if err := kvmContainer.Start(params); err != nil {
 		err = errors.Annotate(err, "kvm container creation failed")
 		return nil, nil, err
 	}
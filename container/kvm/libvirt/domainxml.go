@@ -4,6 +4,7 @@
 package libvirt
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 
@@ -122,17 +123,62 @@ func NewDomain(p domainParams) (Domain, error) {
 		}
 	}
 	for _, iface := range p.NetworkInfo() {
-		d.Interface = append(d.Interface, Interface{
-			Type:   "bridge",
-			MAC:    InterfaceMAC{Address: iface.MACAddress()},
-			Model:  Model{Type: "virtio"},
-			Source: InterfaceSource{Bridge: iface.ParentInterfaceName()},
-			Guest:  InterfaceGuest{Dev: iface.InterfaceName()},
-		})
+		d.Interface = append(d.Interface, newInterface(iface))
 	}
 	return d, nil
 }
 
+// DiskXML returns the XML for a single file backed disk, with the
+// input source path and driver type, attached to the input target device.
+// If serial is not empty, the guest sees it as the disk's serial number.
+// It is used to hot-plug disks into a running domain.
+func DiskXML(source, driver, dev, serial string) (string, error) {
+	switch driver {
+	case "raw", "qcow2":
+	default:
+		return "", errors.Errorf("unsupported disk type %q", driver)
+	}
+	return deviceXML("disk", Disk{
+		Device: "disk",
+		Type:   "file",
+		Driver: DiskDriver{Type: driver, Name: "qemu"},
+		Source: DiskSource{File: source},
+		Target: DiskTarget{Dev: dev},
+		Serial: serial,
+	})
+}
+
+// InterfaceXML returns the XML for a single bridged network interface.
+// It is used to hot-plug network interfaces into a running domain.
+func InterfaceXML(iface InterfaceInfo) (string, error) {
+	return deviceXML("interface", newInterface(iface))
+}
+
+// newInterface returns a bridged virtio interface for the input info.
+func newInterface(iface InterfaceInfo) Interface {
+	return Interface{
+		Type:   "bridge",
+		MAC:    InterfaceMAC{Address: iface.MACAddress()},
+		Model:  Model{Type: "virtio"},
+		Source: InterfaceSource{Bridge: iface.ParentInterfaceName()},
+		Guest:  InterfaceGuest{Dev: iface.InterfaceName()},
+	}
+}
+
+// deviceXML marshals the input device as an element with the input name.
+func deviceXML(name string, device interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "    ")
+	if err := enc.EncodeElement(device, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := enc.Flush(); err != nil {
+		return "", errors.Trace(err)
+	}
+	return buf.String(), nil
+}
+
 // generateOSElement creates the architecture appropriate element details.
 func generateOSElement(p domainParams) OS {
 	switch p.Arch() {
@@ -364,6 +410,7 @@ type Disk struct {
 	Driver DiskDriver `xml:"driver"`
 	Source DiskSource `xml:"source"`
 	Target DiskTarget `xml:"target"`
+	Serial string     `xml:"serial,omitempty"`
 }
 
 // DiskDriver is the type of virtual disk. We generate it dynamically.
@@ -140,6 +140,47 @@ func (domainXMLSuite) TestNewDomainError(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "boom")
 }
 
+func (domainXMLSuite) TestDiskXML(c *gc.C) {
+	got, err := DiskXML("/some/path/disk.img", "raw", "vdc", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(got, gc.Equals, `<disk device="disk" type="file">
+    <driver type="raw" name="qemu"></driver>
+    <source file="/some/path/disk.img"></source>
+    <target dev="vdc"></target>
+</disk>`)
+}
+
+func (domainXMLSuite) TestDiskXMLWithSerial(c *gc.C) {
+	got, err := DiskXML("/some/path/disk.qcow2", "qcow2", "vdc", "volume-0-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(got, gc.Equals, `<disk device="disk" type="file">
+    <driver type="qcow2" name="qemu"></driver>
+    <source file="/some/path/disk.qcow2"></source>
+    <target dev="vdc"></target>
+    <serial>volume-0-1</serial>
+</disk>`)
+}
+
+func (domainXMLSuite) TestDiskXMLUnsupportedDriver(c *gc.C) {
+	_, err := DiskXML("/some/path/disk.img", "vmdk", "vdc", "")
+	c.Check(err, gc.ErrorMatches, `unsupported disk type "vmdk"`)
+}
+
+func (domainXMLSuite) TestInterfaceXML(c *gc.C) {
+	got, err := InterfaceXML(dummyInterface{
+		mac:    "00:00:00:00:00:02",
+		parent: "parentdev",
+		name:   "eth1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(got, gc.Equals, `<interface type="bridge">
+    <mac address="00:00:00:00:00:02"></mac>
+    <model type="virtio"></model>
+    <source bridge="parentdev"></source>
+    <guest dev="eth1"></guest>
+</interface>`)
+}
+
 type dummyParams struct {
 	err       error
 	arch      string
@@ -0,0 +1,518 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// This file contains a client for the libvirt daemon's remote protocol,
+// which is what virsh itself uses to talk to libvirtd. Only the calls
+// needed to manage Juju's KVM guests are implemented.
+// The protocol is XDR over a stream socket; its definition is at:
+// https://libvirt.org/git/?p=libvirt.git;a=blob;f=src/remote/remote_protocol.x
+
+const (
+	// DefaultSocket is the path of the libvirt daemon's read-write socket.
+	DefaultSocket = "/var/run/libvirt/libvirt-sock"
+
+	// DefaultURI is the URI of the system KVM hypervisor connection.
+	DefaultURI = "qemu:///system"
+
+	dialTimeout = 5 * time.Second
+)
+
+const (
+	remoteProgram = 0x20008086
+	remoteVersion = 1
+
+	procConnectOpen           = 1
+	procConnectClose          = 2
+	procDomainCreate          = 9
+	procDomainDefineXML       = 11
+	procDomainDestroy         = 12
+	procDomainLookupByName    = 23
+	procDomainSetAutostart    = 29
+	procDomainAttachDevice    = 160
+	procDomainDetachDevice    = 161
+	procDomainGetState        = 212
+	procDomainUndefineFlags   = 231
+	procConnectListAllDomains = 273
+
+	typeCall  = 0
+	typeReply = 1
+
+	statusOK    = 0
+	statusError = 1
+
+	// headerSize is the size of the packet length plus the header.
+	headerSize = 28
+
+	// maxPacketSize is the largest packet libvirtd will send.
+	maxPacketSize = 32 * 1024 * 1024
+
+	// affectLiveAndConfig applies device changes to both the running
+	// domain and its persistent definition.
+	affectLiveAndConfig = 1 | 2
+
+	// undefineNVRAM removes the domain's NVRAM file when undefining it.
+	undefineNVRAM = 4
+
+	// errNoDomain is the libvirt error code for a missing domain.
+	errNoDomain = 42
+)
+
+// DomainState is the state of a libvirt domain.
+type DomainState int32
+
+const (
+	DomainNoState DomainState = iota
+	DomainRunning
+	DomainBlocked
+	DomainPaused
+	DomainShutdown
+	DomainShutoff
+	DomainCrashed
+	DomainPMSuspended
+)
+
+// String returns the state as reported by `virsh list`.
+func (s DomainState) String() string {
+	switch s {
+	case DomainRunning:
+		return "running"
+	case DomainBlocked:
+		return "idle"
+	case DomainPaused:
+		return "paused"
+	case DomainShutdown:
+		return "in shutdown"
+	case DomainShutoff:
+		return "shut off"
+	case DomainCrashed:
+		return "crashed"
+	case DomainPMSuspended:
+		return "pmsuspended"
+	}
+	return "no state"
+}
+
+// Error is an error returned by the libvirt daemon.
+type Error struct {
+	Code    int32
+	Domain  int32
+	Message string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("libvirt error %d", e.Code)
+	}
+	return e.Message
+}
+
+// IsNotFound returns true if the input error was returned by the libvirt
+// daemon because a domain does not exist.
+func IsNotFound(err error) bool {
+	e, ok := errors.Cause(err).(*Error)
+	return ok && e.Code == errNoDomain
+}
+
+// Conn is a connection to the libvirt daemon. It is safe for concurrent
+// use, but calls on a single connection are serialised; use a connection
+// per goroutine for parallel operations.
+type Conn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	serial uint32
+}
+
+// Dial connects to the libvirt daemon listening on the input unix socket,
+// and opens the system hypervisor connection.
+func Dial(socket string) (*Conn, error) {
+	c, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewConn(c, DefaultURI)
+}
+
+// NewConn opens the hypervisor connection with the input URI over
+// the input network connection to the libvirt daemon.
+func NewConn(c net.Conn, uri string) (*Conn, error) {
+	conn := &Conn{conn: c}
+	var args encoder
+	args.optString(uri)
+	args.uint32(0)
+	if _, err := conn.call(procConnectOpen, args.bytes()); err != nil {
+		_ = c.Close()
+		return nil, errors.Annotatef(err, "opening %q", uri)
+	}
+	return conn, nil
+}
+
+// Close closes the hypervisor connection and the underlying network
+// connection.
+func (c *Conn) Close() error {
+	_, err := c.call(procConnectClose, nil)
+	if cErr := c.conn.Close(); err == nil {
+		err = cErr
+	}
+	return errors.Trace(err)
+}
+
+// DefineDomain defines a persistent domain from the input XML.
+func (c *Conn) DefineDomain(domainXML string) error {
+	var args encoder
+	args.string(domainXML)
+	_, err := c.call(procDomainDefineXML, args.bytes())
+	return errors.Trace(err)
+}
+
+// StartDomain starts the named, defined domain.
+func (c *Conn) StartDomain(name string) error {
+	return errors.Trace(c.domainCall(procDomainCreate, name, nil))
+}
+
+// DestroyDomain forcibly stops the named domain.
+func (c *Conn) DestroyDomain(name string) error {
+	return errors.Trace(c.domainCall(procDomainDestroy, name, nil))
+}
+
+// UndefineDomain removes the definition of the named domain,
+// along with its NVRAM.
+func (c *Conn) UndefineDomain(name string) error {
+	return errors.Trace(c.domainCall(procDomainUndefineFlags, name, func(e *encoder) {
+		e.uint32(undefineNVRAM)
+	}))
+}
+
+// SetAutostart sets whether the named domain is started when
+// the host boots.
+func (c *Conn) SetAutostart(name string, autostart bool) error {
+	return errors.Trace(c.domainCall(procDomainSetAutostart, name, func(e *encoder) {
+		e.bool(autostart)
+	}))
+}
+
+// AttachDevice hot-plugs the device described by the input XML into the
+// named domain, and adds it to the domain's persistent definition.
+func (c *Conn) AttachDevice(name, deviceXML string) error {
+	return errors.Trace(c.domainCall(procDomainAttachDevice, name, func(e *encoder) {
+		e.string(deviceXML)
+		e.uint32(affectLiveAndConfig)
+	}))
+}
+
+// DetachDevice hot-unplugs the device described by the input XML from the
+// named domain, and removes it from the domain's persistent definition.
+func (c *Conn) DetachDevice(name, deviceXML string) error {
+	return errors.Trace(c.domainCall(procDomainDetachDevice, name, func(e *encoder) {
+		e.string(deviceXML)
+		e.uint32(affectLiveAndConfig)
+	}))
+}
+
+// DomainState returns the current state of the named domain.
+func (c *Conn) DomainState(name string) (DomainState, error) {
+	dom, err := c.lookup(name)
+	if err != nil {
+		return DomainNoState, errors.Trace(err)
+	}
+	return c.domainState(dom)
+}
+
+// ListDomains returns the states of all domains, active or not,
+// keyed by domain name.
+func (c *Conn) ListDomains() (map[string]DomainState, error) {
+	var args encoder
+	args.int32(1)
+	args.uint32(0)
+	ret, err := c.call(procConnectListAllDomains, args.bytes())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	n := ret.uint32()
+	if ret.err == nil && n > uint32(len(ret.data)) {
+		return nil, errors.Errorf("invalid domain count %d", n)
+	}
+	doms := make([]domain, n)
+	for i := range doms {
+		doms[i] = ret.domain()
+	}
+	if ret.err != nil {
+		return nil, errors.Annotate(ret.err, "decoding domains")
+	}
+
+	result := make(map[string]DomainState, len(doms))
+	for _, dom := range doms {
+		state, err := c.domainState(dom)
+		if IsNotFound(err) {
+			// The domain was removed after we listed it.
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[dom.name] = state
+	}
+	return result, nil
+}
+
+// domain is a reference to a domain, as passed to and returned by
+// the libvirt daemon.
+type domain struct {
+	name string
+	uuid [16]byte
+	id   int32
+}
+
+// lookup returns a reference to the named domain.
+func (c *Conn) lookup(name string) (domain, error) {
+	var args encoder
+	args.string(name)
+	ret, err := c.call(procDomainLookupByName, args.bytes())
+	if err != nil {
+		return domain{}, errors.Trace(err)
+	}
+	dom := ret.domain()
+	return dom, errors.Trace(ret.err)
+}
+
+// domainCall looks up the named domain, then invokes the input procedure
+// with the domain reference followed by any arguments written by extra.
+func (c *Conn) domainCall(proc uint32, name string, extra func(*encoder)) error {
+	dom, err := c.lookup(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var args encoder
+	args.domain(dom)
+	if extra != nil {
+		extra(&args)
+	}
+	_, err = c.call(proc, args.bytes())
+	return errors.Trace(err)
+}
+
+func (c *Conn) domainState(dom domain) (DomainState, error) {
+	var args encoder
+	args.domain(dom)
+	args.uint32(0)
+	ret, err := c.call(procDomainGetState, args.bytes())
+	if err != nil {
+		return DomainNoState, errors.Trace(err)
+	}
+	state := ret.int32()
+	return DomainState(state), errors.Trace(ret.err)
+}
+
+// call sends a call packet for the input procedure and returns a decoder
+// for the payload of the matching reply.
+func (c *Conn) call(proc uint32, args []byte) (*decoder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.serial++
+	serial := c.serial
+
+	var packet encoder
+	packet.uint32(uint32(headerSize + len(args)))
+	packet.uint32(remoteProgram)
+	packet.uint32(remoteVersion)
+	packet.uint32(proc)
+	packet.uint32(typeCall)
+	packet.uint32(serial)
+	packet.uint32(statusOK)
+	packet.buf.Write(args)
+	if _, err := c.conn.Write(packet.bytes()); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for {
+		header, payload, err := c.readPacket()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// Ignore anything that is not the reply to this call,
+		// such as event messages.
+		if header.program != remoteProgram || header.serial != serial || header.packetType != typeReply {
+			continue
+		}
+		ret := &decoder{data: payload}
+		if header.status == statusError {
+			return nil, decodeError(ret)
+		}
+		return ret, nil
+	}
+}
+
+type packetHeader struct {
+	program    uint32
+	version    uint32
+	procedure  uint32
+	packetType uint32
+	serial     uint32
+	status     uint32
+}
+
+// readPacket reads a single packet from the connection, returning its
+// header and payload.
+func (c *Conn) readPacket() (packetHeader, []byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(c.conn, lenBuf[:]); err != nil {
+		return packetHeader{}, nil, errors.Trace(err)
+	}
+	size := binary.BigEndian.Uint32(lenBuf[:])
+	if size < headerSize || size > maxPacketSize {
+		return packetHeader{}, nil, errors.Errorf("invalid packet size %d", size)
+	}
+	buf := make([]byte, size-4)
+	if _, err := io.ReadFull(c.conn, buf); err != nil {
+		return packetHeader{}, nil, errors.Trace(err)
+	}
+	d := &decoder{data: buf}
+	header := packetHeader{
+		program:    d.uint32(),
+		version:    d.uint32(),
+		procedure:  d.uint32(),
+		packetType: d.uint32(),
+		serial:     d.uint32(),
+		status:     d.uint32(),
+	}
+	return header, d.data, errors.Trace(d.err)
+}
+
+// decodeError decodes the leading fields of a remote_error.
+func decodeError(d *decoder) error {
+	e := &Error{
+		Code:   d.int32(),
+		Domain: d.int32(),
+	}
+	e.Message = d.optString()
+	if d.err != nil {
+		return errors.Annotate(d.err, "decoding libvirt error")
+	}
+	return e
+}
+
+// encoder writes XDR encoded values.
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) bytes() []byte {
+	return e.buf.Bytes()
+}
+
+func (e *encoder) uint32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	e.buf.Write(b[:])
+}
+
+func (e *encoder) int32(v int32) {
+	e.uint32(uint32(v))
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.int32(1)
+	} else {
+		e.int32(0)
+	}
+}
+
+func (e *encoder) string(s string) {
+	e.uint32(uint32(len(s)))
+	e.buf.WriteString(s)
+	e.buf.Write(make([]byte, padding(len(s))))
+}
+
+// optString writes a string that may be null; the empty string
+// is written as null.
+func (e *encoder) optString(s string) {
+	e.bool(s != "")
+	if s != "" {
+		e.string(s)
+	}
+}
+
+func (e *encoder) domain(d domain) {
+	e.string(d.name)
+	e.buf.Write(d.uuid[:])
+	e.int32(d.id)
+}
+
+// decoder reads XDR encoded values. The first error encountered is
+// recorded, after which all reads return zero values.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.data) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (d *decoder) int32() int32 {
+	return int32(d.uint32())
+}
+
+func (d *decoder) string() string {
+	n := d.uint32()
+	if d.err == nil && n > uint32(len(d.data)) {
+		d.err = io.ErrUnexpectedEOF
+	}
+	b := d.next(int(n))
+	d.next(padding(int(n)))
+	return string(b)
+}
+
+func (d *decoder) optString() string {
+	if d.uint32() == 0 {
+		return ""
+	}
+	return d.string()
+}
+
+func (d *decoder) domain() domain {
+	var dom domain
+	dom.name = d.string()
+	copy(dom.uuid[:], d.next(len(dom.uuid)))
+	dom.id = d.int32()
+	return dom
+}
+
+// padding returns the number of bytes needed to pad n bytes
+// to a multiple of four.
+func padding(n int) int {
+	return (4 - n%4) % 4
+}
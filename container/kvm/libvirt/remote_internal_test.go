@@ -0,0 +1,262 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"regexp"
+	"sync"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type remoteSuite struct {
+	testing.IsolationSuite
+
+	server *fakeLibvirtd
+	conn   *Conn
+}
+
+var _ = gc.Suite(&remoteSuite{})
+
+func (s *remoteSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	client, server := net.Pipe()
+	s.server = newFakeLibvirtd(server)
+	go s.server.serve()
+
+	conn, err := NewConn(client, DefaultURI)
+	c.Assert(err, jc.ErrorIsNil)
+	s.conn = conn
+}
+
+func (s *remoteSuite) TearDownTest(c *gc.C) {
+	if s.conn != nil {
+		c.Check(s.conn.Close(), jc.ErrorIsNil)
+	}
+	s.IsolationSuite.TearDownTest(c)
+}
+
+func (s *remoteSuite) TestOpen(c *gc.C) {
+	c.Check(s.server.uri, gc.Equals, DefaultURI)
+}
+
+func (s *remoteSuite) TestDefineStartAndState(c *gc.C) {
+	err := s.conn.DefineDomain("<domain><name>juju-06f00d-0</name></domain>")
+	c.Assert(err, jc.ErrorIsNil)
+
+	state, err := s.conn.DomainState("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(state, gc.Equals, DomainShutoff)
+
+	err = s.conn.StartDomain("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	state, err = s.conn.DomainState("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(state, gc.Equals, DomainRunning)
+}
+
+func (s *remoteSuite) TestListDomains(c *gc.C) {
+	s.server.setDomains(map[string]DomainState{
+		"juju-06f00d-0": DomainRunning,
+		"juju-06f00d-1": DomainShutoff,
+	})
+
+	domains, err := s.conn.ListDomains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(domains, jc.DeepEquals, map[string]DomainState{
+		"juju-06f00d-0": DomainRunning,
+		"juju-06f00d-1": DomainShutoff,
+	})
+}
+
+func (s *remoteSuite) TestDestroyAndUndefine(c *gc.C) {
+	s.server.setDomains(map[string]DomainState{"juju-06f00d-0": DomainRunning})
+
+	c.Assert(s.conn.DestroyDomain("juju-06f00d-0"), jc.ErrorIsNil)
+	c.Assert(s.conn.UndefineDomain("juju-06f00d-0"), jc.ErrorIsNil)
+
+	domains, err := s.conn.ListDomains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(domains, gc.HasLen, 0)
+	c.Check(s.server.undefineFlags, gc.Equals, uint32(undefineNVRAM))
+}
+
+func (s *remoteSuite) TestSetAutostart(c *gc.C) {
+	s.server.setDomains(map[string]DomainState{"juju-06f00d-0": DomainRunning})
+
+	c.Assert(s.conn.SetAutostart("juju-06f00d-0", true), jc.ErrorIsNil)
+	c.Check(s.server.autostart["juju-06f00d-0"], jc.IsTrue)
+}
+
+func (s *remoteSuite) TestAttachAndDetachDevice(c *gc.C) {
+	s.server.setDomains(map[string]DomainState{"juju-06f00d-0": DomainRunning})
+
+	c.Assert(s.conn.AttachDevice("juju-06f00d-0", "<disk/>"), jc.ErrorIsNil)
+	c.Assert(s.conn.DetachDevice("juju-06f00d-0", "<interface/>"), jc.ErrorIsNil)
+	c.Check(s.server.devices, jc.DeepEquals, []string{"+<disk/>", "-<interface/>"})
+	c.Check(s.server.deviceFlags, gc.Equals, uint32(affectLiveAndConfig))
+}
+
+func (s *remoteSuite) TestNotFound(c *gc.C) {
+	_, err := s.conn.DomainState("juju-06f00d-9")
+	c.Check(err, gc.ErrorMatches, "Domain not found: juju-06f00d-9")
+	c.Check(IsNotFound(err), jc.IsTrue)
+
+	err = s.conn.StartDomain("juju-06f00d-9")
+	c.Check(IsNotFound(err), jc.IsTrue)
+}
+
+func (s *remoteSuite) TestIgnoresUnrelatedPackets(c *gc.C) {
+	s.server.setDomains(map[string]DomainState{"juju-06f00d-0": DomainPaused})
+	s.server.sendEvent = true
+
+	state, err := s.conn.DomainState("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(state, gc.Equals, DomainPaused)
+}
+
+func (s *remoteSuite) TestDomainStateString(c *gc.C) {
+	c.Check(DomainRunning.String(), gc.Equals, "running")
+	c.Check(DomainBlocked.String(), gc.Equals, "idle")
+	c.Check(DomainShutdown.String(), gc.Equals, "in shutdown")
+	c.Check(DomainShutoff.String(), gc.Equals, "shut off")
+	c.Check(DomainState(99).String(), gc.Equals, "no state")
+}
+
+// fakeLibvirtd serves the subset of the libvirt remote protocol
+// implemented by Conn, over one end of a pipe.
+type fakeLibvirtd struct {
+	conn net.Conn
+
+	mu            sync.Mutex
+	uri           string
+	domains       map[string]DomainState
+	autostart     map[string]bool
+	devices       []string
+	deviceFlags   uint32
+	undefineFlags uint32
+	sendEvent     bool
+}
+
+var domainNamePattern = regexp.MustCompile(`<name>(.*)</name>`)
+
+func newFakeLibvirtd(conn net.Conn) *fakeLibvirtd {
+	return &fakeLibvirtd{
+		conn:      conn,
+		domains:   make(map[string]DomainState),
+		autostart: make(map[string]bool),
+	}
+}
+
+func (f *fakeLibvirtd) setDomains(domains map[string]DomainState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.domains = domains
+}
+
+func (f *fakeLibvirtd) serve() {
+	defer f.conn.Close()
+	for {
+		var lenBuf [4]byte
+		if _, err := io.ReadFull(f.conn, lenBuf[:]); err != nil {
+			return
+		}
+		buf := make([]byte, binary.BigEndian.Uint32(lenBuf[:])-4)
+		if _, err := io.ReadFull(f.conn, buf); err != nil {
+			return
+		}
+		args := &decoder{data: buf}
+		args.next(8)
+		proc := args.uint32()
+		args.next(4)
+		serial := args.uint32()
+		args.next(4)
+
+		f.mu.Lock()
+		if f.sendEvent {
+			f.write(0, 0, 2, statusOK, nil)
+		}
+		ret, rErr := f.handle(proc, args)
+		f.mu.Unlock()
+
+		if rErr != nil {
+			var payload encoder
+			payload.int32(rErr.Code)
+			payload.int32(rErr.Domain)
+			payload.optString(rErr.Message)
+			f.write(proc, serial, typeReply, statusError, payload.bytes())
+		} else {
+			f.write(proc, serial, typeReply, statusOK, ret.bytes())
+		}
+		if proc == procConnectClose {
+			return
+		}
+	}
+}
+
+func (f *fakeLibvirtd) handle(proc uint32, args *decoder) (*encoder, *Error) {
+	ret := &encoder{}
+	switch proc {
+	case procConnectOpen:
+		f.uri = args.optString()
+	case procDomainDefineXML:
+		name := domainNamePattern.FindStringSubmatch(args.string())[1]
+		f.domains[name] = DomainShutoff
+		ret.domain(domain{name: name})
+	case procDomainLookupByName:
+		name := args.string()
+		if _, ok := f.domains[name]; !ok {
+			return nil, &Error{Code: errNoDomain, Message: "Domain not found: " + name}
+		}
+		ret.domain(domain{name: name})
+	case procDomainCreate:
+		f.domains[args.domain().name] = DomainRunning
+	case procDomainDestroy:
+		f.domains[args.domain().name] = DomainShutoff
+	case procDomainUndefineFlags:
+		delete(f.domains, args.domain().name)
+		f.undefineFlags = args.uint32()
+	case procDomainSetAutostart:
+		name := args.domain().name
+		f.autostart[name] = args.int32() == 1
+	case procDomainAttachDevice, procDomainDetachDevice:
+		args.domain()
+		op := "+"
+		if proc == procDomainDetachDevice {
+			op = "-"
+		}
+		f.devices = append(f.devices, op+args.string())
+		f.deviceFlags = args.uint32()
+	case procDomainGetState:
+		ret.int32(int32(f.domains[args.domain().name]))
+		ret.int32(1)
+	case procConnectListAllDomains:
+		ret.uint32(uint32(len(f.domains)))
+		for name := range f.domains {
+			ret.domain(domain{name: name})
+		}
+		ret.uint32(uint32(len(f.domains)))
+	}
+	return ret, nil
+}
+
+func (f *fakeLibvirtd) write(proc, serial, packetType, status uint32, payload []byte) {
+	var packet encoder
+	packet.uint32(uint32(headerSize + len(payload)))
+	packet.uint32(remoteProgram)
+	packet.uint32(remoteVersion)
+	packet.uint32(proc)
+	packet.uint32(packetType)
+	packet.uint32(serial)
+	packet.uint32(status)
+	packet.buf.Write(payload)
+	f.conn.Write(packet.bytes())
+}
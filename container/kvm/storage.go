@@ -0,0 +1,295 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/paths"
+	"github.com/juju/juju/storage"
+)
+
+// StorageProviderType is the type of the KVM storage provider. Its
+// volumes are disk images in the KVM host's guest pool, hot-plugged
+// into the host's KVM guests.
+const StorageProviderType = storage.ProviderType("kvm")
+
+// guestSystemDisks is the number of disks, the root disk and the
+// cloud-init data source, that every guest is created with.
+const guestSystemDisks = 2
+
+// StorageProviders returns a storage.ProviderRegistry that contains the
+// KVM storage provider.
+func StorageProviders() storage.ProviderRegistry {
+	return storage.StaticProviderRegistry{
+		Providers: map[storage.ProviderType]storage.Provider{
+			StorageProviderType: &storageProvider{
+				dial:       dialLibvirt,
+				runCmd:     runAsLibvirt,
+				pathfinder: paths.DataDir,
+			},
+		},
+	}
+}
+
+// storageProvider implements storage.Provider. Its volumes are scoped
+// to the host of the KVM guest they are attached to, so that they are
+// created and attached by the host's storage provisioner.
+type storageProvider struct {
+	dial       dialFunc
+	runCmd     runFunc
+	pathfinder func(string) (string, error)
+}
+
+var (
+	_ storage.Provider           = (*storageProvider)(nil)
+	_ storage.HostScopedProvider = (*storageProvider)(nil)
+)
+
+// ValidateConfig is part of the storage.Provider interface.
+func (*storageProvider) ValidateConfig(*storage.Config) error {
+	// The KVM provider has no configuration.
+	return nil
+}
+
+// VolumeSource is part of the storage.Provider interface.
+func (p *storageProvider) VolumeSource(cfg *storage.Config) (storage.VolumeSource, error) {
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	guestDir, err := guestPath(p.pathfinder)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &volumeSource{
+		dial:     p.dial,
+		runCmd:   p.runCmd,
+		guestDir: guestDir,
+	}, nil
+}
+
+// FilesystemSource is part of the storage.Provider interface.
+func (*storageProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is part of the storage.Provider interface.
+func (*storageProvider) Supports(kind storage.StorageKind) bool {
+	return kind == storage.StorageKindBlock
+}
+
+// Scope is part of the storage.Provider interface.
+func (*storageProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// HostScopedContainerType is part of the storage.HostScopedProvider interface.
+func (*storageProvider) HostScopedContainerType() instance.ContainerType {
+	return instance.KVM
+}
+
+// Dynamic is part of the storage.Provider interface.
+func (*storageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is part of the storage.Provider interface.
+func (*storageProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is part of the storage.Provider interface.
+func (*storageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// volumeSource implements storage.VolumeSource. Volumes are qcow2
+// images in the guest pool directory, named after their volume tags.
+// Each is attached to its guest with the volume tag as the disk's
+// serial number, so that the guest finds it by ID whichever device
+// name the guest's kernel gives it.
+type volumeSource struct {
+	dial     dialFunc
+	runCmd   runFunc
+	guestDir string
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// CreateVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) CreateVolumes(ctx context.ProviderCallContext, args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := s.createVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "creating volume %s", arg.Tag.Id())
+			continue
+		}
+		results[i].Volume = volume
+	}
+	return results, nil
+}
+
+func (s *volumeSource) createVolume(arg storage.VolumeParams) (*storage.Volume, error) {
+	imgPath := s.volumeFilePath(arg.Tag)
+	if _, err := os.Stat(imgPath); os.IsNotExist(err) {
+		out, err := s.runCmd("qemu-img", "create", "-f", "qcow2", imgPath, fmt.Sprintf("%dM", arg.Size))
+		logger.Debugf("create volume image: %s", out)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.Volume{
+		Tag: arg.Tag,
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId: arg.Tag.String(),
+			Size:     arg.Size,
+		},
+	}, nil
+}
+
+func (s *volumeSource) volumeFilePath(tag names.VolumeTag) string {
+	return filepath.Join(s.guestDir, fmt.Sprintf("%s.qcow", tag.String()))
+}
+
+// ListVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
+	return nil, errors.NotImplementedf("ListVolumes")
+}
+
+// DescribeVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) DescribeVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	return nil, errors.NotImplementedf("DescribeVolumes")
+}
+
+// DestroyVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		tag, err := names.ParseVolumeTag(volumeId)
+		if err != nil {
+			results[i] = errors.Errorf("invalid KVM volume ID %q", volumeId)
+			continue
+		}
+		if err := os.Remove(s.volumeFilePath(tag)); err != nil && !os.IsNotExist(err) {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results, nil
+}
+
+// ReleaseVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) ReleaseVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	return make([]error, len(volumeIds)), nil
+}
+
+// ValidateVolumeParams is part of the storage.VolumeSource interface.
+func (s *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	return nil
+}
+
+// AttachVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) AttachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %s", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *volumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*storage.VolumeAttachment, error) {
+	if !isGuest(arg.Machine) {
+		return nil, errors.NotSupportedf("attaching KVM volumes to %s", names.ReadableString(arg.Machine))
+	}
+	if arg.ReadOnly {
+		return nil, errors.NotSupportedf("read-only KVM volumes")
+	}
+	dev, err := volumeTarget(arg.Volume)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	serial := arg.Volume.String()
+	err = attachDisk(s.dial, s.runCmd, string(arg.InstanceId), s.volumeFilePath(arg.Volume), "qcow2", dev, serial)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.VolumeAttachment{
+		Volume:  arg.Volume,
+		Machine: arg.Machine,
+		VolumeAttachmentInfo: storage.VolumeAttachmentInfo{
+			// udev names virtio disks in /dev/disk/by-id after
+			// their serial numbers.
+			DeviceLink: "/dev/disk/by-id/virtio-" + serial,
+		},
+	}, nil
+}
+
+// DetachVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) DetachVolumes(ctx context.ProviderCallContext, args []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := s.detachVolume(arg); err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+func (s *volumeSource) detachVolume(arg storage.VolumeAttachmentParams) error {
+	dev, err := volumeTarget(arg.Volume)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = detachDisk(s.dial, s.runCmd, string(arg.InstanceId), s.volumeFilePath(arg.Volume), "qcow2", dev)
+	if libvirt.IsNotFound(err) {
+		// The guest has already been destroyed, taking
+		// the attachment with it.
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// isGuest reports whether the input machine is a KVM guest.
+func isGuest(tag names.MachineTag) bool {
+	parts := strings.Split(tag.Id(), "/")
+	return len(parts) >= 3 && parts[len(parts)-2] == string(instance.KVM)
+}
+
+// volumeTarget returns the guest device to attach the input volume as.
+// Volume sequence numbers are unique within a model, so deriving the
+// device from them means the volumes attached to a guest never clash
+// with each other, nor with the guest's system disks.
+func volumeTarget(tag names.VolumeTag) (string, error) {
+	id := tag.Id()
+	seq, err := strconv.Atoi(id[strings.LastIndex(id, "/")+1:])
+	if err != nil {
+		return "", errors.NotValidf("volume ID %q", id)
+	}
+	// Devices are lettered vda-vdz, then vdaa-vdaz and so on.
+	var suffix string
+	for i := seq + guestSystemDisks; ; i = i/26 - 1 {
+		suffix = string(rune('a'+i%26)) + suffix
+		if i < 26 {
+			break
+		}
+	}
+	return "vd" + suffix, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+type storageInternalSuite struct {
+	testing.IsolationSuite
+
+	conn     *fakeLibvirtConn
+	stub     *runStub
+	guestDir string
+	source   storage.VolumeSource
+}
+
+var _ = gc.Suite(&storageInternalSuite{})
+
+func (s *storageInternalSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.conn = &fakeLibvirtConn{}
+	s.stub = &runStub{output: "success"}
+	dataDir := c.MkDir()
+	s.guestDir = filepath.Join(dataDir, "kvm", "guests")
+	c.Assert(os.MkdirAll(s.guestDir, 0755), jc.ErrorIsNil)
+
+	p := &storageProvider{
+		dial:       func() (libvirtConn, error) { return s.conn, nil },
+		runCmd:     s.stub.Run,
+		pathfinder: func(string) (string, error) { return dataDir, nil },
+	}
+	cfg, err := storage.NewConfig("kvm", StorageProviderType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source = source
+}
+
+func (s *storageInternalSuite) attachmentParams(machine string) storage.VolumeAttachmentParams {
+	return storage.VolumeAttachmentParams{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   StorageProviderType,
+			Machine:    names.NewMachineTag(machine),
+			InstanceId: "juju-06f00d-0-kvm-1",
+		},
+		Volume:   names.NewVolumeTag("0/3"),
+		VolumeId: "volume-0-3",
+	}
+}
+
+func (s *storageInternalSuite) TestCreateVolumes(c *gc.C) {
+	results, err := s.source.CreateVolumes(context.NewCloudCallContext(), []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/3"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Check(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		Tag: names.NewVolumeTag("0/3"),
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId: "volume-0-3",
+			Size:     1024,
+		},
+	})
+	c.Check(s.stub.Calls(), jc.DeepEquals, []string{
+		"qemu-img create -f qcow2 " + filepath.Join(s.guestDir, "volume-0-3.qcow") + " 1024M",
+	})
+}
+
+func (s *storageInternalSuite) TestDestroyVolumes(c *gc.C) {
+	imgPath := filepath.Join(s.guestDir, "volume-0-3.qcow")
+	c.Assert(ioutil.WriteFile(imgPath, nil, 0644), jc.ErrorIsNil)
+
+	results, err := s.source.DestroyVolumes(context.NewCloudCallContext(), []string{"volume-0-3", "volume-0-4"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil, nil})
+	c.Check(imgPath, jc.DoesNotExist)
+}
+
+func (s *storageInternalSuite) TestAttachVolumes(c *gc.C) {
+	results, err := s.source.AttachVolumes(context.NewCloudCallContext(), []storage.VolumeAttachmentParams{
+		s.attachmentParams("0/kvm/1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Check(results[0].VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		Volume:  names.NewVolumeTag("0/3"),
+		Machine: names.NewMachineTag("0/kvm/1"),
+		VolumeAttachmentInfo: storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-id/virtio-volume-0-3",
+		},
+	})
+	c.Assert(s.conn.calls, gc.HasLen, 2)
+	c.Check(s.conn.calls[0], jc.HasPrefix, `attach juju-06f00d-0-kvm-1 <disk device="disk" type="file">`)
+	c.Check(s.conn.calls[0], jc.Contains, `<target dev="vdf"></target>`)
+	c.Check(s.conn.calls[0], jc.Contains, `<serial>volume-0-3</serial>`)
+}
+
+func (s *storageInternalSuite) TestAttachVolumesNotGuest(c *gc.C) {
+	results, err := s.source.AttachVolumes(context.NewCloudCallContext(), []storage.VolumeAttachmentParams{
+		s.attachmentParams("0/lxd/1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Check(results[0].Error, gc.ErrorMatches, `attaching volume 0/3: attaching KVM volumes to machine 0/lxd/1 not supported`)
+	c.Check(s.conn.calls, gc.HasLen, 0)
+}
+
+func (s *storageInternalSuite) TestDetachVolumes(c *gc.C) {
+	results, err := s.source.DetachVolumes(context.NewCloudCallContext(), []storage.VolumeAttachmentParams{
+		s.attachmentParams("0/kvm/1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+	c.Assert(s.conn.calls, gc.HasLen, 2)
+	c.Check(s.conn.calls[0], jc.HasPrefix, `detach juju-06f00d-0-kvm-1 <disk device="disk" type="file">`)
+}
+
+func (s *storageInternalSuite) TestDetachVolumesGuestGone(c *gc.C) {
+	s.conn.err = &libvirt.Error{Code: 42, Message: "domain not found"}
+	results, err := s.source.DetachVolumes(context.NewCloudCallContext(), []storage.VolumeAttachmentParams{
+		s.attachmentParams("0/kvm/1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+}
+
+func (s *storageInternalSuite) TestVolumeTarget(c *gc.C) {
+	for seq, dev := range map[string]string{
+		"0":         "vdc",
+		"0/1":       "vdd",
+		"0/23":      "vdz",
+		"0/24":      "vdaa",
+		"0/49":      "vdaz",
+		"0/50":      "vdba",
+		"0/kvm/1/7": "vdj",
+	} {
+		got, err := volumeTarget(names.NewVolumeTag(seq))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(got, gc.Equals, dev, gc.Commentf("volume %s", seq))
+	}
+}
@@ -13,12 +13,13 @@ package kvm
 //   qemu-utils
 //
 // These executables provide Juju's interface to dealing with kvm containers.
-// They are the means by which we start, stop and list running containers on
-// the host
+// The virsh commands are the means by which we start, stop and list running
+// containers on the host when the libvirt API is unavailable; see domains.go.
 
 import (
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -74,6 +75,7 @@ type CreateMachineParams struct {
 
 	runCmd       runFunc
 	runCmdAsRoot runFunc
+	dial         dialFunc
 	arch         string
 }
 
@@ -197,6 +199,23 @@ func CreateMachine(params CreateMachineParams) error {
 		return errors.Annotatef(err, "failed to write domain xml for %q", params.Host())
 	}
 
+	ok, err := withLibvirt(params.dial, func(conn libvirtConn) error {
+		domainXML, err := ioutil.ReadFile(domainPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := conn.DefineDomain(string(domainXML)); err != nil {
+			return errors.Annotatef(err, "failed to define the domain for %q", params.Host())
+		}
+		return errors.Annotatef(conn.StartDomain(params.Host()), "failed to start domain %q", params.Host())
+	})
+	if ok {
+		if err == nil {
+			logger.Debugf("created and started domain %q", params.Host())
+		}
+		return errors.Trace(err)
+	}
+
 	out, err := params.runCmdAsRoot("virsh", "define", domainPath)
 	if err != nil {
 		return errors.Annotatef(err, "failed to defined the domain for %q from %s", params.Host(), domainPath)
@@ -240,19 +259,32 @@ func DestroyMachine(c *kvmContainer) error {
 	// trying to remove wasn't created. However, we still want to try removing
 	// all the parts. The exception here is getting the guestBase, if that
 	// fails we return the error because we cannot continue without it.
+	// The same applies to the equivalent libvirt API calls.
 
-	_, err := c.runCmd("virsh", "destroy", c.Name())
-	if err != nil {
-		logger.Infof("`virsh destroy %s` failed: %q", c.Name(), err)
-	}
+	ok, _ := withLibvirt(c.dial, func(conn libvirtConn) error {
+		if err := conn.DestroyDomain(c.Name()); err != nil {
+			logger.Infof("destroying domain %q failed: %q", c.Name(), err)
+		}
+		// This also removes the domain's NVRAM, as --nvram does below.
+		if err := conn.UndefineDomain(c.Name()); err != nil {
+			logger.Infof("undefining domain %q failed: %q", c.Name(), err)
+		}
+		return nil
+	})
+	if !ok {
+		_, err := c.runCmd("virsh", "destroy", c.Name())
+		if err != nil {
+			logger.Infof("`virsh destroy %s` failed: %q", c.Name(), err)
+		}
 
-	// The nvram flag here removes the pflash drive for us. There is also a
-	// `remove-all-storage` flag, but it is unclear if that would also remove
-	// the backing store which we don't want to do. So we remove those manually
-	// after undefining.
-	_, err = c.runCmd("virsh", "undefine", "--nvram", c.Name())
-	if err != nil {
-		logger.Infof("`virsh undefine --nvram %s` failed: %q", c.Name(), err)
+		// The nvram flag here removes the pflash drive for us. There is also a
+		// `remove-all-storage` flag, but it is unclear if that would also remove
+		// the backing store which we don't want to do. So we remove those manually
+		// after undefining.
+		_, err = c.runCmd("virsh", "undefine", "--nvram", c.Name())
+		if err != nil {
+			logger.Infof("`virsh undefine --nvram %s` failed: %q", c.Name(), err)
+		}
 	}
 	guestBase, err := guestPath(c.pathfinder)
 	if err != nil {
@@ -276,7 +308,12 @@ func AutostartMachine(c *kvmContainer) error {
 	if c.runCmd == nil {
 		c.runCmd = run
 	}
-	_, err := c.runCmd("virsh", "autostart", c.Name())
+	ok, err := withLibvirt(c.dial, func(conn libvirtConn) error {
+		return conn.SetAutostart(c.Name(), true)
+	})
+	if !ok {
+		_, err = c.runCmd("virsh", "autostart", c.Name())
+	}
 	return errors.Annotatef(err, "failed to autostart domain %q", c.Name())
 }
 
@@ -330,11 +367,10 @@ func writeDatasourceVolume(params CreateMachineParams) (string, error) {
 	// found the details in the docs.
 	// http://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html
 	//
-	// The arguments passed to create the DS volume for NoCloud must be
-	// `user-data` and `meta-data`. So the `cloud-init` file we generate won't
-	// work. Also, they must be exactly `user-data` and `meta-data` with no
-	// path beforehand, so `$JUJUDIR/containers/juju-someid-0/user-data` also
-	// fails.
+	// The files in the DS volume for NoCloud must be named `user-data` and
+	// `meta-data`. So the `cloud-init` file we generate won't work. Also, they
+	// must be at the root of the volume, so adding
+	// `$JUJUDIR/containers/juju-someid-0/user-data` as is also fails.
 	//
 	// Furthermore, symlinks aren't followed by NoCloud. So we rename our
 	// cloud-init file to user-data. We could change the output name in
@@ -345,17 +381,6 @@ func writeDatasourceVolume(params CreateMachineParams) (string, error) {
 		return "", errors.Trace(err)
 	}
 
-	// We then change directories and capture our original directory to return
-	// to.  This allows us to run the command with user-data and meta-data as
-	// relative paths to appease the NoCloud script.
-	owd, err := os.Getwd()
-	if err != nil {
-		return "", errors.Trace(err)
-	}
-	if err = os.Chdir(templateDir); err != nil {
-		return "", errors.Trace(err)
-	}
-
 	// Create data the source volume outputting the iso image to the guests
 	// (AKA libvirt storage pool) directory.
 	guestBase, err := guestPath(params.findPath)
@@ -364,27 +389,32 @@ func writeDatasourceVolume(params CreateMachineParams) (string, error) {
 	}
 	dsPath := filepath.Join(guestBase, fmt.Sprintf("%s-ds.iso", params.Host()))
 
+	// Graft points place the files at the root of the volume without
+	// changing the working directory, which would affect the whole process
+	// and prevent machines being created concurrently.
 	out, err := params.runCmd(
 		"genisoimage",
 		"-output", dsPath,
 		"-volid", "cidata",
 		"-joliet", "-rock",
-		userdata,
-		metadata,
-		networkconfig)
+		"-graft-points",
+		graftPoint(templateDir, userdata),
+		graftPoint(templateDir, metadata),
+		graftPoint(templateDir, networkconfig))
 	if err != nil {
 		return "", errors.Trace(err)
 	}
 	logger.Debugf("create ds image: %s", out)
 
-	// Here we return to the old working directory.
-	if err := os.Chdir(owd); err != nil {
-		return "", errors.Trace(err)
-	}
-
 	return dsPath, nil
 }
 
+// graftPoint returns the genisoimage argument that places the named file
+// from the input directory at the root of the image.
+func graftPoint(dir, name string) string {
+	return fmt.Sprintf("%s=%s", name, filepath.Join(dir, name))
+}
+
 // writeDomainXML writes out the configuration required to create a new guest
 // domain.
 func writeDomainXML(templateDir string, p CreateMachineParams) (string, error) {
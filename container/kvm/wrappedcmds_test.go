@@ -145,7 +145,7 @@ func (commandWrapperSuite) TestCreateMachineSuccess(c *gc.C) {
 
 	c.Check(len(stub.Calls()), gc.Equals, 4)
 	want := []string{
-		`genisoimage -output \/tmp\/juju-libvirtSuite-\d+\/kvm\/guests\/host00-ds\.iso -volid cidata -joliet -rock user-data meta-data network-config`,
+		`genisoimage -output \/tmp\/juju-libvirtSuite-\d+\/kvm\/guests\/host00-ds\.iso -volid cidata -joliet -rock -graft-points user-data=\/tmp\/juju-libvirtSuite-\d+\/user-data meta-data=\/tmp\/juju-libvirtSuite-\d+\/meta-data network-config=\/tmp\/juju-libvirtSuite-\d+\/network-config`,
 		`qemu-img create -b \/tmp/juju-libvirtSuite-\d+\/kvm\/guests\/precise-arm64-backing-file.qcow -f qcow2 \/tmp\/juju-libvirtSuite-\d+\/kvm\/guests\/host00.qcow 8G`,
 		`virsh define \/tmp\/juju-libvirtSuite-\d+\/host00.xml`,
 		"virsh start host00",
@@ -32,6 +32,7 @@ type ContainerSpec struct {
 	Config       map[string]string
 	Profiles     []string
 	InstanceType string
+	VirtType     string
 }
 
 // ApplyConstraints applies the input constraints as valid LXD container
@@ -43,6 +44,9 @@ func (c *ContainerSpec) ApplyConstraints(cons constraints.Value) {
 	if cons.HasInstanceType() {
 		c.InstanceType = *cons.InstanceType
 	}
+	if cons.HasVirtType() {
+		c.VirtType = *cons.VirtType
+	}
 	if cons.HasCpuCores() {
 		c.Config["limits.cpu"] = fmt.Sprintf("%d", *cons.CpuCores)
 	}
@@ -200,8 +204,6 @@ func (s *Server) ContainerAddresses(name string) ([]network.Address, error) {
 // If the container fails to be started, it is removed.
 // Upon successful creation and start, the container is returned.
 func (s *Server) CreateContainerFromSpec(spec ContainerSpec) (*Container, error) {
-	logger.Infof("starting new container %q (image %q)", spec.Name, spec.Image.Image.Filename)
-
 	req := api.ContainersPost{
 		Name:         spec.Name,
 		InstanceType: spec.InstanceType,
@@ -212,21 +214,13 @@ func (s *Server) CreateContainerFromSpec(spec ContainerSpec) (*Container, error)
 			Ephemeral: false,
 		},
 	}
-	op, err := s.CreateContainerFromImage(spec.Image.LXDServer, *spec.Image.Image, req)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-
-	if err := op.Wait(); err != nil {
-		return nil, errors.Trace(err)
+	create := s.createContainer
+	if spec.VirtType == VirtTypeVirtualMachine {
+		create = s.createVirtualMachine
 	}
-	opInfo, err := op.GetTarget()
-	if err != nil {
+	if err := create(spec, req); err != nil {
 		return nil, errors.Trace(err)
 	}
-	if opInfo.StatusCode != api.Success {
-		return nil, fmt.Errorf("container creation failed: %s", opInfo.Err)
-	}
 
 	logger.Debugf("created container %q, waiting for start...", spec.Name)
 
@@ -245,6 +239,55 @@ func (s *Server) CreateContainerFromSpec(spec ContainerSpec) (*Container, error)
 	return &c, nil
 }
 
+// createContainer creates a system container from the input
+// request, with the image of the input spec.
+func (s *Server) createContainer(spec ContainerSpec, req api.ContainersPost) error {
+	logger.Infof("starting new container %q (image %q)", spec.Name, spec.Image.Image.Filename)
+
+	op, err := s.CreateContainerFromImage(spec.Image.LXDServer, *spec.Image.Image, req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := op.Wait(); err != nil {
+		return errors.Trace(err)
+	}
+	opInfo, err := op.GetTarget()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if opInfo.StatusCode != api.Success {
+		return fmt.Errorf("container creation failed: %s", opInfo.Err)
+	}
+	return nil
+}
+
+// createVirtualMachine creates a virtual machine from the input
+// request, with the remote image source of the input spec.
+func (s *Server) createVirtualMachine(spec ContainerSpec, req api.ContainersPost) error {
+	if !s.SupportsVirtualMachines() {
+		return errors.NotSupportedf("virtual machines on LXD server %q", s.name)
+	}
+	if spec.Image.Source == nil {
+		return errors.NotValidf("virtual machine %q without a remote image source", spec.Name)
+	}
+	logger.Infof("starting new virtual machine %q (image %q from %q)",
+		spec.Name, spec.Image.Source.Alias, spec.Image.Source.Server)
+
+	req.Source = *spec.Image.Source
+	op, err := s.ContainerServer.(*instanceServer).createInstance(req, VirtTypeVirtualMachine)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := op.Wait(); err != nil {
+		return errors.Trace(err)
+	}
+	if opInfo := op.Get(); opInfo.StatusCode != api.Success {
+		return fmt.Errorf("virtual machine creation failed: %s", opInfo.Err)
+	}
+	return nil
+}
+
 // StartContainer starts the extant container identified by the input name.
 func (s *Server) StartContainer(name string) error {
 	req := api.ContainerStatePut{
@@ -24,6 +24,10 @@ type SourcedImage struct {
 	Image *api.Image
 	// LXDServer is the image server that supplied the image.
 	LXDServer lxd.ImageServer
+	// Source, if set, is the remote source from which the LXD server
+	// pulls the image itself. It is used for virtual machines, whose
+	// images are not located by FindImage.
+	Source *api.ContainerSource
 }
 
 // FindImage searches the input sources in supplied order, looking for an OS
@@ -115,6 +119,31 @@ func (s *Server) FindImage(
 	return sourced, nil
 }
 
+// VirtualMachineImage returns the virtual machine image for the input
+// series and architecture, from the first of the input sources.
+// Unlike FindImage, it does not locate the image: the LXD server pulls
+// it from the source when the virtual machine is created, choosing the
+// virtual machine variant of the image with the aliases we use.
+func VirtualMachineImage(series, arch string, sources []ServerSpec) (SourcedImage, error) {
+	if len(sources) == 0 {
+		return SourcedImage{}, errors.New("no image sources for virtual machine")
+	}
+	aliases, err := seriesRemoteAliases(series, arch)
+	if err != nil {
+		return SourcedImage{}, errors.Trace(err)
+	}
+	remote := sources[0]
+	return SourcedImage{
+		Source: &api.ContainerSource{
+			Type:     "image",
+			Mode:     "pull",
+			Server:   remote.Host,
+			Protocol: string(remote.Protocol),
+			Alias:    aliases[0],
+		},
+	}, nil
+}
+
 // CopyRemoteImage accepts an image sourced from a remote server and copies it
 // to the local cache
 func (s *Server) CopyRemoteImage(
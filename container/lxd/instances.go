@@ -0,0 +1,129 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/lxc/lxd/client"
+	"github.com/lxc/lxd/shared/api"
+)
+
+const (
+	// VirtTypeContainer is the virt-type constraint value, and the LXD
+	// instance type, of system containers.
+	VirtTypeContainer = "container"
+
+	// VirtTypeVirtualMachine is the virt-type constraint value, and the
+	// LXD instance type, of virtual machines.
+	VirtTypeVirtualMachine = "virtual-machine"
+)
+
+// instanceServer wraps a container server that supports the LXD
+// instances API, so that the container methods used by Juju operate
+// on all instances, virtual machines included.
+// The LXD client that we use predates the instances API, and its
+// container methods only see system containers.
+type instanceServer struct {
+	lxd.ContainerServer
+
+	// target is the cluster member on which new instances are created.
+	target string
+}
+
+// UseTarget is part of the lxd.ContainerServer interface.
+func (s *instanceServer) UseTarget(name string) lxd.ContainerServer {
+	return &instanceServer{
+		ContainerServer: s.ContainerServer.UseTarget(name),
+		target:          name,
+	}
+}
+
+// GetContainers is part of the lxd.ContainerServer interface.
+func (s *instanceServer) GetContainers() ([]api.Container, error) {
+	var containers []api.Container
+	if _, err := s.get("/instances?recursion=1", &containers); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return containers, nil
+}
+
+// GetContainer is part of the lxd.ContainerServer interface.
+func (s *instanceServer) GetContainer(name string) (*api.Container, string, error) {
+	var container api.Container
+	eTag, err := s.get(instancePath(name), &container)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return &container, eTag, nil
+}
+
+// GetContainerState is part of the lxd.ContainerServer interface.
+func (s *instanceServer) GetContainerState(name string) (*api.ContainerState, string, error) {
+	var state api.ContainerState
+	eTag, err := s.get(instancePath(name)+"/state", &state)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return &state, eTag, nil
+}
+
+// UpdateContainerState is part of the lxd.ContainerServer interface.
+func (s *instanceServer) UpdateContainerState(
+	name string, state api.ContainerStatePut, eTag string,
+) (lxd.Operation, error) {
+	op, _, err := s.RawOperation("PUT", "/1.0"+instancePath(name)+"/state", state, eTag)
+	return op, errors.Trace(err)
+}
+
+// UpdateContainer is part of the lxd.ContainerServer interface.
+func (s *instanceServer) UpdateContainer(
+	name string, container api.ContainerPut, eTag string,
+) (lxd.Operation, error) {
+	op, _, err := s.RawOperation("PUT", "/1.0"+instancePath(name), container, eTag)
+	return op, errors.Trace(err)
+}
+
+// DeleteContainer is part of the lxd.ContainerServer interface.
+func (s *instanceServer) DeleteContainer(name string) (lxd.Operation, error) {
+	op, _, err := s.RawOperation("DELETE", "/1.0"+instancePath(name), nil, "")
+	return op, errors.Trace(err)
+}
+
+// instancesPost is the request to create an instance of a
+// particular type with the instances API.
+type instancesPost struct {
+	api.ContainersPost
+	Type string `json:"type"`
+}
+
+// createInstance creates an instance of the input type from the
+// input request, on the target cluster member if there is one.
+func (s *instanceServer) createInstance(req api.ContainersPost, instanceType string) (lxd.Operation, error) {
+	path := "/1.0/instances"
+	if s.target != "" {
+		path += "?target=" + url.QueryEscape(s.target)
+	}
+	op, _, err := s.RawOperation("POST", path, instancesPost{
+		ContainersPost: req,
+		Type:           instanceType,
+	}, "")
+	return op, errors.Trace(err)
+}
+
+// get decodes the metadata of the response to a GET request on the
+// input path into target, and returns the response's ETag.
+func (s *instanceServer) get(path string, target interface{}) (string, error) {
+	resp, eTag, err := s.RawQuery("GET", "/1.0"+path, nil, "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return eTag, errors.Trace(resp.MetadataAsStruct(target))
+}
+
+func instancePath(name string) string {
+	return fmt.Sprintf("/instances/%s", url.PathEscape(name))
+}
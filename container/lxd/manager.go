@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
@@ -39,6 +40,8 @@ type containerManager struct {
 
 	imageMetadataURL string
 	imageStream      string
+
+	profiles lxdprofile.Profiles
 }
 
 // containerManager implements container.Manager.
@@ -66,6 +69,11 @@ func NewContainerManager(cfg container.ManagerConfig, svr *Server) (container.Ma
 	imageMetaDataURL := cfg.PopValue(config.ContainerImageMetadataURLKey)
 	imageStream := cfg.PopValue(config.ContainerImageStreamKey)
 
+	profiles, err := lxdprofile.Parse(cfg.PopValue(config.LXDProfilesKey))
+	if err != nil {
+		return nil, errors.Annotate(err, config.LXDProfilesKey)
+	}
+
 	cfg.WarnAboutUnused()
 	return &containerManager{
 		server:           svr,
@@ -74,6 +82,7 @@ func NewContainerManager(cfg container.ManagerConfig, svr *Server) (container.Ma
 		availabilityZone: availabilityZone,
 		imageMetadataURL: imageMetaDataURL,
 		imageStream:      imageStream,
+		profiles:         profiles,
 	}, nil
 }
 
@@ -97,6 +106,10 @@ func (m *containerManager) CreateContainer(
 	callback environs.StatusCallbackFunc,
 ) (instance.Instance, *instance.HardwareCharacteristics, error) {
 	spec, err := m.getContainerSpec(instanceConfig, cons, series, networkConfig, storageConfig, callback)
+	if err != nil {
+		callback(status.ProvisioningError, fmt.Sprintf("Creating container spec: %v", err), nil)
+		return nil, nil, errors.Trace(err)
+	}
 
 	callback(status.Provisioning, "Creating container", nil)
 	c, err := m.server.CreateContainerFromSpec(spec)
@@ -145,7 +158,15 @@ func (m *containerManager) getContainerSpec(
 		return ContainerSpec{}, errors.Trace(err)
 	}
 
-	found, err := m.server.FindImage(series, jujuarch.HostArch(), imageSources, true, callback)
+	var found SourcedImage
+	if cons.HasVirtType() && *cons.VirtType == VirtTypeVirtualMachine {
+		if !m.server.SupportsVirtualMachines() {
+			return ContainerSpec{}, errors.NotSupportedf("virtual machines on this LXD server")
+		}
+		found, err = VirtualMachineImage(series, jujuarch.HostArch(), imageSources)
+	} else {
+		found, err = m.server.FindImage(series, jujuarch.HostArch(), imageSources, true, callback)
+	}
 	if err != nil {
 		return ContainerSpec{}, errors.Annotatef(err, "acquiring LXD image")
 	}
@@ -198,6 +219,24 @@ func (m *containerManager) getContainerSpec(
 		return ContainerSpec{}, errors.Trace(err)
 	}
 
+	// Any user-defined profiles are applied on top of the default,
+	// followed by those defined by the charms of the units to be
+	// deployed to the container.
+	var profiles []string
+	for _, extra := range []lxdprofile.Profiles{m.profiles, instanceConfig.CharmLXDProfiles} {
+		if len(extra) == 0 {
+			continue
+		}
+		names, err := m.server.EnsureProfiles(m.namespace.Prefix(), extra)
+		if err != nil {
+			return ContainerSpec{}, errors.Trace(err)
+		}
+		profiles = append(profiles, names...)
+	}
+	if len(profiles) > 0 {
+		profiles = append([]string{lxdDefaultProfileName}, profiles...)
+	}
+
 	cfg := map[string]string{
 		UserDataKey:      string(userData),
 		NetworkConfigKey: cloudinit.CloudInitNetworkConfigDisabled,
@@ -210,7 +249,7 @@ func (m *containerManager) getContainerSpec(
 		Name:     name,
 		Image:    found,
 		Config:   cfg,
-		Profiles: nil,
+		Profiles: profiles,
 		Devices:  nics,
 	}
 	spec.ApplyConstraints(cons)
@@ -4,6 +4,7 @@
 package lxd_test
 
 import (
+	"encoding/json"
 	"errors"
 	stdtesting "testing"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxd"
 	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/network"
@@ -290,6 +292,193 @@ func (s *managerSuite) TestCreateContainerStartFailed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, ".*start failed")
 }
 
+func (s *managerSuite) TestCreateContainerWithProfiles(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+	s.patch(cSvr)
+
+	cfg := getBaseConfig()
+	cfg[config.LXDProfilesKey] = "iscsi:\n  config:\n    linux.kernel_modules: iscsi_tcp\n"
+	manager := s.makeManagerForConfig(c, cfg, cSvr)
+	iCfg := prepInstanceConfig(c)
+	hostName, err := manager.Namespace().Hostname(iCfg.MachineId)
+	c.Assert(err, jc.ErrorIsNil)
+	profileName := manager.Namespace().Prefix() + "iscsi"
+
+	exp := cSvr.EXPECT()
+	exp.GetProfile(profileName).Return(nil, "", errors.New("not found"))
+	exp.CreateProfile(lxdapi.ProfilesPost{
+		Name: profileName,
+		ProfilePut: lxdapi.ProfilePut{
+			Config: map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+		},
+	}).Return(nil)
+
+	createRemoteOp := lxdtesting.NewMockRemoteOperation(ctrl)
+	createRemoteOp.EXPECT().Wait().Return(nil).AnyTimes()
+	createRemoteOp.EXPECT().GetTarget().Return(&lxdapi.Operation{StatusCode: lxdapi.Success}, nil)
+
+	alias := &lxdapi.ImageAliasesEntry{ImageAliasesEntryPut: lxdapi.ImageAliasesEntryPut{Target: "foo-target"}}
+	image := lxdapi.Image{Filename: "this-is-our-image"}
+	exp.GetImageAlias("juju/xenial/"+s.Arch()).Return(alias, lxdtesting.ETag, nil)
+	exp.GetImage("foo-target").Return(&image, lxdtesting.ETag, nil)
+	exp.CreateContainerFromImage(cSvr, image, gomock.Any()).Do(
+		func(_ lxdclient.ImageServer, _ lxdapi.Image, req lxdapi.ContainersPost) {
+			c.Check(req.Profiles, jc.DeepEquals, []string{"default", profileName})
+		},
+	).Return(createRemoteOp, nil)
+
+	startOp := lxdtesting.NewMockOperation(ctrl)
+	startOp.EXPECT().Wait().Return(nil)
+	exp.UpdateContainerState(hostName, lxdapi.ContainerStatePut{Action: "start", Timeout: -1}, "").Return(startOp, nil)
+	exp.GetContainer(hostName).Return(&lxdapi.Container{Name: hostName}, lxdtesting.ETag, nil)
+
+	_, _, err = manager.CreateContainer(
+		iCfg, constraints.Value{}, "xenial", prepNetworkConfig(), &container.StorageConfig{}, lxdtesting.NoOpCallback,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *managerSuite) TestCreateContainerWithCharmProfiles(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+	s.patch(cSvr)
+
+	manager := s.makeManager(c, cSvr)
+	iCfg := prepInstanceConfig(c)
+	iCfg.CharmLXDProfiles = lxdprofile.Profiles{
+		"app-iscsi": {Config: map[string]string{"linux.kernel_modules": "iscsi_tcp"}},
+	}
+	hostName, err := manager.Namespace().Hostname(iCfg.MachineId)
+	c.Assert(err, jc.ErrorIsNil)
+	profileName := manager.Namespace().Prefix() + "app-iscsi"
+
+	exp := cSvr.EXPECT()
+	exp.GetProfile(profileName).Return(nil, "", errors.New("not found"))
+	exp.CreateProfile(lxdapi.ProfilesPost{
+		Name: profileName,
+		ProfilePut: lxdapi.ProfilePut{
+			Config: map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+		},
+	}).Return(nil)
+
+	createRemoteOp := lxdtesting.NewMockRemoteOperation(ctrl)
+	createRemoteOp.EXPECT().Wait().Return(nil).AnyTimes()
+	createRemoteOp.EXPECT().GetTarget().Return(&lxdapi.Operation{StatusCode: lxdapi.Success}, nil)
+
+	alias := &lxdapi.ImageAliasesEntry{ImageAliasesEntryPut: lxdapi.ImageAliasesEntryPut{Target: "foo-target"}}
+	image := lxdapi.Image{Filename: "this-is-our-image"}
+	exp.GetImageAlias("juju/xenial/"+s.Arch()).Return(alias, lxdtesting.ETag, nil)
+	exp.GetImage("foo-target").Return(&image, lxdtesting.ETag, nil)
+	exp.CreateContainerFromImage(cSvr, image, gomock.Any()).Do(
+		func(_ lxdclient.ImageServer, _ lxdapi.Image, req lxdapi.ContainersPost) {
+			c.Check(req.Profiles, jc.DeepEquals, []string{"default", profileName})
+		},
+	).Return(createRemoteOp, nil)
+
+	startOp := lxdtesting.NewMockOperation(ctrl)
+	startOp.EXPECT().Wait().Return(nil)
+	exp.UpdateContainerState(hostName, lxdapi.ContainerStatePut{Action: "start", Timeout: -1}, "").Return(startOp, nil)
+	exp.GetContainer(hostName).Return(&lxdapi.Container{Name: hostName}, lxdtesting.ETag, nil)
+
+	_, _, err = manager.CreateContainer(
+		iCfg, constraints.Value{}, "xenial", prepNetworkConfig(), &container.StorageConfig{}, lxdtesting.NoOpCallback,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *managerSuite) TestNewContainerManagerInvalidProfiles(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+	svr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg := getBaseConfig()
+	cfg[config.LXDProfilesKey] = "default: {}"
+	_, err = lxd.NewContainerManager(cfg, svr)
+	c.Assert(err, gc.ErrorMatches, `lxd-profiles: LXD profile name "default", which is reserved, not valid`)
+}
+
+func (s *managerSuite) TestCreateVirtualMachine(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "instances", "virtual-machines")
+
+	manager := s.makeManager(c, cSvr)
+	iCfg := prepInstanceConfig(c)
+	hostName, err := manager.Namespace().Hostname(iCfg.MachineId)
+	c.Assert(err, jc.ErrorIsNil)
+
+	createOp := lxdtesting.NewMockOperation(ctrl)
+	createOp.EXPECT().Wait().Return(nil)
+	createOp.EXPECT().Get().Return(lxdapi.Operation{StatusCode: lxdapi.Success})
+
+	startOp := lxdtesting.NewMockOperation(ctrl)
+	startOp.EXPECT().Wait().Return(nil)
+
+	vm, err := json.Marshal(lxdapi.Container{Name: hostName})
+	c.Assert(err, jc.ErrorIsNil)
+
+	exp := cSvr.EXPECT()
+	var created map[string]interface{}
+	gomock.InOrder(
+		exp.RawOperation("POST", "/1.0/instances", gomock.Any(), "").Do(
+			func(_, _ string, data interface{}, _ string) {
+				body, err := json.Marshal(data)
+				c.Assert(err, jc.ErrorIsNil)
+				c.Assert(json.Unmarshal(body, &created), jc.ErrorIsNil)
+			},
+		).Return(createOp, "", nil),
+		exp.RawOperation(
+			"PUT", "/1.0/instances/"+hostName+"/state", lxdapi.ContainerStatePut{Action: "start", Timeout: -1}, "",
+		).Return(startOp, "", nil),
+		exp.RawQuery("GET", "/1.0/instances/"+hostName, nil, "").Return(
+			&lxdapi.Response{Metadata: vm}, lxdtesting.ETag, nil),
+	)
+
+	vmType := "virtual-machine"
+	instance, _, err := manager.CreateContainer(
+		iCfg,
+		constraints.Value{VirtType: &vmType},
+		"xenial",
+		prepNetworkConfig(),
+		&container.StorageConfig{},
+		lxdtesting.NoOpCallback,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(instance.Id()), gc.Equals, hostName)
+
+	c.Check(created["name"], gc.Equals, hostName)
+	c.Check(created["type"], gc.Equals, "virtual-machine")
+	source, ok := created["source"].(map[string]interface{})
+	c.Assert(ok, jc.IsTrue)
+	c.Check(source["type"], gc.Equals, "image")
+	c.Check(source["mode"], gc.Equals, "pull")
+	c.Check(source["server"], gc.Equals, "https://cloud-images.ubuntu.com/releases")
+	c.Check(source["protocol"], gc.Equals, "simplestreams")
+	c.Check(source["alias"], gc.Equals, "xenial/"+s.Arch())
+}
+
+func (s *managerSuite) TestCreateVirtualMachineNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "instances")
+
+	vmType := "virtual-machine"
+	_, _, err := s.makeManager(c, cSvr).CreateContainer(
+		prepInstanceConfig(c),
+		constraints.Value{VirtType: &vmType},
+		"xenial",
+		prepNetworkConfig(),
+		&container.StorageConfig{},
+		lxdtesting.NoOpCallback,
+	)
+	c.Assert(err, gc.ErrorMatches, "virtual machines on this LXD server not supported")
+}
+
 // expectCreateContainer is a convenience function for the expectations
 // concerning a successful container creation based on a cached local
 // image.
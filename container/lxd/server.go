@@ -10,6 +10,8 @@ import (
 	"github.com/lxc/lxd/client"
 	"github.com/lxc/lxd/shared"
 	"github.com/lxc/lxd/shared/api"
+
+	"github.com/juju/juju/core/lxdprofile"
 )
 
 // osSupport is the list of operating system types for which Juju supports
@@ -41,6 +43,8 @@ type Server struct {
 	clusterAPISupport bool
 	storageAPISupport bool
 
+	virtualMachineSupport bool
+
 	localBridgeName string
 }
 
@@ -97,18 +101,34 @@ func NewServer(svr lxd.ContainerServer) (*Server, error) {
 	serverCertificate := info.Environment.Certificate
 	hostArch := arch.NormaliseArch(info.Environment.KernelArchitecture)
 
+	// Servers with the instances API hide virtual machines from the
+	// container API, so we use the instances API where we can.
+	if _, ok := svr.(*instanceServer); !ok && shared.StringInSlice("instances", apiExt) {
+		svr = &instanceServer{ContainerServer: svr}
+	}
+
 	return &Server{
-		ContainerServer:   svr,
-		name:              name,
-		clustered:         clustered,
-		serverCertificate: serverCertificate,
-		hostArch:          hostArch,
-		networkAPISupport: shared.StringInSlice("network", apiExt),
-		clusterAPISupport: shared.StringInSlice("clustering", apiExt),
-		storageAPISupport: shared.StringInSlice("storage", apiExt),
+		ContainerServer:       svr,
+		name:                  name,
+		clustered:             clustered,
+		serverCertificate:     serverCertificate,
+		hostArch:              hostArch,
+		networkAPISupport:     shared.StringInSlice("network", apiExt),
+		clusterAPISupport:     shared.StringInSlice("clustering", apiExt),
+		storageAPISupport:     shared.StringInSlice("storage", apiExt),
+		virtualMachineSupport: shared.StringInSlice("virtual-machines", apiExt),
 	}, nil
 }
 
+// SupportsVirtualMachines returns true if the server can create
+// virtual machines as well as containers.
+func (s *Server) SupportsVirtualMachines() bool {
+	if _, ok := s.ContainerServer.(*instanceServer); !ok {
+		return false
+	}
+	return s.virtualMachineSupport
+}
+
 // Name returns the name of this LXD server.
 func (s *Server) Name() string {
 	return s.name
@@ -186,6 +206,67 @@ func (s *Server) CreateProfileWithConfig(name string, cfg map[string]string) err
 	return errors.Trace(s.CreateProfile(req))
 }
 
+// EnsureProfile creates the profile with the input name, or updates
+// it if it exists, so that it has the input description, config and
+// devices.
+func (s *Server) EnsureProfile(name string, profile lxdprofile.Profile) error {
+	put := api.ProfilePut{
+		Description: profile.Description,
+		Config:      profile.Config,
+		Devices:     profile.Devices,
+	}
+	existing, eTag, err := s.GetProfile(name)
+	if IsLXDNotFound(err) {
+		logger.Debugf("creating LXD profile %q", name)
+		return errors.Trace(s.CreateProfile(api.ProfilesPost{Name: name, ProfilePut: put}))
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if profilePutEqual(existing.Writable(), put) {
+		return nil
+	}
+	logger.Debugf("updating LXD profile %q", name)
+	return errors.Trace(s.UpdateProfile(name, put, eTag))
+}
+
+// EnsureProfiles ensures the input profiles with EnsureProfile, naming
+// each with the input prefix, and returns their names in order.
+func (s *Server) EnsureProfiles(prefix string, profiles lxdprofile.Profiles) ([]string, error) {
+	var names []string
+	for _, name := range profiles.Names() {
+		lxdName := prefix + name
+		if err := s.EnsureProfile(lxdName, profiles[name]); err != nil {
+			return nil, errors.Annotatef(err, "ensuring LXD profile %q", lxdName)
+		}
+		names = append(names, lxdName)
+	}
+	return names, nil
+}
+
+func profilePutEqual(a, b api.ProfilePut) bool {
+	if a.Description != b.Description || len(a.Config) != len(b.Config) || len(a.Devices) != len(b.Devices) {
+		return false
+	}
+	for k, v := range a.Config {
+		if bv, ok := b.Config[k]; !ok || bv != v {
+			return false
+		}
+	}
+	for name, dev := range a.Devices {
+		bdev, ok := b.Devices[name]
+		if !ok || len(bdev) != len(dev) {
+			return false
+		}
+		for k, v := range dev {
+			if bv, ok := bdev[k]; !ok || bv != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // ServerCertificate returns the current server environment certificate
 func (s *Server) ServerCertificate() string {
 	return s.serverCertificate
@@ -4,6 +4,9 @@
 package lxd_test
 
 import (
+	"encoding/json"
+	"errors"
+
 	"github.com/golang/mock/gomock"
 	jc "github.com/juju/testing/checkers"
 	"github.com/lxc/lxd/shared/api"
@@ -11,6 +14,7 @@ import (
 
 	"github.com/juju/juju/container/lxd"
 	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/core/lxdprofile"
 )
 
 type serverSuite struct {
@@ -97,3 +101,144 @@ func (s *serverSuite) TestCreateProfileWithConfig(c *gc.C) {
 	err = jujuSvr.CreateProfileWithConfig("custom", map[string]string{"boot.autostart": "false"})
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *serverSuite) TestEnsureProfileCreates(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	profile := lxdprofile.Profile{
+		Description: "iSCSI",
+		Config:      map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+	}
+	req := api.ProfilesPost{
+		Name: "juju-iscsi",
+		ProfilePut: api.ProfilePut{
+			Description: "iSCSI",
+			Config:      map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+		},
+	}
+	gomock.InOrder(
+		cSvr.EXPECT().GetProfile("juju-iscsi").Return(nil, "", errors.New("not found")),
+		cSvr.EXPECT().CreateProfile(req).Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.EnsureProfile("juju-iscsi", profile)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *serverSuite) TestEnsureProfileUpdates(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	profile := lxdprofile.Profile{
+		Devices: map[string]map[string]string{"kvm": {"type": "unix-char", "path": "/dev/kvm"}},
+	}
+	existing := &api.Profile{
+		Name: "juju-kvm",
+		ProfilePut: api.ProfilePut{
+			Devices: map[string]map[string]string{"kvm": {"type": "unix-char"}},
+		},
+	}
+	put := api.ProfilePut{Devices: profile.Devices}
+	gomock.InOrder(
+		cSvr.EXPECT().GetProfile("juju-kvm").Return(existing, lxdtesting.ETag, nil),
+		cSvr.EXPECT().UpdateProfile("juju-kvm", put, lxdtesting.ETag).Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.EnsureProfile("juju-kvm", profile)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *serverSuite) TestEnsureProfileUnchanged(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	existing := &api.Profile{
+		Name: "juju-iscsi",
+		ProfilePut: api.ProfilePut{
+			Config:  map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+			Devices: map[string]map[string]string{},
+		},
+	}
+	cSvr.EXPECT().GetProfile("juju-iscsi").Return(existing, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.EnsureProfile("juju-iscsi", lxdprofile.Profile{
+		Config: map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *serverSuite) TestEnsureProfiles(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	existing := &api.Profile{Name: "juju-a"}
+	exp := cSvr.EXPECT()
+	gomock.InOrder(
+		exp.GetProfile("juju-a").Return(existing, lxdtesting.ETag, nil),
+		exp.GetProfile("juju-b").Return(nil, "", errors.New("boom")),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuSvr.EnsureProfiles("juju-", lxdprofile.Profiles{"b": {}, "a": {}})
+	c.Assert(err, gc.ErrorMatches, `ensuring LXD profile "juju-b": boom`)
+}
+
+func (s *serverSuite) TestSupportsVirtualMachines(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	for i, test := range []struct {
+		extensions []string
+		supported  bool
+	}{{
+		extensions: nil,
+	}, {
+		extensions: []string{"virtual-machines"},
+	}, {
+		extensions: []string{"instances"},
+	}, {
+		extensions: []string{"instances", "virtual-machines"},
+		supported:  true,
+	}} {
+		c.Logf("test %d: %v", i, test.extensions)
+		jujuSvr, err := lxd.NewServer(s.NewMockServerWithExtensions(ctrl, test.extensions...))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(jujuSvr.SupportsVirtualMachines(), gc.Equals, test.supported)
+	}
+}
+
+func (s *serverSuite) TestInstancesAPIListsAllInstances(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "instances")
+
+	instances, err := json.Marshal([]api.Container{{Name: "juju-0"}, {Name: "juju-vm-1"}, {Name: "other"}})
+	c.Assert(err, jc.ErrorIsNil)
+	cSvr.EXPECT().RawQuery("GET", "/1.0/instances?recursion=1", nil, "").Return(
+		&api.Response{Metadata: instances}, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	containers, err := jujuSvr.FilterContainers("juju-")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	c.Check(containers[0].Name, gc.Equals, "juju-0")
+	c.Check(containers[1].Name, gc.Equals, "juju-vm-1")
+}
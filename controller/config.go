@@ -176,6 +176,30 @@ const (
 
 	// MeteringURL is the key for the url to use for metrics
 	MeteringURL = "metering-url"
+
+	// OIDCIssuerURL sets the URL of an OpenID Connect provider that
+	// controller users may log in with, as user@oidc.
+	OIDCIssuerURL = "oidc-issuer-url"
+
+	// OIDCClientID sets the client ID registered with the OpenID
+	// Connect provider for the controller.
+	OIDCClientID = "oidc-client-id"
+
+	// OIDCUsernameClaim sets the ID token claim that holds the
+	// user name. It defaults to "preferred_username".
+	OIDCUsernameClaim = "oidc-username-claim"
+
+	// OIDCGroupsClaim sets the ID token claim that holds the names of
+	// the groups the user belongs to. It defaults to "groups".
+	OIDCGroupsClaim = "oidc-groups-claim"
+
+	// DefaultOIDCUsernameClaim is the default value for the
+	// OIDCUsernameClaim config value.
+	DefaultOIDCUsernameClaim = "preferred_username"
+
+	// DefaultOIDCGroupsClaim is the default value for the
+	// OIDCGroupsClaim config value.
+	DefaultOIDCGroupsClaim = "groups"
 )
 
 var (
@@ -207,6 +231,10 @@ var (
 		CAASOperatorImagePath,
 		Features,
 		MeteringURL,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCUsernameClaim,
+		OIDCGroupsClaim,
 	}
 
 	// AllowedUpdateConfigAttributes contains all of the controller
@@ -220,6 +248,10 @@ var (
 		JujuManagementSpace,
 		CAASOperatorImagePath,
 		Features,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCUsernameClaim,
+		OIDCGroupsClaim,
 	)
 
 	// DefaultAuditLogExcludeMethods is the default list of methods to
@@ -512,6 +544,34 @@ func (c Config) MeteringURL() string {
 	return url
 }
 
+// OIDCIssuerURL returns the URL of the OpenID Connect provider that
+// users may log in with. It is empty when OIDC login is disabled.
+func (c Config) OIDCIssuerURL() string {
+	return c.asString(OIDCIssuerURL)
+}
+
+// OIDCClientID returns the client ID registered with the OpenID
+// Connect provider for the controller.
+func (c Config) OIDCClientID() string {
+	return c.asString(OIDCClientID)
+}
+
+// OIDCUsernameClaim returns the ID token claim holding the user name.
+func (c Config) OIDCUsernameClaim() string {
+	if v := c.asString(OIDCUsernameClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCUsernameClaim
+}
+
+// OIDCGroupsClaim returns the ID token claim holding the user's groups.
+func (c Config) OIDCGroupsClaim() string {
+	if v := c.asString(OIDCGroupsClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCGroupsClaim
+}
+
 // Validate ensures that config is a valid configuration.
 func Validate(c Config) error {
 	if v, ok := c[IdentityPublicKey].(string); ok {
@@ -535,6 +595,19 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[OIDCIssuerURL].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid OIDC issuer URL")
+		}
+		if u.Scheme != "https" {
+			return errors.Errorf("%s needs to be https", OIDCIssuerURL)
+		}
+		if c.OIDCClientID() == "" {
+			return errors.Errorf("%s requires %s", OIDCIssuerURL, OIDCClientID)
+		}
+	}
+
 	caCert, caCertOK := c.CACert()
 	if !caCertOK {
 		return errors.Errorf("missing CA certificate")
@@ -697,6 +770,10 @@ var configChecker = schema.FieldMap(schema.Fields{
 	Features:                schema.List(schema.String()),
 	CharmStoreURL:           schema.String(),
 	MeteringURL:             schema.String(),
+	OIDCIssuerURL:           schema.String(),
+	OIDCClientID:            schema.String(),
+	OIDCUsernameClaim:       schema.String(),
+	OIDCGroupsClaim:         schema.String(),
 }, schema.Defaults{
 	APIPort:                 DefaultAPIPort,
 	AuditingEnabled:         DefaultAuditingEnabled,
@@ -721,4 +798,8 @@ var configChecker = schema.FieldMap(schema.Fields{
 	Features:                schema.Omit,
 	CharmStoreURL:           csclient.ServerURL,
 	MeteringURL:             romulus.DefaultAPIRoot,
+	OIDCIssuerURL:           schema.Omit,
+	OIDCClientID:            schema.Omit,
+	OIDCUsernameClaim:       schema.Omit,
+	OIDCGroupsClaim:         schema.Omit,
 })
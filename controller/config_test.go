@@ -230,6 +230,28 @@ var validateTests = []struct {
 		controller.CAASOperatorImagePath: "foo//bar",
 	},
 	expectError: `docker image path "foo//bar" not valid`,
+}, {
+	about: "HTTPS OIDC issuer URL OK",
+	config: controller.Config{
+		controller.CACertKey:     testing.CACert,
+		controller.OIDCIssuerURL: "https://issuer.example.com",
+		controller.OIDCClientID:  "juju",
+	},
+}, {
+	about: "HTTP OIDC issuer URL",
+	config: controller.Config{
+		controller.CACertKey:     testing.CACert,
+		controller.OIDCIssuerURL: "http://issuer.example.com",
+		controller.OIDCClientID:  "juju",
+	},
+	expectError: `oidc-issuer-url needs to be https`,
+}, {
+	about: "OIDC issuer URL without client ID",
+	config: controller.Config{
+		controller.CACertKey:     testing.CACert,
+		controller.OIDCIssuerURL: "https://issuer.example.com",
+	},
+	expectError: `oidc-issuer-url requires oidc-client-id`,
 }}
 
 func (s *ConfigSuite) TestValidate(c *gc.C) {
@@ -483,3 +505,33 @@ func (s *ConfigSuite) TestMeteringURLSettingValue(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg.MeteringURL(), gc.Equals, mURL)
 }
+
+func (s *ConfigSuite) TestOIDCClaimDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.OIDCIssuerURL(), gc.Equals, "")
+	c.Check(cfg.OIDCUsernameClaim(), gc.Equals, "preferred_username")
+	c.Check(cfg.OIDCGroupsClaim(), gc.Equals, "groups")
+}
+
+func (s *ConfigSuite) TestOIDCValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			controller.OIDCIssuerURL:     "https://issuer.example.com",
+			controller.OIDCClientID:      "juju",
+			controller.OIDCUsernameClaim: "email",
+			controller.OIDCGroupsClaim:   "roles",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.OIDCIssuerURL(), gc.Equals, "https://issuer.example.com")
+	c.Check(cfg.OIDCClientID(), gc.Equals, "juju")
+	c.Check(cfg.OIDCUsernameClaim(), gc.Equals, "email")
+	c.Check(cfg.OIDCGroupsClaim(), gc.Equals, "roles")
+}
@@ -33,6 +33,7 @@ type Conversation struct {
 	ModelUUID      string `json:"model-uuid"`
 	ConversationID string `json:"conversation-id"` // uint64 in hex
 	ConnectionID   string `json:"connection-id"`   // uint64 in hex (using %X to match the value in log files)
+	APITokenID     string `json:"api-token-id,omitempty"`
 }
 
 // ConversationArgs is the information needed to create a method recorder.
@@ -42,6 +43,10 @@ type ConversationArgs struct {
 	ModelName    string
 	ModelUUID    string
 	ConnectionID uint64
+
+	// APITokenID is the id of the API token the user logged in
+	// with, if any.
+	APITokenID string
 }
 
 // Request represents a call to an API facade made as part of
@@ -128,6 +133,7 @@ func NewRecorder(log AuditLog, clock clock.Clock, c ConversationArgs) (*Recorder
 		When:           clock.Now().Format(time.RFC3339),
 		ModelName:      c.ModelName,
 		ModelUUID:      c.ModelUUID,
+		APITokenID:     c.APITokenID,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
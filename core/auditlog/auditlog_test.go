@@ -137,6 +137,18 @@ func (s *AuditLogSuite) TestRecorder(c *gc.C) {
 	})
 }
 
+func (s *AuditLogSuite) TestRecorderAPIToken(c *gc.C) {
+	var log fakeLog
+	_, err := auditlog.NewRecorder(&log, testing.NewClock(time.Time{}), auditlog.ConversationArgs{
+		Who:        "ci",
+		APITokenID: "some-token",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	log.stub.CheckCallNames(c, "AddConversation")
+	conversation := log.stub.Calls()[0].Args[0].(auditlog.Conversation)
+	c.Assert(conversation.APITokenID, gc.Equals, "some-token")
+}
+
 type fakeLog struct {
 	stub testing.Stub
 }
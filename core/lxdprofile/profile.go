@@ -0,0 +1,95 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package lxdprofile defines the user-defined LXD profiles that are
+// applied to the LXD containers and virtual machines of a model, and
+// the profiles charms define for the instances hosting their units.
+package lxdprofile
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Profile is a user-defined LXD profile.
+type Profile struct {
+	// Description describes the profile.
+	Description string `yaml:"description,omitempty"`
+
+	// Config holds the LXD instance configuration set by the profile.
+	Config map[string]string `yaml:"config,omitempty"`
+
+	// Devices holds the LXD devices added by the profile, by name.
+	Devices map[string]map[string]string `yaml:"devices,omitempty"`
+}
+
+// Profiles holds user-defined LXD profiles by name.
+type Profiles map[string]Profile
+
+// Names returns the sorted names of the profiles.
+func (p Profiles) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse parses LXD profiles from YAML, given as a map of profile
+// names to profiles, for example:
+//
+//	iscsi:
+//	  description: iSCSI initiator support
+//	  config:
+//	    linux.kernel_modules: iscsi_tcp
+//
+// An empty string holds no profiles.
+func Parse(s string) (Profiles, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var profiles Profiles
+	if err := yaml.UnmarshalStrict([]byte(s), &profiles); err != nil {
+		return nil, errors.Annotate(err, "parsing LXD profiles")
+	}
+	for name := range profiles {
+		if err := validateName(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return profiles, nil
+}
+
+// CharmProfileFile is the name of the file in a charm archive that
+// holds the LXD profile to apply to instances hosting the charm's units.
+const CharmProfileFile = "lxd-profile.yaml"
+
+// ParseProfile parses a single LXD profile from YAML, as held in a
+// charm's lxd-profile.yaml, for example:
+//
+//	description: iSCSI initiator support
+//	config:
+//	  linux.kernel_modules: iscsi_tcp
+func ParseProfile(s string) (Profile, error) {
+	var profile Profile
+	if err := yaml.UnmarshalStrict([]byte(s), &profile); err != nil {
+		return Profile{}, errors.Annotate(err, "parsing LXD profile")
+	}
+	return profile, nil
+}
+
+func validateName(name string) error {
+	switch {
+	case name == "":
+		return errors.NotValidf("empty LXD profile name")
+	case name == "default":
+		return errors.NotValidf("LXD profile name %q, which is reserved,", name)
+	case strings.ContainsAny(name, "/ \t\n"):
+		return errors.NotValidf("LXD profile name %q", name)
+	}
+	return nil
+}
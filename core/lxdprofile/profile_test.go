@@ -0,0 +1,99 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdprofile_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/lxdprofile"
+	"github.com/juju/juju/testing"
+)
+
+type ProfileSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ProfileSuite{})
+
+func (*ProfileSuite) TestParse(c *gc.C) {
+	profiles, err := lxdprofile.Parse(`
+iscsi:
+  description: iSCSI initiator support
+  config:
+    linux.kernel_modules: iscsi_tcp
+kvm:
+  devices:
+    kvm:
+      type: unix-char
+      path: /dev/kvm
+`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, jc.DeepEquals, lxdprofile.Profiles{
+		"iscsi": {
+			Description: "iSCSI initiator support",
+			Config:      map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+		},
+		"kvm": {
+			Devices: map[string]map[string]string{
+				"kvm": {"type": "unix-char", "path": "/dev/kvm"},
+			},
+		},
+	})
+	c.Assert(profiles.Names(), jc.DeepEquals, []string{"iscsi", "kvm"})
+}
+
+func (*ProfileSuite) TestParseEmpty(c *gc.C) {
+	profiles, err := lxdprofile.Parse(" \n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, gc.HasLen, 0)
+}
+
+func (*ProfileSuite) TestParseErrors(c *gc.C) {
+	for i, test := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "iscsi: [1, 2]",
+		err:  "parsing LXD profiles: .*",
+	}, {
+		yaml: "iscsi:\n  limits: 2\n",
+		err:  "parsing LXD profiles: .*field limits not found.*",
+	}, {
+		yaml: "default:\n  config: {}\n",
+		err:  `LXD profile name "default", which is reserved, not valid`,
+	}, {
+		yaml: "a/b:\n  config: {}\n",
+		err:  `LXD profile name "a/b" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.yaml)
+		_, err := lxdprofile.Parse(test.yaml)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (*ProfileSuite) TestParseProfile(c *gc.C) {
+	profile, err := lxdprofile.ParseProfile(`
+description: iSCSI initiator support
+config:
+  linux.kernel_modules: iscsi_tcp
+devices:
+  kvm:
+    type: unix-char
+    path: /dev/kvm
+`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile, jc.DeepEquals, lxdprofile.Profile{
+		Description: "iSCSI initiator support",
+		Config:      map[string]string{"linux.kernel_modules": "iscsi_tcp"},
+		Devices: map[string]map[string]string{
+			"kvm": {"type": "unix-char", "path": "/dev/kvm"},
+		},
+	})
+}
+
+func (*ProfileSuite) TestParseProfileError(c *gc.C) {
+	_, err := lxdprofile.ParseProfile("limits: 2\n")
+	c.Assert(err, gc.ErrorMatches, "parsing LXD profile: .*field limits not found.*")
+}
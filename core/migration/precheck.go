@@ -0,0 +1,103 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// PrecheckScope identifies which part of a migration a precheck
+// problem was found in.
+type PrecheckScope string
+
+const (
+	// PrecheckScopeModel is used for problems with the model being
+	// migrated.
+	PrecheckScopeModel PrecheckScope = "model"
+
+	// PrecheckScopeSourceController is used for problems with the
+	// controller currently hosting the model.
+	PrecheckScopeSourceController PrecheckScope = "source-controller"
+
+	// PrecheckScopeTargetController is used for problems with the
+	// controller the model would be migrated to.
+	PrecheckScopeTargetController PrecheckScope = "target-controller"
+)
+
+// PrecheckProblem describes a single failed migration precheck.
+type PrecheckProblem struct {
+	// Scope indicates where the problem was found.
+	Scope PrecheckScope
+
+	// Entity holds the tag of the entity the problem relates to
+	// (e.g. "machine-0" or "unit-mysql-0").
+	Entity string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (p PrecheckProblem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Scope, p.Entity, p.Message)
+}
+
+// PrecheckReport collects all of the problems found when running
+// the migration prechecks without stopping at the first failure.
+type PrecheckReport struct {
+	Problems []PrecheckProblem
+}
+
+// Add records a problem in the report.
+func (r *PrecheckReport) Add(scope PrecheckScope, entity, message string) {
+	r.Problems = append(r.Problems, PrecheckProblem{
+		Scope:   scope,
+		Entity:  entity,
+		Message: message,
+	})
+}
+
+// Merge appends all of the problems in other to the report.
+func (r *PrecheckReport) Merge(other PrecheckReport) {
+	r.Problems = append(r.Problems, other.Problems...)
+}
+
+// Empty returns true if no problems have been recorded.
+func (r *PrecheckReport) Empty() bool {
+	return len(r.Problems) == 0
+}
+
+// ByEntity returns the recorded problems grouped by scope and entity,
+// in a stable order. The keys of the returned map are of the form
+// "<scope> <entity>".
+func (r *PrecheckReport) ByEntity() ([]string, map[string][]PrecheckProblem) {
+	grouped := make(map[string][]PrecheckProblem)
+	var keys []string
+	for _, p := range r.Problems {
+		key := string(p.Scope) + " " + p.Entity
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], p)
+	}
+	sort.Strings(keys)
+	return keys, grouped
+}
+
+// AsError returns an error summarising the problems in the report,
+// or nil if the report is empty.
+func (r *PrecheckReport) AsError() error {
+	if r.Empty() {
+		return nil
+	}
+	lines := make([]string, len(r.Problems))
+	for i, p := range r.Problems {
+		lines[i] = p.String()
+	}
+	return errors.Errorf("migration prechecks failed:\n%s", strings.Join(lines, "\n"))
+}
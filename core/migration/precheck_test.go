@@ -0,0 +1,67 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/migration"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type PrecheckReportSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(new(PrecheckReportSuite))
+
+func (s *PrecheckReportSuite) TestEmpty(c *gc.C) {
+	var report migration.PrecheckReport
+	c.Check(report.Empty(), jc.IsTrue)
+	c.Check(report.AsError(), jc.ErrorIsNil)
+}
+
+func (s *PrecheckReportSuite) TestAdd(c *gc.C) {
+	var report migration.PrecheckReport
+	report.Add(migration.PrecheckScopeModel, "machine-0", "machine 0 is dying")
+	c.Check(report.Empty(), jc.IsFalse)
+	c.Check(report.Problems, jc.DeepEquals, []migration.PrecheckProblem{{
+		Scope:   migration.PrecheckScopeModel,
+		Entity:  "machine-0",
+		Message: "machine 0 is dying",
+	}})
+}
+
+func (s *PrecheckReportSuite) TestMerge(c *gc.C) {
+	var report, other migration.PrecheckReport
+	report.Add(migration.PrecheckScopeModel, "machine-0", "machine 0 is dying")
+	other.Add(migration.PrecheckScopeTargetController, "model-uuid", "model named \"foo\" already exists")
+	report.Merge(other)
+	c.Check(report.Problems, gc.HasLen, 2)
+	c.Check(report.Problems[1].Scope, gc.Equals, migration.PrecheckScopeTargetController)
+}
+
+func (s *PrecheckReportSuite) TestByEntity(c *gc.C) {
+	var report migration.PrecheckReport
+	report.Add(migration.PrecheckScopeModel, "unit-foo-0", "unit foo/0 is dead")
+	report.Add(migration.PrecheckScopeSourceController, "machine-0", "machine 0 is dying")
+	report.Add(migration.PrecheckScopeModel, "unit-foo-0", "unit foo/0 is upgrading")
+	keys, grouped := report.ByEntity()
+	c.Check(keys, jc.DeepEquals, []string{
+		"model unit-foo-0",
+		"source-controller machine-0",
+	})
+	c.Check(grouped["model unit-foo-0"], gc.HasLen, 2)
+	c.Check(grouped["source-controller machine-0"], gc.HasLen, 1)
+}
+
+func (s *PrecheckReportSuite) TestAsError(c *gc.C) {
+	var report migration.PrecheckReport
+	report.Add(migration.PrecheckScopeModel, "unit-foo-0", "unit foo/0 is dead")
+	report.Add(migration.PrecheckScopeSourceController, "machine-0", "machine 0 is dying")
+	c.Check(report.AsError(), gc.ErrorMatches, `migration prechecks failed:
+model: unit-foo-0: unit foo/0 is dead
+source-controller: machine-0: machine 0 is dying`)
+}
@@ -23,6 +23,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
 	jujuversion "github.com/juju/juju/juju/version"
@@ -231,6 +232,11 @@ const (
 	// provisioning machines.
 	CloudInitUserDataKey = "cloudinit-userdata"
 
+	// LXDProfilesKey is the key to specify the user-defined LXD
+	// profiles, in yaml format, that are applied to the LXD containers
+	// and virtual machines created in the model.
+	LXDProfilesKey = "lxd-profiles"
+
 	// BackupDirKey specifies the backup working directory.
 	BackupDirKey = "backup-dir"
 
@@ -239,6 +245,11 @@ const (
 	// list will be comma separated.
 	ContainerInheritProperiesKey = "container-inherit-properties"
 
+	// ReplaceReclaimedMachinesKey is the key for whether machines whose
+	// spot or preemptible instances are reclaimed by the cloud are
+	// automatically replaced with new machines.
+	ReplaceReclaimedMachinesKey = "replace-reclaimed-machines"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -446,8 +457,10 @@ var defaultConfigValues = map[string]interface{}{
 	EgressSubnets:                "",
 	FanConfig:                    "",
 	CloudInitUserDataKey:         "",
+	LXDProfilesKey:               "",
 	ContainerInheritProperiesKey: "",
 	BackupDirKey:                 "",
+	ReplaceReclaimedMachinesKey:  false,
 
 	// Image and agent streams and URLs.
 	"image-stream":               "released",
@@ -674,6 +687,12 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if raw, ok := cfg.defined[LXDProfilesKey].(string); ok && raw != "" {
+		if _, err := lxdprofile.Parse(raw); err != nil {
+			return errors.Annotate(err, LXDProfilesKey)
+		}
+	}
+
 	if raw, ok := cfg.defined[CloudInitUserDataKey].(string); ok && raw != "" {
 		userDataMap, err := ensureStringMaps(raw)
 		if err != nil {
@@ -1179,6 +1198,14 @@ func (c *Config) TransmitVendorMetrics() bool {
 	}
 }
 
+// ReplaceReclaimedMachines returns whether machines whose instances
+// have been reclaimed by the cloud should be replaced automatically.
+// By default this is false.
+func (c *Config) ReplaceReclaimedMachines() bool {
+	val, _ := c.defined[ReplaceReclaimedMachinesKey].(bool)
+	return val
+}
+
 // ProvisionerHarvestMode reports the harvesting methodology the
 // provisioner should take.
 func (c *Config) ProvisionerHarvestMode() HarvestMode {
@@ -1366,6 +1393,14 @@ func (c *Config) CloudInitUserData() map[string]interface{} {
 	return conformingUserDataMap
 }
 
+// LXDProfiles returns the user-defined LXD profiles that are applied
+// to the LXD containers and virtual machines created in the model.
+func (c *Config) LXDProfiles() lxdprofile.Profiles {
+	// The profiles have already passed Validate()
+	profiles, _ := lxdprofile.Parse(c.asString(LXDProfilesKey))
+	return profiles
+}
+
 // ContainerInheritProperies returns a copy of the raw user data keys
 // that were specified by the user.
 func (c *Config) ContainerInheritProperies() string {
@@ -1497,8 +1532,10 @@ var alwaysOptional = schema.Defaults{
 	EgressSubnets:                schema.Omit,
 	FanConfig:                    schema.Omit,
 	CloudInitUserDataKey:         schema.Omit,
+	LXDProfilesKey:               schema.Omit,
 	ContainerInheritProperiesKey: schema.Omit,
 	BackupDirKey:                 schema.Omit,
+	ReplaceReclaimedMachinesKey:  schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -1993,6 +2030,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LXDProfilesKey: {
+		Description: "LXD profiles (in yaml format, by name) to be applied to new LXD containers and virtual machines created in this model",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	ContainerInheritProperiesKey: {
 		Description: "List of properties to be copied from the host machine to new containers created in this model (comma-separated)",
 		Type:        environschema.Tstring,
@@ -2003,4 +2045,9 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	ReplaceReclaimedMachinesKey: {
+		Description: "Whether to automatically replace machines, hosting only stateless units, whose spot or preemptible instances are reclaimed by the cloud",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 }
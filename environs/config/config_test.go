@@ -20,6 +20,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
 	jujuversion "github.com/juju/juju/juju/version"
@@ -565,6 +566,19 @@ var configTests = []configTest{
 			"container-inherit-properties": "apt-security, write_files,users,apt-sources",
 		}),
 		err: `container-inherit-properties: users, write_files not allowed`,
+	}, {
+		about:       "Valid lxd-profiles",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"lxd-profiles": "iscsi:\n  config:\n    linux.kernel_modules: iscsi_tcp\n",
+		}),
+	}, {
+		about:       "Invalid lxd-profiles",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"lxd-profiles": "default:\n  config: {}\n",
+		}),
+		err: `lxd-profiles: LXD profile name "default", which is reserved, not valid`,
 	}, {
 		about:       "String as valid value",
 		useDefaults: config.UseDefaults,
@@ -1114,6 +1128,17 @@ func (s *ConfigSuite) TestAutoHookRetryTrueEnv(c *gc.C) {
 	c.Assert(config.AutomaticallyRetryHooks(), gc.Equals, true)
 }
 
+func (s *ConfigSuite) TestReplaceReclaimedMachinesDefault(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.ReplaceReclaimedMachines(), gc.Equals, false)
+}
+
+func (s *ConfigSuite) TestReplaceReclaimedMachinesTrue(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{
+		"replace-reclaimed-machines": "true"})
+	c.Assert(config.ReplaceReclaimedMachines(), gc.Equals, true)
+}
+
 func (s *ConfigSuite) TestNoBothProxy(c *gc.C) {
 	config := newTestConfig(c, testing.Attrs{
 		"http-proxy":  "http://user@10.0.0.1",
@@ -1344,6 +1369,18 @@ func (s *ConfigSuite) TestContainerInheritProperies(c *gc.C) {
 	c.Assert(cfg.ContainerInheritProperies(), gc.Equals, "ca-certs,apt-primary")
 }
 
+func (s *ConfigSuite) TestLXDProfiles(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"lxd-profiles": "iscsi:\n  config:\n    linux.kernel_modules: iscsi_tcp\n",
+	})
+	c.Assert(cfg.LXDProfiles(), jc.DeepEquals, lxdprofile.Profiles{
+		"iscsi": {Config: map[string]string{"linux.kernel_modules": "iscsi_tcp"}},
+	})
+
+	cfg = newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.LXDProfiles(), gc.HasLen, 0)
+}
+
 func (s *ConfigSuite) TestSchemaNoExtra(c *gc.C) {
 	schema, err := config.Schema(nil)
 	c.Assert(err, gc.IsNil)
@@ -445,6 +445,36 @@ type InstanceTagger interface {
 	TagInstance(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) error
 }
 
+// InstanceReclaimDetector is an interface that can be used for finding
+// out which instances, started on interruptible (spot or preemptible)
+// capacity, have been reclaimed by the cloud. Reclaimed instances are
+// not reported by Environ.Instances.
+type InstanceReclaimDetector interface {
+	// ReclaimedInstances returns the IDs of those of the specified
+	// instances that have been reclaimed by the cloud.
+	ReclaimedInstances(ctx context.ProviderCallContext, ids []instance.Id) ([]instance.Id, error)
+}
+
+// TagUpdater is an interface that can be used for reconciling the
+// tags of instances and volumes with the tags Juju expects them to
+// have. Tags not named in the desired set are left alone, unless
+// Juju applied them previously; see tags.Diff.
+type TagUpdater interface {
+	// UpdateInstanceTags sets those of the desired tags that are
+	// missing from, or differ on, the specified instance, and
+	// removes those previously applied by Juju that are no longer
+	// desired. It reports whether any tags needed to be changed.
+	UpdateInstanceTags(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) (bool, error)
+
+	// UpdateVolumeTags sets those of the desired tags that are
+	// missing from, or differ on, the volume with the specified
+	// provider ID, and removes those previously applied by Juju
+	// that are no longer desired. It reports whether any tags
+	// needed to be changed. An error satisfying errors.IsNotFound
+	// is returned if the volume is not managed by this provider.
+	UpdateVolumeTags(ctx context.ProviderCallContext, volumeId string, tags map[string]string) (bool, error)
+}
+
 // InstanceTypesFetcher is an interface that allows for instance information from
 // a provider to be obtained.
 type InstanceTypesFetcher interface {
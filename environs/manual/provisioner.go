@@ -51,6 +51,18 @@ type ProvisionMachineArgs struct {
 	// ubuntu user's ~/.ssh/authorized_keys.
 	AuthorizedKeys string
 
+	// JumpHost, if set, is the SSH jump host, in the form
+	// [user@]host[:port], through which the machine is reached.
+	JumpHost string
+
+	// SudoPassword, if set, is given to sudo when the ubuntu user is
+	// initialised, instead of sudo prompting for it on Stdin.
+	SudoPassword string
+
+	// DryRun, if true, causes the provisioner to write what it would
+	// do to Stdout, without changing the machine or the model.
+	DryRun bool
+
 	// WinRM contains keys and client interface api with the remote windows machine
 	WinRM WinRMArgs
 
@@ -4,6 +4,22 @@
 
 package sshprovisioner
 
+import (
+	"github.com/juju/juju/environs/manual"
+)
+
 const (
 	DetectionScript = detectionScript
 )
+
+var CheckReachable = &checkReachable
+
+// InitUbuntuUserWithArgs initialises the ubuntu user as
+// ProvisionMachine does with the given arguments.
+func InitUbuntuUserWithArgs(args manual.ProvisionMachineArgs) error {
+	conn, err := newSSHConnection(args)
+	if err != nil {
+		return err
+	}
+	return initUbuntuUser(conn, args.User, args.AuthorizedKeys, args.SudoPassword, args.Stdin, args.Stdout)
+}
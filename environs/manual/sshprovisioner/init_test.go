@@ -10,6 +10,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/environs/manual/sshprovisioner"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/testing"
@@ -169,3 +170,36 @@ func (s *initialisationSuite) TestInitUbuntuUserError(c *gc.C) {
 	err := sshprovisioner.InitUbuntuUser("testhost", "testuser", "", nil, nil)
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 123 \\(failed to create ubuntu user\\)")
 }
+
+func (s *initialisationSuite) TestInitUbuntuUserSudoPassword(c *gc.C) {
+	// sudo is given the password on stdin.
+	defer installFakeSSH(c, "sekrit\n", "", 0)()
+	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
+	err := sshprovisioner.InitUbuntuUserWithArgs(manual.ProvisionMachineArgs{
+		Host:         "testhost",
+		User:         "testuser",
+		SudoPassword: "sekrit",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *initialisationSuite) TestInitUbuntuUserJumpHost(c *gc.C) {
+	// The fake ssh reports its arguments in the error.
+	defer installFakeSSH(c, "sekrit\n", []string{"", "$*"}, 1)()
+	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
+	err := sshprovisioner.InitUbuntuUserWithArgs(manual.ProvisionMachineArgs{
+		Host:         "testhost",
+		User:         "testuser",
+		JumpHost:     "admin@bastion:2222",
+		SudoPassword: "sekrit",
+	})
+	c.Assert(err, gc.ErrorMatches, `(?s)subprocess encountered error code 1 \(.*ProxyCommand.*-p.*2222.*-W.*admin@bastion.* testuser@testhost sudo -S -p '' /bin/bash -c .*\)`)
+}
+
+func (s *initialisationSuite) TestInitUbuntuUserInvalidJumpHost(c *gc.C) {
+	err := sshprovisioner.InitUbuntuUserWithArgs(manual.ProvisionMachineArgs{
+		Host:     "testhost",
+		JumpHost: "admin@",
+	})
+	c.Assert(err, gc.ErrorMatches, `jump host "admin@" not valid`)
+}
@@ -5,7 +5,11 @@
 package sshprovisioner
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/juju/loggo"
+	"github.com/juju/utils"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/manual"
@@ -28,16 +32,29 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 		}
 	}()
 
+	conn, err := newSSHConnection(args)
+	if err != nil {
+		return "", err
+	}
+	if args.JumpHost != "" {
+		if err := checkReachable(args.JumpHost, args.Host); err != nil {
+			return "", err
+		}
+	}
+	if args.DryRun {
+		return "", dryRun(conn, args)
+	}
+
 	// Create the "ubuntu" user and initialise passwordless sudo. We populate
 	// the ubuntu user's authorized_keys file with the public keys in the current
 	// user's ~/.ssh directory. The authenticationworker will later update the
 	// ubuntu user's authorized_keys.
-	if err = InitUbuntuUser(args.Host, args.User,
-		args.AuthorizedKeys, args.Stdin, args.Stdout); err != nil {
+	if err = initUbuntuUser(conn, args.User, args.AuthorizedKeys,
+		args.SudoPassword, args.Stdin, args.Stdout); err != nil {
 		return "", err
 	}
 
-	machineParams, err := gatherMachineParams(args.Host)
+	machineParams, err := gatherMachineParams(conn)
 	if err != nil {
 		return "", err
 	}
@@ -60,7 +77,7 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	}
 
 	// Finally, provision the machine agent.
-	err = runProvisionScript(provisioningScript, args.Host, args.Stderr)
+	err = runProvisionScript(provisioningScript, conn, args.Stderr)
 	if err != nil {
 		return machineId, err
 	}
@@ -68,3 +85,59 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	logger.Infof("Provisioned machine %v", machineId)
 	return machineId, nil
 }
+
+// dryRunMachineId stands in for the machine's id in the provisioning
+// script shown by a dry run, since the real id is only allocated when
+// the machine is added to the model.
+const dryRunMachineId = "0"
+
+// dryRun writes what provisioning the machine would do to args.Stdout,
+// without changing the machine or the model.
+func dryRun(conn sshConnection, args manual.ProvisionMachineArgs) error {
+	w := args.Stdout
+	if ubuntuUserInitialised(conn) {
+		fmt.Fprintf(w, "The ubuntu user on %s is already initialised.\n", conn.host)
+	} else {
+		// The machine can be inspected without root, so the
+		// specified login is used until the ubuntu user exists.
+		conn = conn.as(args.User)
+		script := fmt.Sprintf(initUbuntuScript, utils.ShQuote(args.AuthorizedKeys))
+		fmt.Fprintf(w, "The ubuntu user would be initialised by running, with sudo as %s:\n%s\n\n",
+			conn.target(), strings.TrimSpace(script))
+	}
+	machineParams, err := gatherMachineParams(conn)
+	if err != nil {
+		return err
+	}
+	addrs := make([]string, len(machineParams.Addrs))
+	for i, addr := range machineParams.Addrs {
+		addrs[i] = addr.Value
+	}
+	fmt.Fprintf(w, "A machine would be added to the model with:\n")
+	fmt.Fprintf(w, "  instance id: %s\n", machineParams.InstanceId)
+	fmt.Fprintf(w, "  series:      %s\n", machineParams.Series)
+	fmt.Fprintf(w, "  hardware:    %s\n", machineParams.HardwareCharacteristics)
+	fmt.Fprintf(w, "  addresses:   %s\n", strings.Join(addrs, ", "))
+
+	var arch string
+	if machineParams.HardwareCharacteristics.Arch != nil {
+		arch = *machineParams.HardwareCharacteristics.Arch
+	}
+	provisioningScript, err := args.Client.ProvisioningScript(params.ProvisioningScriptParams{
+		MachineId:              dryRunMachineId,
+		Nonce:                  machineParams.Nonce,
+		DisablePackageCommands: !args.EnableOSRefreshUpdate && !args.EnableOSUpgrade,
+		DryRun:                 true,
+		Series:                 machineParams.Series,
+		Arch:                   arch,
+	})
+	if err != nil {
+		logger.Errorf("cannot obtain provisioning script")
+		return err
+	}
+	fmt.Fprintf(w, "\nThe machine agent would then be installed by running, with sudo as %s,\n", conn.as("ubuntu").target())
+	fmt.Fprintf(w, "the following script. It is shown for machine %s; the machine's real id,\n", dryRunMachineId)
+	fmt.Fprintf(w, "and the agent's password, are allocated when the machine is added:\n%s\n",
+		strings.TrimSpace(provisioningScript))
+	return nil
+}
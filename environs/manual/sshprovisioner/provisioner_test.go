@@ -5,9 +5,11 @@
 package sshprovisioner_test
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/shell"
 	"github.com/juju/version"
@@ -187,3 +189,53 @@ func (s *provisionerSuite) TestProvisioningScript(c *gc.C) {
 	expectedScript := removeLogFile + shell.DumpFileOnErrorScript("/var/log/cloud-init-output.log") + provisioningScript
 	c.Assert(script, gc.Equals, expectedScript)
 }
+
+func (s *provisionerSuite) TestProvisionMachineDryRun(c *gc.C) {
+	var series = jujuversion.SupportedLTS()
+	const arch = "amd64"
+
+	args := s.getArgs(c)
+	args.DryRun = true
+	var stdout bytes.Buffer
+	args.Stdout = &stdout
+
+	defer fakeSSH{
+		Series:             series,
+		Arch:               arch,
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+	}.install(c).Restore()
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := sshprovisioner.ProvisionMachine(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, "")
+	c.Assert(stdout.String(), gc.Matches, `(?s)The ubuntu user on .* is already initialised.
+A machine would be added to the model with:
+  instance id: manual:`+args.Host+`
+  series:      `+series+`
+  hardware:    arch=amd64 .*
+The machine agent would then be installed by running, with sudo as ubuntu@.*,
+the following script. It is shown for machine 0; .*
+rm -f '/var/log/cloud-init-output.log'
+.*jujud-machine-0.*`)
+
+	// Nothing was added to the model.
+	after, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(after, gc.HasLen, len(machines))
+}
+
+func (s *provisionerSuite) TestProvisionMachineJumpHostUnreachable(c *gc.C) {
+	var calls []string
+	s.PatchValue(sshprovisioner.CheckReachable, func(jumpHost, host string) error {
+		calls = append(calls, jumpHost, host)
+		return errors.New("cannot reach it")
+	})
+	args := s.getArgs(c)
+	args.JumpHost = "admin@bastion"
+	_, err := sshprovisioner.ProvisionMachine(args)
+	c.Assert(err, gc.ErrorMatches, "cannot reach it")
+	c.Assert(calls, jc.DeepEquals, []string{"admin@bastion", args.Host})
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -24,10 +25,72 @@ import (
 	"github.com/juju/juju/cloudconfig/sshinit"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	jujussh "github.com/juju/juju/network/ssh"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/state/multiwatcher"
 )
 
+// sshConnection describes how to reach the machine being
+// provisioned over SSH.
+type sshConnection struct {
+	// host is the machine's hostname or address, without a user.
+	host string
+
+	// user is the user to log in as. If it is empty, ssh
+	// chooses the user.
+	user string
+
+	// proxyCommand, if set, is the ssh ProxyCommand with
+	// which the machine is reached, e.g. through a jump host.
+	proxyCommand []string
+}
+
+// newSSHConnection returns the connection, as the ubuntu user, to
+// the machine being provisioned with the given arguments.
+func newSSHConnection(args manual.ProvisionMachineArgs) (sshConnection, error) {
+	conn := sshConnection{host: args.Host, user: "ubuntu"}
+	if args.JumpHost != "" {
+		proxyCommand, err := jujussh.ProxyJumpCommand(args.JumpHost)
+		if err != nil {
+			return sshConnection{}, errors.Trace(err)
+		}
+		conn.proxyCommand = proxyCommand
+	}
+	return conn, nil
+}
+
+// as returns a copy of the connection that logs in as the given user.
+func (c sshConnection) as(user string) sshConnection {
+	c.user = user
+	return c
+}
+
+// target returns the [user@]host to connect to.
+func (c sshConnection) target() string {
+	if c.user == "" {
+		return c.host
+	}
+	return c.user + "@" + c.host
+}
+
+// options returns a new set of options for connecting to the machine.
+func (c sshConnection) options() *ssh.Options {
+	var options ssh.Options
+	if len(c.proxyCommand) > 0 {
+		options.SetProxyCommand(c.proxyCommand...)
+	}
+	return &options
+}
+
+// command returns a command to run on the machine.
+func (c sshConnection) command(args []string, options *ssh.Options) *ssh.Cmd {
+	if options == nil {
+		options = c.options()
+	}
+	return ssh.Command(c.target(), args, options)
+}
+
 // InitUbuntuUser adds the ubuntu user if it doesn't
 // already exist, updates its ~/.ssh/authorized_keys,
 // and enables passwordless sudo for it.
@@ -40,31 +103,39 @@ import (
 // authorizedKeys may be empty, in which case the file
 // will be created and left empty.
 func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io.Writer) error {
-	logger.Infof("initialising %q, user %q", host, login)
+	conn := sshConnection{host: host, user: "ubuntu"}
+	return initUbuntuUser(conn, login, authorizedKeys, "", read, write)
+}
+
+// initUbuntuUser does the work of InitUbuntuUser. If sudoPassword is
+// not empty, it is given to sudo on stdin; otherwise a PTY is
+// allocated so that sudo can prompt for the password.
+func initUbuntuUser(conn sshConnection, login, authorizedKeys, sudoPassword string, read io.Reader, write io.Writer) error {
+	logger.Infof("initialising %q, user %q", conn.host, login)
 
 	// To avoid unnecessary prompting for the specified login,
 	// initUbuntuUser will first attempt to ssh to the machine
 	// as "ubuntu" with password authentication disabled, and
 	// ensure that it can use sudo without a password.
-	//
-	// Note that we explicitly do not allocate a PTY, so we
-	// get a failure if sudo prompts.
-	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "-n", "true"}, nil)
-	if cmd.Run() == nil {
+	if ubuntuUserInitialised(conn) {
 		logger.Infof("ubuntu user is already initialised")
 		return nil
 	}
 
 	// Failed to login as ubuntu (or passwordless sudo is not enabled).
 	// Use specified login, and execute the initUbuntuScript below.
-	if login != "" {
-		host = login + "@" + host
-	}
 	script := fmt.Sprintf(initUbuntuScript, utils.ShQuote(authorizedKeys))
-	var options ssh.Options
+	options := conn.options()
 	options.AllowPasswordAuthentication()
-	options.EnablePTY()
-	cmd = ssh.Command(host, []string{"sudo", "/bin/bash -c " + utils.ShQuote(script)}, &options)
+	sudo := []string{"sudo"}
+	if sudoPassword != "" {
+		// sudo reads the password from stdin, without a prompt.
+		sudo = []string{"sudo", "-S", "-p", "''"}
+		read = strings.NewReader(sudoPassword + "\n")
+	} else {
+		options.EnablePTY()
+	}
+	cmd := conn.as(login).command(append(sudo, "/bin/bash -c "+utils.ShQuote(script)), options)
 	var stderr bytes.Buffer
 	cmd.Stdin = read
 	cmd.Stdout = write
@@ -78,6 +149,15 @@ func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io
 	return nil
 }
 
+// ubuntuUserInitialised reports whether the ubuntu user can log in
+// to the machine and use sudo without a password.
+func ubuntuUserInitialised(conn sshConnection) bool {
+	// Note that we explicitly do not allocate a PTY, so we
+	// get a failure if sudo prompts.
+	cmd := conn.as("ubuntu").command([]string{"sudo", "-n", "true"}, nil)
+	return cmd.Run() == nil
+}
+
 const initUbuntuScript = `
 set -e
 (grep ubuntu /etc/group) || groupadd ubuntu
@@ -93,14 +173,34 @@ if [ ! -z "$authorized_keys" ]; then
     su ubuntu -c 'printf "%%s\n" "$authorized_keys" >> ~/.ssh/authorized_keys'
 fi`
 
+// jumpHostTimeout is how long to wait for an SSH server on the
+// machine to answer through a jump host.
+const jumpHostTimeout = 30 * time.Second
+
+// checkReachable checks that an SSH server on the host answers
+// through the jump host.
+var checkReachable = func(jumpHost, host string) error {
+	dialer, err := jujussh.NewProxyJumpDialer(jumpHost, jumpHostTimeout)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	checker := jujussh.NewReachableChecker(dialer, jumpHostTimeout)
+	if _, err := checker.FindHost(network.NewHostPorts(22, host), nil); err != nil {
+		return errors.Annotatef(err, "cannot reach %s through jump host %s", host, jumpHost)
+	}
+	return nil
+}
+
 // DetectSeriesAndHardwareCharacteristics detects the OS
 // series and hardware characteristics of the remote machine
 // by connecting to the machine and executing a bash script.
-var DetectSeriesAndHardwareCharacteristics = detectSeriesAndHardwareCharacteristics
+var DetectSeriesAndHardwareCharacteristics = func(host string) (instance.HardwareCharacteristics, string, error) {
+	return detectSeriesAndHardwareCharacteristics(sshConnection{host: host, user: "ubuntu"})
+}
 
-func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCharacteristics, series string, err error) {
-	logger.Infof("Detecting series and characteristics on %s", host)
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+func detectSeriesAndHardwareCharacteristics(conn sshConnection) (hc instance.HardwareCharacteristics, series string, err error) {
+	logger.Infof("Detecting series and characteristics on %s", conn.host)
+	cmd := conn.command([]string{"/bin/bash"}, nil)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -161,14 +261,16 @@ func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCh
 
 // CheckProvisioned checks if any juju init service already
 // exist on the host machine.
-var CheckProvisioned = checkProvisioned
+var CheckProvisioned = func(host string) (bool, error) {
+	return checkProvisioned(sshConnection{host: host, user: "ubuntu"})
+}
 
-func checkProvisioned(host string) (bool, error) {
-	logger.Infof("Checking if %s is already provisioned", host)
+func checkProvisioned(conn sshConnection) (bool, error) {
+	logger.Infof("Checking if %s is already provisioned", conn.host)
 
 	script := service.ListServicesScript()
 
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd := conn.command([]string{"/bin/bash"}, nil)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -206,11 +308,12 @@ grep MemTotal /proc/meminfo
 cat /proc/cpuinfo`
 
 // gatherMachineParams collects all the information we know about the machine
-// we are about to provision. It will SSH into that machine as the ubuntu user.
-// The hostname supplied should not include a username.
+// we are about to provision. It will SSH into that machine as the connection's
+// user, usually the ubuntu user.
 // If we can, we will reverse lookup the hostname by its IP address, and use
 // the DNS resolved name, rather than the name that was supplied
-func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
+func gatherMachineParams(conn sshConnection) (*params.AddMachineParams, error) {
+	hostname := conn.host
 
 	// Generate a unique nonce for the machine.
 	uuid, err := utils.NewUUID()
@@ -223,7 +326,7 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		return nil, errors.Annotatef(err, "failed to compute public address for %q", hostname)
 	}
 
-	provisioned, err := checkProvisioned(hostname)
+	provisioned, err := checkProvisioned(conn)
 	if err != nil {
 		return nil, errors.Annotatef(err, "error checking if provisioned")
 	}
@@ -231,7 +334,7 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		return nil, manual.ErrProvisioned
 	}
 
-	hc, series, err := DetectSeriesAndHardwareCharacteristics(hostname)
+	hc, series, err := detectSeriesAndHardwareCharacteristics(conn)
 	if err != nil {
 		return nil, errors.Annotatef(err, "error detecting linux hardware characteristics")
 	}
@@ -254,9 +357,10 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 	return machineParams, nil
 }
 
-func runProvisionScript(script, host string, progressWriter io.Writer) error {
+func runProvisionScript(script string, conn sshConnection, progressWriter io.Writer) error {
 	params := sshinit.ConfigureParams{
-		Host:           "ubuntu@" + host,
+		Host:           conn.target(),
+		SSHOptions:     conn.options(),
 		ProgressWriter: progressWriter,
 	}
 	return sshinit.RunConfigureScript(script, params)
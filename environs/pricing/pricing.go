@@ -0,0 +1,137 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package pricing provides access to offline price lists for the
+// instance types and storage offered by clouds, so that the cost of
+// running a model or bundle can be estimated.
+//
+// Price lists are JSON documents in the following format:
+//
+//	{
+//	    "format": "pricing:1.0",
+//	    "updated": "Mon, 01 Oct 2018 00:00:00 +0000",
+//	    "currency": "USD",
+//	    "clouds": {
+//	        "aws": {
+//	            "us-east-1": {
+//	                "instance-types": {"m3.medium": 0.067},
+//	                "storage": {"ebs": 0.1, "default": 0.1}
+//	            }
+//	        }
+//	    }
+//	}
+//
+// Instance types are priced per hour, and storage per GiB per month.
+// Storage is priced by pool name or storage provider type; the
+// "default" entry prices storage for which no pool was specified.
+package pricing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+)
+
+// Format is the only supported format of price lists.
+const Format = "pricing:1.0"
+
+// HoursPerMonth is the number of hours used when converting hourly
+// prices to monthly ones. It is the average number of hours in a
+// month, and matches what most clouds use.
+const HoursPerMonth = 730
+
+// DefaultStorage is the name of the storage entry used to price
+// storage for which no pool was specified.
+const DefaultStorage = "default"
+
+// Catalog is a price list for one or more clouds.
+type Catalog struct {
+	Format   string `json:"format"`
+	Updated  string `json:"updated,omitempty"`
+	Currency string `json:"currency"`
+
+	// Clouds holds the prices for each cloud, keyed by cloud name
+	// and then region name.
+	Clouds map[string]map[string]RegionPrices `json:"clouds"`
+}
+
+// RegionPrices holds the prices of the instance types and storage
+// offered by a cloud region.
+type RegionPrices struct {
+	// InstanceTypes holds the hourly price of each instance type.
+	InstanceTypes map[string]float64 `json:"instance-types,omitempty"`
+
+	// Storage holds the monthly price of one GiB of storage, keyed
+	// by storage pool name or storage provider type.
+	Storage map[string]float64 `json:"storage,omitempty"`
+}
+
+// Parse parses the given price list.
+func Parse(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal price list")
+	}
+	if catalog.Format != Format {
+		return nil, errors.NotSupportedf("price list format %q", catalog.Format)
+	}
+	if catalog.Currency == "" {
+		return nil, errors.NotValidf("price list without currency")
+	}
+	return &catalog, nil
+}
+
+// ReadFile reads and parses the price list in the specified file.
+func ReadFile(path string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	catalog, err := Parse(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading %s", path)
+	}
+	return catalog, nil
+}
+
+// Region returns the prices for the specified cloud region. An error
+// satisfying errors.IsNotFound is returned if the price list has no
+// prices for the region.
+func (c *Catalog) Region(cloud, region string) (RegionPrices, error) {
+	prices, ok := c.Clouds[cloud][region]
+	if !ok {
+		if region == "" {
+			return RegionPrices{}, errors.NotFoundf("prices for cloud %q", cloud)
+		}
+		return RegionPrices{}, errors.NotFoundf("prices for cloud %q region %q", cloud, region)
+	}
+	return prices, nil
+}
+
+// InstanceTypeMonthly returns the monthly price of running an instance
+// of the specified type. An error satisfying errors.IsNotFound is
+// returned if the instance type has no price.
+func (p RegionPrices) InstanceTypeMonthly(instanceType string) (float64, error) {
+	hourly, ok := p.InstanceTypes[instanceType]
+	if !ok {
+		return 0, errors.NotFoundf("price for instance type %q", instanceType)
+	}
+	return hourly * HoursPerMonth, nil
+}
+
+// StorageMonthly returns the monthly price of a volume of the
+// specified size, in MiB, in the first of the given pools or storage
+// provider types that has a price. An error satisfying
+// errors.IsNotFound is returned if none of them has a price.
+func (p RegionPrices) StorageMonthly(sizeMiB uint64, pools ...string) (float64, error) {
+	for _, pool := range pools {
+		if perGiB, ok := p.Storage[pool]; ok {
+			return perGiB * float64(sizeMiB) / 1024, nil
+		}
+	}
+	if len(pools) == 0 {
+		return 0, errors.NotFoundf("price for storage")
+	}
+	return 0, errors.NotFoundf("price for storage pool %q", pools[0])
+}
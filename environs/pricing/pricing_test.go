@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package pricing_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/pricing"
+)
+
+type pricingSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&pricingSuite{})
+
+const priceList = `{
+    "format": "pricing:1.0",
+    "updated": "Mon, 01 Oct 2018 00:00:00 +0000",
+    "currency": "USD",
+    "clouds": {
+        "aws": {
+            "us-east-1": {
+                "instance-types": {"m3.medium": 0.067, "m3.large": 0.133},
+                "storage": {"ebs": 0.1, "ebs-ssd": 0.125, "default": 0.1}
+            }
+        },
+        "maas": {
+            "": {
+                "instance-types": {}
+            }
+        }
+    }
+}`
+
+func (s *pricingSuite) TestParse(c *gc.C) {
+	catalog, err := pricing.Parse([]byte(priceList))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(catalog.Currency, gc.Equals, "USD")
+	c.Assert(catalog.Clouds, gc.HasLen, 2)
+}
+
+func (s *pricingSuite) TestParseErrors(c *gc.C) {
+	_, err := pricing.Parse([]byte(`{`))
+	c.Assert(err, gc.ErrorMatches, "cannot unmarshal price list: .*")
+
+	_, err = pricing.Parse([]byte(`{"format": "pricing:2.0", "currency": "USD"}`))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `price list format "pricing:2.0" not supported`)
+
+	_, err = pricing.Parse([]byte(`{"format": "pricing:1.0"}`))
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *pricingSuite) TestReadFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "pricing.json")
+	err := ioutil.WriteFile(path, []byte(priceList), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	catalog, err := pricing.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(catalog.Currency, gc.Equals, "USD")
+
+	err = ioutil.WriteFile(path, []byte(`{}`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = pricing.ReadFile(path)
+	c.Assert(err, gc.ErrorMatches, `reading .*pricing.json: price list format "" not supported`)
+}
+
+func (s *pricingSuite) TestRegion(c *gc.C) {
+	catalog, err := pricing.Parse([]byte(priceList))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = catalog.Region("aws", "us-east-1")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = catalog.Region("maas", "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = catalog.Region("aws", "eu-west-1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `prices for cloud "aws" region "eu-west-1" not found`)
+	_, err = catalog.Region("lxd", "")
+	c.Assert(err, gc.ErrorMatches, `prices for cloud "lxd" not found`)
+}
+
+func (s *pricingSuite) TestInstanceTypeMonthly(c *gc.C) {
+	catalog, err := pricing.Parse([]byte(priceList))
+	c.Assert(err, jc.ErrorIsNil)
+	prices, err := catalog.Region("aws", "us-east-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	monthly, err := prices.InstanceTypeMonthly("m3.medium")
+	c.Assert(err, jc.ErrorIsNil)
+	hourly := 0.067
+	c.Assert(monthly, gc.Equals, hourly*pricing.HoursPerMonth)
+
+	_, err = prices.InstanceTypeMonthly("m9.huge")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `price for instance type "m9.huge" not found`)
+}
+
+func (s *pricingSuite) TestStorageMonthly(c *gc.C) {
+	catalog, err := pricing.Parse([]byte(priceList))
+	c.Assert(err, jc.ErrorIsNil)
+	prices, err := catalog.Region("aws", "us-east-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	monthly, err := prices.StorageMonthly(10*1024, "ebs-ssd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(monthly, gc.Equals, 1.25)
+
+	// The first pool with a price wins.
+	monthly, err = prices.StorageMonthly(512, "fast", "ebs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(monthly, gc.Equals, 0.05)
+
+	_, err = prices.StorageMonthly(1024, "fast", "cinder")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `price for storage pool "fast" not found`)
+}
@@ -3,7 +3,14 @@
 
 package tags
 
-import "gopkg.in/juju/names.v2"
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+	"gopkg.in/juju/names.v2"
+)
 
 const (
 	// JujuTagPrefix is the prefix for Juju-managed tags.
@@ -40,6 +47,13 @@ const (
 	// the model and machine id corresponding to the
 	// provisioned machine instance.
 	JujuMachine = JujuTagPrefix + "machine-id"
+
+	// JujuResourceTags is the tag name used for recording which of
+	// a resource's tags were applied by Juju from resource-tags
+	// config, so that they can be removed once they are no longer
+	// configured. The value is a space-separated list of the tag
+	// names.
+	JujuResourceTags = JujuTagPrefix + "resource-tags"
 )
 
 // ResourceTagger is an interface that can provide resource tags.
@@ -67,3 +81,66 @@ func ResourceTags(modelTag names.ModelTag, controllerTag names.ControllerTag, ta
 	allTags[JujuController] = controllerTag.Id()
 	return allTags
 }
+
+// Parse parses a space-separated list of k=v pairs, as used for
+// the "resource-tags" configuration, into a map of tags. Tags
+// using the reserved Juju prefix are rejected.
+func Parse(s string) (map[string]string, error) {
+	parsed, err := keyvalues.Parse(strings.Fields(s), true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k := range parsed {
+		if strings.HasPrefix(k, JujuTagPrefix) {
+			return nil, errors.Errorf("tag %q uses reserved prefix %q", k, JujuTagPrefix)
+		}
+	}
+	return parsed, nil
+}
+
+// Diff compares the current tags of a resource with the desired tags,
+// returning the tags that must be set, because they are missing or
+// have a different value, and the names of the tags that must be
+// removed.
+//
+// Only tags that Juju applied, as recorded in the JujuResourceTags
+// tag, are ever removed; other tags may have been set by something
+// other than Juju, and are left alone. The JujuResourceTags tag is
+// itself set or removed as needed to record the desired tags.
+func Diff(current, desired map[string]string) (set map[string]string, remove []string) {
+	want := make(map[string]string)
+	var managed []string
+	for k, v := range desired {
+		want[k] = v
+		if !strings.HasPrefix(k, JujuTagPrefix) {
+			managed = append(managed, k)
+		}
+	}
+	if len(managed) > 0 {
+		sort.Strings(managed)
+		want[JujuResourceTags] = strings.Join(managed, " ")
+	}
+
+	set = make(map[string]string)
+	for k, v := range want {
+		if cur, ok := current[k]; !ok || cur != v {
+			set[k] = v
+		}
+	}
+	for _, k := range strings.Fields(current[JujuResourceTags]) {
+		if strings.HasPrefix(k, JujuTagPrefix) {
+			continue
+		}
+		if _, ok := want[k]; ok {
+			continue
+		}
+		if _, ok := current[k]; ok {
+			remove = append(remove, k)
+		}
+	}
+	if _, ok := current[JujuResourceTags]; ok && len(managed) == 0 {
+		remove = append(remove, JujuResourceTags)
+	}
+	sort.Strings(remove)
+	return set, remove
+}
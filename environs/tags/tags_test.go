@@ -61,6 +61,97 @@ func (*tagsSuite) TestResourceTagsResourceTaggers(c *gc.C) {
 	})
 }
 
+func (*tagsSuite) TestParse(c *gc.C) {
+	parsed, err := tags.Parse(" team=web  cost-centre=42 empty= ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parsed, jc.DeepEquals, map[string]string{
+		"team":        "web",
+		"cost-centre": "42",
+		"empty":       "",
+	})
+
+	parsed, err = tags.Parse("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parsed, gc.HasLen, 0)
+}
+
+func (*tagsSuite) TestParseErrors(c *gc.C) {
+	_, err := tags.Parse("team")
+	c.Assert(err, gc.ErrorMatches, `expected "key=value", got "team"`)
+	_, err = tags.Parse("team=web team=db")
+	c.Assert(err, gc.ErrorMatches, `key "team" specified more than once`)
+	_, err = tags.Parse("juju-model-uuid=foo")
+	c.Assert(err, gc.ErrorMatches, `tag "juju-model-uuid" uses reserved prefix "juju-"`)
+}
+
+func (*tagsSuite) TestDiff(c *gc.C) {
+	set, remove := tags.Diff(map[string]string{
+		"same":    "value",
+		"changed": "old",
+		"other":   "unmanaged",
+	}, map[string]string{
+		"same":    "value",
+		"changed": "new",
+		"missing": "added",
+	})
+	c.Assert(set, jc.DeepEquals, map[string]string{
+		"changed":            "new",
+		"missing":            "added",
+		"juju-resource-tags": "changed missing same",
+	})
+	c.Assert(remove, gc.HasLen, 0)
+
+	set, remove = tags.Diff(map[string]string{"a": "b"}, nil)
+	c.Assert(set, gc.HasLen, 0)
+	c.Assert(remove, gc.HasLen, 0)
+}
+
+func (*tagsSuite) TestDiffNoDrift(c *gc.C) {
+	set, remove := tags.Diff(map[string]string{
+		"juju-model-uuid":    "uuid",
+		"juju-resource-tags": "team",
+		"team":               "web",
+		"other":              "unmanaged",
+	}, map[string]string{
+		"juju-model-uuid": "uuid",
+		"team":            "web",
+	})
+	c.Assert(set, gc.HasLen, 0)
+	c.Assert(remove, gc.HasLen, 0)
+}
+
+func (*tagsSuite) TestDiffRemovesAppliedTags(c *gc.C) {
+	set, remove := tags.Diff(map[string]string{
+		"juju-model-uuid":    "uuid",
+		"juju-resource-tags": "cost-centre gone team",
+		"cost-centre":        "42",
+		"team":               "web",
+		"other":              "unmanaged",
+	}, map[string]string{
+		"juju-model-uuid": "uuid",
+		"team":            "web",
+	})
+	c.Assert(set, jc.DeepEquals, map[string]string{
+		"juju-resource-tags": "team",
+	})
+	// Only tags that Juju applied, and which are still present,
+	// are removed.
+	c.Assert(remove, jc.DeepEquals, []string{"cost-centre"})
+}
+
+func (*tagsSuite) TestDiffRemovesAllAppliedTags(c *gc.C) {
+	set, remove := tags.Diff(map[string]string{
+		"juju-model-uuid":    "uuid",
+		"juju-resource-tags": "juju-model-uuid team",
+		"team":               "web",
+	}, map[string]string{
+		"juju-model-uuid": "uuid",
+	})
+	c.Assert(set, gc.HasLen, 0)
+	// Juju's own tags are never removed.
+	c.Assert(remove, jc.DeepEquals, []string{"juju-resource-tags", "team"})
+}
+
 func testResourceTags(c *gc.C, controller names.ControllerTag, model names.ModelTag, taggers []tags.ResourceTagger, expectTags map[string]string) {
 	tags := tags.ResourceTags(model, controller, taggers...)
 	c.Assert(tags, jc.DeepEquals, expectTags)
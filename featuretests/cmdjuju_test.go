@@ -110,6 +110,13 @@ func (s *cmdJujuSuite) TestApplicationUnset(c *gc.C) {
 func (s *cmdJujuSuite) TestApplicationGetIAASModel(c *gc.C) {
 	expected := `application: dummy-application
 application-config:
+  resource-tags:
+    default: ""
+    description: Space-separated list of k=v pairs, defining the tags to set on the
+      application's machines and volumes, in addition to the model's resource-tags
+    source: default
+    type: string
+    value: ""
   trust:
     default: false
     description: Does this application have access to trusted credentials
@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/authentication"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/network"
 )
@@ -117,6 +118,9 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 	if host := st.PublicDNSName(); host != "" {
 		params.PublicDNSName = &host
 	}
+	if caCert := st.ControllerCACert(); caCert != "" {
+		params.CACert = &caCert
+	}
 	err = updateControllerDetailsFromLogin(args.Store, args.ControllerName, controller, params)
 	if err != nil {
 		logger.Errorf("cannot cache API addresses: %v", err)
@@ -181,7 +185,9 @@ func connectionInfo(args NewAPIConnectionParams) (*api.Info, *jujuclient.Control
 	account := args.AccountDetails
 	if account.User != "" {
 		userTag := names.NewUserTag(account.User)
-		if userTag.IsLocal() {
+		// OIDC users log in with an ID token as credentials,
+		// so they identify themselves like local users.
+		if userTag.IsLocal() || userTag.Domain() == authentication.OIDCUserDomain {
 			apiInfo.Tag = userTag
 		}
 	}
@@ -240,6 +246,10 @@ type UpdateControllerParams struct {
 	// PublicDNSName (when set) holds the public host name of the controller.
 	PublicDNSName *string
 
+	// CACert (when set) holds the CA certificates trusted by the
+	// controller, which change when the controller CA is rotated.
+	CACert *string
+
 	// ControllerMachineCount (when set) is the total number of controller machines in the environment.
 	ControllerMachineCount *int
 
@@ -294,6 +304,9 @@ func updateControllerDetailsFromLogin(
 	if params.PublicDNSName != nil {
 		newDetails.PublicDNSName = *params.PublicDNSName
 	}
+	if params.CACert != nil {
+		newDetails.CACert = *params.CACert
+	}
 	if reflect.DeepEqual(newDetails, details) {
 		// Nothing has changed - no need to update the controller details.
 		return nil
@@ -151,6 +151,20 @@ func (s *NewAPIClientSuite) TestUpdatesPublicDNSName(c *gc.C) {
 	c.Assert(store.Controllers["controllername"].PublicDNSName, gc.Equals, "somewhere.invalid")
 }
 
+func (s *NewAPIClientSuite) TestUpdatesCACert(c *gc.C) {
+	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
+		conn := mockedAPIState(noFlags)
+		conn.caCert = "rotated-ca-bundle"
+		conn.addr = "0.1.2.3:1234"
+		return conn, nil
+	}
+
+	store := newClientStore(c, "controllername")
+	_, err := newAPIConnectionFromNames(c, "controllername", "", store, apiOpen)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(store.Controllers["controllername"].CACert, gc.Equals, "rotated-ca-bundle")
+}
+
 func (s *NewAPIClientSuite) TestWithInfoNoAddresses(c *gc.C) {
 	store := newClientStore(c, "noconfig")
 	err := store.UpdateController("noconfig", jujuclient.ControllerDetails{
@@ -26,6 +26,7 @@ type mockAPIState struct {
 	modelTag      string
 	controllerTag string
 	publicDNSName string
+	caCert        string
 }
 
 type mockedStateFlags int
@@ -91,6 +92,10 @@ func (s *mockAPIState) PublicDNSName() string {
 	return s.publicDNSName
 }
 
+func (s *mockAPIState) ControllerCACert() string {
+	return s.caCert
+}
+
 func (s *mockAPIState) APIHostPorts() [][]network.HostPort {
 	return s.apiHostPorts
 }
@@ -63,6 +63,17 @@ func ImportModel(st *state.State, bytes []byte) (*state.Model, *state.State, err
 	return dbModel, dbState, nil
 }
 
+// ValidateImport deserializes a model description from the bytes and
+// checks that it could be imported as a new database model. Nothing is
+// written to the database.
+func ValidateImport(st *state.State, bytes []byte) error {
+	model, err := description.Deserialize(bytes)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(st.ValidateImport(model))
+}
+
 // CharmDownlaoder defines a single method that is used to download a
 // charm from the source controller in a migration.
 type CharmDownloader interface {
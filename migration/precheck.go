@@ -6,6 +6,7 @@ package migration
 import (
 	"fmt"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6"
@@ -34,7 +35,9 @@ type PrecheckBackend interface {
 	AllRelations() ([]PrecheckRelation, error)
 	ControllerBackend() (PrecheckBackend, error)
 	CloudCredential(tag names.CloudCredentialTag) (state.Credential, error)
+	ListResources(string) (resource.ApplicationResources, error)
 	ListPendingResources(string) ([]resource.Resource, error)
+	Charm(*charm.URL) (PrecheckCharm, error)
 }
 
 // Pool defines the interface to a StatePool used by the migration
@@ -48,6 +51,7 @@ type Pool interface {
 type PrecheckModel interface {
 	UUID() string
 	Name() string
+	Type() state.ModelType
 	Owner() names.UserTag
 	Life() state.Life
 	MigrationMode() state.MigrationMode
@@ -74,6 +78,7 @@ type PrecheckApplication interface {
 	CharmURL() (*charm.URL, bool)
 	AllUnits() ([]PrecheckUnit, error)
 	MinUnits() int
+	AgentTools() (*tools.Tools, error)
 }
 
 // PrecheckUnit describes state interface for a unit needed by
@@ -88,6 +93,12 @@ type PrecheckUnit interface {
 	AgentPresence() (bool, error)
 }
 
+// PrecheckCharm describes the state interface for a charm needed by
+// migration prechecks.
+type PrecheckCharm interface {
+	IsUploaded() bool
+}
+
 // PrecheckRelation describes the state interface for relations needed
 // for prechecks.
 type PrecheckRelation interface {
@@ -118,7 +129,35 @@ func SourcePrecheck(
 	modelPresence ModelPresence,
 	controllerPresence ModelPresence,
 ) error {
-	ctx := precheckContext{backend, modelPresence}
+	return errors.Trace(sourcePrecheck(backend, modelPresence, controllerPresence, nil))
+}
+
+// SourcePrecheckReport runs the same checks as SourcePrecheck but
+// doesn't stop at the first failed check. Every problem found is
+// recorded in the returned report. An error is only returned if the
+// checks couldn't be run at all.
+func SourcePrecheckReport(
+	backend PrecheckBackend,
+	modelPresence ModelPresence,
+	controllerPresence ModelPresence,
+) (coremigration.PrecheckReport, error) {
+	var report coremigration.PrecheckReport
+	err := sourcePrecheck(backend, modelPresence, controllerPresence, &report)
+	return report, errors.Trace(err)
+}
+
+func sourcePrecheck(
+	backend PrecheckBackend,
+	modelPresence ModelPresence,
+	controllerPresence ModelPresence,
+	report *coremigration.PrecheckReport,
+) error {
+	ctx := precheckContext{
+		backend:  backend,
+		presence: modelPresence,
+		report:   report,
+		scope:    coremigration.PrecheckScopeModel,
+	}
 	if err := ctx.checkModel(); err != nil {
 		return errors.Trace(err)
 	}
@@ -139,7 +178,9 @@ func SourcePrecheck(
 	if cleanupNeeded, err := backend.NeedsCleanup(); err != nil {
 		return errors.Annotate(err, "checking cleanups")
 	} else if cleanupNeeded {
-		return errors.New("cleanup needed")
+		if err := ctx.fail(ctx.modelTag, errors.New("cleanup needed")); err != nil {
+			return err
+		}
 	}
 
 	// Check the source controller.
@@ -147,7 +188,12 @@ func SourcePrecheck(
 	if err != nil {
 		return errors.Trace(err)
 	}
-	controllerCtx := precheckContext{controllerBackend, controllerPresence}
+	controllerCtx := precheckContext{
+		backend:  controllerBackend,
+		presence: controllerPresence,
+		report:   report,
+		scope:    coremigration.PrecheckScopeSourceController,
+	}
 	if err := controllerCtx.checkController(); err != nil {
 		return errors.Annotate(err, "controller")
 	}
@@ -157,6 +203,30 @@ func SourcePrecheck(
 type precheckContext struct {
 	backend  PrecheckBackend
 	presence ModelPresence
+
+	// report is nil when the prechecks should stop at the first
+	// failed check, otherwise every problem found is added to it.
+	report *coremigration.PrecheckReport
+
+	// scope is used for problems added to report.
+	scope coremigration.PrecheckScope
+
+	// modelTag and modelType identify the model being checked.
+	// They are set once the model has been retrieved.
+	modelTag  names.Tag
+	modelType state.ModelType
+}
+
+// fail handles a failed check for the given entity. If the prechecks
+// are being run to produce a report, the problem is recorded and nil
+// is returned so that checking continues. Otherwise the error is
+// returned unchanged.
+func (ctx *precheckContext) fail(entity names.Tag, err error) error {
+	if ctx.report == nil {
+		return err
+	}
+	ctx.report.Add(ctx.scope, entity.String(), err.Error())
+	return nil
 }
 
 func (ctx *precheckContext) checkModel() error {
@@ -164,11 +234,18 @@ func (ctx *precheckContext) checkModel() error {
 	if err != nil {
 		return errors.Annotate(err, "retrieving model")
 	}
+	ctx.modelTag = names.NewModelTag(model.UUID())
+	ctx.modelType = model.Type()
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		if err := ctx.fail(ctx.modelTag, errors.Errorf("model is %s", model.Life())); err != nil {
+			return err
+		}
 	}
 	if model.MigrationMode() == state.MigrationModeImporting {
-		return errors.New("model is being imported as part of another migration")
+		err := errors.New("model is being imported as part of another migration")
+		if err := ctx.fail(ctx.modelTag, err); err != nil {
+			return err
+		}
 	}
 	if credTag, found := model.CloudCredential(); found {
 		creds, err := ctx.backend.CloudCredential(credTag)
@@ -176,7 +253,13 @@ func (ctx *precheckContext) checkModel() error {
 			return errors.Trace(err)
 		}
 		if creds.Revoked {
-			return errors.New("model has revoked credentials")
+			if err := ctx.fail(credTag, errors.New("model has revoked credentials")); err != nil {
+				return err
+			}
+		} else if !creds.IsValid() {
+			if err := ctx.fail(credTag, errors.New("model has invalid credentials")); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -186,10 +269,45 @@ func (ctx *precheckContext) checkModel() error {
 // sure that the preconditions for model migration are met. The
 // backend provided must be for the target controller.
 func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence) error {
+	return errors.Trace(targetPrecheck(backend, pool, modelInfo, presence, nil))
+}
+
+// TargetPrecheckReport runs the same checks as TargetPrecheck but
+// doesn't stop at the first failed check. Every problem found is
+// recorded in the returned report. An error is only returned if the
+// checks couldn't be run at all.
+func TargetPrecheckReport(
+	backend PrecheckBackend,
+	pool Pool,
+	modelInfo coremigration.ModelInfo,
+	presence ModelPresence,
+) (coremigration.PrecheckReport, error) {
+	var report coremigration.PrecheckReport
+	err := targetPrecheck(backend, pool, modelInfo, presence, &report)
+	return report, errors.Trace(err)
+}
+
+func targetPrecheck(
+	backend PrecheckBackend,
+	pool Pool,
+	modelInfo coremigration.ModelInfo,
+	presence ModelPresence,
+	report *coremigration.PrecheckReport,
+) error {
 	if err := modelInfo.Validate(); err != nil {
 		return errors.Trace(err)
 	}
 
+	controllerCtx := precheckContext{
+		backend:  backend,
+		presence: presence,
+		report:   report,
+		scope:    coremigration.PrecheckScopeTargetController,
+	}
+	// Problems with the incoming model are recorded against it,
+	// rather than against the target controller's model.
+	incomingModel := names.NewModelTag(modelInfo.UUID)
+
 	// This check is necessary because there is a window between the
 	// REAP phase and then end of the DONE phase where a model's
 	// documents have been deleted but the migration isn't quite done
@@ -200,7 +318,10 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 	if migrating, err := backend.IsMigrationActive(modelInfo.UUID); err != nil {
 		return errors.Annotate(err, "checking for active migration")
 	} else if migrating {
-		return errors.New("model is being migrated out of target controller")
+		err := errors.New("model is being migrated out of target controller")
+		if err := controllerCtx.fail(incomingModel, err); err != nil {
+			return err
+		}
 	}
 
 	controllerVersion, err := backend.AgentVersion()
@@ -209,16 +330,21 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 	}
 
 	if controllerVersion.Compare(modelInfo.AgentVersion) < 0 {
-		return errors.Errorf("model has higher version than target controller (%s > %s)",
+		err := errors.Errorf("model has higher version than target controller (%s > %s)",
 			modelInfo.AgentVersion, controllerVersion)
+		if err := controllerCtx.fail(incomingModel, err); err != nil {
+			return err
+		}
 	}
 
 	if !controllerVersionCompatible(modelInfo.ControllerAgentVersion, controllerVersion) {
-		return errors.Errorf("source controller has higher version than target controller (%s > %s)",
+		err := errors.Errorf("source controller has higher version than target controller (%s > %s)",
 			modelInfo.ControllerAgentVersion, controllerVersion)
+		if err := controllerCtx.fail(incomingModel, err); err != nil {
+			return err
+		}
 	}
 
-	controllerCtx := precheckContext{backend, presence}
 	if err := controllerCtx.checkController(); err != nil {
 		return errors.Trace(err)
 	}
@@ -239,10 +365,16 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 		// from a previous migration attempt. It will be removed
 		// before the next import.
 		if model.UUID() == modelInfo.UUID && model.MigrationMode() != state.MigrationModeImporting {
-			return errors.Errorf("model with same UUID already exists (%s)", modelInfo.UUID)
+			err := errors.Errorf("model with same UUID already exists (%s)", modelInfo.UUID)
+			if err := controllerCtx.fail(incomingModel, err); err != nil {
+				return err
+			}
 		}
 		if model.Name() == modelInfo.Name && model.Owner() == modelInfo.Owner {
-			return errors.Errorf("model named %q already exists", model.Name())
+			err := errors.Errorf("model named %q already exists", model.Name())
+			if err := controllerCtx.fail(incomingModel, err); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -271,14 +403,19 @@ func (ctx *precheckContext) checkController() error {
 	if err != nil {
 		return errors.Annotate(err, "retrieving model")
 	}
+	ctx.modelTag = names.NewModelTag(model.UUID())
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		if err := ctx.fail(ctx.modelTag, errors.Errorf("model is %s", model.Life())); err != nil {
+			return err
+		}
 	}
 
 	if upgrading, err := ctx.backend.IsUpgrading(); err != nil {
 		return errors.Annotate(err, "checking for upgrades")
 	} else if upgrading {
-		return errors.New("upgrade in progress")
+		if err := ctx.fail(ctx.modelTag, errors.New("upgrade in progress")); err != nil {
+			return err
+		}
 	}
 
 	return errors.Trace(ctx.checkMachines())
@@ -296,30 +433,43 @@ func (ctx *precheckContext) checkMachines() error {
 	}
 	modelPresenceContext := common.ModelPresenceContext{ctx.presence}
 	for _, machine := range machines {
+		tag := names.NewMachineTag(machine.Id())
 		if machine.Life() != state.Alive {
-			return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+			err := errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+			if err := ctx.fail(tag, err); err != nil {
+				return err
+			}
 		}
 
 		if statusInfo, err := machine.InstanceStatus(); err != nil {
 			return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
 		} else if statusInfo.Status != status.Running {
-			return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
+			err := newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
+			if err := ctx.fail(tag, err); err != nil {
+				return err
+			}
 		}
 
 		if statusInfo, err := modelPresenceContext.MachineStatus(machine); err != nil {
 			return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
 		} else if statusInfo.Status != status.Started {
-			return newStatusError("machine %s agent not functioning at this time",
+			err := newStatusError("machine %s agent not functioning at this time",
 				machine.Id(), statusInfo.Status)
+			if err := ctx.fail(tag, err); err != nil {
+				return err
+			}
 		}
 
 		if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
 			return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
 		} else if rebootAction != state.ShouldDoNothing {
-			return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
+			err := errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
+			if err := ctx.fail(tag, err); err != nil {
+				return err
+			}
 		}
 
-		if err := checkAgentTools(modelVersion, machine, "machine "+machine.Id()); err != nil {
+		if err := ctx.checkAgentTools(modelVersion, machine, tag, "machine "+machine.Id()); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -338,8 +488,27 @@ func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, erro
 
 	appUnits := make(map[string][]PrecheckUnit, len(apps))
 	for _, app := range apps {
+		tag := names.NewApplicationTag(app.Name())
 		if app.Life() != state.Alive {
-			return nil, errors.Errorf("application %s is %s", app.Name(), app.Life())
+			err := errors.Errorf("application %s is %s", app.Name(), app.Life())
+			if err := ctx.fail(tag, err); err != nil {
+				return nil, err
+			}
+		}
+		if err := ctx.checkCharm(app, tag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := ctx.checkResources(app, tag); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ctx.modelType == state.ModelTypeCAAS {
+			// Units of CAAS applications are run by the
+			// application's operator, so it's the operator's
+			// agent binaries that need to match.
+			err := ctx.checkAgentTools(modelVersion, app, tag, "application "+app.Name()+" operator")
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
 		}
 		units, err := app.AllUnits()
 		if err != nil {
@@ -354,35 +523,97 @@ func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, erro
 	return appUnits, nil
 }
 
+func (ctx *precheckContext) checkCharm(app PrecheckApplication, tag names.ApplicationTag) error {
+	curl, _ := app.CharmURL()
+	if curl == nil {
+		return nil
+	}
+	ch, err := ctx.backend.Charm(curl)
+	if errors.IsNotFound(err) {
+		return ctx.fail(tag, errors.Errorf("charm %s for application %s not found", curl, app.Name()))
+	} else if err != nil {
+		return errors.Annotatef(err, "retrieving charm %s", curl)
+	}
+	if !ch.IsUploaded() {
+		return ctx.fail(tag, errors.Errorf("charm %s for application %s is not uploaded", curl, app.Name()))
+	}
+	return nil
+}
+
+// checkResources reports resources of the application that exist only
+// as pending uploads. A pending resource alongside an application
+// resource of the same name is left over from a failed deploy or
+// upgrade, but one with no application resource means the resource's
+// content isn't available: pending resources aren't migrated.
+func (ctx *precheckContext) checkResources(app PrecheckApplication, tag names.ApplicationTag) error {
+	pending, err := ctx.backend.ListPendingResources(app.Name())
+	if err != nil {
+		return errors.Annotatef(err, "retrieving pending resources for %s", app.Name())
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	resources, err := ctx.backend.ListResources(app.Name())
+	if err != nil {
+		return errors.Annotatef(err, "retrieving resources for %s", app.Name())
+	}
+	available := set.NewStrings()
+	for _, res := range resources.Resources {
+		available.Add(res.Name)
+	}
+	for _, res := range pending {
+		if available.Contains(res.Name) {
+			continue
+		}
+		// Only report each missing resource once.
+		available.Add(res.Name)
+		err := errors.Errorf("resource %q of application %s is not available (upload pending)", res.Name, app.Name())
+		if err := ctx.fail(tag, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ctx *precheckContext) checkUnits(app PrecheckApplication, units []PrecheckUnit, modelVersion version.Number) error {
 	if len(units) < app.MinUnits() {
-		return errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		err := errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		if err := ctx.fail(names.NewApplicationTag(app.Name()), err); err != nil {
+			return err
+		}
 	}
 
 	appCharmURL, _ := app.CharmURL()
 
 	for _, unit := range units {
+		tag := names.NewUnitTag(unit.Name())
 		if unit.Life() != state.Alive {
-			return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
+			if err := ctx.fail(tag, errors.Errorf("unit %s is %s", unit.Name(), unit.Life())); err != nil {
+				return err
+			}
 		}
 
-		if err := ctx.checkUnitAgentStatus(unit); err != nil {
+		if err := ctx.checkUnitAgentStatus(unit, tag); err != nil {
 			return errors.Trace(err)
 		}
 
-		if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
-			return errors.Trace(err)
+		if ctx.modelType != state.ModelTypeCAAS {
+			if err := ctx.checkAgentTools(modelVersion, unit, tag, "unit "+unit.Name()); err != nil {
+				return errors.Trace(err)
+			}
 		}
 
 		unitCharmURL, _ := unit.CharmURL()
 		if appCharmURL.String() != unitCharmURL.String() {
-			return errors.Errorf("unit %s is upgrading", unit.Name())
+			if err := ctx.fail(tag, errors.Errorf("unit %s is upgrading", unit.Name())); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
-func (ctx *precheckContext) checkUnitAgentStatus(unit PrecheckUnit) error {
+func (ctx *precheckContext) checkUnitAgentStatus(unit PrecheckUnit, tag names.UnitTag) error {
 	modelPresenceContext := common.ModelPresenceContext{ctx.presence}
 	statusData, _ := modelPresenceContext.UnitStatus(unit)
 	if statusData.Err != nil {
@@ -393,20 +624,25 @@ func (ctx *precheckContext) checkUnitAgentStatus(unit PrecheckUnit) error {
 	case status.Idle, status.Executing:
 		// These two are fine.
 	default:
-		return newStatusError("unit %s not idle or executing", unit.Name(), agentStatus)
+		return ctx.fail(tag, newStatusError("unit %s not idle or executing", unit.Name(), agentStatus))
 	}
 	return nil
 }
 
-func checkAgentTools(modelVersion version.Number, agent agentToolsGetter, agentLabel string) error {
+func (ctx *precheckContext) checkAgentTools(
+	modelVersion version.Number,
+	agent agentToolsGetter,
+	tag names.Tag,
+	agentLabel string,
+) error {
 	tools, err := agent.AgentTools()
 	if err != nil {
 		return errors.Annotatef(err, "retrieving agent binaries for %s", agentLabel)
 	}
 	agentVersion := tools.Version.Number
 	if agentVersion != modelVersion {
-		return errors.Errorf("%s agent binaries don't match model (%s != %s)",
-			agentLabel, agentVersion, modelVersion)
+		return ctx.fail(tag, errors.Errorf("%s agent binaries don't match model (%s != %s)",
+			agentLabel, agentVersion, modelVersion))
 	}
 	return nil
 }
@@ -457,7 +693,10 @@ func (ctx *precheckContext) checkRelations(appUnits map[string][]PrecheckUnit) e
 					return errors.Trace(err)
 				}
 				if !inScope {
-					return errors.Errorf("unit %s hasn't joined relation %s yet", unit.Name(), rel)
+					err := errors.Errorf("unit %s hasn't joined relation %s yet", unit.Name(), rel)
+					if err := ctx.fail(names.NewUnitTag(unit.Name()), err); err != nil {
+						return err
+					}
 				}
 			}
 		}
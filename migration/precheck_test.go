@@ -76,6 +76,11 @@ func (*SourcePrecheckSuite) TestPendingResources(c *gc.C) {
 	backend.pendingResources = []resource.Resource{
 		resourcetesting.NewResource(c, nil, "blob", "foo", "body").Resource,
 	}
+	backend.resources = resource.ApplicationResources{
+		Resources: []resource.Resource{
+			resourcetesting.NewResource(c, nil, "blob", "foo", "body").Resource,
+		},
+	}
 	err := sourcePrecheck(backend)
 	// Pending resources shouldn't prevent a migration. If they exist
 	// alongside an application, they're remains of a previous failed
@@ -86,6 +91,25 @@ func (*SourcePrecheckSuite) TestPendingResources(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (*SourcePrecheckSuite) TestPendingResourceUnavailable(c *gc.C) {
+	backend := newHappyBackend()
+	backend.pendingResources = []resource.Resource{
+		resourcetesting.NewResource(c, nil, "blob", "foo", "body").Resource,
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `resource "blob" of application foo is not available \(upload pending\)`)
+}
+
+func (*SourcePrecheckSuite) TestListResourcesError(c *gc.C) {
+	backend := newHappyBackend()
+	backend.pendingResources = []resource.Resource{
+		resourcetesting.NewResource(c, nil, "blob", "foo", "body").Resource,
+	}
+	backend.resourcesErr = errors.New("boom")
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "retrieving resources for foo: boom")
+}
+
 func (*SourcePrecheckSuite) TestImportingModel(c *gc.C) {
 	backend := newFakeBackend()
 	backend.model.migrationMode = state.MigrationModeImporting
@@ -210,6 +234,37 @@ func (s *SourcePrecheckSuite) TestUnitVersionsDontMatch(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "unit bar/1 agent binaries don't match model (1.2.4 != 1.2.3)")
 }
 
+func (s *SourcePrecheckSuite) TestCAASUnitVersionsIgnored(c *gc.C) {
+	backend := &fakeBackend{
+		model: fakeModel{modelType: state.ModelTypeCAAS},
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name: "gitlab",
+				units: []migration.PrecheckUnit{
+					&fakeUnit{name: "gitlab/0", version: version.MustParseBinary("1.2.4-trusty-ppc64")},
+				},
+			},
+		},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SourcePrecheckSuite) TestCAASOperatorVersionsDontMatch(c *gc.C) {
+	backend := &fakeBackend{
+		model: fakeModel{modelType: state.ModelTypeCAAS},
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:    "gitlab",
+				units:   []migration.PrecheckUnit{&fakeUnit{name: "gitlab/0"}},
+				version: version.MustParseBinary("1.2.4-trusty-ppc64"),
+			},
+		},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application gitlab operator agent binaries don't match model (1.2.4 != 1.2.3)")
+}
+
 func (s *SourcePrecheckSuite) TestDeadUnit(c *gc.C) {
 	backend := &fakeBackend{
 		apps: []migration.PrecheckApplication{
@@ -401,6 +456,112 @@ func (s *SourcePrecheckSuite) TestCrossModelUnitsNotYetInScope(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *SourcePrecheckSuite) TestCharmNotUploaded(c *gc.C) {
+	backend := newHappyBackend()
+	backend.charms = map[string]*fakeCharm{
+		"cs:foo-1": {uploaded: false},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "charm cs:foo-1 for application foo is not uploaded")
+}
+
+func (s *SourcePrecheckSuite) TestCharmNotFound(c *gc.C) {
+	backend := newHappyBackend()
+	backend.charmErr = errors.NotFoundf("charm")
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "charm cs:foo-1 for application foo not found")
+}
+
+func (s *SourcePrecheckSuite) TestCharmError(c *gc.C) {
+	backend := newHappyBackend()
+	backend.charmErr = errors.New("boom")
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "retrieving charm cs:foo-1: boom")
+}
+
+type SourcePrecheckReportSuite struct {
+	precheckBaseSuite
+}
+
+var _ = gc.Suite(&SourcePrecheckReportSuite{})
+
+func (*SourcePrecheckReportSuite) TestSuccess(c *gc.C) {
+	backend := newHappyBackend()
+	backend.controllerBackend = newHappyBackend()
+	report, err := migration.SourcePrecheckReport(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Empty(), jc.IsTrue)
+}
+
+func (*SourcePrecheckReportSuite) TestReportsAllProblems(c *gc.C) {
+	backend := &fakeBackend{
+		cleanupNeeded: true,
+		machines: []migration.PrecheckMachine{
+			&fakeMachine{id: "0", life: state.Dying},
+			&fakeMachine{id: "1", instanceStatus: status.Provisioning},
+		},
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:     "foo",
+				minunits: 2,
+				units: []migration.PrecheckUnit{
+					&fakeUnit{name: "foo/0", agentStatus: status.Failed},
+				},
+			},
+		},
+		pendingResources: []resource.Resource{
+			resourcetesting.NewResource(c, nil, "blob", "foo", "body").Resource,
+		},
+		controllerBackend: newBackendWithRebootingMachine(),
+	}
+	backend.model.uuid = modelUUID
+	backend.controllerBackend.model.uuid = "controller-uuid"
+	backend.controllerBackend.isUpgrading = true
+
+	report, err := migration.SourcePrecheckReport(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Problems, jc.DeepEquals, []coremigration.PrecheckProblem{{
+		Scope:   coremigration.PrecheckScopeModel,
+		Entity:  "machine-0",
+		Message: "machine 0 is dying",
+	}, {
+		Scope:   coremigration.PrecheckScopeModel,
+		Entity:  "machine-1",
+		Message: "machine 1 not running (allocating)",
+	}, {
+		Scope:   coremigration.PrecheckScopeModel,
+		Entity:  "application-foo",
+		Message: `resource "blob" of application foo is not available (upload pending)`,
+	}, {
+		Scope:   coremigration.PrecheckScopeModel,
+		Entity:  "application-foo",
+		Message: "application foo is below its minimum units threshold",
+	}, {
+		Scope:   coremigration.PrecheckScopeModel,
+		Entity:  "unit-foo-0",
+		Message: "unit foo/0 not idle or executing (failed)",
+	}, {
+		Scope:   coremigration.PrecheckScopeModel,
+		Entity:  "model-model-uuid",
+		Message: "cleanup needed",
+	}, {
+		Scope:   coremigration.PrecheckScopeSourceController,
+		Entity:  "model-controller-uuid",
+		Message: "upgrade in progress",
+	}, {
+		Scope:   coremigration.PrecheckScopeSourceController,
+		Entity:  "machine-0",
+		Message: "machine 0 is scheduled to reboot",
+	}})
+}
+
+func (*SourcePrecheckReportSuite) TestRetrievalErrorStillFails(c *gc.C) {
+	backend := newFakeBackend()
+	backend.allMachinesErr = errors.New("boom")
+	_, err := migration.SourcePrecheckReport(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(err, gc.ErrorMatches, "retrieving machines: boom")
+}
+
 type TargetPrecheckSuite struct {
 	precheckBaseSuite
 	modelInfo coremigration.ModelInfo
@@ -628,6 +789,56 @@ func (s *TargetPrecheckSuite) TestUUIDAlreadyExistsButImporting(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *TargetPrecheckSuite) TestReportsAllProblems(c *gc.C) {
+	pool := &fakePool{
+		models: []migration.PrecheckModel{
+			&fakeModel{uuid: modelUUID},
+			&fakeModel{
+				uuid:  "uuid",
+				name:  modelName,
+				owner: modelOwner,
+			},
+		},
+	}
+	backend := newBackendWithDyingMachine()
+	backend.model.uuid = "controller-uuid"
+	backend.models = pool.uuids()
+	backend.migrationActive = true
+	sourceVersion := backendVersion
+	sourceVersion.Patch++
+	s.modelInfo.AgentVersion = sourceVersion
+
+	report, err := migration.TargetPrecheckReport(backend, pool, s.modelInfo, allAlivePresence())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Problems, jc.DeepEquals, []coremigration.PrecheckProblem{{
+		Scope:   coremigration.PrecheckScopeTargetController,
+		Entity:  "model-model-uuid",
+		Message: "model is being migrated out of target controller",
+	}, {
+		Scope:   coremigration.PrecheckScopeTargetController,
+		Entity:  "model-model-uuid",
+		Message: "model has higher version than target controller (1.2.4 > 1.2.3)",
+	}, {
+		Scope:   coremigration.PrecheckScopeTargetController,
+		Entity:  "machine-0",
+		Message: "machine 0 is dying",
+	}, {
+		Scope:   coremigration.PrecheckScopeTargetController,
+		Entity:  "model-model-uuid",
+		Message: "model with same UUID already exists (model-uuid)",
+	}, {
+		Scope:   coremigration.PrecheckScopeTargetController,
+		Entity:  "model-model-uuid",
+		Message: `model named "model-name" already exists`,
+	}})
+}
+
+func (s *TargetPrecheckSuite) TestReportInvalidModelInfo(c *gc.C) {
+	s.modelInfo.UUID = ""
+	_, err := migration.TargetPrecheckReport(newHappyBackend(), nil, s.modelInfo, allAlivePresence())
+	c.Assert(err, gc.ErrorMatches, "empty UUID not valid")
+}
+
 type precheckRunner func(migration.PrecheckBackend) error
 
 type precheckBaseSuite struct {
@@ -760,9 +971,15 @@ type fakeBackend struct {
 	credentials    state.Credential
 	credentialsErr error
 
+	resources    resource.ApplicationResources
+	resourcesErr error
+
 	pendingResources    []resource.Resource
 	pendingResourcesErr error
 
+	charms   map[string]*fakeCharm
+	charmErr error
+
 	controllerBackend *fakeBackend
 }
 
@@ -806,10 +1023,25 @@ func (b *fakeBackend) AllRelations() ([]migration.PrecheckRelation, error) {
 	return b.relations, b.allRelsErr
 }
 
+func (b *fakeBackend) ListResources(app string) (resource.ApplicationResources, error) {
+	return b.resources, b.resourcesErr
+}
+
 func (b *fakeBackend) ListPendingResources(app string) ([]resource.Resource, error) {
 	return b.pendingResources, b.pendingResourcesErr
 }
 
+func (b *fakeBackend) Charm(curl *charm.URL) (migration.PrecheckCharm, error) {
+	if b.charmErr != nil {
+		return nil, b.charmErr
+	}
+	if ch, ok := b.charms[curl.String()]; ok {
+		return ch, nil
+	}
+	// Avoid the need to specify this everywhere.
+	return &fakeCharm{uploaded: true}, nil
+}
+
 func (b *fakeBackend) ControllerBackend() (migration.PrecheckBackend, error) {
 	if b.controllerBackend == nil {
 		return b, nil
@@ -841,6 +1073,7 @@ func (p *fakePool) GetModel(uuid string) (migration.PrecheckModel, func(), error
 type fakeModel struct {
 	uuid          string
 	name          string
+	modelType     state.ModelType
 	owner         names.UserTag
 	life          state.Life
 	migrationMode state.MigrationMode
@@ -855,6 +1088,13 @@ func (m *fakeModel) Name() string {
 	return m.name
 }
 
+func (m *fakeModel) Type() state.ModelType {
+	if m.modelType == "" {
+		return state.ModelTypeIAAS
+	}
+	return m.modelType
+}
+
 func (m *fakeModel) Owner() names.UserTag {
 	return m.owner
 }
@@ -933,12 +1173,21 @@ func (m *fakeMachine) ShouldRebootOrShutdown() (state.RebootAction, error) {
 	return m.rebootAction, nil
 }
 
+type fakeCharm struct {
+	uploaded bool
+}
+
+func (ch *fakeCharm) IsUploaded() bool {
+	return ch.uploaded
+}
+
 type fakeApp struct {
 	name     string
 	life     state.Life
 	charmURL string
 	units    []migration.PrecheckUnit
 	minunits int
+	version  version.Binary
 }
 
 func (a *fakeApp) Name() string {
@@ -965,6 +1214,18 @@ func (a *fakeApp) MinUnits() int {
 	return a.minunits
 }
 
+func (a *fakeApp) AgentTools() (*tools.Tools, error) {
+	// Avoid having to specify the version when it's supposed to match
+	// the model config.
+	v := a.version
+	if v.Compare(version.Zero) == 0 {
+		v = backendVersionBinary
+	}
+	return &tools.Tools{
+		Version: v,
+	}, nil
+}
+
 type fakeUnit struct {
 	name        string
 	version     version.Binary
@@ -85,6 +85,10 @@ type netplanBridger struct {
 	Clock     clock.Clock
 	Directory string
 	Timeout   time.Duration
+
+	// ControllerAddresses are checked for connectivity
+	// once bridges are activated.
+	ControllerAddresses []string
 }
 
 var _ Bridger = (*netplanBridger)(nil)
@@ -95,10 +99,11 @@ func (b *netplanBridger) Bridge(devices []DeviceToBridge, reconfigureDelay int)
 		npDevices[i] = netplan.DeviceToBridge(device)
 	}
 	params := netplan.ActivationParams{
-		Clock:     clock.WallClock,
-		Directory: b.Directory,
-		Devices:   npDevices,
-		Timeout:   b.Timeout,
+		Clock:               clock.WallClock,
+		Directory:           b.Directory,
+		Devices:             npDevices,
+		Timeout:             b.Timeout,
+		ControllerAddresses: b.ControllerAddresses,
 	}
 
 	result, err := netplan.BridgeAndActivate(params)
@@ -121,16 +126,19 @@ func (b *netplanBridger) Bridge(devices []DeviceToBridge, reconfigureDelay int)
 	return nil
 }
 
-func newNetplanBridger(clock clock.Clock, timeout time.Duration, directory string) Bridger {
+func newNetplanBridger(clock clock.Clock, timeout time.Duration, directory string, controllerAddresses []string) Bridger {
 	return &netplanBridger{
-		Clock:     clock,
-		Directory: directory,
-		Timeout:   timeout,
+		Clock:               clock,
+		Directory:           directory,
+		Timeout:             timeout,
+		ControllerAddresses: controllerAddresses,
 	}
 }
 
 // DefaultNetplanBridger returns a Bridger instance that can parse a set
 // of netplan yaml files to transform existing devices into bridged devices.
-func DefaultNetplanBridger(timeout time.Duration, directory string) (Bridger, error) {
-	return newNetplanBridger(clock.WallClock, timeout, directory), nil
+// If any controller addresses are supplied, the previous configuration is
+// restored if none of them can be reached once the bridges are activated.
+func DefaultNetplanBridger(timeout time.Duration, directory string, controllerAddresses []string) (Bridger, error) {
+	return newNetplanBridger(clock.WallClock, timeout, directory, controllerAddresses), nil
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/netplan"
 	// Used for some constants and things like LinkLayerDevice[Args]
 	"github.com/juju/juju/state"
 )
@@ -164,6 +165,21 @@ func possibleBridgeTarget(dev *state.LinkLayerDevice) (bool, error) {
 	return false, nil
 }
 
+// bridgeTargetType returns the netplan type of a device that may be
+// bridged, so that the host need not infer it from the device's name
+// or MAC address, which VLANs often share with their parent device.
+func bridgeTargetType(dev *state.LinkLayerDevice) netplan.DeviceType {
+	switch dev.Type() {
+	case state.EthernetDevice:
+		return netplan.TypeEthernet
+	case state.BondDevice:
+		return netplan.TypeBond
+	case state.VLAN_8021QDevice:
+		return netplan.TypeVLAN
+	}
+	return ""
+}
+
 func formatDeviceMap(spacesToDevices map[string][]*state.LinkLayerDevice) string {
 	spaceNames := make([]string, len(spacesToDevices))
 	i := 0
@@ -321,10 +337,12 @@ func (b *BridgePolicy) FindMissingBridgesForContainer(m Machine, containerMachin
 
 	hostToBridge := make([]network.DeviceToBridge, 0, len(hostDeviceNamesToBridge))
 	for _, hostName := range network.NaturallySortDeviceNames(hostDeviceNamesToBridge...) {
+		hostDevice := hostDeviceByName[hostName]
 		hostToBridge = append(hostToBridge, network.DeviceToBridge{
 			DeviceName: hostName,
 			BridgeName: BridgeNameForDevice(hostName),
-			MACAddress: hostDeviceByName[hostName].MACAddress(),
+			MACAddress: hostDevice.MACAddress(),
+			DeviceType: bridgeTargetType(hostDevice),
 		})
 	}
 	return hostToBridge, reconfigureDelay, nil
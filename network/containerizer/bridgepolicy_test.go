@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/network/containerizer"
+	"github.com/juju/juju/network/netplan"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testcharms"
@@ -661,6 +662,7 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerDefaultUnbrid
 	c.Check(missing, gc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "eth0",
 		BridgeName: "br-eth0",
+		DeviceType: netplan.TypeEthernet,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
@@ -709,9 +711,11 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerNoSpaces(c *g
 	c.Check(missing, gc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "ens3",
 		BridgeName: "br-ens3",
+		DeviceType: netplan.TypeEthernet,
 	}, {
 		DeviceName: "ens4",
 		BridgeName: "br-ens4",
+		DeviceType: netplan.TypeEthernet,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
@@ -829,6 +833,7 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerUnknownAndDef
 	c.Check(missing, gc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "ens3",
 		BridgeName: "br-ens3",
+		DeviceType: netplan.TypeEthernet,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
@@ -859,6 +864,7 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerOneOfTwoBridg
 	c.Check(missing, gc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "ens2.1",
 		BridgeName: "br-ens2.1",
+		DeviceType: netplan.TypeEthernet,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
@@ -912,6 +918,7 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerTwoSpacesOneB
 	c.Check(missing, jc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "eth0",
 		BridgeName: "br-eth0",
+		DeviceType: netplan.TypeEthernet,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
@@ -935,12 +942,15 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerMultipleSpace
 	c.Check(missing, jc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "eth0",
 		BridgeName: "br-eth0",
+		DeviceType: netplan.TypeEthernet,
 	}, {
 		DeviceName: "eth0.1",
 		BridgeName: "br-eth0.1",
+		DeviceType: netplan.TypeEthernet,
 	}, {
 		DeviceName: "eth1",
 		BridgeName: "br-eth1",
+		DeviceType: netplan.TypeEthernet,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
@@ -1005,6 +1015,7 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerBondedNICs(c
 	c.Check(missing, jc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "zbond0",
 		BridgeName: "br-zbond0",
+		DeviceType: netplan.TypeBond,
 	}})
 	// We are creating a bridge on a bond, so we use a non-zero delay
 	c.Check(reconfigureDelay, gc.Equals, 13)
@@ -1047,9 +1058,11 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerVLAN(c *gc.C)
 	c.Check(missing, jc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "eth0",
 		BridgeName: "br-eth0",
+		DeviceType: netplan.TypeEthernet,
 	}, {
 		DeviceName: "eth0.100",
 		BridgeName: "br-eth0.100",
+		DeviceType: netplan.TypeVLAN,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
@@ -1113,9 +1126,11 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerVLANOnBond(c
 	c.Check(missing, jc.DeepEquals, []network.DeviceToBridge{{
 		DeviceName: "bond0",
 		BridgeName: "br-bond0",
+		DeviceType: netplan.TypeBond,
 	}, {
 		DeviceName: "bond0.100",
 		BridgeName: "br-bond0.100",
+		DeviceType: netplan.TypeVLAN,
 	}})
 	c.Check(reconfigureDelay, gc.Equals, 13)
 }
@@ -2,6 +2,7 @@ package netplan
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"time"
 
@@ -21,8 +22,24 @@ type ActivationParams struct {
 	RunPrefix string
 	Directory string
 	Timeout   time.Duration
+
+	// ControllerAddresses are the host:port addresses of the controller.
+	// If any are given, one of them must be reachable once the new
+	// configuration is applied, otherwise the previous configuration
+	// is restored.
+	ControllerAddresses []string
+
+	// ConnectivityTimeout is how long to keep trying to reach the
+	// controller after applying the new configuration.
+	ConnectivityTimeout time.Duration
 }
 
+const (
+	defaultConnectivityTimeout = time.Minute
+	connectivityRetryDelay     = 2 * time.Second
+	connectivityDialTimeout    = 5 * time.Second
+)
+
 // ActivationResult captures the result of actively bridging the
 // interfaces using ifup/ifdown.
 type ActivationResult struct {
@@ -34,7 +51,9 @@ type ActivationResult struct {
 // BridgeAndActivate will parse a set of netplan yaml files in a directory,
 // create a new netplan config with the provided interfaces bridged
 // bridged, then reconfigure the network using the ifupdown package
-// for the new bridges.
+// for the new bridges. If controller addresses are given and none of them
+// can be reached once the new configuration is applied, the previous
+// configuration is restored.
 func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 	if len(params.Devices) == 0 {
 		return nil, errors.Errorf("no devices specified")
@@ -47,8 +66,12 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 	}
 
 	for _, device := range params.Devices {
-		var deviceId string
-		deviceId, deviceType, err := netplan.FindDeviceByNameOrMAC(device.DeviceName, device.MACAddress)
+		deviceId, deviceType := "", device.DeviceType
+		if deviceType == "" {
+			deviceId, deviceType, err = netplan.FindDeviceByNameOrMAC(device.DeviceName, device.MACAddress)
+		} else {
+			deviceId, err = netplan.FindDeviceOfType(deviceType, device.DeviceName, device.MACAddress)
+		}
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -83,12 +106,7 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 		return nil, err
 	}
 
-	environ := os.Environ()
-	// TODO(wpk) 2017-06-21 Is there a way to verify that apply is finished?
-	// https://bugs.launchpad.net/netplan/+bug/1701436
-	command := fmt.Sprintf("%snetplan generate && netplan apply && sleep 10", params.RunPrefix)
-
-	result, err := scriptrunner.RunCommand(command, environ, params.Clock, params.Timeout)
+	result, err := apply(params)
 
 	activationResult := ActivationResult{
 		Stderr: string(result.Stderr),
@@ -106,5 +124,60 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 		netplan.Rollback()
 		return &activationResult, errors.Errorf("bridge activation error code %d", result.Code)
 	}
+
+	if err := checkConnectivity(params); err != nil {
+		logger.Errorf("lost connectivity to the controller after bridging, restoring previous configuration: %v", err)
+		netplan.Rollback()
+		if result, err := apply(params); err != nil {
+			logger.Errorf("reapplying previous netplan configuration failed: %v", err)
+		} else if result.Code != 0 {
+			logger.Errorf("reapplying previous netplan configuration failed: %q", result.Stderr)
+		}
+		return &activationResult, errors.Annotate(err, "bridge activation lost connectivity to the controller")
+	}
 	return nil, nil
 }
+
+// apply generates and applies the netplan configuration in place.
+func apply(params ActivationParams) (*scriptrunner.ScriptResult, error) {
+	environ := os.Environ()
+	// TODO(wpk) 2017-06-21 Is there a way to verify that apply is finished?
+	// https://bugs.launchpad.net/netplan/+bug/1701436
+	command := fmt.Sprintf("%snetplan generate && netplan apply && sleep 10", params.RunPrefix)
+
+	return scriptrunner.RunCommand(command, environ, params.Clock, params.Timeout)
+}
+
+// checkConnectivity returns nil if any of the controller addresses in
+// params accepts a TCP connection before the connectivity timeout.
+func checkConnectivity(params ActivationParams) error {
+	if len(params.ControllerAddresses) == 0 {
+		return nil
+	}
+	clk := params.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	timeout := params.ConnectivityTimeout
+	if timeout == 0 {
+		timeout = defaultConnectivityTimeout
+	}
+	deadline := clk.After(timeout)
+	for {
+		var err error
+		for _, addr := range params.ControllerAddresses {
+			var conn net.Conn
+			conn, err = net.DialTimeout("tcp", addr, connectivityDialTimeout)
+			if err == nil {
+				_ = conn.Close()
+				return nil
+			}
+			logger.Debugf("cannot reach controller at %s: %v", addr, err)
+		}
+		select {
+		case <-deadline:
+			return errors.Errorf("cannot reach any controller address %v: %v", params.ControllerAddresses, err)
+		case <-clk.After(connectivityRetryDelay):
+		}
+	}
+}
@@ -2,8 +2,10 @@ package netplan_test
 
 import (
 	"io/ioutil"
+	"net"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -193,3 +195,147 @@ func (s *ActivateSuite) TestActivateTimeout(c *gc.C) {
 	c.Check(result, gc.NotNil)
 	c.Check(err, gc.ErrorMatches, "bridge activation error: command cancelled")
 }
+
+var activateGoldenTests = []struct {
+	name    string
+	devices []netplan.DeviceToBridge
+}{{
+	name: "ethernet",
+	devices: []netplan.DeviceToBridge{{
+		DeviceName: "eno1",
+		MACAddress: "00:11:22:33:44:55",
+		BridgeName: "br-eno1",
+	}},
+}, {
+	name: "bond",
+	devices: []netplan.DeviceToBridge{{
+		DeviceName: "bond0",
+		BridgeName: "br-bond0",
+	}},
+}, {
+	name: "vlan",
+	devices: []netplan.DeviceToBridge{{
+		DeviceName: "eno1",
+		MACAddress: "00:11:22:33:44:55",
+		BridgeName: "br-eno1",
+	}, {
+		DeviceName: "eno1.100",
+		BridgeName: "br-eno1.100",
+	}},
+}, {
+	name: "vlan-on-bond",
+	devices: []netplan.DeviceToBridge{{
+		DeviceName: "bond0",
+		BridgeName: "br-bond0",
+	}, {
+		DeviceName: "bond0.100",
+		BridgeName: "br-bond0.100",
+	}},
+}, {
+	// The device has been renamed since it was observed, and a VLAN on
+	// it shares its MAC address, so only its type identifies it.
+	name: "ethernet-shared-mac",
+	devices: []netplan.DeviceToBridge{{
+		DeviceName: "ens3",
+		MACAddress: "00:11:22:33:44:55",
+		BridgeName: "br-ens3",
+		DeviceType: netplan.TypeEthernet,
+	}},
+}}
+
+// TestActivateGolden checks the configuration written for each directory
+// in testdata/TestBridgeAndActivate against its expected.yaml.
+func (s *ActivateSuite) TestActivateGolden(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	for i, test := range activateGoldenTests {
+		c.Logf("test %d: %s", i, test.name)
+		testDir := path.Join("testdata/TestBridgeAndActivate", test.name)
+		tempDir := c.MkDir()
+		input, err := ioutil.ReadFile(path.Join(testDir, "00.yaml"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(path.Join(tempDir, "00.yaml"), input, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+
+		params := netplan.ActivationParams{
+			Devices:   test.devices,
+			Directory: tempDir,
+			RunPrefix: "exit 0 &&",
+		}
+		result, err := netplan.BridgeAndActivate(params)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(result, gc.IsNil)
+
+		expected, err := ioutil.ReadFile(path.Join(testDir, "expected.yaml"))
+		c.Assert(err, jc.ErrorIsNil)
+		written, err := ioutil.ReadFile(path.Join(tempDir, "99-juju.yaml"))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(written), gc.Equals, string(expected))
+	}
+}
+
+func (s *ActivateSuite) TestActivateControllerReachable(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+
+	tempDir := c.MkDir()
+	input, err := ioutil.ReadFile("testdata/TestBridgeAndActivate/ethernet/00.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path.Join(tempDir, "00.yaml"), input, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := netplan.ActivationParams{
+		Devices: []netplan.DeviceToBridge{{
+			DeviceName: "eno1",
+			BridgeName: "br-eno1",
+		}},
+		Directory:           tempDir,
+		RunPrefix:           "exit 0 &&",
+		ControllerAddresses: []string{listener.Addr().String()},
+	}
+	result, err := netplan.BridgeAndActivate(params)
+	c.Check(result, gc.IsNil)
+	c.Check(err, jc.ErrorIsNil)
+
+	_, err = ioutil.ReadFile(path.Join(tempDir, "99-juju.yaml"))
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ActivateSuite) TestActivateControllerUnreachable(c *gc.C) {
+	coretesting.SkipIfWindowsBug(c, "lp:1771077")
+	// Find a port with nothing listening on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	addr := listener.Addr().String()
+	c.Assert(listener.Close(), jc.ErrorIsNil)
+
+	tempDir := c.MkDir()
+	input, err := ioutil.ReadFile("testdata/TestBridgeAndActivate/ethernet/00.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path.Join(tempDir, "00.yaml"), input, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := netplan.ActivationParams{
+		Devices: []netplan.DeviceToBridge{{
+			DeviceName: "eno1",
+			BridgeName: "br-eno1",
+		}},
+		Directory:           tempDir,
+		RunPrefix:           "exit 0 &&",
+		ControllerAddresses: []string{addr},
+		ConnectivityTimeout: 10 * time.Millisecond,
+	}
+	result, err := netplan.BridgeAndActivate(params)
+	c.Check(result, gc.NotNil)
+	c.Check(err, gc.ErrorMatches, `bridge activation lost connectivity to the controller: cannot reach any controller address \[127.0.0.1:\d+\]: .*`)
+
+	// The original configuration is back in place, on its own.
+	fileInfos, err := ioutil.ReadDir(tempDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fileInfos, gc.HasLen, 1)
+	c.Check(fileInfos[0].Name(), gc.Equals, "00.yaml")
+	content, err := ioutil.ReadFile(path.Join(tempDir, "00.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(content), gc.Equals, string(input))
+}
@@ -283,6 +283,125 @@ func (np *Netplan) createBridgeFromInterface(bridgeName, deviceId string, intf *
 	*intf = Interface{MTU: intf.MTU}
 }
 
+// AddVLAN adds a VLAN device with the input tag on top of the link device,
+// which must already be defined. The VLAN takes the input interface config.
+func (np *Netplan) AddVLAN(name string, tag int, link string, intf Interface) error {
+	if err := np.checkNewDevice(name); err != nil {
+		return errors.Trace(err)
+	}
+	if tag < 1 || tag > 4094 {
+		return errors.NotValidf("VLAN %q tag %d", name, tag)
+	}
+	if !np.hasDevice(link) {
+		return errors.NotFoundf("link device %q for VLAN %q", link, name)
+	}
+	if np.Network.VLANs == nil {
+		np.Network.VLANs = make(map[string]VLAN)
+	}
+	np.Network.VLANs[name] = VLAN{
+		Id:        &tag,
+		Link:      link,
+		Interface: intf,
+	}
+	return nil
+}
+
+// AddBond adds a bond of the input devices, which must already be defined.
+// The bond takes the input interface config and bonding parameters.
+func (np *Netplan) AddBond(name string, interfaces []string, params BondParameters, intf Interface) error {
+	if err := np.checkNewDevice(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := np.checkMembers(name, interfaces); err != nil {
+		return errors.Trace(err)
+	}
+	if np.Network.Bonds == nil {
+		np.Network.Bonds = make(map[string]Bond)
+	}
+	np.Network.Bonds[name] = Bond{
+		Interfaces: interfaces,
+		Interface:  intf,
+		Parameters: params,
+	}
+	return nil
+}
+
+// AddBridge adds a bridge of the input devices, which must already be
+// defined. The bridge takes the input interface config.
+// Use the Bridge*ById methods to bridge an existing, configured device.
+func (np *Netplan) AddBridge(name string, interfaces []string, intf Interface) error {
+	if err := np.checkNewDevice(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := np.checkMembers(name, interfaces); err != nil {
+		return errors.Trace(err)
+	}
+	if np.Network.Bridges == nil {
+		np.Network.Bridges = make(map[string]Bridge)
+	}
+	np.Network.Bridges[name] = Bridge{
+		Interfaces: interfaces,
+		Interface:  intf,
+	}
+	return nil
+}
+
+// hasDevice returns true if a device with the input id is defined.
+func (np *Netplan) hasDevice(id string) bool {
+	if _, ok := np.Network.Ethernets[id]; ok {
+		return true
+	}
+	if _, ok := np.Network.Wifis[id]; ok {
+		return true
+	}
+	if _, ok := np.Network.Bridges[id]; ok {
+		return true
+	}
+	if _, ok := np.Network.Bonds[id]; ok {
+		return true
+	}
+	_, ok := np.Network.VLANs[id]
+	return ok
+}
+
+// checkNewDevice returns an error if the input id is empty or is already
+// used by a device.
+func (np *Netplan) checkNewDevice(id string) error {
+	if id == "" {
+		return errors.NotValidf("empty device id")
+	}
+	if np.hasDevice(id) {
+		return errors.AlreadyExistsf("device %q", id)
+	}
+	return nil
+}
+
+// checkMembers returns an error if any of the input devices, to be made
+// members of the named bond or bridge, is not defined or is already a
+// member of a bond or bridge.
+func (np *Netplan) checkMembers(name string, interfaces []string) error {
+	for _, id := range interfaces {
+		if !np.hasDevice(id) {
+			return errors.NotFoundf("device %q for %q", id, name)
+		}
+		for bName, bond := range np.Network.Bonds {
+			for _, i := range bond.Interfaces {
+				if i == id {
+					return errors.AlreadyExistsf("cannot add device %q to %q, device in bond %q", id, name, bName)
+				}
+			}
+		}
+		for bName, bridge := range np.Network.Bridges {
+			for _, i := range bridge.Interfaces {
+				if i == id {
+					return errors.AlreadyExistsf("cannot add device %q to %q, device in bridge %q", id, name, bName)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func Unmarshal(in []byte, out interface{}) (err error) {
 	return goyaml.UnmarshalStrict(in, out)
 }
@@ -346,6 +465,28 @@ func ReadDirectory(dirPath string) (np Netplan, err error) {
 	return np, nil
 }
 
+// InUse reports whether netplan configures the machine's network, that
+// is whether the netplan directory holds configuration for any network
+// devices. A machine whose network is configured some other way, such
+// as by ifupdown, may still have netplan installed, or an empty netplan
+// directory.
+func InUse(dirPath string) (bool, error) {
+	np, err := ReadDirectory(dirPath)
+	if os.IsNotExist(errors.Cause(err)) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return np.hasDevices(), nil
+}
+
+// hasDevices reports whether the configuration defines any network
+// devices.
+func (np *Netplan) hasDevices() bool {
+	n := np.Network
+	return len(n.Ethernets)+len(n.Wifis)+len(n.Bridges)+len(n.Bonds)+len(n.VLANs) > 0
+}
+
 // MoveYamlsToBak moves source .yaml files in a directory to .yaml.bak.(timestamp), except
 func (np *Netplan) MoveYamlsToBak() (err error) {
 	if np.backedFiles != nil {
@@ -489,6 +630,35 @@ const (
 	TypeBond     = DeviceType("bond")
 )
 
+// FindDeviceOfType looks for a device of the given type matching the
+// name of the device or its MAC address, preferring the name.
+func (np *Netplan) FindDeviceOfType(deviceType DeviceType, name, mac string) (string, error) {
+	var findByName, findByMAC func(string) (string, error)
+	switch deviceType {
+	case TypeEthernet:
+		findByName, findByMAC = np.FindEthernetByName, np.FindEthernetByMAC
+	case TypeBond:
+		findByName, findByMAC = np.FindBondByName, np.FindBondByMAC
+	case TypeVLAN:
+		findByName, findByMAC = np.FindVLANByName, np.FindVLANByMAC
+	default:
+		return "", errors.NotValidf("device type %q", deviceType)
+	}
+	if name != "" {
+		device, err := findByName(name)
+		if !errors.IsNotFound(err) {
+			return device, errors.Trace(err)
+		}
+	}
+	if mac != "" {
+		device, err := findByMAC(mac)
+		if !errors.IsNotFound(err) {
+			return device, errors.Trace(err)
+		}
+	}
+	return "", errors.NotFoundf("%s device - name %q MAC %q", deviceType, name, mac)
+}
+
 // FindDeviceByMACOrName will look for an Ethernet, VLAN or Bond matching the Name of the device or its MAC address.
 // Name is preferred to MAC address.
 func (np *Netplan) FindDeviceByNameOrMAC(name, mac string) (string, DeviceType, error) {
@@ -1259,6 +1259,64 @@ network:
 	checkFindDevice(c, np, "", "de:ad:be:ef:01:03", "eno3.123", netplan.TypeVLAN, "")
 }
 
+func (s *NetplanSuite) TestFindDeviceOfType(c *gc.C) {
+	np := MustNetplanFromYaml(c, `
+network:
+  version: 2
+  ethernets:
+    eno3:
+      match:
+        macaddress: de:ad:be:ef:01:03
+        set-name: eno3
+  vlans:
+    eno3.123:
+      id: 123
+      link: eno3
+      macaddress: de:ad:be:ef:01:03
+`)
+	device, err := np.FindDeviceOfType(netplan.TypeEthernet, "eno3", "")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(device, gc.Equals, "eno3")
+
+	// The MAC address is shared by both devices, the type picks one.
+	device, err = np.FindDeviceOfType(netplan.TypeEthernet, "", "de:ad:be:ef:01:03")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(device, gc.Equals, "eno3")
+	device, err = np.FindDeviceOfType(netplan.TypeVLAN, "", "de:ad:be:ef:01:03")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(device, gc.Equals, "eno3.123")
+
+	_, err = np.FindDeviceOfType(netplan.TypeBond, "eno3", "de:ad:be:ef:01:03")
+	c.Check(err, gc.ErrorMatches, `bond device - name "eno3" MAC "de:ad:be:ef:01:03" not found`)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = np.FindDeviceOfType(netplan.DeviceType("wifi"), "eno3", "")
+	c.Check(err, gc.ErrorMatches, `device type "wifi" not valid`)
+}
+
+func (s *NetplanSuite) TestInUse(c *gc.C) {
+	inUse, err := netplan.InUse("testdata/TestReadDirectory")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(inUse, jc.IsTrue)
+}
+
+func (s *NetplanSuite) TestInUseEmptyDirectory(c *gc.C) {
+	tempDir := c.MkDir()
+	err := ioutil.WriteFile(path.Join(tempDir, "00-file.yaml"), []byte("network:\n  version: 2\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	inUse, err := netplan.InUse(tempDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(inUse, jc.IsFalse)
+}
+
+func (s *NetplanSuite) TestInUseMissingDirectory(c *gc.C) {
+	tempDir := c.MkDir()
+	os.RemoveAll(tempDir)
+	inUse, err := netplan.InUse(tempDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(inUse, jc.IsFalse)
+}
+
 func (s *NetplanSuite) TestReadDirectory(c *gc.C) {
 	c.Skip("Full netplan merge not supported yet, see https://bugs.launchpad.net/juju/+bug/1701429")
 	expected := `
@@ -1606,3 +1664,68 @@ func (s *NetplanSuite) TestNetplanExamples(c *gc.C) {
 		}
 	}
 }
+
+func (s *NetplanSuite) TestAddDevices(c *gc.C) {
+	input, err := ioutil.ReadFile("testdata/TestAddDevices/00.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	np := MustNetplanFromYaml(c, string(input))
+
+	miimon := 100
+	mode := "802.3ad"
+	err = np.AddBond("bond0", []string{"eno1", "eno2"}, netplan.BondParameters{
+		Mode:               netplan.IntString{String: &mode},
+		MIIMonitorInterval: &miimon,
+	}, netplan.Interface{MTU: 9000})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = np.AddVLAN("bond0.100", 100, "bond0", netplan.Interface{
+		Addresses: []string{"10.100.0.2/24"},
+		Gateway4:  "10.100.0.1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	dhcp := true
+	err = np.AddBridge("br-eno3", []string{"eno3"}, netplan.Interface{DHCP4: &dhcp})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected, err := ioutil.ReadFile("testdata/TestAddDevices/expected.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	out, err := netplan.Marshal(np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, string(expected))
+}
+
+func (s *NetplanSuite) TestAddDevicesErrors(c *gc.C) {
+	np := MustNetplanFromYaml(c, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      dhcp4: true
+    eno2:
+      dhcp4: true
+  bonds:
+    bond0:
+      interfaces: [eno1]
+`)
+	err := np.AddVLAN("eno1.100", 100, "eno3", netplan.Interface{})
+	c.Check(err, gc.ErrorMatches, `link device "eno3" for VLAN "eno1.100" not found`)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	err = np.AddVLAN("eno1.5000", 5000, "eno1", netplan.Interface{})
+	c.Check(err, gc.ErrorMatches, `VLAN "eno1.5000" tag 5000 not valid`)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+
+	err = np.AddVLAN("eno2", 100, "eno1", netplan.Interface{})
+	c.Check(err, gc.ErrorMatches, `device "eno2" already exists`)
+	c.Check(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	err = np.AddBond("bond1", []string{"eno1", "eno2"}, netplan.BondParameters{}, netplan.Interface{})
+	c.Check(err, gc.ErrorMatches, `cannot add device "eno1" to "bond1", device in bond "bond0" already exists`)
+
+	err = np.AddBridge("br0", []string{"eno3"}, netplan.Interface{})
+	c.Check(err, gc.ErrorMatches, `device "eno3" for "br0" not found`)
+
+	err = np.AddBridge("", []string{"eno2"}, netplan.Interface{})
+	c.Check(err, gc.ErrorMatches, `empty device id not valid`)
+}
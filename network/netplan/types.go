@@ -12,4 +12,8 @@ type DeviceToBridge struct {
 
 	// MACAddress is the MAC address of the device to be bridged
 	MACAddress string
+
+	// DeviceType is the type of the device to be bridged, if known.
+	// When it is not, the type is inferred from the configuration.
+	DeviceType DeviceType
 }
@@ -16,6 +16,8 @@ import (
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+
+	"github.com/juju/juju/network/netplan"
 )
 
 var logger = loggo.GetLogger("juju.network")
@@ -410,6 +412,10 @@ type DeviceToBridge struct {
 
 	// MACAddress is the MAC address of the device to be bridged
 	MACAddress string
+
+	// DeviceType is the netplan type of the device to be bridged: an
+	// ethernet, a bond or a VLAN. It is empty if the type is not known.
+	DeviceType netplan.DeviceType
 }
 
 // LXCNetDefaultConfig is the location of the default network config
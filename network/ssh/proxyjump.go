@@ -0,0 +1,171 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ParseJumpHost splits a jump host, given in the form
+// [user@]host[:port], into the [user@]host to connect to and the
+// port, which is zero if none was given.
+func ParseJumpHost(jumpHost string) (userHost string, port int, err error) {
+	userHost = jumpHost
+	hostPort := jumpHost
+	if at := strings.LastIndex(jumpHost, "@"); at != -1 {
+		hostPort = jumpHost[at+1:]
+	}
+	if host, portString, err := net.SplitHostPort(hostPort); err == nil {
+		port, err = strconv.Atoi(portString)
+		if err != nil || port <= 0 || port > 65535 {
+			return "", 0, errors.NotValidf("jump host %q port", jumpHost)
+		}
+		userHost = strings.TrimSuffix(jumpHost, hostPort) + host
+		hostPort = host
+	}
+	if hostPort == "" || strings.HasPrefix(userHost, "@") {
+		return "", 0, errors.NotValidf("jump host %q", jumpHost)
+	}
+	return userHost, port, nil
+}
+
+// ProxyJumpCommand returns the command that, used as an SSH
+// ProxyCommand, connects to the target host through the given jump
+// host, which has the form [user@]host[:port].
+func ProxyJumpCommand(jumpHost string) ([]string, error) {
+	args, err := jumpHostArgs(jumpHost, "%h:%p")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append([]string{"ssh"}, args...), nil
+}
+
+func jumpHostArgs(jumpHost, target string) ([]string, error) {
+	userHost, port, err := ParseJumpHost(jumpHost)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var args []string
+	if port != 0 {
+		args = append(args, "-p", strconv.Itoa(port))
+	}
+	return append(args, "-W", target, userHost), nil
+}
+
+// NewProxyJumpDialer returns a Dialer that makes TCP connections
+// through the given jump host, which has the form [user@]host[:port],
+// by running "ssh -W" on it. The jump host must accept key based
+// authentication. A ReachableChecker using the dialer checks hosts
+// that can only be reached through the jump host.
+func NewProxyJumpDialer(jumpHost string, timeout time.Duration) (Dialer, error) {
+	if _, _, err := ParseJumpHost(jumpHost); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &proxyJumpDialer{jumpHost: jumpHost, timeout: timeout}, nil
+}
+
+type proxyJumpDialer struct {
+	jumpHost string
+	timeout  time.Duration
+}
+
+// Dial is part of the Dialer interface.
+func (d *proxyJumpDialer) Dial(network, address string) (net.Conn, error) {
+	if network != "tcp" {
+		return nil, errors.NotSupportedf("network %q through a jump host", network)
+	}
+	args := []string{"-o", "BatchMode=yes"}
+	if d.timeout > 0 {
+		seconds := int((d.timeout + time.Second - 1) / time.Second)
+		args = append(args, "-o", fmt.Sprintf("ConnectTimeout=%d", seconds))
+	}
+	jumpArgs, err := jumpHostArgs(d.jumpHost, address)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cmd := exec.Command("ssh", append(args, jumpArgs...)...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Annotatef(err, "connecting to %s through %s", address, d.jumpHost)
+	}
+	logger.Debugf("connecting to %s through %s", address, d.jumpHost)
+	return &proxyJumpConn{
+		Reader:      stdout,
+		WriteCloser: stdin,
+		cmd:         cmd,
+		addr:        proxyJumpAddr(address),
+	}, nil
+}
+
+// proxyJumpConn is a net.Conn that reads from and writes to an
+// "ssh -W" process.
+type proxyJumpConn struct {
+	io.Reader
+	io.WriteCloser
+	cmd  *exec.Cmd
+	addr net.Addr
+}
+
+// Close is part of the net.Conn interface.
+func (c *proxyJumpConn) Close() error {
+	c.WriteCloser.Close()
+	c.cmd.Process.Kill()
+	c.cmd.Wait()
+	return nil
+}
+
+// LocalAddr is part of the net.Conn interface.
+func (c *proxyJumpConn) LocalAddr() net.Addr {
+	return c.addr
+}
+
+// RemoteAddr is part of the net.Conn interface.
+func (c *proxyJumpConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// SetDeadline is part of the net.Conn interface. Deadlines are
+// not supported; the connection timeout is applied by ssh instead.
+func (c *proxyJumpConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+// SetReadDeadline is part of the net.Conn interface.
+func (c *proxyJumpConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+// SetWriteDeadline is part of the net.Conn interface.
+func (c *proxyJumpConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+// proxyJumpAddr is the net.Addr of a connection made through
+// a jump host.
+type proxyJumpAddr string
+
+// Network is part of the net.Addr interface.
+func (a proxyJumpAddr) Network() string {
+	return "tcp"
+}
+
+// String is part of the net.Addr interface.
+func (a proxyJumpAddr) String() string {
+	return string(a)
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh_test
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network/ssh"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ProxyJumpSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ProxyJumpSuite{})
+
+func (s *ProxyJumpSuite) TestParseJumpHost(c *gc.C) {
+	for i, test := range []struct {
+		jumpHost string
+		userHost string
+		port     int
+		err      string
+	}{{
+		jumpHost: "bastion",
+		userHost: "bastion",
+	}, {
+		jumpHost: "admin@bastion",
+		userHost: "admin@bastion",
+	}, {
+		jumpHost: "admin@bastion:2222",
+		userHost: "admin@bastion",
+		port:     2222,
+	}, {
+		jumpHost: "10.0.0.1:22",
+		userHost: "10.0.0.1",
+		port:     22,
+	}, {
+		jumpHost: "admin@[2001:db8::1]:2222",
+		userHost: "admin@2001:db8::1",
+		port:     2222,
+	}, {
+		jumpHost: "bastion:ssh",
+		err:      `jump host "bastion:ssh" port not valid`,
+	}, {
+		jumpHost: "admin@",
+		err:      `jump host "admin@" not valid`,
+	}, {
+		jumpHost: "@bastion",
+		err:      `jump host "@bastion" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.jumpHost)
+		userHost, port, err := ssh.ParseJumpHost(test.jumpHost)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(userHost, gc.Equals, test.userHost)
+		c.Check(port, gc.Equals, test.port)
+	}
+}
+
+func (s *ProxyJumpSuite) TestProxyJumpCommand(c *gc.C) {
+	command, err := ssh.ProxyJumpCommand("admin@bastion:2222")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command, jc.DeepEquals, []string{"ssh", "-p", "2222", "-W", "%h:%p", "admin@bastion"})
+
+	command, err = ssh.ProxyJumpCommand("bastion")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command, jc.DeepEquals, []string{"ssh", "-W", "%h:%p", "bastion"})
+
+	_, err = ssh.ProxyJumpCommand("")
+	c.Assert(err, gc.ErrorMatches, `jump host "" not valid`)
+}
+
+func (s *ProxyJumpSuite) TestDial(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("test uses a shell script as ssh")
+	}
+	// The fake ssh records its arguments and echoes its input,
+	// standing in for the connection to the target.
+	fakebin := c.MkDir()
+	argsFile := filepath.Join(fakebin, "ssh.args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\nexec cat\n"
+	err := ioutil.WriteFile(filepath.Join(fakebin, "ssh"), []byte(script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchEnvPathPrepend(fakebin)
+
+	dialer, err := ssh.NewProxyJumpDialer("admin@bastion:2222", 1500*time.Millisecond)
+	c.Assert(err, jc.ErrorIsNil)
+	conn, err := dialer.Dial("tcp", "10.0.0.2:22")
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	c.Check(conn.RemoteAddr().String(), gc.Equals, "10.0.0.2:22")
+
+	_, err = conn.Write([]byte("SSH-2.0-test\r\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	buf := make([]byte, 14)
+	_, err = io.ReadFull(conn, buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(buf), gc.Equals, "SSH-2.0-test\r\n")
+
+	args, err := ioutil.ReadFile(argsFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(strings.TrimSpace(string(args)), gc.Equals,
+		"-o BatchMode=yes -o ConnectTimeout=2 -p 2222 -W 10.0.0.2:22 admin@bastion")
+}
+
+func (s *ProxyJumpSuite) TestDialUnsupportedNetwork(c *gc.C) {
+	dialer, err := ssh.NewProxyJumpDialer("bastion", time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = dialer.Dial("udp", "10.0.0.2:22")
+	c.Assert(err, gc.ErrorMatches, `network "udp" through a jump host not supported`)
+}
+
+func (s *ProxyJumpSuite) TestNewProxyJumpDialerInvalid(c *gc.C) {
+	_, err := ssh.NewProxyJumpDialer("admin@", time.Second)
+	c.Assert(err, gc.ErrorMatches, `jump host "admin@" not valid`)
+}
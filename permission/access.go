@@ -27,6 +27,13 @@ const (
 	// AdminAccess allows a user full control over the subject.
 	AdminAccess Access = "admin"
 
+	// Application permissions
+
+	// OperateAccess allows a user to run commands and actions on, and
+	// ssh to, the units of an application, without being able to change
+	// its configuration or remove it.
+	OperateAccess Access = "operate"
+
 	// Controller permissions
 
 	// LoginAccess allows a user to log-ing into the subject.
@@ -69,6 +76,16 @@ func ValidateOfferAccess(access Access) error {
 	return errors.NotValidf("%q offer access", access)
 }
 
+// ValidateApplicationAccess returns error if the passed access is not a
+// valid application access level.
+func ValidateApplicationAccess(access Access) error {
+	switch access {
+	case ReadAccess, OperateAccess, AdminAccess:
+		return nil
+	}
+	return errors.NotValidf("%q application access", access)
+}
+
 //ValidateControllerAccess returns error if the passed access is not a valid
 // controller access level.
 func ValidateControllerAccess(access Access) error {
@@ -183,3 +200,38 @@ func (a Access) GreaterOfferAccessThan(access Access) bool {
 	}
 	return v1 > v2
 }
+
+func (a Access) applicationValue() int {
+	switch a {
+	case NoAccess:
+		return 0
+	case ReadAccess:
+		return 1
+	case OperateAccess:
+		return 2
+	case AdminAccess:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// EqualOrGreaterApplicationAccessThan returns true if the current access
+// is equal or greater than the passed in access level.
+func (a Access) EqualOrGreaterApplicationAccessThan(access Access) bool {
+	v1, v2 := a.applicationValue(), access.applicationValue()
+	if v1 < 0 || v2 < 0 {
+		return false
+	}
+	return v1 >= v2
+}
+
+// GreaterApplicationAccessThan returns true if the current access is
+// greater than the passed in access level.
+func (a Access) GreaterApplicationAccessThan(access Access) bool {
+	v1, v2 := a.applicationValue(), access.applicationValue()
+	if v1 < 0 || v2 < 0 {
+		return false
+	}
+	return v1 > v2
+}
@@ -199,3 +199,56 @@ func (*accessSuite) TestGreaterControllerAccessThan(c *gc.C) {
 	c.Check(superuser.GreaterControllerAccessThan(addmodel), jc.IsTrue)
 	c.Check(superuser.GreaterControllerAccessThan(superuser), jc.IsFalse)
 }
+
+func (*accessSuite) TestEqualOrGreaterApplicationAccessThan(c *gc.C) {
+	var (
+		undefined = permission.NoAccess
+		read      = permission.ReadAccess
+		operate   = permission.OperateAccess
+		admin     = permission.AdminAccess
+		write     = permission.WriteAccess
+		consume   = permission.ConsumeAccess
+	)
+	// Access levels that don't apply to applications never compare.
+	for _, value := range []permission.Access{write, consume} {
+		c.Check(value.EqualOrGreaterApplicationAccessThan(undefined), jc.IsFalse)
+		c.Check(value.EqualOrGreaterApplicationAccessThan(read), jc.IsFalse)
+		c.Check(read.EqualOrGreaterApplicationAccessThan(value), jc.IsFalse)
+	}
+
+	c.Check(undefined.EqualOrGreaterApplicationAccessThan(undefined), jc.IsTrue)
+	c.Check(undefined.EqualOrGreaterApplicationAccessThan(read), jc.IsFalse)
+
+	c.Check(read.EqualOrGreaterApplicationAccessThan(read), jc.IsTrue)
+	c.Check(read.EqualOrGreaterApplicationAccessThan(operate), jc.IsFalse)
+
+	c.Check(operate.EqualOrGreaterApplicationAccessThan(read), jc.IsTrue)
+	c.Check(operate.EqualOrGreaterApplicationAccessThan(operate), jc.IsTrue)
+	c.Check(operate.EqualOrGreaterApplicationAccessThan(admin), jc.IsFalse)
+
+	c.Check(admin.EqualOrGreaterApplicationAccessThan(operate), jc.IsTrue)
+	c.Check(admin.EqualOrGreaterApplicationAccessThan(admin), jc.IsTrue)
+}
+
+func (*accessSuite) TestGreaterApplicationAccessThan(c *gc.C) {
+	var (
+		undefined = permission.NoAccess
+		read      = permission.ReadAccess
+		operate   = permission.OperateAccess
+		admin     = permission.AdminAccess
+	)
+	c.Check(undefined.GreaterApplicationAccessThan(undefined), jc.IsFalse)
+	c.Check(read.GreaterApplicationAccessThan(undefined), jc.IsTrue)
+	c.Check(operate.GreaterApplicationAccessThan(read), jc.IsTrue)
+	c.Check(operate.GreaterApplicationAccessThan(operate), jc.IsFalse)
+	c.Check(admin.GreaterApplicationAccessThan(operate), jc.IsTrue)
+	c.Check(permission.WriteAccess.GreaterApplicationAccessThan(read), jc.IsFalse)
+}
+
+func (*accessSuite) TestValidateApplicationAccess(c *gc.C) {
+	for _, access := range []permission.Access{permission.ReadAccess, permission.OperateAccess, permission.AdminAccess} {
+		c.Check(permission.ValidateApplicationAccess(access), jc.ErrorIsNil)
+	}
+	err := permission.ValidateApplicationAccess(permission.WriteAccess)
+	c.Check(err, gc.ErrorMatches, `"write" application access not valid`)
+}
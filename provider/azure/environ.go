@@ -375,6 +375,8 @@ func (env *azureEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (
 		constraints.CpuPower,
 		constraints.Tags,
 		constraints.VirtType,
+		constraints.InstanceLifecycle,
+		constraints.SpotMaxPrice,
 	})
 	validator.RegisterVocabulary(
 		constraints.Arch,
@@ -1798,6 +1798,125 @@ func (s *environSuite) TestAdoptResources(c *gc.C) {
 	c.Check(gTags[tags.JujuController], gc.Equals, "new-controller")
 }
 
+func (s *environSuite) TestUpdateInstanceTags(c *gc.C) {
+	providers := []resources.Provider{{
+		Namespace: to.StringPtr("Microsoft.Compute"),
+		ResourceTypes: &[]resources.ProviderResourceType{{
+			ResourceType: to.StringPtr("virtualMachines"),
+			APIVersions:  &[]string{"2016-04-30-preview", "2015-06-15"},
+		}},
+	}}
+	vm := resources.GenericResource{
+		ID:       to.StringPtr("/subscriptions/foo/resourceGroups/bar/providers/Microsoft.Compute/virtualMachines/machine-0"),
+		Name:     to.StringPtr("machine-0"),
+		Type:     to.StringPtr("Microsoft.Compute/virtualMachines"),
+		Location: to.StringPtr("westus"),
+		Tags: to.StringMapPtr(map[string]string{
+			tags.JujuModel: testing.ModelTag.Id(),
+			"team":         "db",
+		}),
+		Properties: &map[string]interface{}{"has-properties": true},
+	}
+
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/providers", resources.ProviderListResult{Value: &providers}),
+		s.makeSender(".*/providers/Microsoft.Compute/virtualMachines/machine-0", vm),
+		s.makeSender(".*/providers/Microsoft.Compute/virtualMachines/machine-0", vm),
+	}
+
+	changed, err := env.(environs.TagUpdater).UpdateInstanceTags(s.callCtx, "machine-0", map[string]string{
+		tags.JujuModel: testing.ModelTag.Id(),
+		"team":         "web",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	c.Assert(s.requests, gc.HasLen, 3)
+	c.Check(s.requests[1].Method, gc.Equals, "GET")
+	c.Check(s.requests[1].URL.Query().Get("api-version"), gc.Equals, "2016-04-30-preview")
+	c.Check(s.requests[2].Method, gc.Equals, "PUT")
+	c.Check(s.requests[2].URL.Query().Get("api-version"), gc.Equals, "2016-04-30-preview")
+
+	req := s.requests[2]
+	data := make([]byte, req.ContentLength)
+	_, err = req.Body.Read(data)
+	c.Assert(err, jc.ErrorIsNil)
+	var resource resources.GenericResource
+	err = json.Unmarshal(data, &resource)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(to.StringMap(*resource.Tags), jc.DeepEquals, map[string]string{
+		tags.JujuModel:        testing.ModelTag.Id(),
+		tags.JujuResourceTags: "team",
+		"team":                "web",
+	})
+	c.Check(*resource.Properties, gc.DeepEquals, map[string]interface{}{"has-properties": true})
+}
+
+func (s *environSuite) TestUpdateInstanceTagsRemovesTags(c *gc.C) {
+	vm := resources.GenericResource{
+		ID:   to.StringPtr("/subscriptions/foo/resourceGroups/bar/providers/Microsoft.Compute/virtualMachines/machine-0"),
+		Name: to.StringPtr("machine-0"),
+		Tags: to.StringMapPtr(map[string]string{
+			tags.JujuModel:        testing.ModelTag.Id(),
+			tags.JujuResourceTags: "cost-centre team",
+			"cost-centre":         "42",
+			"team":                "web",
+			"other":               "unmanaged",
+		}),
+	}
+
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/providers", makeProvidersResult()),
+		s.makeSender(".*/providers/Microsoft.Compute/virtualMachines/machine-0", vm),
+		s.makeSender(".*/providers/Microsoft.Compute/virtualMachines/machine-0", vm),
+	}
+
+	changed, err := env.(environs.TagUpdater).UpdateInstanceTags(s.callCtx, "machine-0", map[string]string{
+		tags.JujuModel: testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	c.Assert(s.requests, gc.HasLen, 3)
+	c.Check(s.requests[2].Method, gc.Equals, "PUT")
+
+	req := s.requests[2]
+	data := make([]byte, req.ContentLength)
+	_, err = req.Body.Read(data)
+	c.Assert(err, jc.ErrorIsNil)
+	var resource resources.GenericResource
+	err = json.Unmarshal(data, &resource)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(to.StringMap(*resource.Tags), jc.DeepEquals, map[string]string{
+		tags.JujuModel: testing.ModelTag.Id(),
+		"other":        "unmanaged",
+	})
+}
+
+func (s *environSuite) TestUpdateInstanceTagsNoDrift(c *gc.C) {
+	vm := resources.GenericResource{
+		ID:   to.StringPtr("/subscriptions/foo/resourceGroups/bar/providers/Microsoft.Compute/virtualMachines/machine-0"),
+		Name: to.StringPtr("machine-0"),
+		Tags: to.StringMapPtr(map[string]string{
+			tags.JujuResourceTags: "team",
+			"team":                "web",
+		}),
+	}
+
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/providers", makeProvidersResult()),
+		s.makeSender(".*/providers/Microsoft.Compute/virtualMachines/machine-0", vm),
+	}
+
+	changed, err := env.(environs.TagUpdater).UpdateInstanceTags(s.callCtx, "machine-0", map[string]string{
+		"team": "web",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsFalse)
+	c.Assert(s.requests, gc.HasLen, 2)
+}
+
 func makeProvidersResult() resources.ProviderListResult {
 	providers := []resources.Provider{{
 		Namespace: to.StringPtr("Beck.Replica"),
@@ -0,0 +1,103 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure
+
+import (
+	"path"
+
+	"github.com/Azure/azure-sdk-for-go/arm/resources/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	internalazureresources "github.com/juju/juju/provider/azure/internal/azureresources"
+	"github.com/juju/juju/provider/azure/internal/errorutils"
+)
+
+const (
+	virtualMachineResourceType = "Microsoft.Compute/virtualMachines"
+	diskResourceType           = "Microsoft.Compute/disks"
+)
+
+// UpdateInstanceTags is part of the environs.TagUpdater interface.
+func (env *azureEnviron) UpdateInstanceTags(ctx context.ProviderCallContext, id instance.Id, desired map[string]string) (bool, error) {
+	return env.updateResourceTags(ctx, virtualMachineResourceType, string(id), desired)
+}
+
+// UpdateVolumeTags is part of the environs.TagUpdater interface.
+// Only managed disks can be tagged; models using unmanaged disks
+// store their volumes as blobs, which do not carry resource tags.
+func (env *azureEnviron) UpdateVolumeTags(ctx context.ProviderCallContext, volumeId string, desired map[string]string) (bool, error) {
+	storageClient, _, err := env.maybeGetStorageClient()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if storageClient != nil {
+		return false, errors.NotSupportedf("tagging unmanaged disk volumes")
+	}
+	return env.updateResourceTags(ctx, diskResourceType, volumeId, desired)
+}
+
+// updateResourceTags sets those of the desired tags that are missing
+// from, or differ on, the named resource of the given type in the
+// model's resource group, and removes those tags Juju applied that
+// are no longer desired, reporting whether any needed to be changed.
+func (env *azureEnviron) updateResourceTags(
+	ctx context.ProviderCallContext,
+	resourceType, name string,
+	desired map[string]string,
+) (bool, error) {
+	apiVersions, err := collectAPIVersions(ctx, resources.ProvidersClient{env.resources})
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	apiVersion := apiVersions[resourceType]
+	resourceID := path.Join(
+		"/subscriptions",
+		env.subscriptionId,
+		"resourceGroups",
+		env.resourceGroup,
+		"providers",
+		resourceType,
+		name,
+	)
+
+	client := internalazureresources.ResourcesClient{&resources.GroupClient{env.resources}}
+	resource, err := client.GetByID(resourceID, apiVersion)
+	if err != nil {
+		if isNotFoundResponse(resource.Response) {
+			return false, errors.NotFoundf("%s %q", resourceType, name)
+		}
+		return false, errorutils.HandleCredentialError(errors.Annotatef(err, "getting resource %q", name), ctx)
+	}
+	resourceTags := toTags(resource.Tags)
+	set, remove := tags.Diff(resourceTags, desired)
+	if len(set) == 0 && len(remove) == 0 {
+		return false, nil
+	}
+
+	logger.Debugf("updating tags %v and removing tags %v on %s", set, remove, resourceID)
+	if resourceTags == nil {
+		resourceTags = make(map[string]string)
+	}
+	for k, v := range set {
+		resourceTags[k] = v
+	}
+	for _, k := range remove {
+		delete(resourceTags, k)
+	}
+	resource.Tags = to.StringMapPtr(resourceTags)
+	_, errCh := client.CreateOrUpdateByID(
+		resourceID,
+		resource,
+		nil, // cancel channel
+		apiVersion,
+	)
+	if err := <-errCh; err != nil {
+		return false, errorutils.HandleCredentialError(errors.Annotatef(err, "updating tags for %q", name), ctx)
+	}
+	return true, nil
+}
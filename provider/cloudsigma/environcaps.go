@@ -13,6 +13,8 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.InstanceLifecycle,
+	constraints.SpotMaxPrice,
 }
 
 // ConstraintsValidator returns a Validator instance which
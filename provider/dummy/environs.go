@@ -996,7 +996,12 @@ func (e *environ) DestroyController(ctx context.ProviderCallContext, controllerU
 // ConstraintsValidator is defined on the Environs interface.
 func (e *environ) ConstraintsValidator(ctx context.ProviderCallContext) (constraints.Validator, error) {
 	validator := constraints.NewValidator()
-	validator.RegisterUnsupported([]string{constraints.CpuPower, constraints.VirtType})
+	validator.RegisterUnsupported([]string{
+		constraints.CpuPower,
+		constraints.VirtType,
+		constraints.InstanceLifecycle,
+		constraints.SpotMaxPrice,
+	})
 	validator.RegisterConflicts([]string{constraints.InstanceType}, []string{constraints.Mem})
 	validator.RegisterVocabulary(constraints.Arch, []string{arch.AMD64, arch.ARM64, arch.I386, arch.PPC64EL})
 	return validator, nil
@@ -184,6 +184,7 @@ func (e *environ) ConstraintsValidator(ctx context.ProviderCallContext) (constra
 		instTypeNames[i] = itype.Name
 	}
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
+	validator.RegisterVocabulary(constraints.InstanceLifecycle, []string{constraints.InstanceLifecycleSpot})
 	return validator, nil
 }
 
@@ -331,6 +332,10 @@ func (e *environ) PrecheckInstance(ctx context.ProviderCallContext, args environ
 	); err != nil {
 		return errors.Trace(err)
 	}
+	if args.Constraints.HasSpotMaxPrice() && !isSpotInstance(args.Constraints) {
+		return errors.Errorf("%q constraint requires %s=%s",
+			constraints.SpotMaxPrice, constraints.InstanceLifecycle, constraints.InstanceLifecycleSpot)
+	}
 	if !args.Constraints.HasInstanceType() {
 		return nil
 	}
@@ -599,7 +604,16 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	}
 
 	callback(status.Allocating, fmt.Sprintf("Trying to start instance in availability zone %q", availabilityZone), nil)
-	instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
+	client := e.ec2
+	if isSpotInstance(args.Constraints) {
+		var maxPrice string
+		if args.Constraints.HasSpotMaxPrice() {
+			maxPrice = *args.Constraints.SpotMaxPrice
+		}
+		client = withSpotMarket(client, maxPrice)
+		args.InstanceConfig.Tags[tagInstanceLifecycle] = marketTypeSpot
+	}
+	instResp, err = runInstances(client, ctx, runArgs, callback)
 	if err != nil {
 		if !isZoneOrSubnetConstrainedError(err) {
 			err = annotateWrapError(err, "cannot run instances")
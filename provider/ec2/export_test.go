@@ -66,6 +66,7 @@ var (
 	DeleteSecurityGroupInsistently = &deleteSecurityGroupInsistently
 	TerminateInstancesById         = &terminateInstancesById
 	MaybeConvertCredentialError    = maybeConvertCredentialError
+	DeleteTags                     = &deleteTags
 )
 
 const VPCIDNone = vpcIDNone
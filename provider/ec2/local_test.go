@@ -5,6 +5,7 @@ package ec2_test
 
 import (
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
@@ -733,6 +734,177 @@ func (t *localServerSuite) TestInstanceStatus(c *gc.C) {
 	c.Assert(inst.Status(t.callCtx).Message, gc.Equals, "terminated")
 }
 
+func (t *localServerSuite) TestStartInstanceSpot(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	var query url.Values
+	realRunInstances := *ec2.RunInstances
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ctx context.ProviderCallContext, ri *amzec2.RunInstances, c environs.StatusCallbackFunc) (*amzec2.RunInstancesResp, error) {
+		sign := e.Sign
+		e = amzec2.New(e.Auth, e.Region, func(req *http.Request, auth aws.Auth) error {
+			err := sign(req, auth)
+			query = req.URL.Query()
+			return err
+		})
+		return realRunInstances(e, ctx, ri, c)
+	})
+
+	params := environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		StatusCallback: fakeCallback,
+		Constraints:    constraints.MustParse("instance-lifecycle=spot spot-max-price=0.05"),
+	}
+	_, err := testing.StartInstanceWithParams(env, t.callCtx, "1", params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(query.Get("InstanceMarketOptions.MarketType"), gc.Equals, "spot")
+	c.Assert(query.Get("InstanceMarketOptions.SpotOptions.MaxPrice"), gc.Equals, "0.05")
+	// Instance market options are only understood by newer API versions.
+	c.Assert(query.Get("Version"), gc.Equals, "2016-11-15")
+
+	// On-demand instances are started as before.
+	_, err = testing.StartInstanceWithParams(env, t.callCtx, "2", environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		StatusCallback: fakeCallback,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(query.Get("InstanceMarketOptions.MarketType"), gc.Equals, "")
+	c.Assert(query.Get("Version"), gc.Not(gc.Equals), "2016-11-15")
+}
+
+func (t *localServerSuite) TestReclaimedInstances(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	spot, err := testing.StartInstanceWithParams(env, t.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		StatusCallback: fakeCallback,
+		Constraints:    constraints.MustParse("instance-lifecycle=spot"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	onDemand, _ := testing.AssertStartInstance(c, env, t.callCtx, t.ControllerUUID, "2")
+	ids := []instance.Id{spot.Instance.Id(), onDemand.Id()}
+
+	reclaimed, err := env.(environs.InstanceReclaimDetector).ReclaimedInstances(t.callCtx, ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reclaimed, gc.HasLen, 0)
+
+	// Both instances go away, but only the spot instance is
+	// considered reclaimed.
+	_, err = ec2.EnvironEC2(env).TerminateInstances([]string{string(ids[0]), string(ids[1])})
+	c.Assert(err, jc.ErrorIsNil)
+	reclaimed, err = env.(environs.InstanceReclaimDetector).ReclaimedInstances(t.callCtx, ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reclaimed, jc.DeepEquals, []instance.Id{spot.Instance.Id()})
+}
+
+func (t *localServerSuite) TestUpdateInstanceTags(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	inst, _ := testing.AssertStartInstance(c, env, t.callCtx, t.ControllerUUID, "1")
+	updater := env.(environs.TagUpdater)
+
+	desired := map[string]string{"team": "web"}
+	changed, err := updater.UpdateInstanceTags(t.callCtx, inst.Id(), desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+
+	resp, err := t.client.Instances([]string{string(inst.Id())}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	instTags := make(map[string]string)
+	for _, tag := range resp.Reservations[0].Instances[0].Tags {
+		instTags[tag.Key] = tag.Value
+	}
+	c.Assert(instTags["team"], gc.Equals, "web")
+	c.Assert(instTags[tags.JujuResourceTags], gc.Equals, "team")
+	// Existing tags are left alone.
+	c.Assert(instTags[tags.JujuModel], gc.Equals, coretesting.ModelTag.Id())
+
+	changed, err = updater.UpdateInstanceTags(t.callCtx, inst.Id(), desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsFalse)
+}
+
+func (t *localServerSuite) TestUpdateInstanceTagsRemovesTags(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	inst, _ := testing.AssertStartInstance(c, env, t.callCtx, t.ControllerUUID, "1")
+	updater := env.(environs.TagUpdater)
+
+	// The test server does not support deleting tags.
+	var deleted []string
+	t.PatchValue(ec2.DeleteTags, func(e *amzec2.EC2, ctx context.ProviderCallContext, keys []string, ids ...string) error {
+		c.Check(ids, jc.DeepEquals, []string{string(inst.Id())})
+		deleted = append(deleted, keys...)
+		return nil
+	})
+
+	_, err := updater.UpdateInstanceTags(t.callCtx, inst.Id(), map[string]string{
+		"team":        "web",
+		"cost-centre": "42",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deleted, gc.HasLen, 0)
+
+	changed, err := updater.UpdateInstanceTags(t.callCtx, inst.Id(), map[string]string{"team": "web"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	c.Assert(deleted, jc.DeepEquals, []string{"cost-centre"})
+}
+
+func (t *localServerSuite) TestDeleteTagsRequest(c *gc.C) {
+	var query url.Values
+	client := amzec2.New(t.client.Auth, t.client.Region, func(req *http.Request, auth aws.Auth) error {
+		query = req.URL.Query()
+		return errors.New("not sent")
+	})
+	err := (*ec2.DeleteTags)(client, t.callCtx, []string{"team"}, "i-0")
+	c.Assert(err, gc.ErrorMatches, "not sent")
+	c.Assert(query.Get("Action"), gc.Equals, "DeleteTags")
+	c.Assert(query.Get("ResourceId.1"), gc.Equals, "i-0")
+	c.Assert(query.Get("Tag.1.Key"), gc.Equals, "team")
+	// Specifying a value would only delete tags with that value.
+	_, ok := query["Tag.1.Value"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (t *localServerSuite) TestUpdateInstanceTagsNotFound(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	_, err := env.(environs.TagUpdater).UpdateInstanceTags(t.callCtx, "i-missing", map[string]string{"team": "web"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (t *localServerSuite) TestUpdateVolumeTags(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	resp, err := t.client.CreateVolume(amzec2.CreateVolume{
+		VolumeSize: 1,
+		VolumeType: "gp2",
+		AvailZone:  "test-available",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	updater := env.(environs.TagUpdater)
+
+	desired := map[string]string{"team": "web"}
+	changed, err := updater.UpdateVolumeTags(t.callCtx, resp.Id, desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	changed, err = updater.UpdateVolumeTags(t.callCtx, resp.Id, desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsFalse)
+
+	volumes, err := t.client.Volumes([]string{resp.Id}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTags := make(map[string]string)
+	for _, tag := range volumes.Volumes[0].Tags {
+		volumeTags[tag.Key] = tag.Value
+	}
+	c.Assert(volumeTags, jc.DeepEquals, map[string]string{
+		"team":                "web",
+		tags.JujuResourceTags: "team",
+	})
+}
+
+func (t *localServerSuite) TestUpdateVolumeTagsNotEBS(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	_, err := env.(environs.TagUpdater).UpdateVolumeTags(t.callCtx, "loop0", map[string]string{"team": "web"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (t *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
 	env := t.prepareAndBootstrap(c)
 	_, hc := testing.AssertStartInstance(c, env, t.callCtx, t.ControllerUUID, "1")
@@ -1386,6 +1558,16 @@ func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid constraint value: instance-type=foo\nvalid values are:.*")
 }
 
+func (t *localServerSuite) TestConstraintsValidatorInstanceLifecycle(c *gc.C) {
+	env := t.Prepare(c)
+	validator, err := env.ConstraintsValidator(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("instance-lifecycle=spot"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("instance-lifecycle=preemptible"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: instance-lifecycle=preemptible\nvalid values are: \\[spot\\]")
+}
+
 func (t *localServerSuite) TestConstraintsValidatorVocabNoDefaultOrSpecifiedVPC(c *gc.C) {
 	t.srv.defaultVPC.IsDefault = false
 	err := t.srv.ec2srv.UpdateVPC(*t.srv.defaultVPC)
@@ -1467,6 +1649,16 @@ func (t *localServerSuite) TestPrecheckInstanceUnsupportedArch(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid AWS instance type "cc1.4xlarge" and arch "i386" specified`)
 }
 
+func (t *localServerSuite) TestPrecheckInstanceSpotMaxPriceWithoutSpot(c *gc.C) {
+	env := t.Prepare(c)
+	cons := constraints.MustParse("spot-max-price=0.05")
+	err := env.PrecheckInstance(t.callCtx, environs.PrecheckInstanceParams{
+		Series:      supportedversion.SupportedLTS(),
+		Constraints: cons,
+	})
+	c.Assert(err, gc.ErrorMatches, `"spot-max-price" constraint requires instance-lifecycle=spot`)
+}
+
 func (t *localServerSuite) TestPrecheckInstanceAvailZone(c *gc.C) {
 	env := t.Prepare(c)
 	placement := "zone=test-available"
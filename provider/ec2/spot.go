@@ -0,0 +1,91 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+)
+
+const (
+	// tagInstanceLifecycle is the tag key used to record that an
+	// instance was started on interruptible capacity, so that we
+	// can tell reclaimed instances from ones we terminated.
+	tagInstanceLifecycle = "juju-instance-lifecycle"
+
+	// marketTypeSpot is the instance market type for spot instances.
+	marketTypeSpot = "spot"
+
+	// spotAPIVersion is the first EC2 API version to support instance
+	// market options in RunInstances requests. The client library uses
+	// an older version, which ignores them and starts on-demand
+	// instances.
+	spotAPIVersion = "2016-11-15"
+)
+
+// reclaimedInstanceStates are the states which a reclaimed spot
+// instance may be in.
+var reclaimedInstanceStates = []string{"shutting-down", "terminated", "stopping", "stopped"}
+
+// isSpotInstance reports whether the constraints ask for the
+// instance to be started on the spot market.
+func isSpotInstance(cons constraints.Value) bool {
+	return cons.HasInstanceLifecycle() && *cons.InstanceLifecycle == constraints.InstanceLifecycleSpot
+}
+
+// withSpotMarket returns a copy of the given client which requests
+// spot capacity, capped at maxPrice if it is non-empty, for any
+// instances it runs.
+//
+// The EC2 client library has no support for instance market options,
+// so they are added to RunInstances requests just before signing, along
+// with an API version that supports them.
+func withSpotMarket(client *ec2.EC2, maxPrice string) *ec2.EC2 {
+	sign := client.Sign
+	return ec2.New(client.Auth, client.Region, func(req *http.Request, auth aws.Auth) error {
+		query := req.URL.Query()
+		if query.Get("Action") == "RunInstances" {
+			query.Set("Version", spotAPIVersion)
+			query.Set("InstanceMarketOptions.MarketType", marketTypeSpot)
+			if maxPrice != "" {
+				query.Set("InstanceMarketOptions.SpotOptions.MaxPrice", maxPrice)
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+		return sign(req, auth)
+	})
+}
+
+// ReclaimedInstances is part of the environs.InstanceReclaimDetector
+// interface.
+func (e *environ) ReclaimedInstances(ctx context.ProviderCallContext, ids []instance.Id) ([]instance.Id, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = string(id)
+	}
+	filter := ec2.NewFilter()
+	filter.Add("instance-id", idStrings...)
+	filter.Add("instance-state-name", reclaimedInstanceStates...)
+	filter.Add("tag:"+tagInstanceLifecycle, marketTypeSpot)
+	e.addModelFilter(filter)
+	insts, err := e.allInstances(ctx, filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reclaimed := make([]instance.Id, len(insts))
+	for i, inst := range insts {
+		reclaimed[i] = inst.Id()
+	}
+	return reclaimed, nil
+}
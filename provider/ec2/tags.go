@@ -0,0 +1,125 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+)
+
+// ec2TagsMap converts a list of EC2 tags into a map.
+func ec2TagsMap(ec2Tags []ec2.Tag) map[string]string {
+	m := make(map[string]string, len(ec2Tags))
+	for _, tag := range ec2Tags {
+		m[tag.Key] = tag.Value
+	}
+	return m
+}
+
+// UpdateInstanceTags is part of the environs.TagUpdater interface.
+func (e *environ) UpdateInstanceTags(ctx context.ProviderCallContext, id instance.Id, desired map[string]string) (bool, error) {
+	resp, err := e.ec2.Instances([]string{string(id)}, nil)
+	if ec2ErrCode(err) == "InvalidInstanceID.NotFound" {
+		return false, errors.NotFoundf("instance %q", id)
+	} else if err != nil {
+		return false, maybeConvertCredentialError(err, ctx)
+	}
+	var current []ec2.Tag
+	found := false
+	for _, r := range resp.Reservations {
+		for _, inst := range r.Instances {
+			if inst.InstanceId == string(id) {
+				current = inst.Tags
+				found = true
+			}
+		}
+	}
+	if !found {
+		return false, errors.NotFoundf("instance %q", id)
+	}
+	set, remove := tags.Diff(ec2TagsMap(current), desired)
+	if len(set) == 0 && len(remove) == 0 {
+		return false, nil
+	}
+	if err := tagResources(e.ec2, ctx, set, string(id)); err != nil {
+		return false, errors.Annotatef(err, "tagging instance %q", id)
+	}
+	if err := deleteTags(e.ec2, ctx, remove, string(id)); err != nil {
+		return false, errors.Annotatef(err, "removing tags from instance %q", id)
+	}
+	return true, nil
+}
+
+// UpdateVolumeTags is part of the environs.TagUpdater interface.
+func (e *environ) UpdateVolumeTags(ctx context.ProviderCallContext, volumeId string, desired map[string]string) (bool, error) {
+	if !strings.HasPrefix(volumeId, "vol-") {
+		// Not an EBS volume.
+		return false, errors.NotFoundf("volume %q", volumeId)
+	}
+	resp, err := e.ec2.Volumes([]string{volumeId}, nil)
+	if ec2ErrCode(err) == volumeNotFound {
+		return false, errors.NotFoundf("volume %q", volumeId)
+	} else if err != nil {
+		return false, maybeConvertCredentialError(err, ctx)
+	}
+	if len(resp.Volumes) != 1 || resp.Volumes[0].Id != volumeId {
+		return false, errors.NotFoundf("volume %q", volumeId)
+	}
+	set, remove := tags.Diff(ec2TagsMap(resp.Volumes[0].Tags), desired)
+	if len(set) == 0 && len(remove) == 0 {
+		return false, nil
+	}
+	if err := tagResources(e.ec2, ctx, set, volumeId); err != nil {
+		return false, errors.Annotatef(err, "tagging volume %q", volumeId)
+	}
+	if err := deleteTags(e.ec2, ctx, remove, volumeId); err != nil {
+		return false, errors.Annotatef(err, "removing tags from volume %q", volumeId)
+	}
+	return true, nil
+}
+
+var deleteTags = _deleteTags
+
+// _deleteTags removes the tags with the given names from the
+// specified resources.
+//
+// The EC2 client library has no support for deleting tags, so a
+// CreateTags request is made instead, and turned into a DeleteTags
+// request just before signing. Tag values are dropped from the
+// request, as EC2 would otherwise only delete tags with those values.
+func _deleteTags(client *ec2.EC2, ctx context.ProviderCallContext, keys []string, resourceIds ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	sign := client.Sign
+	client = ec2.New(client.Auth, client.Region, func(req *http.Request, auth aws.Auth) error {
+		query := req.URL.Query()
+		if query.Get("Action") == "CreateTags" {
+			query.Set("Action", "DeleteTags")
+			for k := range query {
+				if strings.HasPrefix(k, "Tag.") && strings.HasSuffix(k, ".Value") {
+					query.Del(k)
+				}
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+		return sign(req, auth)
+	})
+	ec2Tags := make([]ec2.Tag, len(keys))
+	for i, k := range keys {
+		ec2Tags[i] = ec2.Tag{Key: k}
+	}
+	if _, err := client.CreateTags(resourceIds, ec2Tags); err != nil {
+		return maybeConvertCredentialError(err, ctx)
+	}
+	return nil
+}
@@ -31,9 +31,12 @@ type gceConnection interface {
 	AddInstance(spec google.InstanceSpec) (*google.Instance, error)
 	RemoveInstances(prefix string, ids ...string) error
 	UpdateMetadata(key, value string, ids ...string) error
+	RemoveMetadata(key string, ids ...string) error
 
 	IngressRules(fwname string) ([]network.IngressRule, error)
-	OpenPorts(fwname string, rules ...network.IngressRule) error
+	// OpenPorts opens the ports on each of the named networks, or
+	// on the default network if none are named.
+	OpenPorts(fwname string, networks []string, rules ...network.IngressRule) error
 	ClosePorts(fwname string, rules ...network.IngressRule) error
 
 	AvailabilityZones(region string) ([]google.AvailabilityZone, error)
@@ -213,13 +216,13 @@ func (env *environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.Pro
 		params.ControllerConfig.APIPort(),
 		params.ControllerConfig.APIPort(),
 	)
-	if err := env.gce.OpenPorts(env.globalFirewallName(), rule); err != nil {
+	if err := env.gce.OpenPorts(env.globalFirewallName(), nil, rule); err != nil {
 		return nil, errors.Trace(err)
 	}
 	if params.ControllerConfig.AutocertDNSName() != "" {
 		// Open port 80 as well as it handles Let's Encrypt HTTP challenge.
 		rule = network.NewOpenIngressRule("tcp", 80, 80)
-		if err := env.gce.OpenPorts(env.globalFirewallName(), rule); err != nil {
+		if err := env.gce.OpenPorts(env.globalFirewallName(), nil, rule); err != nil {
 			return nil, errors.Trace(err)
 		}
 	}
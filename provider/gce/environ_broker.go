@@ -5,11 +5,14 @@ package gce
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	jujuos "github.com/juju/os"
 	"github.com/juju/os/series"
 	"github.com/juju/utils"
+	"google.golang.org/api/compute/v1"
 
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/cloudconfig/providerinit"
@@ -19,6 +22,7 @@ import (
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/gce/google"
 	"github.com/juju/juju/tools"
@@ -165,19 +169,31 @@ func (env *environ) newRawInstance(args environs.StartInstanceParams, spec *inst
 		return nil, common.ZoneIndependentError(err)
 	}
 
+	networks, err := env.instanceNetworks(args)
+	if err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
+	// Firewall rules apply to a single network, so the ports that
+	// are open for the whole model must be opened on the instance's
+	// networks too.
+	if err := env.openGlobalPortsOnNetworks(networks); err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
+
 	// TODO(ericsnow) Use the env ID for the network name (instead of default)?
 	// TODO(ericsnow) Make the network name configurable?
-	// TODO(ericsnow) Support multiple networks?
 	// TODO(ericsnow) Use a different net interface name? Configurable?
 	inst, err := env.gce.AddInstance(google.InstanceSpec{
-		ID:                hostname,
-		Type:              spec.InstanceType.Name,
-		Disks:             disks,
-		NetworkInterfaces: []string{"ExternalNAT"},
-		Metadata:          metadata,
-		Tags:              tags,
-		AvailabilityZone:  args.AvailabilityZone,
-		// Network is omitted (left empty).
+		ID:                 hostname,
+		Type:               spec.InstanceType.Name,
+		Disks:              disks,
+		Network:            networks[0],
+		NetworkInterfaces:  []string{"ExternalNAT"},
+		AdditionalNetworks: networks[1:],
+		Metadata:           metadata,
+		Tags:               tags,
+		AvailabilityZone:   args.AvailabilityZone,
+		Preemptible:        isPreemptible(args.Constraints),
 	})
 	if err != nil {
 		// We currently treat all AddInstance failures
@@ -188,6 +204,98 @@ func (env *environ) newRawInstance(args environs.StartInstanceParams, spec *inst
 	return inst, nil
 }
 
+// instanceNetworks returns the specs of the networks to which a new
+// instance should be connected, so that it is in the subnetworks of
+// the spaces it is constrained to and bound to. The instance's
+// external access is through the first network. If no spaces are
+// required, the instance is connected to the default network.
+func (env *environ) instanceNetworks(args environs.StartInstanceParams) ([]google.NetworkSpec, error) {
+	subnetIds := spaceSubnetIds(args.SubnetsToZones, args.EndpointBindings)
+	if len(subnetIds) == 0 {
+		return []google.NetworkSpec{{}}, nil
+	}
+
+	subnets, err := env.gce.Subnetworks(env.cloud.Region)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	networks, err := env.networksByURL()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnetsByName := make(map[string]*compute.Subnetwork)
+	for _, subnet := range subnets {
+		subnetsByName[subnet.Name] = subnet
+	}
+	legacyNetworks := make(map[string]*compute.Network)
+	for _, netwk := range networks {
+		if netwk.IPv4Range != "" {
+			legacyNetworks[netwk.Name] = netwk
+		}
+	}
+
+	var results []google.NetworkSpec
+	networkSubnets := make(map[string]string)
+	for _, subnetId := range subnetIds {
+		var spec google.NetworkSpec
+		if subnet, ok := subnetsByName[subnetId]; ok {
+			netwk, ok := networks[subnet.Network]
+			if !ok {
+				return nil, errors.NotFoundf("network %q for subnet %q", subnet.Network, subnet.Name)
+			}
+			spec = google.NetworkSpec{
+				Name:       netwk.Name,
+				Subnetwork: subnet.SelfLink,
+			}
+		} else if netwk, ok := legacyNetworks[subnetId]; ok {
+			spec = google.NetworkSpec{Name: netwk.Name}
+		} else {
+			return nil, errors.NotFoundf("subnet %q", subnetId)
+		}
+		if other, ok := networkSubnets[spec.Name]; ok {
+			return nil, errors.Errorf(
+				"subnets %q and %q are both in network %q, and an instance can have only one interface per network",
+				other, subnetId, spec.Name,
+			)
+		}
+		networkSubnets[spec.Name] = subnetId
+		results = append(results, spec)
+	}
+	return results, nil
+}
+
+// spaceSubnetIds returns the IDs of the subnets to which an instance
+// must be connected. Each space is a single subnet, so the provider ID
+// of a bound space is the ID of its subnet. Any one of the subnets
+// allowed by the spaces constraint will do, so one that is also bound
+// is preferred; it is returned first.
+func spaceSubnetIds(subnetsToZones map[network.Id][]string, endpointBindings map[string]network.Id) []string {
+	bound := set.NewStrings()
+	for _, spaceProviderId := range endpointBindings {
+		if spaceProviderId != "" {
+			bound.Add(string(spaceProviderId))
+		}
+	}
+	var results []string
+	if len(subnetsToZones) > 0 {
+		allowed := make([]string, 0, len(subnetsToZones))
+		for subnetId := range subnetsToZones {
+			allowed = append(allowed, string(subnetId))
+		}
+		sort.Strings(allowed)
+		chosen := allowed[0]
+		for _, subnetId := range allowed {
+			if bound.Contains(subnetId) {
+				chosen = subnetId
+				break
+			}
+		}
+		results = append(results, chosen)
+		bound.Remove(chosen)
+	}
+	return append(results, bound.SortedValues()...)
+}
+
 // getMetadata builds the raw "user-defined" metadata for the new
 // instance (relative to the provided args) and returns it.
 func getMetadata(args environs.StartInstanceParams, os jujuos.OSType) (map[string]string, error) {
@@ -11,15 +11,19 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	"github.com/juju/version"
+	"google.golang.org/api/compute/v1"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
 	"github.com/juju/juju/storage"
 )
 
@@ -159,13 +163,171 @@ func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
 }
 
 func (s *environBrokerSuite) TestNewRawInstanceZoneSpecificError(c *gc.C) {
+	// Fail AddInstance, which follows the check for open ports.
 	s.FakeConn.Err = errors.New("blargh")
+	s.FakeConn.FailOnCall = 1
 
 	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
 	c.Assert(err, gc.ErrorMatches, "blargh")
 	c.Assert(err, gc.Not(jc.Satisfies), environs.IsAvailabilityZoneIndependent)
 }
 
+func (s *environBrokerSuite) setUpNetworks() {
+	s.FakeConn.Networks_ = []*compute.Network{{
+		Name:     "go-team1",
+		SelfLink: "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/go-team1",
+	}, {
+		Name:     "albini",
+		SelfLink: "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/albini",
+	}, {
+		Name:      "legacy",
+		IPv4Range: "10.240.0.0/16",
+		SelfLink:  "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/legacy",
+	}}
+	s.FakeConn.Subnets = []*compute.Subnetwork{{
+		Name:        "go-team",
+		IpCidrRange: "10.0.10.0/24",
+		Network:     "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/go-team1",
+		SelfLink:    "https://www.googleapis.com/compute/v1/projects/sonic-youth/regions/us-east1/subnetworks/go-team",
+	}, {
+		Name:        "shellac",
+		IpCidrRange: "10.0.20.0/24",
+		Network:     "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/albini",
+		SelfLink:    "https://www.googleapis.com/compute/v1/projects/sonic-youth/regions/us-east1/subnetworks/shellac",
+	}, {
+		Name:        "flour",
+		IpCidrRange: "10.0.30.0/24",
+		Network:     "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/albini",
+		SelfLink:    "https://www.googleapis.com/compute/v1/projects/sonic-youth/regions/us-east1/subnetworks/flour",
+	}}
+}
+
+func (s *environBrokerSuite) addInstanceSpec(c *gc.C) google.InstanceSpec {
+	for _, call := range s.FakeConn.Calls {
+		if call.FuncName == "AddInstance" {
+			return call.InstanceSpec
+		}
+	}
+	c.Fatalf("AddInstance not called")
+	return google.InstanceSpec{}
+}
+
+func (s *environBrokerSuite) TestNewRawInstanceDefaultNetwork(c *gc.C) {
+	s.FakeConn.Inst = s.BaseInstance
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spec := s.addInstanceSpec(c)
+	c.Check(spec.Network, jc.DeepEquals, google.NetworkSpec{})
+	c.Check(spec.NetworkInterfaces, jc.DeepEquals, []string{"ExternalNAT"})
+	c.Check(spec.AdditionalNetworks, gc.HasLen, 0)
+}
+
+func (s *environBrokerSuite) TestNewRawInstanceOpensGlobalPorts(c *gc.C) {
+	s.setUpNetworks()
+	s.FakeConn.Inst = s.BaseInstance
+	s.FakeConn.Rules = s.Rules
+	s.StartInstArgs.SubnetsToZones = map[network.Id][]string{
+		"go-team": {"home-zone"},
+	}
+	s.StartInstArgs.EndpointBindings = map[string]network.Id{
+		"db": "shellac",
+	}
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	called, calls := s.FakeConn.WasCalled("OpenPorts")
+	c.Assert(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 1)
+	c.Check(calls[0].FirewallName, gc.Equals, gce.GlobalFirewallName(s.Env))
+	c.Check(calls[0].Networks, jc.SameContents, []string{"go-team1", "albini"})
+	c.Check(calls[0].Rules, jc.DeepEquals, s.Rules)
+}
+
+func (s *environBrokerSuite) TestNewRawInstancePreemptible(c *gc.C) {
+	s.FakeConn.Inst = s.BaseInstance
+	s.StartInstArgs.Constraints = constraints.MustParse("instance-lifecycle=preemptible")
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spec := s.addInstanceSpec(c)
+	c.Check(spec.Preemptible, jc.IsTrue)
+}
+
+func (s *environBrokerSuite) TestNewRawInstanceSpaces(c *gc.C) {
+	s.setUpNetworks()
+	s.FakeConn.Inst = s.BaseInstance
+	s.StartInstArgs.SubnetsToZones = map[network.Id][]string{
+		"go-team": {"home-zone"},
+	}
+	s.StartInstArgs.EndpointBindings = map[string]network.Id{
+		"db":      "shellac",
+		"website": "go-team",
+		"cache":   "legacy",
+	}
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spec := s.addInstanceSpec(c)
+	c.Check(spec.Network, jc.DeepEquals, google.NetworkSpec{
+		Name:       "go-team1",
+		Subnetwork: "https://www.googleapis.com/compute/v1/projects/sonic-youth/regions/us-east1/subnetworks/go-team",
+	})
+	c.Check(spec.NetworkInterfaces, jc.DeepEquals, []string{"ExternalNAT"})
+	c.Check(spec.AdditionalNetworks, jc.DeepEquals, []google.NetworkSpec{{
+		Name: "legacy",
+	}, {
+		Name:       "albini",
+		Subnetwork: "https://www.googleapis.com/compute/v1/projects/sonic-youth/regions/us-east1/subnetworks/shellac",
+	}})
+}
+
+func (s *environBrokerSuite) TestNewRawInstanceSpacesPrefersBoundSubnet(c *gc.C) {
+	s.setUpNetworks()
+	s.FakeConn.Inst = s.BaseInstance
+	s.StartInstArgs.SubnetsToZones = map[network.Id][]string{
+		"go-team": {"home-zone"},
+		"shellac": {"home-zone"},
+	}
+	s.StartInstArgs.EndpointBindings = map[string]network.Id{
+		"db": "shellac",
+	}
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	spec := s.addInstanceSpec(c)
+	c.Check(spec.Network.Name, gc.Equals, "albini")
+	c.Check(spec.AdditionalNetworks, gc.HasLen, 0)
+}
+
+func (s *environBrokerSuite) TestNewRawInstanceSpacesSameNetwork(c *gc.C) {
+	s.setUpNetworks()
+	s.StartInstArgs.EndpointBindings = map[string]network.Id{
+		"db":      "shellac",
+		"website": "flour",
+	}
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, gc.ErrorMatches, `subnets "flour" and "shellac" are both in network "albini", .*`)
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+}
+
+func (s *environBrokerSuite) TestNewRawInstanceSpacesUnknownSubnet(c *gc.C) {
+	s.setUpNetworks()
+	s.StartInstArgs.SubnetsToZones = map[network.Id][]string{
+		"brunettes": {"home-zone"},
+	}
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, gc.ErrorMatches, `subnet "brunettes" not found`)
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+}
+
 func (s *environBrokerSuite) TestGetMetadataUbuntu(c *gc.C) {
 	metadata, err := gce.GetMetadata(s.StartInstArgs, jujuos.Ubuntu)
 
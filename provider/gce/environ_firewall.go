@@ -4,11 +4,15 @@
 package gce
 
 import (
+	"path"
+
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/gce/google"
 )
 
 // globalFirewallName returns the name to use for the global firewall.
@@ -16,11 +20,39 @@ func (env *environ) globalFirewallName() string {
 	return common.EnvFullName(env.uuid)
 }
 
-// OpenPorts opens the given port ranges for the whole environment.
+// OpenPorts opens the given port ranges for the whole environment, on
+// every network that the environment's instances are connected to.
 // Must only be used if the environment was setup with the
 // FwGlobal firewall mode.
 func (env *environ) OpenPorts(ctx context.ProviderCallContext, rules []network.IngressRule) error {
-	err := env.gce.OpenPorts(env.globalFirewallName(), rules...)
+	instances, err := env.gceInstances()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	networks := set.NewStrings()
+	for _, inst := range instances {
+		networks = networks.Union(set.NewStrings(google.NetworkNames(inst.NetworkInterfaces...)...))
+	}
+	err = env.gce.OpenPorts(env.globalFirewallName(), networks.SortedValues(), rules...)
+	return errors.Trace(err)
+}
+
+// openGlobalPortsOnNetworks opens the port ranges that are open for
+// the whole environment on each of the given networks, so that they
+// are also open for instances connected to those networks.
+func (env *environ) openGlobalPortsOnNetworks(networks []google.NetworkSpec) error {
+	rules, err := env.gce.IngressRules(env.globalFirewallName())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	names := make([]string, len(networks))
+	for i, spec := range networks {
+		names[i] = path.Base(spec.Path())
+	}
+	err = env.gce.OpenPorts(env.globalFirewallName(), names, rules...)
 	return errors.Trace(err)
 }
 
@@ -5,9 +5,11 @@ package gce_test
 
 import (
 	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/compute/v1"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
 )
 
 type environFirewallSuite struct {
@@ -34,10 +36,38 @@ func (s *environFirewallSuite) TestOpenPortsAPI(c *gc.C) {
 	err := s.Env.OpenPorts(s.CallCtx, s.Rules)
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
-	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "OpenPorts")
-	c.Check(s.FakeConn.Calls[0].FirewallName, gc.Equals, fwname)
-	c.Check(s.FakeConn.Calls[0].Rules, jc.DeepEquals, s.Rules)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Instances")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "OpenPorts")
+	c.Check(s.FakeConn.Calls[1].FirewallName, gc.Equals, fwname)
+	c.Check(s.FakeConn.Calls[1].Networks, gc.HasLen, 0)
+	c.Check(s.FakeConn.Calls[1].Rules, jc.DeepEquals, s.Rules)
+}
+
+func (s *environFirewallSuite) TestOpenPortsInstanceNetworks(c *gc.C) {
+	s.FakeConn.Insts = []google.Instance{
+		*google.NewInstance(google.InstanceSummary{
+			ID: "spam",
+			NetworkInterfaces: []*compute.NetworkInterface{{
+				Network: "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/go-team",
+			}, {
+				Network: "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/albini",
+			}},
+		}, nil),
+		*google.NewInstance(google.InstanceSummary{
+			ID: "eggs",
+			NetworkInterfaces: []*compute.NetworkInterface{{
+				Network: "https://www.googleapis.com/compute/v1/projects/sonic-youth/global/networks/go-team",
+			}},
+		}, nil),
+	}
+	err := s.Env.OpenPorts(s.CallCtx, s.Rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	called, calls := s.FakeConn.WasCalled("OpenPorts")
+	c.Assert(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 1)
+	c.Check(calls[0].Networks, jc.DeepEquals, []string{"albini", "go-team"})
 }
 
 func (s *environFirewallSuite) TestClosePorts(c *gc.C) {
@@ -138,6 +138,38 @@ func (env *environ) AdoptResources(ctx context.ProviderCallContext, controllerUU
 	return errors.Trace(env.gce.UpdateMetadata(tags.JujuController, controllerUUID, stringIds...))
 }
 
+// isPreemptible reports whether the constraints ask for the instance
+// to be started on preemptible capacity.
+func isPreemptible(cons constraints.Value) bool {
+	return cons.HasInstanceLifecycle() && *cons.InstanceLifecycle == constraints.InstanceLifecyclePreemptible
+}
+
+// ReclaimedInstances is part of the environs.InstanceReclaimDetector
+// interface. Preemptible instances which GCE has stopped are reported
+// as reclaimed.
+func (env *environ) ReclaimedInstances(ctx context.ProviderCallContext, ids []instance.Id) ([]instance.Id, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	prefix := env.namespace.Prefix()
+	instances, err := env.gce.Instances(prefix, google.StatusStopping, google.StatusTerminated)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	wanted := make(map[instance.Id]bool)
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var reclaimed []instance.Id
+	for _, inst := range instances {
+		id := instance.Id(inst.ID)
+		if inst.Preemptible && wanted[id] {
+			reclaimed = append(reclaimed, id)
+		}
+	}
+	return reclaimed, nil
+}
+
 // TODO(ericsnow) Turn into an interface.
 type instPlacement struct {
 	Zone *google.AvailabilityZone
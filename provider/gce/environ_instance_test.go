@@ -138,6 +138,132 @@ func (s *environInstSuite) TestControllerInstancesMixed(c *gc.C) {
 	c.Check(ids, jc.DeepEquals, []instance.Id{"spam"})
 }
 
+func (s *environInstSuite) TestReclaimedInstances(c *gc.C) {
+	spam := s.NewBaseInstance(c, "spam")
+	spam.InstanceSummary.Preemptible = true
+	ham := s.NewBaseInstance(c, "ham")
+	eggs := s.NewBaseInstance(c, "eggs")
+	eggs.InstanceSummary.Preemptible = true
+	s.FakeConn.Insts = []google.Instance{*spam, *ham, *eggs}
+
+	ids, err := s.Env.ReclaimedInstances(s.CallCtx, []instance.Id{"spam", "ham"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ids, jc.DeepEquals, []instance.Id{"spam"})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Instances")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, s.Prefix())
+	c.Check(s.FakeConn.Calls[0].Statuses, jc.DeepEquals, []string{google.StatusStopping, google.StatusTerminated})
+}
+
+func (s *environInstSuite) TestReclaimedInstancesNoIds(c *gc.C) {
+	ids, err := s.Env.ReclaimedInstances(s.CallCtx, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ids, gc.HasLen, 0)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 0)
+}
+
+func (s *environInstSuite) TestUpdateInstanceTags(c *gc.C) {
+	spam := s.NewBaseInstance(c, "spam")
+	s.FakeConn.Insts = []google.Instance{*spam}
+
+	changed, err := s.Env.UpdateInstanceTags(s.CallCtx, "spam", map[string]string{
+		tags.JujuController: s.ControllerUUID,
+		"team":              "web",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(changed, jc.IsTrue)
+
+	called, calls := s.FakeConn.WasCalled("UpdateMetadata")
+	c.Check(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 2)
+	updated := make(map[string]string)
+	for _, call := range calls {
+		c.Check(call.IDs, jc.DeepEquals, []string{"spam"})
+		updated[call.Key] = call.Value
+	}
+	c.Check(updated, jc.DeepEquals, map[string]string{
+		"team":                "web",
+		tags.JujuResourceTags: "team",
+	})
+}
+
+func (s *environInstSuite) TestUpdateInstanceTagsRemovesTags(c *gc.C) {
+	metadata := make(map[string]string)
+	for k, v := range s.UbuntuMetadata {
+		metadata[k] = v
+	}
+	metadata["team"] = "web"
+	metadata[tags.JujuResourceTags] = "team"
+	s.UbuntuMetadata = metadata
+	spam := s.NewBaseInstance(c, "spam")
+	s.FakeConn.Insts = []google.Instance{*spam}
+
+	changed, err := s.Env.UpdateInstanceTags(s.CallCtx, "spam", map[string]string{
+		tags.JujuController: s.ControllerUUID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(changed, jc.IsTrue)
+
+	called, _ := s.FakeConn.WasCalled("UpdateMetadata")
+	c.Check(called, jc.IsFalse)
+	called, calls := s.FakeConn.WasCalled("RemoveMetadata")
+	c.Check(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 2)
+	c.Check(calls[0].Key, gc.Equals, tags.JujuResourceTags)
+	c.Check(calls[1].Key, gc.Equals, "team")
+	c.Check(calls[1].IDs, jc.DeepEquals, []string{"spam"})
+}
+
+func (s *environInstSuite) TestUpdateInstanceTagsNoDrift(c *gc.C) {
+	spam := s.NewBaseInstance(c, "spam")
+	s.FakeConn.Insts = []google.Instance{*spam}
+
+	changed, err := s.Env.UpdateInstanceTags(s.CallCtx, "spam", map[string]string{
+		tags.JujuController: s.ControllerUUID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(changed, jc.IsFalse)
+
+	called, _ := s.FakeConn.WasCalled("UpdateMetadata")
+	c.Check(called, jc.IsFalse)
+}
+
+func (s *environInstSuite) TestUpdateInstanceTagsNotFound(c *gc.C) {
+	_, err := s.Env.UpdateInstanceTags(s.CallCtx, "spam", map[string]string{"team": "web"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *environInstSuite) TestUpdateVolumeTags(c *gc.C) {
+	s.FakeConn.GoogleDisk = s.BaseDisk
+
+	changed, err := s.Env.UpdateVolumeTags(s.CallCtx, s.BaseDisk.Name, map[string]string{
+		tags.JujuModel:      "foo",
+		tags.JujuController: s.ControllerUUID,
+		"team":              "web", // not a valid disk label, so dropped
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(changed, jc.IsTrue)
+
+	called, calls := s.FakeConn.WasCalled("SetDiskLabels")
+	c.Check(called, jc.IsTrue)
+	c.Assert(calls, gc.HasLen, 1)
+	c.Check(calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(calls[0].LabelFingerprint, gc.Equals, "foo")
+	c.Check(calls[0].Labels, jc.DeepEquals, map[string]string{
+		"yodel":             "eh",
+		tags.JujuModel:      "foo",
+		tags.JujuController: s.ControllerUUID,
+	})
+}
+
+func (s *environInstSuite) TestUpdateVolumeTagsNotGCE(c *gc.C) {
+	_, err := s.Env.UpdateVolumeTags(s.CallCtx, "loop0", map[string]string{"team": "web"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 0)
+}
+
 func (s *environInstSuite) TestParsePlacement(c *gc.C) {
 	zone := google.NewZone("a-zone", google.StatusUp, "", "")
 	s.FakeConn.Zones = []google.AvailabilityZone{zone}
@@ -177,6 +177,7 @@ func (e *environ) NetworkInterfaces(ctx context.ProviderCallContext, instId inst
 			ProviderId:        network.Id(fmt.Sprintf("%s/%s", instId, iface.Name)),
 			ProviderSubnetId:  details.subnet,
 			ProviderNetworkId: details.network,
+			ProviderSpaceId:   details.space,
 			AvailabilityZones: copyStrings(zones),
 			InterfaceName:     iface.Name,
 			Address:           network.NewScopedAddress(iface.NetworkIP, network.ScopeCloudLocal),
@@ -193,6 +194,7 @@ type networkDetails struct {
 	cidr    string
 	subnet  network.Id
 	network network.Id
+	space   network.Id
 }
 
 // findNetworkDetails looks up the network information we need to
@@ -210,6 +212,7 @@ func findNetworkDetails(iface compute.NetworkInterface, subnets subnetMap, netwo
 		result.cidr = netwk.IPv4Range
 		result.subnet = ""
 		result.network = network.Id(netwk.Name)
+		result.space = network.Id(netwk.Name)
 	} else {
 		subnet, ok := subnets[iface.Subnetwork]
 		if !ok {
@@ -218,6 +221,7 @@ func findNetworkDetails(iface compute.NetworkInterface, subnets subnetMap, netwo
 		result.cidr = subnet.CIDR
 		result.subnet = subnet.ProviderId
 		result.network = subnet.ProviderNetworkId
+		result.space = subnet.SpaceProviderId
 	}
 	return result, nil
 }
@@ -254,17 +258,36 @@ func (e *environ) subnetsByURL(urls []string, networks networkMap, zones []strin
 
 // SupportsSpaces implements environs.NetworkingEnviron.
 func (e *environ) SupportsSpaces(ctx context.ProviderCallContext) (bool, error) {
-	return false, nil
+	return true, nil
 }
 
 // SupportsSpaceDiscovery implements environs.NetworkingEnviron.
 func (e *environ) SupportsSpaceDiscovery(ctx context.ProviderCallContext) (bool, error) {
-	return false, nil
+	return true, nil
 }
 
 // Spaces implements environs.NetworkingEnviron.
+//
+// Each subnetwork in the region is modelled as a space of its own,
+// named after the subnetwork, as is each legacy network.
 func (e *environ) Spaces(ctx context.ProviderCallContext) ([]network.SpaceInfo, error) {
-	return nil, errors.NotSupportedf("spaces")
+	zones, err := e.zoneNames(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets, err := e.getMatchingSubnets(&includeAny{}, zones)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]network.SpaceInfo, len(subnets))
+	for i, subnet := range subnets {
+		results[i] = network.SpaceInfo{
+			Name:       string(subnet.SpaceProviderId),
+			ProviderId: subnet.SpaceProviderId,
+			Subnets:    []network.SubnetInfo{subnet},
+		}
+	}
+	return results, nil
 }
 
 // SupportsContainerAddresses implements environs.NetworkingEnviron.
@@ -334,7 +357,8 @@ func makeSubnetInfo(subnetId network.Id, networkId network.Id, cidr string, zone
 		CIDR:              cidr,
 		AvailabilityZones: copyStrings(zones),
 		VLANTag:           0,
-		SpaceProviderId:   "",
+		// Each subnet is a space of its own.
+		SpaceProviderId: subnetId,
 	}
 }
 
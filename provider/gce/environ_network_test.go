@@ -89,21 +89,21 @@ func (s *environNetSuite) TestGettingAllSubnets(c *gc.C) {
 		CIDR:              "10.0.10.0/24",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		VLANTag:           0,
-		SpaceProviderId:   "",
+		SpaceProviderId:   "go-team",
 	}, {
 		ProviderId:        "shellac",
 		ProviderNetworkId: "albini",
 		CIDR:              "10.0.20.0/24",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		VLANTag:           0,
-		SpaceProviderId:   "",
+		SpaceProviderId:   "shellac",
 	}, {
 		ProviderId:        "legacy",
 		ProviderNetworkId: "legacy",
 		CIDR:              "10.240.0.0/16",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		VLANTag:           0,
-		SpaceProviderId:   "",
+		SpaceProviderId:   "legacy",
 	}})
 }
 
@@ -133,7 +133,7 @@ func (s *environNetSuite) TestRestrictingToSubnets(c *gc.C) {
 		CIDR:              "10.0.20.0/24",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		VLANTag:           0,
-		SpaceProviderId:   "",
+		SpaceProviderId:   "shellac",
 	}})
 }
 
@@ -159,7 +159,7 @@ func (s *environNetSuite) TestSpecificInstance(c *gc.C) {
 		CIDR:              "10.0.10.0/24",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		VLANTag:           0,
-		SpaceProviderId:   "",
+		SpaceProviderId:   "go-team",
 	}})
 }
 
@@ -176,7 +176,7 @@ func (s *environNetSuite) TestSpecificInstanceAndRestrictedSubnets(c *gc.C) {
 		CIDR:              "10.0.10.0/24",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		VLANTag:           0,
-		SpaceProviderId:   "",
+		SpaceProviderId:   "go-team",
 	}})
 }
 
@@ -203,6 +203,7 @@ func (s *environNetSuite) TestInterfaces(c *gc.C) {
 		ProviderId:        "moana/somenetif",
 		ProviderSubnetId:  "go-team",
 		ProviderNetworkId: "go-team1",
+		ProviderSpaceId:   "go-team",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		InterfaceName:     "somenetif",
 		InterfaceType:     network.EthernetInterface,
@@ -216,8 +217,6 @@ func (s *environNetSuite) TestInterfaces(c *gc.C) {
 func (s *environNetSuite) TestInterfacesMulti(c *gc.C) {
 	s.cannedData()
 	baseInst := s.NewBaseInstance(c, "moana")
-	// This isn't possible in GCE at the moment, but we don't want to
-	// break when it is.
 	summary := &baseInst.InstanceSummary
 	summary.NetworkInterfaces = append(summary.NetworkInterfaces, &compute.NetworkInterface{
 		Name:       "othernetif",
@@ -241,6 +240,7 @@ func (s *environNetSuite) TestInterfacesMulti(c *gc.C) {
 		ProviderId:        "moana/somenetif",
 		ProviderSubnetId:  "go-team",
 		ProviderNetworkId: "go-team1",
+		ProviderSpaceId:   "go-team",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		InterfaceName:     "somenetif",
 		InterfaceType:     network.EthernetInterface,
@@ -254,6 +254,7 @@ func (s *environNetSuite) TestInterfacesMulti(c *gc.C) {
 		ProviderId:        "moana/othernetif",
 		ProviderSubnetId:  "shellac",
 		ProviderNetworkId: "albini",
+		ProviderSpaceId:   "shellac",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		InterfaceName:     "othernetif",
 		InterfaceType:     network.EthernetInterface,
@@ -291,6 +292,7 @@ func (s *environNetSuite) TestInterfacesLegacy(c *gc.C) {
 		ProviderId:        "moana/somenetif",
 		ProviderSubnetId:  "",
 		ProviderNetworkId: "legacy",
+		ProviderSpaceId:   "legacy",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		InterfaceName:     "somenetif",
 		InterfaceType:     network.EthernetInterface,
@@ -329,6 +331,7 @@ func (s *environNetSuite) TestInterfacesSameSubnetwork(c *gc.C) {
 		ProviderId:        "moana/somenetif",
 		ProviderSubnetId:  "go-team",
 		ProviderNetworkId: "go-team1",
+		ProviderSpaceId:   "go-team",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		InterfaceName:     "somenetif",
 		InterfaceType:     network.EthernetInterface,
@@ -342,6 +345,7 @@ func (s *environNetSuite) TestInterfacesSameSubnetwork(c *gc.C) {
 		ProviderId:        "moana/othernetif",
 		ProviderSubnetId:  "go-team",
 		ProviderNetworkId: "go-team1",
+		ProviderSpaceId:   "go-team",
 		AvailabilityZones: []string{"a-zone", "b-zone"},
 		InterfaceName:     "othernetif",
 		InterfaceType:     network.EthernetInterface,
@@ -351,3 +355,52 @@ func (s *environNetSuite) TestInterfacesSameSubnetwork(c *gc.C) {
 		Address:           network.NewScopedAddress("10.0.10.4", network.ScopeCloudLocal),
 	}})
 }
+
+func (s *environNetSuite) TestSupportsSpaces(c *gc.C) {
+	supported, err := s.NetEnv.SupportsSpaces(s.CallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+
+	supported, err = s.NetEnv.SupportsSpaceDiscovery(s.CallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+}
+
+func (s *environNetSuite) TestSpaces(c *gc.C) {
+	s.cannedData()
+
+	spaces, err := s.NetEnv.Spaces(s.CallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(spaces, gc.DeepEquals, []network.SpaceInfo{{
+		Name:       "go-team",
+		ProviderId: "go-team",
+		Subnets: []network.SubnetInfo{{
+			ProviderId:        "go-team",
+			ProviderNetworkId: "go-team1",
+			CIDR:              "10.0.10.0/24",
+			AvailabilityZones: []string{"a-zone", "b-zone"},
+			SpaceProviderId:   "go-team",
+		}},
+	}, {
+		Name:       "shellac",
+		ProviderId: "shellac",
+		Subnets: []network.SubnetInfo{{
+			ProviderId:        "shellac",
+			ProviderNetworkId: "albini",
+			CIDR:              "10.0.20.0/24",
+			AvailabilityZones: []string{"a-zone", "b-zone"},
+			SpaceProviderId:   "shellac",
+		}},
+	}, {
+		Name:       "legacy",
+		ProviderId: "legacy",
+		Subnets: []network.SubnetInfo{{
+			ProviderId:        "legacy",
+			ProviderNetworkId: "legacy",
+			CIDR:              "10.240.0.0/16",
+			AvailabilityZones: []string{"a-zone", "b-zone"},
+			SpaceProviderId:   "legacy",
+		}},
+	}})
+}
@@ -34,6 +34,7 @@ func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args envir
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.SpotMaxPrice,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -73,6 +74,8 @@ func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (const
 
 	validator.RegisterVocabulary(constraints.Container, []string{vtype})
 
+	validator.RegisterVocabulary(constraints.InstanceLifecycle, []string{constraints.InstanceLifecyclePreemptible})
+
 	return validator, nil
 }
 
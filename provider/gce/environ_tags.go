@@ -0,0 +1,76 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+)
+
+// UpdateInstanceTags is part of the environs.TagUpdater interface.
+// GCE instances carry Juju's resource tags as metadata.
+func (env *environ) UpdateInstanceTags(ctx context.ProviderCallContext, id instance.Id, desired map[string]string) (bool, error) {
+	instances, err := env.gceInstances()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	var current map[string]string
+	found := false
+	for _, inst := range instances {
+		if inst.ID == string(id) {
+			current = inst.Metadata()
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, errors.NotFoundf("instance %q", id)
+	}
+	set, remove := tags.Diff(current, desired)
+	for k, v := range set {
+		if err := env.gce.UpdateMetadata(k, v, string(id)); err != nil {
+			return false, errors.Annotatef(err, "updating metadata for instance %q", id)
+		}
+	}
+	for _, k := range remove {
+		if err := env.gce.RemoveMetadata(k, string(id)); err != nil {
+			return false, errors.Annotatef(err, "removing metadata from instance %q", id)
+		}
+	}
+	return len(set) > 0 || len(remove) > 0, nil
+}
+
+// UpdateVolumeTags is part of the environs.TagUpdater interface.
+// GCE disks carry only those resource tags that are known to be
+// valid disk labels; see resourceTagsToDiskLabels. As these are all
+// Juju's own tags, no labels ever need to be removed.
+func (env *environ) UpdateVolumeTags(ctx context.ProviderCallContext, volumeId string, desired map[string]string) (bool, error) {
+	zone, _, err := parseVolumeId(volumeId)
+	if err != nil {
+		// Not a GCE disk.
+		return false, errors.NotFoundf("volume %q", volumeId)
+	}
+	disk, err := env.gce.Disk(zone, volumeId)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	diff, _ := tags.Diff(disk.Labels, resourceTagsToDiskLabels(desired))
+	if len(diff) == 0 {
+		return false, nil
+	}
+	labels := make(map[string]string)
+	for k, v := range disk.Labels {
+		labels[k] = v
+	}
+	for k, v := range diff {
+		labels[k] = v
+	}
+	if err := env.gce.SetDiskLabels(zone, volumeId, disk.LabelFingerprint, labels); err != nil {
+		return false, errors.Annotatef(err, "updating labels on volume %q", volumeId)
+	}
+	return true, nil
+}
@@ -188,6 +188,55 @@ func (gce *Connection) updateInstanceMetadata(instance *compute.Instance, key, v
 	return errors.Trace(gce.raw.SetMetadata(gce.projectID, zoneName, instance.Name, metadata))
 }
 
+// RemoveMetadata removes the metadata key from all of the instance
+// ids given. The call blocks until all of the instances are updated
+// or the request fails.
+func (gce *Connection) RemoveMetadata(key string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	instances, err := gce.raw.ListInstances(gce.projectID, "")
+	if err != nil {
+		return errors.Annotatef(err, "removing metadata from instances %v", ids)
+	}
+	var failed []string
+	for _, instID := range ids {
+		for _, inst := range instances {
+			if inst.Name == instID {
+				if err := gce.removeInstanceMetadata(inst, key); err != nil {
+					failed = append(failed, instID)
+					logger.Errorf("while removing metadata %q from instance %q: %v", key, instID, err)
+				}
+				break
+			}
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("some metadata removals failed: %v", failed)
+	}
+	return nil
+}
+
+func (gce *Connection) removeInstanceMetadata(instance *compute.Instance, key string) error {
+	metadata := instance.Metadata
+	if findMetadataItem(metadata.Items, key) == nil {
+		// The key's already gone.
+		return nil
+	}
+	items := make([]*compute.MetadataItems, 0, len(metadata.Items))
+	for _, item := range metadata.Items {
+		if item != nil && item.Key == key {
+			continue
+		}
+		items = append(items, item)
+	}
+	metadata.Items = items
+	// The GCE API won't accept a full URL for the zone (lp:1667172).
+	zoneName := path.Base(instance.Zone)
+	return errors.Trace(gce.raw.SetMetadata(gce.projectID, zoneName, instance.Name, metadata))
+}
+
 func findMetadataItem(items []*compute.MetadataItems, key string) *compute.MetadataItems {
 	for _, item := range items {
 		if item == nil {
@@ -463,6 +463,51 @@ func (s *connSuite) TestUpdateMetadataChecksCurrentValue(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
 }
 
+func (s *connSuite) TestRemoveMetadata(c *gc.C) {
+	// Ensure we extract the name from the URL we get on the raw instance.
+	s.RawInstanceFull.Zone = "http://eels/lone/wolf/a-zone"
+	s.RawInstanceFull.Metadata.Items = append(s.RawInstanceFull.Metadata.Items, makeMetadataItems("business", "time"))
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+
+	err := s.Conn.RemoveMetadata("business", s.RawInstanceFull.Name)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
+
+	call := s.FakeConn.Calls[1]
+	c.Check(call.FuncName, gc.Equals, "SetMetadata")
+	c.Check(call.ProjectID, gc.Equals, "spam")
+	c.Check(call.ZoneName, gc.Equals, "a-zone")
+	c.Check(call.InstanceId, gc.Equals, "spam")
+
+	md := call.Metadata
+	c.Check(md.Fingerprint, gc.Equals, "heymumwatchthis")
+	c.Assert(md.Items, gc.HasLen, 1)
+	checkMetadataItems(c, md.Items[0], "eggs", "steak")
+}
+
+func (s *connSuite) TestRemoveMetadataMissingKey(c *gc.C) {
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+	err := s.Conn.RemoveMetadata("business", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Since the instance doesn't have the key we don't issue
+	// the update.
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
+}
+
+func (s *connSuite) TestRemoveMetadataError(c *gc.C) {
+	s.RawInstanceFull.Metadata.Items = append(s.RawInstanceFull.Metadata.Items, makeMetadataItems("business", "time"))
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+	s.FakeConn.Err = errors.New("kablooey")
+	s.FakeConn.FailOnCall = 1
+
+	err := s.Conn.RemoveMetadata("business", "spam")
+	c.Assert(err, gc.ErrorMatches, `some metadata removals failed: \[spam\]`)
+}
+
 func makeMetadataItems(key, value string) *compute.MetadataItems {
 	return &compute.MetadataItems{Key: key, Value: google.StringPtr(value)}
 }
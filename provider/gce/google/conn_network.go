@@ -15,40 +15,72 @@ import (
 	"github.com/juju/juju/network"
 )
 
-// FirewallRules collects the firewall rules for the given name
-// (within the Connection's project) and returns them as a RuleSet. If
-// no rules match the name the RuleSet will be empty and no error is
+// firewallRules collects the firewall rules for the given name
+// (within the Connection's project) and returns them as a RuleSet
+// for each network that they apply to, keyed by the network's name.
+// If no rules match the name the map will be empty and no error is
 // returned.
-func (gce Connection) firewallRules(fwname string) (ruleSet, error) {
+func (gce Connection) firewallRules(fwname string) (map[string]ruleSet, error) {
 	firewalls, err := gce.raw.GetFirewalls(gce.projectID, fwname)
 	if errors.IsNotFound(err) {
-		return make(ruleSet), nil
+		return make(map[string]ruleSet), nil
 	}
 	if err != nil {
 		return nil, errors.Annotate(err, "while getting firewall rules from GCE")
 	}
 
-	return newRuleSetFromFirewalls(firewalls...)
+	networkFirewalls := make(map[string][]*compute.Firewall)
+	for _, fw := range firewalls {
+		name := firewallNetwork(fw)
+		networkFirewalls[name] = append(networkFirewalls[name], fw)
+	}
+	result := make(map[string]ruleSet)
+	for name, firewalls := range networkFirewalls {
+		rules, err := newRuleSetFromFirewalls(firewalls...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[name] = rules
+	}
+	return result, nil
 }
 
 // IngressRules build a list of all open port ranges for a given firewall name
 // (within the Connection's project) and returns it. If the firewall
 // does not exist then the list will be empty and no error is returned.
+// Rules that are open on more than one network are only reported once.
 func (gce Connection) IngressRules(fwname string) ([]network.IngressRule, error) {
-	ruleset, err := gce.firewallRules(fwname)
+	rulesets, err := gce.firewallRules(fwname)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return ruleset.toIngressRules()
+	seen := set.NewStrings()
+	var results []network.IngressRule
+	for _, ruleset := range rulesets {
+		rules, err := ruleset.toIngressRules()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, rule := range rules {
+			if seen.Contains(rule.String()) {
+				continue
+			}
+			seen.Add(rule.String())
+			results = append(results, rule)
+		}
+	}
+	network.SortIngressRules(results)
+	return results, nil
 }
 
-// OpenPorts adds or updates GCE firewall rules so that traffic to the
-// target ports is allowed from the source ranges specified by the
-// ingress rules. If a rule matching a set of source ranges doesn't
-// already exist, it will be created - the name will be made unique
-// using a random suffix.
-func (gce Connection) OpenPorts(target string, rules ...network.IngressRule) error {
-	return errors.Trace(gce.OpenPortsWithNamer(target, RandomSuffixNamer, rules...))
+// OpenPorts adds or updates GCE firewall rules on each of the named
+// networks so that traffic to the target ports is allowed from the
+// source ranges specified by the ingress rules. If no networks are
+// named, the rules are opened on the default network. If a rule
+// matching a set of source ranges doesn't already exist, it will be
+// created - the name will be made unique using a random suffix.
+func (gce Connection) OpenPorts(target string, networks []string, rules ...network.IngressRule) error {
+	return errors.Trace(gce.OpenPortsWithNamer(target, networks, RandomSuffixNamer, rules...))
 }
 
 // FirewallNamer generates a unique name for a firewall given the firewall, a
@@ -59,13 +91,16 @@ type FirewallNamer func(fw *firewall, prefix string, existingNames set.Strings)
 // as OpenPorts, but uses the FirewallNamer passed in to generate the
 // firewall name - this is mostly useful for getting predictable
 // results in tests.
-func (gce Connection) OpenPortsWithNamer(target string, namer FirewallNamer, rules ...network.IngressRule) error {
+func (gce Connection) OpenPortsWithNamer(target string, networks []string, namer FirewallNamer, rules ...network.IngressRule) error {
 	if len(rules) == 0 {
 		return nil
 	}
+	if len(networks) == 0 {
+		networks = []string{networkDefaultName}
+	}
 
 	// First gather the current ingress rules.
-	currentRuleSet, err := gce.firewallRules(target)
+	currentRuleSets, err := gce.firewallRules(target)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -81,48 +116,63 @@ func (gce Connection) OpenPortsWithNamer(target string, namer FirewallNamer, rul
 	}
 	sort.Strings(sortedKeys)
 
-	allNames := currentRuleSet.allNames()
+	allNames := set.NewStrings()
+	for _, currentRuleSet := range currentRuleSets {
+		allNames = allNames.Union(currentRuleSet.allNames())
+	}
 
-	// Get the rules by sorted key for deterministic testing.
-	for _, key := range sortedKeys {
-		inputFirewall := inputRuleSet[key]
+	// Open the rules on each network in turn, in sorted order for
+	// deterministic testing.
+	for _, networkName := range set.NewStrings(networks...).SortedValues() {
+		currentRuleSet := currentRuleSets[networkName]
+		prefix := firewallPrefix(target, networkName)
 
-		// First check to see if there's any existing firewall with the same ports as what we want.
-		existingFirewall, ok := currentRuleSet.matchProtocolPorts(inputFirewall.AllowedPorts)
-		if !ok {
-			// If not, look for any existing firewall with the same source CIDRs.
-			existingFirewall, ok = currentRuleSet.matchSourceCIDRs(inputFirewall.SourceCIDRs)
-		}
+		// Get the rules by sorted key for deterministic testing.
+		for _, key := range sortedKeys {
+			inputFirewall := inputRuleSet[key]
 
-		if !ok {
-			// Create a new firewall.
-			name, err := namer(inputFirewall, target, allNames)
-			if err != nil {
-				return errors.Trace(err)
+			// First check to see if there's any existing firewall with the same ports as what we want.
+			existingFirewall, ok := currentRuleSet.matchProtocolPorts(inputFirewall.AllowedPorts)
+			if !ok {
+				// If not, look for any existing firewall with the same source CIDRs.
+				existingFirewall, ok = currentRuleSet.matchSourceCIDRs(inputFirewall.SourceCIDRs)
 			}
-			allNames.Add(name)
-			spec := firewallSpec(name, target, inputFirewall.SourceCIDRs, inputFirewall.AllowedPorts)
-			if err := gce.raw.AddFirewall(gce.projectID, spec); err != nil {
-				return errors.Annotatef(err, "opening port(s) %+v", rules)
+
+			if !ok {
+				// Create a new firewall.
+				name, err := namer(inputFirewall, prefix, allNames)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				allNames.Add(name)
+				spec := firewallSpec(name, target, networkName, inputFirewall.SourceCIDRs, inputFirewall.AllowedPorts)
+				if err := gce.raw.AddFirewall(gce.projectID, spec); err != nil {
+					return errors.Annotatef(err, "opening port(s) %+v", rules)
+				}
+				continue
 			}
-			continue
-		}
 
-		// An existing firewall exists with either same same ports or the same source
-		// CIDRs as what we have been asked to open. Either way, we just need to update
-		// the existing firewall.
+			// An existing firewall exists with either same same ports or the same source
+			// CIDRs as what we have been asked to open. Either way, we just need to update
+			// the existing firewall.
 
-		// Merge the ports.
-		allowedPorts := existingFirewall.AllowedPorts.union(inputFirewall.AllowedPorts)
+			// Merge the ports.
+			allowedPorts := existingFirewall.AllowedPorts.union(inputFirewall.AllowedPorts)
 
-		// Merge the CIDRs
-		cidrs := set.NewStrings(existingFirewall.SourceCIDRs...)
-		combinedCIDRs := cidrs.Union(set.NewStrings(inputFirewall.SourceCIDRs...)).SortedValues()
+			// Merge the CIDRs
+			cidrs := set.NewStrings(existingFirewall.SourceCIDRs...)
+			combinedCIDRs := cidrs.Union(set.NewStrings(inputFirewall.SourceCIDRs...)).SortedValues()
 
-		// Copy new firewall details into required firewall spec.
-		spec := firewallSpec(existingFirewall.Name, target, combinedCIDRs, allowedPorts)
-		if err := gce.raw.UpdateFirewall(gce.projectID, existingFirewall.Name, spec); err != nil {
-			return errors.Annotatef(err, "opening port(s) %+v", rules)
+			if allowedPorts.String() == existingFirewall.AllowedPorts.String() && len(combinedCIDRs) == cidrs.Size() {
+				// The ports are already open.
+				continue
+			}
+
+			// Copy new firewall details into required firewall spec.
+			spec := firewallSpec(existingFirewall.Name, target, networkName, combinedCIDRs, allowedPorts)
+			if err := gce.raw.UpdateFirewall(gce.projectID, existingFirewall.Name, spec); err != nil {
+				return errors.Annotatef(err, "opening port(s) %+v", rules)
+			}
 		}
 	}
 	return nil
@@ -151,18 +201,36 @@ func RandomSuffixNamer(fw *firewall, prefix string, existingNames set.Strings) (
 }
 
 // ClosePorts sends a request to the GCE API to close the provided port
-// ranges on the named firewall. If the firewall does not exist nothing
-// happens. If the firewall is left with no ports then it is removed.
-// Otherwise it will be left with just the open ports it has that do not
-// match the provided port ranges. The call blocks until the ports are
-// closed or the request fails.
+// ranges on the named firewall, on every network it applies to. If the
+// firewall does not exist nothing happens. If the firewall is left with
+// no ports then it is removed. Otherwise it will be left with just the
+// open ports it has that do not match the provided port ranges. The
+// call blocks until the ports are closed or the request fails.
 func (gce Connection) ClosePorts(target string, rules ...network.IngressRule) error {
 	// First gather the current ingress rules.
-	currentRuleSet, err := gce.firewallRules(target)
+	currentRuleSets, err := gce.firewallRules(target)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if len(currentRuleSets) == 0 {
+		currentRuleSets[networkDefaultName] = make(ruleSet)
+	}
+	var networks []string
+	for networkName := range currentRuleSets {
+		networks = append(networks, networkName)
+	}
+	sort.Strings(networks)
+	for _, networkName := range networks {
+		if err := gce.closePorts(target, networkName, currentRuleSets[networkName], rules...); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
 
+// closePorts closes the provided port ranges on the named firewall's
+// rules for a single network.
+func (gce Connection) closePorts(target, networkName string, currentRuleSet ruleSet, rules ...network.IngressRule) error {
 	// From the input rules, compose the firewall specs we want to add.
 	inputRuleSet := newRuleSetFromRules(rules...)
 
@@ -186,7 +254,7 @@ func (gce Connection) ClosePorts(target string, rules ...network.IngressRule) er
 			}
 
 			// Update the existing firewall with the remaining CIDRs.
-			spec := firewallSpec(existingFirewall.Name, target, remainingCidrs, existingFirewall.AllowedPorts)
+			spec := firewallSpec(existingFirewall.Name, target, networkName, remainingCidrs, existingFirewall.AllowedPorts)
 			if err := gce.raw.UpdateFirewall(gce.projectID, existingFirewall.Name, spec); err != nil {
 				return errors.Annotatef(err, "closing port(s) %+v", rules)
 			}
@@ -208,7 +276,7 @@ func (gce Connection) ClosePorts(target string, rules ...network.IngressRule) er
 		remainingPorts := existingFirewall.AllowedPorts.remove(inputFirewall.AllowedPorts)
 
 		// Copy new firewall details into required firewall spec.
-		spec := firewallSpec(existingFirewall.Name, target, existingFirewall.SourceCIDRs, remainingPorts)
+		spec := firewallSpec(existingFirewall.Name, target, networkName, existingFirewall.SourceCIDRs, remainingPorts)
 		if err := gce.raw.UpdateFirewall(gce.projectID, existingFirewall.Name, spec); err != nil {
 			return errors.Annotatef(err, "closing port(s) %+v", rules)
 		}
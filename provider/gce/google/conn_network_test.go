@@ -130,7 +130,7 @@ func (s *connSuite) TestConnectionOpenPortsAdd(c *gc.C) {
 	rule2 := network.MustNewIngressRule("udp", 80, 81, "0.0.0.0/0")
 	rule3 := network.MustNewIngressRule("tcp", 100, 120, "192.168.1.0/24", "10.0.0.0/24")
 	rule4 := network.MustNewIngressRule("udp", 67, 67, "10.0.0.0/24")
-	err := s.Conn.OpenPortsWithNamer("spam", google.HashSuffixNamer, rule, rule2, rule3, rule4)
+	err := s.Conn.OpenPortsWithNamer("spam", nil, google.HashSuffixNamer, rule, rule2, rule3, rule4)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Check(s.FakeConn.Calls, gc.HasLen, 4)
@@ -138,6 +138,7 @@ func (s *connSuite) TestConnectionOpenPortsAdd(c *gc.C) {
 	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
 	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam-4eebe8d7a9",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"192.168.1.0/24", "10.0.0.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -148,6 +149,7 @@ func (s *connSuite) TestConnectionOpenPortsAdd(c *gc.C) {
 	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "AddFirewall")
 	c.Check(s.FakeConn.Calls[2].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam-a34d80f7b6",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"10.0.0.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -158,6 +160,7 @@ func (s *connSuite) TestConnectionOpenPortsAdd(c *gc.C) {
 	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "AddFirewall")
 	c.Check(s.FakeConn.Calls[3].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"0.0.0.0/0"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -170,6 +173,98 @@ func (s *connSuite) TestConnectionOpenPortsAdd(c *gc.C) {
 	})
 }
 
+func (s *connSuite) TestConnectionOpenPortsNetworks(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		Network:      "https://www.googleapis.com/compute/v1/projects/spam/global/networks/default",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	}}
+
+	rule := network.MustNewIngressRule("tcp", 80, 80)
+	err := s.Conn.OpenPortsWithNamer("spam", []string{"foo", "default"}, google.HashSuffixNamer, rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The port is already open on the default network, so the
+	// rule is only added to the other network.
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewalls")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:         "spam-foo",
+		Network:      "global/networks/foo",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	})
+}
+
+func (s *connSuite) TestConnectionIngressRulesNetworks(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	}, {
+		Name:         "spam-foo",
+		Network:      "global/networks/foo",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80", "443"},
+		}},
+	}}
+
+	ports, err := s.Conn.IngressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ports, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 443, 443, "0.0.0.0/0"),
+	})
+}
+
+func (s *connSuite) TestConnectionClosePortsNetworks(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	}, {
+		Name:         "spam-foo",
+		Network:      "global/networks/foo",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80"},
+		}},
+	}}
+
+	rule := network.MustNewIngressRule("tcp", 80, 80)
+	err := s.Conn.ClosePorts("spam", rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[2].Name, gc.Equals, "spam-foo")
+}
+
 func (s *connSuite) TestConnectionOpenPortsUpdateSameCIDR(c *gc.C) {
 	s.FakeConn.Firewalls = []*compute.Firewall{{
 		Name:         "spam-ad7554",
@@ -182,7 +277,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateSameCIDR(c *gc.C) {
 	}}
 
 	rules := network.MustNewIngressRule("tcp", 443, 443, "192.168.1.0/24", "10.0.0.0/24")
-	err := s.Conn.OpenPortsWithNamer("spam", google.HashSuffixNamer, rules)
+	err := s.Conn.OpenPortsWithNamer("spam", nil, google.HashSuffixNamer, rules)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
@@ -191,6 +286,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateSameCIDR(c *gc.C) {
 	sort.Strings(s.FakeConn.Calls[1].Firewall.Allowed[0].Ports)
 	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam-ad7554",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"10.0.0.0/24", "192.168.1.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -212,7 +308,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateAddCIDR(c *gc.C) {
 	}}
 
 	rules := network.MustNewIngressRule("tcp", 80, 81, "10.0.0.0/24")
-	err := s.Conn.OpenPortsWithNamer("spam", google.HashSuffixNamer, rules)
+	err := s.Conn.OpenPortsWithNamer("spam", nil, google.HashSuffixNamer, rules)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
@@ -222,6 +318,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateAddCIDR(c *gc.C) {
 	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "spam-arbitrary-name")
 	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam-arbitrary-name",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"10.0.0.0/24", "192.168.1.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -254,7 +351,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateAndAdd(c *gc.C) {
 	rule2 := network.MustNewIngressRule("tcp", 80, 100, "10.0.0.0/24")
 	rule3 := network.MustNewIngressRule("tcp", 443, 443, "10.0.0.0/24")
 	rule4 := network.MustNewIngressRule("udp", 67, 67, "172.0.0.0/24")
-	err := s.Conn.OpenPortsWithNamer("spam", google.HashSuffixNamer, rule1, rule2, rule3, rule4)
+	err := s.Conn.OpenPortsWithNamer("spam", nil, google.HashSuffixNamer, rule1, rule2, rule3, rule4)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Check(s.FakeConn.Calls, gc.HasLen, 4)
@@ -264,6 +361,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateAndAdd(c *gc.C) {
 	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "spam-8e65efabcd")
 	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam-8e65efabcd",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"172.0.0.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -278,6 +376,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateAndAdd(c *gc.C) {
 	sort.Strings(s.FakeConn.Calls[2].Firewall.Allowed[0].Ports)
 	c.Check(s.FakeConn.Calls[2].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam-a34d80f7b6",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"10.0.0.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -290,6 +389,7 @@ func (s *connSuite) TestConnectionOpenPortsUpdateAndAdd(c *gc.C) {
 	c.Check(s.FakeConn.Calls[3].Name, gc.Equals, "spam-d01a82")
 	c.Check(s.FakeConn.Calls[3].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam-d01a82",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"192.168.1.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -341,6 +441,7 @@ func (s *connSuite) TestConnectionClosePortsUpdate(c *gc.C) {
 	sort.Strings(s.FakeConn.Calls[1].Firewall.Allowed[0].Ports)
 	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"0.0.0.0/0"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -371,6 +472,7 @@ func (s *connSuite) TestConnectionClosePortsCollapseUpdate(c *gc.C) {
 	sort.Strings(s.FakeConn.Calls[1].Firewall.Allowed[0].Ports)
 	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"0.0.0.0/0"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -402,6 +504,7 @@ func (s *connSuite) TestConnectionClosePortsRemoveCIDR(c *gc.C) {
 	sort.Strings(s.FakeConn.Calls[1].Firewall.Allowed[0].Ports)
 	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
 		Name:         "glass-onion",
+		Network:      "global/networks/default",
 		TargetTags:   []string{"spam"},
 		SourceRanges: []string{"10.0.0.0/24"},
 		Allowed: []*compute.FirewallAllowed{{
@@ -49,6 +49,10 @@ func SetInstanceSpec(inst *Instance, spec *InstanceSpec) {
 	inst.spec = spec
 }
 
+func InstanceSpecRaw(spec InstanceSpec) *compute.Instance {
+	return spec.raw()
+}
+
 func NewNetInterface(spec NetworkSpec, name string) *compute.NetworkInterface {
 	return spec.newInterface(name)
 }
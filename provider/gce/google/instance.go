@@ -40,6 +40,12 @@ type InstanceSpec struct {
 	// be provided.
 	NetworkInterfaces []string
 
+	// AdditionalNetworks identifies further networks to which a new
+	// instance should be connected, each through an interface with
+	// no external access. GCE requires each of an instance's
+	// interfaces to be on a different network.
+	AdditionalNetworks []NetworkSpec
+
 	// Metadata is the GCE instance "user-specified" metadata that will
 	// be initialized on the new instance.
 	Metadata map[string]string
@@ -52,6 +58,11 @@ type InstanceSpec struct {
 	// AvailabilityZone holds the name of the availability zone in which
 	// to create the instance.
 	AvailabilityZone string
+
+	// Preemptible indicates that the instance should be started on
+	// preemptible capacity. GCE may stop preemptible instances at
+	// any time, and they are never restarted automatically.
+	Preemptible bool
 }
 
 func (is InstanceSpec) raw() *compute.Instance {
@@ -61,10 +72,25 @@ func (is InstanceSpec) raw() *compute.Instance {
 		NetworkInterfaces: is.networkInterfaces(),
 		Metadata:          packMetadata(is.Metadata),
 		Tags:              &compute.Tags{Items: is.Tags},
+		Scheduling:        is.scheduling(),
 		// MachineType is set in the addInstance call.
 	}
 }
 
+func (is InstanceSpec) scheduling() *compute.Scheduling {
+	if !is.Preemptible {
+		return nil
+	}
+	// Preemptible instances cannot be restarted automatically or
+	// migrated during host maintenance.
+	automaticRestart := false
+	return &compute.Scheduling{
+		Preemptible:       true,
+		AutomaticRestart:  &automaticRestart,
+		OnHostMaintenance: "TERMINATE",
+	}
+}
+
 // Summary builds an InstanceSummary based on the spec and returns it.
 func (is InstanceSpec) Summary() InstanceSummary {
 	raw := is.raw()
@@ -84,6 +110,9 @@ func (is InstanceSpec) networkInterfaces() []*compute.NetworkInterface {
 	for _, name := range is.NetworkInterfaces {
 		result = append(result, is.Network.newInterface(name))
 	}
+	for _, spec := range is.AdditionalNetworks {
+		result = append(result, spec.newInterface(""))
+	}
 	return result
 }
 
@@ -111,6 +140,9 @@ type InstanceSummary struct {
 	// NetworkInterfaces are the network connections associated with
 	// the instance.
 	NetworkInterfaces []*compute.NetworkInterface
+	// Preemptible indicates whether the instance runs on preemptible
+	// capacity.
+	Preemptible bool
 }
 
 func newInstanceSummary(raw *compute.Instance) InstanceSummary {
@@ -121,6 +153,7 @@ func newInstanceSummary(raw *compute.Instance) InstanceSummary {
 		Metadata:          unpackMetadata(raw.Metadata),
 		Addresses:         extractAddresses(raw.NetworkInterfaces...),
 		NetworkInterfaces: raw.NetworkInterfaces,
+		Preemptible:       raw.Scheduling != nil && raw.Scheduling.Preemptible,
 	}
 }
 
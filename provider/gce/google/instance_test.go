@@ -36,6 +36,42 @@ func (s *instanceSuite) TestNewInstanceNoSpec(c *gc.C) {
 	c.Check(spec, gc.IsNil)
 }
 
+func (s *instanceSuite) TestInstanceSpecAdditionalNetworks(c *gc.C) {
+	s.InstanceSpec.AdditionalNetworks = []google.NetworkSpec{{
+		Name:       "ham",
+		Subnetwork: "regions/a-region/subnetworks/eggs",
+	}}
+	summary := s.InstanceSpec.Summary()
+
+	c.Assert(summary.NetworkInterfaces, gc.HasLen, 2)
+	c.Check(summary.NetworkInterfaces[0].Network, gc.Equals, "global/networks/somenetwork")
+	c.Check(summary.NetworkInterfaces[0].AccessConfigs, gc.HasLen, 1)
+	c.Check(summary.NetworkInterfaces[1], jc.DeepEquals, &compute.NetworkInterface{
+		Network:    "global/networks/ham",
+		Subnetwork: "regions/a-region/subnetworks/eggs",
+	})
+}
+
+func (s *instanceSuite) TestInstanceSpecPreemptible(c *gc.C) {
+	s.InstanceSpec.Preemptible = true
+	raw := google.InstanceSpecRaw(s.InstanceSpec)
+
+	automaticRestart := false
+	c.Check(raw.Scheduling, jc.DeepEquals, &compute.Scheduling{
+		Preemptible:       true,
+		AutomaticRestart:  &automaticRestart,
+		OnHostMaintenance: "TERMINATE",
+	})
+	c.Check(s.InstanceSpec.Summary().Preemptible, jc.IsTrue)
+}
+
+func (s *instanceSuite) TestInstanceSpecNotPreemptible(c *gc.C) {
+	raw := google.InstanceSpecRaw(s.InstanceSpec)
+
+	c.Check(raw.Scheduling, gc.IsNil)
+	c.Check(s.InstanceSpec.Summary().Preemptible, jc.IsFalse)
+}
+
 func (s *instanceSuite) TestInstanceRootDiskGB(c *gc.C) {
 	size := s.Instance.RootDiskGB()
 
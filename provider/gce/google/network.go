@@ -4,8 +4,10 @@
 package google
 
 import (
+	"path"
 	"sort"
 
+	"github.com/juju/collections/set"
 	"google.golang.org/api/compute/v1"
 
 	"github.com/juju/juju/network"
@@ -26,6 +28,10 @@ const (
 type NetworkSpec struct {
 	// Name is the unqualified name of the network.
 	Name string
+	// Subnetwork is the URL of the subnetwork, within the network, to
+	// which an interface is connected. If it is empty then GCE picks
+	// the network's subnetwork in the instance's region.
+	Subnetwork string
 	// TODO(ericsnow) support a CIDR for internal IP addr range?
 }
 
@@ -53,21 +59,24 @@ func (ns *NetworkSpec) newInterface(name string) *compute.NetworkInterface {
 	}
 	return &compute.NetworkInterface{
 		Network:       ns.Path(),
+		Subnetwork:    ns.Subnetwork,
 		AccessConfigs: access,
 	}
 }
 
 // firewallSpec expands a port range set in to compute.FirewallAllowed
-// and returns a compute.Firewall for the provided name.
-func firewallSpec(name, target string, sourceCIDRs []string, ports protocolPorts) *compute.Firewall {
+// and returns a compute.Firewall for the provided name, applying to
+// the named network.
+func firewallSpec(name, target, networkName string, sourceCIDRs []string, ports protocolPorts) *compute.Firewall {
 	if len(sourceCIDRs) == 0 {
 		sourceCIDRs = []string{"0.0.0.0/0"}
 	}
+	netSpec := NetworkSpec{Name: networkName}
 	firewall := compute.Firewall{
 		// Allowed is set below.
 		// Description is not set.
-		Name: name,
-		// Network: (defaults to global)
+		Name:    name,
+		Network: netSpec.Path(),
 		// SourceTags is not set.
 		TargetTags:   []string{target},
 		SourceRanges: sourceCIDRs,
@@ -89,6 +98,39 @@ func firewallSpec(name, target string, sourceCIDRs []string, ports protocolPorts
 	return &firewall
 }
 
+// firewallNetwork returns the name of the network to which the
+// firewall rule applies.
+func firewallNetwork(fw *compute.Firewall) string {
+	if fw.Network == "" {
+		return networkDefaultName
+	}
+	return path.Base(fw.Network)
+}
+
+// firewallPrefix returns the prefix of the names of the firewall rules
+// for the target on the named network. Rules on the default network
+// are named after the target alone, as they always have been.
+func firewallPrefix(target, networkName string) string {
+	if networkName == networkDefaultName {
+		return target
+	}
+	return target + "-" + networkName
+}
+
+// NetworkNames returns the names of the networks to which the given
+// interfaces are connected, without duplicates.
+func NetworkNames(interfaces ...*compute.NetworkInterface) []string {
+	names := set.NewStrings()
+	for _, iface := range interfaces {
+		if iface.Network == "" {
+			names.Add(networkDefaultName)
+			continue
+		}
+		names.Add(path.Base(iface.Network))
+	}
+	return names.SortedValues()
+}
+
 func extractAddresses(interfaces ...*compute.NetworkInterface) []network.Address {
 	var addresses []network.Address
 
@@ -44,6 +44,19 @@ func (s *networkSuite) TestNetworkSpecNewInterface(c *gc.C) {
 	})
 }
 
+func (s *networkSuite) TestNetworkSpecNewInterfaceSubnetwork(c *gc.C) {
+	spec := google.NetworkSpec{
+		Name:       "spam",
+		Subnetwork: "regions/us-east1/subnetworks/ham",
+	}
+	netIF := google.NewNetInterface(spec, "")
+
+	c.Check(netIF, gc.DeepEquals, &compute.NetworkInterface{
+		Network:    "global/networks/spam",
+		Subnetwork: "regions/us-east1/subnetworks/ham",
+	})
+}
+
 type ByIPProtocol []*compute.FirewallAllowed
 
 func (s ByIPProtocol) Len() int {
@@ -61,7 +74,7 @@ func (s *networkSuite) TestFirewallSpec(c *gc.C) {
 		"tcp": {{FromPort: 80, ToPort: 81}, {FromPort: 8888, ToPort: 8888}},
 		"udp": {{FromPort: 1234, ToPort: 1234}},
 	}
-	fw := google.FirewallSpec("spam", "target", []string{"192.168.1.0/24", "10.0.0.0/24"}, ports)
+	fw := google.FirewallSpec("spam", "target", "foo", []string{"192.168.1.0/24", "10.0.0.0/24"}, ports)
 
 	allowed := []*compute.FirewallAllowed{{
 		IPProtocol: "tcp",
@@ -76,6 +89,7 @@ func (s *networkSuite) TestFirewallSpec(c *gc.C) {
 	}
 	c.Check(fw, jc.DeepEquals, &compute.Firewall{
 		Name:         "spam",
+		Network:      "global/networks/foo",
 		TargetTags:   []string{"target"},
 		SourceRanges: []string{"192.168.1.0/24", "10.0.0.0/24"},
 		Allowed:      allowed,
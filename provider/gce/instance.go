@@ -69,14 +69,16 @@ func findInst(id instance.Id, instances []instance.Instance) instance.Instance {
 // firewall stuff
 
 // OpenPorts opens the given ports on the instance, which
-// should have been started with the given machine id.
+// should have been started with the given machine id, on each
+// of the networks it is connected to.
 func (inst *environInstance) OpenPorts(ctx context.ProviderCallContext, machineID string, rules []network.IngressRule) error {
 	// TODO(ericsnow) Make sure machineId matches inst.Id()?
 	name, err := inst.env.namespace.Hostname(machineID)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	err = inst.env.gce.OpenPorts(name, rules...)
+	networks := google.NetworkNames(inst.base.NetworkInterfaces...)
+	err = inst.env.gce.OpenPorts(name, networks, rules...)
 	return errors.Trace(err)
 }
 
@@ -480,6 +480,7 @@ type fakeConnCall struct {
 	Statuses         []string
 	InstanceSpec     google.InstanceSpec
 	FirewallName     string
+	Networks         []string
 	Rules            []network.IngressRule
 	Region           string
 	Disks            []google.DiskSpec
@@ -570,6 +571,15 @@ func (fc *fakeConn) UpdateMetadata(key, value string, ids ...string) error {
 	return fc.err()
 }
 
+func (fc *fakeConn) RemoveMetadata(key string, ids ...string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RemoveMetadata",
+		Key:      key,
+		IDs:      ids,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) IngressRules(fwname string) ([]network.IngressRule, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName:     "Ports",
@@ -578,10 +588,11 @@ func (fc *fakeConn) IngressRules(fwname string) ([]network.IngressRule, error) {
 	return fc.Rules, fc.err()
 }
 
-func (fc *fakeConn) OpenPorts(fwname string, rules ...network.IngressRule) error {
+func (fc *fakeConn) OpenPorts(fwname string, networks []string, rules ...network.IngressRule) error {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName:     "OpenPorts",
 		FirewallName: fwname,
+		Networks:     networks,
 		Rules:        rules,
 	})
 	return fc.err()
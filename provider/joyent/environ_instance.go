@@ -49,6 +49,8 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.InstanceLifecycle,
+	constraints.SpotMaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/cloudconfig/providerinit"
 	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/environs/tags"
@@ -113,7 +114,15 @@ func (env *environ) newContainer(
 	}
 	defer cleanupCallback()
 
-	image, err := env.server.FindImage(args.InstanceConfig.Series, arch, imageSources, true, statusCallback)
+	var image lxd.SourcedImage
+	cons := args.Constraints
+	virtualMachine := cons.HasVirtType() && *cons.VirtType == lxd.VirtTypeVirtualMachine
+	if virtualMachine {
+		// The LXD server pulls virtual machine images itself.
+		image, err = lxd.VirtualMachineImage(args.InstanceConfig.Series, arch, imageSources)
+	} else {
+		image, err = env.server.FindImage(args.InstanceConfig.Series, arch, imageSources, true, statusCallback)
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -128,6 +137,9 @@ func (env *environ) newContainer(
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if virtualMachine && !target.SupportsVirtualMachines() {
+		return nil, errors.NotSupportedf("virtual machines on LXD server %q", target.Name())
+	}
 
 	statusCallback(status.Allocating, "Creating container", nil)
 	container, err := target.CreateContainerFromSpec(cSpec)
@@ -177,9 +189,25 @@ func (env *environ) getContainerSpec(
 	if err != nil {
 		return lxd.ContainerSpec{}, errors.Trace(err)
 	}
+
+	// User-defined profiles from the model config are applied after the
+	// default and model profiles, so that they may override them, and
+	// are followed by those defined by the charms of the units to be
+	// deployed to the instance.
+	profiles := []string{"default", env.profileName()}
+	for _, extra := range []lxdprofile.Profiles{env.ecfg.Config.LXDProfiles(), args.InstanceConfig.CharmLXDProfiles} {
+		if len(extra) == 0 {
+			continue
+		}
+		names, err := env.server.EnsureProfiles(env.profileName()+"-", extra)
+		if err != nil {
+			return lxd.ContainerSpec{}, errors.Trace(err)
+		}
+		profiles = append(profiles, names...)
+	}
 	cSpec := lxd.ContainerSpec{
 		Name:     hostname,
-		Profiles: []string{"default", env.profileName()},
+		Profiles: profiles,
 		Image:    image,
 		Config:   make(map[string]string),
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/constraints"
 	containerlxd "github.com/juju/juju/container/lxd"
 	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/provider/lxd"
 )
@@ -311,6 +312,132 @@ func (s *environBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *environBrokerSuite) TestStartInstanceVirtualMachine(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	// Check that a virtual machine is requested, with an image that
+	// the LXD server pulls from the image source.
+	check := func(spec containerlxd.ContainerSpec) bool {
+		if spec.VirtType != containerlxd.VirtTypeVirtualMachine || spec.Image.Source == nil {
+			return false
+		}
+		return spec.Image.Source.Alias == "bionic/amd64" && spec.Image.Source.Mode == "pull"
+	}
+
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.GetNICsFromProfile("default").Return(map[string]map[string]string{"eth0": {}}, nil),
+		exp.SupportsVirtualMachines().Return(true),
+		exp.CreateContainerFromSpec(matchesContainerSpec(check)).Return(&containerlxd.Container{}, nil),
+		exp.HostArch().Return(arch.AMD64),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("virt-type=virtual-machine")
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environBrokerSuite) TestStartInstanceVirtualMachineNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.GetNICsFromProfile("default").Return(map[string]map[string]string{"eth0": {}}, nil),
+		exp.SupportsVirtualMachines().Return(false),
+		exp.Name().Return("node01"),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("virt-type=virtual-machine")
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `virtual machines on LXD server "node01" not supported`)
+}
+
+func (s *environBrokerSuite) TestStartInstanceWithLXDProfiles(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	env := s.NewEnviron(c, svr, map[string]interface{}{
+		"lxd-profiles": "iscsi:\n  config:\n    linux.kernel_modules: iscsi_tcp\n",
+	})
+	modelProfile := "juju-" + env.Config().Name()
+
+	// Check that the user-defined profile follows the model profile.
+	check := func(spec containerlxd.ContainerSpec) bool {
+		return reflect.DeepEqual(spec.Profiles, []string{"default", modelProfile, modelProfile + "-iscsi"})
+	}
+
+	profiles := lxdprofile.Profiles{
+		"iscsi": {Config: map[string]string{"linux.kernel_modules": "iscsi_tcp"}},
+	}
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
+		exp.EnsureProfiles(modelProfile+"-", profiles).Return([]string{modelProfile + "-iscsi"}, nil),
+		exp.GetNICsFromProfile("default").Return(map[string]map[string]string{"eth0": {}}, nil),
+		exp.CreateContainerFromSpec(matchesContainerSpec(check)).Return(&containerlxd.Container{}, nil),
+		exp.HostArch().Return(arch.AMD64),
+	)
+
+	_, err := env.StartInstance(s.callCtx, s.GetStartInstanceArgs(c, "bionic"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environBrokerSuite) TestStartInstanceWithCharmLXDProfiles(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	env := s.NewEnviron(c, svr, map[string]interface{}{
+		"lxd-profiles": "iscsi:\n  config:\n    linux.kernel_modules: iscsi_tcp\n",
+	})
+	modelProfile := "juju-" + env.Config().Name()
+
+	// Check that the charm's profile follows the user-defined profile.
+	check := func(spec containerlxd.ContainerSpec) bool {
+		return reflect.DeepEqual(spec.Profiles, []string{
+			"default", modelProfile, modelProfile + "-iscsi", modelProfile + "-app-kvm",
+		})
+	}
+
+	userProfiles := lxdprofile.Profiles{
+		"iscsi": {Config: map[string]string{"linux.kernel_modules": "iscsi_tcp"}},
+	}
+	charmProfiles := lxdprofile.Profiles{
+		"app-kvm": {Devices: map[string]map[string]string{
+			"kvm": {"type": "unix-char", "path": "/dev/kvm"},
+		}},
+	}
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
+		exp.EnsureProfiles(modelProfile+"-", userProfiles).Return([]string{modelProfile + "-iscsi"}, nil),
+		exp.EnsureProfiles(modelProfile+"-", charmProfiles).Return([]string{modelProfile + "-app-kvm"}, nil),
+		exp.GetNICsFromProfile("default").Return(map[string]map[string]string{"eth0": {}}, nil),
+		exp.CreateContainerFromSpec(matchesContainerSpec(check)).Return(&containerlxd.Container{}, nil),
+		exp.HostArch().Return(arch.AMD64),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.InstanceConfig.CharmLXDProfiles = charmProfiles
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *environBrokerSuite) TestStartInstanceNoTools(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
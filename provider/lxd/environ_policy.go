@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 )
@@ -21,8 +22,9 @@ func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args envir
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
-	constraints.VirtType,
 	constraints.Container,
+	constraints.InstanceLifecycle,
+	constraints.SpotMaxPrice,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -32,6 +34,7 @@ func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (const
 
 	validator.RegisterUnsupported(unsupportedConstraints)
 	validator.RegisterVocabulary(constraints.Arch, []string{env.server.HostArch()})
+	validator.RegisterVocabulary(constraints.VirtType, []string{lxd.VirtTypeContainer, lxd.VirtTypeVirtualMachine})
 
 	return validator, nil
 }
@@ -7,6 +7,7 @@ package lxd
 import (
 	gomock "github.com/golang/mock/gomock"
 	lxd "github.com/juju/juju/container/lxd"
+	lxdprofile "github.com/juju/juju/core/lxdprofile"
 	environs "github.com/juju/juju/environs"
 	network "github.com/juju/juju/network"
 	client "github.com/lxc/lxd/client"
@@ -184,6 +185,19 @@ func (mr *MockServerMockRecorder) EnsureDefaultStorage(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureDefaultStorage", reflect.TypeOf((*MockServer)(nil).EnsureDefaultStorage), arg0, arg1)
 }
 
+// EnsureProfiles mocks base method
+func (m *MockServer) EnsureProfiles(arg0 string, arg1 lxdprofile.Profiles) ([]string, error) {
+	ret := m.ctrl.Call(m, "EnsureProfiles", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureProfiles indicates an expected call of EnsureProfiles
+func (mr *MockServerMockRecorder) EnsureProfiles(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureProfiles", reflect.TypeOf((*MockServer)(nil).EnsureProfiles), arg0, arg1)
+}
+
 // FilterContainers mocks base method
 func (m *MockServer) FilterContainers(arg0 string, arg1 ...string) ([]lxd.Container, error) {
 	varargs := []interface{}{arg0}
@@ -459,6 +473,18 @@ func (mr *MockServerMockRecorder) StorageSupported() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageSupported", reflect.TypeOf((*MockServer)(nil).StorageSupported))
 }
 
+// SupportsVirtualMachines mocks base method
+func (m *MockServer) SupportsVirtualMachines() bool {
+	ret := m.ctrl.Call(m, "SupportsVirtualMachines")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SupportsVirtualMachines indicates an expected call of SupportsVirtualMachines
+func (mr *MockServerMockRecorder) SupportsVirtualMachines() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportsVirtualMachines", reflect.TypeOf((*MockServer)(nil).SupportsVirtualMachines))
+}
+
 // UpdateContainerConfig mocks base method
 func (m *MockServer) UpdateContainerConfig(arg0 string, arg1 map[string]string) error {
 	ret := m.ctrl.Call(m, "UpdateContainerConfig", arg0, arg1)
@@ -25,6 +25,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container/lxd"
 	containerlxd "github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
@@ -530,6 +531,15 @@ func (conn *StubClient) HasProfile(name string) (bool, error) {
 	return false, conn.NextErr()
 }
 
+func (conn *StubClient) EnsureProfiles(prefix string, profiles lxdprofile.Profiles) ([]string, error) {
+	conn.AddCall("EnsureProfiles", prefix, profiles)
+	var names []string
+	for _, name := range profiles.Names() {
+		names = append(names, prefix+name)
+	}
+	return names, conn.NextErr()
+}
+
 func (conn *StubClient) VerifyNetworkDevice(profile *api.Profile, ETag string) error {
 	conn.AddCall("VerifyNetworkDevice", profile, ETag)
 	return conn.NextErr()
@@ -675,6 +685,11 @@ func (conn *StubClient) IsClustered() bool {
 	return true
 }
 
+func (conn *StubClient) SupportsVirtualMachines() bool {
+	conn.AddCall("SupportsVirtualMachines")
+	return false
+}
+
 func (conn *StubClient) Name() string {
 	conn.AddCall("Name")
 	return "server"
@@ -4,13 +4,65 @@
 package maas
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
 
-var configSchema = environschema.Fields{}
+const (
+	// ReleaseEraseKey is the model config key for the way MAAS
+	// should erase the disks of nodes when Juju releases them.
+	ReleaseEraseKey = "maas-release-erase"
+
+	// StorageLayoutKey is the model config key for the storage
+	// layout MAAS should apply to nodes acquired by Juju.
+	StorageLayoutKey = "maas-storage-layout"
+
+	// RootDiskTagsKey is the model config key for the MAAS block
+	// device tags that the root disk of acquired nodes must have.
+	RootDiskTagsKey = "maas-root-disk-tags"
+)
+
+const (
+	// eraseNone releases nodes without erasing their disks.
+	eraseNone = "none"
+
+	// eraseFull erases the disks of released nodes by
+	// overwriting them.
+	eraseFull = "erase"
+
+	// eraseSecure erases the disks of released nodes using
+	// the disks' secure erase feature, falling back to
+	// overwriting them if it is not available.
+	eraseSecure = "secure"
+
+	// eraseQuick erases the disks of released nodes by
+	// wiping only their beginning and end.
+	eraseQuick = "quick"
+)
+
+// storageLayouts holds the storage layouts that MAAS
+// may be asked to apply to acquired nodes.
+var storageLayouts = []interface{}{"flat", "lvm", "bcache"}
+
+var configSchema = environschema.Fields{
+	ReleaseEraseKey: {
+		Description: `How MAAS should erase the disks of nodes released by Juju: "none", "erase", "secure" or "quick".`,
+		Type:        environschema.Tstring,
+		Values:      []interface{}{eraseNone, eraseFull, eraseSecure, eraseQuick},
+	},
+	StorageLayoutKey: {
+		Description: `The storage layout MAAS should apply to nodes acquired by Juju: "flat", "lvm" or "bcache". If empty, the MAAS default layout is used.`,
+		Type:        environschema.Tstring,
+		Values:      append([]interface{}{""}, storageLayouts...),
+	},
+	RootDiskTagsKey: {
+		Description: "A comma-separated list of MAAS block device tags that the root disk of nodes acquired by Juju must have.",
+		Type:        environschema.Tstring,
+	},
+}
 
 var configFields = func() schema.Fields {
 	fs, _, err := configSchema.ValidationSchema()
@@ -20,13 +72,37 @@ var configFields = func() schema.Fields {
 	return fs
 }()
 
-var configDefaults = schema.Defaults{}
+var configDefaults = schema.Defaults{
+	ReleaseEraseKey:  eraseNone,
+	StorageLayoutKey: "",
+	RootDiskTagsKey:  "",
+}
 
 type maasModelConfig struct {
 	*config.Config
 	attrs map[string]interface{}
 }
 
+// releaseErase returns how MAAS should erase the disks
+// of released nodes.
+func (c *maasModelConfig) releaseErase() string {
+	return c.attrs[ReleaseEraseKey].(string)
+}
+
+// storageLayout returns the storage layout MAAS should
+// apply to acquired nodes, or "" for the MAAS default.
+func (c *maasModelConfig) storageLayout() string {
+	return c.attrs[StorageLayoutKey].(string)
+}
+
+// rootDiskTags returns the MAAS block device tags that the
+// root disk of acquired nodes must have.
+func (c *maasModelConfig) rootDiskTags() []string {
+	// The value is checked by Validate, so it cannot fail here.
+	tags, _ := parseTags(c.attrs[RootDiskTagsKey].(string))
+	return tags
+}
+
 func (prov MaasEnvironProvider) newConfig(cfg *config.Config) (*maasModelConfig, error) {
 	validCfg, err := prov.Validate(cfg, nil)
 	if err != nil {
@@ -73,5 +149,8 @@ func (prov MaasEnvironProvider) Validate(cfg, oldCfg *config.Config) (*config.Co
 		Config: cfg,
 		attrs:  validated,
 	}
+	if _, err := parseTags(envCfg.attrs[RootDiskTagsKey].(string)); err != nil {
+		return nil, errors.Annotatef(err, "invalid %s", RootDiskTagsKey)
+	}
 	return cfg.Apply(envCfg.attrs)
 }
@@ -70,3 +70,43 @@ func (*configSuite) TestSchema(c *gc.C) {
 		c.Check(fields[name], jc.DeepEquals, field)
 	}
 }
+
+func (*configSuite) TestDefaults(c *gc.C) {
+	cfg, err := newConfig(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.releaseErase(), gc.Equals, "none")
+	c.Check(cfg.storageLayout(), gc.Equals, "")
+	c.Check(cfg.rootDiskTags(), gc.HasLen, 0)
+}
+
+func (*configSuite) TestStorageOptions(c *gc.C) {
+	cfg, err := newConfig(map[string]interface{}{
+		"maas-release-erase":  "secure",
+		"maas-storage-layout": "bcache",
+		"maas-root-disk-tags": "ssd, nvme",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.releaseErase(), gc.Equals, "secure")
+	c.Check(cfg.storageLayout(), gc.Equals, "bcache")
+	c.Check(cfg.rootDiskTags(), jc.DeepEquals, []string{"ssd", "nvme"})
+}
+
+func (*configSuite) TestInvalidStorageOptions(c *gc.C) {
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"maas-release-erase": "shred"},
+		err:   `.*expected one of .*, got "shred"`,
+	}, {
+		attrs: map[string]interface{}{"maas-storage-layout": "zfs"},
+		err:   `.*expected one of .*, got "zfs"`,
+	}, {
+		attrs: map[string]interface{}{"maas-root-disk-tags": "fast disk"},
+		err:   `invalid maas-root-disk-tags: tags may not contain whitespace: "fast disk"`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		_, err := newConfig(test.attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -60,6 +60,7 @@ const statusPollInterval = 5 * time.Second
 
 var (
 	ReleaseNodes         = releaseNodes
+	SetStorageLayout     = setStorageLayout
 	DeploymentStatusCall = deploymentStatusCall
 	GetMAAS2Controller   = getMAAS2Controller
 )
@@ -76,6 +77,11 @@ func releaseNodes(nodes gomaasapi.MAASObject, ids url.Values) error {
 	return err
 }
 
+func setStorageLayout(node gomaasapi.MAASObject, layout string) error {
+	_, err := node.CallPost("set_storage_layout", url.Values{"storage_layout": {layout}})
+	return err
+}
+
 type maasEnviron struct {
 	name  string
 	cloud environs.CloudSpec
@@ -87,9 +93,10 @@ type maasEnviron struct {
 	// ecfgMutex protects the *Unlocked fields below.
 	ecfgMutex sync.Mutex
 
-	ecfgUnlocked       *maasModelConfig
-	maasClientUnlocked *gomaasapi.MAASObject
-	storageUnlocked    storage.Storage
+	ecfgUnlocked        *maasModelConfig
+	maasClientUnlocked  *gomaasapi.MAASObject
+	maas2ClientUnlocked *gomaasapi.MAASObject
+	storageUnlocked     storage.Storage
 
 	// maasController provides access to the MAAS 2.0 API.
 	maasController gomaasapi.Controller
@@ -303,6 +310,19 @@ func (env *maasEnviron) SetConfig(cfg *config.Config) error {
 		return errors.Trace(err)
 	default:
 		env.maasController = controller
+		// The controller does not expose every operation that
+		// Juju needs, such as erasing disks on release, so keep
+		// a client for the raw MAAS 2.0 API as well.
+		_, _, includesVersion := gomaasapi.SplitVersionedURL(maasServer)
+		versionURL := maasServer
+		if !includesVersion {
+			versionURL = gomaasapi.AddAPIVersionToURL(maasServer, apiVersion2)
+		}
+		authClient, err := gomaasapi.NewAuthenticatedClient(versionURL, maasOAuth)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		env.maas2ClientUnlocked = gomaasapi.NewMAAS(*authClient)
 	}
 	env.apiVersion = apiVersion
 	return nil
@@ -596,30 +616,58 @@ func (e *maasEnviron) DeriveAvailabilityZones(ctx context.ProviderCallContext, a
 }
 
 type maasPlacement struct {
-	nodeName string
-	zoneName string
-	systemId string
+	nodeName      string
+	zoneName      string
+	systemId      string
+	storageLayout string
 }
 
+// parsePlacement parses a placement directive, which is either a node
+// name or a comma-separated list of key=value directives, such as
+// "zone=z1,storage-layout=lvm".
 func (e *maasEnviron) parsePlacement(ctx context.ProviderCallContext, placement string) (*maasPlacement, error) {
 	pos := strings.IndexRune(placement, '=')
 	if pos == -1 {
 		// If there's no '=' delimiter, assume it's a node name.
 		return &maasPlacement{nodeName: placement}, nil
 	}
-	switch key, value := placement[:pos], placement[pos+1:]; key {
-	case "zone":
-		availabilityZone := value
-		err := common.ValidateAvailabilityZone(e, ctx, availabilityZone)
-		if err != nil {
-			return nil, err
+	var result maasPlacement
+	for _, directive := range strings.Split(placement, ",") {
+		pos := strings.IndexRune(directive, '=')
+		if pos == -1 {
+			return nil, errors.Errorf("unknown placement directive: %v", directive)
+		}
+		switch key, value := directive[:pos], directive[pos+1:]; key {
+		case "zone":
+			availabilityZone := value
+			err := common.ValidateAvailabilityZone(e, ctx, availabilityZone)
+			if err != nil {
+				return nil, err
+			}
+			result.zoneName = availabilityZone
+		case "system-id":
+			result.systemId = value
+		case "storage-layout":
+			if err := validateStorageLayout(value); err != nil {
+				return nil, errors.Trace(err)
+			}
+			result.storageLayout = value
+		default:
+			return nil, errors.Errorf("unknown placement directive: %v", directive)
 		}
-		return &maasPlacement{zoneName: availabilityZone}, nil
-	case "system-id":
-		return &maasPlacement{systemId: value}, nil
 	}
+	return &result, nil
+}
 
-	return nil, errors.Errorf("unknown placement directive: %v", placement)
+// validateStorageLayout returns an error if MAAS cannot
+// be asked to apply the given storage layout.
+func validateStorageLayout(layout string) error {
+	for _, valid := range storageLayouts {
+		if layout == valid {
+			return nil
+		}
+	}
+	return errors.NotValidf("storage layout %q", layout)
 }
 
 func (env *maasEnviron) PrecheckInstance(ctx context.ProviderCallContext, args environs.PrecheckInstanceParams) error {
@@ -697,6 +745,15 @@ func (env *maasEnviron) getMAASClient() *gomaasapi.MAASObject {
 	return env.maasClientUnlocked
 }
 
+// getMAAS2Client returns a client for the raw MAAS 2.0 API, for the
+// operations not supported by the MAAS 2 controller.
+func (env *maasEnviron) getMAAS2Client() *gomaasapi.MAASObject {
+	env.ecfgMutex.Lock()
+	defer env.ecfgMutex.Unlock()
+
+	return env.maas2ClientUnlocked
+}
+
 var dashSuffix = regexp.MustCompile("^(.*)-\\d+$")
 
 func spaceNamesToSpaceInfo(spaces []string, spaceMap map[string]network.SpaceInfo) ([]network.SpaceInfo, error) {
@@ -883,6 +940,22 @@ func (environ *maasEnviron) startNode(node gomaasapi.MAASObject, series string,
 	return nil, err
 }
 
+// setStorageLayout asks MAAS to apply the given storage layout to an
+// acquired node, replacing the default layout, before it is deployed.
+func (environ *maasEnviron) setStorageLayout(inst maasInstance, layout string) error {
+	var node gomaasapi.MAASObject
+	if environ.usingMAAS2() {
+		machines := environ.getMAAS2Client().GetSubObject("machines")
+		node = machines.GetSubObject(string(inst.Id()))
+	} else {
+		node = *inst.(*maas1Instance).maasObject
+	}
+	if err := SetStorageLayout(node, layout); err != nil {
+		return errors.Annotatef(err, "cannot set storage layout %q", layout)
+	}
+	return nil
+}
+
 func (environ *maasEnviron) startNode2(node maas2Instance, series string, userdata []byte) (*maas2Instance, error) {
 	err := node.machine.Start(gomaasapi.StartArgs{DistroSeries: series, UserData: string(userdata)})
 	if err != nil {
@@ -913,11 +986,16 @@ func (environ *maasEnviron) StartInstance(
 
 	availabilityZone := args.AvailabilityZone
 	var nodeName, systemId string
+	ecfg := environ.ecfg()
+	storageLayout := ecfg.storageLayout()
 	if args.Placement != "" {
 		placement, err := environ.parsePlacement(ctx, args.Placement)
 		if err != nil {
 			return nil, common.ZoneIndependentError(err)
 		}
+		if placement.storageLayout != "" {
+			storageLayout = placement.storageLayout
+		}
 		// NOTE(axw) we wipe out args.AvailabilityZone if the
 		// user specified a specific node or system ID via
 		// placement, as placement must always take precedence.
@@ -938,7 +1016,7 @@ func (environ *maasEnviron) StartInstance(
 	}
 
 	// Storage.
-	volumes, err := buildMAASVolumeParameters(args.Volumes, args.Constraints)
+	volumes, err := buildMAASVolumeParameters(args.Volumes, args.Constraints, ecfg.rootDiskTags())
 	if err != nil {
 		return nil, common.ZoneIndependentError(errors.Annotate(err, "invalid volume parameters"))
 	}
@@ -984,6 +1062,12 @@ func (environ *maasEnviron) StartInstance(
 		}
 	}()
 
+	if storageLayout != "" {
+		if err := environ.setStorageLayout(inst, storageLayout); err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
+	}
+
 	hc, err := inst.hardwareCharacteristics()
 	if err != nil {
 		return nil, common.ZoneIndependentError(err)
@@ -1478,6 +1562,50 @@ func (environ *maasEnviron) releaseNodesIndividually(ids []instance.Id) error {
 	return errors.Trace(lastErr)
 }
 
+// releaseEraseParams returns the parameters asking MAAS to
+// erase the disks of a released node in the given way.
+func releaseEraseParams(erase string) url.Values {
+	params := url.Values{
+		"comment": {"Released by Juju MAAS provider"},
+		"erase":   {"true"},
+	}
+	switch erase {
+	case eraseSecure:
+		params.Set("secure_erase", "true")
+	case eraseQuick:
+		params.Set("quick_erase", "true")
+	}
+	return params
+}
+
+// releaseNodesErasing releases the given nodes one at a time, asking
+// MAAS to erase their disks in the given way. MAAS only accepts erase
+// options when releasing nodes individually.
+func (environ *maasEnviron) releaseNodesErasing(nodes gomaasapi.MAASObject, ids []instance.Id, erase string) error {
+	params := releaseEraseParams(erase)
+	var lastErr error
+	for _, id := range ids {
+		node := nodes.GetSubObject(extractSystemId(id))
+		_, err := node.CallPost("release", params)
+		if err == nil {
+			continue
+		}
+		// As when releasing nodes in bulk, a status code of 409
+		// means the node is already released or erasing, and
+		// 403 or 404 that it is not ours or no longer exists.
+		if maasErr, ok := gomaasapi.GetServerError(err); ok {
+			switch maasErr.StatusCode {
+			case 403, 404, 409:
+				logger.Infof("ignoring error while releasing node %v (%v)", id, err)
+				continue
+			}
+		}
+		lastErr = errors.Annotatef(err, "cannot release node %v", id)
+		logger.Errorf("error while releasing node %v (%v)", id, err)
+	}
+	return lastErr
+}
+
 func instanceIdsToSystemIDs(ids []instance.Id) []string {
 	systemIDs := make([]string, len(ids))
 	for index, id := range ids {
@@ -1493,17 +1621,23 @@ func (environ *maasEnviron) StopInstances(ctx context.ProviderCallContext, ids .
 		return nil
 	}
 
-	if environ.usingMAAS2() {
-		err := environ.releaseNodes2(ids, true)
-		if err != nil {
-			return errors.Trace(err)
-		}
-	} else {
+	var err error
+	erase := environ.ecfg().releaseErase()
+	switch {
+	case erase != eraseNone && environ.usingMAAS2():
+		nodes := environ.getMAAS2Client().GetSubObject("machines")
+		err = environ.releaseNodesErasing(nodes, ids, erase)
+	case erase != eraseNone:
 		nodes := environ.getMAASClient().GetSubObject("nodes")
-		err := environ.releaseNodes1(nodes, getSystemIdValues("nodes", ids), true)
-		if err != nil {
-			return errors.Trace(err)
-		}
+		err = environ.releaseNodesErasing(nodes, ids, erase)
+	case environ.usingMAAS2():
+		err = environ.releaseNodes2(ids, true)
+	default:
+		nodes := environ.getMAASClient().GetSubObject("nodes")
+		err = environ.releaseNodes1(nodes, getSystemIdValues("nodes", ids), true)
+	}
+	if err != nil {
+		return errors.Trace(err)
 	}
 	return common.RemoveStateInstances(environ.Storage(), ids...)
 
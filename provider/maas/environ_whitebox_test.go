@@ -300,6 +300,62 @@ func (suite *environSuite) TestStopInstancesReturnsUnexpectedError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.Equals, environs.ErrNoInstances)
 }
 
+func (suite *environSuite) TestStopInstancesErasesDisks(c *gc.C) {
+	suite.getInstance("test1")
+	suite.getInstance("test2")
+	suite.testMAASObject.TestServer.OwnedNodes()["test1"] = true
+	suite.testMAASObject.TestServer.OwnedNodes()["test2"] = true
+	env := suite.makeEnviron()
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-release-erase": "secure"})
+
+	err := env.StopInstances(suite.callCtx, "test1", "test2")
+	c.Assert(err, jc.ErrorIsNil)
+	// Erase options are only accepted when releasing nodes individually.
+	c.Check(suite.testMAASObject.TestServer.NodesOperations(), gc.HasLen, 0)
+	c.Check(suite.testMAASObject.TestServer.NodeOperations(), gc.DeepEquals, map[string][]string{
+		"test1": {"release"},
+		"test2": {"release"},
+	})
+	values := suite.testMAASObject.TestServer.NodeOperationRequestValues()["test1"][0]
+	c.Check(values.Get("erase"), gc.Equals, "true")
+	c.Check(values.Get("secure_erase"), gc.Equals, "true")
+	c.Check(values.Get("quick_erase"), gc.Equals, "")
+}
+
+func (suite *environSuite) TestStopInstancesQuickErase(c *gc.C) {
+	suite.getInstance("test1")
+	suite.testMAASObject.TestServer.OwnedNodes()["test1"] = true
+	env := suite.makeEnviron()
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-release-erase": "quick"})
+
+	err := env.StopInstances(suite.callCtx, "test1")
+	c.Assert(err, jc.ErrorIsNil)
+	values := suite.testMAASObject.TestServer.NodeOperationRequestValues()["test1"][0]
+	c.Check(values.Get("erase"), gc.Equals, "true")
+	c.Check(values.Get("secure_erase"), gc.Equals, "")
+	c.Check(values.Get("quick_erase"), gc.Equals, "true")
+}
+
+func (suite *environSuite) TestStopInstancesErasingIgnoresMissingNode(c *gc.C) {
+	suite.getInstance("test1")
+	suite.testMAASObject.TestServer.OwnedNodes()["test1"] = true
+	env := suite.makeEnviron()
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-release-erase": "erase"})
+
+	err := env.StopInstances(suite.callCtx, "unknown", "test1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(suite.testMAASObject.TestServer.NodeOperations(), gc.DeepEquals, map[string][]string{
+		"test1": {"release"},
+	})
+}
+
+func (suite *environSuite) setModelConfig(c *gc.C, env environs.Environ, attrs coretesting.Attrs) {
+	cfg, err := env.Config().Apply(attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (suite *environSuite) TestControllerInstances(c *gc.C) {
 	env := suite.makeEnviron()
 	_, err := env.ControllerInstances(suite.callCtx, suite.controllerUUID)
@@ -706,6 +762,25 @@ func (s *environSuite) TestPrecheckNodePlacement(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *environSuite) TestPrecheckStorageLayoutPlacement(c *gc.C) {
+	s.testMAASObject.TestServer.AddZone("zone1", "the grass is greener in zone1")
+	env := s.makeEnviron()
+	err := env.PrecheckInstance(s.callCtx, environs.PrecheckInstanceParams{Series: jujuversion.SupportedLTS(), Placement: "zone=zone1,storage-layout=lvm"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environSuite) TestPrecheckInvalidStorageLayoutPlacement(c *gc.C) {
+	env := s.makeEnviron()
+	err := env.PrecheckInstance(s.callCtx, environs.PrecheckInstanceParams{Series: jujuversion.SupportedLTS(), Placement: "storage-layout=zfs"})
+	c.Assert(err, gc.ErrorMatches, `storage layout "zfs" not valid`)
+}
+
+func (s *environSuite) TestPrecheckInvalidCombinedPlacement(c *gc.C) {
+	env := s.makeEnviron()
+	err := env.PrecheckInstance(s.callCtx, environs.PrecheckInstanceParams{Series: jujuversion.SupportedLTS(), Placement: "storage-layout=lvm,notzone=anything"})
+	c.Assert(err, gc.ErrorMatches, "unknown placement directive: notzone=anything")
+}
+
 func (s *environSuite) TestDeriveAvailabilityZones(c *gc.C) {
 	s.testMAASObject.TestServer.AddZone("zone1", "the grass is greener in zone1")
 	env := s.makeEnviron()
@@ -906,6 +981,62 @@ func (s *environSuite) TestStartInstanceUnsupportedStorage(c *gc.C) {
 	c.Assert(s.testMAASObject.TestServer.OwnedNodes()["thenode1"], jc.IsFalse)
 }
 
+func (s *environSuite) TestStartInstanceStorageLayout(c *gc.C) {
+	var layouts []string
+	s.PatchValue(&SetStorageLayout, func(node gomaasapi.MAASObject, layout string) error {
+		layouts = append(layouts, layout)
+		return nil
+	})
+	env := s.bootstrap(c)
+	// By default the MAAS storage layout is used.
+	c.Assert(layouts, gc.HasLen, 0)
+
+	s.setModelConfig(c, env, coretesting.Attrs{"maas-storage-layout": "lvm"})
+	s.newNode(c, "thenode1", "host1", nil)
+	s.addSubnet(c, 1, 1, "thenode1")
+	s.newNode(c, "thenode2", "host2", nil)
+	s.addSubnet(c, 2, 2, "thenode2")
+	params := environs.StartInstanceParams{ControllerUUID: s.controllerUUID}
+	_, err := testing.StartInstanceWithParams(env, s.callCtx, "1", params)
+	c.Assert(err, jc.ErrorIsNil)
+	// Placement takes precedence over model config.
+	params.Placement = "storage-layout=bcache"
+	_, err = testing.StartInstanceWithParams(env, s.callCtx, "2", params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(layouts, jc.DeepEquals, []string{"lvm", "bcache"})
+}
+
+func (s *environSuite) TestStartInstanceStorageLayoutError(c *gc.C) {
+	s.PatchValue(&SetStorageLayout, func(node gomaasapi.MAASObject, layout string) error {
+		return errors.New("boom")
+	})
+	env := s.bootstrap(c)
+	s.newNode(c, "thenode1", "host1", nil)
+	s.addSubnet(c, 1, 1, "thenode1")
+	params := environs.StartInstanceParams{
+		ControllerUUID: s.controllerUUID,
+		Placement:      "storage-layout=flat",
+	}
+	_, err := testing.StartInstanceWithParams(env, s.callCtx, "1", params)
+	c.Assert(err, gc.ErrorMatches, `cannot set storage layout "flat": boom`)
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+	operations := s.testMAASObject.TestServer.NodesOperations()
+	c.Check(operations, gc.DeepEquals, []string{"acquire", "acquire", "release"})
+	c.Assert(s.testMAASObject.TestServer.OwnedNodes()["thenode1"], jc.IsFalse)
+}
+
+func (s *environSuite) TestStartInstanceRootDiskTags(c *gc.C) {
+	env := s.bootstrap(c)
+	s.setModelConfig(c, env, coretesting.Attrs{"maas-root-disk-tags": "ssd, nvme"})
+	s.newNode(c, "thenode1", "host1", nil)
+	s.addSubnet(c, 1, 1, "thenode1")
+	params := environs.StartInstanceParams{ControllerUUID: s.controllerUUID}
+	_, err := testing.StartInstanceWithParams(env, s.callCtx, "1", params)
+	c.Assert(err, jc.ErrorIsNil)
+	values := s.testMAASObject.TestServer.NodeOperationRequestValues()["thenode1"][0]
+	c.Assert(values.Get("storage"), gc.Equals, "root:0(ssd,nvme)")
+}
+
 func (s *environSuite) TestGetAvailabilityZones(c *gc.C) {
 	env := s.makeEnviron()
 
@@ -293,6 +293,91 @@ func (suite *maas2EnvironSuite) TestStopInstancesReturnsUnexpectedError(c *gc.C)
 	suite.checkStopInstancesFails(c, errors.New("Something completely unexpected!"))
 }
 
+// newTestServer returns a gomaasapi test server to serve the raw
+// MAAS 2.0 API operations that the controller does not support.
+func (suite *maas2EnvironSuite) newTestServer() *gomaasapi.SimpleTestServer {
+	server := gomaasapi.NewSimpleServer()
+	server.Start()
+	suite.AddCleanup(func(*gc.C) { server.Close() })
+	return server
+}
+
+func (suite *maas2EnvironSuite) setModelConfig(c *gc.C, env environs.Environ, attrs coretesting.Attrs) {
+	cfg, err := env.Config().Apply(attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (suite *maas2EnvironSuite) TestStopInstancesErasesDisks(c *gc.C) {
+	server := suite.newTestServer()
+	server.AddPostResponse("/api/2.0/machines/test1/?op=release", http.StatusOK, "{}")
+	server.AddPostResponse("/api/2.0/machines/test2/?op=release", http.StatusOK, "{}")
+	controller := newFakeControllerWithFiles(&fakeFile{name: coretesting.ModelTag.Id() + "-provider-state"})
+	env := suite.makeEnvironWithEndpoint(c, controller, server.Server.URL)
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-release-erase": "secure"})
+
+	err := env.StopInstances(suite.callCtx, "test1", "test2")
+	c.Assert(err, jc.ErrorIsNil)
+	// Erase options are only accepted when releasing machines
+	// individually, so the controller's bulk release is not used.
+	c.Check(collectReleaseArgs(controller), gc.HasLen, 0)
+	requests := server.LastNRequests(2)
+	for i, id := range []string{"test1", "test2"} {
+		c.Check(requests[i].URL.String(), gc.Equals, "/api/2.0/machines/"+id+"/?op=release")
+		c.Check(requests[i].PostForm.Get("erase"), gc.Equals, "true")
+		c.Check(requests[i].PostForm.Get("secure_erase"), gc.Equals, "true")
+		c.Check(requests[i].PostForm.Get("quick_erase"), gc.Equals, "")
+	}
+}
+
+func (suite *maas2EnvironSuite) TestStopInstancesQuickErase(c *gc.C) {
+	server := suite.newTestServer()
+	server.AddPostResponse("/api/2.0/machines/test1/?op=release", http.StatusOK, "{}")
+	controller := newFakeControllerWithFiles(&fakeFile{name: coretesting.ModelTag.Id() + "-provider-state"})
+	env := suite.makeEnvironWithEndpoint(c, controller, server.Server.URL)
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-release-erase": "quick"})
+
+	err := env.StopInstances(suite.callCtx, "test1")
+	c.Assert(err, jc.ErrorIsNil)
+	request := server.LastRequest()
+	c.Check(request.URL.String(), gc.Equals, "/api/2.0/machines/test1/?op=release")
+	c.Check(request.PostForm.Get("erase"), gc.Equals, "true")
+	c.Check(request.PostForm.Get("secure_erase"), gc.Equals, "")
+	c.Check(request.PostForm.Get("quick_erase"), gc.Equals, "true")
+}
+
+func (suite *maas2EnvironSuite) TestStopInstancesErasingIgnoresMissingMachine(c *gc.C) {
+	server := suite.newTestServer()
+	server.AddPostResponse("/api/2.0/machines/test1/?op=release", http.StatusNotFound, "")
+	server.AddPostResponse("/api/2.0/machines/test2/?op=release", http.StatusConflict, "")
+	server.AddPostResponse("/api/2.0/machines/test3/?op=release", http.StatusOK, "{}")
+	controller := newFakeControllerWithFiles(&fakeFile{name: coretesting.ModelTag.Id() + "-provider-state"})
+	env := suite.makeEnvironWithEndpoint(c, controller, server.Server.URL)
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-release-erase": "erase"})
+
+	err := env.StopInstances(suite.callCtx, "test1", "test2", "test3")
+	c.Assert(err, jc.ErrorIsNil)
+	requests := server.LastNRequests(3)
+	for i, id := range []string{"test1", "test2", "test3"} {
+		c.Check(requests[i].URL.String(), gc.Equals, "/api/2.0/machines/"+id+"/?op=release")
+	}
+}
+
+func (suite *maas2EnvironSuite) TestStopInstancesErasingReturnsUnexpectedError(c *gc.C) {
+	server := suite.newTestServer()
+	server.AddPostResponse("/api/2.0/machines/test1/?op=release", http.StatusInternalServerError, "boom")
+	server.AddPostResponse("/api/2.0/machines/test2/?op=release", http.StatusOK, "{}")
+	controller := newFakeControllerWithFiles(&fakeFile{name: coretesting.ModelTag.Id() + "-provider-state"})
+	env := suite.makeEnvironWithEndpoint(c, controller, server.Server.URL)
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-release-erase": "erase"})
+
+	err := env.StopInstances(suite.callCtx, "test1", "test2")
+	c.Assert(err, gc.ErrorMatches, "cannot release node test1: .*")
+	// The remaining machines are still released.
+	c.Check(server.LastRequest().URL.String(), gc.Equals, "/api/2.0/machines/test2/?op=release")
+}
+
 func (suite *maas2EnvironSuite) TestStartInstanceError(c *gc.C) {
 	suite.injectController(&fakeController{
 		allocateMachineError: errors.New("Charles Babbage"),
@@ -311,6 +396,72 @@ func (suite *maas2EnvironSuite) TestStartInstance(c *gc.C) {
 	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("Bruce Sterling"))
 }
 
+func (suite *maas2EnvironSuite) injectControllerWithMachine(c *gc.C, systemID string) *fakeController {
+	controller := newFakeController()
+	controller.allocateMachine = newFakeMachine(systemID, arch.HostArch(), "")
+	controller.allocateMachineMatches = gomaasapi.ConstraintMatches{
+		Storage: map[string][]gomaasapi.BlockDevice{},
+	}
+	suite.injectController(controller)
+	suite.setupFakeTools(c)
+	return controller
+}
+
+func (suite *maas2EnvironSuite) TestStartInstanceStorageLayout(c *gc.C) {
+	server := suite.newTestServer()
+	server.AddPostResponse("/api/2.0/machines/abc123/?op=set_storage_layout", http.StatusOK, "{}")
+	server.AddPostResponse("/api/2.0/machines/abc123/?op=set_storage_layout", http.StatusOK, "{}")
+	controller := suite.injectControllerWithMachine(c, "abc123")
+	env := suite.makeEnvironWithEndpoint(c, controller, server.Server.URL)
+	suite.setModelConfig(c, env, coretesting.Attrs{"maas-storage-layout": "lvm"})
+
+	params := environs.StartInstanceParams{ControllerUUID: suite.controllerUUID}
+	_, err := jujutesting.StartInstanceWithParams(env, suite.callCtx, "1", params)
+	c.Assert(err, jc.ErrorIsNil)
+	request := server.LastRequest()
+	c.Check(request.URL.String(), gc.Equals, "/api/2.0/machines/abc123/?op=set_storage_layout")
+	c.Check(request.PostForm.Get("storage_layout"), gc.Equals, "lvm")
+
+	// Placement takes precedence over model config.
+	params.Placement = "storage-layout=bcache"
+	_, err = jujutesting.StartInstanceWithParams(env, suite.callCtx, "2", params)
+	c.Assert(err, jc.ErrorIsNil)
+	request = server.LastRequest()
+	c.Check(request.URL.String(), gc.Equals, "/api/2.0/machines/abc123/?op=set_storage_layout")
+	c.Check(request.PostForm.Get("storage_layout"), gc.Equals, "bcache")
+}
+
+func (suite *maas2EnvironSuite) TestStartInstanceDefaultStorageLayout(c *gc.C) {
+	server := suite.newTestServer()
+	controller := suite.injectControllerWithMachine(c, "abc123")
+	env := suite.makeEnvironWithEndpoint(c, controller, server.Server.URL)
+
+	params := environs.StartInstanceParams{ControllerUUID: suite.controllerUUID}
+	_, err := jujutesting.StartInstanceWithParams(env, suite.callCtx, "1", params)
+	c.Assert(err, jc.ErrorIsNil)
+	// MAAS's default storage layout is left alone.
+	c.Check(server.RequestCount(), gc.Equals, 0)
+}
+
+func (suite *maas2EnvironSuite) TestStartInstanceStorageLayoutError(c *gc.C) {
+	server := suite.newTestServer()
+	server.AddPostResponse("/api/2.0/machines/abc123/?op=set_storage_layout", http.StatusConflict, "boom")
+	controller := suite.injectControllerWithMachine(c, "abc123")
+	env := suite.makeEnvironWithEndpoint(c, controller, server.Server.URL)
+
+	params := environs.StartInstanceParams{
+		ControllerUUID: suite.controllerUUID,
+		Placement:      "storage-layout=flat",
+	}
+	_, err := jujutesting.StartInstanceWithParams(env, suite.callCtx, "1", params)
+	c.Assert(err, gc.ErrorMatches, `cannot set storage layout "flat": .*`)
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+	// The acquired machine is released again.
+	args := collectReleaseArgs(controller)
+	c.Assert(args, gc.HasLen, 1)
+	c.Assert(args[0].SystemIDs, gc.DeepEquals, []string{"abc123"})
+}
+
 func (suite *maas2EnvironSuite) TestStartInstanceAppliesResourceTags(c *gc.C) {
 	env, controller := suite.injectControllerWithSpacesAndCheck(c, nil, gomaasapi.AllocateMachineArgs{})
 	config := env.Config()
@@ -30,6 +30,13 @@ func (suite *maas2Suite) injectController(controller gomaasapi.Controller) {
 }
 
 func (suite *maas2Suite) makeEnviron(c *gc.C, controller gomaasapi.Controller) *maasEnviron {
+	return suite.makeEnvironWithEndpoint(c, controller, "http://any-old-junk.invalid/")
+}
+
+// makeEnvironWithEndpoint returns an environ that uses the given
+// controller, and the MAAS 2.0 API at the given endpoint for the
+// operations the controller does not support.
+func (suite *maas2Suite) makeEnvironWithEndpoint(c *gc.C, controller gomaasapi.Controller, endpoint string) *maasEnviron {
 	if controller != nil {
 		suite.injectController(controller)
 	}
@@ -45,7 +52,7 @@ func (suite *maas2Suite) makeEnviron(c *gc.C, controller gomaasapi.Controller) *
 	cloud := environs.CloudSpec{
 		Type:       "maas",
 		Name:       "maas",
-		Endpoint:   "http://any-old-junk.invalid/",
+		Endpoint:   endpoint,
 		Credential: &cred,
 	}
 
@@ -78,21 +78,29 @@ func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
 	case []string:
 		tags = v
 	case string:
-		fields := strings.Split(v, ",")
-		for _, f := range fields {
-			f = strings.TrimSpace(f)
-			if len(f) == 0 {
-				continue
-			}
-			if i := strings.IndexFunc(f, unicode.IsSpace); i >= 0 {
-				return nil, errors.Errorf("tags may not contain whitespace: %q", f)
-			}
-			tags = append(tags, f)
+		if tags, err = parseTags(v); err != nil {
+			return nil, errors.Trace(err)
 		}
 	}
 	return &storageConfig{tags: tags}, nil
 }
 
+// parseTags parses a comma-separated list of MAAS tags.
+func parseTags(s string) ([]string, error) {
+	var tags []string
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if len(f) == 0 {
+			continue
+		}
+		if i := strings.IndexFunc(f, unicode.IsSpace); i >= 0 {
+			return nil, errors.Errorf("tags may not contain whitespace: %q", f)
+		}
+		tags = append(tags, f)
+	}
+	return tags, nil
+}
+
 // ValidateConfig is defined on the Provider interface.
 func (maasStorageProvider) ValidateConfig(cfg *storage.Config) error {
 	_, err := newStorageConfig(cfg.Attrs())
@@ -149,12 +157,14 @@ func mibToGb(m uint64) uint64 {
 
 // buildMAASVolumeParameters creates the MAAS volume information to include
 // in a request to acquire a MAAS node, based on the supplied storage parameters.
-func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Value) ([]volumeInfo, error) {
-	if len(args) == 0 && cons.RootDisk == nil {
+// The root disk is requested with the given tags, so that it can be placed
+// on a specific block device.
+func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Value, rootTags []string) ([]volumeInfo, error) {
+	if len(args) == 0 && cons.RootDisk == nil && len(rootTags) == 0 {
 		return nil, nil
 	}
 	volumes := make([]volumeInfo, len(args)+1)
-	rootVolume := volumeInfo{name: rootDiskLabel}
+	rootVolume := volumeInfo{name: rootDiskLabel, tags: rootTags}
 	if cons.RootDisk != nil {
 		rootVolume.sizeInGB = mibToGb(*cons.RootDisk)
 	}
@@ -22,7 +22,7 @@ type volumeSuite struct {
 var _ = gc.Suite(&volumeSuite{})
 
 func (s *volumeSuite) TestBuildMAASVolumeParametersNoVolumes(c *gc.C) {
-	vInfo, err := buildMAASVolumeParameters(nil, constraints.Value{})
+	vInfo, err := buildMAASVolumeParameters(nil, constraints.Value{}, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(vInfo, gc.HasLen, 0)
 }
@@ -31,7 +31,7 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersJustRootDisk(c *gc.C) {
 	var cons constraints.Value
 	rootSize := uint64(20000)
 	cons.RootDisk = &rootSize
-	vInfo, err := buildMAASVolumeParameters(nil, cons)
+	vInfo, err := buildMAASVolumeParameters(nil, cons, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
 		{"root", 20, nil},
@@ -41,7 +41,7 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersJustRootDisk(c *gc.C) {
 func (s *volumeSuite) TestBuildMAASVolumeParametersNoTags(c *gc.C) {
 	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
 		{Tag: names.NewVolumeTag("1"), Size: 2000000},
-	}, constraints.Value{})
+	}, constraints.Value{}, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
 		{"root", 0, nil}, //root disk
@@ -55,7 +55,7 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersWithRootDisk(c *gc.C) {
 	cons.RootDisk = &rootSize
 	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
 		{Tag: names.NewVolumeTag("1"), Size: 2000000},
-	}, cons)
+	}, cons, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
 		{"root", 20, nil}, //root disk
@@ -66,7 +66,7 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersWithRootDisk(c *gc.C) {
 func (s *volumeSuite) TestBuildMAASVolumeParametersWithTags(c *gc.C) {
 	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{
 		{Tag: names.NewVolumeTag("1"), Size: 2000000, Attributes: map[string]interface{}{"tags": "tag1,tag2"}},
-	}, constraints.Value{})
+	}, constraints.Value{}, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
 		{"root", 0, nil}, //root disk
@@ -74,6 +74,23 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersWithTags(c *gc.C) {
 	})
 }
 
+func (s *volumeSuite) TestBuildMAASVolumeParametersWithRootDiskTags(c *gc.C) {
+	vInfo, err := buildMAASVolumeParameters(nil, constraints.Value{}, []string{"ssd"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, []string{"ssd"}},
+	})
+
+	vInfo, err = buildMAASVolumeParameters([]storage.VolumeParams{
+		{Tag: names.NewVolumeTag("1"), Size: 2000000, Attributes: map[string]interface{}{"tags": "hdd"}},
+	}, constraints.Value{}, []string{"ssd"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, []string{"ssd"}},
+		{"1", 1954, []string{"hdd"}},
+	})
+}
+
 func (s *volumeSuite) TestInstanceVolumesMAAS2(c *gc.C) {
 	instance := maas2Instance{
 		machine: &fakeMachine{},
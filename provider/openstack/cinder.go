@@ -6,7 +6,9 @@ package openstack
 import (
 	"fmt"
 	"math"
+	"net/http"
 	"net/url"
+	"path"
 	"sync"
 	"time"
 
@@ -117,7 +119,11 @@ var newOpenstackStorage = func(env *Environ) (OpenstackStorage, error) {
 	}
 
 	return &openstackStorageAdapter{
-		cinderClient{cinder.Basic(env.volumeURL, client.TenantId(), client.Token)},
+		cinderClient{
+			Client:   cinder.Basic(env.volumeURL, client.TenantId(), client.Token),
+			endpoint: env.volumeURL,
+			token:    client.Token,
+		},
 		novaClient{env.novaUnlocked},
 	}, nil
 }
@@ -591,6 +597,7 @@ type OpenstackStorage interface {
 	DetachVolume(serverId, attachmentId string) error
 	ListVolumeAttachments(serverId string) ([]nova.VolumeAttachment, error)
 	SetVolumeMetadata(volumeId string, metadata map[string]string) (map[string]string, error)
+	DeleteVolumeMetadata(volumeId, key string) error
 }
 
 type endpointResolver interface {
@@ -625,6 +632,11 @@ type openstackStorageAdapter struct {
 
 type cinderClient struct {
 	*cinder.Client
+
+	// endpoint and token are used to make the requests which
+	// the goose Cinder client does not support.
+	endpoint *url.URL
+	token    func() string
 }
 
 type novaClient struct {
@@ -666,6 +678,31 @@ func (ga *openstackStorageAdapter) SetVolumeMetadata(volumeId string, metadata m
 	return ga.cinderClient.SetVolumeMetadata(volumeId, metadata)
 }
 
+// DeleteVolumeMetadata is part of the OpenstackStorage interface.
+// The goose Cinder client can only add or update metadata items, so
+// the request is made directly.
+func (ga *openstackStorageAdapter) DeleteVolumeMetadata(volumeId, key string) error {
+	metadataURL := *ga.endpoint
+	metadataURL.Path = path.Join(metadataURL.Path, "volumes", volumeId, "metadata", key)
+	req, err := http.NewRequest("DELETE", metadataURL.String(), nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("X-Auth-Token", ga.token())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Annotatef(err, "deleting metadata %q from volume %q", key, volumeId)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return errors.NotFoundf("metadata %q on volume %q", key, volumeId)
+	}
+	return errors.Errorf("deleting metadata %q from volume %q: %s", key, volumeId, resp.Status)
+}
+
 // DeleteVolume is part of the OpenstackStorage interface.
 func (ga *openstackStorageAdapter) DeleteVolume(volumeId string) error {
 	if err := ga.cinderClient.DeleteVolume(volumeId); err != nil {
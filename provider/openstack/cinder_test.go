@@ -623,6 +623,7 @@ type mockAdapter struct {
 	detachVolume          func(string, string) error
 	listVolumeAttachments func(string) ([]nova.VolumeAttachment, error)
 	setVolumeMetadata     func(string, map[string]string) (map[string]string, error)
+	deleteVolumeMetadata  func(string, string) error
 }
 
 func (ma *mockAdapter) GetVolume(volumeId string) (*cinder.Volume, error) {
@@ -692,6 +693,14 @@ func (ma *mockAdapter) SetVolumeMetadata(volumeId string, metadata map[string]st
 	return nil, nil
 }
 
+func (ma *mockAdapter) DeleteVolumeMetadata(volumeId, key string) error {
+	ma.MethodCall(ma, "DeleteVolumeMetadata", volumeId, key)
+	if ma.deleteVolumeMetadata != nil {
+		return ma.deleteVolumeMetadata(volumeId, key)
+	}
+	return nil
+}
+
 type testEndpointResolver struct {
 	authenticated   bool
 	regionEndpoints map[string]identity.ServiceURLs
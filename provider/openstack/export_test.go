@@ -74,6 +74,7 @@ var (
 	NovaListAvailabilityZones   = &novaListAvailabilityZones
 	AvailabilityZoneAllocations = &availabilityZoneAllocations
 	NewOpenstackStorage         = &newOpenstackStorage
+	DeleteServerMetadata        = &deleteServerMetadata
 )
 
 func NewCinderVolumeSource(s OpenstackStorage) storage.VolumeSource {
@@ -562,3 +563,5 @@ func GetFirewaller(e environs.Environ) Firewaller {
 	env := e.(*Environ)
 	return env.firewaller
 }
+
+type RequestSender = requestSender
@@ -1183,7 +1183,8 @@ func (s *localServerSuite) TestSubnetsFindAll(c *gc.C) {
 			ProviderId:        network.Id(os.Id),
 			VLANTag:           0,
 			AvailabilityZones: net.AvailabilityZones,
-			SpaceProviderId:   "",
+			SpaceProviderId:   network.Id(os.NetworkId),
+			ProviderNetworkId: network.Id(os.NetworkId),
 		}
 	}
 
@@ -1220,7 +1221,8 @@ func (s *localServerSuite) TestSubnetsFindAllWithExternal(c *gc.C) {
 			ProviderId:        network.Id(os.Id),
 			VLANTag:           0,
 			AvailabilityZones: net.AvailabilityZones,
-			SpaceProviderId:   "",
+			SpaceProviderId:   network.Id(os.NetworkId),
+			ProviderNetworkId: network.Id(os.NetworkId),
 		}
 	}
 
@@ -1254,6 +1256,95 @@ func (s *localServerSuite) TestSuperSubnets(c *gc.C) {
 	c.Check(obtainedSubnets, jc.DeepEquals, expectedSubnets)
 }
 
+func (s *localServerSuite) TestSupportsSpaces(c *gc.C) {
+	env := s.prepareNetworkingEnviron(c, s.env.Config())
+	supported, err := env.SupportsSpaces(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(supported, jc.IsTrue)
+	supported, err = env.SupportsSpaceDiscovery(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(supported, jc.IsTrue)
+}
+
+func (s *localServerSuite) TestSpaces(c *gc.C) {
+	env := s.prepareNetworkingEnviron(c, s.env.Config())
+	spaces, err := env.Spaces(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	obtainedSpaceMap := make(map[network.Id]network.SpaceInfo)
+	for _, space := range spaces {
+		obtainedSpaceMap[space.ProviderId] = space
+	}
+	// The external network is not a space.
+	c.Check(obtainedSpaceMap, gc.Not(jc.HasKey), network.Id("998"))
+
+	// The internal network is a space, containing all of its subnets.
+	space, ok := obtainedSpaceMap["999"]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(space.Name, gc.Equals, "private_999")
+
+	neutronClient := openstack.GetNeutronClient(s.env)
+	openstackSubnets, err := neutronClient.ListSubnetsV2()
+	c.Assert(err, jc.ErrorIsNil)
+	net, err := neutronClient.GetNetworkV2("999")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var expectedSubnets []network.SubnetInfo
+	for _, os := range openstackSubnets {
+		if os.NetworkId != "999" {
+			continue
+		}
+		expectedSubnets = append(expectedSubnets, network.SubnetInfo{
+			CIDR:              os.Cidr,
+			ProviderId:        network.Id(os.Id),
+			AvailabilityZones: net.AvailabilityZones,
+			SpaceProviderId:   "999",
+			ProviderNetworkId: "999",
+		})
+	}
+	c.Check(space.Subnets, jc.SameContents, expectedSubnets)
+}
+
+func (s *localServerSuite) TestStartInstanceSpacesUnknownSubnet(c *gc.C) {
+	err := bootstrapEnv(c, s.env)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = testing.StartInstanceWithParams(s.env, s.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID: s.ControllerUUID,
+		SubnetsToZones: map[network.Id][]string{
+			"missing": {"test-available"},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `subnet "missing" not found`)
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+}
+
+func (s *localServerSuite) TestStartInstanceSpacesSubnet(c *gc.C) {
+	err := bootstrapEnv(c, s.env)
+	c.Assert(err, jc.ErrorIsNil)
+
+	neutronClient := openstack.GetNeutronClient(s.env)
+	openstackSubnets, err := neutronClient.ListSubnetsV2()
+	c.Assert(err, jc.ErrorIsNil)
+	subnetsToZones := make(map[network.Id][]string)
+	for _, os := range openstackSubnets {
+		if os.NetworkId == "999" {
+			subnetsToZones[network.Id(os.Id)] = []string{"test-available"}
+		}
+	}
+	c.Assert(subnetsToZones, gc.Not(gc.HasLen), 0)
+
+	// The network containing the subnets is the model's network,
+	// so the instance is started with only that network.
+	result, err := testing.StartInstanceWithParams(s.env, s.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID: s.ControllerUUID,
+		SubnetsToZones: subnetsToZones,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.env.StopInstances(s.callCtx, result.Instance.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *localServerSuite) TestFindImageBadDefaultImage(c *gc.C) {
 	imagetesting.PatchOfficialDataSources(&s.CleanupSuite, "")
 	env := s.Open(c, s.env.Config())
@@ -2408,6 +2499,121 @@ func (t *localServerSuite) TestTagInstance(c *gc.C) {
 	assertMetadata(extraKey, extraValue)
 }
 
+func (t *localServerSuite) TestUpdateInstanceTags(c *gc.C) {
+	err := bootstrapEnv(c, t.env)
+	c.Assert(err, jc.ErrorIsNil)
+	instances, err := t.env.AllInstances(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+	updater := t.env.(environs.TagUpdater)
+
+	desired := map[string]string{
+		"juju-model-uuid": coretesting.ModelTag.Id(),
+		"team":            "web",
+	}
+	changed, err := updater.UpdateInstanceTags(t.callCtx, instances[0].Id(), desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	changed, err = updater.UpdateInstanceTags(t.callCtx, instances[0].Id(), desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsFalse)
+
+	instances, err = t.env.AllInstances(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(
+		openstack.InstanceServerDetail(instances[0]).Metadata,
+		jc.DeepEquals,
+		map[string]string{
+			"juju-model-uuid":      coretesting.ModelTag.Id(),
+			"juju-controller-uuid": coretesting.ControllerTag.Id(),
+			"juju-is-controller":   "true",
+			"juju-resource-tags":   "team",
+			"team":                 "web",
+		},
+	)
+}
+
+func (t *localServerSuite) TestUpdateInstanceTagsRemovesTags(c *gc.C) {
+	err := bootstrapEnv(c, t.env)
+	c.Assert(err, jc.ErrorIsNil)
+	instances, err := t.env.AllInstances(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+	updater := t.env.(environs.TagUpdater)
+
+	var deleted []string
+	t.PatchValue(openstack.DeleteServerMetadata, func(_ openstack.RequestSender, serverId, key string) error {
+		c.Check(serverId, gc.Equals, string(instances[0].Id()))
+		deleted = append(deleted, key)
+		return nil
+	})
+
+	_, err = updater.UpdateInstanceTags(t.callCtx, instances[0].Id(), map[string]string{
+		"team":        "web",
+		"cost-centre": "42",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deleted, gc.HasLen, 0)
+
+	changed, err := updater.UpdateInstanceTags(t.callCtx, instances[0].Id(), map[string]string{"team": "web"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	c.Assert(deleted, jc.DeepEquals, []string{"cost-centre"})
+}
+
+func (t *localServerSuite) TestUpdateInstanceTagsNotFound(c *gc.C) {
+	_, err := t.env.(environs.TagUpdater).UpdateInstanceTags(t.callCtx, "missing", map[string]string{"team": "web"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (t *localServerSuite) TestUpdateVolumeTags(c *gc.C) {
+	err := bootstrapEnv(c, t.env)
+	c.Assert(err, jc.ErrorIsNil)
+	volume := addVolume(c, t.env, t.callCtx, coretesting.ControllerTag.Id(), "0")
+	updater := t.env.(environs.TagUpdater)
+
+	desired := map[string]string{"team": "web"}
+	changed, err := updater.UpdateVolumeTags(t.callCtx, volume.VolumeId, desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	changed, err = updater.UpdateVolumeTags(t.callCtx, volume.VolumeId, desired)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsFalse)
+
+	storageAdapter, err := (*openstack.NewOpenstackStorage)(t.env.(*openstack.Environ))
+	c.Assert(err, jc.ErrorIsNil)
+	cinderVolume, err := storageAdapter.GetVolume(volume.VolumeId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cinderVolume.Metadata["team"], gc.Equals, "web")
+	c.Check(cinderVolume.Metadata[tags.JujuResourceTags], gc.Equals, "team")
+	c.Check(cinderVolume.Metadata[tags.JujuController], gc.Equals, coretesting.ControllerTag.Id())
+}
+
+func (t *localServerSuite) TestUpdateVolumeTagsRemovesTags(c *gc.C) {
+	mockAdapter := &mockAdapter{
+		getVolume: func(volumeId string) (*cinder.Volume, error) {
+			return &cinder.Volume{
+				ID: volumeId,
+				Metadata: map[string]string{
+					tags.JujuResourceTags: "cost-centre team",
+					"cost-centre":         "42",
+					"team":                "web",
+				},
+			}, nil
+		},
+	}
+	overrideCinderProvider(c, &t.CleanupSuite, mockAdapter)
+
+	changed, err := t.env.(environs.TagUpdater).UpdateVolumeTags(t.callCtx, "vol-0", map[string]string{"team": "web"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changed, jc.IsTrue)
+	mockAdapter.CheckCalls(c, []gitjujutesting.StubCall{
+		{"GetVolume", []interface{}{"vol-0"}},
+		{"SetVolumeMetadata", []interface{}{"vol-0", map[string]string{tags.JujuResourceTags: "team"}}},
+		{"DeleteVolumeMetadata", []interface{}{"vol-0", "cost-centre"}},
+	})
+}
+
 func (s *localServerSuite) TestAdoptResources(c *gc.C) {
 	err := bootstrapEnv(c, s.env)
 	c.Assert(err, jc.ErrorIsNil)
@@ -2755,6 +2961,14 @@ func makeCredential(cred *identity.Credentials) cloud.Credential {
 
 // noSwiftSuite contains tests that run against an OpenStack service double
 // that lacks Swift.
+func (s *noNeutronSuite) TestSupportsSpaces(c *gc.C) {
+	netenv, ok := environs.SupportsNetworking(s.env)
+	c.Assert(ok, jc.IsTrue)
+	supported, err := netenv.SupportsSpaces(context.NewCloudCallContext())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsFalse)
+}
+
 type noSwiftSuite struct {
 	coretesting.BaseSuite
 	cred *identity.Credentials
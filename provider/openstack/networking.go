@@ -6,11 +6,16 @@ package openstack
 import (
 	"fmt"
 	"net"
+	"net/http"
+	"net/url"
+	"sort"
 	"sync"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/utils"
+	gooseclient "gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
 	"gopkg.in/goose.v2/neutron"
 	"gopkg.in/goose.v2/nova"
 
@@ -43,6 +48,11 @@ type Networking interface {
 	// interfaces on the given instance.
 	// Needed for Environ.Networking
 	NetworkInterfaces(instId instance.Id) ([]network.InterfaceInfo, error)
+
+	// Spaces returns the spaces known by OpenStack for the
+	// environment, along with their subnets.
+	// Needed for Environ.Networking
+	Spaces() ([]network.SpaceInfo, error)
 }
 
 // NetworkingDecorator is an interface that provides a means of overriding
@@ -127,6 +137,14 @@ func (n *switchingNetworking) NetworkInterfaces(instId instance.Id) ([]network.I
 	return n.networking.NetworkInterfaces(instId)
 }
 
+// Spaces is part of the Networking interface.
+func (n *switchingNetworking) Spaces() ([]network.SpaceInfo, error) {
+	if err := n.initNetworking(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return n.networking.Spaces()
+}
+
 type networkingBase struct {
 	env *Environ
 }
@@ -308,20 +326,441 @@ func makeSubnetInfo(neutron *neutron.Client, subnet neutron.SubnetV2) (network.S
 		return network.SubnetInfo{}, err
 	}
 
+	info := makeNetworkSubnetInfo(*net, subnet)
+	logger.Tracef("found subnet with info %#v", info)
+	return info, nil
+}
+
+// makeNetworkSubnetInfo returns the SubnetInfo for a subnet of the given
+// Neutron network. Each Neutron network is modelled as a space, so the
+// subnet's space is identified by the network's ID.
+func makeNetworkSubnetInfo(net neutron.NetworkV2, subnet neutron.SubnetV2) network.SubnetInfo {
 	// TODO (hml) 2017-03-20:
 	// With goose updates, VLANTag can be updated to be
 	// network.segmentation_id, if network.network_type equals vlan
-	info := network.SubnetInfo{
+	return network.SubnetInfo{
 		CIDR:              subnet.Cidr,
 		ProviderId:        network.Id(subnet.Id),
 		VLANTag:           0,
 		AvailabilityZones: net.AvailabilityZones,
-		SpaceProviderId:   "",
+		SpaceProviderId:   network.Id(net.Id),
+		ProviderNetworkId: network.Id(net.Id),
+	}
+}
+
+// Subnets returns basic information about the specified subnets known
+// by the provider for the specified instance or list of ids. subnetIds can be
+// empty, in which case all known are returned.
+func (n *NeutronNetworking) Subnets(instId instance.Id, subnetIds []network.Id) ([]network.SubnetInfo, error) {
+	var results []network.SubnetInfo
+	subIdSet := set.NewStrings()
+	for _, subId := range subnetIds {
+		subIdSet.Add(string(subId))
+	}
+	netIds := set.NewStrings()
+	displayIds := ""
+	neutron := n.env.neutron()
+	network := n.env.ecfg().network()
+	netId, err := resolveNeutronNetwork(neutron, network, false)
+	if err != nil {
+		logger.Warningf("could not resolve internal network id for %q: %v", network, err)
+		// Note: (jam 2018-05-23) We don't treat this as fatal because we used to never pay attention to it anyway
+	} else {
+		netIds.Add(netId)
+		displayIds = fmt.Sprintf("[%q", netId)
+		// Note, there are cases where we will detect an external
+		// network without it being explicitly configured by the user.
+		// The subnets of other networks are discovered through Spaces.
+		externalNetwork := n.env.ecfg().externalNetwork()
+		if externalNetwork != "" {
+			netId, err := resolveNeutronNetwork(neutron, externalNetwork, true)
+			if err != nil {
+				logger.Warningf("could not resolve external network id for %q: %v", externalNetwork, err)
+			} else {
+				netIds.Add(netId)
+				displayIds = fmt.Sprintf("%s, %q", displayIds, netId)
+			}
+		}
+		displayIds = displayIds + "]"
+	}
+	logger.Debugf("finding subnets in networks: %s", displayIds)
+
+	if instId != instance.UnknownId {
+		// TODO(hml): 2017-03-20
+		// Implement Subnets() for case where instId is specified
+		return nil, errors.NotSupportedf("neutron subnets with instance Id")
+	} else {
+		// TODO(jam): 2018-05-23 It is likely that ListSubnetsV2 could
+		// take a Filter rather that doing the filtering client side.
+		subnets, err := neutron.ListSubnetsV2()
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to retrieve subnets")
+		}
+		if len(subnetIds) == 0 {
+			for _, subnet := range subnets {
+				if !netIds.IsEmpty() && !netIds.Contains(subnet.NetworkId) {
+					logger.Tracef("ignoring subnet %q, part of network %q not %v", subnet.Id, subnet.NetworkId, displayIds)
+					continue
+				}
+				subIdSet.Add(subnet.Id)
+			}
+		}
+		for _, subnet := range subnets {
+			if !subIdSet.Contains(subnet.Id) {
+				logger.Tracef("subnet %q not in %v, skipping", subnet.Id, subnetIds)
+				continue
+			}
+			subIdSet.Remove(subnet.Id)
+			if info, err := makeSubnetInfo(neutron, subnet); err == nil {
+				// Error will already have been logged.
+				results = append(results, info)
+			}
+
+		}
+	}
+	if !subIdSet.IsEmpty() {
+		return nil, errors.Errorf("failed to find the following subnet ids: %v", subIdSet.Values())
+	}
+	return results, nil
+}
+
+// internalNetworkFilter returns a neutron.Filter to match Neutron Networks
+// with router:external = false.
+func internalNetworkFilter() *neutron.Filter {
+	filter := neutron.NewFilter()
+	filter.Set(neutron.FilterRouterExternal, "false")
+	return filter
+}
+
+// NetworkInterfaces is part of the Networking interface.
+//
+// Each of the instance's Neutron ports is reported as a NIC, with the
+// port's MAC address and an interface for each of its fixed IPs.
+func (n *NeutronNetworking) NetworkInterfaces(instId instance.Id) ([]network.InterfaceInfo, error) {
+	if _, err := n.env.nova().GetServer(string(instId)); err != nil {
+		return nil, errors.Annotatef(err, "getting instance %q", instId)
+	}
+	ports, err := listServerPorts(n.env.client(), string(instId))
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting ports of instance %q", instId)
+	}
+	neutron := n.env.neutron()
+	networks, err := neutron.ListNetworksV2(internalNetworkFilter())
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to retrieve networks")
+	}
+	subnets, err := neutron.ListSubnetsV2()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to retrieve subnets")
+	}
+	return makeInterfaceInfos(ports, networks, subnets), nil
+}
+
+// neutronPort holds the details of a Neutron port needed to
+// report an instance's network interfaces.
+type neutronPort struct {
+	Id         string `json:"id"`
+	NetworkId  string `json:"network_id"`
+	MACAddress string `json:"mac_address"`
+	CreatedAt  string `json:"created_at"`
+	FixedIPs   []struct {
+		SubnetId  string `json:"subnet_id"`
+		IPAddress string `json:"ip_address"`
+	} `json:"fixed_ips"`
+}
+
+// requestSender sends requests to an OpenStack service.
+type requestSender interface {
+	SendRequest(method, svcType, apiVersion, apiCall string, requestData *goosehttp.RequestData) error
+}
+
+// listServerPorts returns the Neutron ports attached to the server with
+// the given ID, in the order in which they were created. Nova creates
+// the ports for a new server in the order of the server's networks,
+// which is also the order of the server's NICs.
+func listServerPorts(client requestSender, serverId string) ([]neutronPort, error) {
+	var resp struct {
+		Ports []neutronPort `json:"ports"`
+	}
+	params := url.Values{"device_id": {serverId}}
+	requestData := goosehttp.RequestData{
+		RespValue:      &resp,
+		Params:         &params,
+		ExpectedStatus: []int{http.StatusOK},
+	}
+	if err := client.SendRequest(gooseclient.GET, "network", "v2.0", "ports", &requestData); err != nil {
+		return nil, errors.Trace(err)
+	}
+	ports := resp.Ports
+	sort.SliceStable(ports, func(i, j int) bool {
+		if ports[i].CreatedAt != ports[j].CreatedAt {
+			return ports[i].CreatedAt < ports[j].CreatedAt
+		}
+		return ports[i].Id < ports[j].Id
+	})
+	return ports, nil
+}
+
+// makeInterfaceInfos returns an InterfaceInfo for each fixed IP of the
+// given ports, which must be in device order. Ports that are not on a
+// known network, and fixed IPs that are not in a known subnet, are
+// skipped.
+func makeInterfaceInfos(
+	ports []neutronPort,
+	networks []neutron.NetworkV2,
+	subnets []neutron.SubnetV2,
+) []network.InterfaceInfo {
+	networksById := make(map[string]neutron.NetworkV2)
+	for _, netw := range networks {
+		networksById[netw.Id] = netw
+	}
+	subnetsById := make(map[string]neutron.SubnetV2)
+	for _, subnet := range subnets {
+		subnetsById[subnet.Id] = subnet
+	}
+
+	var results []network.InterfaceInfo
+	for deviceIndex, port := range ports {
+		netw, ok := networksById[port.NetworkId]
+		if !ok {
+			logger.Debugf("port %q on unknown network %q, skipping", port.Id, port.NetworkId)
+			continue
+		}
+		for _, fixedIP := range port.FixedIPs {
+			subnet, ok := subnetsById[fixedIP.SubnetId]
+			if !ok {
+				logger.Debugf("address %q of port %q not in any known subnet, skipping", fixedIP.IPAddress, port.Id)
+				continue
+			}
+			results = append(results, network.InterfaceInfo{
+				DeviceIndex:       deviceIndex,
+				MACAddress:        port.MACAddress,
+				CIDR:              subnet.Cidr,
+				ProviderId:        network.Id(port.Id),
+				ProviderSubnetId:  network.Id(subnet.Id),
+				ProviderNetworkId: network.Id(netw.Id),
+				ProviderSpaceId:   network.Id(netw.Id),
+				AvailabilityZones: netw.AvailabilityZones,
+				InterfaceType:     network.EthernetInterface,
+				ConfigType:        network.ConfigDHCP,
+				Address:           network.NewAddress(fixedIP.IPAddress),
+			})
+		}
+	}
+	return results
+}
+
+// Spaces is part of the Networking interface.
+func (n *switchingNetworking) Spaces() ([]network.SpaceInfo, error) {
+	if err := n.initNetworking(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return n.networking.Spaces()
+}
+
+type networkingBase struct {
+	env *Environ
+}
+
+func processResolveNetworkIds(name string, networkIds []string) (string, error) {
+	switch len(networkIds) {
+	case 1:
+		return networkIds[0], nil
+	case 0:
+		return "", errors.Errorf("no networks exist with label %q", name)
+	}
+	return "", errors.Errorf("multiple networks with label %q: %v", name, networkIds)
+}
+
+// NeutronNetworking is an implementation of Networking that uses the Neutron
+// network APIs.
+type NeutronNetworking struct {
+	networkingBase
+}
+
+// networkFilter returns a neutron.Filter to match Neutron Networks with
+// the exact given name AND router:external boolean result.
+func projectIdFilter(projectId string) *neutron.Filter {
+	filter := neutron.NewFilter()
+	filter.Set(neutron.FilterProjectId, projectId)
+	return filter
+}
+
+// AllocatePublicIP is part of the Networking interface.
+func (n *NeutronNetworking) AllocatePublicIP(instId instance.Id) (*string, error) {
+	extNetworkIds := make([]string, 0)
+	neutronClient := n.env.neutron()
+	externalNetwork := n.env.ecfg().externalNetwork()
+	if externalNetwork != "" {
+		// the config specified an external network, try it first.
+		netId, err := resolveNeutronNetwork(neutronClient, externalNetwork, true)
+		if err != nil {
+			logger.Debugf("external network %s not found, search for one", externalNetwork)
+		} else {
+			logger.Debugf("using external network %q", externalNetwork)
+			extNetworkIds = []string{netId}
+		}
+	}
+
+	if len(extNetworkIds) == 0 {
+		// Create slice of network.Ids for external networks in the same AZ as
+		// the instance's network, to find an existing floating ip in, or allocate
+		// a new floating ip from.
+		network := n.env.ecfg().network()
+		netId, err := resolveNeutronNetwork(neutronClient, network, false)
+		netDetails, err := neutronClient.GetNetworkV2(netId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		for _, az := range netDetails.AvailabilityZones {
+			extNetIds, _ := getExternalNeutronNetworksByAZ(n.env, az)
+			if len(extNetIds) > 0 {
+				extNetworkIds = append(extNetworkIds, extNetIds...)
+			}
+		}
+
+		if len(extNetworkIds) == 0 {
+			return nil, errors.NewNotFound(nil, fmt.Sprintf("could not find an external network in availability zone %s", netDetails.AvailabilityZones))
+		}
+	}
+
+	// Look for FIPs in same project as the credentials.
+	// Admins have visibility into other projects.
+	fips, err := n.env.neutron().ListFloatingIPsV2(projectIdFilter(n.env.client().TenantId()))
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
+
+	// Is there an unused FloatingIP on an external network in the instance's availability zone?
+	for _, fip := range fips {
+		if fip.FixedIP == "" {
+			// Not a perfect solution.  If an external network was specified in the
+			// config, it'll be at the top of the extNetworkIds, but may be not used
+			// if the available FIP isn't it in.  However the instance and the
+			// FIP will be in the same availability zone.
+			for _, extNetId := range extNetworkIds {
+				if fip.FloatingNetworkId == extNetId {
+					logger.Debugf("found unassigned public ip: %v", fip.IP)
+					return &fip.IP, nil
+				}
+			}
+		}
+	}
+
+	// allocate a new IP and use it
+	var lastErr error
+	for _, extNetId := range extNetworkIds {
+		var newfip *neutron.FloatingIPV2
+		newfip, lastErr = neutronClient.AllocateFloatingIPV2(extNetId)
+		if lastErr == nil {
+			logger.Debugf("allocated new public IP: %s", newfip.IP)
+			return &newfip.IP, nil
+		}
+	}
+
+	logger.Debugf("Unable to allocate a public IP")
+	return nil, lastErr
+}
+
+// externalNetworkFilter returns a neutron.Filter to match Neutron Networks with
+// router:external = true.
+func externalNetworkFilter() *neutron.Filter {
+	filter := neutron.NewFilter()
+	filter.Set(neutron.FilterRouterExternal, "true")
+	return filter
+}
+
+// getExternalNeutronNetworksByAZ returns all external networks within the
+// given availability zone. If azName is empty, return all external networks.
+func getExternalNeutronNetworksByAZ(e *Environ, azName string) ([]string, error) {
+	neutron := e.neutron()
+	// Find all external networks in availability zone
+	networks, err := neutron.ListNetworksV2(externalNetworkFilter())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	netIds := make([]string, 0)
+	for _, network := range networks {
+		for _, netAZ := range network.AvailabilityZones {
+			if azName == netAZ {
+				netIds = append(netIds, network.Id)
+				break
+			}
+		}
+	}
+	if len(netIds) == 0 {
+		return nil, errors.NewNotFound(nil, "No External networks found to allocate a Floating IP")
+	}
+	return netIds, nil
+}
+
+// DefaultNetworks is part of the Networking interface.
+func (n *NeutronNetworking) DefaultNetworks() ([]nova.ServerNetworks, error) {
+	return []nova.ServerNetworks{}, nil
+}
+
+// ResolveNetwork is part of the Networking interface.
+func (n *NeutronNetworking) ResolveNetwork(name string, external bool) (string, error) {
+	return resolveNeutronNetwork(n.env.neutron(), name, external)
+}
+
+// networkFilter returns a neutron.Filter to match Neutron Networks with
+// the exact given name AND router:external boolean result.
+func networkFilter(name string, external bool) *neutron.Filter {
+	filter := neutron.NewFilter()
+	filter.Set(neutron.FilterNetwork, fmt.Sprintf("%s", name))
+	filter.Set(neutron.FilterRouterExternal, fmt.Sprintf("%t", external))
+	return filter
+}
+
+func resolveNeutronNetwork(neutron *neutron.Client, name string, external bool) (string, error) {
+	if utils.IsValidUUIDString(name) {
+		return name, nil
+	}
+	networks, err := neutron.ListNetworksV2(networkFilter(name, external))
+	if err != nil {
+		return "", err
+	}
+	var networkIds []string
+	for _, network := range networks {
+		networkIds = append(networkIds, network.Id)
+	}
+	return processResolveNetworkIds(name, networkIds)
+}
+
+func makeSubnetInfo(neutron *neutron.Client, subnet neutron.SubnetV2) (network.SubnetInfo, error) {
+	_, _, err := net.ParseCIDR(subnet.Cidr)
+	if err != nil {
+		return network.SubnetInfo{}, errors.Annotatef(err, "skipping subnet %q, invalid CIDR", subnet.Cidr)
+	}
+	net, err := neutron.GetNetworkV2(subnet.NetworkId)
+	if err != nil {
+		return network.SubnetInfo{}, err
+	}
+
+	info := makeNetworkSubnetInfo(*net, subnet)
 	logger.Tracef("found subnet with info %#v", info)
 	return info, nil
 }
 
+// makeNetworkSubnetInfo returns the SubnetInfo for a subnet of the given
+// Neutron network. Each Neutron network is modelled as a space, so the
+// subnet's space is identified by the network's ID.
+func makeNetworkSubnetInfo(net neutron.NetworkV2, subnet neutron.SubnetV2) network.SubnetInfo {
+	// TODO (hml) 2017-03-20:
+	// With goose updates, VLANTag can be updated to be
+	// network.segmentation_id, if network.network_type equals vlan
+	return network.SubnetInfo{
+		CIDR:              subnet.Cidr,
+		ProviderId:        network.Id(subnet.Id),
+		VLANTag:           0,
+		AvailabilityZones: net.AvailabilityZones,
+		SpaceProviderId:   network.Id(net.Id),
+		ProviderNetworkId: network.Id(net.Id),
+	}
+}
+
 // Subnets returns basic information about the specified subnets known
 // by the provider for the specified instance or list of ids. subnetIds can be
 // empty, in which case all known are returned.
@@ -344,8 +783,7 @@ func (n *NeutronNetworking) Subnets(instId instance.Id, subnetIds []network.Id)
 		displayIds = fmt.Sprintf("[%q", netId)
 		// Note, there are cases where we will detect an external
 		// network without it being explicitly configured by the user.
-		// When we get to a point where we start detecting spaces for
-		// users on Openstack, we'll probably need to include better logic here.
+		// The subnets of other networks are discovered through Spaces.
 		externalNetwork := n.env.ecfg().externalNetwork()
 		if externalNetwork != "" {
 			netId, err := resolveNeutronNetwork(neutron, externalNetwork, true)
@@ -399,6 +837,152 @@ func (n *NeutronNetworking) Subnets(instId instance.Id, subnetIds []network.Id)
 	return results, nil
 }
 
+// internalNetworkFilter returns a neutron.Filter to match Neutron Networks
+// with router:external = false.
+func internalNetworkFilter() *neutron.Filter {
+	filter := neutron.NewFilter()
+	filter.Set(neutron.FilterRouterExternal, "false")
+	return filter
+}
+
+// NetworkInterfaces is part of the Networking interface.
+//
+// Nova reports the instance's fixed addresses keyed by network name, but
+// not their MAC addresses or the order of the NICs, so interfaces are
+// reported in network name order.
 func (n *NeutronNetworking) NetworkInterfaces(instId instance.Id) ([]network.InterfaceInfo, error) {
-	return nil, errors.NotSupportedf("neutron network interfaces")
+	server, err := n.env.nova().GetServer(string(instId))
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting instance %q", instId)
+	}
+	neutron := n.env.neutron()
+	networks, err := neutron.ListNetworksV2(internalNetworkFilter())
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to retrieve networks")
+	}
+	subnets, err := neutron.ListSubnetsV2()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to retrieve subnets")
+	}
+	return makeInterfaceInfos(server.Addresses, networks, subnets), nil
+}
+
+// makeInterfaceInfos returns an InterfaceInfo for each fixed address of
+// an instance, given the instance's addresses keyed by network name.
+// Each network the instance is connected to is reported as a separate
+// NIC. Addresses that are not in a subnet of a known network are skipped.
+func makeInterfaceInfos(
+	addresses map[string][]nova.IPAddress,
+	networks []neutron.NetworkV2,
+	subnets []neutron.SubnetV2,
+) []network.InterfaceInfo {
+	var netNames []string
+	for netName := range addresses {
+		netNames = append(netNames, netName)
+	}
+	sort.Strings(netNames)
+
+	var results []network.InterfaceInfo
+	for deviceIndex, netName := range netNames {
+		for _, address := range addresses[netName] {
+			if address.Type == "floating" {
+				continue
+			}
+			netw, subnet, ok := findAddressSubnet(netName, address.Address, networks, subnets)
+			if !ok {
+				logger.Debugf("address %q on network %q not in any known subnet, skipping", address.Address, netName)
+				continue
+			}
+			results = append(results, network.InterfaceInfo{
+				DeviceIndex:       deviceIndex,
+				CIDR:              subnet.Cidr,
+				ProviderSubnetId:  network.Id(subnet.Id),
+				ProviderNetworkId: network.Id(netw.Id),
+				ProviderSpaceId:   network.Id(netw.Id),
+				AvailabilityZones: netw.AvailabilityZones,
+				InterfaceType:     network.EthernetInterface,
+				ConfigType:        network.ConfigDHCP,
+				Address:           network.NewAddress(address.Address),
+			})
+		}
+	}
+	return results
+}
+
+// findAddressSubnet returns the network with the given name, and its
+// subnet, that contain the given address.
+func findAddressSubnet(
+	netName, address string,
+	networks []neutron.NetworkV2,
+	subnets []neutron.SubnetV2,
+) (neutron.NetworkV2, neutron.SubnetV2, bool) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return neutron.NetworkV2{}, neutron.SubnetV2{}, false
+	}
+	for _, netw := range networks {
+		if netw.Name != netName {
+			continue
+		}
+		for _, subnet := range subnets {
+			if subnet.NetworkId != netw.Id {
+				continue
+			}
+			_, ipNet, err := net.ParseCIDR(subnet.Cidr)
+			if err != nil || !ipNet.Contains(ip) {
+				continue
+			}
+			return netw, subnet, true
+		}
+	}
+	return neutron.NetworkV2{}, neutron.SubnetV2{}, false
+}
+
+// Spaces is part of the Networking interface.
+//
+// Each internal Neutron network with subnets is modelled as a space,
+// named after the network and identified by the network's ID.
+func (n *NeutronNetworking) Spaces() ([]network.SpaceInfo, error) {
+	neutron := n.env.neutron()
+	networks, err := neutron.ListNetworksV2(internalNetworkFilter())
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to retrieve networks")
+	}
+	subnets, err := neutron.ListSubnetsV2()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to retrieve subnets")
+	}
+	return makeSpaceInfos(networks, subnets), nil
+}
+
+// makeSpaceInfos returns a SpaceInfo for each of the given networks that
+// has subnets. Networks with no name are named by their ID.
+func makeSpaceInfos(networks []neutron.NetworkV2, subnets []neutron.SubnetV2) []network.SpaceInfo {
+	networkSubnets := make(map[string][]neutron.SubnetV2)
+	for _, subnet := range subnets {
+		networkSubnets[subnet.NetworkId] = append(networkSubnets[subnet.NetworkId], subnet)
+	}
+
+	var spaces []network.SpaceInfo
+	for _, netw := range networks {
+		space := network.SpaceInfo{
+			Name:       netw.Name,
+			ProviderId: network.Id(netw.Id),
+		}
+		if space.Name == "" {
+			space.Name = netw.Id
+		}
+		for _, subnet := range networkSubnets[netw.Id] {
+			if _, _, err := net.ParseCIDR(subnet.Cidr); err != nil {
+				logger.Warningf("skipping subnet %q, invalid CIDR %q", subnet.Id, subnet.Cidr)
+				continue
+			}
+			space.Subnets = append(space.Subnets, makeNetworkSubnetInfo(netw, subnet))
+		}
+		// Skip spaces with no subnets.
+		if len(space.Subnets) > 0 {
+			spaces = append(spaces, space)
+		}
+	}
+	return spaces
 }
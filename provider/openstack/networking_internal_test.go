@@ -0,0 +1,197 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goosehttp "gopkg.in/goose.v2/http"
+	"gopkg.in/goose.v2/neutron"
+
+	"github.com/juju/juju/network"
+)
+
+type networkingInternalSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&networkingInternalSuite{})
+
+var (
+	testNetworks = []neutron.NetworkV2{{
+		Id:                "net-a",
+		Name:              "alpha",
+		AvailabilityZones: []string{"nova"},
+	}, {
+		Id:   "net-b",
+		Name: "beta",
+	}, {
+		Id: "net-c",
+	}}
+	testSubnets = []neutron.SubnetV2{{
+		Id:        "sub-a1",
+		NetworkId: "net-a",
+		Cidr:      "10.0.0.0/24",
+	}, {
+		Id:        "sub-a2",
+		NetworkId: "net-a",
+		Cidr:      "10.0.1.0/24",
+	}, {
+		Id:        "sub-b1",
+		NetworkId: "net-b",
+		Cidr:      "invalid",
+	}, {
+		Id:        "sub-c1",
+		NetworkId: "net-c",
+		Cidr:      "192.168.0.0/16",
+	}}
+)
+
+func (s *networkingInternalSuite) TestMakeSpaceInfos(c *gc.C) {
+	spaces := makeSpaceInfos(testNetworks, testSubnets)
+	c.Assert(spaces, jc.DeepEquals, []network.SpaceInfo{{
+		Name:       "alpha",
+		ProviderId: "net-a",
+		Subnets: []network.SubnetInfo{{
+			CIDR:              "10.0.0.0/24",
+			ProviderId:        "sub-a1",
+			AvailabilityZones: []string{"nova"},
+			SpaceProviderId:   "net-a",
+			ProviderNetworkId: "net-a",
+		}, {
+			CIDR:              "10.0.1.0/24",
+			ProviderId:        "sub-a2",
+			AvailabilityZones: []string{"nova"},
+			SpaceProviderId:   "net-a",
+			ProviderNetworkId: "net-a",
+		}},
+	}, {
+		// The unnamed network is named after its ID; "beta"
+		// is skipped, as it has no valid subnets.
+		Name:       "net-c",
+		ProviderId: "net-c",
+		Subnets: []network.SubnetInfo{{
+			CIDR:              "192.168.0.0/16",
+			ProviderId:        "sub-c1",
+			SpaceProviderId:   "net-c",
+			ProviderNetworkId: "net-c",
+		}},
+	}})
+}
+
+func (s *networkingInternalSuite) TestListServerPorts(c *gc.C) {
+	sender := &fakeRequestSender{response: `{"ports": [
+		{"id": "port-2", "network_id": "net-c", "mac_address": "fa:16:3e:00:00:02", "created_at": "2018-09-01T10:00:01Z"},
+		{"id": "port-1", "network_id": "net-a", "mac_address": "fa:16:3e:00:00:01", "created_at": "2018-09-01T10:00:00Z",
+		 "fixed_ips": [{"subnet_id": "sub-a2", "ip_address": "10.0.1.5"}]}
+	]}`}
+	ports, err := listServerPorts(sender, "server-id")
+	c.Assert(err, jc.ErrorIsNil)
+	sender.CheckCall(c, 0, "SendRequest", "GET", "network", "v2.0", "ports", "device_id=server-id")
+	c.Assert(ports, gc.HasLen, 2)
+	c.Check(ports[0].Id, gc.Equals, "port-1")
+	c.Check(ports[0].MACAddress, gc.Equals, "fa:16:3e:00:00:01")
+	c.Check(ports[0].FixedIPs, gc.HasLen, 1)
+	c.Check(ports[0].FixedIPs[0].SubnetId, gc.Equals, "sub-a2")
+	c.Check(ports[0].FixedIPs[0].IPAddress, gc.Equals, "10.0.1.5")
+	c.Check(ports[1].Id, gc.Equals, "port-2")
+}
+
+func (s *networkingInternalSuite) TestListServerPortsError(c *gc.C) {
+	sender := &fakeRequestSender{}
+	sender.SetErrors(errors.New("boom"))
+	_, err := listServerPorts(sender, "server-id")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *networkingInternalSuite) TestMakeInterfaceInfos(c *gc.C) {
+	var ports []neutronPort
+	err := json.Unmarshal([]byte(`[
+		{"id": "port-1", "network_id": "net-a", "mac_address": "fa:16:3e:00:00:01",
+		 "fixed_ips": [{"subnet_id": "sub-a2", "ip_address": "10.0.1.5"}]},
+		{"id": "port-2", "network_id": "net-c", "mac_address": "fa:16:3e:00:00:02",
+		 "fixed_ips": [{"subnet_id": "sub-c1", "ip_address": "192.168.1.2"}, {"subnet_id": "sub-z", "ip_address": "172.16.0.1"}]},
+		{"id": "port-3", "network_id": "external", "mac_address": "fa:16:3e:00:00:03",
+		 "fixed_ips": [{"subnet_id": "sub-ext", "ip_address": "203.0.113.10"}]}
+	]`), &ports)
+	c.Assert(err, jc.ErrorIsNil)
+	interfaces := makeInterfaceInfos(ports, testNetworks, testSubnets)
+	c.Assert(interfaces, jc.DeepEquals, []network.InterfaceInfo{{
+		DeviceIndex:       0,
+		MACAddress:        "fa:16:3e:00:00:01",
+		CIDR:              "10.0.1.0/24",
+		ProviderId:        "port-1",
+		ProviderSubnetId:  "sub-a2",
+		ProviderNetworkId: "net-a",
+		ProviderSpaceId:   "net-a",
+		AvailabilityZones: []string{"nova"},
+		InterfaceType:     network.EthernetInterface,
+		ConfigType:        network.ConfigDHCP,
+		Address:           network.NewAddress("10.0.1.5"),
+	}, {
+		DeviceIndex:       1,
+		MACAddress:        "fa:16:3e:00:00:02",
+		CIDR:              "192.168.0.0/16",
+		ProviderId:        "port-2",
+		ProviderSubnetId:  "sub-c1",
+		ProviderNetworkId: "net-c",
+		ProviderSpaceId:   "net-c",
+		InterfaceType:     network.EthernetInterface,
+		ConfigType:        network.ConfigDHCP,
+		Address:           network.NewAddress("192.168.1.2"),
+	}})
+}
+
+func (s *networkingInternalSuite) TestNetworkIdsForSpaces(c *gc.C) {
+	networkIds, err := networkIdsForSpaces(
+		map[network.Id][]string{
+			"sub-c1": {"nova"},
+			"sub-a1": {"nova"},
+			"sub-a2": {"nova"},
+		},
+		map[string]network.Id{
+			"db":      "net-b",
+			"website": "net-a",
+		},
+		testSubnets,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(networkIds, jc.DeepEquals, []string{"net-a", "net-b", "net-c"})
+}
+
+func (s *networkingInternalSuite) TestNetworkIdsForSpacesUnknownSubnet(c *gc.C) {
+	_, err := networkIdsForSpaces(
+		map[network.Id][]string{"sub-z": {"nova"}},
+		nil,
+		testSubnets,
+	)
+	c.Assert(err, gc.ErrorMatches, `subnet "sub-z" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+// fakeRequestSender records the requests sent to it, and responds
+// with the configured JSON response.
+type fakeRequestSender struct {
+	testing.Stub
+	response string
+}
+
+func (f *fakeRequestSender) SendRequest(method, svcType, apiVersion, apiCall string, requestData *goosehttp.RequestData) error {
+	var params string
+	if requestData.Params != nil {
+		params = requestData.Params.Encode()
+	}
+	f.MethodCall(f, "SendRequest", method, svcType, apiVersion, apiCall, params)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	if requestData.RespValue == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(f.response), requestData.RespValue)
+}
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/jsonschema"
 	"github.com/juju/loggo"
@@ -501,6 +502,8 @@ func (e *Environ) neutron() *neutron.Client {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.InstanceLifecycle,
+	constraints.SpotMaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -958,6 +961,63 @@ func (*Environ) MaintainInstance(ctx context.ProviderCallContext, args environs.
 	return nil
 }
 
+// spaceNetworkIds returns the IDs of the Neutron networks that an instance
+// must be connected to, to satisfy its spaces constraints and endpoint
+// bindings. Each Neutron network is modelled as a space, so the endpoint
+// bindings refer to networks directly, while the subnets of the space
+// named in the constraints are mapped to the networks containing them.
+func (e *Environ) spaceNetworkIds(args environs.StartInstanceParams) ([]string, error) {
+	if len(args.SubnetsToZones) == 0 && len(args.EndpointBindings) == 0 {
+		return nil, nil
+	}
+	if !e.supportsNeutron() {
+		return nil, errors.NotSupportedf("spaces without Neutron networking")
+	}
+	var subnets []neutron.SubnetV2
+	if len(args.SubnetsToZones) > 0 {
+		var err error
+		subnets, err = e.neutron().ListSubnetsV2()
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to retrieve subnets")
+		}
+	}
+	return networkIdsForSpaces(args.SubnetsToZones, args.EndpointBindings, subnets)
+}
+
+// networkIdsForSpaces returns the sorted IDs of the networks containing
+// the given subnets, and of the networks the endpoints are bound to.
+func networkIdsForSpaces(
+	subnetsToZones map[network.Id][]string,
+	endpointBindings map[string]network.Id,
+	subnets []neutron.SubnetV2,
+) ([]string, error) {
+	subnetNetworkIds := make(map[network.Id]string)
+	for _, subnet := range subnets {
+		subnetNetworkIds[network.Id(subnet.Id)] = subnet.NetworkId
+	}
+	networkIds := set.NewStrings()
+	for subnetId := range subnetsToZones {
+		networkId, ok := subnetNetworkIds[subnetId]
+		if !ok {
+			return nil, errors.NotFoundf("subnet %q", subnetId)
+		}
+		networkIds.Add(networkId)
+	}
+	for _, spaceProviderId := range endpointBindings {
+		networkIds.Add(string(spaceProviderId))
+	}
+	return networkIds.SortedValues(), nil
+}
+
+func hasNetwork(networks []nova.ServerNetworks, networkId string) bool {
+	for _, n := range networks {
+		if n.NetworkId == networkId {
+			return true
+		}
+	}
+	return false
+}
+
 // StartInstance is specified in the InstanceBroker interface.
 func (e *Environ) StartInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) (_ *environs.StartInstanceResult, err error) {
 	if args.AvailabilityZone != "" {
@@ -1023,6 +1083,17 @@ func (e *Environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		logger.Debugf("using network id %q", networkId)
 		networks = append(networks, nova.ServerNetworks{NetworkId: networkId})
 	}
+	spaceNetworkIds, err := e.spaceNetworkIds(args)
+	if err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
+	for _, networkId := range spaceNetworkIds {
+		if hasNetwork(networks, networkId) {
+			continue
+		}
+		logger.Debugf("using network id %q for spaces", networkId)
+		networks = append(networks, nova.ServerNetworks{NetworkId: networkId})
+	}
 
 	machineName := resourceName(
 		e.namespace,
@@ -1774,17 +1845,25 @@ func (e *Environ) NetworkInterfaces(ctx context.ProviderCallContext, instId inst
 
 // SupportsSpaces is specified on environs.Networking.
 func (e *Environ) SupportsSpaces(ctx context.ProviderCallContext) (bool, error) {
-	return false, nil
+	// Spaces are modelled on Neutron networks, so they
+	// are not supported with legacy Nova networking.
+	client := e.client()
+	if !client.IsAuthenticated() {
+		if err := authenticateClient(client); err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	return e.supportsNeutron(), nil
 }
 
 // SupportsSpaceDiscovery is specified on environs.Networking.
 func (e *Environ) SupportsSpaceDiscovery(ctx context.ProviderCallContext) (bool, error) {
-	return false, nil
+	return e.SupportsSpaces(ctx)
 }
 
 // Spaces is specified on environs.Networking.
 func (e *Environ) Spaces(ctx context.ProviderCallContext) ([]network.SpaceInfo, error) {
-	return nil, errors.NotSupportedf("spaces")
+	return e.networking.Spaces()
 }
 
 // SupportsContainerAddresses is specified on environs.Networking.
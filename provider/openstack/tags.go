@@ -0,0 +1,85 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/juju/errors"
+	gooseclient "gopkg.in/goose.v2/client"
+	gooseerrors "gopkg.in/goose.v2/errors"
+	goosehttp "gopkg.in/goose.v2/http"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+)
+
+// UpdateInstanceTags is part of the environs.TagUpdater interface.
+func (e *Environ) UpdateInstanceTags(ctx context.ProviderCallContext, id instance.Id, desired map[string]string) (bool, error) {
+	server, err := e.nova().GetServer(string(id))
+	if gooseerrors.IsNotFound(err) {
+		return false, errors.NotFoundf("instance %q", id)
+	} else if err != nil {
+		return false, errors.Annotatef(err, "getting server %q", id)
+	}
+	set, remove := tags.Diff(server.Metadata, desired)
+	if len(set) == 0 && len(remove) == 0 {
+		return false, nil
+	}
+	if len(set) > 0 {
+		if err := e.nova().SetServerMetadata(string(id), set); err != nil {
+			return false, errors.Annotate(err, "setting server metadata")
+		}
+	}
+	for _, key := range remove {
+		if err := deleteServerMetadata(e.client(), string(id), key); err != nil {
+			return false, errors.Annotate(err, "deleting server metadata")
+		}
+	}
+	return true, nil
+}
+
+// UpdateVolumeTags is part of the environs.TagUpdater interface.
+func (e *Environ) UpdateVolumeTags(ctx context.ProviderCallContext, volumeId string, desired map[string]string) (bool, error) {
+	cinder, err := e.cinderProvider()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	volume, err := cinder.storageAdapter.GetVolume(volumeId)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	set, remove := tags.Diff(volume.Metadata, desired)
+	if len(set) == 0 && len(remove) == 0 {
+		return false, nil
+	}
+	if len(set) > 0 {
+		if _, err := cinder.storageAdapter.SetVolumeMetadata(volumeId, set); err != nil {
+			return false, errors.Annotate(err, "setting volume metadata")
+		}
+	}
+	for _, key := range remove {
+		if err := cinder.storageAdapter.DeleteVolumeMetadata(volumeId, key); err != nil {
+			return false, errors.Annotate(err, "deleting volume metadata")
+		}
+	}
+	return true, nil
+}
+
+// deleteServerMetadata removes the metadata item with the given key
+// from the server with the given ID. The goose Nova client can only
+// add or update metadata items, so the request is made directly.
+var deleteServerMetadata = func(client requestSender, serverId, key string) error {
+	requestData := goosehttp.RequestData{
+		ExpectedStatus: []int{http.StatusNoContent, http.StatusOK},
+	}
+	apiCall := fmt.Sprintf("servers/%s/metadata/%s", serverId, url.PathEscape(key))
+	if err := client.SendRequest(gooseclient.DELETE, "compute", "v2", apiCall, &requestData); err != nil {
+		return errors.Annotatef(err, "deleting metadata %q from server %q", key, serverId)
+	}
+	return nil
+}
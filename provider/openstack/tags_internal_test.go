@@ -0,0 +1,70 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type tagsInternalSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&tagsInternalSuite{})
+
+func (s *tagsInternalSuite) TestDeleteServerMetadata(c *gc.C) {
+	sender := &fakeRequestSender{}
+	err := deleteServerMetadata(sender, "server-id", "cost centre")
+	c.Assert(err, jc.ErrorIsNil)
+	sender.CheckCall(c, 0, "SendRequest", "DELETE", "compute", "v2", "servers/server-id/metadata/cost%20centre", "")
+}
+
+func (s *tagsInternalSuite) TestDeleteServerMetadataError(c *gc.C) {
+	sender := &fakeRequestSender{}
+	sender.SetErrors(errors.New("boom"))
+	err := deleteServerMetadata(sender, "server-id", "team")
+	c.Assert(err, gc.ErrorMatches, `deleting metadata "team" from server "server-id": boom`)
+}
+
+func (s *tagsInternalSuite) TestDeleteVolumeMetadata(c *gc.C) {
+	var method, path, token string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		method, path, token = req.Method, req.URL.Path, req.Header.Get("X-Auth-Token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	endpoint, err := url.Parse(srv.URL + "/v2/tenant-id")
+	c.Assert(err, jc.ErrorIsNil)
+
+	adapter := &openstackStorageAdapter{cinderClient: cinderClient{
+		endpoint: endpoint,
+		token:    func() string { return "token" },
+	}}
+	err = adapter.DeleteVolumeMetadata("vol-0", "team")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(method, gc.Equals, "DELETE")
+	c.Check(path, gc.Equals, "/v2/tenant-id/volumes/vol-0/metadata/team")
+	c.Check(token, gc.Equals, "token")
+}
+
+func (s *tagsInternalSuite) TestDeleteVolumeMetadataNotFound(c *gc.C) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	endpoint, err := url.Parse(srv.URL)
+	c.Assert(err, jc.ErrorIsNil)
+
+	adapter := &openstackStorageAdapter{cinderClient: cinderClient{
+		endpoint: endpoint,
+		token:    func() string { return "token" },
+	}}
+	err = adapter.DeleteVolumeMetadata("vol-0", "team")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -652,6 +652,8 @@ func (o *OracleEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (c
 		constraints.CpuPower,
 		constraints.RootDisk,
 		constraints.VirtType,
+		constraints.InstanceLifecycle,
+		constraints.SpotMaxPrice,
 	}
 
 	// we choose to use the default validator implementation
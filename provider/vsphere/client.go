@@ -20,17 +20,23 @@ type DialFunc func(_ context.Context, _ *url.URL, datacenter string) (Client, er
 
 // Client is an interface for interacting with the vSphere API.
 type Client interface {
+	AttachVirtualDisk(context.Context, *mo.VirtualMachine, string) (string, error)
 	Close(context.Context) error
 	ComputeResources(context.Context) ([]*mo.ComputeResource, error)
+	CreateVirtualDisk(context.Context, string, int64) error
 	CreateVirtualMachine(context.Context, vsphereclient.CreateVirtualMachineParams) (*mo.VirtualMachine, error)
 	Datastores(context.Context) ([]*mo.Datastore, error)
 	DeleteDatastoreFile(context.Context, string) error
+	DeleteVirtualDisk(context.Context, string) error
 	DestroyVMFolder(context.Context, string) error
+	DetachVirtualDisk(context.Context, *mo.VirtualMachine, string) error
 	EnsureVMFolder(context.Context, string) (*object.Folder, error)
+	MoveVirtualDisk(context.Context, string, string) error
 	MoveVMFolderInto(context.Context, string, string) error
 	MoveVMsInto(context.Context, string, ...types.ManagedObjectReference) error
 	RemoveVirtualMachines(context.Context, string) error
 	UpdateVirtualMachineExtraConfig(context.Context, *mo.VirtualMachine, map[string]string) error
+	VirtualDisks(context.Context, string) ([]*types.VmDiskFileInfo, error)
 	VirtualMachines(context.Context, string) ([]*mo.VirtualMachine, error)
 }
 
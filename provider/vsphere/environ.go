@@ -19,8 +19,6 @@ import (
 	"github.com/juju/juju/provider/common"
 )
 
-// Note: This provider/environment does *not* implement storage.
-
 type environ struct {
 	name     string
 	cloud    environs.CloudSpec
@@ -328,6 +328,10 @@ func (c *Client) createImportSpec(
 		return nil, errors.Trace(err)
 	}
 
+	// Expose disk UUIDs to the guest, so that attached volumes can be
+	// identified by their WWN.
+	s.ExtraConfig = append(s.ExtraConfig, &types.OptionValue{Key: "disk.EnableUUID", Value: "TRUE"})
+
 	// Apply metadata. Note that we do not have the ability set create or
 	// apply tags that will show up in vCenter, as that requires a separate
 	// vSphere Automation that we do not have an SDK for.
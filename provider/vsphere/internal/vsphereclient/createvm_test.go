@@ -130,6 +130,7 @@ func (s *clientSuite) TestCreateVirtualMachine(c *gc.C) {
 			ConfigSpec: types.VirtualMachineConfigSpec{
 				Name: "vm-name.tmp",
 				ExtraConfig: []types.BaseOptionValue{
+					&types.OptionValue{Key: "disk.EnableUUID", Value: "TRUE"},
 					&types.OptionValue{Key: "k", Value: "v"},
 				},
 			},
@@ -285,6 +286,7 @@ func (s *clientSuite) TestCreateVirtualMachineMultipleNetworksSpecifiedFirstDefa
 		ConfigSpec: types.VirtualMachineConfigSpec{
 			Name: "vm-name.tmp",
 			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: "disk.EnableUUID", Value: "TRUE"},
 				&types.OptionValue{Key: "k", Value: "v"},
 			},
 			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
@@ -335,6 +337,7 @@ func (s *clientSuite) TestCreateVirtualMachineNetworkSpecifiedDVPortgroup(c *gc.
 		ConfigSpec: types.VirtualMachineConfigSpec{
 			Name: "vm-name.tmp",
 			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: "disk.EnableUUID", Value: "TRUE"},
 				&types.OptionValue{Key: "k", Value: "v"},
 			},
 			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
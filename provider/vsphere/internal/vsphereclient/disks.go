@@ -0,0 +1,270 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package vsphereclient
+
+import (
+	"context"
+	"path"
+
+	"github.com/juju/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// CreateVirtualDisk creates a thin-provisioned virtual disk with the
+// given capacity at the specified datastore path, e.g.
+// "[datastore1] juju-volumes/volume-0.vmdk". The directory containing
+// the disk is created if it does not already exist.
+func (c *Client) CreateVirtualDisk(ctx context.Context, datastorePath string, capacityKB int64) error {
+	var diskPath object.DatastorePath
+	if !diskPath.FromString(datastorePath) {
+		return errors.NotValidf("datastore path %q", datastorePath)
+	}
+	_, datacenter, err := c.finder(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := c.ensureParentDirectory(ctx, datacenter, diskPath); err != nil {
+		return errors.Annotate(err, "creating disk directory")
+	}
+
+	diskManager := object.NewVirtualDiskManager(c.client.Client)
+	task, err := diskManager.CreateVirtualDisk(ctx, datastorePath, datacenter, &types.FileBackedVirtualDiskSpec{
+		VirtualDiskSpec: types.VirtualDiskSpec{
+			AdapterType: string(types.VirtualDiskAdapterTypeLsiLogic),
+			DiskType:    string(types.VirtualDiskTypeThin),
+		},
+		CapacityKb: capacityKB,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := task.WaitForResult(ctx, nil); err != nil {
+		return errors.Annotate(err, "creating disk")
+	}
+	return nil
+}
+
+// MoveVirtualDisk moves the virtual disk at the source datastore path
+// to the destination datastore path. The directory containing the
+// destination is created if it does not already exist.
+func (c *Client) MoveVirtualDisk(ctx context.Context, sourcePath, destPath string) error {
+	var diskPath object.DatastorePath
+	if !diskPath.FromString(destPath) {
+		return errors.NotValidf("datastore path %q", destPath)
+	}
+	_, datacenter, err := c.finder(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.ensureParentDirectory(ctx, datacenter, diskPath); err != nil {
+		return errors.Annotate(err, "creating disk directory")
+	}
+
+	diskManager := object.NewVirtualDiskManager(c.client.Client)
+	task, err := diskManager.MoveVirtualDisk(ctx, sourcePath, datacenter, destPath, datacenter, false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := task.WaitForResult(ctx, nil); err != nil {
+		return errors.Annotate(err, "moving disk")
+	}
+	return nil
+}
+
+// DeleteVirtualDisk deletes the virtual disk at the specified datastore
+// path. It is not an error for the disk not to exist.
+func (c *Client) DeleteVirtualDisk(ctx context.Context, datastorePath string) error {
+	_, datacenter, err := c.finder(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	diskManager := object.NewVirtualDiskManager(c.client.Client)
+	task, err := diskManager.DeleteVirtualDisk(ctx, datastorePath, datacenter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := task.WaitForResult(ctx, nil); err != nil {
+		if types.IsFileNotFound(err) {
+			return nil
+		}
+		return errors.Annotate(err, "deleting disk")
+	}
+	return nil
+}
+
+// VirtualDisks returns the virtual disks in the specified datastore
+// directory, e.g. "[datastore1] juju-volumes". The paths of the returned
+// disks are relative to the directory. If the directory does not exist,
+// no disks are returned.
+func (c *Client) VirtualDisks(ctx context.Context, datastoreDirPath string) ([]*types.VmDiskFileInfo, error) {
+	var dirPath object.DatastorePath
+	if !dirPath.FromString(datastoreDirPath) {
+		return nil, errors.NotValidf("datastore path %q", datastoreDirPath)
+	}
+	finder, _, err := c.finder(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	datastore, err := finder.Datastore(ctx, dirPath.Datastore)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	browser, err := datastore.Browser(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	task, err := browser.SearchDatastore(ctx, datastoreDirPath, &types.HostDatastoreBrowserSearchSpec{
+		MatchPattern: []string{"*.vmdk"},
+		Query: []types.BaseFileQuery{&types.VmDiskFileQuery{
+			Details: &types.VmDiskFileQueryFlags{
+				DiskType:   true,
+				CapacityKb: true,
+			},
+		}},
+		Details: &types.FileQueryFlags{FileType: true},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		if types.IsFileNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Annotate(err, "listing disks")
+	}
+
+	var disks []*types.VmDiskFileInfo
+	results := info.Result.(types.HostDatastoreBrowserSearchResults)
+	for _, file := range results.File {
+		if disk, ok := file.(*types.VmDiskFileInfo); ok {
+			disks = append(disks, disk)
+		}
+	}
+	return disks, nil
+}
+
+// AttachVirtualDisk attaches the virtual disk at the specified datastore
+// path to the VM, on the VM's first SCSI controller with a free slot. The
+// disk's UUID is returned; if the VM has "disk.EnableUUID" set, the guest
+// sees this as the disk's WWN. Attaching a disk that is already attached
+// to the VM has no effect.
+func (c *Client) AttachVirtualDisk(
+	ctx context.Context,
+	vmInfo *mo.VirtualMachine,
+	datastorePath string,
+) (string, error) {
+	_, datacenter, err := c.finder(ctx)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	diskManager := object.NewVirtualDiskManager(c.client.Client)
+	uuid, err := diskManager.QueryVirtualDiskUuid(ctx, datastorePath, datacenter)
+	if err != nil {
+		return "", errors.Annotate(err, "querying disk UUID")
+	}
+
+	vm := object.NewVirtualMachine(c.client.Client, vmInfo.Reference())
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if findVirtualDisk(devices, datastorePath) != nil {
+		return uuid, nil
+	}
+	controller, err := devices.FindSCSIController("")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	disk := &types.VirtualDisk{
+		VirtualDevice: types.VirtualDevice{
+			Backing: &types.VirtualDiskFlatVer2BackingInfo{
+				DiskMode: string(types.VirtualDiskModePersistent),
+				VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+					FileName: datastorePath,
+				},
+			},
+		},
+	}
+	devices.AssignController(disk, controller)
+	if err := vm.AddDevice(ctx, disk); err != nil {
+		return "", errors.Annotate(err, "attaching disk")
+	}
+	return uuid, nil
+}
+
+// DetachVirtualDisk detaches the virtual disk at the specified datastore
+// path from the VM, leaving the disk in place. It is not an error for the
+// disk not to be attached to the VM.
+func (c *Client) DetachVirtualDisk(
+	ctx context.Context,
+	vmInfo *mo.VirtualMachine,
+	datastorePath string,
+) error {
+	vm := object.NewVirtualMachine(c.client.Client, vmInfo.Reference())
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	disk := findVirtualDisk(devices, datastorePath)
+	if disk == nil {
+		return nil
+	}
+	if err := vm.RemoveDevice(ctx, true, disk); err != nil {
+		return errors.Annotate(err, "detaching disk")
+	}
+	return nil
+}
+
+// findVirtualDisk returns the virtual disk in the device list that is
+// backed by the file at the specified datastore path, or nil if there
+// is none.
+func findVirtualDisk(devices object.VirtualDeviceList, datastorePath string) *types.VirtualDisk {
+	for _, dev := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		disk := dev.(*types.VirtualDisk)
+		backing, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo)
+		if !ok {
+			continue
+		}
+		if backing.GetVirtualDeviceFileBackingInfo().FileName == datastorePath {
+			return disk
+		}
+	}
+	return nil
+}
+
+// ensureParentDirectory creates the directory containing the given
+// datastore path, if it does not already exist.
+func (c *Client) ensureParentDirectory(
+	ctx context.Context,
+	datacenter *object.Datacenter,
+	datastorePath object.DatastorePath,
+) error {
+	dirPath := object.DatastorePath{
+		Datastore: datastorePath.Datastore,
+		Path:      path.Dir(datastorePath.Path),
+	}
+	fileManager := object.NewFileManager(c.client.Client)
+	if err := fileManager.MakeDirectory(ctx, dirPath.String(), datacenter, true); err != nil {
+		if !isFileAlreadyExists(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func isFileAlreadyExists(err error) bool {
+	if soap.IsSoapFault(err) {
+		switch soap.ToSoapFault(err).VimFault().(type) {
+		case types.FileAlreadyExists:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package vsphereclient
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+	gc "gopkg.in/check.v1"
+)
+
+func (s *clientSuite) TestCreateVirtualDisk(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	err := client.CreateVirtualDisk(context.Background(), "[datastore1] juju-volumes/volume-0.vmdk", 1024)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.roundTripper.CheckCalls(c, []testing.StubCall{
+		retrievePropertiesStubCall("FakeRootFolder"),
+		retrievePropertiesStubCall("FakeRootFolder"),
+		{"MakeDirectory", []interface{}{"[datastore1] juju-volumes"}},
+		{"CreateVirtualDisk", []interface{}{
+			"[datastore1] juju-volumes/volume-0.vmdk",
+			&types.FileBackedVirtualDiskSpec{
+				VirtualDiskSpec: types.VirtualDiskSpec{
+					AdapterType: "lsiLogic",
+					DiskType:    "thin",
+				},
+				CapacityKb: 1024,
+			},
+		}},
+		{"CreatePropertyCollector", nil},
+		{"CreateFilter", nil},
+		{"WaitForUpdatesEx", nil},
+	})
+}
+
+func (s *clientSuite) TestCreateVirtualDiskInvalidPath(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	err := client.CreateVirtualDisk(context.Background(), "volume-0.vmdk", 1024)
+	c.Assert(err, gc.ErrorMatches, `datastore path "volume-0.vmdk" not valid`)
+	s.roundTripper.CheckNoCalls(c)
+}
+
+func (s *clientSuite) TestMoveVirtualDisk(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	err := client.MoveVirtualDisk(
+		context.Background(),
+		"[datastore1] juju-volumes/model/volume-0.vmdk",
+		"[datastore1] juju-volumes-released/model/volume-0.vmdk",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.roundTripper.CheckCalls(c, []testing.StubCall{
+		retrievePropertiesStubCall("FakeRootFolder"),
+		retrievePropertiesStubCall("FakeRootFolder"),
+		{"MakeDirectory", []interface{}{"[datastore1] juju-volumes-released/model"}},
+		{"MoveVirtualDisk", []interface{}{
+			"[datastore1] juju-volumes/model/volume-0.vmdk",
+			"[datastore1] juju-volumes-released/model/volume-0.vmdk",
+		}},
+		{"CreatePropertyCollector", nil},
+		{"CreateFilter", nil},
+		{"WaitForUpdatesEx", nil},
+	})
+}
+
+func (s *clientSuite) TestDeleteVirtualDisk(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	err := client.DeleteVirtualDisk(context.Background(), "[datastore1] juju-volumes/volume-0.vmdk")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.roundTripper.CheckCalls(c, []testing.StubCall{
+		retrievePropertiesStubCall("FakeRootFolder"),
+		retrievePropertiesStubCall("FakeRootFolder"),
+		{"DeleteVirtualDisk", []interface{}{"[datastore1] juju-volumes/volume-0.vmdk"}},
+		{"CreatePropertyCollector", nil},
+		{"CreateFilter", nil},
+		{"WaitForUpdatesEx", nil},
+	})
+}
+
+func (s *clientSuite) TestDeleteVirtualDiskNotFound(c *gc.C) {
+	s.roundTripper.taskError[deleteVirtualDiskTask] = &types.LocalizedMethodFault{
+		Fault: &types.FileNotFound{},
+	}
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	err := client.DeleteVirtualDisk(context.Background(), "[datastore1] juju-volumes/volume-0.vmdk")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestVirtualDisks(c *gc.C) {
+	s.roundTripper.taskResult[searchDatastoreTask] = types.HostDatastoreBrowserSearchResults{
+		File: []types.BaseFileInfo{
+			&types.VmDiskFileInfo{
+				FileInfo:   types.FileInfo{Path: "volume-0.vmdk"},
+				CapacityKb: 1024,
+			},
+			&types.FileInfo{Path: "notes.txt"},
+		},
+	}
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	disks, err := client.VirtualDisks(context.Background(), "[datastore2] juju-volumes")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(disks, gc.HasLen, 1)
+	c.Check(disks[0].Path, gc.Equals, "volume-0.vmdk")
+	c.Check(disks[0].CapacityKb, gc.Equals, int64(1024))
+}
+
+func (s *clientSuite) TestVirtualDisksNoDirectory(c *gc.C) {
+	s.roundTripper.taskError[searchDatastoreTask] = &types.LocalizedMethodFault{
+		Fault: &types.FileNotFound{},
+	}
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	disks, err := client.VirtualDisks(context.Background(), "[datastore2] juju-volumes")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(disks, gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestAttachVirtualDisk(c *gc.C) {
+	s.addSCSIController("FakeVm0")
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	uuid, err := client.AttachVirtualDisk(context.Background(), fakeVM("FakeVm0"), "[datastore1] juju-volumes/volume-0.vmdk")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuid, gc.Equals, "60 00 C2 91 2b 3c 4d 5e-6f 70 81 92 a3 b4 c5 d6")
+
+	s.roundTripper.CheckCalls(c, []testing.StubCall{
+		retrievePropertiesStubCall("FakeRootFolder"),
+		retrievePropertiesStubCall("FakeRootFolder"),
+		{"QueryVirtualDiskUuid", []interface{}{"[datastore1] juju-volumes/volume-0.vmdk"}},
+		retrievePropertiesStubCall("FakeVm0"),
+		{"ReconfigVM_Task", nil},
+		{"CreatePropertyCollector", nil},
+		{"CreateFilter", nil},
+		{"WaitForUpdatesEx", nil},
+	})
+}
+
+func (s *clientSuite) TestAttachVirtualDiskAlreadyAttached(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.AttachVirtualDisk(context.Background(), fakeVM("FakeVm0"), "disk.vmdk")
+	c.Assert(err, jc.ErrorIsNil)
+	s.roundTripper.CheckCallNames(c,
+		"RetrieveProperties",
+		"RetrieveProperties",
+		"QueryVirtualDiskUuid",
+		"RetrieveProperties",
+	)
+}
+
+func (s *clientSuite) TestAttachVirtualDiskNoController(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.AttachVirtualDisk(context.Background(), fakeVM("FakeVm0"), "[datastore1] juju-volumes/volume-0.vmdk")
+	c.Assert(err, gc.ErrorMatches, "no available SCSI controller")
+}
+
+func (s *clientSuite) TestDetachVirtualDisk(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	err := client.DetachVirtualDisk(context.Background(), fakeVM("FakeVm0"), "disk.vmdk")
+	c.Assert(err, jc.ErrorIsNil)
+	s.roundTripper.CheckCallNames(c,
+		"RetrieveProperties",
+		"ReconfigVM_Task",
+		"CreatePropertyCollector",
+		"CreateFilter",
+		"WaitForUpdatesEx",
+	)
+}
+
+func (s *clientSuite) TestDetachVirtualDiskNotAttached(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	err := client.DetachVirtualDisk(context.Background(), fakeVM("FakeVm0"), "[datastore1] juju-volumes/volume-0.vmdk")
+	c.Assert(err, jc.ErrorIsNil)
+	s.roundTripper.CheckCallNames(c, "RetrieveProperties")
+}
+
+// addSCSIController adds a SCSI controller to the devices of the named
+// fake VM.
+func (s *clientSuite) addSCSIController(vm string) {
+	props := s.roundTripper.contents[vm][0].PropSet
+	for i, prop := range props {
+		if prop.Name != "config.hardware.device" {
+			continue
+		}
+		devices := prop.Val.([]types.BaseVirtualDevice)
+		props[i].Val = append(devices, &types.VirtualLsiLogicController{
+			VirtualSCSIController: types.VirtualSCSIController{
+				VirtualController: types.VirtualController{
+					VirtualDevice: types.VirtualDevice{Key: 1000},
+				},
+				ScsiCtlrUnitNumber: 7,
+			},
+		})
+	}
+}
+
+func fakeVM(ref string) *mo.VirtualMachine {
+	var vm mo.VirtualMachine
+	vm.Self = types.ManagedObjectReference{
+		Type:  "VirtualMachine",
+		Value: ref,
+	}
+	return &vm
+}
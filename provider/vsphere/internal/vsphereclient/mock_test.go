@@ -62,6 +62,18 @@ var (
 		Type:  "Task",
 		Value: "ExtendVirtualDisk",
 	}
+	createVirtualDiskTask = types.ManagedObjectReference{
+		Type:  "Task",
+		Value: "CreateVirtualDisk",
+	}
+	deleteVirtualDiskTask = types.ManagedObjectReference{
+		Type:  "Task",
+		Value: "DeleteVirtualDisk",
+	}
+	moveVirtualDiskTask = types.ManagedObjectReference{
+		Type:  "Task",
+		Value: "MoveVirtualDisk",
+	}
 )
 
 type mockRoundTripper struct {
@@ -156,6 +168,22 @@ func (r *mockRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault
 		req := req.(*methods.ExtendVirtualDisk_TaskBody).Req
 		r.MethodCall(r, "ExtendVirtualDisk", req.Name, req.NewCapacityKb)
 		res.Res = &types.ExtendVirtualDisk_TaskResponse{extendVirtualDiskTask}
+	case *methods.CreateVirtualDisk_TaskBody:
+		req := req.(*methods.CreateVirtualDisk_TaskBody).Req
+		r.MethodCall(r, "CreateVirtualDisk", req.Name, req.Spec)
+		res.Res = &types.CreateVirtualDisk_TaskResponse{createVirtualDiskTask}
+	case *methods.DeleteVirtualDisk_TaskBody:
+		req := req.(*methods.DeleteVirtualDisk_TaskBody).Req
+		r.MethodCall(r, "DeleteVirtualDisk", req.Name)
+		res.Res = &types.DeleteVirtualDisk_TaskResponse{deleteVirtualDiskTask}
+	case *methods.MoveVirtualDisk_TaskBody:
+		req := req.(*methods.MoveVirtualDisk_TaskBody).Req
+		r.MethodCall(r, "MoveVirtualDisk", req.SourceName, req.DestName)
+		res.Res = &types.MoveVirtualDisk_TaskResponse{moveVirtualDiskTask}
+	case *methods.QueryVirtualDiskUuidBody:
+		req := req.(*methods.QueryVirtualDiskUuidBody).Req
+		r.MethodCall(r, "QueryVirtualDiskUuid", req.Name)
+		res.Res = &types.QueryVirtualDiskUuidResponse{"60 00 C2 91 2b 3c 4d 5e-6f 70 81 92 a3 b4 c5 d6"}
 	case *methods.CreatePropertyCollectorBody:
 		r.MethodCall(r, "CreatePropertyCollector")
 		uuid := utils.MustNewUUID().String()
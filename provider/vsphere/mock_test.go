@@ -38,6 +38,15 @@ type mockClient struct {
 	virtualMachines       []*mo.VirtualMachine
 	datastores            []*mo.Datastore
 	vmFolder              *object.Folder
+	virtualDisks          map[string][]*types.VmDiskFileInfo
+	virtualDiskUUID       string
+}
+
+func (c *mockClient) AttachVirtualDisk(ctx context.Context, vm *mo.VirtualMachine, path string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "AttachVirtualDisk", ctx, vm, path)
+	return c.virtualDiskUUID, c.NextErr()
 }
 
 func (c *mockClient) Close(ctx context.Context) error {
@@ -54,6 +63,13 @@ func (c *mockClient) ComputeResources(ctx context.Context) ([]*mo.ComputeResourc
 	return c.computeResources, c.NextErr()
 }
 
+func (c *mockClient) CreateVirtualDisk(ctx context.Context, path string, capacityKB int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "CreateVirtualDisk", ctx, path, capacityKB)
+	return c.NextErr()
+}
+
 func (c *mockClient) CreateVirtualMachine(ctx context.Context, args vsphereclient.CreateVirtualMachineParams) (*mo.VirtualMachine, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -75,6 +91,13 @@ func (c *mockClient) DeleteDatastoreFile(ctx context.Context, path string) error
 	return c.NextErr()
 }
 
+func (c *mockClient) DeleteVirtualDisk(ctx context.Context, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DeleteVirtualDisk", ctx, path)
+	return c.NextErr()
+}
+
 func (c *mockClient) DestroyVMFolder(ctx context.Context, path string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -82,6 +105,13 @@ func (c *mockClient) DestroyVMFolder(ctx context.Context, path string) error {
 	return c.NextErr()
 }
 
+func (c *mockClient) DetachVirtualDisk(ctx context.Context, vm *mo.VirtualMachine, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DetachVirtualDisk", ctx, vm, path)
+	return c.NextErr()
+}
+
 func (c *mockClient) EnsureVMFolder(ctx context.Context, path string) (*object.Folder, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -89,6 +119,13 @@ func (c *mockClient) EnsureVMFolder(ctx context.Context, path string) (*object.F
 	return c.vmFolder, c.NextErr()
 }
 
+func (c *mockClient) MoveVirtualDisk(ctx context.Context, source, dest string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "MoveVirtualDisk", ctx, source, dest)
+	return c.NextErr()
+}
+
 func (c *mockClient) MoveVMFolderInto(ctx context.Context, parent string, child string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -117,6 +154,13 @@ func (c *mockClient) UpdateVirtualMachineExtraConfig(ctx context.Context, vm *mo
 	return c.NextErr()
 }
 
+func (c *mockClient) VirtualDisks(ctx context.Context, path string) ([]*types.VmDiskFileInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "VirtualDisks", ctx, path)
+	return c.virtualDisks[path], c.NextErr()
+}
+
 func (c *mockClient) VirtualMachines(ctx context.Context, path string) ([]*mo.VirtualMachine, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
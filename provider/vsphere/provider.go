@@ -139,7 +139,24 @@ func (p *environProvider) PrepareConfig(args environs.PrepareConfigParams) (*con
 	if err := validateCloudSpec(args.Cloud); err != nil {
 		return nil, errors.Annotate(err, "validating cloud spec")
 	}
-	return args.Config, nil
+	return configWithDefaults(args.Config)
+}
+
+// UpgradeConfig is specified in the ModelConfigUpgrader interface.
+func (*environProvider) UpgradeConfig(cfg *config.Config) (*config.Config, error) {
+	return configWithDefaults(cfg)
+}
+
+func configWithDefaults(cfg *config.Config) (*config.Config, error) {
+	defaults := make(map[string]interface{})
+	if _, ok := cfg.StorageDefaultBlockSource(); !ok {
+		// Set the default block source.
+		defaults[config.StorageDefaultBlockSourceKey] = string(vsphereStorageProviderType)
+	}
+	if len(defaults) == 0 {
+		return cfg, nil
+	}
+	return cfg.Apply(defaults)
 }
 
 // Validate implements environs.EnvironProvider.
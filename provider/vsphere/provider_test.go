@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/testing"
 )
 
 type providerSuite struct {
@@ -75,6 +76,34 @@ func (s *providerSuite) TestPrepareConfig(c *gc.C) {
 	})
 	c.Check(err, jc.ErrorIsNil)
 	c.Check(cfg, gc.NotNil)
+	source, ok := cfg.StorageDefaultBlockSource()
+	c.Check(ok, jc.IsTrue)
+	c.Check(source, gc.Equals, "vsphere")
+}
+
+func (s *providerSuite) TestPrepareConfigKeepsDefaultBlockSource(c *gc.C) {
+	cfg, err := s.provider.PrepareConfig(environs.PrepareConfigParams{
+		Config: fakeConfig(c, testing.Attrs{"storage-default-block-source": "loop"}),
+		Cloud:  fakeCloudSpec(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	source, _ := cfg.StorageDefaultBlockSource()
+	c.Assert(source, gc.Equals, "loop")
+}
+
+func (s *providerSuite) TestUpgradeConfig(c *gc.C) {
+	c.Assert(s.provider, gc.Implements, new(environs.ModelConfigUpgrader))
+	upgrader := s.provider.(environs.ModelConfigUpgrader)
+
+	cfg := fakeConfig(c)
+	_, ok := cfg.StorageDefaultBlockSource()
+	c.Assert(ok, jc.IsFalse)
+
+	cfg, err := upgrader.UpgradeConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	source, ok := cfg.StorageDefaultBlockSource()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(source, gc.Equals, "vsphere")
 }
 
 func (s *providerSuite) TestValidate(c *gc.C) {
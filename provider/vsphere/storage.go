@@ -9,12 +9,85 @@ import (
 	"github.com/juju/juju/storage"
 )
 
+const (
+	vsphereStorageProviderType = storage.ProviderType("vsphere")
+
+	// datastoreAttribute is the storage pool attribute naming the
+	// datastore in which volumes are created. If it is not specified,
+	// the model's "datastore" config is used; failing that, the first
+	// accessible datastore is used.
+	datastoreAttribute = "datastore"
+)
+
 // StorageProviderTypes implements storage.ProviderRegistry.
 func (*environ) StorageProviderTypes() ([]storage.ProviderType, error) {
-	return nil, nil
+	return []storage.ProviderType{vsphereStorageProviderType}, nil
 }
 
 // StorageProvider implements storage.ProviderRegistry.
-func (*environ) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+func (env *environ) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == vsphereStorageProviderType {
+		return &storageProvider{env}, nil
+	}
 	return nil, errors.NotFoundf("storage provider %q", t)
 }
+
+// storageProvider implements storage.Provider, providing volumes
+// backed by VMDKs.
+type storageProvider struct {
+	env *environ
+}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+// VolumeSource is part of the storage.Provider interface.
+func (p *storageProvider) VolumeSource(cfg *storage.Config) (storage.VolumeSource, error) {
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	datastore, _ := cfg.ValueString(datastoreAttribute)
+	return &volumeSource{
+		env:       p.env,
+		datastore: datastore,
+	}, nil
+}
+
+// FilesystemSource is part of the storage.Provider interface.
+func (p *storageProvider) FilesystemSource(cfg *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is part of the storage.Provider interface.
+func (p *storageProvider) Supports(kind storage.StorageKind) bool {
+	return kind == storage.StorageKindBlock
+}
+
+// Scope is part of the storage.Provider interface.
+func (p *storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is part of the storage.Provider interface.
+func (p *storageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is part of the storage.Provider interface.
+func (p *storageProvider) Releasable() bool {
+	return true
+}
+
+// DefaultPools is part of the storage.Provider interface.
+func (p *storageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// ValidateConfig is part of the storage.Provider interface.
+func (p *storageProvider) ValidateConfig(cfg *storage.Config) error {
+	if v, ok := cfg.Attrs()[datastoreAttribute]; ok {
+		if _, ok := v.(string); !ok {
+			return errors.Errorf("expected string for %q, got %T", datastoreAttribute, v)
+		}
+	}
+	return nil
+}
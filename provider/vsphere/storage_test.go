@@ -0,0 +1,354 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package vsphere_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	volumesDir         = "juju-volumes/2d02eeac-9dbb-11e4-89d3-123b93f75cba"
+	releasedVolumesDir = "juju-volumes-released/2d02eeac-9dbb-11e4-89d3-123b93f75cba"
+)
+
+type storageSuite struct {
+	EnvironFixture
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) storageProvider(c *gc.C) storage.Provider {
+	providerTypes, err := s.env.StorageProviderTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providerTypes, jc.DeepEquals, []storage.ProviderType{"vsphere"})
+	p, err := s.env.StorageProvider("vsphere")
+	c.Assert(err, jc.ErrorIsNil)
+	return p
+}
+
+func (s *storageSuite) volumeSource(c *gc.C, attrs map[string]interface{}) storage.VolumeSource {
+	cfg, err := storage.NewConfig("vsphere", "vsphere", attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := s.storageProvider(c).VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+// checkClientCall checks the arguments of the client call at
+// the given index, skipping the leading context argument.
+func (s *storageSuite) checkClientCall(c *gc.C, index int, name string, args ...interface{}) {
+	call := s.client.Calls()[index]
+	c.Assert(call.FuncName, gc.Equals, name)
+	c.Assert(call.Args, gc.HasLen, len(args)+1)
+	c.Assert(call.Args[0], gc.Implements, new(context.Context))
+	c.Assert(call.Args[1:], jc.DeepEquals, args)
+}
+
+func (s *storageSuite) TestStorageProviderUnknown(c *gc.C) {
+	_, err := s.env.StorageProvider("ebs")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *storageSuite) TestStorageProviderProperties(c *gc.C) {
+	p := s.storageProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+	c.Assert(p.Releasable(), jc.IsTrue)
+	c.Assert(p.DefaultPools(), gc.HasLen, 0)
+
+	_, err := p.FilesystemSource(nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageSuite) TestValidateConfig(c *gc.C) {
+	p := s.storageProvider(c)
+	cfg, err := storage.NewConfig("foo", "vsphere", map[string]interface{}{
+		"datastore": "datastore1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("foo", "vsphere", map[string]interface{}{
+		"datastore": 123,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `expected string for "datastore", got int`)
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{"datastore": "ds1"})
+	s.client.SetErrors(nil, errors.New("boom"))
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}, {
+		Tag:  names.NewVolumeTag("1"),
+		Size: 2048,
+	}, {
+		Tag: names.NewVolumeTag("2"),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		Tag: names.NewVolumeTag("0"),
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId:   "[ds1] " + volumesDir + "/volume-0.vmdk",
+			Size:       1024,
+			Persistent: true,
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, "creating volume 1: boom")
+	c.Assert(results[2].Error, gc.ErrorMatches, "creating volume 2: volume size 0 not valid")
+
+	s.client.CheckCallNames(c, "CreateVirtualDisk", "CreateVirtualDisk", "Close")
+	s.checkClientCall(c, 0, "CreateVirtualDisk", "[ds1] "+volumesDir+"/volume-0.vmdk", int64(1024*1024))
+	s.checkClientCall(c, 1, "CreateVirtualDisk", "[ds1] "+volumesDir+"/volume-1.vmdk", int64(2048*1024))
+}
+
+func (s *storageSuite) TestCreateVolumesFirstAccessibleDatastore(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{{
+		ManagedEntity: mo.ManagedEntity{Name: "foo"},
+	}, {
+		ManagedEntity: mo.ManagedEntity{Name: "bar"},
+		Summary:       types.DatastoreSummary{Accessible: true},
+	}}
+	source := s.volumeSource(c, nil)
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "[bar] "+volumesDir+"/volume-0.vmdk")
+	s.client.CheckCallNames(c, "Datastores", "CreateVirtualDisk", "Close")
+}
+
+func (s *storageSuite) TestCreateVolumesDefaultBlockSource(c *gc.C) {
+	// Storage added without naming a pool uses the model's default
+	// block source, which is the vsphere provider with no attributes.
+	cfg, err := s.provider.PrepareConfig(environs.PrepareConfigParams{
+		Config: fakeConfig(c),
+		Cloud:  fakeCloudSpec(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	poolName, ok := cfg.StorageDefaultBlockSource()
+	c.Assert(ok, jc.IsTrue)
+	p, err := s.env.StorageProvider(storage.ProviderType(poolName))
+	c.Assert(err, jc.ErrorIsNil)
+	poolCfg, err := storage.NewConfig(poolName, storage.ProviderType(poolName), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(poolCfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.datastores = []*mo.Datastore{{
+		ManagedEntity: mo.ManagedEntity{Name: "foo"},
+		Summary:       types.DatastoreSummary{Accessible: true},
+	}}
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "[foo] "+volumesDir+"/volume-0.vmdk")
+}
+
+func (s *storageSuite) TestListVolumes(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{{
+		ManagedEntity: mo.ManagedEntity{Name: "foo"},
+	}, {
+		ManagedEntity: mo.ManagedEntity{Name: "bar"},
+		Summary:       types.DatastoreSummary{Accessible: true},
+	}, {
+		ManagedEntity: mo.ManagedEntity{Name: "baz"},
+		Summary:       types.DatastoreSummary{Accessible: true},
+	}}
+	s.client.virtualDisks = map[string][]*types.VmDiskFileInfo{
+		"[bar] " + volumesDir: {
+			{FileInfo: types.FileInfo{Path: "volume-0.vmdk"}},
+			{FileInfo: types.FileInfo{Path: "volume-1.vmdk"}},
+		},
+		"[baz] " + volumesDir: {
+			{FileInfo: types.FileInfo{Path: "volume-2.vmdk"}},
+		},
+	}
+	source := s.volumeSource(c, nil)
+	volumeIds, err := source.ListVolumes(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{
+		"[bar] " + volumesDir + "/volume-0.vmdk",
+		"[bar] " + volumesDir + "/volume-1.vmdk",
+		"[baz] " + volumesDir + "/volume-2.vmdk",
+	})
+	s.client.CheckCallNames(c, "Datastores", "VirtualDisks", "VirtualDisks", "Close")
+}
+
+func (s *storageSuite) TestListVolumesPoolDatastore(c *gc.C) {
+	s.client.virtualDisks = map[string][]*types.VmDiskFileInfo{
+		"[ds1] " + volumesDir: {
+			{FileInfo: types.FileInfo{Path: "volume-0.vmdk"}},
+		},
+	}
+	source := s.volumeSource(c, map[string]interface{}{"datastore": "ds1"})
+	volumeIds, err := source.ListVolumes(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"[ds1] " + volumesDir + "/volume-0.vmdk"})
+	s.client.CheckCallNames(c, "VirtualDisks", "Close")
+	s.checkClientCall(c, 0, "VirtualDisks", "[ds1] "+volumesDir)
+}
+
+func (s *storageSuite) TestDescribeVolumes(c *gc.C) {
+	s.client.virtualDisks = map[string][]*types.VmDiskFileInfo{
+		"[ds1] " + volumesDir: {
+			{FileInfo: types.FileInfo{Path: "volume-0.vmdk"}, CapacityKb: 1024 * 1024},
+			{FileInfo: types.FileInfo{Path: "volume-1.vmdk"}, CapacityKb: 2048 * 1024},
+		},
+	}
+	source := s.volumeSource(c, nil)
+	results, err := source.DescribeVolumes(s.callCtx, []string{
+		"[ds1] " + volumesDir + "/volume-1.vmdk",
+		"[ds1] " + volumesDir + "/volume-2.vmdk",
+		"[ds1] " + volumesDir + "/volume-0.vmdk",
+		"volume-0.vmdk",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 4)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId:   "[ds1] " + volumesDir + "/volume-1.vmdk",
+		Size:       2048,
+		Persistent: true,
+	})
+	c.Assert(results[1].Error, jc.Satisfies, errors.IsNotFound)
+	c.Assert(results[2].Error, jc.ErrorIsNil)
+	c.Assert(results[2].VolumeInfo.Size, gc.Equals, uint64(1024))
+	c.Assert(results[3].Error, gc.ErrorMatches, `volume ID "volume-0.vmdk" not valid`)
+
+	// The directory listing is only requested once.
+	s.client.CheckCallNames(c, "VirtualDisks", "Close")
+}
+
+func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	s.client.SetErrors(nil, errors.New("boom"))
+	results, err := source.DestroyVolumes(s.callCtx, []string{
+		"[ds1] " + volumesDir + "/volume-0.vmdk",
+		"[ds1] " + volumesDir + "/volume-1.vmdk",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.ErrorIsNil)
+	c.Assert(results[1], gc.ErrorMatches, `destroying volume "\[ds1\] .*/volume-1.vmdk": boom`)
+	s.client.CheckCallNames(c, "DeleteVirtualDisk", "DeleteVirtualDisk", "Close")
+	s.checkClientCall(c, 0, "DeleteVirtualDisk", "[ds1] "+volumesDir+"/volume-0.vmdk")
+}
+
+func (s *storageSuite) TestReleaseVolumes(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	results, err := source.ReleaseVolumes(s.callCtx, []string{
+		"[ds1] " + volumesDir + "/volume-0.vmdk",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0], jc.ErrorIsNil)
+	s.client.CheckCallNames(c, "MoveVirtualDisk", "Close")
+	s.checkClientCall(c, 0, "MoveVirtualDisk",
+		"[ds1] "+volumesDir+"/volume-0.vmdk",
+		"[ds1] "+releasedVolumesDir+"/volume-0.vmdk",
+	)
+}
+
+func (s *storageSuite) TestAttachVolumes(c *gc.C) {
+	vm := buildVM("inst-0").vm()
+	s.client.virtualMachines = []*mo.VirtualMachine{vm}
+	s.client.virtualDiskUUID = "60 00 C2 91 2b 3c 4d 5e-6f 70 81 92 a3 b4 c5 d6"
+	source := s.volumeSource(c, nil)
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-0",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "[ds1] " + volumesDir + "/volume-0.vmdk",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		Volume:  names.NewVolumeTag("0"),
+		Machine: names.NewMachineTag("0"),
+		VolumeAttachmentInfo: storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-id/wwn-0x6000c2912b3c4d5e6f708192a3b4c5d6",
+		},
+	})
+
+	s.client.CheckCallNames(c, "VirtualMachines", "AttachVirtualDisk", "Close")
+	s.checkClientCall(c, 0, "VirtualMachines",
+		`Juju Controller (*)/Model "testmodel" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)/inst-0`,
+	)
+	s.checkClientCall(c, 1, "AttachVirtualDisk", vm, "[ds1] "+volumesDir+"/volume-0.vmdk")
+}
+
+func (s *storageSuite) TestAttachVolumesInstanceNotFound(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	results, err := source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-0",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "[ds1] " + volumesDir + "/volume-0.vmdk",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `attaching volume 0 to machine 0: instance "inst-0" not found`)
+	s.client.CheckCallNames(c, "VirtualMachines", "Close")
+}
+
+func (s *storageSuite) TestDetachVolumes(c *gc.C) {
+	vm := buildVM("inst-0").vm()
+	s.client.virtualMachines = []*mo.VirtualMachine{vm}
+	source := s.volumeSource(c, nil)
+	results, err := source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-0",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "[ds1] " + volumesDir + "/volume-0.vmdk",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+	s.client.CheckCallNames(c, "VirtualMachines", "DetachVirtualDisk", "Close")
+	s.checkClientCall(c, 1, "DetachVirtualDisk", vm, "[ds1] "+volumesDir+"/volume-0.vmdk")
+}
+
+func (s *storageSuite) TestDetachVolumesInstanceNotFound(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	results, err := source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-0",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "[ds1] " + volumesDir + "/volume-0.vmdk",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+	s.client.CheckCallNames(c, "VirtualMachines", "Close")
+}
@@ -0,0 +1,372 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package vsphere
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
+
+// volumeSource implements storage.VolumeSource. Volumes are VMDKs,
+// stored in a per-model directory in a datastore. The ID of a volume
+// is the datastore path of its VMDK, e.g.
+// "[datastore1] juju-volumes/<model-uuid>/volume-0.vmdk".
+type volumeSource struct {
+	env *environ
+
+	// datastore is the name of the datastore in which to create
+	// volumes. If empty, the model's datastore is used.
+	datastore string
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// volumeDirectoryName returns the name of the datastore directory in
+// which the model's volumes are stored.
+func volumeDirectoryName(modelUUID string) string {
+	return fmt.Sprintf("juju-volumes/%s", modelUUID)
+}
+
+// releasedVolumeDirectoryName returns the name of the datastore
+// directory to which the model's volumes are moved when released.
+// Released volumes are no longer listed by the volume source, and
+// so are not destroyed along with the model.
+func releasedVolumeDirectoryName(modelUUID string) string {
+	return fmt.Sprintf("juju-volumes-released/%s", modelUUID)
+}
+
+// CreateVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) CreateVolumes(ctx context.ProviderCallContext, params []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(params))
+	err := s.env.withSession(func(env *sessionEnviron) error {
+		datastore, err := s.selectDatastore(env)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		dirPath := object.DatastorePath{
+			Datastore: datastore,
+			Path:      volumeDirectoryName(env.Config().UUID()),
+		}
+		for i, p := range params {
+			volume, err := s.createVolume(env, dirPath, p)
+			if err != nil {
+				results[i].Error = errors.Annotatef(err, "creating volume %s", p.Tag.Id())
+				continue
+			}
+			results[i].Volume = volume
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+func (s *volumeSource) createVolume(
+	env *sessionEnviron,
+	dirPath object.DatastorePath,
+	p storage.VolumeParams,
+) (*storage.Volume, error) {
+	if err := s.ValidateVolumeParams(p); err != nil {
+		return nil, errors.Trace(err)
+	}
+	diskPath := object.DatastorePath{
+		Datastore: dirPath.Datastore,
+		Path:      path.Join(dirPath.Path, p.Tag.String()+".vmdk"),
+	}
+	volumeId := diskPath.String()
+	if err := env.client.CreateVirtualDisk(env.ctx, volumeId, int64(p.Size)*1024); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.Volume{
+		Tag: p.Tag,
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId:   volumeId,
+			Size:       p.Size,
+			Persistent: true,
+		},
+	}, nil
+}
+
+// selectDatastore returns the name of the datastore in which to create
+// volumes. If neither the storage pool nor the model specify one, the
+// first accessible datastore is used.
+func (s *volumeSource) selectDatastore(env *sessionEnviron) (string, error) {
+	if s.datastore != "" {
+		return s.datastore, nil
+	}
+	if datastore := env.ecfg.datastore(); datastore != "" {
+		return datastore, nil
+	}
+	datastores, err := env.client.Datastores(env.ctx)
+	if err != nil {
+		return "", errors.Annotate(err, "listing datastores")
+	}
+	for _, ds := range datastores {
+		if ds.Summary.Accessible {
+			return ds.Name, nil
+		}
+	}
+	return "", errors.New("could not find an accessible datastore")
+}
+
+// ListVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
+	var volumeIds []string
+	err := s.env.withSession(func(env *sessionEnviron) error {
+		// If the pool does not specify a datastore, the model's
+		// volumes may be spread across any of the datastores.
+		var datastores []string
+		if s.datastore != "" {
+			datastores = []string{s.datastore}
+		} else {
+			all, err := env.client.Datastores(env.ctx)
+			if err != nil {
+				return errors.Annotate(err, "listing datastores")
+			}
+			for _, ds := range all {
+				if ds.Summary.Accessible {
+					datastores = append(datastores, ds.Name)
+				}
+			}
+		}
+		for _, datastore := range datastores {
+			dirPath := object.DatastorePath{
+				Datastore: datastore,
+				Path:      volumeDirectoryName(env.Config().UUID()),
+			}
+			disks, err := env.client.VirtualDisks(env.ctx, dirPath.String())
+			if err != nil {
+				return errors.Annotatef(err, "listing volumes in datastore %q", datastore)
+			}
+			for _, disk := range disks {
+				diskPath := object.DatastorePath{
+					Datastore: datastore,
+					Path:      path.Join(dirPath.Path, disk.Path),
+				}
+				volumeIds = append(volumeIds, diskPath.String())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) DescribeVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	err := s.env.withSession(func(env *sessionEnviron) error {
+		// Volumes are usually in the same directory, so we
+		// cache the directory listings to save API calls.
+		dirDisks := make(map[string][]*types.VmDiskFileInfo)
+		for i, volumeId := range volumeIds {
+			var diskPath object.DatastorePath
+			if !diskPath.FromString(volumeId) {
+				results[i].Error = errors.NotValidf("volume ID %q", volumeId)
+				continue
+			}
+			dirPath := object.DatastorePath{
+				Datastore: diskPath.Datastore,
+				Path:      path.Dir(diskPath.Path),
+			}
+			disks, ok := dirDisks[dirPath.String()]
+			if !ok {
+				var err error
+				disks, err = env.client.VirtualDisks(env.ctx, dirPath.String())
+				if err != nil {
+					results[i].Error = errors.Trace(err)
+					continue
+				}
+				dirDisks[dirPath.String()] = disks
+			}
+			results[i].Error = errors.NotFoundf("volume %q", volumeId)
+			for _, disk := range disks {
+				if disk.Path != path.Base(diskPath.Path) {
+					continue
+				}
+				results[i].Error = nil
+				results[i].VolumeInfo = &storage.VolumeInfo{
+					VolumeId:   volumeId,
+					Size:       uint64(disk.CapacityKb / 1024),
+					Persistent: true,
+				}
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// DestroyVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	err := s.env.withSession(func(env *sessionEnviron) error {
+		for i, volumeId := range volumeIds {
+			if err := env.client.DeleteVirtualDisk(env.ctx, volumeId); err != nil {
+				results[i] = errors.Annotatef(err, "destroying volume %q", volumeId)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// ReleaseVolumes is part of the storage.VolumeSource interface.
+//
+// Released volumes are moved out of the model's volume directory,
+// so that they are not destroyed along with the model. The VMDKs
+// can then be imported into another model.
+func (s *volumeSource) ReleaseVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	err := s.env.withSession(func(env *sessionEnviron) error {
+		for i, volumeId := range volumeIds {
+			var diskPath object.DatastorePath
+			if !diskPath.FromString(volumeId) {
+				results[i] = errors.NotValidf("volume ID %q", volumeId)
+				continue
+			}
+			releasedPath := object.DatastorePath{
+				Datastore: diskPath.Datastore,
+				Path: path.Join(
+					releasedVolumeDirectoryName(env.Config().UUID()),
+					path.Base(diskPath.Path),
+				),
+			}
+			if err := env.client.MoveVirtualDisk(env.ctx, volumeId, releasedPath.String()); err != nil {
+				results[i] = errors.Annotatef(err, "releasing volume %q", volumeId)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// ValidateVolumeParams is part of the storage.VolumeSource interface.
+func (s *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if params.Size == 0 {
+		return errors.NotValidf("volume size 0")
+	}
+	return nil
+}
+
+// AttachVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) AttachVolumes(ctx context.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(params))
+	err := s.env.withSession(func(env *sessionEnviron) error {
+		for i, p := range params {
+			attachment, err := s.attachVolume(env, p)
+			if err != nil {
+				results[i].Error = errors.Annotatef(
+					err, "attaching volume %s to machine %s",
+					p.Volume.Id(), p.Machine.Id(),
+				)
+				continue
+			}
+			results[i].VolumeAttachment = attachment
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+func (s *volumeSource) attachVolume(
+	env *sessionEnviron,
+	p storage.VolumeAttachmentParams,
+) (*storage.VolumeAttachment, error) {
+	vm, err := env.virtualMachine(p.InstanceId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uuid, err := env.client.AttachVirtualDisk(env.ctx, vm, p.VolumeId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.VolumeAttachment{
+		Volume:  p.Volume,
+		Machine: p.Machine,
+		VolumeAttachmentInfo: storage.VolumeAttachmentInfo{
+			DeviceLink: diskDeviceLink(uuid),
+		},
+	}, nil
+}
+
+// DetachVolumes is part of the storage.VolumeSource interface.
+func (s *volumeSource) DetachVolumes(ctx context.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(params))
+	err := s.env.withSession(func(env *sessionEnviron) error {
+		for i, p := range params {
+			vm, err := env.virtualMachine(p.InstanceId)
+			if errors.IsNotFound(err) {
+				// The VM is gone, so the volume is no longer attached.
+				continue
+			}
+			if err == nil {
+				err = env.client.DetachVirtualDisk(env.ctx, vm, p.VolumeId)
+			}
+			if err != nil {
+				results[i] = errors.Annotatef(
+					err, "detaching volume %s from machine %s",
+					p.Volume.Id(), p.Machine.Id(),
+				)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results, nil
+}
+
+// virtualMachine returns the VM for the instance with the given ID.
+func (env *sessionEnviron) virtualMachine(id instance.Id) (*mo.VirtualMachine, error) {
+	vms, err := env.client.VirtualMachines(env.ctx, path.Join(
+		controllerFolderName("*"),
+		env.modelFolderName(),
+		string(id),
+	))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(vms) == 0 {
+		return nil, errors.NotFoundf("instance %q", id)
+	}
+	return vms[0], nil
+}
+
+// diskDeviceLink returns the device link for an attached disk with
+// the given UUID, as reported by vSphere. VMs are created with
+// "disk.EnableUUID" set, so the guest sees the disk UUID as the
+// disk's WWN.
+func diskDeviceLink(uuid string) string {
+	wwn := strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(uuid))
+	return "/dev/disk/by-id/wwn-0x" + wwn
+}
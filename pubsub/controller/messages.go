@@ -22,3 +22,29 @@ type ConfigChangedMessage struct {
 	// different machines, and the forwarding of those messages cross each other.
 	// Adding a version could allow subscribers to ignore lower versioned messages.
 }
+
+// RaftLeaderTopic messages are published by the raft-leader-publisher
+// worker whenever the raft leader changes, and in response to requests
+// on RaftLeaderRequestTopic.
+// data: `RaftLeaderMessage`
+const RaftLeaderTopic = "controller.raft-leader"
+
+// RaftLeaderMessage identifies the controller machine that is currently
+// the raft leader. Leader is empty if no leader is known.
+type RaftLeaderMessage struct {
+	Leader    string `yaml:"leader"`
+	LocalOnly bool   `yaml:"local-only"`
+}
+
+// RaftLeaderRequestTopic is the topic that requests for the current
+// raft leader are published on. The raft-leader-publisher worker
+// responds by publishing on RaftLeaderTopic.
+// data: `RaftLeaderRequest`
+const RaftLeaderRequestTopic = "controller.raft-leader-request"
+
+// RaftLeaderRequest indicates the worker asking for the raft leader. It
+// should always be LocalOnly, as only the local raft worker is asked.
+type RaftLeaderRequest struct {
+	Requester string `yaml:"requester"`
+	LocalOnly bool   `yaml:"local-only"`
+}
@@ -4,6 +4,7 @@
 package rpc
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/juju/errors"
@@ -28,6 +29,7 @@ type Call struct {
 type RequestError struct {
 	Message string
 	Code    string
+	Info    map[string]interface{}
 }
 
 func (e *RequestError) Error() string {
@@ -41,6 +43,19 @@ func (e *RequestError) ErrorCode() string {
 	return e.Code
 }
 
+// UnmarshalInfo unmarshals the error's additional
+// information into the given value.
+func (e *RequestError) UnmarshalInfo(to interface{}) error {
+	if e.Info == nil {
+		return nil
+	}
+	data, err := json.Marshal(e.Info)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(data, to))
+}
+
 func (conn *Conn) send(call *Call) {
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
@@ -111,6 +126,7 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		call.Error = &RequestError{
 			Message: hdr.Error,
 			Code:    hdr.ErrorCode,
+			Info:    hdr.ErrorInfo,
 		}
 		err = conn.readBody(nil, false)
 		call.done()
@@ -59,44 +59,48 @@ type inMsgV0 struct {
 	Params    json.RawMessage
 	Error     string
 	ErrorCode string
+	ErrorInfo map[string]interface{}
 	Response  json.RawMessage
 }
 
 type inMsgV1 struct {
-	RequestId uint64          `json:"request-id"`
-	Type      string          `json:"type"`
-	Version   int             `json:"version"`
-	Id        string          `json:"id"`
-	Request   string          `json:"request"`
-	Params    json.RawMessage `json:"params"`
-	Error     string          `json:"error"`
-	ErrorCode string          `json:"error-code"`
-	Response  json.RawMessage `json:"response"`
+	RequestId uint64                 `json:"request-id"`
+	Type      string                 `json:"type"`
+	Version   int                    `json:"version"`
+	Id        string                 `json:"id"`
+	Request   string                 `json:"request"`
+	Params    json.RawMessage        `json:"params"`
+	Error     string                 `json:"error"`
+	ErrorCode string                 `json:"error-code"`
+	ErrorInfo map[string]interface{} `json:"error-info"`
+	Response  json.RawMessage        `json:"response"`
 }
 
 // outMsg holds an outgoing message.
 type outMsgV0 struct {
 	RequestId uint64
-	Type      string      `json:",omitempty"`
-	Version   int         `json:",omitempty"`
-	Id        string      `json:",omitempty"`
-	Request   string      `json:",omitempty"`
-	Params    interface{} `json:",omitempty"`
-	Error     string      `json:",omitempty"`
-	ErrorCode string      `json:",omitempty"`
-	Response  interface{} `json:",omitempty"`
+	Type      string                 `json:",omitempty"`
+	Version   int                    `json:",omitempty"`
+	Id        string                 `json:",omitempty"`
+	Request   string                 `json:",omitempty"`
+	Params    interface{}            `json:",omitempty"`
+	Error     string                 `json:",omitempty"`
+	ErrorCode string                 `json:",omitempty"`
+	ErrorInfo map[string]interface{} `json:",omitempty"`
+	Response  interface{}            `json:",omitempty"`
 }
 
 type outMsgV1 struct {
-	RequestId uint64      `json:"request-id,omitempty"`
-	Type      string      `json:"type,omitempty"`
-	Version   int         `json:"version,omitempty"`
-	Id        string      `json:"id,omitempty"`
-	Request   string      `json:"request,omitempty"`
-	Params    interface{} `json:"params,omitempty"`
-	Error     string      `json:"error,omitempty"`
-	ErrorCode string      `json:"error-code,omitempty"`
-	Response  interface{} `json:"response,omitempty"`
+	RequestId uint64                 `json:"request-id,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	Version   int                    `json:"version,omitempty"`
+	Id        string                 `json:"id,omitempty"`
+	Request   string                 `json:"request,omitempty"`
+	Params    interface{}            `json:"params,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	ErrorCode string                 `json:"error-code,omitempty"`
+	ErrorInfo map[string]interface{} `json:"error-info,omitempty"`
+	Response  interface{}            `json:"response,omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -139,6 +143,7 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.ErrorInfo = c.msg.ErrorInfo
 	hdr.Version = version
 	return nil
 }
@@ -172,6 +177,7 @@ func (c *Codec) readV0Message(m json.RawMessage) (inMsgV1, int, error) {
 		Params:    msg.Params,
 		Error:     msg.Error,
 		ErrorCode: msg.ErrorCode,
+		ErrorInfo: msg.ErrorInfo,
 		Response:  msg.Response,
 	}, 0, nil
 }
@@ -249,6 +255,7 @@ func newOutMsgV0(hdr *rpc.Header, body interface{}) outMsgV0 {
 		Request:   hdr.Request.Action,
 		Error:     hdr.Error,
 		ErrorCode: hdr.ErrorCode,
+		ErrorInfo: hdr.ErrorInfo,
 	}
 	if hdr.IsRequest() {
 		result.Params = body
@@ -272,6 +279,7 @@ func newOutMsgV1(hdr *rpc.Header, body interface{}) outMsgV1 {
 		Request:   hdr.Request.Action,
 		Error:     hdr.Error,
 		ErrorCode: hdr.ErrorCode,
+		ErrorInfo: hdr.ErrorInfo,
 	}
 	if hdr.IsRequest() {
 		result.Params = body
@@ -767,6 +767,38 @@ func (*rpcSuite) TestErrorCode(c *gc.C) {
 	c.Assert(errors.Cause(err).(rpc.ErrorCoder).ErrorCode(), gc.Equals, "code")
 }
 
+type infoError struct {
+	codedError
+	info map[string]interface{}
+}
+
+func (e *infoError) ErrorInfo() map[string]interface{} {
+	return e.info
+}
+
+func (*rpcSuite) TestErrorInfo(c *gc.C) {
+	root := &Root{
+		errorInst: &ErrorMethods{&infoError{
+			codedError: codedError{"message", "code"},
+			info:       map[string]interface{}{"retry-after": float64(1000)},
+		}},
+	}
+	client, _, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	err := client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
+	c.Assert(errors.Cause(err), gc.DeepEquals, &rpc.RequestError{
+		Message: "message",
+		Code:    "code",
+		Info:    map[string]interface{}{"retry-after": float64(1000)},
+	})
+	var info struct {
+		RetryAfter int `json:"retry-after"`
+	}
+	err = errors.Cause(err).(*rpc.RequestError).UnmarshalInfo(&info)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.RetryAfter, gc.Equals, 1000)
+}
+
 func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	root := &Root{
 		errorInst: &ErrorMethods{&codedError{"message", "code"}},
@@ -65,6 +65,10 @@ type Header struct {
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
 
+	// ErrorInfo holds additional information provided
+	// by the error, if any.
+	ErrorInfo map[string]interface{}
+
 	// Version defines the wire format of the request and response structure.
 	Version int
 }
@@ -336,6 +340,12 @@ type ErrorCoder interface {
 	ErrorCode() string
 }
 
+// ErrorInfoProvider represents an error that has additional
+// information, sent to the client alongside the error's code.
+type ErrorInfoProvider interface {
+	ErrorInfo() map[string]interface{}
+}
+
 // Root represents a type that can be used to lookup a Method and place
 // calls on that method.
 type Root interface {
@@ -486,6 +496,9 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, recorder Recorde
 	} else {
 		hdr.ErrorCode = ""
 	}
+	if err, ok := err.(ErrorInfoProvider); ok {
+		hdr.ErrorInfo = err.ErrorInfo()
+	}
 	hdr.Error = err.Error()
 	if err := recorder.HandleReply(reqHdr.Request, hdr, struct{}{}); err != nil {
 		logger.Errorf("error recording reply %+v: %T %+v", hdr, err, err)
@@ -0,0 +1,326 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+)
+
+var (
+	antiAffinityErr  = errors.New("anti-affinity policy violated")
+	noAffineMachines = errors.New("no suitable machines host affine applications")
+)
+
+// antiAffineApplications returns the names of the applications with
+// whose units a unit of the named application must never share a host
+// machine: those listed in its anti-affinity constraint, and those
+// whose own anti-affinity constraint lists it.
+func antiAffineApplications(st *State, appName string, cons *constraints.Value) (set.Strings, error) {
+	result := set.NewStrings()
+	if cons.AntiAffinity != nil {
+		result = set.NewStrings(*cons.AntiAffinity...)
+	}
+	constraintsCollection, closer := st.db().GetCollection(constraintsC)
+	defer closer()
+
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	err := constraintsCollection.Find(
+		bson.D{{"antiaffinity", appName}},
+	).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	prefix := applicationGlobalKey("")
+	for _, doc := range docs {
+		key := st.localID(doc.DocID)
+		if strings.HasPrefix(key, prefix) {
+			result.Add(strings.TrimPrefix(key, prefix))
+		}
+	}
+	result.Remove(appName)
+	return result, nil
+}
+
+// validateAffinityConstraints returns an error if the affinity or
+// anti-affinity constraint of the named application refers to the
+// application itself.
+func validateAffinityConstraints(appName string, cons constraints.Value) error {
+	check := func(attr string, apps *[]string) error {
+		if apps == nil {
+			return nil
+		}
+		for _, app := range *apps {
+			if app == appName {
+				return errors.NotValidf("%q constraint naming application %q itself", attr, appName)
+			}
+		}
+		return nil
+	}
+	if err := check(constraints.Affinity, cons.Affinity); err != nil {
+		return err
+	}
+	return check(constraints.AntiAffinity, cons.AntiAffinity)
+}
+
+// hostApplications returns the names of the applications with
+// principal units on the host of the specified machine: the top-level
+// machine and any containers nested within it. The ids of those
+// machines are also returned.
+func hostApplications(st *State, machineId string) (set.Strings, []string, error) {
+	hostId := TopParentId(machineId)
+	machinesCollection, closer := st.db().GetCollection(machinesC)
+	defer closer()
+
+	var docs []machineDoc
+	err := machinesCollection.Find(bson.D{{"$or", []bson.D{
+		{{"machineid", hostId}},
+		{{"machineid", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(hostId+"/")}}},
+	}}}).Select(bson.D{{"machineid", 1}, {"principals", 1}}).All(&docs)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	apps := set.NewStrings()
+	machineIds := make([]string, len(docs))
+	for i, doc := range docs {
+		machineIds[i] = doc.Id
+		for _, unitName := range doc.Principals {
+			apps.Add(unitAppName(unitName))
+		}
+	}
+	return apps, machineIds, nil
+}
+
+// checkAntiAffinity returns an error with antiAffinityErr as its cause
+// if the host of the specified machine runs units of any of the given
+// applications. Otherwise it returns ops that assert that this is still
+// the case when the assignment is made: that no machine on the host
+// has been assigned a unit of those applications, and that no container
+// has been added to the host since it was checked.
+func checkAntiAffinity(st *State, machineId string, antiAffine set.Strings) ([]txn.Op, error) {
+	if antiAffine.IsEmpty() {
+		return nil, nil
+	}
+	apps, machineIds, err := hostApplications(st, machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	conflicts := apps.Intersection(antiAffine)
+	if !conflicts.IsEmpty() {
+		quoted := make([]string, 0, conflicts.Size())
+		for _, app := range conflicts.SortedValues() {
+			quoted = append(quoted, fmt.Sprintf("%q", app))
+		}
+		return nil, errors.Annotatef(
+			antiAffinityErr, "machine %s hosts units of %s",
+			TopParentId(machineId), strings.Join(quoted, ", "),
+		)
+	}
+
+	quoted := make([]string, 0, antiAffine.Size())
+	for _, app := range antiAffine.SortedValues() {
+		quoted = append(quoted, regexp.QuoteMeta(app))
+	}
+	noAntiAffineUnits := bson.D{{"principals", bson.D{{"$not", bson.RegEx{
+		Pattern: "^(" + strings.Join(quoted, "|") + ")/",
+	}}}}}
+	noNewContainers := bson.D{{"children", bson.D{{"$not", bson.D{{"$elemMatch", bson.D{
+		{"$nin", machineIds},
+	}}}}}}}
+	ops := make([]txn.Op, 0, 2*len(machineIds))
+	for _, id := range machineIds {
+		ops = append(ops, txn.Op{
+			C:      machinesC,
+			Id:     st.docID(id),
+			Assert: noAntiAffineUnits,
+		}, txn.Op{
+			C:      containerRefsC,
+			Id:     st.docID(id),
+			Assert: noNewContainers,
+		})
+	}
+	return ops, nil
+}
+
+// checkAntiAffinity returns an error with antiAffinityErr as its cause
+// if assigning the unit to the specified machine would place it on the
+// same host as units of an application it has anti-affinity with.
+// Otherwise it returns ops asserting that this is still so when the
+// assignment is made.
+func (u *Unit) checkAntiAffinity(machineId string) ([]txn.Op, error) {
+	if u.doc.Principal != "" {
+		return nil, nil
+	}
+	cons, err := u.Constraints()
+	if errors.IsNotFound(err) {
+		cons = &constraints.Value{}
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	antiAffine, err := antiAffineApplications(u.st, u.doc.Application, cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return checkAntiAffinity(u.st, machineId, antiAffine)
+}
+
+// assignToAffineMachine assigns the unit to a machine already running
+// units of one of the applications named in the unit's affinity
+// constraint. If the unit has no affinity, or no such machine is
+// suitable, an error with noAffineMachines as its cause is returned.
+func (u *Unit) assignToAffineMachine() (*Machine, error) {
+	var m *Machine
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var err error
+		u := u // don't change outer var
+		if attempt > 0 {
+			u, err = u.st.Unit(u.Name())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		var ops []txn.Op
+		m, ops, err = u.assignToAffineMachineOps()
+		return ops, err
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	u.doc.MachineId = m.doc.Id
+	m.doc.Clean = false
+	return m, nil
+}
+
+func (u *Unit) assignToAffineMachineOps() (_ *Machine, _ []txn.Op, err error) {
+	failure := func(err error) (*Machine, []txn.Op, error) {
+		return nil, nil, err
+	}
+	context := "affine machine"
+
+	if u.doc.Principal != "" {
+		err = fmt.Errorf("unit is a subordinate")
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
+	cons, err := u.Constraints()
+	if err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
+	if !cons.HasAffinity() {
+		return failure(noAffineMachines)
+	}
+	antiAffine, err := antiAffineApplications(u.st, u.doc.Application, cons)
+	if err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
+	storageParams, err := u.storageParams()
+	if err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
+	machines, err := u.affineMachines(cons)
+	if err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
+	for _, m := range machines {
+		antiAffinityOps, err := checkAntiAffinity(u.st, m.Id(), antiAffine)
+		if errors.Cause(err) == antiAffinityErr {
+			continue
+		} else if err != nil {
+			assignContextf(&err, u.Name(), context)
+			return failure(err)
+		}
+		if err := validateDynamicMachineStorageParams(m, storageParams); err != nil {
+			if errors.IsNotSupported(err) {
+				continue
+			}
+			assignContextf(&err, u.Name(), context)
+			return failure(err)
+		}
+		ops, err := u.assignToMachineOps(m, false)
+		if err == nil {
+			return m, append(ops, antiAffinityOps...), nil
+		}
+		switch errors.Cause(err) {
+		case machineNotAliveErr, machineInMaintenanceErr:
+		default:
+			assignContextf(&err, u.Name(), context)
+			return failure(err)
+		}
+	}
+	return failure(noAffineMachines)
+}
+
+// affineMachines returns the machines able to host the unit that run
+// units of the applications named in the unit's affinity constraint,
+// and that satisfy its hardware constraints.
+// They are ordered according to the placement strategy: machines with
+// the fewest units of the unit's own application come first when
+// spreading, and those with the most when packing.
+func (u *Unit) affineMachines(cons *constraints.Value) ([]*Machine, error) {
+	quoted := make([]string, len(*cons.Affinity))
+	for i, app := range *cons.Affinity {
+		quoted[i] = regexp.QuoteMeta(app)
+	}
+	terms := bson.D{
+		{"life", Alive},
+		{"series", u.doc.Series},
+		{"jobs", JobHostUnits},
+		{"principals", bson.RegEx{Pattern: "^(" + strings.Join(quoted, "|") + ")/"}},
+	}
+	terms = append(terms, notInMaintenanceDoc...)
+	if cons.Container != nil {
+		containerType := *cons.Container
+		if containerType == instance.NONE {
+			containerType = ""
+		}
+		terms = append(terms, bson.DocElem{"containertype", string(containerType)})
+	}
+	hardwareTerms, err := hardwareConstraintsTerms(u.st.db(), cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	terms = append(terms, hardwareTerms...)
+	machinesCollection, closer := u.st.db().GetCollection(machinesC)
+	defer closer()
+	var mdocs []*machineDoc
+	if err := machinesCollection.Find(terms).Sort("_id").All(&mdocs); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ownUnits := make(map[string]int)
+	machines := make([]*Machine, len(mdocs))
+	for i, mdoc := range mdocs {
+		machines[i] = newMachine(u.st, mdoc)
+		for _, unitName := range mdoc.Principals {
+			if unitAppName(unitName) == u.doc.Application {
+				ownUnits[mdoc.Id]++
+			}
+		}
+	}
+	pack := cons.HasPlacementStrategy() && *cons.PlacementStrategy == constraints.PlacementPack
+	sort.SliceStable(machines, func(i, j int) bool {
+		ni, nj := ownUnits[machines[i].Id()], ownUnits[machines[j].Id()]
+		if pack {
+			return ni > nj
+		}
+		return ni < nj
+	})
+	return machines, nil
+}
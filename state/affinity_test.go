@@ -0,0 +1,204 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type AffinitySuite struct {
+	ConnSuite
+	wordpress *state.Application
+	mysql     *state.Application
+	machines  []*state.Machine
+}
+
+var _ = gc.Suite(&AffinitySuite{})
+
+func (s *AffinitySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.machines = make([]*state.Machine, 2)
+	for i := range s.machines {
+		var err error
+		s.machines[i], err = s.State.AddOneMachine(state.MachineTemplate{
+			Series: "quantal",
+			Jobs:   []state.MachineJob{state.JobHostUnits},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+// addWordpressInContainer assigns a wordpress unit to a new container
+// on the first machine, which remains clean.
+func (s *AffinitySuite) addWordpressInContainer(c *gc.C) {
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnitWithPlacement(unit, &instance.Placement{
+		Scope: string(instance.LXD), Directive: s.machines[0].Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AffinitySuite) setConstraints(c *gc.C, app *state.Application, cons string) {
+	err := app.SetConstraints(constraints.MustParse(cons))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AffinitySuite) TestAntiAffinitySkipsHost(c *gc.C) {
+	s.addWordpressInContainer(c)
+	s.setConstraints(c, s.mysql, "anti-affinity=wordpress")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, s.machines[1].Id())
+}
+
+func (s *AffinitySuite) TestAntiAffinityIsSymmetric(c *gc.C) {
+	s.addWordpressInContainer(c)
+	s.setConstraints(c, s.wordpress, "anti-affinity=mysql")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, s.machines[1].Id())
+}
+
+func (s *AffinitySuite) TestAntiAffinityRefusesMachine(c *gc.C) {
+	s.addWordpressInContainer(c)
+	s.setConstraints(c, s.mysql, "anti-affinity=wordpress")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machines[0])
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0" to machine 0: machine 0 hosts units of "wordpress": anti-affinity policy violated`)
+}
+
+func (s *AffinitySuite) TestAntiAffinityRefusesContainerPlacement(c *gc.C) {
+	s.addWordpressInContainer(c)
+	s.setConstraints(c, s.mysql, "anti-affinity=wordpress")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnitWithPlacement(unit, &instance.Placement{
+		Scope: string(instance.LXD), Directive: s.machines[0].Id(),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0" to machine 0: machine 0 hosts units of "wordpress": anti-affinity policy violated`)
+
+	// No container was left behind.
+	containers, err := s.machines[0].Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 1)
+}
+
+func (s *AffinitySuite) TestAntiAffinityConcurrentUnit(c *gc.C) {
+	s.setConstraints(c, s.mysql, "anti-affinity=wordpress")
+	wordpress, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := wordpress.AssignToMachine(s.machines[0])
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = unit.AssignToMachine(s.machines[0])
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0" to machine 0: machine 0 hosts units of "wordpress": anti-affinity policy violated`)
+}
+
+func (s *AffinitySuite) TestAntiAffinityConcurrentContainer(c *gc.C) {
+	s.setConstraints(c, s.mysql, "anti-affinity=wordpress")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		s.addWordpressInContainer(c)
+	}).Check()
+
+	err = unit.AssignToMachine(s.machines[0])
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0" to machine 0: machine 0 hosts units of "wordpress": anti-affinity policy violated`)
+}
+
+func (s *AffinitySuite) TestAffinity(c *gc.C) {
+	wordpress, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.AssignToMachine(s.machines[1])
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setConstraints(c, s.mysql, "affinity=wordpress")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, s.machines[1].Id())
+}
+
+func (s *AffinitySuite) TestAffinityRespectsHardwareConstraints(c *gc.C) {
+	for i, mem := range []uint64{1024, 8192} {
+		hc := &instance.HardwareCharacteristics{Mem: &mem}
+		err := s.machines[i].SetProvisioned(instance.Id(fmt.Sprintf("inst-%d", i)), "fake_nonce", hc)
+		c.Assert(err, jc.ErrorIsNil)
+		wordpress, err := s.wordpress.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = wordpress.AssignToMachine(s.machines[i])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	s.setConstraints(c, s.mysql, "affinity=wordpress mem=4G")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, s.machines[1].Id())
+}
+
+func (s *AffinitySuite) TestAffinityFallsBack(c *gc.C) {
+	s.setConstraints(c, s.mysql, "affinity=wordpress")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, s.machines[0].Id())
+}
+
+func (s *AffinitySuite) TestAffinityRespectsAntiAffinity(c *gc.C) {
+	for i, app := range []*state.Application{s.wordpress, s.mysql} {
+		unit, err := app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(s.machines[i])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// A second mysql unit may not join wordpress on machine 0 if
+	// wordpress has anti-affinity with mysql, so it goes elsewhere.
+	s.setConstraints(c, s.wordpress, "anti-affinity=mysql")
+	s.setConstraints(c, s.mysql, "affinity=wordpress")
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), s.machines[0].Id())
+}
+
+func (s *AffinitySuite) TestSelfAffinityNotValid(c *gc.C) {
+	err := s.mysql.SetConstraints(constraints.MustParse("anti-affinity=wordpress,mysql"))
+	c.Assert(err, gc.ErrorMatches, `"anti-affinity" constraint naming application "mysql" itself not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -154,6 +154,27 @@ func allCollections() collectionSchema {
 			rawAccess: true,
 		},
 
+		// This collection holds the user groups defined in the controller,
+		// along with their explicitly added members.
+		userGroupsC: {
+			global: true,
+		},
+
+		// This collection holds the groups that an external identity
+		// provider claimed each user belongs to when they last logged in.
+		userGroupClaimsC: {
+			global:    true,
+			rawAccess: true,
+		},
+
+		// This collection holds the API tokens issued to users.
+		apiTokensC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"user"},
+			}},
+		},
+
 		// This collection is used as a unique key restraint. The _id field is
 		// a concatenation of multiple fields that form a compound index,
 		// allowing us to ensure users cannot have the same name for two
@@ -510,6 +531,7 @@ const (
 	actionresultsC             = "actionresults"
 	actionsC                   = "actions"
 	annotationsC               = "annotations"
+	apiTokensC                 = "apitokens"
 	autocertCacheC             = "autocertCache"
 	assignUnitC                = "assignUnits"
 	bakeryStorageItemsC        = "bakeryStorageItems"
@@ -582,6 +604,8 @@ const (
 	txnsC                      = "txns"
 	unitsC                     = "units"
 	upgradeInfoC               = "upgradeInfo"
+	userGroupClaimsC           = "usergroupclaims"
+	userGroupsC                = "usergroups"
 	userLastLoginC             = "userLastLogin"
 	usermodelnameC             = "usermodelname"
 	usersC                     = "users"
@@ -0,0 +1,266 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// apiTokenDoc records an API token issued by the controller. Only a
+// salted hash of the token's secret is stored.
+type apiTokenDoc struct {
+	DocID        string    `bson:"_id"`
+	User         string    `bson:"user"`
+	Description  string    `bson:"description"`
+	SecretHash   string    `bson:"secret-hash"`
+	SecretSalt   string    `bson:"secret-salt"`
+	CreatedBy    string    `bson:"created-by"`
+	DateCreated  time.Time `bson:"date-created"`
+	Expires      time.Time `bson:"expires"`
+	Models       []string  `bson:"models,omitempty"`
+	FacadeGroups []string  `bson:"facade-groups,omitempty"`
+}
+
+// APIToken represents a revocable token, issued by the controller,
+// with which a user may log in to the API without a password.
+type APIToken struct {
+	st  *State
+	doc apiTokenDoc
+}
+
+// Id returns the token's unique identifier.
+func (t *APIToken) Id() string {
+	return t.doc.DocID
+}
+
+// UserTag returns the tag of the user that logs in with the token.
+func (t *APIToken) UserTag() names.UserTag {
+	return names.NewUserTag(t.doc.User)
+}
+
+// Description returns the description given when the token was added.
+func (t *APIToken) Description() string {
+	return t.doc.Description
+}
+
+// CreatedBy returns the name of the user that added the token.
+func (t *APIToken) CreatedBy() string {
+	return t.doc.CreatedBy
+}
+
+// DateCreated returns when the token was added in UTC.
+func (t *APIToken) DateCreated() time.Time {
+	return t.doc.DateCreated.UTC()
+}
+
+// Expires returns when the token stops being valid, in UTC.
+func (t *APIToken) Expires() time.Time {
+	return t.doc.Expires.UTC()
+}
+
+// Models returns the UUIDs of the models the token is restricted to.
+// If it is empty, the token may be used with any model the user has
+// access to.
+func (t *APIToken) Models() []string {
+	return t.doc.Models
+}
+
+// FacadeGroups returns the names of the facade method groups the token
+// is restricted to. If it is empty, the token may be used to call any
+// method the user has access to.
+func (t *APIToken) FacadeGroups() []string {
+	return t.doc.FacadeGroups
+}
+
+// Expired reports whether the token has expired at the given time.
+func (t *APIToken) Expired(now time.Time) bool {
+	return !now.Before(t.doc.Expires)
+}
+
+// SecretValid reports whether the given secret matches the token's.
+func (t *APIToken) SecretValid(secret string) bool {
+	if t.doc.SecretSalt == "" {
+		return false
+	}
+	return utils.UserPasswordHash(secret, t.doc.SecretSalt) == t.doc.SecretHash
+}
+
+// AddAPITokenArgs holds the parameters for adding an API token.
+type AddAPITokenArgs struct {
+	// User is the local user that logs in with the token.
+	User names.UserTag
+
+	// CreatedBy is the user adding the token.
+	CreatedBy names.UserTag
+
+	// Description describes the purpose of the token.
+	Description string
+
+	// Expires is when the token stops being valid.
+	Expires time.Time
+
+	// Models, if non-empty, holds the UUIDs of the only
+	// models the token may be used with.
+	Models []string
+
+	// FacadeGroups, if non-empty, holds the names of the only
+	// facade method groups the token may be used to call.
+	FacadeGroups []string
+}
+
+// AddAPIToken adds an API token for a local user, returning the token
+// and its secret. The secret is not stored and cannot be retrieved
+// later.
+func (st *State) AddAPIToken(args AddAPITokenArgs) (*APIToken, string, error) {
+	if !args.User.IsLocal() {
+		return nil, "", errors.NotValidf("API token for external user %q", args.User.Id())
+	}
+	if args.Expires.IsZero() {
+		return nil, "", errors.NotValidf("API token without expiry")
+	}
+	if !args.Expires.After(st.clock().Now()) {
+		return nil, "", errors.NotValidf("API token expiry %s in the past", args.Expires.UTC().Format(time.RFC3339))
+	}
+	user, err := st.User(args.User)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if user.IsDisabled() {
+		return nil, "", errors.Errorf("user %q is disabled", user.Name())
+	}
+	for _, modelUUID := range args.Models {
+		if !names.IsValidModel(modelUUID) {
+			return nil, "", errors.NotValidf("model UUID %q", modelUUID)
+		}
+	}
+
+	id, err := utils.NewUUID()
+	if err != nil {
+		return nil, "", errors.Annotate(err, "cannot generate token id")
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Annotate(err, "cannot generate token secret")
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Annotate(err, "cannot generate salt")
+	}
+	token := &APIToken{
+		st: st,
+		doc: apiTokenDoc{
+			DocID:        id.String(),
+			User:         user.Name(),
+			Description:  args.Description,
+			SecretHash:   utils.UserPasswordHash(secret, salt),
+			SecretSalt:   salt,
+			CreatedBy:    args.CreatedBy.Id(),
+			DateCreated:  st.nowToTheSecond(),
+			Expires:      args.Expires.UTC(),
+			Models:       args.Models,
+			FacadeGroups: args.FacadeGroups,
+		},
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     user.doc.DocID,
+		Assert: bson.D{{"deleted", bson.D{{"$ne", true}}}},
+	}, {
+		C:      apiTokensC,
+		Id:     token.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &token.doc,
+	}}
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			return nil, "", errors.Errorf("cannot add API token for %q: user removed or token id in use", user.Name())
+		}
+		return nil, "", errors.Trace(err)
+	}
+	return token, secret, nil
+}
+
+// APIToken returns the API token with the given id.
+func (st *State) APIToken(id string) (*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	token := &APIToken{st: st}
+	err := tokens.FindId(id).One(&token.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("API token %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get API token %q", id)
+	}
+	return token, nil
+}
+
+// APITokens returns the API tokens for the given user, or for all users
+// if the tag is empty, ordered by creation date.
+func (st *State) APITokens(user names.UserTag) ([]*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var query bson.D
+	if user != (names.UserTag{}) {
+		query = bson.D{{"user", user.Id()}}
+	}
+	var docs []apiTokenDoc
+	if err := tokens.Find(query).Sort("date-created", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get API tokens")
+	}
+	result := make([]*APIToken, len(docs))
+	for i, doc := range docs {
+		result[i] = &APIToken{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RevokeAPIToken removes the API token with the given id, so that it
+// can no longer be used to log in.
+func (st *State) RevokeAPIToken(id string) error {
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("API token %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// WatchAPIToken returns a watcher for observing changes to the API
+// token with the given id, including its revocation.
+func (st *State) WatchAPIToken(id string) NotifyWatcher {
+	return newEntityWatcher(st, apiTokensC, id)
+}
+
+// removeAPITokensOps returns the operations to remove all the API
+// tokens for the given user.
+func (st *State) removeAPITokensOps(user names.UserTag) ([]txn.Op, error) {
+	tokens, err := st.APITokens(user)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(tokens))
+	for i, token := range tokens {
+		ops[i] = txn.Op{
+			C:      apiTokensC,
+			Id:     token.Id(),
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type APITokenSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&APITokenSuite{})
+
+func (s *APITokenSuite) addToken(c *gc.C, user string) (*state.APIToken, string) {
+	token, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		User:      names.NewUserTag(user),
+		CreatedBy: s.Owner,
+		Expires:   s.Clock.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return token, secret
+}
+
+func (s *APITokenSuite) TestAddAPIToken(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	expires := s.Clock.Now().Add(24 * time.Hour).Round(time.Second)
+	token, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		User:         names.NewUserTag("ci"),
+		CreatedBy:    s.Owner,
+		Description:  "deploy pipeline",
+		Expires:      expires,
+		Models:       []string{s.Model.UUID()},
+		FacadeGroups: []string{"deploy"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret, gc.Not(gc.Equals), "")
+	c.Assert(token.UserTag(), gc.Equals, names.NewUserTag("ci"))
+	c.Assert(token.CreatedBy(), gc.Equals, s.Owner.Id())
+	c.Assert(token.Description(), gc.Equals, "deploy pipeline")
+	c.Assert(token.Expires().Equal(expires), jc.IsTrue)
+	c.Assert(token.Models(), jc.DeepEquals, []string{s.Model.UUID()})
+	c.Assert(token.FacadeGroups(), jc.DeepEquals, []string{"deploy"})
+
+	token, err = s.State.APIToken(token.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.SecretValid(secret), jc.IsTrue)
+	c.Assert(token.SecretValid(secret+"x"), jc.IsFalse)
+	c.Assert(token.Expired(s.Clock.Now()), jc.IsFalse)
+	c.Assert(token.Expired(expires), jc.IsTrue)
+}
+
+func (s *APITokenSuite) TestAddAPITokenInvalid(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	disabled := s.Factory.MakeUser(c, &factory.UserParams{Name: "old", Disabled: true})
+	for i, test := range []struct {
+		args state.AddAPITokenArgs
+		err  string
+	}{{
+		args: state.AddAPITokenArgs{User: names.NewUserTag("ci@external"), Expires: s.Clock.Now().Add(time.Hour)},
+		err:  `API token for external user "ci@external" not valid`,
+	}, {
+		args: state.AddAPITokenArgs{User: names.NewUserTag("ci")},
+		err:  `API token without expiry not valid`,
+	}, {
+		args: state.AddAPITokenArgs{User: names.NewUserTag("ci"), Expires: s.Clock.Now().Add(-time.Hour)},
+		err:  `API token expiry .* in the past not valid`,
+	}, {
+		args: state.AddAPITokenArgs{User: names.NewUserTag("nobody"), Expires: s.Clock.Now().Add(time.Hour)},
+		err:  `user "nobody" not found`,
+	}, {
+		args: state.AddAPITokenArgs{User: disabled.UserTag(), Expires: s.Clock.Now().Add(time.Hour)},
+		err:  `user "old" is disabled`,
+	}, {
+		args: state.AddAPITokenArgs{User: names.NewUserTag("ci"), Expires: s.Clock.Now().Add(time.Hour), Models: []string{"foo"}},
+		err:  `model UUID "foo" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		_, _, err := s.State.AddAPIToken(test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *APITokenSuite) TestAPITokens(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	ci1, _ := s.addToken(c, "ci")
+	bob, _ := s.addToken(c, "bob")
+	ci2, _ := s.addToken(c, "ci")
+
+	tokens, err := s.State.APITokens(names.NewUserTag("ci"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokenIds(tokens), jc.SameContents, []string{ci1.Id(), ci2.Id()})
+
+	tokens, err = s.State.APITokens(names.UserTag{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokenIds(tokens), jc.SameContents, []string{ci1.Id(), ci2.Id(), bob.Id()})
+}
+
+func (s *APITokenSuite) TestRevokeAPIToken(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	token, _ := s.addToken(c, "ci")
+
+	err := s.State.RevokeAPIToken(token.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.APIToken(token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RevokeAPIToken(token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *APITokenSuite) TestRemoveUserRemovesAPITokens(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	token, _ := s.addToken(c, "ci")
+
+	err := s.State.RemoveUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.APIToken(token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func tokenIds(tokens []*state.APIToken) []string {
+	ids := make([]string, len(tokens))
+	for i, token := range tokens {
+		ids[i] = token.Id()
+	}
+	return ids
+}
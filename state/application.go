@@ -388,6 +388,13 @@ func (a *Application) removeOps(asserts bson.D) ([]txn.Op, error) {
 	}
 	ops = append(ops, removeOfferOps...)
 
+	// Remove access granted on the application.
+	removeAccessOps, err := removeApplicationAccessOps(a.st, a.doc.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, removeAccessOps...)
+
 	// Note that appCharmDecRefOps might not catch the final decref
 	// when run in a transaction that decrefs more than once. So we
 	// avoid attempting to do the final cleanup in the ref dec ops and
@@ -2035,6 +2042,9 @@ func (a *Application) SetConstraints(cons constraints.Value) (err error) {
 	if a.doc.Subordinate {
 		return ErrSubordinateConstraints
 	}
+	if err := validateAffinityConstraints(a.doc.Name, cons); err != nil {
+		return errors.Trace(err)
+	}
 	defer errors.DeferredAnnotatef(&err, "cannot set constraints")
 	if a.doc.Life != Alive {
 		return applicationNotAliveErr
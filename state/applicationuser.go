@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/permission"
+)
+
+// applicationPermissionGlobalKey prefixes the keys of the objects
+// that access to applications is recorded against.
+const applicationPermissionGlobalKey = "ap"
+
+// applicationPermissionKey returns the key that access to the named
+// application in the given model is recorded against. The permissions
+// collection is global, so the key must include the model UUID.
+func applicationPermissionKey(modelUUID, appName string) string {
+	return fmt.Sprintf("%s#%s#%s", applicationPermissionGlobalKey, modelUUID, appName)
+}
+
+// GetApplicationAccess gets the access permission for the specified
+// user on an application in the model.
+func (st *State) GetApplicationAccess(appName string, user names.UserTag) (permission.Access, error) {
+	perm, err := st.userPermission(applicationPermissionKey(st.ModelUUID(), appName), userGlobalKey(userAccessID(user)))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return perm.access(), nil
+}
+
+// GetApplicationUsers gets the access permissions on an application
+// in the model.
+func (st *State) GetApplicationUsers(appName string) (map[string]permission.Access, error) {
+	perms, err := st.usersPermissions(applicationPermissionKey(st.ModelUUID(), appName))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]permission.Access)
+	for _, p := range perms {
+		result[userIDFromGlobalKey(p.doc.SubjectGlobalKey)] = p.access()
+	}
+	return result, nil
+}
+
+// CreateApplicationAccess creates a new access permission for a user
+// on an application.
+func (st *State) CreateApplicationAccess(app names.ApplicationTag, user names.UserTag, access permission.Access) error {
+	if err := permission.ValidateApplicationAccess(access); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Local users and groups must exist.
+	if user.IsLocal() {
+		if _, err := st.User(user); err != nil {
+			if errors.IsNotFound(err) {
+				return errors.Annotatef(err, "user %q does not exist locally", user.Name())
+			}
+			return errors.Trace(err)
+		}
+	} else if IsUserGroupTag(user) {
+		if _, err := st.UserGroup(user.Name()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if _, err := st.Application(app.Id()); err != nil {
+		return errors.Annotate(err, "creating application access")
+	}
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     st.docID(app.Id()),
+		Assert: isAliveDoc,
+	}, createPermissionOp(applicationPermissionKey(st.ModelUUID(), app.Id()), userGlobalKey(userAccessID(user)), access)}
+
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		if _, err := st.GetApplicationAccess(app.Id(), user); err == nil {
+			return errors.AlreadyExistsf("permission for user %q for application %q", user.Id(), app.Id())
+		}
+		return errors.Errorf("application %q is no longer alive", app.Id())
+	}
+	return errors.Trace(err)
+}
+
+// UpdateApplicationAccess changes the user's access permissions on
+// an application.
+func (st *State) UpdateApplicationAccess(app names.ApplicationTag, user names.UserTag, access permission.Access) error {
+	if err := permission.ValidateApplicationAccess(access); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := st.GetApplicationAccess(app.Id(), user); err != nil {
+		return errors.Trace(err)
+	}
+	op := updatePermissionOp(applicationPermissionKey(st.ModelUUID(), app.Id()), userGlobalKey(userAccessID(user)), access)
+	err := st.db().RunTransaction([]txn.Op{op})
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("permission for user %q for application %q", user.Id(), app.Id())
+	}
+	return errors.Trace(err)
+}
+
+// RemoveApplicationAccess removes the access permission for a user
+// on an application.
+func (st *State) RemoveApplicationAccess(app names.ApplicationTag, user names.UserTag) error {
+	op := removePermissionOp(applicationPermissionKey(st.ModelUUID(), app.Id()), userGlobalKey(userAccessID(user)))
+	err := st.db().RunTransaction([]txn.Op{op})
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("permission for user %q for application %q", user.Id(), app.Id())
+	}
+	return errors.Trace(err)
+}
+
+// removeApplicationAccessOps returns the operations that remove all
+// access granted on the named application.
+func removeApplicationAccessOps(st *State, appName string) ([]txn.Op, error) {
+	pattern := bson.M{
+		"_id": bson.M{"$regex": "^" + permissionID(applicationPermissionKey(st.ModelUUID(), appName), "")},
+	}
+	return st.removeInCollectionOps(permissionsC, pattern)
+}
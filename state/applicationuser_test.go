@@ -0,0 +1,119 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ApplicationUserSuite struct {
+	ConnSuite
+	app  *state.Application
+	user names.UserTag
+}
+
+var _ = gc.Suite(&ApplicationUserSuite{})
+
+func (s *ApplicationUserSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.app = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.user = s.Factory.MakeUser(c, &factory.UserParams{
+		Name:   "validusername",
+		Access: permission.ReadAccess,
+	}).UserTag()
+}
+
+func (s *ApplicationUserSuite) TestCreateApplicationAccess(c *gc.C) {
+	_, err := s.State.GetApplicationAccess("mysql", s.user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.CreateApplicationAccess(s.app.ApplicationTag(), s.user, permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.GetApplicationAccess("mysql", s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.OperateAccess)
+
+	access, err = s.State.UserPermission(s.user, s.app.ApplicationTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.OperateAccess)
+
+	err = s.State.CreateApplicationAccess(s.app.ApplicationTag(), s.user, permission.AdminAccess)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ApplicationUserSuite) TestCreateApplicationAccessInvalid(c *gc.C) {
+	err := s.State.CreateApplicationAccess(s.app.ApplicationTag(), s.user, permission.WriteAccess)
+	c.Assert(err, gc.ErrorMatches, `"write" application access not valid`)
+
+	err = s.State.CreateApplicationAccess(s.app.ApplicationTag(), names.NewUserTag("nobody"), permission.ReadAccess)
+	c.Assert(err, gc.ErrorMatches, `user "nobody" does not exist locally: user "nobody" not found`)
+
+	err = s.State.CreateApplicationAccess(names.NewApplicationTag("wordpress"), s.user, permission.ReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.CreateApplicationAccess(s.app.ApplicationTag(), state.UserGroupTag("devs"), permission.ReadAccess)
+	c.Assert(err, gc.ErrorMatches, `group "devs" not found`)
+}
+
+func (s *ApplicationUserSuite) TestGetApplicationUsers(c *gc.C) {
+	err := s.State.CreateApplicationAccess(s.app.ApplicationTag(), s.user, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CreateApplicationAccess(s.app.ApplicationTag(), names.NewUserTag("bob@external"), permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	users, err := s.State.GetApplicationUsers("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, jc.DeepEquals, map[string]permission.Access{
+		"validusername": permission.ReadAccess,
+		"bob@external":  permission.AdminAccess,
+	})
+}
+
+func (s *ApplicationUserSuite) TestUpdateApplicationAccess(c *gc.C) {
+	err := s.State.UpdateApplicationAccess(s.app.ApplicationTag(), s.user, permission.AdminAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.CreateApplicationAccess(s.app.ApplicationTag(), s.user, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateApplicationAccess(s.app.ApplicationTag(), s.user, permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.GetApplicationAccess("mysql", s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *ApplicationUserSuite) TestRemoveApplicationAccess(c *gc.C) {
+	err := s.State.RemoveApplicationAccess(s.app.ApplicationTag(), s.user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.CreateApplicationAccess(s.app.ApplicationTag(), s.user, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveApplicationAccess(s.app.ApplicationTag(), s.user)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.GetApplicationAccess("mysql", s.user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ApplicationUserSuite) TestRemoveApplicationRemovesAccess(c *gc.C) {
+	err := s.State.CreateApplicationAccess(s.app.ApplicationTag(), s.user, permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.app.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// An application of the same name does not inherit the access.
+	s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err = s.State.GetApplicationAccess("mysql", s.user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
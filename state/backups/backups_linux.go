@@ -140,6 +140,9 @@ func (b *backups) Restore(backupId string, args RestoreArgs) (names.Tag, error)
 	}
 	APIHostPorts := network.NewHostPorts(ssi.APIPort, args.PrivateAddress, args.PublicAddress)
 	agentConfig.SetAPIHostPorts([][]network.HostPort{APIHostPorts})
+	if err := regenerateControllerCert(agentConfig, args.PrivateAddress, args.PublicAddress); err != nil {
+		return nil, errors.Annotate(err, "cannot regenerate controller certificate")
+	}
 	if err := agentConfig.Write(); err != nil {
 		return nil, errors.Annotate(err, "cannot write new agent configuration")
 	}
@@ -262,6 +265,11 @@ func (b *backups) Restore(backupId string, args RestoreArgs) (names.Tag, error)
 		return nil, errors.Annotate(err, "cannot update api server host ports")
 	}
 
+	logger.Infof("removing controllers lost with the backed up replica-set")
+	if err := demoteOtherControllers(st, backupMachine.Id()); err != nil {
+		return nil, errors.Annotate(err, "cannot remove old controllers")
+	}
+
 	// update all agents known to the new controller.
 	// TODO(perrito666): We should never stop process because of this.
 	// updateAllMachines will not return errors for individual
@@ -23,6 +23,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
@@ -136,6 +137,67 @@ func updateMachineAddresses(machine *state.Machine, privateAddress, publicAddres
 	return nil
 }
 
+// regenerateControllerCert replaces the controller certificate held in the
+// agent config with one that is valid for the given addresses, signed by
+// the existing CA. A restore onto a fresh instance would otherwise serve
+// a certificate issued for the addresses of the lost controller.
+func regenerateControllerCert(agentConfig agent.ConfigSetter, addresses ...string) error {
+	ssi, ok := agentConfig.StateServingInfo()
+	if !ok {
+		return errors.New("cannot determine state serving info")
+	}
+	if ssi.CAPrivateKey == "" {
+		return errors.New("no CA private key in agent config")
+	}
+	// The certificate is used for both mongo and the API server, so must
+	// include the same well-known names as the certupdater worker does.
+	hostnames := []string{"localhost", "juju-apiserver", "juju-mongodb", "anything"}
+	for _, addr := range addresses {
+		if addr != "" && addr != "localhost" {
+			hostnames = append(hostnames, addr)
+		}
+	}
+	newCert, newKey, err := controller.GenerateControllerCertAndKey(agentConfig.CACert(), ssi.CAPrivateKey, hostnames)
+	if err != nil {
+		return errors.Annotate(err, "cannot generate controller certificate")
+	}
+	ssi.Cert = newCert
+	ssi.PrivateKey = newKey
+	agentConfig.SetStateServingInfo(ssi)
+	return nil
+}
+
+// demoteOtherControllers removes the vote from, and force destroys, every
+// controller machine other than the restored one. After a restore the
+// replica-set holds only the restored member, so any other controllers
+// recorded in the backup cannot rejoin it; once they are gone, enable-ha
+// can be used to grow the cluster again.
+func demoteOtherControllers(st *state.State, restoredId string) error {
+	info, err := st.ControllerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, id := range info.MachineIds {
+		if id == restoredId {
+			continue
+		}
+		machine, err := st.Machine(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("removing controller machine %s, which is not part of the restored replica-set", id)
+		if err := machine.SetHasVote(false); err != nil {
+			return errors.Annotatef(err, "cannot remove vote from machine %s", id)
+		}
+		if err := machine.ForceDestroy(); err != nil {
+			return errors.Annotatef(err, "cannot destroy machine %s", id)
+		}
+	}
+	return nil
+}
+
 // assign to variables for testing purposes.
 var mongoDefaultDialOpts = mongo.DefaultDialOpts
 var environsGetNewPolicyFunc = stateenvirons.GetNewPolicyFunc
@@ -13,10 +13,12 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/replicaset"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	utilscert "github.com/juju/utils/cert"
 	"github.com/juju/utils/ssh"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
@@ -24,6 +26,8 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/mongo/mongotest"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
@@ -256,3 +260,111 @@ func (r *RestoreSuite) TestRunViaSSH(c *gc.C) {
 	expectedOptions.SetKnownHostsFile(os.DevNull)
 	c.Assert(passedOptions, jc.DeepEquals, &expectedOptions)
 }
+
+func (r *RestoreSuite) TestRegenerateControllerCert(c *gc.C) {
+	configParams := agent.AgentConfigParams{
+		Paths: agent.Paths{
+			DataDir: path.Join(r.cwd, "dataDir"),
+			LogDir:  path.Join(r.cwd, "logDir"),
+		},
+		UpgradedToVersion: jujuversion.Current,
+		Tag:               names.NewMachineTag("0"),
+		Controller:        coretesting.ControllerTag,
+		Model:             coretesting.ModelTag,
+		Password:          "placeholder",
+		Nonce:             "dummyNonce",
+		APIAddresses:      []string{"fakeAPIAddress:12345"},
+		CACert:            coretesting.CACert,
+	}
+	servingInfo := params.StateServingInfo{
+		APIPort:        1234,
+		StatePort:      12345,
+		Cert:           coretesting.ServerCert,
+		PrivateKey:     coretesting.ServerKey,
+		CAPrivateKey:   coretesting.CAKey,
+		SharedSecret:   "a secret",
+		SystemIdentity: "an identity",
+	}
+	conf, err := agent.NewStateMachineConfig(configParams, servingInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = regenerateControllerCert(conf, "10.0.0.1", "203.0.113.1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ssi, ok := conf.StateServingInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(ssi.Cert, gc.Not(gc.Equals), coretesting.ServerCert)
+	c.Assert(ssi.PrivateKey, gc.Not(gc.Equals), coretesting.ServerKey)
+	c.Assert(cert.Verify(ssi.Cert, coretesting.CACert, time.Now()), jc.ErrorIsNil)
+
+	x509Cert, err := utilscert.ParseCert(ssi.Cert)
+	c.Assert(err, jc.ErrorIsNil)
+	var ips []string
+	for _, ip := range x509Cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	c.Assert(ips, jc.SameContents, []string{"10.0.0.1", "203.0.113.1"})
+	c.Assert(x509Cert.DNSNames, jc.SameContents, []string{"localhost", "juju-apiserver", "juju-mongodb", "anything"})
+}
+
+func (r *RestoreSuite) TestRegenerateControllerCertNoCAKey(c *gc.C) {
+	configParams := agent.AgentConfigParams{
+		Paths: agent.Paths{
+			DataDir: path.Join(r.cwd, "dataDir"),
+			LogDir:  path.Join(r.cwd, "logDir"),
+		},
+		UpgradedToVersion: jujuversion.Current,
+		Tag:               names.NewMachineTag("0"),
+		Controller:        coretesting.ControllerTag,
+		Model:             coretesting.ModelTag,
+		Password:          "placeholder",
+		Nonce:             "dummyNonce",
+		APIAddresses:      []string{"fakeAPIAddress:12345"},
+		CACert:            coretesting.CACert,
+	}
+	conf, err := agent.NewStateMachineConfig(configParams, params.StateServingInfo{
+		APIPort:    1234,
+		StatePort:  12345,
+		Cert:       coretesting.ServerCert,
+		PrivateKey: coretesting.ServerKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = regenerateControllerCert(conf, "10.0.0.1")
+	c.Assert(err, gc.ErrorMatches, "no CA private key in agent config")
+}
+
+type demoteControllersSuite struct {
+	statetesting.StateSuite
+}
+
+var _ = gc.Suite(&demoteControllersSuite{})
+
+func (s *demoteControllersSuite) TestDemoteOtherControllers(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, id := range []string{"0", "1", "2"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(m.SetHasVote(true), jc.ErrorIsNil)
+	}
+
+	err = demoteOtherControllers(s.State, "1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	restored, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restored.Life(), gc.Equals, state.Alive)
+	c.Assert(restored.HasVote(), jc.IsTrue)
+	c.Assert(restored.WantsVote(), jc.IsTrue)
+
+	for _, id := range []string{"0", "2"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(m.Life(), gc.Equals, state.Dying)
+		c.Check(m.HasVote(), jc.IsFalse)
+		c.Check(m.WantsVote(), jc.IsFalse)
+	}
+}
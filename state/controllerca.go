@@ -0,0 +1,409 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/cert"
+	jujucontroller "github.com/juju/juju/controller"
+)
+
+// caRotationKey is the key for the document, in the controllers
+// collection, which records the progress of a controller CA rotation.
+const caRotationKey = "caRotation"
+
+// CARotationPhase describes how far a controller CA rotation has got.
+type CARotationPhase string
+
+const (
+	// CARotationTrust is the first phase of a CA rotation. Both the old
+	// and the new CA are trusted, and server certificates are still
+	// issued by the old CA. Agents move on to trusting both CAs.
+	CARotationTrust CARotationPhase = "trust"
+
+	// CARotationIssue is the second phase of a CA rotation. Both CAs are
+	// still trusted, but server certificates are now issued by the new
+	// CA. Each controller re-issues its server certificate.
+	CARotationIssue CARotationPhase = "issue"
+)
+
+// controllerCARotationDoc records the progress of a controller CA rotation.
+type controllerCARotationDoc struct {
+	Id              string          `bson:"_id"`
+	Phase           CARotationPhase `bson:"phase"`
+	OldCACert       string          `bson:"old-ca-cert"`
+	NewCACert       string          `bson:"new-ca-cert"`
+	NewCAPrivateKey string          `bson:"new-ca-private-key"`
+
+	// Trusted holds the agents, as model UUID and tag, that trust the
+	// new CA.
+	Trusted []string `bson:"trusted"`
+
+	// Issued holds the ids of the controller machines whose API
+	// server and mongod are both serving certificates issued by the
+	// new CA.
+	Issued []string `bson:"issued"`
+}
+
+// ControllerCARotation describes a controller CA rotation in progress.
+type ControllerCARotation struct {
+	Phase           CARotationPhase
+	OldCACert       string
+	NewCACert       string
+	NewCAPrivateKey string
+	Trusted         []string
+	Issued          []string
+}
+
+// caRotationAgentKey returns the key used to record that the agent
+// with the given tag, in the model with the given UUID, trusts the
+// new CA.
+func caRotationAgentKey(modelUUID string, tag names.Tag) string {
+	return modelUUID + ":" + tag.String()
+}
+
+// ControllerCARotation returns the controller CA rotation in progress.
+// An error satisfying errors.IsNotFound is returned if there is none.
+func (st *State) ControllerCARotation() (ControllerCARotation, error) {
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+
+	var doc controllerCARotationDoc
+	err := controllers.FindId(caRotationKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return ControllerCARotation{}, errors.NotFoundf("controller CA rotation")
+	} else if err != nil {
+		return ControllerCARotation{}, errors.Annotate(err, "cannot get controller CA rotation")
+	}
+	return ControllerCARotation{
+		Phase:           doc.Phase,
+		OldCACert:       doc.OldCACert,
+		NewCACert:       doc.NewCACert,
+		NewCAPrivateKey: doc.NewCAPrivateKey,
+		Trusted:         doc.Trusted,
+		Issued:          doc.Issued,
+	}, nil
+}
+
+// WatchControllerCARotation returns a watcher for observing changes to
+// the controller CA rotation.
+func (st *State) WatchControllerCARotation() NotifyWatcher {
+	return newEntityWatcher(st, controllersC, caRotationKey)
+}
+
+// setControllerCACertOps returns the operations which set the trusted
+// controller CA certificates to the given bundle.
+func (st *State) setControllerCACertOps(bundle string) ([]txn.Op, error) {
+	settings, err := readSettings(st.db(), controllersC, controllerSettingsGlobalKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	settings.Set(jujucontroller.CACertKey, bundle)
+	_, ops := settings.settingsUpdateOps()
+	return ops, nil
+}
+
+// StartControllerCARotation introduces the given CA alongside the
+// current controller CA. Both CAs are trusted from then on, so that
+// agents and clients can learn about the new CA before any server
+// certificate is issued by it.
+func (st *State) StartControllerCARotation(caCert, caKey string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot start controller CA rotation")
+	if _, err := st.ControllerCARotation(); err == nil {
+		return errors.AlreadyExistsf("controller CA rotation")
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	oldCACert, ok := cfg.CACert()
+	if !ok {
+		return errors.New("controller config has no CA certificate")
+	}
+	// The old CA comes first, as it still issues the server certificates.
+	bundle, err := cert.JoinCertBundle(oldCACert, caCert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: txn.DocMissing,
+		Insert: controllerCARotationDoc{
+			Id:              caRotationKey,
+			Phase:           CARotationTrust,
+			OldCACert:       oldCACert,
+			NewCACert:       caCert,
+			NewCAPrivateKey: caKey,
+		},
+	}}
+	settingsOps, err := st.setControllerCACertOps(bundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, settingsOps...)
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.AlreadyExistsf("controller CA rotation")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// SetControllerCATrusted records that the agent with the given tag, in
+// this model, trusts the given CA certificate bundle. Nothing is
+// recorded unless the bundle includes the new CA being rotated to.
+func (st *State) SetControllerCATrusted(tag names.Tag, caCert string) error {
+	rotation, err := st.ControllerCARotation()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	trusted, err := bundleIncludes(caCert, rotation.NewCACert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !trusted {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"new-ca-cert", rotation.NewCACert}},
+		Update: bson.D{{"$addToSet", bson.D{{"trusted", caRotationAgentKey(st.ModelUUID(), tag)}}}},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		// The rotation completed or was replaced; there is nothing
+		// left to record.
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot record trusted controller CA")
+	}
+	return nil
+}
+
+// bundleIncludes reports whether the CA certificate bundle includes the
+// given CA certificate.
+func bundleIncludes(bundle, caCert string) (bool, error) {
+	certs, err := cert.ParseCertBundle(bundle)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	wanted, err := cert.ParseCertBundle(caCert)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, c := range certs {
+		if c.Equal(wanted[0]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetControllerCertIssued records that the API server and mongod of
+// the controller machine with the given id are serving certificates
+// issued by the new CA.
+func (st *State) SetControllerCertIssued(machineId string) error {
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", CARotationIssue}},
+		Update: bson.D{{"$addToSet", bson.D{{"issued", machineId}}}},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("controller CA rotation issuing certificates")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot record issued controller certificate")
+	}
+	return nil
+}
+
+// caasModelUUIDs returns the UUIDs of all CAAS models.
+func (st *State) caasModelUUIDs() (set.Strings, error) {
+	models, closer := st.db().GetRawCollection(modelsC)
+	defer closer()
+	var modelDocs []modelDoc
+	if err := models.Find(bson.D{{"type", ModelTypeCAAS}}).All(&modelDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot get models")
+	}
+	caasModels := set.NewStrings()
+	for _, doc := range modelDocs {
+		caasModels.Add(doc.UUID)
+	}
+	return caasModels, nil
+}
+
+// ControllerCAUntrustingAgents returns the agents, as model UUID and
+// tag, which have yet to trust the new CA of the rotation in progress.
+// All machine agents, the unit agents of IAAS models and the
+// application operators of CAAS models are considered.
+func (st *State) ControllerCAUntrustingAgents() ([]string, error) {
+	rotation, err := st.ControllerCARotation()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	trusted := set.NewStrings(rotation.Trusted...)
+	caasModels, err := st.caasModelUUIDs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var untrusting []string
+	notDead := bson.D{{"life", bson.D{{"$ne", Dead}}}}
+
+	machines, closer := st.db().GetRawCollection(machinesC)
+	defer closer()
+	var machineDocs []machineDoc
+	if err := machines.Find(notDead).All(&machineDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot get machines")
+	}
+	for _, doc := range machineDocs {
+		key := caRotationAgentKey(doc.ModelUUID, names.NewMachineTag(doc.Id))
+		if !trusted.Contains(key) {
+			untrusting = append(untrusting, key)
+		}
+	}
+
+	units, closer := st.db().GetRawCollection(unitsC)
+	defer closer()
+	var unitDocs []unitDoc
+	if err := units.Find(notDead).All(&unitDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot get units")
+	}
+	for _, doc := range unitDocs {
+		if caasModels.Contains(doc.ModelUUID) {
+			continue
+		}
+		key := caRotationAgentKey(doc.ModelUUID, names.NewUnitTag(doc.Name))
+		if !trusted.Contains(key) {
+			untrusting = append(untrusting, key)
+		}
+	}
+
+	if caasModels.IsEmpty() {
+		return untrusting, nil
+	}
+	applications, closer := st.db().GetRawCollection(applicationsC)
+	defer closer()
+	var appDocs []applicationDoc
+	if err := applications.Find(bson.D{
+		{"model-uuid", bson.D{{"$in", caasModels.Values()}}},
+		{"life", bson.D{{"$ne", Dead}}},
+	}).All(&appDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot get applications")
+	}
+	for _, doc := range appDocs {
+		key := caRotationAgentKey(doc.ModelUUID, names.NewApplicationTag(doc.Name))
+		if !trusted.Contains(key) {
+			untrusting = append(untrusting, key)
+		}
+	}
+	return untrusting, nil
+}
+
+// IssueControllerCertsFromNewCA moves the CA rotation in progress on
+// to issuing server certificates from the new CA. Every agent must
+// trust the new CA first.
+func (st *State) IssueControllerCertsFromNewCA() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot issue certificates from the new controller CA")
+	rotation, err := st.ControllerCARotation()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if rotation.Phase != CARotationTrust {
+		return errors.Errorf("controller CA rotation is already in the %q phase", rotation.Phase)
+	}
+	untrusting, err := st.ControllerCAUntrustingAgents()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(untrusting) > 0 {
+		return errors.Errorf("%d agent(s) do not trust the new CA yet", len(untrusting))
+	}
+
+	// The new CA comes first, as it now issues the server certificates.
+	bundle, err := cert.JoinCertBundle(rotation.NewCACert, rotation.OldCACert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", CARotationTrust}},
+		Update: bson.D{{"$set", bson.D{{"phase", CARotationIssue}}}},
+	}, {
+		C:      controllersC,
+		Id:     stateServingInfoKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"caprivatekey", rotation.NewCAPrivateKey}}}},
+	}}
+	settingsOps, err := st.setControllerCACertOps(bundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, settingsOps...)
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("controller CA rotation changed concurrently")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// RetireOldControllerCA completes the CA rotation in progress, after
+// which only the new CA is trusted. Every controller must have had its
+// API server and mongod certificates issued by the new CA first.
+func (st *State) RetireOldControllerCA() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot retire the old controller CA")
+	rotation, err := st.ControllerCARotation()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if rotation.Phase != CARotationIssue {
+		return errors.Errorf("server certificates are not yet issued by the new CA")
+	}
+	info, err := st.ControllerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	issued := set.NewStrings(rotation.Issued...)
+	for _, id := range info.MachineIds {
+		if !issued.Contains(id) {
+			return errors.Errorf("controller machine %s is still using a certificate issued by the old CA", id)
+		}
+	}
+
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", CARotationIssue}, {"issued", bson.D{{"$all", info.MachineIds}}}},
+		Remove: true,
+	}, {
+		C:      controllersC,
+		Id:     modelGlobalKey,
+		Assert: bson.D{{"machineids", info.MachineIds}},
+	}}
+	settingsOps, err := st.setControllerCACertOps(rotation.NewCACert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, settingsOps...)
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("controller CA rotation or controllers changed concurrently")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ControllerCASuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ControllerCASuite{})
+
+func (s *ControllerCASuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	err := s.State.SetStateServingInfo(state.StateServingInfo{
+		APIPort:      1234,
+		StatePort:    4321,
+		Cert:         coretesting.ServerCert,
+		PrivateKey:   coretesting.ServerKey,
+		CAPrivateKey: coretesting.CAKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ControllerCASuite) assertTrustedCAs(c *gc.C, expected ...string) {
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	expectedBundle, err := cert.JoinCertBundle(expected...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caCert, gc.Equals, expectedBundle)
+}
+
+func (s *ControllerCASuite) TestStartRotation(c *gc.C) {
+	err := s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertTrustedCAs(c, coretesting.CACert, coretesting.OtherCACert)
+	rotation, err := s.State.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation, jc.DeepEquals, state.ControllerCARotation{
+		Phase:           state.CARotationTrust,
+		OldCACert:       coretesting.CACert,
+		NewCACert:       coretesting.OtherCACert,
+		NewCAPrivateKey: coretesting.OtherCAKey,
+	})
+
+	err = s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ControllerCASuite) TestNoRotation(c *gc.C) {
+	_, err := s.State.ControllerCARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Agents reporting their CA outside of a rotation is not an error.
+	err = s.State.SetControllerCATrusted(names.NewMachineTag("0"), coretesting.CACert)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ControllerCASuite) TestSetTrusted(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	untrusting, err := s.State.ControllerCAUntrustingAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(untrusting, jc.DeepEquals, []string{s.State.ModelUUID() + ":" + m.Tag().String()})
+
+	// Only knowing the old CA doesn't count.
+	err = s.State.SetControllerCATrusted(m.Tag(), coretesting.CACert)
+	c.Assert(err, jc.ErrorIsNil)
+	untrusting, err = s.State.ControllerCAUntrustingAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(untrusting, gc.HasLen, 1)
+
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	bundle, _ := cfg.CACert()
+	err = s.State.SetControllerCATrusted(m.Tag(), bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	untrusting, err = s.State.ControllerCAUntrustingAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(untrusting, gc.HasLen, 0)
+}
+
+func (s *ControllerCASuite) TestRotation(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.IssueControllerCertsFromNewCA()
+	c.Assert(err, gc.ErrorMatches, `cannot issue certificates from the new controller CA: 1 agent\(s\) do not trust the new CA yet`)
+
+	err = s.State.SetControllerCATrusted(m.Tag(), coretesting.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.IssueControllerCertsFromNewCA()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertTrustedCAs(c, coretesting.OtherCACert, coretesting.CACert)
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.CAPrivateKey, gc.Equals, coretesting.OtherCAKey)
+
+	err = s.State.RetireOldControllerCA()
+	c.Assert(err, gc.ErrorMatches, `cannot retire the old controller CA: controller machine 0 is still using a certificate issued by the old CA`)
+
+	err = s.State.SetControllerCertIssued(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RetireOldControllerCA()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertTrustedCAs(c, coretesting.OtherCACert)
+	_, err = s.State.ControllerCARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ControllerCASuite) TestOperatorsMustTrust(c *gc.C) {
+	caasSt := s.Factory.MakeCAASModel(c, nil)
+	defer caasSt.Close()
+	f := factory.NewFactory(caasSt)
+	ch := f.MakeCharm(c, &factory.CharmParams{Series: "kubernetes"})
+	app := f.MakeApplication(c, &factory.ApplicationParams{Charm: ch})
+
+	err := s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	untrusting, err := s.State.ControllerCAUntrustingAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(untrusting, jc.DeepEquals, []string{caasSt.ModelUUID() + ":" + app.Tag().String()})
+	err = s.State.IssueControllerCertsFromNewCA()
+	c.Assert(err, gc.ErrorMatches, `cannot issue certificates from the new controller CA: 1 agent\(s\) do not trust the new CA yet`)
+
+	err = caasSt.SetControllerCATrusted(app.Tag(), coretesting.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	untrusting, err = s.State.ControllerCAUntrustingAgents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(untrusting, gc.HasLen, 0)
+	err = s.State.IssueControllerCertsFromNewCA()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ControllerCASuite) TestRetireBeforeIssuing(c *gc.C) {
+	err := s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RetireOldControllerCA()
+	c.Assert(err, gc.ErrorMatches, `cannot retire the old controller CA: server certificates are not yet issued by the new CA`)
+	err = s.State.SetControllerCertIssued("0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ControllerCASuite) TestWatchRotation(c *gc.C) {
+	w := s.State.WatchControllerCARotation()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
@@ -42,6 +43,77 @@ func distributeUnit(u *Unit, candidates []instance.Id) ([]instance.Id, error) {
 	return distributor.DistributeInstances(CallContext(u.st), candidates, distributionGroup)
 }
 
+// packUnit takes a unit and set of clean, possibly empty, instances and
+// orders them so that those in the availability zones hosting the most
+// units of the unit's application come first. Instances in zones with
+// no units of the application keep their relative order at the end.
+func packUnit(u *Unit, candidates []instance.Id, machines map[instance.Id]*Machine) ([]instance.Id, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	units, err := allUnits(u.st, u.doc.Application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zoneUnits := make(map[string]int)
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		zone, err := machineAvailabilityZone(u.st, machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if zone != "" {
+			zoneUnits[zone]++
+		}
+	}
+	if len(zoneUnits) == 0 {
+		return candidates, nil
+	}
+
+	candidateUnits := make(map[instance.Id]int)
+	for _, id := range candidates {
+		m, ok := machines[id]
+		if !ok {
+			return nil, errors.Errorf("invalid instance: %v", id)
+		}
+		zone, err := machineAvailabilityZone(u.st, m.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		candidateUnits[id] = zoneUnits[zone]
+	}
+	result := make([]instance.Id, len(candidates))
+	copy(result, candidates)
+	sort.SliceStable(result, func(i, j int) bool {
+		return candidateUnits[result[i]] > candidateUnits[result[j]]
+	})
+	return result, nil
+}
+
+// machineAvailabilityZone returns the availability zone of the instance
+// hosting the specified machine, or an empty string if it is not known.
+func machineAvailabilityZone(st *State, machineId string) (string, error) {
+	host, err := st.Machine(TopParentId(machineId))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	hc, err := host.HardwareCharacteristics()
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if hc.AvailabilityZone == nil {
+		return "", nil
+	}
+	return *hc.AvailabilityZone, nil
+}
+
 // ApplicationInstances returns the instance IDs of provisioned
 // machines that are assigned units of the specified application.
 func ApplicationInstances(st *State, application string) ([]instance.Id, error) {
@@ -10,6 +10,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -182,6 +183,33 @@ func (s *InstanceDistributorSuite) TestDistributeInstancesNoPolicy(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *InstanceDistributorSuite) TestPackInstances(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("placement-strategy=pack"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machines[0])
+	c.Assert(err, jc.ErrorIsNil)
+	for i, zone := range []string{"az1", "az2", "az1"} {
+		zone := zone
+		instId := instance.Id(fmt.Sprintf("i-blah-%d", i))
+		err = s.machines[i].SetProvisioned(instId, "fake-nonce", &instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// The distributor is not consulted when packing; the clean
+	// machine in the zone already hosting wordpress is chosen.
+	s.distributor.err = fmt.Errorf("should not have been invoked")
+	unit, err = s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, s.machines[2].Id())
+	c.Assert(s.distributor.candidates, gc.IsNil)
+}
+
 type ApplicationMachinesSuite struct {
 	ConnSuite
 	wordpress *state.Application
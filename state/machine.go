@@ -139,6 +139,22 @@ type machineDoc struct {
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
 
+	// Maintenance describes the progress of maintenance on the machine.
+	// While it is set, no units may be assigned to the machine or to the
+	// containers it hosts.
+	Maintenance string `bson:",omitempty"`
+
+	// MaintenanceReplacements records the units added elsewhere to
+	// replace those hosted by the machine while it is in maintenance,
+	// keyed by the name of the unit replaced.
+	MaintenanceReplacements map[string]string `bson:",omitempty"`
+
+	// ReplacedInstance holds the ID of the machine's instance while
+	// it is being replaced, and volumes are being detached from it.
+	// The instance is disassociated from the machine once they have
+	// all been detached.
+	ReplacedInstance string `bson:",omitempty"`
+
 	// StopMongoUntilVersion holds the version that must be checked to
 	// know if mongo must be stopped.
 	StopMongoUntilVersion string `bson:",omitempty"`
@@ -1373,6 +1389,12 @@ func (m *Machine) SetInstanceInfo(
 		return errors.Trace(err)
 	}
 
+	// Reattach any volumes that were detached when the machine's
+	// previous instance was replaced.
+	if err := sb.reattachVolumes(m); err != nil {
+		return errors.Trace(err)
+	}
+
 	// Record volumes and volume attachments, and set the initial
 	// status: attached or attaching.
 	if err := setProvisionedVolumeInfo(sb, volumes); err != nil {
@@ -0,0 +1,174 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// machineInMaintenanceErr is returned when a unit cannot be assigned to
+// a machine because the machine, or the machine hosting it, is in
+// maintenance.
+var machineInMaintenanceErr = errors.New("machine is in maintenance")
+
+var (
+	notInMaintenanceDoc = bson.D{{"maintenance", bson.D{{"$exists", false}}}}
+	inMaintenanceDoc    = bson.D{{"maintenance", bson.D{{"$exists", true}}}}
+)
+
+// SetMaintenance puts the machine into maintenance, recording the given
+// message to describe its progress. While the machine is in maintenance
+// no units will be assigned to it or to any container it hosts; units
+// already assigned are not affected. The message may be updated by
+// calling SetMaintenance again.
+func (m *Machine) SetMaintenance(message string) error {
+	if message == "" {
+		return errors.NotValidf("empty maintenance message")
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"maintenance", message}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, ErrDead), "cannot set maintenance on machine %v", m)
+	}
+	m.doc.Maintenance = message
+	return nil
+}
+
+// AddMaintenanceReplacements records units added elsewhere to replace
+// units hosted by the machine, keyed by the name of the unit replaced,
+// so that they are not added again if preparing the machine for
+// maintenance is repeated. The machine must be in maintenance; the
+// replacements are forgotten when maintenance ends.
+func (m *Machine) AddMaintenanceReplacements(replacements map[string]string) error {
+	if len(replacements) == 0 {
+		return nil
+	}
+	var set bson.D
+	for replaced, replacement := range replacements {
+		if !names.IsValidUnit(replaced) {
+			return errors.NotValidf("unit name %q", replaced)
+		}
+		if !names.IsValidUnit(replacement) {
+			return errors.NotValidf("unit name %q", replacement)
+		}
+		set = append(set, bson.DocElem{"maintenancereplacements." + replaced, replacement})
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: append(isAliveDoc, inMaintenanceDoc...),
+		Update: bson.D{{"$set", set}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			if err := m.Refresh(); err != nil {
+				return errors.Annotatef(err, "cannot record replacement units for machine %v", m)
+			}
+			if m.doc.Life != Alive {
+				err = ErrDead
+			} else {
+				err = errors.New("machine is not in maintenance")
+			}
+		}
+		return errors.Annotatef(err, "cannot record replacement units for machine %v", m)
+	}
+	if m.doc.MaintenanceReplacements == nil {
+		m.doc.MaintenanceReplacements = make(map[string]string)
+	}
+	for replaced, replacement := range replacements {
+		m.doc.MaintenanceReplacements[replaced] = replacement
+	}
+	return nil
+}
+
+// MaintenanceReplacements returns the units recorded as replacing units
+// hosted by the machine while it is in maintenance, keyed by the name
+// of the unit replaced.
+func (m *Machine) MaintenanceReplacements() map[string]string {
+	result := make(map[string]string, len(m.doc.MaintenanceReplacements))
+	for replaced, replacement := range m.doc.MaintenanceReplacements {
+		result[replaced] = replacement
+	}
+	return result
+}
+
+// ClearMaintenance takes the machine out of maintenance, so that units
+// may be assigned to it again, and forgets any replacement units
+// recorded for it.
+func (m *Machine) ClearMaintenance() error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{
+			{"maintenance", nil},
+			{"maintenancereplacements", nil},
+		}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, errors.NotFoundf("machine %v", m)), "cannot clear maintenance on machine %v", m)
+	}
+	m.doc.Maintenance = ""
+	m.doc.MaintenanceReplacements = nil
+	return nil
+}
+
+// InMaintenance reports whether the machine is in maintenance.
+func (m *Machine) InMaintenance() bool {
+	return m.doc.Maintenance != ""
+}
+
+// MaintenanceMessage returns the message describing the progress of
+// maintenance on the machine, or "" if it is not in maintenance.
+func (m *Machine) MaintenanceMessage() string {
+	return m.doc.Maintenance
+}
+
+// hostMaintenanceOps returns txn.Ops asserting that the top-level machine
+// hosting the specified container is not in maintenance, or an error with
+// machineInMaintenanceErr as its cause if it is. No ops are returned for
+// a top-level machine; callers assert on its document themselves.
+func hostMaintenanceOps(st *State, machineId string) ([]txn.Op, error) {
+	hostId := TopParentId(machineId)
+	if hostId == machineId {
+		return nil, nil
+	}
+	host, err := st.Machine(hostId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if host.InMaintenance() {
+		return nil, errors.Annotatef(machineInMaintenanceErr, "host machine %s", hostId)
+	}
+	return []txn.Op{{
+		C:      machinesC,
+		Id:     host.doc.DocID,
+		Assert: notInMaintenanceDoc,
+	}}, nil
+}
+
+// checkMaintenance returns an error with machineInMaintenanceErr as its
+// cause if the specified machine, or the machine hosting it, is in
+// maintenance. A machine that does not exist is left for the caller to
+// report.
+func checkMaintenance(st *State, machineId string) error {
+	m, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if m.InMaintenance() {
+		return machineInMaintenanceErr
+	}
+	_, err = hostMaintenanceOps(st, machineId)
+	return errors.Trace(err)
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type MachineMaintenanceSuite struct {
+	ConnSuite
+	wordpress *state.Application
+	machine   *state.Machine
+}
+
+var _ = gc.Suite(&MachineMaintenanceSuite{})
+
+func (s *MachineMaintenanceSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineMaintenanceSuite) addUnit(c *gc.C) *state.Unit {
+	unit, err := s.wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *MachineMaintenanceSuite) TestSetMaintenance(c *gc.C) {
+	c.Assert(s.machine.InMaintenance(), jc.IsFalse)
+	err := s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.InMaintenance(), jc.IsTrue)
+	c.Assert(s.machine.MaintenanceMessage(), gc.Equals, "draining")
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.MaintenanceMessage(), gc.Equals, "draining")
+
+	err = m.ClearMaintenance()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.InMaintenance(), jc.IsFalse)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.InMaintenance(), jc.IsFalse)
+}
+
+func (s *MachineMaintenanceSuite) TestSetMaintenanceEmptyMessage(c *gc.C) {
+	err := s.machine.SetMaintenance("")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *MachineMaintenanceSuite) TestSetMaintenanceDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetMaintenance("draining")
+	c.Assert(err, gc.ErrorMatches, "cannot set maintenance on machine 0: not found or dead")
+}
+
+func (s *MachineMaintenanceSuite) TestAssignToMachineRefused(c *gc.C) {
+	err := s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.addUnit(c)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: machine is in maintenance`)
+}
+
+func (s *MachineMaintenanceSuite) TestAssignToContainerRefused(c *gc.C) {
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.addUnit(c)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0/lxd/0: host machine 0: machine is in maintenance`)
+}
+
+func (s *MachineMaintenanceSuite) TestAssignWithPlacementRefused(c *gc.C) {
+	err := s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.addUnit(c)
+	err = s.State.AssignUnitWithPlacement(unit, &instance.Placement{
+		Scope: string(instance.LXD), Directive: s.machine.Id(),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: machine is in maintenance`)
+
+	// No container was left behind.
+	containers, err := s.machine.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *MachineMaintenanceSuite) TestAssignCleanSkipsMachine(c *gc.C) {
+	err := s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.addUnit(c)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), s.machine.Id())
+}
+
+func (s *MachineMaintenanceSuite) TestAssignAfterClearMaintenance(c *gc.C) {
+	err := s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.ClearMaintenance()
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.addUnit(c)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineMaintenanceSuite) TestAddMaintenanceReplacements(c *gc.C) {
+	err := s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.AddMaintenanceReplacements(map[string]string{"wordpress/0": "wordpress/2"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.AddMaintenanceReplacements(map[string]string{"mysql/0": "mysql/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.MaintenanceReplacements(), jc.DeepEquals, map[string]string{
+		"wordpress/0": "wordpress/2",
+		"mysql/0":     "mysql/1",
+	})
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.MaintenanceReplacements(), jc.DeepEquals, map[string]string{
+		"wordpress/0": "wordpress/2",
+		"mysql/0":     "mysql/1",
+	})
+
+	// The replacements are forgotten when maintenance ends.
+	err = m.ClearMaintenance()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.MaintenanceReplacements(), gc.HasLen, 0)
+}
+
+func (s *MachineMaintenanceSuite) TestAddMaintenanceReplacementsNotInMaintenance(c *gc.C) {
+	err := s.machine.AddMaintenanceReplacements(map[string]string{"wordpress/0": "wordpress/2"})
+	c.Assert(err, gc.ErrorMatches, "cannot record replacement units for machine 0: machine is not in maintenance")
+}
+
+func (s *MachineMaintenanceSuite) TestAddMaintenanceReplacementsInvalidUnit(c *gc.C) {
+	err := s.machine.SetMaintenance("draining")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.AddMaintenanceReplacements(map[string]string{"wordpress/0": "wordpress"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+)
+
+// ReplaceReclaimedMachine replaces the machine with the given id, whose
+// instance has been reclaimed by the cloud, with a new machine created
+// from the same series, constraints and jobs. A new unit is added for
+// each principal unit of the old machine, and staged for assignment to
+// the new machine by the unit assigner, and the old machine is then
+// force-destroyed, along with its units. All of this is done in a single
+// transaction, so either the machine is replaced or nothing changes.
+//
+// Only machines hosting nothing but units of stateless applications (ones
+// whose charms declare no storage) may be replaced; an error satisfying
+// errors.IsNotSupported is returned for any other machine.
+func (st *State) ReplaceReclaimedMachine(id string) (*Machine, error) {
+	m, err := st.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var replacementId string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		newId, ops, err := st.replaceReclaimedMachineOps(m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		replacementId = newId
+		return ops, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.Machine(replacementId)
+}
+
+// replaceReclaimedMachineOps returns the id of the machine that will
+// replace the given machine, and the operations to replace it.
+func (st *State) replaceReclaimedMachineOps(m *Machine) (string, []txn.Op, error) {
+	if m.Life() != Alive {
+		return "", nil, errors.NotSupportedf("replacing %s machine %s", m.Life(), m.Id())
+	}
+	if m.IsManager() {
+		return "", nil, errors.NotSupportedf("replacing controller machine %s", m.Id())
+	}
+	containers, err := m.Containers()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if len(containers) > 0 {
+		return "", nil, errors.NotSupportedf("replacing machine %s hosting containers", m.Id())
+	}
+
+	var apps []*Application
+	for _, name := range m.Principals() {
+		unit, err := st.Unit(name)
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		app, err := unit.Application()
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		ch, _, err := app.Charm()
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		if len(ch.Meta().Storage) > 0 {
+			return "", nil, errors.NotSupportedf(
+				"replacing machine %s hosting unit %s with storage", m.Id(), name,
+			)
+		}
+		apps = append(apps, app)
+	}
+
+	cons, err := m.Constraints()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	mdoc, ops, err := st.addMachineOps(MachineTemplate{
+		Series:      m.Series(),
+		Constraints: cons,
+		Jobs:        m.Jobs(),
+	})
+	if err != nil {
+		return "", nil, errors.Annotatef(err, "adding replacement for machine %s", m.Id())
+	}
+	placement := instance.Placement{
+		Scope:     instance.MachineScope,
+		Directive: mdoc.Id,
+	}
+	for _, app := range apps {
+		unitName, unitOps, err := app.addUnitOps("", AddUnitParams{}, nil)
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		ops = append(ops, unitOps...)
+		ops = append(ops, assignUnitOps(unitName, placement)...)
+	}
+
+	// The old machine must not have changed since it was checked.
+	principalsTerm := bson.DocElem{"principals", m.Principals()}
+	if len(m.Principals()) == 0 {
+		principalsTerm = bson.DocElem{"$or", []bson.D{
+			{{"principals", bson.D{{"$size", 0}}}},
+			{{"principals", bson.D{{"$exists", false}}}},
+		}}
+	}
+	ops = append(ops, txn.Op{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"life", Alive}, principalsTerm},
+	}, txn.Op{
+		C:  containerRefsC,
+		Id: m.doc.DocID,
+		Assert: bson.D{{"$or", []bson.D{
+			{{"children", bson.D{{"$size", 0}}}},
+			{{"children", bson.D{{"$exists", false}}}},
+		}}},
+	})
+	destroyOps, err := m.forceDestroyOps()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	ops = append(ops, destroyOps...)
+	return mdoc.Id, ops, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+type MachineReclaimSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MachineReclaimSuite{})
+
+func (s *MachineReclaimSuite) TestReplaceReclaimedMachine(c *gc.C) {
+	cons := constraints.MustParse("mem=4G instance-lifecycle=spot")
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Constraints: cons,
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	replacement, err := s.State.ReplaceReclaimedMachine(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Id(), gc.Not(gc.Equals), m.Id())
+	c.Assert(replacement.Series(), gc.Equals, "quantal")
+	c.Assert(replacement.Jobs(), jc.DeepEquals, []state.MachineJob{state.JobHostUnits})
+	replacementCons, err := replacement.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacementCons, jc.DeepEquals, cons)
+
+	// The new unit is staged for assignment to the replacement.
+	results, err := s.State.AssignStagedUnits([]string{"wordpress/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.UnitAssignmentResult{{Unit: "wordpress/1"}})
+	err = replacement.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Principals(), jc.DeepEquals, []string{"wordpress/1"})
+
+	// The old machine is on its way out.
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	if !errors.IsNotFound(err) {
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(m.Life(), gc.Not(gc.Equals), state.Alive)
+	}
+}
+
+func (s *MachineReclaimSuite) TestReplaceReclaimedMachineWithStorage(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	ch := s.AddTestingCharm(c, "storage-block")
+	storage := map[string]state.StorageConstraints{
+		"data": makeStorageCons("loop", 1024, 1),
+	}
+	app := s.AddTestingApplicationWithStorage(c, "storage-block", ch, storage)
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.ReplaceReclaimedMachine(m.Id())
+	c.Assert(err, gc.ErrorMatches, `replacing machine 0 hosting unit storage-block/0 with storage not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+}
+
+func (s *MachineReclaimSuite) TestReplaceReclaimedMachineController(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.ReplaceReclaimedMachine(m.Id())
+	c.Assert(err, gc.ErrorMatches, `replacing controller machine 0 not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MachineReclaimSuite) TestReplaceReclaimedMachineUnitAddedConcurrently(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		unit, err := mysql.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	replacement, err := s.State.ReplaceReclaimedMachine(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the second attempt was applied, replacing both units
+	// on a single new machine.
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 2)
+	assignments, err := s.State.AllUnitAssignments()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(assignments, gc.HasLen, 2)
+	var unitNames []string
+	for _, assignment := range assignments {
+		c.Check(assignment.Scope, gc.Equals, "#")
+		c.Check(assignment.Directive, gc.Equals, replacement.Id())
+		unitNames = append(unitNames, assignment.Unit)
+	}
+	results, err := s.State.AssignStagedUnits(unitNames)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, result := range results {
+		c.Check(result.Error, jc.ErrorIsNil)
+	}
+	err = replacement.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Principals(), jc.SameContents, unitNames)
+	c.Assert(unitNames, gc.HasLen, 2)
+}
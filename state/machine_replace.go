@@ -0,0 +1,588 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/status"
+)
+
+// ReplacedInstanceKey is the key in a machine's instance status data
+// that records the ID of the instance that was replaced by a call to
+// ReplaceInstance. The provisioner uses it to stop the old instance
+// before starting a new one.
+const ReplacedInstanceKey = "replaced-instance"
+
+// ReplaceInstance disassociates the machine from its current instance,
+// so that the provisioner will start a new instance for the same machine,
+// preserving the machine's ID, units and storage. This differs from
+// ReplaceReclaimedMachine, which creates a new machine and new units.
+//
+// Attachments of volumes that can be detached are first marked Dying,
+// so that the storage provisioner detaches the volumes from the old
+// instance; they will be reattached when the new instance is recorded.
+// The machine keeps its instance until the last of those attachments
+// has been removed. The machine's instance is then cleared, and its
+// instance status set to Replacing, recording the replaced instance ID;
+// the provisioner will then stop the old instance and start a new one.
+// Storage bound to the machine is reset so that it will be created
+// again with the new instance.
+//
+// Controller machines, containers, machines hosting containers, and
+// manually provisioned machines cannot be replaced; an error satisfying
+// errors.IsNotSupported is returned for them.
+func (m *Machine) ReplaceInstance() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot replace instance of machine %s", m)
+	sb, err := NewStorageBackend(m.st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var (
+		instId      instance.Id
+		replacement *instanceReplacement
+	)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		instId, replacement = "", nil
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.ReplacedInstance != "" {
+			// The machine's volumes are already being
+			// detached from the instance being replaced.
+			return nil, jujutxn.ErrNoOperations
+		}
+		if err := m.checkReplaceable(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		instId, err = m.InstanceId()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// Fail before detaching anything if the machine's
+		// filesystems cannot be reset for the new instance.
+		if _, _, err := sb.replaceMachineFilesystemsOps(m); err != nil {
+			return nil, errors.Trace(err)
+		}
+		detachOps, err := sb.detachReplacedVolumesOps(m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(detachOps) == 0 {
+			// There is nothing to detach, so the
+			// instance can be cleared immediately.
+			var ops []txn.Op
+			ops, replacement, err = m.clearInstanceOps(sb)
+			return ops, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:  machinesC,
+			Id: m.doc.DocID,
+			Assert: append(isAliveDoc,
+				bson.DocElem{"replacedinstance", bson.D{{"$exists", false}}},
+			),
+			Update: bson.D{{"$set", bson.D{{"replacedinstance", string(instId)}}}},
+		}, {
+			C:      instanceDataC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"instanceid", instId}},
+		}}
+		return append(ops, detachOps...), nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	if replacement != nil {
+		return errors.Trace(replacement.setStatus(sb))
+	}
+	if instId == "" {
+		return nil
+	}
+	m.doc.ReplacedInstance = string(instId)
+	if err := m.SetInstanceStatus(status.StatusInfo{
+		Status:  status.Replacing,
+		Message: "detaching volumes from instance " + string(instId),
+	}); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// instanceReplacement records a machine that has been disassociated
+// from its replaced instance, along with the storage that was reset
+// to be created again with the new instance.
+type instanceReplacement struct {
+	machine     *Machine
+	instId      instance.Id
+	volumes     []names.VolumeTag
+	filesystems []names.FilesystemTag
+}
+
+// clearInstanceOps returns txn.Ops to disassociate the machine from its
+// instance, once any detachable volumes have been detached from it.
+func (m *Machine) clearInstanceOps(sb *storageBackend) ([]txn.Op, *instanceReplacement, error) {
+	instId, err := m.InstanceId()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	replacedAssert := bson.DocElem{"replacedinstance", m.doc.ReplacedInstance}
+	if m.doc.ReplacedInstance == "" {
+		replacedAssert.Value = bson.D{{"$exists", false}}
+	}
+	ops := []txn.Op{{
+		C:  machinesC,
+		Id: m.doc.DocID,
+		Assert: append(isAliveDoc,
+			bson.DocElem{"nonce", m.doc.Nonce},
+			replacedAssert,
+		),
+		Update: bson.D{
+			{"$set", bson.D{
+				{"nonce", ""},
+				{"addresses", []address{}},
+				{"machineaddresses", []address{}},
+			}},
+			{"$unset", bson.D{
+				{"preferredpublicaddress", nil},
+				{"preferredprivateaddress", nil},
+				{"replacedinstance", nil},
+			}},
+		},
+	}, {
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"instanceid", instId}},
+		Remove: true,
+	}, {
+		C:      blockDevicesC,
+		Id:     m.doc.Id,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"blockdevices", []BlockDeviceInfo{}}}}},
+	}}
+	devicesOps, err := m.removeAllLinkLayerDevicesOps()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	addressesOps, err := m.removeAllAddressesOps()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	ops = append(ops, devicesOps...)
+	ops = append(ops, addressesOps...)
+
+	volumeOps, resetVolumes, err := sb.replaceMachineVolumesOps(m)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	filesystemOps, resetFilesystems, err := sb.replaceMachineFilesystemsOps(m)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	ops = append(ops, volumeOps...)
+	ops = append(ops, filesystemOps...)
+	return ops, &instanceReplacement{
+		machine:     m,
+		instId:      instId,
+		volumes:     resetVolumes,
+		filesystems: resetFilesystems,
+	}, nil
+}
+
+// setStatus records that the machine is waiting for a new instance
+// to replace the one that was cleared, and that its reset storage is
+// waiting to be created again.
+func (r *instanceReplacement) setStatus(sb *storageBackend) error {
+	m := r.machine
+	m.doc.Nonce = ""
+	m.doc.ReplacedInstance = ""
+	if err := m.SetStatus(status.StatusInfo{Status: status.Pending}); err != nil {
+		return errors.Trace(err)
+	}
+	if err := m.SetInstanceStatus(status.StatusInfo{
+		Status:  status.Replacing,
+		Message: "replacing instance " + string(r.instId),
+		Data: map[string]interface{}{
+			ReplacedInstanceKey: string(r.instId),
+		},
+	}); err != nil {
+		return errors.Trace(err)
+	}
+	for _, tag := range r.volumes {
+		v, err := getVolumeByTag(sb.mb, tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := v.SetStatus(status.StatusInfo{Status: status.Pending}); err != nil {
+			return errors.Annotatef(err, "setting status of %s", names.ReadableString(tag))
+		}
+	}
+	for _, tag := range r.filesystems {
+		f, err := getFilesystemByTag(sb.mb, tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := f.SetStatus(status.StatusInfo{Status: status.Pending}); err != nil {
+			return errors.Annotatef(err, "setting status of %s", names.ReadableString(tag))
+		}
+	}
+	return nil
+}
+
+// replacedInstanceDetachedOps returns txn.Ops to clear the instance of
+// the host machine, if it is being replaced and the attachment of the
+// given volume is the last to be removed from it. The attachment must
+// be removed in the same transaction.
+func (sb *storageBackend) replacedInstanceDetachedOps(host names.Tag, removing names.VolumeTag) (
+	[]txn.Op, *instanceReplacement, error,
+) {
+	if host.Kind() != names.MachineTagKind {
+		return nil, nil, nil
+	}
+	m, err := sb.machine(host.Id())
+	if errors.IsNotFound(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if m.doc.ReplacedInstance == "" || m.Life() != Alive {
+		// The machine's instance is not being replaced, or the
+		// machine is going away, taking the instance with it.
+		return nil, nil, nil
+	}
+	attachments, err := sb.MachineVolumeAttachments(m.MachineTag())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	for _, a := range attachments {
+		if a.Volume() != removing && a.Life() == Dying {
+			// Still detaching.
+			return nil, nil, nil
+		}
+	}
+	return m.clearInstanceOps(sb)
+}
+
+// checkReplaceable returns an error satisfying errors.IsNotSupported
+// if the machine's instance cannot be replaced.
+func (m *Machine) checkReplaceable() error {
+	if m.Life() != Alive {
+		return errors.NotSupportedf("replacing %s machine", m.Life())
+	}
+	if m.IsManager() {
+		return errors.NotSupportedf("replacing controller machine")
+	}
+	if m.IsContainer() {
+		return errors.NotSupportedf("replacing container")
+	}
+	containers, err := m.Containers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(containers) > 0 {
+		return errors.NotSupportedf("replacing machine hosting containers")
+	}
+	manual, err := m.IsManual()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if manual {
+		return errors.NotSupportedf("replacing manually provisioned machine")
+	}
+	return nil
+}
+
+// detachReplacedVolumesOps returns txn.Ops to mark Dying the attachments
+// of detachable volumes to the machine whose instance is being replaced,
+// so that the storage provisioner detaches them from the old instance.
+func (sb *storageBackend) detachReplacedVolumesOps(m *Machine) ([]txn.Op, error) {
+	attachments, err := sb.MachineVolumeAttachments(m.MachineTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	for _, a := range attachments {
+		if a.Life() != Alive {
+			continue
+		}
+		if _, err := a.Info(); errors.IsNotProvisioned(err) {
+			// Not attached yet; it will be attached
+			// to the new instance as usual.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		detachable, err := isDetachableVolumeTag(sb.mb.db(), a.Volume())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if detachable {
+			ops = append(ops, detachVolumeOps(m.MachineTag(), a.Volume())...)
+		}
+	}
+	return ops, nil
+}
+
+// replaceMachineVolumesOps returns txn.Ops to reset the volumes bound to
+// the machine to their provisioning parameters, along with their
+// attachments, so they are created again with the new instance.
+// Detachable volumes have already been detached from the machine.
+func (sb *storageBackend) replaceMachineVolumesOps(m *Machine) ([]txn.Op, []names.VolumeTag, error) {
+	attachments, err := sb.MachineVolumeAttachments(m.MachineTag())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	var reset []names.VolumeTag
+	for _, a := range attachments {
+		if a.Life() != Alive {
+			continue
+		}
+		info, err := a.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		v, err := getVolumeByTag(sb.mb, a.Volume())
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if v.Detachable() {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      volumeAttachmentsC,
+			Id:     volumeAttachmentId(m.Id(), v.doc.Name),
+			Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+			Update: bson.D{
+				{"$set", bson.D{{"params", &VolumeAttachmentParams{ReadOnly: info.ReadOnly}}}},
+				{"$unset", bson.D{{"info", nil}}},
+			},
+		})
+		if volumeInfo, err := v.Info(); err == nil {
+			ops = append(ops, txn.Op{
+				C:      volumesC,
+				Id:     v.doc.Name,
+				Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+				Update: bson.D{
+					{"$set", bson.D{{"params", &VolumeParams{
+						Pool: volumeInfo.Pool,
+						Size: volumeInfo.Size,
+					}}}},
+					{"$unset", bson.D{{"info", nil}}},
+				},
+			})
+			reset = append(reset, v.VolumeTag())
+		}
+	}
+	return ops, reset, nil
+}
+
+// replaceMachineFilesystemsOps returns txn.Ops to reset the filesystem
+// attachments of the machine to their provisioning parameters, so they
+// are mounted again on the new instance. Filesystems whose contents do
+// not survive the replacement of the instance are reset too.
+func (sb *storageBackend) replaceMachineFilesystemsOps(m *Machine) ([]txn.Op, []names.FilesystemTag, error) {
+	attachments, err := sb.MachineFilesystemAttachments(m.MachineTag())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	var reset []names.FilesystemTag
+	for _, a := range attachments {
+		if a.Life() != Alive {
+			continue
+		}
+		info, err := a.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		f, err := getFilesystemByTag(sb.mb, a.Filesystem())
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		volumeTag, err := f.Volume()
+		if err == ErrNoBackingVolume && f.Detachable() {
+			// Filesystems managed by the environ provider are
+			// attached by the model's storage provisioner, which
+			// has no way of learning of the new instance.
+			return nil, nil, errors.NotSupportedf(
+				"replacing machine with %s attached",
+				names.ReadableString(f.FilesystemTag()),
+			)
+		} else if err != nil && err != ErrNoBackingVolume {
+			return nil, nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      filesystemAttachmentsC,
+			Id:     filesystemAttachmentId(m.Id(), f.doc.FilesystemId),
+			Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+			Update: bson.D{
+				{"$set", bson.D{{"params", &FilesystemAttachmentParams{
+					Location: info.MountPoint,
+					ReadOnly: info.ReadOnly,
+				}}}},
+				{"$unset", bson.D{{"info", nil}}},
+			},
+		})
+		if volumeTag != (names.VolumeTag{}) {
+			detachable, err := isDetachableVolumeTag(sb.mb.db(), volumeTag)
+			if err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			if detachable {
+				// The filesystem lives on a volume that will
+				// be reattached to the new instance.
+				continue
+			}
+		}
+		if fsInfo, err := f.Info(); err == nil {
+			ops = append(ops, txn.Op{
+				C:      filesystemsC,
+				Id:     f.doc.FilesystemId,
+				Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+				Update: bson.D{
+					{"$set", bson.D{{"params", &FilesystemParams{
+						Pool: fsInfo.Pool,
+						Size: fsInfo.Size,
+					}}}},
+					{"$unset", bson.D{{"info", nil}}},
+				},
+			})
+			reset = append(reset, f.FilesystemTag())
+		}
+	}
+	return ops, reset, nil
+}
+
+// VolumesToReattach returns the volume attachments that will be created
+// when the machine is next provisioned. These are attachments for the
+// detachable volumes assigned to storage attached to the machine's units,
+// which are not attached to the machine. This is the case after the
+// machine's instance has been replaced by ReplaceInstance.
+func (m *Machine) VolumesToReattach() ([]VolumeAttachment, error) {
+	sb, err := NewStorageBackend(m.st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	templates, err := sb.volumesToReattach(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attachments := make([]VolumeAttachment, len(templates))
+	for i, t := range templates {
+		params := t.params
+		attachments[i] = &volumeAttachment{volumeAttachmentDoc{
+			DocID:     volumeAttachmentId(m.Id(), t.tag.Id()),
+			ModelUUID: m.doc.ModelUUID,
+			Volume:    t.tag.Id(),
+			Host:      m.Id(),
+			Life:      Alive,
+			Params:    &params,
+		}}
+	}
+	return attachments, nil
+}
+
+func (sb *storageBackend) volumesToReattach(m *Machine) ([]volumeAttachmentTemplate, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var templates []volumeAttachmentTemplate
+	for _, u := range units {
+		storageAttachments, err := sb.UnitStorageAttachments(u.UnitTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, sa := range storageAttachments {
+			if sa.Life() != Alive {
+				continue
+			}
+			v, err := sb.storageInstanceVolume(sa.StorageInstance())
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if v.Life() != Alive || !v.Detachable() {
+				continue
+			}
+			if _, err := sb.VolumeAttachment(m.MachineTag(), v.VolumeTag()); err == nil {
+				continue
+			} else if !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+			readOnly, err := sb.storageReadOnly(u, sa.StorageInstance())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			templates = append(templates, volumeAttachmentTemplate{
+				tag:      v.VolumeTag(),
+				params:   VolumeAttachmentParams{ReadOnly: readOnly},
+				existing: true,
+			})
+		}
+	}
+	return templates, nil
+}
+
+// storageReadOnly reports whether the unit's charm requires the
+// specified storage to be attached read-only.
+func (sb *storageBackend) storageReadOnly(u *Unit, tag names.StorageTag) (bool, error) {
+	si, err := sb.storageInstance(tag)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	ch, err := u.charm()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return ch.Meta().Storage[si.StorageName()].ReadOnly, nil
+}
+
+// reattachVolumes attaches the volumes reported by VolumesToReattach
+// to the machine.
+func (sb *storageBackend) reattachVolumes(m *Machine) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		templates, err := sb.volumesToReattach(m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(templates) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops, err := addMachineStorageAttachmentsOps(m, templates, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, t := range templates {
+			ops = append(ops, txn.Op{
+				C:      volumesC,
+				Id:     t.tag.Id(),
+				Assert: isAliveDoc,
+			})
+		}
+		ops = append(ops, createMachineVolumeAttachmentsOps(m.Id(), templates)...)
+		return ops, nil
+	}
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "reattaching volumes to machine %s", m.Id())
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+type MachineReplaceSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&MachineReplaceSuite{})
+
+func (s *MachineReplaceSuite) TestReplaceInstance(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned("inst-0", "nonce-0", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = m.ReplaceInstance()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Life(), gc.Equals, state.Alive)
+	_, err = m.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	c.Assert(m.CheckProvisioned("nonce-0"), jc.IsFalse)
+
+	machineStatus, err := m.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineStatus.Status, gc.Equals, status.Pending)
+	instanceStatus, err := m.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceStatus.Status, gc.Equals, status.Replacing)
+	c.Assert(instanceStatus.Message, gc.Equals, "replacing instance inst-0")
+	c.Assert(instanceStatus.Data, jc.DeepEquals, map[string]interface{}{
+		state.ReplacedInstanceKey: "inst-0",
+	})
+
+	// The machine may now be provisioned again.
+	err = m.SetProvisioned("inst-1", "nonce-1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	instId, err := m.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("inst-1"))
+}
+
+func (s *MachineReplaceSuite) TestReplaceInstanceNotProvisioned(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.ReplaceInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *MachineReplaceSuite) TestReplaceInstanceController(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned("inst-0", "nonce-0", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.ReplaceInstance()
+	c.Assert(err, gc.ErrorMatches, `cannot replace instance of machine 0: replacing controller machine not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MachineReplaceSuite) TestReplaceInstanceHostingContainers(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned("inst-0", "nonce-0", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, m.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.ReplaceInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MachineReplaceSuite) TestReplaceInstanceReattachesDetachableVolumes(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "modelscoped")
+	s.provisionStorageVolume(c, u, storageTag)
+	m := unitMachine(c, s.State, u)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err := m.ReplaceInstance()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume is detached from the old instance before the
+	// instance is cleared.
+	attachment := s.volumeAttachment(c, m.MachineTag(), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Dying)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	instId, err := m.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instId, gc.Equals, instance.Id("inst-id"))
+	instanceStatus, err := m.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceStatus.Status, gc.Equals, status.Replacing)
+	c.Assert(instanceStatus.Message, gc.Equals, "detaching volumes from instance inst-id")
+	c.Assert(instanceStatus.Data, gc.HasLen, 0)
+
+	// Replacing the instance again while detaching does nothing.
+	err = m.ReplaceInstance()
+	c.Assert(err, jc.ErrorIsNil)
+	attachment = s.volumeAttachment(c, m.MachineTag(), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Dying)
+
+	// Once the storage provisioner has detached the volume,
+	// the instance is cleared.
+	err = s.storageBackend.RemoveVolumeAttachment(m.MachineTag(), volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = m.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	instanceStatus, err = m.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceStatus.Status, gc.Equals, status.Replacing)
+	c.Assert(instanceStatus.Data, jc.DeepEquals, map[string]interface{}{
+		state.ReplacedInstanceKey: "inst-id",
+	})
+
+	// The volume survives, but is no longer attached.
+	s.assertVolumeInfo(c, volume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "vol-123",
+		Pool:     "modelscoped",
+	})
+	_, err = s.storageBackend.VolumeAttachment(m.MachineTag(), volume.VolumeTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	attachments, err := m.VolumesToReattach()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 1)
+	c.Assert(attachments[0].Volume(), gc.Equals, volume.VolumeTag())
+	c.Assert(attachments[0].Host(), gc.Equals, m.MachineTag())
+	_, ok := attachments[0].Params()
+	c.Assert(ok, jc.IsTrue)
+
+	// Recording the new instance reattaches the volume.
+	err = m.SetInstanceInfo("inst-1", "nonce-1", nil, nil, nil, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	attachment = s.volumeAttachment(c, m.MachineTag(), volume.VolumeTag())
+	_, ok = attachment.Params()
+	c.Assert(ok, jc.IsTrue)
+	attachments, err = m.VolumesToReattach()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+}
+
+func (s *MachineReplaceSuite) TestReplaceInstanceResetsMachineVolumes(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	s.provisionStorageVolume(c, u, storageTag)
+	m := unitMachine(c, s.State, u)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	err := m.ReplaceInstance()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume will be created again along with the new instance.
+	s.assertVolumeUnprovisioned(c, volume.VolumeTag())
+	attachment := s.volumeAttachment(c, m.MachineTag(), volume.VolumeTag())
+	_, err = attachment.Info()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	_, ok := attachment.Params()
+	c.Assert(ok, jc.IsTrue)
+
+	err = m.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := m.VolumesToReattach()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+}
@@ -170,10 +170,8 @@ func (st *State) exportImpl(cfg ExportConfig) (description.Model, error) {
 		return nil, errors.Trace(err)
 	}
 
-	if dbModel.Type() == ModelTypeIAAS {
-		if err := export.storage(); err != nil {
-			return nil, errors.Trace(err)
-		}
+	if err := export.storage(); err != nil {
+		return nil, errors.Trace(err)
 	}
 
 	// If we are doing a partial export, it doesn't really make sense
@@ -1605,16 +1603,21 @@ func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArg
 		return nil
 	}
 	result := description.ConstraintsArgs{
-		Architecture: optionalString("arch"),
-		Container:    optionalString("container"),
-		CpuCores:     optionalInt("cpucores"),
-		CpuPower:     optionalInt("cpupower"),
-		InstanceType: optionalString("instancetype"),
-		Memory:       optionalInt("mem"),
-		RootDisk:     optionalInt("rootdisk"),
-		Spaces:       optionalStringSlice("spaces"),
-		Tags:         optionalStringSlice("tags"),
-		VirtType:     optionalString("virttype"),
+		Affinity:          optionalStringSlice("affinity"),
+		AntiAffinity:      optionalStringSlice("antiaffinity"),
+		Architecture:      optionalString("arch"),
+		Container:         optionalString("container"),
+		CpuCores:          optionalInt("cpucores"),
+		CpuPower:          optionalInt("cpupower"),
+		InstanceLifecycle: optionalString("instancelifecycle"),
+		InstanceType:      optionalString("instancetype"),
+		Memory:            optionalInt("mem"),
+		PlacementStrategy: optionalString("placementstrategy"),
+		RootDisk:          optionalInt("rootdisk"),
+		Spaces:            optionalStringSlice("spaces"),
+		SpotMaxPrice:      optionalString("spotmaxprice"),
+		Tags:              optionalStringSlice("tags"),
+		VirtType:          optionalString("virttype"),
 	}
 	if optionalErr != nil {
 		return description.ConstraintsArgs{}, errors.Trace(optionalErr)
@@ -1806,8 +1809,15 @@ func (e *exporter) addVolume(vol *volume, volAttachments []volumeAttachmentDoc)
 	for _, doc := range volAttachments {
 		va := volumeAttachment{doc}
 		logger.Debugf("  attachment %#v", doc)
+		m, ok := va.Host().(names.MachineTag)
+		if !ok {
+			// Volumes in CAAS models are attached to units, which
+			// the model description can't represent. The attachment
+			// is recreated on import from the storage attachment.
+			continue
+		}
 		args := description.VolumeAttachmentArgs{
-			Machine: va.Host().(names.MachineTag),
+			Machine: m,
 		}
 		if info, err := va.Info(); err == nil {
 			logger.Debugf("    info %#v", info)
@@ -1909,7 +1919,15 @@ func (e *exporter) addFilesystem(fs *filesystem, fsAttachments []filesystemAttac
 	for _, doc := range fsAttachments {
 		va := filesystemAttachment{doc}
 		logger.Debugf("  attachment %#v", doc)
-		var args description.FilesystemAttachmentArgs
+		m, ok := va.Host().(names.MachineTag)
+		if !ok {
+			// Filesystems in CAAS models are attached to units; see
+			// the comment in addVolume.
+			continue
+		}
+		args := description.FilesystemAttachmentArgs{
+			Machine: m,
+		}
 		if info, err := va.Info(); err == nil {
 			logger.Debugf("    info %#v", info)
 			args.Provisioned = true
@@ -1921,10 +1939,6 @@ func (e *exporter) addFilesystem(fs *filesystem, fsAttachments []filesystemAttac
 			args.ReadOnly = params.ReadOnly
 			args.MountPoint = params.Location
 		}
-		// TODO(caas) - handle non-machine hosts
-		if m, ok := va.Host().(names.MachineTag); ok {
-			args.Machine = m
-		}
 		exFilesystem.AddAttachment(args)
 	}
 	return nil
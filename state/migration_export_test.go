@@ -277,6 +277,33 @@ func (s *MigrationExportSuite) TestMachinesWithVirtConstraint(c *gc.C) {
 	s.assertMachinesMigrated(c, constraints.MustParse("arch=amd64 mem=8G virt-type=kvm"))
 }
 
+func (s *MigrationExportSuite) TestMachinesWithInstanceLifecycleConstraint(c *gc.C) {
+	s.assertMachinesMigrated(c, constraints.MustParse("arch=amd64 mem=8G instance-lifecycle=spot spot-max-price=0.05"))
+}
+
+func (s *MigrationExportSuite) TestApplicationWithAffinityConstraints(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Constraints: constraints.MustParse("affinity=mysql anti-affinity=wordpress,haproxy"),
+	})
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	applications := model.Applications()
+	c.Assert(applications, gc.HasLen, 1)
+	cons := applications[0].Constraints()
+	c.Assert(cons, gc.NotNil)
+	c.Assert(cons.Affinity(), jc.DeepEquals, []string{"mysql"})
+	c.Assert(cons.AntiAffinity(), jc.DeepEquals, []string{"wordpress", "haproxy"})
+}
+
+func (s *MigrationExportSuite) TestModelWithPlacementStrategyConstraint(c *gc.C) {
+	err := s.State.SetModelConstraints(constraints.MustParse("placement-strategy=pack"))
+	c.Assert(err, jc.ErrorIsNil)
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Constraints().PlacementStrategy(), gc.Equals, "pack")
+}
+
 func (s *MigrationExportSuite) assertMachinesMigrated(c *gc.C, cons constraints.Value) {
 	// Add a machine with an LXC container.
 	machine1 := s.Factory.MakeMachine(c, &factory.MachineParams{
@@ -305,6 +332,12 @@ func (s *MigrationExportSuite) assertMachinesMigrated(c *gc.C, cons constraints.
 	if cons.HasVirtType() {
 		c.Assert(constraints.VirtType(), gc.Equals, *cons.VirtType)
 	}
+	if cons.HasInstanceLifecycle() {
+		c.Assert(constraints.InstanceLifecycle(), gc.Equals, *cons.InstanceLifecycle)
+	}
+	if cons.HasSpotMaxPrice() {
+		c.Assert(constraints.SpotMaxPrice(), gc.Equals, *cons.SpotMaxPrice)
+	}
 
 	tools, err := machine1.AgentTools()
 	c.Assert(err, jc.ErrorIsNil)
@@ -40,42 +40,163 @@ import (
 // know how long this is going to be, but we need something.
 var initialLeaderClaimTime = time.Minute
 
-// Import the database agnostic model representation into the database.
-func (st *State) Import(model description.Model) (_ *Model, _ *State, err error) {
+// ValidateImport checks that the database agnostic model representation
+// could be imported into the database. It is used to verify a migration
+// before it is started, and writes nothing: the model's UUID, name,
+// cloud, region, owner and credential are checked against the
+// controller as Import would check them, and the model's contents are
+// checked for anything Import would refuse.
+func (st *State) ValidateImport(model description.Model) error {
+	args, newCredential, err := st.importModelArgs(model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := st.checkNewModelArgs(args, newCredential); err != nil {
+		return errors.Trace(err)
+	}
+	check := importer{
+		model:  model,
+		logger: loggo.GetLogger("juju.state.import-model"),
+	}
+	return errors.Trace(check.validate())
+}
+
+// checkNewModelArgs performs the checks that NewModel would perform
+// for the model args, without creating the model. If newCredential
+// is not nil, it is the model's cloud credential, which Import would
+// create along with the model.
+func (st *State) checkNewModelArgs(args ModelArgs, newCredential *cloud.Credential) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	controllerInfo, err := st.ControllerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if args.Type == ModelTypeIAAS && controllerInfo.CloudName != args.CloudName {
+		return errors.NewNotValid(
+			nil, fmt.Sprintf("controller cloud %s does not match model cloud %s", controllerInfo.CloudName, args.CloudName))
+	}
+	modelCloud, err := st.Cloud(args.CloudName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if args.Type == ModelTypeIAAS {
+		if _, err := validateCloudRegion(modelCloud, args.CloudRegion); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	owner := args.Owner
+	cloudCredentials, err := st.CloudCredentials(owner, args.CloudName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if newCredential != nil {
+		credential := convertCloudCredentialToState(args.CloudCredential, *newCredential)
+		if _, err := validateCloudCredentials(modelCloud, map[names.CloudCredentialTag]Credential{
+			args.CloudCredential: credential,
+		}); err != nil {
+			return errors.Annotate(err, "validating cloud credentials")
+		}
+		cloudCredentials[args.CloudCredential.Id()] = credential
+	}
+	if _, err := validateCloudCredential(modelCloud, cloudCredentials, args.CloudCredential); err != nil {
+		return errors.Trace(err)
+	}
+
+	if owner.IsLocal() {
+		if _, err := st.User(owner); err != nil {
+			return errors.Annotate(err, "cannot create model")
+		}
+	}
+
+	name := args.Config.Name()
+	models, closer := st.db().GetCollection(modelsC)
+	defer closer()
+	modelCount, err := models.Find(bson.D{
+		{"owner", owner.Id()},
+		{"name", name}},
+	).Count()
+	if err != nil {
+		return errors.Trace(err)
+	} else if modelCount > 0 {
+		return errors.AlreadyExistsf("model %q for %s", name, owner.Id())
+	}
+	return nil
+}
+
+// checkImportCredential checks that the cloud credential from an
+// imported model description matches any existing credential with
+// the same id. It returns the credential's tag and whether the
+// credential already exists.
+func (st *State) checkImportCredential(creds description.CloudCredential) (names.CloudCredentialTag, bool, error) {
+	// TODO: there really should be a way to create a cloud credential
+	// tag in the names package from the cloud, owner and name.
+	credID := fmt.Sprintf("%s/%s/%s", creds.Cloud(), creds.Owner(), creds.Name())
+	if !names.IsValidCloudCredential(credID) {
+		return names.CloudCredentialTag{}, false, errors.Errorf("model credentials id not valid: %q", credID)
+	}
+	credTag := names.NewCloudCredentialTag(credID)
+
+	existingCreds, err := st.CloudCredential(credTag)
+	if errors.IsNotFound(err) {
+		return credTag, false, nil
+	} else if err != nil {
+		return names.CloudCredentialTag{}, false, errors.Trace(err)
+	}
+	// Ensure existing creds match.
+	if existingCreds.AuthType != creds.AuthType() {
+		return names.CloudCredentialTag{}, false, errors.Errorf(
+			"credential auth type mismatch: %q != %q", existingCreds.AuthType, creds.AuthType())
+	}
+	if !reflect.DeepEqual(existingCreds.Attributes, creds.Attributes()) {
+		return names.CloudCredentialTag{}, false, errors.Errorf(
+			"credential attribute mismatch: %v != %v", existingCreds.Attributes, creds.Attributes())
+	}
+	if existingCreds.Revoked {
+		return names.CloudCredentialTag{}, false, errors.Errorf("credential %q is revoked", credID)
+	}
+	return credTag, true, nil
+}
+
+// importModelArgs checks the model representation against the
+// controller, and returns the args with which to create the model.
+// If the model's cloud credential does not yet exist, it is returned
+// so that it can be created along with the model.
+func (st *State) importModelArgs(model description.Model) (ModelArgs, *cloud.Credential, error) {
 	modelUUID := model.Tag().Id()
-	logger := loggo.GetLogger("juju.state.import-model")
-	logger.Debugf("import starting for model %s", modelUUID)
 
 	// At this stage, attempting to import a model with the same
 	// UUID as an existing model will error.
 	if modelExists, err := st.ModelExists(modelUUID); err != nil {
-		return nil, nil, errors.Trace(err)
+		return ModelArgs{}, nil, errors.Trace(err)
 	} else if modelExists {
 		// We have an existing matching model.
-		return nil, nil, errors.AlreadyExistsf("model %s", modelUUID)
+		return ModelArgs{}, nil, errors.AlreadyExistsf("model %s", modelUUID)
 	}
 
 	if len(model.RemoteApplications()) != 0 {
 		// Cross-model relations are currently limited to models on
 		// the same controller, while migration is for getting the
 		// model to a new controller.
-		return nil, nil, errors.New("can't import models with remote applications")
+		return ModelArgs{}, nil, errors.New("can't import models with remote applications")
 	}
 
 	// Unfortunately a version was released that exports v4 models
 	// with the Type field blank. Treat this as IAAS.
 	modelType := ModelTypeIAAS
 	if model.Type() != "" {
+		var err error
 		modelType, err = ParseModelType(model.Type())
 		if err != nil {
-			return nil, nil, errors.Trace(err)
+			return ModelArgs{}, nil, errors.Trace(err)
 		}
 	}
 
-	// Create the model.
 	cfg, err := config.New(config.NoDefaults, model.Config())
 	if err != nil {
-		return nil, nil, errors.Trace(err)
+		return ModelArgs{}, nil, errors.Trace(err)
 	}
 	args := ModelArgs{
 		Type:                    modelType,
@@ -87,43 +208,40 @@ func (st *State) Import(model description.Model) (_ *Model, _ *State, err error)
 		EnvironVersion:          model.EnvironVersion(),
 		StorageProviderRegistry: storage.StaticProviderRegistry{},
 	}
+	var newCredential *cloud.Credential
 	if creds := model.CloudCredential(); creds != nil {
 		// Need to add credential or make sure an existing credential
 		// matches.
-		// TODO: there really should be a way to create a cloud credential
-		// tag in the names package from the cloud, owner and name.
-		credID := fmt.Sprintf("%s/%s/%s", creds.Cloud(), creds.Owner(), creds.Name())
-		if !names.IsValidCloudCredential(credID) {
-			return nil, nil, errors.Errorf("model credentials id not valid: %q", credID)
+		credTag, exists, err := st.checkImportCredential(creds)
+		if err != nil {
+			return ModelArgs{}, nil, errors.Trace(err)
 		}
-		credTag := names.NewCloudCredentialTag(credID)
-
-		existingCreds, err := st.CloudCredential(credTag)
-
-		if errors.IsNotFound(err) {
+		if !exists {
 			credential := cloud.NewCredential(
 				cloud.AuthType(creds.AuthType()),
 				creds.Attributes())
-			if err := st.UpdateCloudCredential(credTag, credential); err != nil {
-				return nil, nil, errors.Trace(err)
-			}
-		} else if err != nil {
-			return nil, nil, errors.Trace(err)
-		} else {
-			// ensure existing creds match
-			if existingCreds.AuthType != creds.AuthType() {
-				return nil, nil, errors.Errorf("credential auth type mismatch: %q != %q", existingCreds.AuthType, creds.AuthType())
-			}
-			if !reflect.DeepEqual(existingCreds.Attributes, creds.Attributes()) {
-				return nil, nil, errors.Errorf("credential attribute mismatch: %v != %v", existingCreds.Attributes, creds.Attributes())
-			}
-			if existingCreds.Revoked {
-				return nil, nil, errors.Errorf("credential %q is revoked", credID)
-			}
+			newCredential = &credential
 		}
-
 		args.CloudCredential = credTag
 	}
+	return args, newCredential, nil
+}
+
+// Import the database agnostic model representation into the database.
+func (st *State) Import(model description.Model) (_ *Model, _ *State, err error) {
+	modelUUID := model.Tag().Id()
+	logger := loggo.GetLogger("juju.state.import-model")
+	logger.Debugf("import starting for model %s", modelUUID)
+
+	args, newCredential, err := st.importModelArgs(model)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if newCredential != nil {
+		if err := st.UpdateCloudCredential(args.CloudCredential, *newCredential); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
 	dbModel, newSt, err := st.NewModel(args)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
@@ -197,10 +315,8 @@ func (st *State) Import(model description.Model) (_ *Model, _ *State, err error)
 		return nil, nil, errors.Annotate(err, "ipaddresses")
 	}
 
-	if dbModel.Type() == ModelTypeIAAS {
-		if err := restore.storage(); err != nil {
-			return nil, nil, errors.Annotate(err, "storage")
-		}
+	if err := restore.storage(); err != nil {
+		return nil, nil, errors.Annotate(err, "storage")
 	}
 
 	// NOTE: at the end of the import make sure that the mode of the model
@@ -238,6 +354,49 @@ type importer struct {
 	applicationUnits map[string]map[string]*Unit
 }
 
+// importBlockTypes maps the block names in a model description to the
+// blocks they switch on.
+var importBlockTypes = map[string]BlockType{
+	"destroy-model": DestroyBlock,
+	"remove-object": RemoveBlock,
+	"all-changes":   ChangeBlock,
+}
+
+// validate checks the model's contents for anything that the import
+// would refuse, without touching the database. Errors are annotated
+// as the import would annotate them.
+func (i *importer) validate() error {
+	for blockName := range i.model.Blocks() {
+		if _, ok := importBlockTypes[blockName]; !ok {
+			return errors.Annotate(
+				errors.Errorf("unknown block type: %q", blockName),
+				"base model aspects",
+			)
+		}
+	}
+	if err := i.validateMachines(i.model.Machines()); err != nil {
+		return errors.Annotate(err, "machines")
+	}
+	for _, a := range i.model.Applications() {
+		if _, err := charm.ParseURL(a.CharmURL()); err != nil {
+			return errors.Annotate(err, "applications")
+		}
+	}
+	return nil
+}
+
+func (i *importer) validateMachines(machines []description.Machine) error {
+	for _, m := range machines {
+		if _, err := i.makeMachineJobs(m.Jobs()); err != nil {
+			return errors.Annotatef(err, "machine %s", m.Id())
+		}
+		if err := i.validateMachines(m.Containers()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 func (i *importer) modelExtras() error {
 	if latest := i.model.LatestToolsVersion(); latest != version.Zero {
 		if err := i.dbModel.UpdateLatestToolsVersion(latest); err != nil {
@@ -251,14 +410,8 @@ func (i *importer) modelExtras() error {
 		}
 	}
 
-	blockType := map[string]BlockType{
-		"destroy-model": DestroyBlock,
-		"remove-object": RemoveBlock,
-		"all-changes":   ChangeBlock,
-	}
-
 	for blockName, message := range i.model.Blocks() {
-		block, ok := blockType[blockName]
+		block, ok := importBlockTypes[blockName]
 		if !ok {
 			return errors.Errorf("unknown block type: %q", blockName)
 		}
@@ -1562,6 +1715,12 @@ func (i *importer) constraints(cons description.Constraints) constraints.Value {
 		return result
 	}
 
+	if affinity := cons.Affinity(); len(affinity) > 0 {
+		result.Affinity = &affinity
+	}
+	if antiAffinity := cons.AntiAffinity(); len(antiAffinity) > 0 {
+		result.AntiAffinity = &antiAffinity
+	}
 	if arch := cons.Architecture(); arch != "" {
 		result.Arch = &arch
 	}
@@ -1574,18 +1733,27 @@ func (i *importer) constraints(cons description.Constraints) constraints.Value {
 	if power := cons.CpuPower(); power != 0 {
 		result.CpuPower = &power
 	}
+	if lifecycle := cons.InstanceLifecycle(); lifecycle != "" {
+		result.InstanceLifecycle = &lifecycle
+	}
 	if inst := cons.InstanceType(); inst != "" {
 		result.InstanceType = &inst
 	}
 	if mem := cons.Memory(); mem != 0 {
 		result.Mem = &mem
 	}
+	if strategy := cons.PlacementStrategy(); strategy != "" {
+		result.PlacementStrategy = &strategy
+	}
 	if disk := cons.RootDisk(); disk != 0 {
 		result.RootDisk = &disk
 	}
 	if spaces := cons.Spaces(); len(spaces) > 0 {
 		result.Spaces = &spaces
 	}
+	if price := cons.SpotMaxPrice(); price != "" {
+		result.SpotMaxPrice = &price
+	}
 	if tags := cons.Tags(); len(tags) > 0 {
 		result.Tags = &tags
 	}
@@ -1742,7 +1910,7 @@ func (i *importer) volumes() error {
 	i.logger.Debugf("importing volumes")
 	sb, err := NewStorageBackend(i.st)
 	if err != nil {
-		return errors.NewNotSupported(err, "adding volumes to CAAS model")
+		return errors.Trace(err)
 	}
 	for _, volume := range i.model.Volumes() {
 		err := i.addVolume(volume, sb)
@@ -1758,6 +1926,11 @@ func (i *importer) volumes() error {
 func (i *importer) addVolume(volume description.Volume, sb *storageBackend) error {
 	attachments := volume.Attachments()
 	tag := volume.Tag()
+	unitId := i.unitStorageHostId(volume.Storage(), len(attachments))
+	attachmentCount := len(attachments)
+	if unitId != "" {
+		attachmentCount = 1
+	}
 	var params *VolumeParams
 	var info *VolumeInfo
 	if volume.Provisioned() {
@@ -1781,10 +1954,12 @@ func (i *importer) addVolume(volume description.Volume, sb *storageBackend) erro
 		// Life: ..., // TODO: import life, default is Alive
 		Params:          params,
 		Info:            info,
-		AttachmentCount: len(attachments),
+		AttachmentCount: attachmentCount,
 	}
 	if detachable, err := isDetachableVolumePool(sb, volume.Pool()); err != nil {
 		return errors.Trace(err)
+	} else if !detachable && unitId != "" {
+		doc.HostId = unitId
 	} else if !detachable && len(attachments) == 1 {
 		doc.HostId = attachments[0].Machine().Id()
 	}
@@ -1794,6 +1969,18 @@ func (i *importer) addVolume(volume description.Volume, sb *storageBackend) erro
 	for _, attachment := range attachments {
 		ops = append(ops, i.addVolumeAttachmentOp(tag.Id(), attachment))
 	}
+	if unitId != "" {
+		ops = append(ops, txn.Op{
+			C:      volumeAttachmentsC,
+			Id:     volumeAttachmentId(unitId, tag.Id()),
+			Assert: txn.DocMissing,
+			Insert: &volumeAttachmentDoc{
+				Volume: tag.Id(),
+				Host:   unitId,
+				Params: &VolumeAttachmentParams{},
+			},
+		})
+	}
 
 	if err := i.st.db().RunTransaction(ops); err != nil {
 		return errors.Trace(err)
@@ -1839,7 +2026,7 @@ func (i *importer) filesystems() error {
 	i.logger.Debugf("importing filesystems")
 	sb, err := NewStorageBackend(i.st)
 	if err != nil {
-		return errors.NewNotSupported(err, "adding filesystems to CAAS model")
+		return errors.Trace(err)
 	}
 	for _, fs := range i.model.Filesystems() {
 		err := i.addFilesystem(fs, sb)
@@ -1856,6 +2043,11 @@ func (i *importer) addFilesystem(filesystem description.Filesystem, sb *storageB
 
 	attachments := filesystem.Attachments()
 	tag := filesystem.Tag()
+	unitId := i.unitStorageHostId(filesystem.Storage(), len(attachments))
+	attachmentCount := len(attachments)
+	if unitId != "" {
+		attachmentCount = 1
+	}
 	var params *FilesystemParams
 	var info *FilesystemInfo
 	if filesystem.Provisioned() {
@@ -1877,10 +2069,12 @@ func (i *importer) addFilesystem(filesystem description.Filesystem, sb *storageB
 		// Life: ..., // TODO: import life, default is Alive
 		Params:          params,
 		Info:            info,
-		AttachmentCount: len(attachments),
+		AttachmentCount: attachmentCount,
 	}
 	if detachable, err := isDetachableFilesystemPool(sb, filesystem.Pool()); err != nil {
 		return errors.Trace(err)
+	} else if !detachable && unitId != "" {
+		doc.HostId = unitId
 	} else if !detachable && len(attachments) == 1 {
 		doc.HostId = attachments[0].Machine().Id()
 	}
@@ -1890,6 +2084,21 @@ func (i *importer) addFilesystem(filesystem description.Filesystem, sb *storageB
 	for _, attachment := range attachments {
 		ops = append(ops, i.addFilesystemAttachmentOp(tag.Id(), attachment))
 	}
+	if unitId != "" {
+		// The attachment info is filled in again by the
+		// CAAS unit provisioner once the target controller
+		// sees the unit's pod.
+		ops = append(ops, txn.Op{
+			C:      filesystemAttachmentsC,
+			Id:     filesystemAttachmentId(unitId, tag.Id()),
+			Assert: txn.DocMissing,
+			Insert: &filesystemAttachmentDoc{
+				Filesystem: tag.Id(),
+				Host:       unitId,
+				Params:     &FilesystemAttachmentParams{},
+			},
+		})
+	}
 
 	if err := i.st.db().RunTransaction(ops); err != nil {
 		return errors.Trace(err)
@@ -1931,6 +2140,27 @@ func (i *importer) addFilesystemAttachmentOp(fsID string, attachment description
 	}
 }
 
+// unitStorageHostId returns the id of the unit a CAAS model's volume
+// or filesystem is attached to. The model description only records
+// machine attachments, so for CAAS models the attachment is recovered
+// from the unit the storage instance is attached to. An empty string
+// is returned if there is no such unit, or the model isn't CAAS.
+func (i *importer) unitStorageHostId(storageTag names.StorageTag, machineAttachments int) string {
+	if i.dbModel.Type() != ModelTypeCAAS || machineAttachments > 0 || storageTag.Id() == "" {
+		return ""
+	}
+	for _, storage := range i.model.Storages() {
+		if storage.Tag() != storageTag {
+			continue
+		}
+		if units := storage.Attachments(); len(units) == 1 {
+			return units[0].Id()
+		}
+		break
+	}
+	return ""
+}
+
 func (i *importer) storagePools() error {
 	registry, err := i.st.storageProviderRegistry()
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/yaml.v2"
 
+	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/payload"
@@ -61,6 +62,82 @@ func (s *MigrationImportSuite) TestExisting(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
 }
 
+func (s *MigrationImportSuite) TestValidateImportExisting(c *gc.C) {
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ValidateImport(out)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MigrationImportSuite) TestValidateImportDoesNotImport(c *gc.C) {
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	uuid := utils.MustNewUUID().String()
+	err = s.State.ValidateImport(newModel(out, uuid, "new"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	exists, err := s.State.ModelExists(uuid)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+}
+
+func (s *MigrationImportSuite) TestValidateImportUnknownBlock(c *gc.C) {
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	uuid := utils.MustNewUUID().String()
+	err = s.State.ValidateImport(&badBlocksModel{newModel(out, uuid, "new")})
+	c.Assert(err, gc.ErrorMatches, `base model aspects: unknown block type: "bogus"`)
+
+	exists, err := s.State.ModelExists(uuid)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+}
+
+func (s *MigrationImportSuite) TestValidateImportNameClash(c *gc.C) {
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	uuid := utils.MustNewUUID().String()
+	err = s.State.ValidateImport(newModel(out, uuid, s.Model.Name()))
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(`model %q for %s already exists`, s.Model.Name(), s.Owner.Id()))
+}
+
+func (s *MigrationImportSuite) TestValidateImportDoesNotAddCredential(c *gc.C) {
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	credTag := names.NewCloudCredentialTag(fmt.Sprintf("dummy/%s/validate", s.Owner.Id()))
+	out.SetCloudCredential(description.CloudCredentialArgs{
+		Owner:    s.Owner,
+		Cloud:    names.NewCloudTag("dummy"),
+		Name:     "validate",
+		AuthType: string(cloud.EmptyAuthType),
+	})
+
+	uuid := utils.MustNewUUID().String()
+	err = s.State.ValidateImport(newModel(out, uuid, "new"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.CloudCredential(credTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MigrationImportSuite) TestValidateImportUnknownMachineJob(c *gc.C) {
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	out.AddMachine(description.MachineArgs{
+		Id:   names.NewMachineTag("42"),
+		Jobs: []string{"bogus"},
+	})
+
+	uuid := utils.MustNewUUID().String()
+	err = s.State.ValidateImport(newModel(out, uuid, "new"))
+	c.Assert(err, gc.ErrorMatches, `machines: machine 42: unknown machine job: "bogus"`)
+}
+
 func (s *MigrationImportSuite) importModel(c *gc.C, st *state.State, transform ...func(map[string]interface{})) (*state.Model, *state.State) {
 	out, err := st.Export()
 	c.Assert(err, jc.ErrorIsNil)
@@ -249,6 +326,21 @@ func (s *MigrationImportSuite) TestModelUsers(c *gc.C) {
 	c.Assert(allUsers, gc.HasLen, 3)
 }
 
+func (s *MigrationImportSuite) TestApplicationAccessNotMigrated(c *gc.C) {
+	// Access granted on individual applications has no representation
+	// in the model description, so it is deliberately not migrated. It
+	// must be granted again on the target controller.
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "mysql"})
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	err := s.State.CreateApplicationAccess(app.ApplicationTag(), user.UserTag(), permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, newSt := s.importModel(c, s.State)
+	users, err := newSt.GetApplicationUsers("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 0)
+}
+
 func (s *MigrationImportSuite) TestSLA(c *gc.C) {
 	err := s.State.SetSLA("essential", "bob", []byte("creds"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -381,6 +473,23 @@ func (s *MigrationImportSuite) TestMachines(c *gc.C) {
 	c.Assert(newCons.String(), gc.Equals, cons.String())
 }
 
+func (s *MigrationImportSuite) TestMachinesWithInstanceLifecycleConstraint(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64 mem=8G instance-lifecycle=spot spot-max-price=0.05")
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: cons,
+	})
+
+	_, newSt := s.importModel(c, s.State)
+
+	importedMachines, err := newSt.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(importedMachines, gc.HasLen, 1)
+	newCons, err := importedMachines[0].Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	// Can't test the constraints directly, so go through the string repr.
+	c.Assert(newCons.String(), gc.Equals, cons.String())
+}
+
 func (s *MigrationImportSuite) TestMachineDevices(c *gc.C) {
 	machine := s.Factory.MakeMachine(c, nil)
 	// Create two devices, first with all fields set, second just to show that
@@ -539,6 +648,27 @@ func (s *MigrationImportSuite) TestApplications(c *gc.C) {
 	s.assertImportedApplication(c, application, pwd, cons, exported, newModel, newSt)
 }
 
+func (s *MigrationImportSuite) TestApplicationsWithPlacementConstraints(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64 mem=8G affinity=mysql anti-affinity=wordpress placement-strategy=spread")
+	charm, application, pwd := s.setupSourceApplications(c, s.State, cons)
+
+	allApplications, err := s.State.AllApplications()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(allApplications, gc.HasLen, 1)
+	exported := allApplications[0]
+
+	newModel, newSt := s.importModel(c, s.State)
+	// Manually copy across the charm from the old model
+	// as it's normally done later.
+	f := factory.NewFactory(newSt)
+	f.MakeCharm(c, &factory.CharmParams{
+		Name:     "starsay", // it has resources
+		URL:      charm.URL().String(),
+		Revision: strconv.Itoa(charm.Revision()),
+	})
+	s.assertImportedApplication(c, application, pwd, cons, exported, newModel, newSt)
+}
+
 func (s *MigrationImportSuite) TestCAASApplications(c *gc.C) {
 	caasSt := s.Factory.MakeCAASModel(c, nil)
 	s.AddCleanup(func(_ *gc.C) { caasSt.Close() })
@@ -1392,6 +1522,58 @@ func (s *MigrationImportSuite) TestFilesystems(c *gc.C) {
 	c.Check(attParams.ReadOnly, jc.IsTrue)
 }
 
+func (s *MigrationImportSuite) TestCAASFilesystems(c *gc.C) {
+	caasSt := s.Factory.MakeCAASModel(c, nil)
+	s.AddCleanup(func(_ *gc.C) { caasSt.Close() })
+	unit := factory.NewFactory(caasSt).MakeUnit(c, nil)
+
+	out, err := caasSt.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// CAAS filesystems are attached to units, so the exported
+	// filesystem has no machine attachments; the attachment is
+	// recovered from the storage instance.
+	storageTag := names.NewStorageTag("data/0")
+	fsTag := names.NewFilesystemTag("0")
+	out.AddStorage(description.StorageArgs{
+		Tag:         storageTag,
+		Kind:        "filesystem",
+		Owner:       unit.UnitTag(),
+		Name:        "data",
+		Attachments: []names.UnitTag{unit.UnitTag()},
+	})
+	fs := out.AddFilesystem(description.FilesystemArgs{
+		Tag:          fsTag,
+		Storage:      storageTag,
+		Provisioned:  true,
+		Size:         1024,
+		Pool:         "rootfs",
+		FilesystemID: "pvc-data-0",
+	})
+	fs.SetStatus(description.StatusArgs{
+		Value:   string(status.Attached),
+		Updated: coretesting.ZeroTime(),
+	})
+
+	uuid := utils.MustNewUUID().String()
+	_, newSt, err := caasSt.Import(newModel(out, uuid, "new"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { c.Check(newSt.Close(), jc.ErrorIsNil) })
+
+	newSb, err := state.NewStorageBackend(newSt)
+	c.Assert(err, jc.ErrorIsNil)
+	filesystem, err := newSb.Filesystem(fsTag)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := filesystem.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.FilesystemId, gc.Equals, "pvc-data-0")
+
+	attachment, err := newSb.FilesystemAttachment(unit.UnitTag(), fsTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, needsProvisioning := attachment.Params()
+	c.Check(needsProvisioning, jc.IsTrue)
+}
+
 func (s *MigrationImportSuite) TestStorage(c *gc.C) {
 	app, u, storageTag := s.makeUnitWithStorage(c)
 	sb, err := state.NewStorageBackend(s.State)
@@ -1827,6 +2009,15 @@ func (m *mockModel) Config() map[string]interface{} {
 	return c
 }
 
+// badBlocksModel reports a block that can't be imported.
+type badBlocksModel struct {
+	description.Model
+}
+
+func (m *badBlocksModel) Blocks() map[string]string {
+	return map[string]string{"bogus": "no"}
+}
+
 // swapModel will swap the order of the applications appearing in the
 // model.
 type swapModel struct {
@@ -24,6 +24,10 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		modelsC,
 		modelUsersC,
 		modelUserLastConnectionC,
+		// Model access is migrated with the model's users. Access
+		// granted on individual applications is deliberately not
+		// migrated, as the model description cannot represent it;
+		// see TestApplicationAccessNotMigrated.
 		permissionsC,
 		settingsC,
 		sequenceC,
@@ -104,6 +108,11 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Users aren't migrated.
 		usersC,
 		userLastLoginC,
+		// User groups are controller global, not migrated.
+		userGroupsC,
+		userGroupClaimsC,
+		// API tokens are controller global, not migrated.
+		apiTokensC,
 		// Controller users contain extra data about users therefore
 		// are not migrated either.
 		controllerUsersC,
@@ -307,6 +316,14 @@ func (s *MigrationSuite) TestMachineDocFields(c *gc.C) {
 		// Ignored at this stage, could be an issue if mongo 3.0 isn't
 		// available.
 		"StopMongoUntilVersion",
+		// Maintenance is an operator's transient hold on the machine,
+		// not carried across to the target controller.
+		"Maintenance",
+		"MaintenanceReplacements",
+		// ReplacedInstance is only set while volumes are detached
+		// from an instance being replaced; the instance is
+		// disassociated from the machine once they are.
+		"ReplacedInstance",
 	)
 	migrated := set.NewStrings(
 		"Addresses",
@@ -552,6 +569,11 @@ func (s *MigrationSuite) TestConstraintsDocFields(c *gc.C) {
 		"Tags",
 		"Spaces",
 		"VirtType",
+		"InstanceLifecycle",
+		"SpotMaxPrice",
+		"Affinity",
+		"AntiAffinity",
+		"PlacementStrategy",
 	)
 	s.AssertExportedFields(c, constraintsDoc{}, fields)
 }
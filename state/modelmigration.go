@@ -530,6 +530,22 @@ func (mig *modelMigration) minionReportId(phase migration.Phase, globalKey strin
 }
 
 func (mig *modelMigration) getAllAgents() (names.Set, error) {
+	model, err := mig.st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if model.Type() == ModelTypeCAAS {
+		// Units in CAAS models don't run their own agents; the
+		// application operators are the migration minions.
+		appTags, err := mig.loadAgentTags(applicationsC, "name",
+			func(name string) names.Tag { return names.NewApplicationTag(name) },
+		)
+		if err != nil {
+			return nil, errors.Annotate(err, "loading application names")
+		}
+		return appTags, nil
+	}
+
 	machineTags, err := mig.loadAgentTags(machinesC, "machineid",
 		func(id string) names.Tag { return names.NewMachineTag(id) },
 	)
@@ -845,6 +861,8 @@ func agentTagToGlobalKey(tag names.Tag) (string, error) {
 		return machineGlobalKey(t.Id()), nil
 	case names.UnitTag:
 		return unitAgentGlobalKey(t.Id()), nil
+	case names.ApplicationTag:
+		return applicationGlobalKey(t.Id()), nil
 	default:
 		return "", errors.Errorf("%s is not an agent tag", tag)
 	}
@@ -861,6 +879,8 @@ func globalKeyToAgentTag(key string) (names.Tag, error) {
 		return names.NewMachineTag(keyId), nil
 	case "u":
 		return names.NewUnitTag(keyId), nil
+	case "a":
+		return names.NewApplicationTag(keyId), nil
 	default:
 		return nil, errors.NotValidf("global key type %q", keyType)
 	}
@@ -662,6 +662,31 @@ func (s *MigrationSuite) TestMinionReports(c *gc.C) {
 	c.Check(reports.Unknown, jc.SameContents, []names.Tag{m2.Tag()})
 }
 
+func (s *MigrationSuite) TestMinionReportsCAAS(c *gc.C) {
+	caasSt := s.Factory.MakeCAASModel(c, nil)
+	s.AddCleanup(func(*gc.C) { caasSt.Close() })
+
+	// Units of CAAS applications don't have agents, so only the
+	// application operators are expected to report.
+	caasFactory := factory.NewFactory(caasSt)
+	ch := caasFactory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", Series: "kubernetes"})
+	app0 := caasFactory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress", Charm: ch})
+	caasFactory.MakeUnit(c, &factory.UnitParams{Application: app0})
+	app1 := caasFactory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress2", Charm: ch})
+
+	mig, err := caasSt.CreateMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	const phase = migration.QUIESCE
+	c.Assert(mig.SubmitMinionReport(app0.Tag(), phase, true), jc.ErrorIsNil)
+
+	reports, err := mig.MinionReports()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(reports.Succeeded, jc.SameContents, []names.Tag{app0.Tag()})
+	c.Check(reports.Failed, gc.HasLen, 0)
+	c.Check(reports.Unknown, jc.SameContents, []names.Tag{app1.Tag()})
+}
+
 func (s *MigrationSuite) TestDuplicateMinionReportsSameSuccess(c *gc.C) {
 	// It should be OK for a minion report to arrive more than once
 	// for the same migration, agent and phase as long as the value of
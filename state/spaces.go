@@ -4,6 +4,9 @@
 package state
 
 import (
+	"strings"
+
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2"
@@ -247,3 +250,321 @@ func (s *Space) Refresh() error {
 	s.doc = doc
 	return nil
 }
+
+// spaceUsageDoc holds the fields of the endpoint bindings and constraints
+// documents that may refer to a space.
+type spaceUsageDoc struct {
+	DocID    string      `bson:"_id"`
+	Bindings bindingsMap `bson:"bindings,omitempty"`
+	Spaces   []string    `bson:"spaces,omitempty"`
+	TxnRevno int64       `bson:"txn-revno"`
+}
+
+// describeGlobalKey returns a human readable description of the entity
+// identified by the local global key.
+func describeGlobalKey(key string) string {
+	switch {
+	case key == modelGlobalKey:
+		return "the model"
+	case strings.HasPrefix(key, "a#"):
+		return "application " + strings.TrimPrefix(key, "a#")
+	case strings.HasPrefix(key, "m#"):
+		return "machine " + strings.TrimPrefix(key, "m#")
+	}
+	return key
+}
+
+// spaceBindings returns the endpoint bindings documents which bind at
+// least one endpoint to the named space.
+func (st *State) spaceBindings(name string) ([]spaceUsageDoc, error) {
+	bindingsCollection, closer := st.db().GetCollection(endpointBindingsC)
+	defer closer()
+
+	var docs, result []spaceUsageDoc
+	if err := bindingsCollection.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get endpoint bindings")
+	}
+	for _, doc := range docs {
+		for _, space := range doc.Bindings {
+			if space == name {
+				result = append(result, doc)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// spaceConstraints returns the constraints documents which include or
+// exclude the named space.
+func (st *State) spaceConstraints(name string) ([]spaceUsageDoc, error) {
+	constraintsCollection, closer := st.db().GetCollection(constraintsC)
+	defer closer()
+
+	var docs []spaceUsageDoc
+	query := bson.D{{"spaces", bson.D{{"$in", []string{name, "^" + name}}}}}
+	if err := constraintsCollection.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get constraints")
+	}
+	return docs, nil
+}
+
+// checkSpaceNotInControllerConfig returns an error if the named space is
+// used by the controller configuration of the controller model.
+func (st *State) checkSpaceNotInControllerConfig(name string) error {
+	if !st.IsController() {
+		return nil
+	}
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for key, value := range map[string]string{
+		"juju-ha-space":   cfg.JujuHASpace(),
+		"juju-mgmt-space": cfg.JujuManagementSpace(),
+	} {
+		if value == name {
+			return errors.Errorf("space %q is used as %s in the controller config", name, key)
+		}
+	}
+	return nil
+}
+
+// spaceSubnetsOps returns the operations that move the non-FAN subnets of
+// the space from one name to another. FAN subnets follow their underlay.
+func (st *State) spaceSubnetsOps(from, to string) ([]txn.Op, error) {
+	subnetsCollection, closer := st.db().GetCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	query := bson.D{{"space-name", from}, {"fan-local-underlay", bson.D{{"$exists", false}}}}
+	if err := subnetsCollection.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets of space %q", from)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      subnetsC,
+			Id:     doc.CIDR,
+			Assert: bson.D{{"space-name", from}},
+			Update: bson.D{{"$set", bson.D{{"space-name", to}}}},
+		}
+	}
+	return ops, nil
+}
+
+// RenameSpace renames the space, updating the subnets, endpoint bindings
+// and constraints which refer to it.
+func (st *State) RenameSpace(from, to string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot rename space %q to %q", from, to)
+	if !names.IsValidSpace(to) {
+		return errors.NewNotValid(nil, "invalid space name")
+	}
+	if err := st.checkSpaceNotInControllerConfig(from); err != nil {
+		return errors.Trace(err)
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		space, err := st.Space(from)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if space.Life() != Alive {
+			return nil, spaceNotAliveErr
+		}
+		if _, err := st.Space(to); err == nil {
+			return nil, errors.AlreadyExistsf("space %q", to)
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+
+		newDoc := space.doc
+		newDoc.Name = to
+		ops := []txn.Op{{
+			C:      spacesC,
+			Id:     from,
+			Assert: isAliveDoc,
+			Remove: true,
+		}, {
+			C:      spacesC,
+			Id:     to,
+			Assert: txn.DocMissing,
+			Insert: newDoc,
+		}}
+
+		subnetOps, err := st.spaceSubnetsOps(from, to)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, subnetOps...)
+
+		bindings, err := st.spaceBindings(from)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, doc := range bindings {
+			newBindings := make(bindingsMap)
+			for endpoint, space := range doc.Bindings {
+				if space == from {
+					space = to
+				}
+				newBindings[endpoint] = space
+			}
+			ops = append(ops, txn.Op{
+				C:      endpointBindingsC,
+				Id:     doc.DocID,
+				Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+				Update: bson.D{{"$set", bson.D{{"bindings", newBindings}}}},
+			})
+		}
+
+		constraints, err := st.spaceConstraints(from)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, doc := range constraints {
+			newSpaces := make([]string, len(doc.Spaces))
+			for i, space := range doc.Spaces {
+				switch space {
+				case from:
+					space = to
+				case "^" + from:
+					space = "^" + to
+				}
+				newSpaces[i] = space
+			}
+			ops = append(ops, txn.Op{
+				C:      constraintsC,
+				Id:     doc.DocID,
+				Assert: bson.D{{"spaces", doc.Spaces}},
+				Update: bson.D{{"$set", bson.D{{"spaces", newSpaces}}}},
+			})
+		}
+		return ops, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// RemoveSpace removes the space, moving its subnets to the default space.
+// A space which is used by endpoint bindings, constraints or the
+// controller config cannot be removed.
+func (st *State) RemoveSpace(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove space %q", name)
+	if err := st.checkSpaceNotInControllerConfig(name); err != nil {
+		return errors.Trace(err)
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		space, err := st.Space(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		bindings, err := st.spaceBindings(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		constraints, err := st.spaceConstraints(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var users []string
+		for _, doc := range bindings {
+			users = append(users, describeGlobalKey(st.localID(doc.DocID)))
+		}
+		for _, doc := range constraints {
+			users = append(users, describeGlobalKey(st.localID(doc.DocID))+" constraints")
+		}
+		if len(users) > 0 {
+			return nil, errors.Errorf("space is used by %s", strings.Join(users, ", "))
+		}
+
+		ops := []txn.Op{{
+			C:      spacesC,
+			Id:     name,
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		if space.ProviderId() != "" {
+			ops = append(ops, st.networkEntityGlobalKeyRemoveOp("space", space.ProviderId()))
+		}
+		subnetOps, err := st.spaceSubnetsOps(name, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, subnetOps...), nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// UpdateSpace replaces the subnets of the space with the given subnets.
+// Subnets which leave the space move to the default space. The subnets
+// of spaces discovered from the provider cannot be changed.
+func (st *State) UpdateSpace(name string, subnets []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update space %q", name)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		space, err := st.Space(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if space.Life() != Alive {
+			return nil, spaceNotAliveErr
+		}
+		if space.ProviderId() != "" {
+			return nil, errors.NotSupportedf("changing the subnets of provider space %q", name)
+		}
+
+		wanted := set.NewStrings(subnets...)
+		ops := []txn.Op{{
+			C:      spacesC,
+			Id:     name,
+			Assert: isAliveDoc,
+		}}
+		current, err := st.spaceSubnetsOps(name, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, op := range current {
+			if !wanted.Contains(op.Id.(string)) {
+				ops = append(ops, op)
+			}
+		}
+
+		for _, cidr := range wanted.SortedValues() {
+			subnet, err := st.Subnet(cidr)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if subnet.FanLocalUnderlay() != "" {
+				return nil, errors.Errorf("cannot set space for FAN subnet %q - it is always inherited from underlay", cidr)
+			}
+			from := subnet.SpaceName()
+			if from == name {
+				continue
+			}
+			if from != "" {
+				fromSpace, err := st.Space(from)
+				if err != nil && !errors.IsNotFound(err) {
+					return nil, errors.Trace(err)
+				}
+				if err == nil && fromSpace.ProviderId() != "" {
+					return nil, errors.Errorf("subnet %q belongs to provider space %q", cidr, from)
+				}
+			}
+			// Subnets in the default space may not have a space-name.
+			assertSpace := bson.DocElem{"space-name", from}
+			if from == "" {
+				assertSpace.Value = bson.D{{"$in", []interface{}{nil, ""}}}
+			}
+			ops = append(ops, txn.Op{
+				C:      subnetsC,
+				Id:     cidr,
+				Assert: bson.D{assertSpace, {"fan-local-underlay", bson.D{{"$exists", false}}}},
+				Update: bson.D{{"$set", bson.D{{"space-name", name}}}},
+			})
+		}
+		return ops, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
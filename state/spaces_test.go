@@ -11,6 +11,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
@@ -593,3 +594,132 @@ func (s *SpacesSuite) TestFanSubnetInheritsSpace(c *gc.C) {
 	c.Assert(foundSubnet, gc.NotNil)
 	c.Assert(foundSubnet.SpaceName(), gc.Equals, "space1")
 }
+
+func (s *SpacesSuite) TestRenameSpaceMovesSubnetsBindingsAndConstraints(c *gc.C) {
+	_, err := s.addSpaceWithSubnets(c, addSpaceArgs{Name: "db", SubnetCIDRs: []string{"10.0.0.0/24"}})
+	c.Assert(err, jc.ErrorIsNil)
+	ch := s.AddMetaCharm(c, "mysql", metaBase, 42)
+	app := s.AddTestingApplicationWithBindings(c, "yoursql", ch, map[string]string{"server": "db"})
+	err = app.SetConstraints(constraints.MustParse("spaces=^db"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RenameSpace("db", "database")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertSpaceNotFound(c, "db")
+	space, err := s.State.Space("database")
+	c.Assert(err, jc.ErrorIsNil)
+	subnet, err := s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, space.Name())
+
+	bindings, err := app.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings["server"], gc.Equals, "database")
+	cons, err := app.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*cons.Spaces, jc.DeepEquals, []string{"^database"})
+}
+
+func (s *SpacesSuite) TestRenameSpaceToExistingName(c *gc.C) {
+	s.addAliveSpace(c, "one")
+	s.addAliveSpace(c, "two")
+
+	err := s.State.RenameSpace("one", "two")
+	c.Assert(err, gc.ErrorMatches, `cannot rename space "one" to "two": space "two" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *SpacesSuite) TestRenameSpaceNotFound(c *gc.C) {
+	err := s.State.RenameSpace("missing", "other")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SpacesSuite) TestRemoveSpaceMovesSubnetsToDefaultSpace(c *gc.C) {
+	_, err := s.addSpaceWithSubnets(c, addSpaceArgs{Name: "gone", SubnetCIDRs: []string{"10.0.0.0/24"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveSpace("gone")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertSpaceNotFound(c, "gone")
+	subnet, err := s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+}
+
+func (s *SpacesSuite) TestRemoveSpaceUsedByBindings(c *gc.C) {
+	s.addAliveSpace(c, "db")
+	ch := s.AddMetaCharm(c, "mysql", metaBase, 42)
+	s.AddTestingApplicationWithBindings(c, "yoursql", ch, map[string]string{"server": "db"})
+
+	err := s.State.RemoveSpace("db")
+	c.Assert(err, gc.ErrorMatches, `cannot remove space "db": space is used by application yoursql`)
+	_, err = s.State.Space("db")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SpacesSuite) TestRemoveSpaceUsedByConstraints(c *gc.C) {
+	s.addAliveSpace(c, "db")
+	err := s.State.SetModelConstraints(constraints.MustParse("spaces=db"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveSpace("db")
+	c.Assert(err, gc.ErrorMatches, `cannot remove space "db": space is used by the model constraints`)
+}
+
+func (s *SpacesSuite) TestUpdateSpaceReplacesSubnets(c *gc.C) {
+	_, err := s.addSpaceWithSubnets(c, addSpaceArgs{Name: "one", SubnetCIDRs: []string{"10.0.0.0/24", "10.0.1.0/24"}})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.addSpaceWithSubnets(c, addSpaceArgs{Name: "two", SubnetCIDRs: []string{"10.0.2.0/24"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.UpdateSpace("two", []string{"10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	for cidr, spaceName := range map[string]string{
+		"10.0.0.0/24": "one",
+		"10.0.1.0/24": "two",
+		"10.0.2.0/24": "",
+	} {
+		subnet, err := s.State.Subnet(cidr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(subnet.SpaceName(), gc.Equals, spaceName, gc.Commentf("subnet %s", cidr))
+	}
+}
+
+func (s *SpacesSuite) TestUpdateSpaceSubnetInDefaultSpace(c *gc.C) {
+	s.addSubnets(c, []string{"10.0.0.0/24"})
+	s.addAliveSpace(c, "one")
+
+	err := s.State.UpdateSpace("one", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	subnet, err := s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "one")
+}
+
+func (s *SpacesSuite) TestUpdateProviderSpaceFails(c *gc.C) {
+	_, err := s.addSpaceWithSubnets(c, addSpaceArgs{Name: "provider", ProviderId: "p-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addSubnets(c, []string{"10.0.0.0/24"})
+
+	err = s.State.UpdateSpace("provider", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot update space "provider": changing the subnets of provider space "provider" not supported`)
+}
+
+func (s *SpacesSuite) TestUpdateSpaceWithSubnetFromProviderSpaceFails(c *gc.C) {
+	_, err := s.addSpaceWithSubnets(c, addSpaceArgs{Name: "provider", ProviderId: "p-1", SubnetCIDRs: []string{"10.0.0.0/24"}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addAliveSpace(c, "mine")
+
+	err = s.State.UpdateSpace("mine", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot update space "mine": subnet "10.0.0.0/24" belongs to provider space "provider"`)
+}
+
+func (s *SpacesSuite) TestUpdateSpaceSubnetNotFound(c *gc.C) {
+	s.addAliveSpace(c, "mine")
+
+	err := s.State.UpdateSpace("mine", []string{"10.9.0.0/24"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
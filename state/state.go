@@ -272,6 +272,15 @@ func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// And all user permissions for the model's applications.
+	appPermPattern := bson.M{
+		"_id": bson.M{"$regex": "^" + applicationPermissionGlobalKey + "#" + modelUUID + "#"},
+	}
+	appPermOps, err := st.removeInCollectionOps(permissionsC, appPermPattern)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, appPermOps...)
 	err = st.db().RunTransaction(ops)
 	if err != nil {
 		return errors.Trace(err)
@@ -1340,6 +1349,9 @@ func (st *State) processIAASModelApplicationArgs(args *AddApplicationArgs) error
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := validateAffinityConstraints(args.Name, args.Constraints); err != nil {
+		return errors.Trace(err)
+	}
 
 	for _, placement := range args.Placement {
 		data, err := st.parsePlacement(placement)
@@ -1523,6 +1535,22 @@ func (st *State) AssignUnitWithPlacement(unit *Unit, placement *instance.Placeme
 	// TODO(natefinch) this should be done as a single transaction, not two.
 	// Mark https://launchpad.net/bugs/1506994 fixed when done.
 
+	// Check maintenance and anti-affinity before creating any
+	// container, so that a refused placement does not leave an
+	// empty one behind.
+	data, err := st.parsePlacement(placement)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if data.machineId != "" {
+		if err := checkMaintenance(st, data.machineId); err != nil {
+			return errors.Annotatef(err, "cannot assign unit %q to machine %s", unit.Name(), data.machineId)
+		}
+		if _, err := unit.checkAntiAffinity(data.machineId); err != nil {
+			return errors.Annotatef(err, "cannot assign unit %q to machine %s", unit.Name(), data.machineId)
+		}
+	}
+
 	m, err := st.addMachineWithPlacement(unit, placement)
 	if err != nil {
 		return errors.Trace(err)
@@ -2118,11 +2146,17 @@ func (st *State) AssignUnit(u *Unit, policy AssignmentPolicy) (err error) {
 		}
 		return u.AssignToMachine(m)
 	case AssignClean:
+		if _, err = u.assignToAffineMachine(); errors.Cause(err) != noAffineMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanMachine(); errors.Cause(err) != noCleanMachines {
 			return errors.Trace(err)
 		}
 		return u.AssignToNewMachineOrContainer()
 	case AssignCleanEmpty:
+		if _, err = u.assignToAffineMachine(); errors.Cause(err) != noAffineMachines {
+			return errors.Trace(err)
+		}
 		if _, err = u.AssignToCleanEmptyMachine(); errors.Cause(err) != noCleanMachines {
 			return errors.Trace(err)
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/juju/juju/caas"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
@@ -151,9 +152,14 @@ func NewStorageProviderRegistryForModel(
 }
 
 // NewStorageProviderRegistry returns a storage.ProviderRegistry that chains
-// the provided registry with the common storage providers.
+// the provided registry with the common storage providers and the KVM
+// storage provider, used to add storage to KVM guests.
 func NewStorageProviderRegistry(reg storage.ProviderRegistry) storage.ProviderRegistry {
-	return storage.ChainedProviderRegistry{reg, provider.CommonStorageProviders()}
+	return storage.ChainedProviderRegistry{
+		reg,
+		provider.CommonStorageProviders(),
+		kvm.StorageProviders(),
+	}
 }
 
 func environProvider(st *state.State) (environs.EnvironProvider, error) {
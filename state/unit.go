@@ -1562,7 +1562,19 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 				return nil, errors.Trace(err)
 			}
 		}
-		return u.assignToMachineOps(m, unused)
+		var antiAffinityOps []txn.Op
+		if u.doc.MachineId == "" {
+			var err error
+			antiAffinityOps, err = u.checkAntiAffinity(m.Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ops, err := u.assignToMachineOps(m, unused)
+		if err != nil {
+			return nil, err
+		}
+		return append(ops, antiAffinityOps...), nil
 	}
 	if err := u.st.db().Run(buildTxn); err != nil {
 		return errors.Trace(err)
@@ -1578,6 +1590,7 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
+// - machineInMaintenanceErr when the machine or its host is in maintenance.
 func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if u.Life() != Alive {
 		return nil, unitNotAliveErr
@@ -1591,6 +1604,13 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	if unused && !m.doc.Clean {
 		return nil, inUseErr
 	}
+	if m.InMaintenance() {
+		return nil, machineInMaintenanceErr
+	}
+	hostOps, err := hostMaintenanceOps(u.st, m.doc.Id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	storageParams, err := u.storageParams()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1634,7 +1654,7 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 			{{"machineid", m.Id()}},
 		},
 	}}...)
-	massert := isAliveDoc
+	massert := append(isAliveDoc, notInMaintenanceDoc...)
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
@@ -1651,6 +1671,7 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	},
 		removeStagedAssignmentOp(u.doc.DocID),
 	}
+	ops = append(ops, hostOps...)
 	ops = append(ops, storageOps...)
 	return ops, nil
 }
@@ -1899,11 +1920,19 @@ func (u *Unit) assignToNewMachineOps(
 		if len(containers) > 0 {
 			return nil, nil, machineNotCleanErr
 		}
+		if mparent.InMaintenance() {
+			return nil, nil, machineInMaintenanceErr
+		}
+		hostOps, err := hostMaintenanceOps(u.st, parentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		ops = append(ops, hostOps...)
 		parentDocId := u.st.docID(parentId)
 		ops = append(ops, txn.Op{
 			C:      machinesC,
 			Id:     parentDocId,
-			Assert: bson.D{{"clean", true}},
+			Assert: append(bson.D{{"clean", true}}, notInMaintenanceDoc...),
 		}, txn.Op{
 			C:      containerRefsC,
 			Id:     parentDocId,
@@ -2001,9 +2030,11 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 		return ops, err
 	}
 	if err := u.st.db().Run(buildTxn); err != nil {
-		if errors.Cause(err) == machineNotCleanErr {
-			// The clean machine was used before we got a chance
-			// to use it so just stick the unit on a new machine.
+		switch errors.Cause(err) {
+		case machineNotCleanErr, machineInMaintenanceErr:
+			// The clean machine was used, or put into maintenance,
+			// before we got a chance to use it so just stick the
+			// unit on a new machine.
 			return u.AssignToNewMachine()
 		}
 		return errors.Trace(err)
@@ -2351,6 +2382,7 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		{"clean", true},
 		{"machineid", bson.D{{"$nin", machinesWithContainers}}},
 	}
+	terms = append(terms, notInMaintenanceDoc...)
 	// Add the container filter term if necessary.
 	var containerType instance.ContainerType
 	if cons.Container != nil {
@@ -2362,13 +2394,22 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		terms = append(terms, bson.DocElem{"containertype", string(containerType)})
 	}
 
-	// Find the ids of machines which satisfy any required hardware
-	// constraints. If there is no instanceData for a machine, that
-	// machine is not considered as suitable for deploying the unit.
-	// This can happen if the machine is not yet provisioned. It may
-	// be that when the machine is provisioned it will be found to
-	// be suitable, but we don't know that right now and it's best
-	// to err on the side of caution and exclude such machines.
+	hardwareTerms, err := hardwareConstraintsTerms(db, cons)
+	if err != nil {
+		return nil, err
+	}
+	return append(terms, hardwareTerms...), nil
+}
+
+// hardwareConstraintsTerms returns machine query terms selecting the
+// machines that satisfy any required hardware constraints. If there is
+// no instanceData for a machine, that machine is not considered as
+// suitable for deploying the unit. This can happen if the machine is
+// not yet provisioned. It may be that when the machine is provisioned
+// it will be found to be suitable, but we don't know that right now
+// and it's best to err on the side of caution and exclude such
+// machines.
+func hardwareConstraintsTerms(db Database, cons *constraints.Value) (bson.D, error) {
 	var suitableInstanceData []instanceData
 	var suitableTerms bson.D
 	if cons.Arch != nil && *cons.Arch != "" {
@@ -2389,20 +2430,20 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	if cons.Tags != nil && len(*cons.Tags) > 0 {
 		suitableTerms = append(suitableTerms, bson.DocElem{"tags", bson.D{{"$all", *cons.Tags}}})
 	}
-	if len(suitableTerms) > 0 {
-		instanceDataCollection, closer := db.GetCollection(instanceDataC)
-		defer closer()
-		err := instanceDataCollection.Find(suitableTerms).Select(bson.M{"_id": 1}).All(&suitableInstanceData)
-		if err != nil {
-			return nil, err
-		}
-		var suitableIds = make([]string, len(suitableInstanceData))
-		for i, m := range suitableInstanceData {
-			suitableIds[i] = m.DocID
-		}
-		terms = append(terms, bson.DocElem{"_id", bson.D{{"$in", suitableIds}}})
+	if len(suitableTerms) == 0 {
+		return nil, nil
+	}
+	instanceDataCollection, closer := db.GetCollection(instanceDataC)
+	defer closer()
+	err := instanceDataCollection.Find(suitableTerms).Select(bson.M{"_id": 1}).All(&suitableInstanceData)
+	if err != nil {
+		return nil, err
+	}
+	var suitableIds = make([]string, len(suitableInstanceData))
+	for i, m := range suitableInstanceData {
+		suitableIds[i] = m.DocID
 	}
-	return terms, nil
+	return bson.D{{"_id", bson.D{{"$in", suitableIds}}}}, nil
 }
 
 // assignToCleanMaybeEmptyMachine implements AssignToCleanMachine and AssignToCleanEmptyMachine.
@@ -2484,6 +2525,11 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 		assignContextf(&err, u.Name(), context)
 		return failure(err)
 	}
+	antiAffine, err := antiAffineApplications(u.st, u.doc.Application, cons)
+	if err != nil {
+		assignContextf(&err, u.Name(), context)
+		return failure(err)
+	}
 
 	// Find all of the candidate machines, and associated
 	// instances for those that are provisioned. Instances
@@ -2514,13 +2560,20 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 	}
 
 	// Filter the list of instances that are suitable for
-	// distribution, and then map them back to machines.
+	// distribution, and then map them back to machines. When
+	// packing, the instances are instead ordered to favour the
+	// zones already hosting the application.
 	//
 	// TODO(axw) 2014-05-30 #1324904
 	// Shuffle machines to reduce likelihood of collisions.
 	// The partition of provisioned/unprovisioned machines
 	// must be maintained.
-	if instances, err = distributeUnit(u, instances); err != nil {
+	if cons.HasPlacementStrategy() && *cons.PlacementStrategy == constraints.PlacementPack {
+		instances, err = packUnit(u, instances, instanceMachines)
+	} else {
+		instances, err = distributeUnit(u, instances)
+	}
+	if err != nil {
 		assignContextf(&err, u.Name(), context)
 		return failure(err)
 	}
@@ -2544,6 +2597,15 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 	// provisioned without the fact having yet been recorded
 	// in state.
 	for _, m := range machines {
+		// Skip machines whose host runs units of applications
+		// the unit must not be placed alongside.
+		antiAffinityOps, err := checkAntiAffinity(u.st, m.Id(), antiAffine)
+		if errors.Cause(err) == antiAffinityErr {
+			continue
+		} else if err != nil {
+			assignContextf(&err, u.Name(), context)
+			return failure(err)
+		}
 		// Check that the unit storage is compatible with
 		// the machine in question.
 		if err := validateDynamicMachineStorageParams(m, storageParams); err != nil {
@@ -2555,10 +2617,10 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 		}
 		ops, err := u.assignToMachineOps(m, true)
 		if err == nil {
-			return m, ops, nil
+			return m, append(ops, antiAffinityOps...), nil
 		}
 		switch errors.Cause(err) {
-		case inUseErr, machineNotAliveErr:
+		case inUseErr, machineNotAliveErr, machineInMaintenanceErr:
 		default:
 			assignContextf(&err, u.Name(), context)
 			return failure(err)
@@ -149,7 +149,11 @@ func (st *State) RemoveUser(tag names.UserTag) error {
 			Assert: txn.DocExists,
 			Update: bson.M{"$set": bson.M{"deleted": true}},
 		}}
-		return ops, nil
+		tokenOps, err := st.removeAPITokensOps(u.UserTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, tokenOps...), nil
 	}
 	return st.db().Run(buildTxn)
 }
@@ -71,6 +71,16 @@ func (st *State) addUserAccess(spec UserAccessSpec, target userAccessTarget) (pe
 		}
 	}
 
+	// Ensure the group exists before granting access to it.
+	if IsUserGroupTag(spec.User) {
+		if _, err := st.UserGroup(spec.User.Name()); err != nil {
+			return permission.UserAccess{}, errors.Trace(err)
+		}
+		if spec.DisplayName == "" {
+			spec.DisplayName = spec.User.Name()
+		}
+	}
+
 	// Ensure local createdBy user exists.
 	if spec.CreatedBy.IsLocal() {
 		if _, err := st.User(spec.CreatedBy); err != nil {
@@ -159,6 +169,8 @@ func (st *State) UserPermission(subject names.UserTag, target names.Tag) (permis
 			return "", errors.Trace(err)
 		}
 		return st.GetOfferAccess(offerUUID, subject)
+	case names.ApplicationTagKind:
+		return st.GetApplicationAccess(target.Id(), subject)
 	default:
 		return "", errors.NotValidf("%q as a target", target.Kind())
 	}
@@ -0,0 +1,267 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// UserGroupDomain is the domain of the user tags that stand for user
+// groups when granting access. Access granted to "devs@group" applies
+// to every member of the group "devs".
+const UserGroupDomain = "group"
+
+// UserGroupTag returns the tag that stands for the named group when
+// granting access.
+func UserGroupTag(name string) names.UserTag {
+	return names.NewLocalUserTag(name).WithDomain(UserGroupDomain)
+}
+
+// IsUserGroupTag reports whether the tag stands for a user group.
+func IsUserGroupTag(tag names.UserTag) bool {
+	return tag.Domain() == UserGroupDomain
+}
+
+type userGroupDoc struct {
+	DocID       string    `bson:"_id"`
+	Name        string    `bson:"name"`
+	Members     []string  `bson:"members"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+}
+
+// UserGroupClaimsExpiry is how long the groups claimed for a user by
+// an external identity provider apply for. Each login refreshes the
+// claims, so a user whose membership is revoked by the identity
+// provider loses the group's access at the latest this long after
+// they last logged in.
+const UserGroupClaimsExpiry = 24 * time.Hour
+
+// userGroupClaimsDoc records the groups that an external identity
+// provider most recently claimed a user belongs to.
+type userGroupClaimsDoc struct {
+	DocID   string    `bson:"_id"`
+	User    string    `bson:"user"`
+	Groups  []string  `bson:"groups"`
+	Updated time.Time `bson:"updated"`
+}
+
+// UserGroup represents a named group of users in the controller.
+type UserGroup struct {
+	st  *State
+	doc userGroupDoc
+}
+
+// Name returns the name of the group.
+func (g *UserGroup) Name() string {
+	return g.doc.Name
+}
+
+// Tag returns the tag that stands for the group when granting access.
+func (g *UserGroup) Tag() names.UserTag {
+	return UserGroupTag(g.doc.Name)
+}
+
+// Members returns the tags of the users explicitly added to the group,
+// sorted by name.
+func (g *UserGroup) Members() []names.UserTag {
+	members := make([]names.UserTag, len(g.doc.Members))
+	for i, m := range g.doc.Members {
+		members[i] = names.NewUserTag(m)
+	}
+	return members
+}
+
+// CreatedBy returns the name of the user that created the group.
+func (g *UserGroup) CreatedBy() string {
+	return g.doc.CreatedBy
+}
+
+// DateCreated returns when the group was created in UTC.
+func (g *UserGroup) DateCreated() time.Time {
+	return g.doc.DateCreated.UTC()
+}
+
+// AddUserGroup adds a user group with the given name to the controller.
+func (st *State) AddUserGroup(name, creator string) (*UserGroup, error) {
+	if !names.IsValidUserName(name) {
+		return nil, errors.NotValidf("group name %q", name)
+	}
+	group := &UserGroup{
+		st: st,
+		doc: userGroupDoc{
+			DocID:       strings.ToLower(name),
+			Name:        name,
+			CreatedBy:   creator,
+			DateCreated: st.nowToTheSecond(),
+		},
+	}
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     group.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &group.doc,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("group %q", name)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return group, nil
+}
+
+// UserGroup returns the user group with the given name.
+func (st *State) UserGroup(name string) (*UserGroup, error) {
+	groups, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	group := &UserGroup{st: st}
+	err := groups.FindId(strings.ToLower(name)).One(&group.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("group %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get group %q", name)
+	}
+	return group, nil
+}
+
+// AllUserGroups returns all user groups in the controller,
+// sorted by name.
+func (st *State) AllUserGroups() ([]*UserGroup, error) {
+	groups, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	var docs []userGroupDoc
+	if err := groups.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get groups")
+	}
+	result := make([]*UserGroup, len(docs))
+	for i, doc := range docs {
+		result[i] = &UserGroup{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// AddUserGroupMember adds the user to the named group. Local users
+// must exist; external users may be added before they first log in.
+func (st *State) AddUserGroupMember(name string, user names.UserTag) error {
+	if IsUserGroupTag(user) {
+		return errors.NotValidf("group %q as a group member", user.Name())
+	}
+	if user.IsLocal() {
+		if _, err := st.User(user); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	group, err := st.UserGroup(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	member := userAccessID(user)
+	members := set.NewStrings(group.doc.Members...)
+	if members.Contains(member) {
+		return errors.AlreadyExistsf("%q in group %q", user.Id(), name)
+	}
+	members.Add(member)
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     group.doc.DocID,
+		Assert: bson.D{{"members", bson.D{{"$ne", member}}}},
+		Update: bson.D{{"$set", bson.D{{"members", members.SortedValues()}}}},
+	}}
+	err = st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		if _, err := st.UserGroup(name); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.AlreadyExistsf("%q in group %q", user.Id(), name)
+	}
+	return errors.Trace(err)
+}
+
+// SetUserGroupClaims records the groups that an external identity
+// provider claims the user belongs to, replacing any previously
+// recorded claims. Claims for groups that do not exist in the
+// controller are recorded but have no effect until they do. Claims
+// lapse after UserGroupClaimsExpiry unless recorded again.
+func (st *State) SetUserGroupClaims(user names.UserTag, groups []string) error {
+	if user.IsLocal() {
+		return errors.NotValidf("group claims for local user %q", user.Id())
+	}
+	claimed := set.NewStrings()
+	for _, g := range groups {
+		claimed.Add(strings.ToLower(g))
+	}
+	id := userAccessID(user)
+	doc := userGroupClaimsDoc{
+		DocID:   id,
+		User:    user.Id(),
+		Groups:  claimed.SortedValues(),
+		Updated: st.nowToTheSecond(),
+	}
+	claims, closer := st.db().GetCollection(userGroupClaimsC)
+	defer closer()
+	_, err := claims.Writeable().UpsertId(id, &doc)
+	return errors.Annotatef(err, "cannot record group claims for %q", user.Id())
+}
+
+// UserGroups returns the tags standing for the groups the user is a
+// member of, either explicitly or by the unexpired claims of an
+// external identity provider, sorted by name.
+func (st *State) UserGroups(user names.UserTag) ([]names.UserTag, error) {
+	if IsUserGroupTag(user) {
+		return nil, nil
+	}
+	groupsColl, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	id := userAccessID(user)
+	var docs []userGroupDoc
+	if err := groupsColl.Find(bson.D{{"members", id}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get groups for %q", user.Id())
+	}
+	groups := set.NewStrings()
+	for _, doc := range docs {
+		groups.Add(doc.Name)
+	}
+
+	if !user.IsLocal() {
+		claims, closer := st.db().GetCollection(userGroupClaimsC)
+		defer closer()
+		var claimsDoc userGroupClaimsDoc
+		err := claims.FindId(id).One(&claimsDoc)
+		if err != nil && err != mgo.ErrNotFound {
+			return nil, errors.Annotatef(err, "cannot get group claims for %q", user.Id())
+		}
+		expired := st.clock().Now().After(claimsDoc.Updated.Add(UserGroupClaimsExpiry))
+		if len(claimsDoc.Groups) > 0 && !expired {
+			docs = nil
+			err := groupsColl.Find(bson.D{{"_id", bson.D{{"$in", claimsDoc.Groups}}}}).All(&docs)
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot get claimed groups for %q", user.Id())
+			}
+			for _, doc := range docs {
+				groups.Add(doc.Name)
+			}
+		}
+	}
+
+	groupNames := groups.SortedValues()
+	result := make([]names.UserTag, len(groupNames))
+	for i, name := range groupNames {
+		result[i] = UserGroupTag(name)
+	}
+	return result, nil
+}
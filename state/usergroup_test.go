@@ -0,0 +1,174 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserGroupSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserGroupSuite{})
+
+func (s *UserGroupSuite) TestAddUserGroup(c *gc.C) {
+	group, err := s.State.AddUserGroup("Devs", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "Devs")
+	c.Assert(group.Tag(), gc.Equals, names.NewUserTag("Devs@group"))
+	c.Assert(group.CreatedBy(), gc.Equals, "admin")
+	c.Assert(group.Members(), gc.HasLen, 0)
+
+	group, err = s.State.UserGroup("devs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "Devs")
+
+	_, err = s.State.AddUserGroup("devs", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `group "devs" already exists`)
+}
+
+func (s *UserGroupSuite) TestAddUserGroupInvalidName(c *gc.C) {
+	_, err := s.State.AddUserGroup("devs@group", "admin")
+	c.Assert(err, gc.ErrorMatches, `group name "devs@group" not valid`)
+}
+
+func (s *UserGroupSuite) TestUserGroupNotFound(c *gc.C) {
+	_, err := s.State.UserGroup("devs")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserGroupSuite) TestAllUserGroups(c *gc.C) {
+	for _, name := range []string{"ops", "devs"} {
+		_, err := s.State.AddUserGroup(name, "admin")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	groups, err := s.State.AllUserGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 2)
+	c.Assert(groups[0].Name(), gc.Equals, "devs")
+	c.Assert(groups[1].Name(), gc.Equals, "ops")
+}
+
+func (s *UserGroupSuite) TestAddUserGroupMember(c *gc.C) {
+	_, err := s.State.AddUserGroup("devs", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+
+	err = s.State.AddUserGroupMember("devs", alice.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddUserGroupMember("devs", names.NewUserTag("bob@external"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	group, err := s.State.UserGroup("devs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{
+		names.NewUserTag("alice"),
+		names.NewUserTag("bob@external"),
+	})
+
+	err = s.State.AddUserGroupMember("devs", alice.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *UserGroupSuite) TestAddUserGroupMemberErrors(c *gc.C) {
+	err := s.State.AddUserGroupMember("devs", names.NewUserTag("bob@external"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.AddUserGroup("devs", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddUserGroupMember("devs", names.NewUserTag("nobody"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.AddUserGroupMember("devs", names.NewUserTag("ops@group"))
+	c.Assert(err, gc.ErrorMatches, `group "ops" as a group member not valid`)
+}
+
+func (s *UserGroupSuite) TestUserGroups(c *gc.C) {
+	for _, name := range []string{"devs", "ops", "qa"} {
+		_, err := s.State.AddUserGroup(name, "admin")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	bob := names.NewUserTag("bob@oidc")
+	err := s.State.AddUserGroupMember("devs", bob)
+	c.Assert(err, jc.ErrorIsNil)
+
+	groups, err := s.State.UserGroups(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []names.UserTag{state.UserGroupTag("devs")})
+
+	// Claims from the identity provider add to explicit membership,
+	// ignoring groups that do not exist.
+	err = s.State.SetUserGroupClaims(bob, []string{"QA", "unknown"})
+	c.Assert(err, jc.ErrorIsNil)
+	groups, err = s.State.UserGroups(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []names.UserTag{
+		state.UserGroupTag("devs"),
+		state.UserGroupTag("qa"),
+	})
+
+	// Later claims replace earlier ones.
+	err = s.State.SetUserGroupClaims(bob, []string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	groups, err = s.State.UserGroups(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []names.UserTag{
+		state.UserGroupTag("devs"),
+		state.UserGroupTag("ops"),
+	})
+
+	// Claims not refreshed by a login lapse.
+	s.Clock.Advance(state.UserGroupClaimsExpiry + time.Second)
+	groups, err = s.State.UserGroups(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []names.UserTag{state.UserGroupTag("devs")})
+
+	err = s.State.SetUserGroupClaims(bob, []string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	groups, err = s.State.UserGroups(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []names.UserTag{
+		state.UserGroupTag("devs"),
+		state.UserGroupTag("ops"),
+	})
+}
+
+func (s *UserGroupSuite) TestSetUserGroupClaimsLocalUser(c *gc.C) {
+	err := s.State.SetUserGroupClaims(names.NewUserTag("alice"), []string{"devs"})
+	c.Assert(err, gc.ErrorMatches, `group claims for local user "alice" not valid`)
+}
+
+func (s *UserGroupSuite) TestGrantToGroup(c *gc.C) {
+	tag := state.UserGroupTag("devs")
+	_, err := s.Model.AddUser(state.UserAccessSpec{
+		User:      tag,
+		CreatedBy: s.Owner,
+		Access:    permission.WriteAccess,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.AddUserGroup("devs", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	modelUser, err := s.Model.AddUser(state.UserAccessSpec{
+		User:      tag,
+		CreatedBy: s.Owner,
+		Access:    permission.WriteAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(modelUser.DisplayName, gc.Equals, "devs")
+
+	access, err := s.State.UserPermission(tag, s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+}
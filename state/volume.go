@@ -604,9 +604,13 @@ func detachVolumeOps(host names.Tag, v names.VolumeTag) []txn.Op {
 
 // RemoveVolumeAttachment removes the volume attachment from state.
 // RemoveVolumeAttachment will fail if the attachment is not Dying.
+// If the attachment is the last to be removed from a machine whose
+// instance is being replaced, the machine's instance is cleared.
 func (sb *storageBackend) RemoveVolumeAttachment(host names.Tag, volume names.VolumeTag) (err error) {
 	defer errors.DeferredAnnotatef(&err, "removing attachment of volume %s from %s", volume.Id(), names.ReadableString(host))
+	var replacement *instanceReplacement
 	buildTxn := func(attempt int) ([]txn.Op, error) {
+		replacement = nil
 		attachment, err := sb.VolumeAttachment(host, volume)
 		if errors.IsNotFound(err) && attempt > 0 {
 			// We only ignore IsNotFound on attempts after the
@@ -624,9 +628,21 @@ func (sb *storageBackend) RemoveVolumeAttachment(host names.Tag, volume names.Vo
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		return removeVolumeAttachmentOps(host, v), nil
+		ops := removeVolumeAttachmentOps(host, v)
+		replaceOps, r, err := sb.replacedInstanceDetachedOps(host, volume)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		replacement = r
+		return append(ops, replaceOps...), nil
 	}
-	return sb.mb.db().Run(buildTxn)
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return err
+	}
+	if replacement != nil {
+		return errors.Trace(replacement.setStatus(sb))
+	}
+	return nil
 }
 
 func removeVolumeAttachmentOps(host names.Tag, v *volume) []txn.Op {
@@ -931,6 +947,23 @@ func (sb *storageBackend) validateVolumeParams(params VolumeParams, machineId st
 	if params.Size == 0 {
 		return "", errors.New("invalid size 0")
 	}
+	_, provider, err := poolStorageProvider(sb, params.Pool)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if p, ok := provider.(storage.HostScopedProvider); ok {
+		if ctype := p.HostScopedContainerType(); ctype != "" {
+			if ContainerTypeFromId(machineId) != ctype {
+				return "", errors.NotSupportedf(
+					"%q pool volumes for machine %q, which is not a %s container",
+					params.Pool, machineId, ctype,
+				)
+			}
+			// The container's host creates and attaches the
+			// volume, so it is scoped to the host.
+			machineId = ParentId(machineId)
+		}
+	}
 	return machineId, nil
 }
 
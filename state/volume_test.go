@@ -928,6 +928,61 @@ func (s *VolumeStateSuite) TestVolumeMachineScoped(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *VolumeStateSuite) TestVolumeHostScoped(c *gc.C) {
+	host, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	guest, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.HostVolumeParams{{
+			Volume: state.VolumeParams{Pool: "hostscoped", Size: 1024},
+		}},
+	}, host.Id(), instance.KVM)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume is scoped to the host, which provisions it,
+	// but attached to the guest.
+	volume := s.volume(c, names.NewVolumeTag("0/0"))
+	c.Assert(volume.Life(), gc.Equals, state.Alive)
+	_, err = s.storageBackend.VolumeAttachment(guest.MachineTag(), volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.storageBackend.WatchMachineVolumeAttachments(host.MachineTag())
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent("0/kvm/0:0/0")
+	wc.AssertNoChange()
+
+	w2 := s.storageBackend.WatchMachineVolumeAttachments(guest.MachineTag())
+	defer testing.AssertStop(c, w2)
+	wc2 := testing.NewStringsWatcherC(c, s.State, w2)
+	wc2.AssertChange()
+	wc2.AssertNoChange()
+}
+
+func (s *VolumeStateSuite) TestVolumeHostScopedNotKVMContainer(c *gc.C) {
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.HostVolumeParams{{
+			Volume: state.VolumeParams{Pool: "hostscoped", Size: 1024},
+		}},
+	}
+	_, err := s.State.AddOneMachine(template)
+	c.Assert(err, gc.ErrorMatches, `.*"hostscoped" pool volumes for machine "[0-9]+", which is not a kvm container not supported`)
+
+	host, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineInsideMachine(template, host.Id(), instance.LXD)
+	c.Assert(err, gc.ErrorMatches, `.*"hostscoped" pool volumes for machine "[0-9]+/lxd/0", which is not a kvm container not supported`)
+}
+
 func (s *VolumeStateSuite) TestVolumeBindingStorage(c *gc.C) {
 	// Volumes created assigned to a storage instance are bound
 	// to the machine/model, and not the storage. i.e. storage
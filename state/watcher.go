@@ -303,9 +303,32 @@ func (sb *storageBackend) watchModelMachinestorageAttachments(collection string)
 
 // WatchMachineVolumeAttachments returns a StringsWatcher that notifies of
 // changes to the lifecycles of all volume attachments related to the specified
-// machine, for volumes scoped to the machine.
+// machine, for volumes scoped to the machine. This includes the attachments
+// to the machine's containers of volumes scoped to the machine by
+// host-scoped storage providers.
 func (sb *storageBackend) WatchMachineVolumeAttachments(m names.MachineTag) StringsWatcher {
-	return sb.watchMachineStorageAttachments(m, volumeAttachmentsC)
+	mb := sb.mb
+	pattern := fmt.Sprintf(
+		"^%s(/%s/%s)*:%s/.*", mb.docID(m.Id()),
+		names.ContainerTypeSnippet, names.NumberSnippet, m.Id(),
+	)
+	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
+	filter := func(id interface{}) bool {
+		k, err := mb.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		host, volume, err := ParseVolumeAttachmentId(k)
+		if err != nil {
+			return false
+		}
+		if host != m && !strings.HasPrefix(host.Id(), m.Id()+"/") {
+			return false
+		}
+		volumeMachine, ok := names.VolumeMachine(volume)
+		return ok && volumeMachine == m
+	}
+	return newLifecycleWatcher(mb, volumeAttachmentsC, members, filter, nil)
 }
 
 // WatchMachineFilesystemAttachments returns a StringsWatcher that notifies of
@@ -1622,6 +1645,20 @@ func (u *Unit) WatchApplicationConfigSettings() (NotifyWatcher, error) {
 	return newEntityWatcher(u.st, settingsC, u.st.docID(applicationConfigKey)), nil
 }
 
+// WatchAllApplicationConfig returns a NotifyWatcher that notifies of
+// changes to the application configuration of any application in the
+// model.
+func (st *State) WatchAllApplicationConfig() NotifyWatcher {
+	isLocal := isLocalID(st)
+	return newNotifyCollWatcher(st, settingsC, func(id interface{}) bool {
+		if !isLocal(id) {
+			return false
+		}
+		key := st.localID(id.(string))
+		return strings.HasPrefix(key, "a#") && strings.HasSuffix(key, "#application")
+	})
+}
+
 // WatchMeterStatus returns a watcher observing changes that affect the meter status
 // of a unit.
 func (u *Unit) WatchMeterStatus() NotifyWatcher {
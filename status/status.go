@@ -219,6 +219,15 @@ const (
 	Provisioning      Status = "allocating"
 	Running           Status = "running"
 	ProvisioningError Status = "provisioning error"
+
+	// Reclaimed indicates that the instance ran on interruptible
+	// (spot or preemptible) capacity, and the cloud has reclaimed it.
+	Reclaimed Status = "reclaimed"
+
+	// Replacing indicates that the machine's instance is being
+	// replaced: volumes are detached from the old instance, which is
+	// then stopped and a new instance provisioned for the machine.
+	Replacing Status = "replacing"
 )
 
 const (
@@ -229,6 +238,10 @@ const (
 	MessageInstallingCharm   = "installing charm software"
 )
 
+// TagDriftKey is the model status data key under which warnings about
+// drift in the tags of the model's instances and volumes are recorded.
+const TagDriftKey = "tag-drift"
+
 func (status Status) KnownInstanceStatus() bool {
 	switch status {
 	case
@@ -236,6 +249,8 @@ func (status Status) KnownInstanceStatus() bool {
 		ProvisioningError,
 		Allocating,
 		Running,
+		Reclaimed,
+		Replacing,
 		Unknown:
 		return true
 	}
@@ -319,3 +334,23 @@ func ValidModelStatus(status Status) bool {
 func (status Status) Matches(candidate Status) bool {
 	return status == candidate
 }
+
+// TagDriftWarnings returns the tag drift warnings recorded in the
+// given model status data.
+func TagDriftWarnings(data map[string]interface{}) []string {
+	switch warnings := data[TagDriftKey].(type) {
+	case []string:
+		return warnings
+	case []interface{}:
+		// Status data read back from the database holds
+		// lists of any type.
+		result := make([]string, 0, len(warnings))
+		for _, w := range warnings {
+			if w, ok := w.(string); ok {
+				result = append(result, w)
+			}
+		}
+		return result
+	}
+	return nil
+}
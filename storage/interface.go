@@ -87,6 +87,19 @@ type Provider interface {
 	ValidateConfig(*Config) error
 }
 
+// HostScopedProvider is an optional interface that a machine-scoped
+// Provider may implement when its volumes for a container are created
+// and attached by the container's host machine, rather than by the
+// container itself.
+type HostScopedProvider interface {
+	// HostScopedContainerType returns the type of container whose
+	// volumes are scoped to the container's host machine, or "" if
+	// the provider's volumes are scoped to their own machines. If
+	// it is not "", the provider's volumes may only be created for
+	// containers of that type.
+	HostScopedContainerType() instance.ContainerType
+}
+
 // VolumeSource provides an interface for creating, destroying, describing,
 // attaching and detaching volumes in the environment. A VolumeSource is
 // configured in a particular way, and corresponds to a storage "pool".
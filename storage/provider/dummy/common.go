@@ -3,7 +3,10 @@
 
 package dummy
 
-import "github.com/juju/juju/storage"
+import (
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
 
 // StorageProviders returns a provider registry with some
 // well-defined dummy storage providers.
@@ -33,6 +36,14 @@ func StorageProviders() storage.ProviderRegistry {
 				StorageScope: storage.ScopeMachine,
 				IsDynamic:    true,
 			},
+			"hostscoped": &StorageProvider{
+				StorageScope:      storage.ScopeMachine,
+				IsDynamic:         true,
+				HostContainerType: instance.KVM,
+				SupportsFunc: func(k storage.StorageKind) bool {
+					return k == storage.StorageKindBlock
+				},
+			},
 		},
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/storage"
 )
 
@@ -29,6 +30,10 @@ type StorageProvider struct {
 	// supports releasing storage.
 	IsReleasable bool
 
+	// HostContainerType defines the type of container whose volumes
+	// the provider reports are scoped to the containers' hosts.
+	HostContainerType instance.ContainerType
+
 	// DefaultPools_ will be returned by DefaultPools.
 	DefaultPools_ []*storage.Config
 
@@ -103,6 +108,12 @@ func (p *StorageProvider) Releasable() bool {
 	return p.IsReleasable
 }
 
+// HostScopedContainerType is defined on storage.HostScopedProvider.
+func (p *StorageProvider) HostScopedContainerType() instance.ContainerType {
+	p.MethodCall(p, "HostScopedContainerType")
+	return p.HostContainerType
+}
+
 // DefaultPool is defined on storage.Provider.
 func (p *StorageProvider) DefaultPools() []*storage.Config {
 	p.MethodCall(p, "DefaultPools")
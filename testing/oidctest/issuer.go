@@ -0,0 +1,217 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package oidctest provides a stub OpenID Connect issuer for
+// testing OIDC login.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+const (
+	// KeyID is the ID of the key the issuer signs tokens with.
+	KeyID = "test-key"
+
+	// DeviceCode is the device code handed out by the issuer's
+	// device authorization endpoint.
+	DeviceCode = "device-code"
+
+	// UserCode is the user code handed out by the issuer's
+	// device authorization endpoint.
+	UserCode = "ABCD-EFGH"
+)
+
+// Issuer is a stub OpenID Connect issuer. It serves the discovery
+// document, key set, and the device authorization and token endpoints
+// of the OAuth 2.0 device authorization grant.
+type Issuer struct {
+	*httptest.Server
+
+	key *rsa.PrivateKey
+
+	mu sync.Mutex
+	// claims holds the claims of the ID token to hand out once
+	// the device authorization is approved.
+	claims map[string]interface{}
+	// pending holds the number of token requests that will be
+	// answered with "authorization_pending" before the token
+	// is handed out.
+	pending int
+	// keySetRequests holds the number of times the key set
+	// has been requested.
+	keySetRequests int
+}
+
+// NewIssuer returns a new stub issuer, serving over TLS. The caller
+// is responsible for calling Close.
+func NewIssuer() *Issuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	issuer := &Issuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.serveDiscovery)
+	mux.HandleFunc("/jwks", issuer.serveKeys)
+	mux.HandleFunc("/device", issuer.serveDeviceAuthorization)
+	mux.HandleFunc("/token", issuer.serveToken)
+	issuer.Server = httptest.NewTLSServer(mux)
+	return issuer
+}
+
+// Approve records that the user approved the device authorization,
+// and that the token endpoint should issue an ID token with the given
+// claims after answering the given number of requests with
+// "authorization_pending".
+func (i *Issuer) Approve(claims map[string]interface{}, pending int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.claims = claims
+	i.pending = pending
+}
+
+// Claims returns a set of valid claims for the given client ID and
+// user, expiring after the given duration.
+func (i *Issuer) Claims(clientID, username string, groups []string, expiry time.Duration) map[string]interface{} {
+	claims := map[string]interface{}{
+		"iss":                i.URL,
+		"aud":                clientID,
+		"sub":                username,
+		"preferred_username": username,
+		"exp":                time.Now().Add(expiry).Unix(),
+	}
+	if groups != nil {
+		claims["groups"] = groups
+	}
+	return claims
+}
+
+// KeySetRequests returns the number of times the issuer's key set
+// has been requested.
+func (i *Issuer) KeySetRequests() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.keySetRequests
+}
+
+// IDToken returns an ID token with the given claims, signed by
+// the issuer's key.
+func (i *Issuer) IDToken(claims map[string]interface{}) string {
+	return i.sign(i.key, KeyID, claims)
+}
+
+// ForeignIDToken returns an ID token with the given claims, signed
+// by a key the issuer does not publish.
+func (i *Issuer) ForeignIDToken(claims map[string]interface{}) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return i.sign(key, KeyID, claims)
+}
+
+// UnknownKeyIDToken returns an ID token with the given claims, naming
+// a signing key ID that the issuer does not publish.
+func (i *Issuer) UnknownKeyIDToken(claims map[string]interface{}) string {
+	return i.sign(i.key, "unknown-key", claims)
+}
+
+func (i *Issuer) sign(key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header := encodeSegment(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	payload := encodeSegment(claims)
+	digest := sha256.Sum256([]byte(header + "." + payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (i *Issuer) serveDiscovery(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                        i.URL,
+		"jwks_uri":                      i.URL + "/jwks",
+		"device_authorization_endpoint": i.URL + "/device",
+		"token_endpoint":                i.URL + "/token",
+	})
+}
+
+func (i *Issuer) serveKeys(w http.ResponseWriter, req *http.Request) {
+	i.mu.Lock()
+	i.keySetRequests++
+	i.mu.Unlock()
+	pub := i.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (i *Issuer) serveDeviceAuthorization(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" || req.PostFormValue("client_id") == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":      DeviceCode,
+		"user_code":        UserCode,
+		"verification_uri": i.URL + "/activate",
+		"expires_in":       600,
+		"interval":         0,
+	})
+}
+
+func (i *Issuer) serveToken(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" ||
+		req.PostFormValue("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" ||
+		req.PostFormValue("device_code") != DeviceCode {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.claims == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "access_denied"})
+		return
+	}
+	if i.pending > 0 {
+		i.pending--
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     i.IDToken(i.claims),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
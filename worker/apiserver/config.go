@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -96,6 +97,81 @@ func getRateLimitConfig(cfg agent.Config) (apiserver.RateLimitConfig, error) {
 		}
 		result.ConnUpperThreshold = val
 	}
+	if v := cfg.Value(agent.AgentUserCallRate); v != "" {
+		val, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentUserCallRate,
+			)
+		}
+		result.UserCallRate = val
+	}
+	if v := cfg.Value(agent.AgentUserCallBurst); v != "" {
+		val, err := strconv.Atoi(v)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentUserCallBurst,
+			)
+		}
+		result.UserCallBurst = val
+	}
+	if v := cfg.Value(agent.AgentConnCallRate); v != "" {
+		val, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentConnCallRate,
+			)
+		}
+		result.ConnCallRate = val
+	}
+	if v := cfg.Value(agent.AgentConnCallBurst); v != "" {
+		val, err := strconv.Atoi(v)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentConnCallBurst,
+			)
+		}
+		result.ConnCallBurst = val
+	}
+	if v := cfg.Value(agent.AgentFacadeGroupCallLimits); v != "" {
+		val, err := parseFacadeGroupCallLimits(v)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentFacadeGroupCallLimits,
+			)
+		}
+		result.FacadeGroupCallLimits = val
+	}
+	return result, nil
+}
+
+// parseFacadeGroupCallLimits parses facade group call limits of the
+// form "<group>=<rate>/<burst>,...", for example "status=5/10".
+func parseFacadeGroupCallLimits(v string) (map[string]apiserver.CallLimit, error) {
+	result := make(map[string]apiserver.CallLimit)
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.NotValidf("call limit %q", item)
+		}
+		limit := strings.SplitN(parts[1], "/", 2)
+		if len(limit) != 2 {
+			return nil, errors.NotValidf("call limit %q", item)
+		}
+		rate, err := strconv.ParseFloat(limit[0], 64)
+		if err != nil {
+			return nil, errors.NotValidf("call limit %q", item)
+		}
+		burst, err := strconv.Atoi(limit[1])
+		if err != nil {
+			return nil, errors.NotValidf("call limit %q", item)
+		}
+		result[parts[0]] = apiserver.CallLimit{Rate: rate, Burst: burst}
+	}
 	return result, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/agent"
 	coreapiserver "github.com/juju/juju/apiserver"
 	apitesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/auditlog"
@@ -121,3 +122,31 @@ func (s *WorkerStateSuite) TestStart(c *gc.C) {
 		PrometheusRegisterer: &s.prometheusRegisterer,
 	})
 }
+
+func (s *WorkerStateSuite) TestCallLimitConfig(c *gc.C) {
+	s.agentConfig.values = map[string]string{
+		agent.AgentUserCallRate:          "20",
+		agent.AgentUserCallBurst:         "40",
+		agent.AgentFacadeGroupCallLimits: "status=0.5/2, deploy=1/1",
+	}
+	w, err := apiserver.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(s.stub.Calls()) > 0 {
+			break
+		}
+	}
+	if !s.stub.CheckCallNames(c, "NewServer") {
+		return
+	}
+	config := s.stub.Calls()[0].Args[0].(coreapiserver.ServerConfig)
+	c.Assert(config.RateLimitConfig.UserCallRate, gc.Equals, 20.0)
+	c.Assert(config.RateLimitConfig.UserCallBurst, gc.Equals, 40)
+	c.Assert(config.RateLimitConfig.ConnCallRate, gc.Equals, 0.0)
+	c.Assert(config.RateLimitConfig.FacadeGroupCallLimits, jc.DeepEquals, map[string]coreapiserver.CallLimit{
+		"status": {Rate: 0.5, Burst: 2},
+		"deploy": {Rate: 1, Burst: 1},
+	})
+}
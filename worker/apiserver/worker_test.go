@@ -149,6 +149,11 @@ func (s *WorkerValidationSuite) TestValidateRateLimitConfig(c *gc.C) {
 	s.testValidateRateLimitConfig(c, agent.AgentConnLookbackWindow, "foo", "parsing AGENT_CONN_LOOKBACK_WINDOW: .*")
 	s.testValidateRateLimitConfig(c, agent.AgentConnLowerThreshold, "foo", "parsing AGENT_CONN_LOWER_THRESHOLD: .*")
 	s.testValidateRateLimitConfig(c, agent.AgentConnUpperThreshold, "foo", "parsing AGENT_CONN_UPPER_THRESHOLD: .*")
+	s.testValidateRateLimitConfig(c, agent.AgentUserCallRate, "foo", "parsing AGENT_USER_CALL_RATE: .*")
+	s.testValidateRateLimitConfig(c, agent.AgentUserCallBurst, "foo", "parsing AGENT_USER_CALL_BURST: .*")
+	s.testValidateRateLimitConfig(c, agent.AgentConnCallRate, "foo", "parsing AGENT_CONN_CALL_RATE: .*")
+	s.testValidateRateLimitConfig(c, agent.AgentConnCallBurst, "foo", "parsing AGENT_CONN_CALL_BURST: .*")
+	s.testValidateRateLimitConfig(c, agent.AgentFacadeGroupCallLimits, "status=5", `parsing AGENT_FACADE_GROUP_CALL_LIMITS: call limit "status=5" not valid`)
 }
 
 func (s *WorkerValidationSuite) testValidateRateLimitConfig(c *gc.C, key, value, expect string) {
@@ -84,9 +84,9 @@ func (config ManifoldConfig) start(context dependency.Context) (_ worker.Worker,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	if auditConfig.Enabled {
-		auditConfig.Target = logFactory(auditConfig)
-	}
+	// The audit log is always opened, even when auditing is disabled,
+	// because API token sessions are recorded regardless.
+	auditConfig.Target = logFactory(auditConfig)
 
 	w, err := config.NewWorker(st, auditConfig, logFactory)
 	if err != nil {
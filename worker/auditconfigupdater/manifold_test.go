@@ -147,7 +147,10 @@ func (s *manifoldSuite) TestStartWithAuditingDisabled(c *gc.C) {
 	c.Assert(args[0], gc.Equals, s.State)
 
 	auditConfig := args[1].(auditlog.Config)
-	c.Assert(auditConfig.Target, gc.IsNil)
+	c.Assert(auditConfig.Enabled, jc.IsFalse)
+	// The log is still opened to record API token sessions.
+	c.Assert(auditConfig.Target, gc.NotNil)
+	auditConfig.Target.Close()
 }
 
 func (s *manifoldSuite) TestOutput(c *gc.C) {
@@ -96,12 +96,14 @@ func (u *updater) newConfig() (auditlog.Config, error) {
 		MaxBackups:     cfg.AuditLogMaxBackups(),
 		ExcludeMethods: cfg.AuditLogExcludeMethods(),
 	}
-	if result.Enabled && u.current.Target == nil {
+	if u.current.Target == nil {
+		// API token sessions are recorded even when auditing is
+		// disabled, so there must always be a target.
 		result.Target = u.logFactory(result)
 	} else {
 		// Keep the existing target to avoid file handle leaks from
 		// disabling and enabling auditing - we'll still stop logging
-		// because enabled is false.
+		// other conversations because enabled is false.
 		result.Target = u.current.Target
 	}
 	return result, nil
@@ -0,0 +1,101 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/watcher"
+)
+
+var logger = loggo.GetLogger("juju.worker.cacertupdater")
+
+// Facade exposes the controller CA certificate bundle to an agent, and
+// records which agents have learnt it.
+type Facade interface {
+	ControllerCACert() (string, error)
+	WatchControllerCACert() (watcher.NotifyWatcher, error)
+	SetControllerCATrusted(tag names.Tag, caCert string) error
+}
+
+// CACertSetter writes the CA certificate bundle to the agent's config.
+type CACertSetter interface {
+	CACert() string
+	SetCACert(caCert string) error
+}
+
+// Config holds the dependencies of the CA certificate updater.
+type Config struct {
+	Tag    names.Tag
+	Facade Facade
+	Setter CACertSetter
+}
+
+// Validate returns an error if the config cannot be used to start
+// a CA certificate updater.
+func (config Config) Validate() error {
+	if config.Tag == nil {
+		return errors.NotValidf("nil Tag")
+	}
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Setter == nil {
+		return errors.NotValidf("nil Setter")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that keeps the CA certificate bundle in
+// the agent's config in step with the controller's, so the agent
+// keeps trusting the controller while its CA is rotated.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &caCertUpdater{config: config},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type caCertUpdater struct {
+	config Config
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (u *caCertUpdater) SetUp() (watcher.NotifyWatcher, error) {
+	return u.config.Facade.WatchControllerCACert()
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (u *caCertUpdater) Handle(_ <-chan struct{}) error {
+	caCert, err := u.config.Facade.ControllerCACert()
+	if err != nil {
+		return errors.Annotate(err, "cannot get controller CA certificate")
+	}
+	if caCert != u.config.Setter.CACert() {
+		logger.Infof("updating controller CA certificate")
+		if err := u.config.Setter.SetCACert(caCert); err != nil {
+			return errors.Annotate(err, "cannot update agent CA certificate")
+		}
+	}
+	// Always report, so a controller that restarted mid-rotation
+	// still learns that this agent trusts the new CA.
+	if err := u.config.Facade.SetControllerCATrusted(u.config.Tag, caCert); err != nil {
+		return errors.Annotate(err, "cannot record controller CA certificate as trusted")
+	}
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (u *caCertUpdater) TearDown() error {
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/cacertupdater"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+type mockFacade struct {
+	testing.Stub
+	caCert  string
+	changes chan struct{}
+	trusted chan string
+}
+
+func (f *mockFacade) ControllerCACert() (string, error) {
+	f.MethodCall(f, "ControllerCACert")
+	return f.caCert, f.NextErr()
+}
+
+func (f *mockFacade) WatchControllerCACert() (watcher.NotifyWatcher, error) {
+	f.MethodCall(f, "WatchControllerCACert")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return watchertest.NewMockNotifyWatcher(f.changes), nil
+}
+
+func (f *mockFacade) SetControllerCATrusted(tag names.Tag, caCert string) error {
+	f.MethodCall(f, "SetControllerCATrusted", tag, caCert)
+	f.trusted <- caCert
+	return f.NextErr()
+}
+
+type mockSetter struct {
+	caCert string
+}
+
+func (s *mockSetter) CACert() string {
+	return s.caCert
+}
+
+func (s *mockSetter) SetCACert(caCert string) error {
+	s.caCert = caCert
+	return nil
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := cacertupdater.NewWorker(cacertupdater.Config{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *WorkerSuite) TestUpdatesCACert(c *gc.C) {
+	facade := &mockFacade{
+		caCert:  coretesting.CACert + coretesting.OtherCACert,
+		changes: make(chan struct{}, 1),
+		trusted: make(chan string, 1),
+	}
+	facade.changes <- struct{}{}
+	setter := &mockSetter{caCert: coretesting.CACert}
+	tag := names.NewMachineTag("42")
+
+	w, err := cacertupdater.NewWorker(cacertupdater.Config{
+		Tag:    tag,
+		Facade: facade,
+		Setter: setter,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	select {
+	case caCert := <-facade.trusted:
+		c.Assert(caCert, gc.Equals, facade.caCert)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for CA to be trusted")
+	}
+	workertest.CleanKill(c, w)
+	c.Assert(setter.caCert, gc.Equals, facade.caCert)
+	facade.CheckCall(c, 2, "SetControllerCATrusted", tag, facade.caCert)
+}
+
+func (s *WorkerSuite) TestSetTrustedError(c *gc.C) {
+	facade := &mockFacade{
+		caCert:  coretesting.CACert,
+		changes: make(chan struct{}, 1),
+		trusted: make(chan string, 1),
+	}
+	facade.SetErrors(nil, nil, errors.New("boom"))
+	facade.changes <- struct{}{}
+
+	w, err := cacertupdater.NewWorker(cacertupdater.Config{
+		Tag:    names.NewUnitTag("mysql/0"),
+		Facade: facade,
+		Setter: &mockSetter{caCert: coretesting.CACert},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "cannot record controller CA certificate as trusted: boom")
+}
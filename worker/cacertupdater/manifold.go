@@ -0,0 +1,62 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
+type ManifoldConfig engine.AgentAPIManifoldConfig
+
+// Manifold returns a dependency manifold that runs a CA certificate
+// updater worker, using the resource names defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := engine.AgentAPIManifoldConfig(config)
+	return engine.AgentAPIManifold(typedConfig, newWorker)
+}
+
+// newWorker wraps NewWorker for use in a engine.AgentAPIManifold. The
+// worker uninstalls itself when the controller predates CA rotation.
+func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	facade, err := apiagent.NewState(apiCaller)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := facade.ControllerCACert(); errors.IsNotSupported(err) {
+		return nil, dependency.ErrUninstall
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewWorker(Config{
+		Tag:    a.CurrentConfig().Tag(),
+		Facade: facade,
+		Setter: caCertSetter{a},
+	})
+}
+
+// caCertSetter adapts an agent.Agent to the CACertSetter interface.
+type caCertSetter struct {
+	agent.Agent
+}
+
+// CACert is part of the CACertSetter interface.
+func (s caCertSetter) CACert() string {
+	return s.CurrentConfig().CACert()
+}
+
+// SetCACert is part of the CACertSetter interface.
+func (s caCertSetter) SetCACert(caCert string) error {
+	return s.ChangeConfig(func(c agent.ConfigSetter) error {
+		c.SetCACert(caCert)
+		return nil
+	})
+}
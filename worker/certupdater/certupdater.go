@@ -5,6 +5,7 @@ package certupdater
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
@@ -12,7 +13,9 @@ import (
 	"github.com/juju/utils/cert"
 	worker "gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	jujucert "github.com/juju/juju/cert"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -32,7 +35,14 @@ type CertificateUpdater struct {
 	setter          StateServingInfoSetter
 	configGetter    ControllerConfigGetter
 	hostPortsGetter APIHostPortsGetter
+	caRotation      CARotationGetter
+	mongo           MongoCertUpdater
+	machineId       string
 	addresses       []network.Address
+
+	// servingInfo holds the last serving info written by the
+	// worker, which the getter may not yet reflect.
+	servingInfo *params.StateServingInfo
 }
 
 // AddressWatcher is an interface that is provided to NewCertificateUpdater
@@ -64,6 +74,27 @@ type APIHostPortsGetter interface {
 	APIHostPortsForClients() ([][]network.HostPort, error)
 }
 
+// CARotationGetter is an interface that is provided to NewCertificateUpdater.
+// It is used to re-issue the controller certificate from a new CA while the
+// controller CA is being rotated.
+type CARotationGetter interface {
+	ControllerCARotation() (state.ControllerCARotation, error)
+	WatchControllerCARotation() state.NotifyWatcher
+	SetControllerCertIssued(machineId string) error
+}
+
+// MongoCertUpdater is an interface that is provided to NewCertificateUpdater.
+// It is used to have the controller's mongod serve a certificate issued by
+// a new CA while the controller CA is being rotated.
+type MongoCertUpdater interface {
+	// ServerCert returns the PEM-encoded certificate mongod is serving.
+	ServerCert() (string, error)
+
+	// UpdateServerCert writes the certificate and key for mongod to
+	// serve, and restarts mongod so that it uses them.
+	UpdateServerCert(cert, key string) error
+}
+
 // Config holds the configuration for the certificate updater worker.
 type Config struct {
 	AddressWatcher         AddressWatcher
@@ -71,6 +102,14 @@ type Config struct {
 	StateServingInfoSetter StateServingInfoSetter
 	ControllerConfigGetter ControllerConfigGetter
 	APIHostPortsGetter     APIHostPortsGetter
+
+	// CARotationGetter and MachineId are optional; when set, the
+	// worker re-issues the certificate from a new controller CA.
+	// MongoCertUpdater is also optional; when set, mongod is made to
+	// serve the re-issued certificate too.
+	CARotationGetter CARotationGetter
+	MongoCertUpdater MongoCertUpdater
+	MachineId        string
 }
 
 // NewCertificateUpdater returns a worker.Worker that watches for changes to
@@ -83,11 +122,17 @@ func NewCertificateUpdater(config Config) worker.Worker {
 		hostPortsGetter: config.APIHostPortsGetter,
 		getter:          config.StateServingInfoGetter,
 		setter:          config.StateServingInfoSetter,
+		caRotation:      config.CARotationGetter,
+		mongo:           config.MongoCertUpdater,
+		machineId:       config.MachineId,
 	})
 }
 
 // SetUp is defined on the NotifyWatchHandler interface.
 func (c *CertificateUpdater) SetUp() (state.NotifyWatcher, error) {
+	if err := c.reissueCertificate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	// Populate certificate SAN with any addresses we know about now.
 	apiHostPorts, err := c.hostPortsGetter.APIHostPortsForClients()
 	if err != nil {
@@ -105,11 +150,22 @@ func (c *CertificateUpdater) SetUp() (state.NotifyWatcher, error) {
 	if err := c.updateCertificate(initialSANAddresses); err != nil {
 		return nil, errors.Annotate(err, "setting initial certificate SAN list")
 	}
-	return c.addressWatcher.WatchAddresses(), nil
+	if c.caRotation == nil {
+		return c.addressWatcher.WatchAddresses(), nil
+	}
+	return common.NewMultiNotifyWatcher(
+		c.addressWatcher.WatchAddresses(),
+		c.caRotation.WatchControllerCARotation(),
+	), nil
 }
 
 // Handle is defined on the NotifyWatchHandler interface.
 func (c *CertificateUpdater) Handle(done <-chan struct{}) error {
+	// Re-issue first, so that any address update is signed with the
+	// CA key matching the first certificate of the CA bundle.
+	if err := c.reissueCertificate(); err != nil {
+		return errors.Trace(err)
+	}
 	addresses := c.addressWatcher.Addresses()
 	if reflect.DeepEqual(addresses, c.addresses) {
 		// Sometimes the watcher will tell us things have changed, when they
@@ -120,13 +176,112 @@ func (c *CertificateUpdater) Handle(done <-chan struct{}) error {
 	return c.updateCertificate(addresses)
 }
 
+// stateServingInfo returns the serving info most recently written by
+// the worker, falling back to the getter.
+func (c *CertificateUpdater) stateServingInfo() (params.StateServingInfo, bool) {
+	if c.servingInfo != nil {
+		return *c.servingInfo, true
+	}
+	return c.getter.StateServingInfo()
+}
+
+func (c *CertificateUpdater) setStateServingInfo(info params.StateServingInfo) error {
+	if err := c.setter(info); err != nil {
+		return errors.Trace(err)
+	}
+	c.servingInfo = &info
+	return nil
+}
+
+// reissueCertificate replaces the controller certificate with one issued
+// by the new controller CA, once a CA rotation has reached that phase.
+// The controller is only recorded as issued once both the API server
+// and mongod use the new certificate.
+func (c *CertificateUpdater) reissueCertificate() error {
+	if c.caRotation == nil {
+		return nil
+	}
+	rotation, err := c.caRotation.ControllerCARotation()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot read controller CA rotation")
+	}
+	if rotation.Phase != state.CARotationIssue {
+		return nil
+	}
+
+	stateInfo, ok := c.stateServingInfo()
+	if !ok {
+		return errors.New("no state serving info, cannot regenerate server certificate")
+	}
+	if err := jujucert.Verify(stateInfo.Cert, rotation.NewCACert, time.Now()); err != nil {
+		srvCert, err := cert.ParseCert(stateInfo.Cert)
+		if err != nil {
+			return errors.Annotate(err, "cannot parse existing TLS certificate")
+		}
+		hostnames := append([]string(nil), srvCert.DNSNames...)
+		for _, ip := range srvCert.IPAddresses {
+			hostnames = append(hostnames, ip.String())
+		}
+		newCert, newKey, err := controller.GenerateControllerCertAndKey(
+			rotation.NewCACert, rotation.NewCAPrivateKey, hostnames,
+		)
+		if err != nil {
+			return errors.Annotate(err, "cannot generate controller certificate")
+		}
+		stateInfo.Cert = newCert
+		stateInfo.PrivateKey = newKey
+		stateInfo.CAPrivateKey = rotation.NewCAPrivateKey
+		if err := c.setStateServingInfo(stateInfo); err != nil {
+			return errors.Annotate(err, "cannot write agent config")
+		}
+		logger.Infof("controller certificate issued by the new controller CA")
+	}
+	if c.mongo != nil {
+		if err := c.updateMongoCert(stateInfo, rotation.NewCACert); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := c.caRotation.SetControllerCertIssued(c.machineId); err != nil {
+		return errors.Annotate(err, "cannot record controller certificate as issued")
+	}
+	return nil
+}
+
+// updateMongoCert has mongod serve the controller certificate from the
+// given serving info, unless mongod already serves a certificate issued
+// by the given CA. Checking what mongod serves, rather than remembering
+// what was written, means an interrupted update is completed on retry.
+func (c *CertificateUpdater) updateMongoCert(info params.StateServingInfo, caCert string) error {
+	served, err := c.mongo.ServerCert()
+	if err != nil {
+		return errors.Annotate(err, "cannot get mongod certificate")
+	}
+	if jujucert.Verify(served, caCert, time.Now()) == nil {
+		return nil
+	}
+	if err := c.mongo.UpdateServerCert(info.Cert, info.PrivateKey); err != nil {
+		return errors.Annotate(err, "cannot update mongod certificate")
+	}
+	served, err = c.mongo.ServerCert()
+	if err != nil {
+		return errors.Annotate(err, "cannot get mongod certificate")
+	}
+	if err := jujucert.Verify(served, caCert, time.Now()); err != nil {
+		return errors.Annotate(err, "mongod is not serving a certificate from the new CA")
+	}
+	logger.Infof("mongod certificate issued by the new controller CA")
+	return nil
+}
+
 func (c *CertificateUpdater) updateCertificate(addresses []network.Address) error {
 	logger.Debugf("new machine addresses: %#v", addresses)
 	c.addresses = addresses
 
 	// Older Juju deployments will not have the CA cert private key
 	// available.
-	stateInfo, ok := c.getter.StateServingInfo()
+	stateInfo, ok := c.stateServingInfo()
 	if !ok {
 		return errors.New("no state serving info, cannot regenerate server certificate")
 	}
@@ -173,7 +328,7 @@ func (c *CertificateUpdater) updateCertificate(addresses []network.Address) erro
 	}
 	stateInfo.Cert = newCert
 	stateInfo.PrivateKey = newKey
-	err = c.setter(stateInfo)
+	err = c.setStateServingInfo(stateInfo)
 	if err != nil {
 		return errors.Annotate(err, "cannot write agent config")
 	}
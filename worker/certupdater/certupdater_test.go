@@ -5,10 +5,12 @@ package certupdater_test
 
 import (
 	"crypto/x509"
+	"sync"
 	stdtesting "testing"
 	"time"
 
 	"github.com/juju/collections/set"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/cert"
 	gc "gopkg.in/check.v1"
@@ -218,3 +220,132 @@ func (s *CertUpdaterSuite) TestAddressChangeNoCAKey(c *gc.C) {
 		c.Fatalf("set state serving info unexpectedly called")
 	}
 }
+
+type mockCARotation struct {
+	rotation state.ControllerCARotation
+	changes  chan struct{}
+	issued   chan string
+}
+
+func (r *mockCARotation) ControllerCARotation() (state.ControllerCARotation, error) {
+	if r.rotation.Phase == "" {
+		return state.ControllerCARotation{}, errors.NotFoundf("controller CA rotation")
+	}
+	return r.rotation, nil
+}
+
+func (r *mockCARotation) WatchControllerCARotation() state.NotifyWatcher {
+	return newMockNotifyWatcher(r.changes)
+}
+
+func (r *mockCARotation) SetControllerCertIssued(machineId string) error {
+	r.issued <- machineId
+	return nil
+}
+
+type mockMongo struct {
+	mu        sync.Mutex
+	cert      string
+	updateErr error
+}
+
+func (m *mockMongo) ServerCert() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cert, nil
+}
+
+func (m *mockMongo) UpdateServerCert(cert, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	m.cert = cert
+	return nil
+}
+
+func newIssuingCARotation() *mockCARotation {
+	rotation := &mockCARotation{
+		rotation: state.ControllerCARotation{
+			Phase:           state.CARotationIssue,
+			OldCACert:       coretesting.CACert,
+			NewCACert:       coretesting.OtherCACert,
+			NewCAPrivateKey: coretesting.OtherCAKey,
+		},
+		changes: make(chan struct{}, 1),
+		issued:  make(chan string, 1),
+	}
+	rotation.changes <- struct{}{}
+	return rotation
+}
+
+func (s *CertUpdaterSuite) TestReissueFromNewCA(c *gc.C) {
+	var servingInfo params.StateServingInfo
+	setter := func(info params.StateServingInfo) error {
+		servingInfo = info
+		return nil
+	}
+	addressChanges := make(chan struct{}, 1)
+	addressChanges <- struct{}{}
+	rotation := newIssuingCARotation()
+	mongo := &mockMongo{cert: coretesting.ServerCert}
+	worker := certupdater.NewCertificateUpdater(certupdater.Config{
+		AddressWatcher:         &mockMachine{addressChanges},
+		APIHostPortsGetter:     &mockAPIHostGetter{},
+		ControllerConfigGetter: &mockConfigGetter{},
+		StateServingInfoGetter: s,
+		StateServingInfoSetter: setter,
+		CARotationGetter:       rotation,
+		MongoCertUpdater:       mongo,
+		MachineId:              "2",
+	})
+	defer workertest.CleanKill(c, worker)
+
+	select {
+	case machineId := <-rotation.issued:
+		c.Assert(machineId, gc.Equals, "2")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for certificate to be issued")
+	}
+	workertest.CleanKill(c, worker)
+
+	c.Assert(servingInfo.CAPrivateKey, gc.Equals, coretesting.OtherCAKey)
+	err := jujucert.Verify(servingInfo.Cert, coretesting.OtherCACert, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujucert.Verify(servingInfo.Cert, coretesting.CACert, time.Now())
+	c.Assert(err, gc.NotNil)
+	mongoCert, err := mongo.ServerCert()
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujucert.Verify(mongoCert, coretesting.OtherCACert, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CertUpdaterSuite) TestReissueNotRecordedUntilMongoUpdated(c *gc.C) {
+	setter := func(info params.StateServingInfo) error {
+		return nil
+	}
+	rotation := newIssuingCARotation()
+	mongo := &mockMongo{
+		cert:      coretesting.ServerCert,
+		updateErr: errors.New("boom"),
+	}
+	worker := certupdater.NewCertificateUpdater(certupdater.Config{
+		AddressWatcher:         &mockMachine{make(chan struct{})},
+		APIHostPortsGetter:     &mockAPIHostGetter{},
+		ControllerConfigGetter: &mockConfigGetter{},
+		StateServingInfoGetter: s,
+		StateServingInfoSetter: setter,
+		CARotationGetter:       rotation,
+		MongoCertUpdater:       mongo,
+		MachineId:              "2",
+	})
+	err := workertest.CheckKilled(c, worker)
+	c.Assert(err, gc.ErrorMatches, "cannot update mongod certificate: boom")
+
+	select {
+	case machineId := <-rotation.issued:
+		c.Fatalf("machine %s unexpectedly recorded as issued", machineId)
+	default:
+	}
+}
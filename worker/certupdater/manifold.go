@@ -4,11 +4,18 @@
 package certupdater
 
 import (
+	"crypto/tls"
+	"encoding/pem"
+	"net"
+	"strconv"
+	"time"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/worker.v1"
 
 	jujuagent "github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/common"
 	"github.com/juju/juju/worker/dependency"
@@ -86,12 +93,23 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		return nil, errors.Trace(err)
 	}
 
+	var mongoCertUpdater MongoCertUpdater
+	if info, ok := agentConfig.StateServingInfo(); ok {
+		mongoCertUpdater = mongoServer{
+			dataDir: agentConfig.DataDir(),
+			port:    info.StatePort,
+		}
+	}
+
 	w := config.NewWorker(Config{
 		AddressWatcher:         addressWatcher,
 		StateServingInfoGetter: agentConfig,
 		StateServingInfoSetter: setStateServingInfo,
 		ControllerConfigGetter: st,
 		APIHostPortsGetter:     st,
+		CARotationGetter:       st,
+		MongoCertUpdater:       mongoCertUpdater,
+		MachineId:              agentConfig.Tag().Id(),
 	})
 	return common.NewCleanupWorker(w, func() { stTracker.Done() }), nil
 }
@@ -101,3 +119,41 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 func NewMachineAddressWatcher(st *state.State, machineId string) (AddressWatcher, error) {
 	return st.Machine(machineId)
 }
+
+// mongoServer implements MongoCertUpdater for the mongod running
+// alongside the controller agent.
+type mongoServer struct {
+	dataDir string
+	port    int
+}
+
+// ServerCert is part of the MongoCertUpdater interface.
+func (m mongoServer) ServerCert() (string, error) {
+	addr := net.JoinHostPort("localhost", strconv.Itoa(m.port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+		// Only the certificate is wanted, so there is nothing to
+		// verify it against.
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer conn.Close()
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", errors.New("mongod served no certificate")
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[0].Raw})), nil
+}
+
+// UpdateServerCert is part of the MongoCertUpdater interface.
+func (m mongoServer) UpdateServerCert(cert, key string) error {
+	if err := mongo.UpdateSSLKey(m.dataDir, cert, key); err != nil {
+		return errors.Trace(err)
+	}
+	if err := mongo.ReStartService(); err != nil {
+		return errors.Annotate(err, "cannot restart mongod")
+	}
+	return nil
+}
@@ -116,9 +116,22 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 		StateServingInfoGetter: &s.agent.conf,
 		ControllerConfigGetter: s.State,
 		APIHostPortsGetter:     s.State,
+		CARotationGetter:       s.State,
+		MachineId:              "123",
 	})
 }
 
+func (s *ManifoldSuite) TestStartController(c *gc.C) {
+	s.agent.conf.info = &params.StateServingInfo{StatePort: 37017}
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.stub.CheckCallNames(c, "NewMachineAddressWatcher", "NewWorker")
+	config := s.stub.Calls()[1].Args[0].(certupdater.Config)
+	c.Assert(config.MongoCertUpdater, gc.NotNil)
+}
+
 func (s *ManifoldSuite) TestStopWorkerClosesState(c *gc.C) {
 	w, err := s.manifold.Start(s.context)
 	c.Assert(err, jc.ErrorIsNil)
@@ -6,12 +6,14 @@ package instancepoller
 import (
 	"time"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker/catacomb"
 	"github.com/juju/juju/worker/common"
 )
@@ -136,9 +138,20 @@ func (a *aggregator) doRequests(reqs []instanceInfoReq) error {
 		ids[i] = req.instId
 	}
 	insts, err := a.config.Environ.Instances(a.callContext, ids)
+	reclaimed, reclaimErr := a.reclaimedInstances(ids, insts, err)
+	if reclaimErr != nil {
+		logger.Warningf("cannot check for reclaimed instances: %v", reclaimErr)
+	}
 	for i, req := range reqs {
 		var reply instanceInfoReply
-		if err != nil && err != environs.ErrPartialInstances {
+		if reclaimed.Contains(string(req.instId)) {
+			reply.info = instanceInfo{
+				status: instance.InstanceStatus{
+					Status:  status.Reclaimed,
+					Message: "instance reclaimed by the cloud",
+				},
+			}
+		} else if err != nil && err != environs.ErrPartialInstances {
 			reply.err = err
 		} else {
 			reply.info, reply.err = a.instInfo(req.instId, insts[i])
@@ -154,6 +167,38 @@ func (a *aggregator) doRequests(reqs []instanceInfoReq) error {
 	return nil
 }
 
+// reclaimedInstances returns the IDs of those of the given instances
+// that were not found because the cloud has reclaimed them, if the
+// environ can tell.
+func (a *aggregator) reclaimedInstances(ids []instance.Id, insts []instance.Instance, err error) (set.Strings, error) {
+	detector, ok := a.config.Environ.(environs.InstanceReclaimDetector)
+	if !ok {
+		return nil, nil
+	}
+	var missing []instance.Id
+	switch err {
+	case environs.ErrNoInstances:
+		missing = ids
+	case environs.ErrPartialInstances:
+		for i, inst := range insts {
+			if inst == nil {
+				missing = append(missing, ids[i])
+			}
+		}
+	default:
+		return nil, nil
+	}
+	reclaimed, err := detector.ReclaimedInstances(a.callContext, missing)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := set.NewStrings()
+	for _, id := range reclaimed {
+		result.Add(string(id))
+	}
+	return result, nil
+}
+
 // instInfo returns the instance info for the given id
 // and instance. If inst is nil, it returns a not-found error.
 func (a *aggregator) instInfo(id instance.Id, inst instance.Instance) (instanceInfo, error) {
@@ -425,6 +425,63 @@ func (s *aggregateSuite) TestPartialInstanceErrors(c *gc.C) {
 	c.Assert(testGetter.counter, gc.Equals, int32(1))
 }
 
+type reclaimDetectingInstanceGetter struct {
+	testInstanceGetter
+	reclaimed   []instance.Id
+	reclaimedOf []instance.Id
+}
+
+func (g *reclaimDetectingInstanceGetter) ReclaimedInstances(ctx context.ProviderCallContext, ids []instance.Id) ([]instance.Id, error) {
+	g.reclaimedOf = ids
+	return g.reclaimed, nil
+}
+
+func (s *aggregateSuite) TestReclaimedInstances(c *gc.C) {
+	testGetter := new(reclaimDetectingInstanceGetter)
+	clock := jujutesting.NewClock(time.Now())
+	delay := time.Second
+
+	cfg := aggregatorConfigForTest(clock, delay, testGetter)
+
+	testGetter.err = environs.ErrPartialInstances
+	testGetter.newTestInstance("foo", "not foobar", []string{"192.168.1.2"})
+	testGetter.reclaimed = []instance.Id{"foo2"}
+
+	aggregator, err := newAggregator(cfg)
+	c.Check(err, jc.ErrorIsNil)
+
+	// Ensure the worker is killed and cleaned up if the test exits early.
+	defer workertest.CleanKill(c, aggregator)
+
+	var wg sync.WaitGroup
+	checkInfo := func(id instance.Id, expectStatus status.Status, expectedError string) {
+		defer wg.Done()
+		info, err := aggregator.instanceInfo(id)
+		if expectedError == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, expectedError)
+		}
+		c.Check(info.status.Status, gc.Equals, expectStatus)
+	}
+
+	wg.Add(3)
+	go checkInfo("foo", status.Unknown, "")
+	go checkInfo("foo2", status.Reclaimed, "")
+	go checkInfo("foo3", "", "instance foo3 not found")
+
+	// Unwind the testing clock to let our requests through.
+	waitAlarms(c, clock, 3)
+	clock.Advance(delay)
+
+	wg.Wait()
+	workertest.CleanKill(c, aggregator)
+
+	// Only the missing instances are checked.
+	c.Assert(testGetter.reclaimedOf, jc.SameContents, []instance.Id{"foo2", "foo3"})
+	c.Assert(testGetter.counter, gc.Equals, int32(1))
+}
+
 func waitAlarms(c *gc.C, clock *jujutesting.Clock, count int) {
 	timeout := time.After(testing.LongWait)
 	for i := 0; i < count; i++ {
@@ -5,7 +5,6 @@ package provisioner
 
 import (
 	"fmt"
-	"os"
 	"sync/atomic"
 	"time"
 
@@ -27,6 +26,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/netplan"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/watcher"
 	workercommon "github.com/juju/juju/worker/common"
@@ -34,7 +34,6 @@ import (
 
 var (
 	systemNetworkInterfacesFile = "/etc/network/interfaces"
-	systemSbinIfup              = "/sbin/ifup"
 	systemNetplanDirectory      = "/etc/netplan"
 	activateBridgesTimeout      = 5 * time.Minute
 )
@@ -268,12 +267,23 @@ func (cs *ContainerSetup) observeNetwork() ([]params.NetworkConfig, error) {
 	return cs.getNetConfig(common.DefaultNetworkConfigSource())
 }
 
-func defaultBridger() (network.Bridger, error) {
-	if _, err := os.Stat(systemSbinIfup); err == nil {
+func (cs *ContainerSetup) defaultBridger() (network.Bridger, error) {
+	// Machines configured through netplan may still have ifup installed, so
+	// decide on the configuration rather than on the available tools.
+	inUse, err := netplan.InUse(systemNetplanDirectory)
+	if err != nil {
+		return nil, errors.Annotate(err, "checking for netplan configuration")
+	}
+	if !inUse {
 		return network.DefaultEtcNetworkInterfacesBridger(activateBridgesTimeout, systemNetworkInterfacesFile)
-	} else {
-		return network.DefaultNetplanBridger(activateBridgesTimeout, systemNetplanDirectory)
 	}
+	// The controller addresses are used to check that bridging has not cut
+	// this machine off from the controller.
+	addrs, err := cs.config.APIAddresses()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting controller addresses")
+	}
+	return network.DefaultNetplanBridger(activateBridgesTimeout, systemNetplanDirectory, addrs)
 }
 
 func (cs *ContainerSetup) prepareHost(containerTag names.MachineTag, log loggo.Logger) error {
@@ -282,7 +292,7 @@ func (cs *ContainerSetup) prepareHost(containerTag names.MachineTag, log loggo.L
 		ObserveNetworkFunc: cs.observeNetwork,
 		LockName:           cs.initLockName,
 		AcquireLockFunc:    cs.acquireLock,
-		CreateBridger:      defaultBridger,
+		CreateBridger:      cs.defaultBridger,
 		// TODO(jam): 2017-02-08 figure out how to thread catacomb.Dying() into
 		// this function, so that we can stop trying to acquire the lock if we
 		// are stopping.
@@ -16,6 +16,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/network"
@@ -134,6 +135,23 @@ func (s *lxdBrokerSuite) TestStartInstancePopulatesFallbackNetworkInfo(c *gc.C)
 	c.Assert(err, gc.ErrorMatches, "container address allocation not supported")
 }
 
+func (s *lxdBrokerSuite) TestStartInstancePassesVirtType(c *gc.C) {
+	broker, brokerErr := s.newLXDBroker(c)
+	c.Assert(brokerErr, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("virt-type=virtual-machine")
+	_, err := broker.StartInstance(context.NewCloudCallContext(), environs.StartInstanceParams{
+		Constraints:    cons,
+		Tools:          makePossibleTools(),
+		InstanceConfig: makeInstanceConfig(c, s, "1/lxd/0"),
+		StatusCallback: makeNoOpStatusCallback(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.manager.CheckCallNames(c, "CreateContainer")
+	c.Assert(s.manager.Calls()[0].Args[1], jc.DeepEquals, cons)
+}
+
 func (s *lxdBrokerSuite) TestStartInstanceNoHostArchTools(c *gc.C) {
 	broker, brokerErr := s.newLXDBroker(c)
 	c.Assert(brokerErr, jc.ErrorIsNil)
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/controller/authentication"
+	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
@@ -227,6 +228,14 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 			continue
 		}
 		machine := result.Machine
+		// The instance ID recorded in the status data is lost once the
+		// status is reset, so stop any replaced instance first. If that
+		// fails, the machine is left waiting, with the instance ID still
+		// recorded, and stopping it is retried on the next pass.
+		if err := task.stopReplacedInstance(machine, result.Status.Data); err != nil {
+			logger.Errorf("cannot stop instance replaced for machine %q: %v", machine.Id(), err)
+			continue
+		}
 		if err := machine.SetStatus(status.Pending, "", nil); err != nil {
 			logger.Errorf("cannot reset status of machine %q: %v", machine.Id(), err)
 			continue
@@ -243,6 +252,34 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	return task.startMachines(pending)
 }
 
+// stopReplacedInstance stops the instance that was replaced for the
+// machine, as recorded in its instance status data, if the harvest
+// mode allows destroyed instances to be harvested.
+func (task *provisionerTask) stopReplacedInstance(machine *apiprovisioner.Machine, data map[string]interface{}) error {
+	instId, _ := data[state.ReplacedInstanceKey].(string)
+	if instId == "" {
+		return nil
+	}
+	if !task.harvestMode.HarvestDestroyed() {
+		logger.Infof(
+			"not stopping instance %q replaced for machine %q (harvest mode is %q)",
+			instId, machine.Id(), task.harvestMode.String(),
+		)
+		return nil
+	}
+	if keep, err := machine.KeepInstance(); err != nil {
+		return errors.Annotate(err, "getting keep-instance")
+	} else if keep {
+		logger.Debugf("not stopping instance %q replaced for machine %q: keep-instance is true", instId, machine.Id())
+		return nil
+	}
+	logger.Infof("stopping instance %q replaced for machine %q", instId, machine.Id())
+	if err := task.broker.StopInstances(task.cloudCallCtx, instance.Id(instId)); err != nil {
+		return errors.Annotatef(err, "stopping instance %q", instId)
+	}
+	return nil
+}
+
 func (task *provisionerTask) processMachines(ids []string) error {
 	logger.Tracef("processMachines(%v)", ids)
 
@@ -592,6 +629,17 @@ func (task *provisionerTask) constructInstanceConfig(
 
 	instanceConfig.CloudInitUserData = pInfo.CloudInitUserData
 
+	if len(pInfo.CharmLXDProfiles) > 0 {
+		instanceConfig.CharmLXDProfiles = make(lxdprofile.Profiles)
+		for name, profile := range pInfo.CharmLXDProfiles {
+			instanceConfig.CharmLXDProfiles[name] = lxdprofile.Profile{
+				Description: profile.Description,
+				Config:      profile.Config,
+				Devices:     profile.Devices,
+			}
+		}
+	}
+
 	return instanceConfig, nil
 }
 
@@ -1570,6 +1570,43 @@ func (s *ProvisionerSuite) TestProvisionerRetriesTransientErrors(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
 }
 
+func (s *ProvisionerSuite) TestProvisionerReplacesInstance(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	p := s.newEnvironProvisioner(c)
+	defer workertest.CleanKill(c, p)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	inst := s.checkStartInstance(c, m)
+
+	// The replaced instance is stopped, and a new one started
+	// for the same machine.
+	err = m.ReplaceInstance()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStopInstances(c, inst)
+	newInst := s.checkStartInstance(c, m)
+	c.Assert(newInst.Id(), gc.Not(gc.Equals), inst.Id())
+}
+
+func (s *ProvisionerSuite) TestProvisionerRetriesStoppingReplacedInstance(c *gc.C) {
+	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
+	broker := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int), stopInstanceFailures: 2}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	inst := s.checkStartInstance(c, m)
+
+	// Stopping the replaced instance fails at first; no new instance
+	// is started until it has been stopped.
+	err = m.ReplaceInstance()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStopInstances(c, inst)
+	newInst := s.checkStartInstance(c, m)
+	c.Assert(newInst.Id(), gc.Not(gc.Equals), inst.Id())
+}
+
 func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
 	broker := &mockBroker{Environ: s.Environ, retryCount: make(map[string]int),
@@ -1983,6 +2020,7 @@ type mockBroker struct {
 	retryCount               map[string]int
 	startInstanceFailureInfo map[string]mockBrokerFailures
 	derivedAZ                map[string][]string
+	stopInstanceFailures     int
 }
 
 type mockBrokerFailures struct {
@@ -2012,6 +2050,19 @@ func (b *mockBroker) StartInstance(ctx context.ProviderCallContext, args environ
 	return nil, returnError
 }
 
+func (b *mockBroker) StopInstances(ctx context.ProviderCallContext, ids ...instance.Id) error {
+	// Instances are stopped successfully unless
+	// mock.stopInstanceFailures is set, in which case that many
+	// attempts fail first.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopInstanceFailures > 0 {
+		b.stopInstanceFailures--
+		return errors.New("error: cannot stop instances")
+	}
+	return b.Environ.StopInstances(ctx, ids...)
+}
+
 func (b *mockBroker) getRetryCount(id string) int {
 	b.mu.Lock()
 	retries := b.retryCount[id]
@@ -0,0 +1,158 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftleader
+
+import (
+	"github.com/hashicorp/raft"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/pubsub/controller"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.raft.raftleader")
+
+// Config holds the configuration for a raft leader publisher worker.
+type Config struct {
+	Raft *raft.Raft
+	Hub  *pubsub.StructuredHub
+}
+
+// Validate validates the raft leader publisher worker configuration.
+func (config Config) Validate() error {
+	if config.Raft == nil {
+		return errors.NotValidf("nil Raft")
+	}
+	if config.Hub == nil {
+		return errors.NotValidf("nil Hub")
+	}
+	return nil
+}
+
+// Worker is a worker that publishes the identity of the raft leader on
+// the central hub, so that the API server can report each controller's
+// raft role.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	requests chan struct{}
+}
+
+// NewWorker returns a new raft leader publisher worker, with the given
+// configuration.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config:   config,
+		requests: make(chan struct{}, 1),
+	}
+	unsubscribe, err := config.Hub.Subscribe(
+		controller.RaftLeaderRequestTopic,
+		w.leaderRequested,
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "subscribing to raft leader requests")
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: func() error {
+			defer unsubscribe()
+			return w.loop()
+		},
+	}); err != nil {
+		unsubscribe()
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *Worker) loop() error {
+	ch := make(chan raft.Observation, 1)
+	or := raft.NewObserver(ch, false, func(o *raft.Observation) bool {
+		_, ok := o.Data.(raft.LeaderObservation)
+		return ok
+	})
+	w.config.Raft.RegisterObserver(or)
+	defer w.config.Raft.DeregisterObserver(or)
+
+	// Publish the current leader straight away, so that subscribers
+	// which started before this worker learn of it.
+	if err := w.publish(); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-ch:
+		case <-w.requests:
+		}
+		if err := w.publish(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+func (w *Worker) publish() error {
+	leader, err := leaderID(w.config.Raft)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("publishing raft leader %q", leader)
+	msg := controller.RaftLeaderMessage{
+		Leader:    leader,
+		LocalOnly: true,
+	}
+	if _, err := w.config.Hub.Publish(controller.RaftLeaderTopic, msg); err != nil {
+		return errors.Annotate(err, "publishing raft leader")
+	}
+	return nil
+}
+
+func (w *Worker) leaderRequested(topic string, req controller.RaftLeaderRequest, err error) {
+	if err != nil {
+		// This should never happen, so treat it as fatal.
+		w.catacomb.Kill(errors.Annotate(err, "raft leader request callback failed"))
+		return
+	}
+	select {
+	case w.requests <- struct{}{}:
+	default:
+		// A publish is already pending.
+	}
+}
+
+// leaderID returns the server ID of the current raft leader, or "" if
+// no leader is known. The server ID is the controller machine's id.
+func leaderID(r *raft.Raft) (string, error) {
+	address := r.Leader()
+	if address == "" {
+		return "", nil
+	}
+	future := r.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return "", errors.Annotate(err, "getting raft configuration")
+	}
+	for _, server := range future.Configuration().Servers {
+		if server.Address == address {
+			return string(server.ID), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftleader_test
+
+import (
+	"time"
+
+	"github.com/juju/pubsub"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/pubsub/centralhub"
+	"github.com/juju/juju/pubsub/controller"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/raft"
+	"github.com/juju/juju/worker/raft/raftleader"
+	"github.com/juju/juju/worker/raft/rafttest"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type workerFixture struct {
+	rafttest.RaftFixture
+	hub    *pubsub.StructuredHub
+	config raftleader.Config
+}
+
+func (s *workerFixture) SetUpTest(c *gc.C) {
+	s.FSM = &raft.SimpleFSM{}
+	s.RaftFixture.SetUpTest(c)
+	s.hub = centralhub.New(names.NewMachineTag("0"))
+	s.config = raftleader.Config{
+		Raft: s.Raft,
+		Hub:  s.hub,
+	}
+}
+
+type WorkerValidationSuite struct {
+	workerFixture
+}
+
+var _ = gc.Suite(&WorkerValidationSuite{})
+
+func (s *WorkerValidationSuite) TestValidateErrors(c *gc.C) {
+	type test struct {
+		f      func(*raftleader.Config)
+		expect string
+	}
+	tests := []test{{
+		func(cfg *raftleader.Config) { cfg.Raft = nil },
+		"nil Raft not valid",
+	}, {
+		func(cfg *raftleader.Config) { cfg.Hub = nil },
+		"nil Hub not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test #%d (%s)", i, test.expect)
+		config := s.config
+		test.f(&config)
+		w, err := raftleader.NewWorker(config)
+		if !c.Check(err, gc.NotNil) {
+			workertest.DirtyKill(c, w)
+			continue
+		}
+		c.Check(w, gc.IsNil)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+type WorkerSuite struct {
+	workerFixture
+	worker worker.Worker
+	leader chan controller.RaftLeaderMessage
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.workerFixture.SetUpTest(c)
+	s.leader = make(chan controller.RaftLeaderMessage, 10)
+
+	// Use a local variable to send to the channel in the callback, so
+	// we don't get races when a subsequent test overwrites s.leader
+	// with a new channel.
+	leader := s.leader
+	unsubscribe, err := s.hub.Subscribe(
+		controller.RaftLeaderTopic,
+		func(topic string, msg controller.RaftLeaderMessage, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			leader <- msg
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { unsubscribe() })
+
+	worker, err := raftleader.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.DirtyKill(c, worker)
+	})
+	s.worker = worker
+}
+
+func (s *WorkerSuite) TestCleanKill(c *gc.C) {
+	workertest.CleanKill(c, s.worker)
+}
+
+func (s *WorkerSuite) assertLeaderPublished(c *gc.C, expect string) {
+	select {
+	case msg := <-s.leader:
+		c.Assert(msg, gc.Equals, controller.RaftLeaderMessage{
+			Leader:    expect,
+			LocalOnly: true,
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for raft leader")
+	}
+}
+
+func (s *WorkerSuite) TestPublishesLeaderOnStart(c *gc.C) {
+	s.assertLeaderPublished(c, "0")
+}
+
+func (s *WorkerSuite) TestPublishesLeaderOnRequest(c *gc.C) {
+	s.assertLeaderPublished(c, "0")
+	_, err := s.hub.Publish(controller.RaftLeaderRequestTopic, controller.RaftLeaderRequest{
+		Requester: "test",
+		LocalOnly: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertLeaderPublished(c, "0")
+}
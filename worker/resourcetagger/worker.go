@@ -0,0 +1,200 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/resourcetagger"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+	"github.com/juju/juju/worker/common"
+)
+
+var logger = loggo.GetLogger("juju.worker.resourcetagger")
+
+// Facade exposes the controller functionality required by the worker.
+type Facade interface {
+	// WatchForModelConfigChanges returns a watcher that fires when
+	// the model config, and hence the model's resource-tags, changes.
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+
+	// WatchApplicationConfig returns a watcher that fires when the
+	// application config, and hence the resource-tags, of any of
+	// the model's applications changes.
+	WatchApplicationConfig() (watcher.NotifyWatcher, error)
+
+	// TaggableResources returns the instances and volumes in the
+	// model, along with the tags each should have.
+	TaggableResources() ([]resourcetagger.Resource, error)
+
+	// SetTagDrift records the machines and volumes whose tags had
+	// drifted during the most recent reconciliation, and whether
+	// they could be corrected.
+	SetTagDrift([]resourcetagger.TagDrift) error
+}
+
+// Config defines the operation of a resource tagger worker.
+type Config struct {
+	// Facade is the worker's view of the controller.
+	Facade Facade
+
+	// Environ is used to read and update the tags of
+	// instances and volumes.
+	Environ environs.TagUpdater
+
+	// Clock is the worker's view of time.
+	Clock clock.Clock
+
+	// Period is the time between reconciliations, in the absence
+	// of any model config changes.
+	Period time.Duration
+
+	// CredentialAPI is used to invalidate the model's cloud
+	// credential if the provider rejects it.
+	CredentialAPI common.CredentialAPI
+}
+
+// Validate returns an error if the configuration cannot be expected
+// to start a functional worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Environ == nil {
+		return errors.NotValidf("nil Environ")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Period <= 0 {
+		return errors.NotValidf("non-positive Period")
+	}
+	if config.CredentialAPI == nil {
+		return errors.NotValidf("nil CredentialAPI")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that periodically ensures the model's
+// instances and volumes carry the tags derived from the model's and
+// applications' resource-tags, correcting any that have drifted.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &tagWorker{
+		config: config,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type tagWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *tagWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *tagWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *tagWorker) loop() error {
+	modelConfigWatcher, err := w.config.Facade.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(modelConfigWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	appConfigWatcher, err := w.config.Facade.WatchApplicationConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(appConfigWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The model config watcher's initial event triggers the first
+	// reconciliation; subsequent ones happen on model or application
+	// config changes, or every Period.
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-modelConfigWatcher.Changes():
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+		case _, ok := <-appConfigWatcher.Changes():
+			if !ok {
+				return errors.New("application config watcher closed")
+			}
+		case <-timer:
+		}
+		if err := w.reconcile(); err != nil {
+			return errors.Trace(err)
+		}
+		timer = w.config.Clock.After(w.config.Period)
+	}
+}
+
+func (w *tagWorker) reconcile() error {
+	resources, err := w.config.Facade.TaggableResources()
+	if err != nil {
+		return errors.Annotate(err, "getting taggable resources")
+	}
+	ctx := common.NewCloudCallContext(w.config.CredentialAPI)
+	var drift []resourcetagger.TagDrift
+	for _, r := range resources {
+		var changed bool
+		var err error
+		switch tag := r.Tag.(type) {
+		case names.MachineTag:
+			changed, err = w.config.Environ.UpdateInstanceTags(ctx, instance.Id(r.ProviderId), r.Tags)
+		case names.VolumeTag:
+			changed, err = w.config.Environ.UpdateVolumeTags(ctx, r.ProviderId, r.Tags)
+		default:
+			logger.Warningf("cannot update tags of %s", names.ReadableString(tag))
+			continue
+		}
+		switch {
+		case errors.IsNotFound(err), errors.IsNotSupported(err):
+			// The resource has gone away, or belongs to a storage
+			// provider that does not support tagging; either way
+			// there is nothing to reconcile.
+			logger.Debugf("not updating tags of %s: %v", names.ReadableString(r.Tag), err)
+		case err != nil:
+			// Failing to tag one resource should not prevent
+			// the others from being reconciled.
+			logger.Errorf("updating tags of %s: %v", names.ReadableString(r.Tag), err)
+			drift = append(drift, resourcetagger.TagDrift{Tag: r.Tag, Error: err})
+		case changed:
+			logger.Infof("corrected tags of %s", names.ReadableString(r.Tag))
+			drift = append(drift, resourcetagger.TagDrift{Tag: r.Tag})
+		}
+	}
+	return errors.Annotate(w.config.Facade.SetTagDrift(drift), "recording tag drift")
+}
@@ -0,0 +1,240 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resourcetagger_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	apiresourcetagger "github.com/juju/juju/api/resourcetagger"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/resourcetagger"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock            *testing.Clock
+	configChanges    chan struct{}
+	appConfigChanges chan struct{}
+	facade           *mockFacade
+	environ          *mockTagUpdater
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(coretesting.ZeroTime())
+	s.configChanges = make(chan struct{}, 1)
+	s.configChanges <- struct{}{}
+	s.appConfigChanges = make(chan struct{}, 1)
+	s.facade = &mockFacade{
+		configChanges:    s.configChanges,
+		appConfigChanges: s.appConfigChanges,
+		drift:            make(chan []apiresourcetagger.TagDrift, 1),
+		resources: []apiresourcetagger.Resource{{
+			Tag:        names.NewMachineTag("0"),
+			ProviderId: "inst-0",
+			Tags:       map[string]string{"team": "web"},
+		}, {
+			Tag:        names.NewVolumeTag("1"),
+			ProviderId: "vol-1",
+			Tags:       map[string]string{"team": "db"},
+		}},
+	}
+	s.environ = &mockTagUpdater{changed: map[string]bool{}}
+}
+
+func (s *WorkerSuite) config() resourcetagger.Config {
+	return resourcetagger.Config{
+		Facade:        s.facade,
+		Environ:       s.environ,
+		Clock:         s.clock,
+		Period:        time.Minute,
+		CredentialAPI: &credentialAPIForTest{},
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		mutate func(*resourcetagger.Config)
+		err    string
+	}{{
+		func(cfg *resourcetagger.Config) { cfg.Facade = nil },
+		"nil Facade not valid",
+	}, {
+		func(cfg *resourcetagger.Config) { cfg.Environ = nil },
+		"nil Environ not valid",
+	}, {
+		func(cfg *resourcetagger.Config) { cfg.Clock = nil },
+		"nil Clock not valid",
+	}, {
+		func(cfg *resourcetagger.Config) { cfg.Period = 0 },
+		"non-positive Period not valid",
+	}, {
+		func(cfg *resourcetagger.Config) { cfg.CredentialAPI = nil },
+		"nil CredentialAPI not valid",
+	}} {
+		c.Logf("test #%d: %s", i, test.err)
+		config := s.config()
+		test.mutate(&config)
+		err := config.Validate()
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *WorkerSuite) TestReconcilesOnStart(c *gc.C) {
+	s.environ.changed["vol-1"] = true
+	w, err := resourcetagger.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.waitDrift(c), jc.DeepEquals, []apiresourcetagger.TagDrift{{Tag: names.NewVolumeTag("1")}})
+	s.environ.stub.CheckCalls(c, []testing.StubCall{
+		{"UpdateInstanceTags", []interface{}{instance.Id("inst-0"), map[string]string{"team": "web"}}},
+		{"UpdateVolumeTags", []interface{}{"vol-1", map[string]string{"team": "db"}}},
+	})
+}
+
+func (s *WorkerSuite) TestReconcilesAfterPeriod(c *gc.C) {
+	w, err := resourcetagger.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.waitDrift(c), gc.HasLen, 0)
+	s.environ.changed["inst-0"] = true
+	err = s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.waitDrift(c), jc.DeepEquals, []apiresourcetagger.TagDrift{{Tag: names.NewMachineTag("0")}})
+}
+
+func (s *WorkerSuite) TestReconcilesOnConfigChange(c *gc.C) {
+	w, err := resourcetagger.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.waitDrift(c), gc.HasLen, 0)
+	s.environ.changed["inst-0"] = true
+	s.configChanges <- struct{}{}
+	c.Assert(s.waitDrift(c), jc.DeepEquals, []apiresourcetagger.TagDrift{{Tag: names.NewMachineTag("0")}})
+}
+
+func (s *WorkerSuite) TestReconcilesOnApplicationConfigChange(c *gc.C) {
+	w, err := resourcetagger.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.waitDrift(c), gc.HasLen, 0)
+	s.environ.changed["inst-0"] = true
+	s.appConfigChanges <- struct{}{}
+	c.Assert(s.waitDrift(c), jc.DeepEquals, []apiresourcetagger.TagDrift{{Tag: names.NewMachineTag("0")}})
+}
+
+func (s *WorkerSuite) TestReportsResourceErrors(c *gc.C) {
+	s.environ.changed["vol-1"] = true
+	s.environ.stub.SetErrors(
+		errors.NotFoundf("instance inst-0"),
+		errors.New("boom"),
+	)
+	w, err := resourcetagger.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	// Resources that have gone away are skipped, but failures
+	// to correct tags are reported as drift.
+	drift := s.waitDrift(c)
+	c.Assert(drift, gc.HasLen, 1)
+	c.Check(drift[0].Tag, gc.Equals, names.NewVolumeTag("1"))
+	c.Check(drift[0].Error, gc.ErrorMatches, "boom")
+	s.environ.stub.CheckCallNames(c, "UpdateInstanceTags", "UpdateVolumeTags")
+}
+
+func (s *WorkerSuite) TestTaggableResourcesError(c *gc.C) {
+	s.facade.stub.SetErrors(errors.New("boom"))
+	w, err := resourcetagger.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting taggable resources: boom")
+}
+
+func (s *WorkerSuite) waitDrift(c *gc.C) []apiresourcetagger.TagDrift {
+	select {
+	case drift := <-s.facade.drift:
+		return drift
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tag drift to be recorded")
+	}
+	panic("unreachable")
+}
+
+type mockFacade struct {
+	stub             testing.Stub
+	configChanges    chan struct{}
+	appConfigChanges chan struct{}
+	resources        []apiresourcetagger.Resource
+	drift            chan []apiresourcetagger.TagDrift
+}
+
+func (f *mockFacade) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	return watchertest.NewMockNotifyWatcher(f.configChanges), nil
+}
+
+func (f *mockFacade) WatchApplicationConfig() (watcher.NotifyWatcher, error) {
+	return watchertest.NewMockNotifyWatcher(f.appConfigChanges), nil
+}
+
+func (f *mockFacade) TaggableResources() ([]apiresourcetagger.Resource, error) {
+	f.stub.AddCall("TaggableResources")
+	if err := f.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.resources, nil
+}
+
+func (f *mockFacade) SetTagDrift(drift []apiresourcetagger.TagDrift) error {
+	f.stub.AddCall("SetTagDrift", drift)
+	f.drift <- drift
+	return f.stub.NextErr()
+}
+
+type mockTagUpdater struct {
+	stub    testing.Stub
+	changed map[string]bool
+}
+
+func (u *mockTagUpdater) UpdateInstanceTags(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) (bool, error) {
+	u.stub.AddCall("UpdateInstanceTags", id, tags)
+	if err := u.stub.NextErr(); err != nil {
+		return false, err
+	}
+	return u.changed[string(id)], nil
+}
+
+func (u *mockTagUpdater) UpdateVolumeTags(ctx context.ProviderCallContext, volumeId string, tags map[string]string) (bool, error) {
+	u.stub.AddCall("UpdateVolumeTags", volumeId, tags)
+	if err := u.stub.NextErr(); err != nil {
+		return false, err
+	}
+	return u.changed[volumeId], nil
+}
+
+type credentialAPIForTest struct{}
+
+func (*credentialAPIForTest) InvalidateModelCredential(reason string) error {
+	return nil
+}
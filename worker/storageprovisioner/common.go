@@ -42,7 +42,8 @@ func storageEntityLife(ctx *context, tags []names.Tag) (alive, dying, dead []nam
 }
 
 // attachmentLife queries the lifecycle state of each specified
-// attachment, and then partitions the IDs by them.
+// attachment, and then partitions the IDs by them. Attachments
+// that have been removed from state are reported as dead.
 func attachmentLife(ctx *context, ids []params.MachineStorageId) (
 	alive, dying, dead []params.MachineStorageId, _ error,
 ) {
@@ -51,6 +52,10 @@ func attachmentLife(ctx *context, ids []params.MachineStorageId) (
 		return nil, nil, nil, errors.Annotate(err, "getting machine attachment life")
 	}
 	for i, result := range lifeResults {
+		if result.Error != nil && params.IsCodeNotFound(result.Error) {
+			dead = append(dead, ids[i])
+			continue
+		}
 		if result.Error != nil {
 			return nil, nil, nil, errors.Annotatef(
 				result.Error, "getting life of %s attached to %s",
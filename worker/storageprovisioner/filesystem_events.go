@@ -74,10 +74,11 @@ func filesystemAttachmentsChanged(ctx *context, watcherIds []watcher.MachineStor
 		return errors.Trace(err)
 	}
 	logger.Debugf("filesystem attachment alive: %v, dying: %v, dead: %v", alive, dying, dead)
-	if len(dead) != 0 {
-		// We should not see dead filesystem attachments;
-		// attachments go directly from Dying to removed.
-		logger.Warningf("unexpected dead filesystem attachments: %v", dead)
+	for _, id := range dead {
+		// Attachments go directly from Dying to removed,
+		// so these have been removed; forget them.
+		removePendingFilesystemAttachment(ctx, id)
+		delete(ctx.filesystemAttachments, id)
 	}
 	if len(alive)+len(dying) == 0 {
 		return nil
@@ -15,6 +15,8 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/storageprovisioner"
 	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/container/kvm"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/worker/common"
 	"github.com/juju/juju/worker/dependency"
@@ -50,13 +52,19 @@ func (config MachineManifoldConfig) newWorker(a agent.Agent, apiCaller base.APIC
 	}
 
 	storageDir := filepath.Join(cfg.DataDir(), "storage")
+	// Machines provision the volumes for their KVM guests, as well
+	// as their own.
+	registry := storage.ChainedProviderRegistry{
+		provider.CommonStorageProviders(),
+		kvm.StorageProviders(),
+	}
 	w, err := NewStorageProvisioner(Config{
 		Scope:            tag,
 		StorageDir:       storageDir,
 		Volumes:          api,
 		Filesystems:      api,
 		Life:             api,
-		Registry:         provider.CommonStorageProviders(),
+		Registry:         registry,
 		Machines:         api,
 		Status:           api,
 		Clock:            config.Clock,
@@ -1324,6 +1324,63 @@ func (s *storageProvisionerSuite) TestDetachVolumes(c *gc.C) {
 	waitChannel(c, removed, "waiting for attachment to be removed")
 }
 
+func (s *storageProvisionerSuite) TestReattachRemovedVolumeAttachment(c *gc.C) {
+	volumeAttachmentInfoSet := make(chan interface{}, 2)
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.setVolumeAttachmentInfo = func(volumeAttachments []params.VolumeAttachment) ([]params.ErrorResult, error) {
+		for _, a := range volumeAttachments {
+			id := params.MachineStorageId{
+				MachineTag:    a.MachineTag,
+				AttachmentTag: a.VolumeTag,
+			}
+			volumeAccessor.provisionedAttachments[id] = a
+		}
+		volumeAttachmentInfoSet <- nil
+		return make([]params.ErrorResult, len(volumeAttachments)), nil
+	}
+
+	// The attachment is alive, then removed (as when the machine's
+	// instance is replaced), and then recreated.
+	var lifeCalls int
+	attachmentLife := func(ids []params.MachineStorageId) ([]params.LifeResult, error) {
+		lifeCalls++
+		if lifeCalls == 2 {
+			return []params.LifeResult{{
+				Error: &params.Error{Code: params.CodeNotFound},
+			}}, nil
+		}
+		return []params.LifeResult{{Life: params.Alive}}, nil
+	}
+
+	volumeAccessor.provisionedVolumes["volume-1"] = params.Volume{
+		VolumeTag: "volume-1",
+		Info: params.VolumeInfo{
+			VolumeId: "vol-123",
+		},
+	}
+	volumeAccessor.provisionedMachines["machine-1"] = instance.Id("already-provisioned-1")
+
+	args := &workerArgs{
+		volumes:  volumeAccessor,
+		life:     &mockLifecycleManager{attachmentLife: attachmentLife},
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	attachmentChange := []watcher.MachineStorageId{{
+		MachineTag: "machine-1", AttachmentTag: "volume-1",
+	}}
+	volumeAccessor.attachmentsWatcher.changes <- attachmentChange
+	volumeAccessor.volumesWatcher.changes <- []string{"1"}
+	waitChannel(c, volumeAttachmentInfoSet, "waiting for volume attachments to be set")
+
+	volumeAccessor.attachmentsWatcher.changes <- attachmentChange
+	volumeAccessor.attachmentsWatcher.changes <- attachmentChange
+	waitChannel(c, volumeAttachmentInfoSet, "waiting for volume to be reattached")
+}
+
 func (s *storageProvisionerSuite) TestDetachVolumesRetry(c *gc.C) {
 	machine := names.NewMachineTag("1")
 	volume := names.NewVolumeTag("1")
@@ -63,10 +63,13 @@ func volumeAttachmentsChanged(ctx *context, watcherIds []watcher.MachineStorageI
 		return errors.Trace(err)
 	}
 	logger.Debugf("volume attachments alive: %v, dying: %v, dead: %v", alive, dying, dead)
-	if len(dead) != 0 {
-		// We should not see dead volume attachments;
-		// attachments go directly from Dying to removed.
-		logger.Warningf("unexpected dead volume attachments: %v", dead)
+	for _, id := range dead {
+		// Attachments go directly from Dying to removed, so these
+		// have been removed; for example, when the machine's instance
+		// was replaced. Forget them, so they are attached afresh if
+		// they are recreated.
+		removePendingVolumeAttachment(ctx, id)
+		delete(ctx.volumeAttachments, id)
 	}
 	if len(alive)+len(dying) == 0 {
 		return nil